@@ -0,0 +1,52 @@
+// Command batchapply applies a batch of edits, given as JSON, to a mod and saves the result.
+// Relative file names within the batch are resolved against the working directory.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/inkyblackness/hacked/headless"
+)
+
+func main() {
+	dataDir := flag.String("data", "", "Path to the main data directory of the game.")
+	modDir := flag.String("mod", "", "Path to a mod directory to start from. Optional.")
+	batchFile := flag.String("batch", "", "Path to the JSON file with the batch of edits.")
+	outDir := flag.String("out", "", "Path to save the mod to. Defaults to the mod directory.")
+	flag.Parse()
+
+	if (len(*dataDir) == 0) || (len(*batchFile) == 0) {
+		fmt.Fprintln(os.Stderr, "The data directory and the batch file must be specified.")
+		flag.Usage()
+		os.Exit(2)
+	}
+	if (len(*outDir) == 0) && (len(*modDir) == 0) {
+		fmt.Fprintln(os.Stderr, "Either the mod directory or the output directory must be specified.")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	session := headless.NewSession()
+	exitOnError("Failed to load data", session.AddManifestEntry(*dataDir))
+	if len(*modDir) > 0 {
+		exitOnError("Failed to load mod", session.LoadMod(*modDir))
+	}
+
+	file, err := os.Open(*batchFile)
+	exitOnError("Failed to open batch", err)
+	batch, err := headless.ReadBatch(file)
+	_ = file.Close()
+	exitOnError("Failed to read batch", err)
+
+	exitOnError("Failed to apply batch", headless.NewDispatcher().Apply(session, batch))
+	exitOnError("Failed to save mod", session.Save(*outDir))
+}
+
+func exitOnError(context string, err error) {
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v: %v\n", context, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,222 @@
+// Command movieencode encodes a sequence of images into a movie (MOVI) file.
+// The images are read and encoded one at a time, so that long sequences can be encoded with little memory.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"image"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	_ "image/gif"
+	_ "image/png"
+
+	"github.com/inkyblackness/hacked/ss1/content/bitmap"
+	"github.com/inkyblackness/hacked/ss1/content/movie"
+	"github.com/inkyblackness/hacked/ss1/world"
+)
+
+func main() {
+	inDir := flag.String("in", "", "Path to a directory with the frames as images. They are taken in order of their names.")
+	outFile := flag.String("out", "", "Filename of the movie to write.")
+	paletteFile := flag.String("palette", "", "Palette file (.pal, .gpl, or raw) the frames are mapped to. "+
+		"If not specified, the frames must be paletted images, and the palette of the first one is used.")
+	framesPerSecond := flag.Float64("fps", 10.0, "Number of frames per second.")
+	sceneLength := flag.Int("scene", movie.DefaultSceneLength, "Number of frames per scene. "+
+		"Shorter scenes need less memory, longer scenes allow a better compression.")
+	modeName := flag.String("mode", movie.PaletteLookupCompact.String(), "Palette lookup mode: "+
+		paletteLookupModeNames()+". Compact results in the smallest movies, Direct encodes the fastest.")
+	seed := flag.Uint64("seed", 0, "Basis for choices between equivalent encodings of the compact mode. "+
+		"The same frames encoded with the same seed always result in the same movie.")
+	flag.Parse()
+
+	if (len(*inDir) == 0) || (len(*outFile) == 0) {
+		fmt.Fprintln(os.Stderr, "The input directory and the output file must be specified.")
+		flag.Usage()
+		os.Exit(2)
+	}
+	if (*framesPerSecond <= 0) || (*sceneLength < 1) {
+		fmt.Fprintln(os.Stderr, "Frames per second and scene length must be positive.")
+		os.Exit(2)
+	}
+	mode, modeKnown := paletteLookupModeNamed(*modeName)
+	if !modeKnown {
+		fmt.Fprintf(os.Stderr, "Unknown palette lookup mode: %v\n", *modeName)
+		os.Exit(2)
+	}
+
+	filenames, err := frameFilenames(*inDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to list frames: %v\n", err)
+		os.Exit(1)
+	}
+	if len(filenames) == 0 {
+		fmt.Fprintln(os.Stderr, "No frames found.")
+		os.Exit(1)
+	}
+	first, err := readImage(filenames[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to read first frame: %v\n", err)
+		os.Exit(1)
+	}
+	var mapper frameMapper
+	if len(*paletteFile) > 0 {
+		mapper.palette, err = readPalette(*paletteFile)
+	} else {
+		mapper.palette, err = paletteOf(first)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to determine palette: %v\n", err)
+		os.Exit(1)
+	}
+	mapper.mapped = len(*paletteFile) > 0
+	mapper.bitmapper = bitmap.NewBitmapper(&mapper.palette)
+	mapper.size = first.Bounds().Size()
+	if ((mapper.size.X % movie.TileSideLength) != 0) || ((mapper.size.Y % movie.TileSideLength) != 0) {
+		fmt.Fprintf(os.Stderr, "Frame size %vx%v is not a multiple of %v.\n", mapper.size.X, mapper.size.Y, movie.TileSideLength)
+		os.Exit(1)
+	}
+
+	frameTime := 1.0 / float32(*framesPerSecond)
+	template := movie.NewContainerBuilder().
+		VideoWidth(uint16(mapper.size.X)).
+		VideoHeight(uint16(mapper.size.Y)).
+		StartPalette(&mapper.palette).
+		MediaDuration(float32(len(filenames)) * frameTime).
+		Build()
+	err = world.SaveFile(*outFile, func(dest io.WriteSeeker) error {
+		writer, writerErr := movie.NewStreamWriter(dest, template, movie.StreamEntryCount(len(filenames), *sceneLength))
+		if writerErr != nil {
+			return writerErr
+		}
+		encoder := movie.NewStreamEncoder(mapper.size.X, mapper.size.Y, writer)
+		encoder.SetSceneLength(*sceneLength)
+		encoder.SetPaletteLookupMode(mode)
+		encoder.SetSeed(*seed)
+		for index, filename := range filenames {
+			frame, frameErr := mapper.frameFrom(filename)
+			if frameErr != nil {
+				return frameErr
+			}
+			frameErr = encoder.Push(float32(index)*frameTime, frame)
+			if frameErr != nil {
+				return fmt.Errorf("%v: %w", filename, frameErr)
+			}
+		}
+		encodeErr := encoder.Close()
+		if encodeErr != nil {
+			return encodeErr
+		}
+		return writer.Close()
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to encode movie: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+type frameMapper struct {
+	palette   bitmap.Palette
+	mapped    bool
+	bitmapper *bitmap.Bitmapper
+	size      image.Point
+}
+
+// frameFrom reads the given image as a frame of palette indices.
+// Index zero is transparent and keeps the pixel of the previous frame.
+func (mapper frameMapper) frameFrom(filename string) ([]byte, error) {
+	img, err := readImage(filename)
+	if err != nil {
+		return nil, err
+	}
+	if img.Bounds().Size() != mapper.size {
+		return nil, fmt.Errorf("%v: frame size %v differs from first frame", filename, img.Bounds().Size())
+	}
+	if paletted, isPaletted := img.(*image.Paletted); isPaletted && !mapper.mapped {
+		frame := make([]byte, mapper.size.X*mapper.size.Y)
+		for y := 0; y < mapper.size.Y; y++ {
+			copy(frame[y*mapper.size.X:(y+1)*mapper.size.X], paletted.Pix[y*paletted.Stride:])
+		}
+		return frame, nil
+	}
+	if !mapper.mapped {
+		return nil, fmt.Errorf("%v: frame is not a paletted image, a palette file is required", filename)
+	}
+	return mapper.bitmapper.Map(img).Pixels, nil
+}
+
+func frameFilenames(dir string) ([]string, error) {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var filenames []string
+	for _, info := range infos {
+		if info.IsDir() || strings.HasPrefix(info.Name(), ".") {
+			continue
+		}
+		filenames = append(filenames, filepath.Join(dir, info.Name()))
+	}
+	sort.Strings(filenames)
+	return filenames, nil
+}
+
+func readImage(filename string) (image.Image, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close() // nolint: errcheck
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %w", filename, err)
+	}
+	return img, nil
+}
+
+func readPalette(filename string) (bitmap.Palette, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return bitmap.Palette{}, err
+	}
+	defer file.Close() // nolint: errcheck
+	return bitmap.PaletteFormatForFilename(filename).Decode(file)
+}
+
+func paletteOf(img image.Image) (bitmap.Palette, error) {
+	var pal bitmap.Palette
+	paletted, isPaletted := img.(*image.Paletted)
+	if !isPaletted {
+		return pal, fmt.Errorf("first frame is not a paletted image, a palette file is required")
+	}
+	for index, clr := range paletted.Palette {
+		if index >= len(pal) {
+			break
+		}
+		r, g, b, _ := clr.RGBA()
+		pal[index] = bitmap.RGB{Red: byte(r >> 8), Green: byte(g >> 8), Blue: byte(b >> 8)}
+	}
+	return pal, nil
+}
+
+func paletteLookupModeNames() string {
+	var names []string
+	for _, mode := range movie.PaletteLookupModes() {
+		names = append(names, mode.String())
+	}
+	return strings.Join(names, ", ")
+}
+
+func paletteLookupModeNamed(name string) (movie.PaletteLookupMode, bool) {
+	for _, mode := range movie.PaletteLookupModes() {
+		if strings.EqualFold(mode.String(), name) {
+			return mode, true
+		}
+	}
+	return movie.PaletteLookupCompact, false
+}
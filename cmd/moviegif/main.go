@@ -0,0 +1,107 @@
+// Command moviegif exports a movie of a world, or a time range of it, as an animated GIF.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"image/gif"
+	"io/ioutil"
+	"os"
+	"strconv"
+
+	"github.com/inkyblackness/hacked/headless"
+	"github.com/inkyblackness/hacked/ss1/content/movie"
+	"github.com/inkyblackness/hacked/ss1/resource"
+)
+
+func main() {
+	dataDir := flag.String("data", "", "Path to the main data directory of the game.")
+	modDir := flag.String("mod", "", "Path to a mod directory to apply on top of the data. Optional.")
+	idText := flag.String("id", "", "Resource ID of the movie, such as 0x0BD6.")
+	langIndex := flag.Int("lang", 0, "Language of the movie: 0 (default), 1 (French), 2 (German).")
+	from := flag.Float64("from", 0, "Start time, in seconds, of the exported range.")
+	to := flag.Float64("to", 0, "End time, in seconds, of the exported range. Zero for the end of the movie.")
+	outFile := flag.String("out", "", "Filename of the GIF to write.")
+	bestEffort := flag.Bool("besteffort", false, "Continue past scenes that can not be decoded, repeating the last good frame.")
+	flag.Parse()
+
+	if (len(*dataDir) == 0) || (len(*idText) == 0) || (len(*outFile) == 0) {
+		fmt.Fprintln(os.Stderr, "The data directory, the movie ID, and the output file must be specified.")
+		flag.Usage()
+		os.Exit(2)
+	}
+	idValue, err := strconv.ParseUint(*idText, 0, 16)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid movie ID: %v\n", err)
+		os.Exit(2)
+	}
+	languages := resource.Languages()
+	if (*langIndex < 0) || (*langIndex >= len(languages)) {
+		fmt.Fprintf(os.Stderr, "Invalid language: %d\n", *langIndex)
+		os.Exit(2)
+	}
+
+	session := headless.NewSession()
+	err = session.AddManifestEntry(*dataDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load data: %v\n", err)
+		os.Exit(1)
+	}
+	if len(*modDir) > 0 {
+		err = session.LoadMod(*modDir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to load mod: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	reader, err := session.Mod().LocalizedResources(languages[*langIndex]).SelectBlock(resource.ID(idValue), 0)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to find movie: %v\n", err)
+		os.Exit(1)
+	}
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to read movie: %v\n", err)
+		os.Exit(1)
+	}
+	container, err := movie.Read(bytes.NewReader(data))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to decode movie: %v\n", err)
+		os.Exit(1)
+	}
+	var animation *gif.GIF
+	if *bestEffort {
+		var sceneErrors []*movie.SceneError
+		animation, sceneErrors = movie.ToGifBestEffort(container, float32(*from), float32(*to))
+		for _, sceneErr := range sceneErrors {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", sceneErr)
+		}
+	} else {
+		animation, err = movie.ToGif(container, float32(*from), float32(*to))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to convert movie: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	if size := movie.EstimatedGifSize(animation); size > movie.GifSizeWarningLimit {
+		fmt.Fprintf(os.Stderr, "Warning: the GIF will be large, about %d MB. Consider exporting a smaller range.\n",
+			size/(1024*1024))
+	}
+
+	file, err := os.Create(*outFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create file: %v\n", err)
+		os.Exit(1)
+	}
+	err = gif.EncodeAll(file, animation)
+	closeErr := file.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to write GIF: %v\n", err)
+		os.Exit(1)
+	}
+}
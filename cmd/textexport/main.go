@@ -0,0 +1,59 @@
+// Command textexport writes all known texts of a world to standard output, without opening a window.
+// It serves as an example for using the headless session.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/inkyblackness/hacked/headless"
+	"github.com/inkyblackness/hacked/ss1/edit"
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/world/ids"
+)
+
+func main() {
+	dataDir := flag.String("data", "", "Path to the main data directory of the game.")
+	modDir := flag.String("mod", "", "Path to a mod directory to apply on top of the data. Optional.")
+	verbose := flag.Bool("v", false, "Log the progress of loading files to standard error.")
+	flag.Parse()
+
+	if len(*dataDir) == 0 {
+		fmt.Fprintln(os.Stderr, "The data directory must be specified.")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	session := headless.NewSession()
+	if *verbose {
+		session.SetLoadProgress(func(filename string, loaded, total int) {
+			fmt.Fprintf(os.Stderr, "Loaded %d/%d: %v\n", loaded, total, filename)
+		})
+	}
+	err := session.AddManifestEntry(*dataDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load data: %v\n", err)
+		os.Exit(1)
+	}
+	if len(*modDir) > 0 {
+		err = session.LoadMod(*modDir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to load mod: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	textService := session.TextService()
+	for _, info := range edit.KnownTexts() {
+		resourceInfo, _ := ids.Info(info.ID)
+		for _, lang := range resource.Languages() {
+			for index := 0; index < resourceInfo.MaxCount; index++ {
+				value := textService.Text(resource.KeyOf(info.ID, lang, index))
+				if len(value) > 0 {
+					fmt.Printf("%v\t%v\t%d\t%v\t%q\n", info.Title, info.ID, index, lang, value)
+				}
+			}
+		}
+	}
+}
@@ -2,6 +2,7 @@ package editor
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/inkyblackness/imgui-go"
 
@@ -15,6 +16,7 @@ import (
 	"github.com/inkyblackness/hacked/editor/messages"
 	"github.com/inkyblackness/hacked/editor/objects"
 	"github.com/inkyblackness/hacked/editor/project"
+	"github.com/inkyblackness/hacked/editor/render"
 	"github.com/inkyblackness/hacked/editor/texts"
 	"github.com/inkyblackness/hacked/editor/textures"
 	"github.com/inkyblackness/hacked/ss1/content/archive"
@@ -34,6 +36,12 @@ import (
 	"github.com/inkyblackness/hacked/ui/opengl"
 )
 
+// onDemandRefreshInterval is the time after which the application is rendered even without any input.
+const onDemandRefreshInterval = time.Second
+
+// textureUploadsPerFrame limits the number of textures downloaded to OpenGL per rendered frame.
+const textureUploadsPerFrame = 16
+
 // Application is the root object of the graphical editor.
 // It is set up by the main method.
 type Application struct {
@@ -48,6 +56,15 @@ type Application struct {
 	FontFile string
 	// FontSize specifies the font size to use.
 	FontSize float32
+	// RenderOnDemand reduces rendering to when it is necessary, instead of continuously.
+	RenderOnDemand bool
+	// LayoutFile specifies the file in which the state of the windows is kept between sessions.
+	// It is written when the application is quit.
+	// If empty, the state is not kept.
+	LayoutFile string
+	// RecoveryFile specifies the file in which unsaved changes are periodically kept, to recover from a crash.
+	// If empty, no recovery file is written.
+	RecoveryFile string
 	// GuiScale is applied when the window is initialized.
 	GuiScale   float32
 	guiContext *gui.Context
@@ -60,8 +77,11 @@ type Application struct {
 	eventDispatcher *event.Dispatcher
 
 	cmdStack       *cmd.Stack
+	undoScope      cmd.UndoScope
+	undoContext    string
+	windowFocus    render.WindowFocus
 	mod            *world.Mod
-	cp             text.Codepage
+	codepages      *text.Codepages
 	textLineCache  *text.Cache
 	textPageCache  *text.Cache
 	messagesCache  *text.ElectronicMessageCache
@@ -75,6 +95,7 @@ type Application struct {
 	levels [archive.MaxLevels]*level.Level
 
 	projectView      *project.View
+	integrityView    *project.IntegrityView
 	archiveView      *archives.View
 	levelControlView *levels.ControlView
 	levelTilesView   *levels.TilesView
@@ -88,10 +109,13 @@ type Application struct {
 	aboutView        *about.View
 	licensesView     *about.LicensesView
 
-	modalState gui.ModalStateWrapper
+	modalState     gui.ModalStateWrapper
+	fileDropNotice fileDropNotice
 
 	failureMessage string
 	failurePending bool
+
+	quitConfirmationPending bool
 }
 
 // InitializeWindow takes the given window and attaches the callbacks.
@@ -99,6 +123,7 @@ func (app *Application) InitializeWindow(window opengl.Window) (err error) {
 	app.window = window
 	app.clipboard.window = window
 	app.gl = window.OpenGL()
+	app.window.SetRenderOnDemand(app.RenderOnDemand, onDemandRefreshInterval)
 
 	app.initSignalling()
 	app.initWindowCallbacks()
@@ -110,6 +135,8 @@ func (app *Application) InitializeWindow(window opengl.Window) (err error) {
 
 	app.initModel()
 	app.initView()
+	app.loadLayout()
+	app.projectView.OfferRecovery()
 
 	app.onWindowResize(app.window.Size())
 
@@ -124,9 +151,10 @@ func (app *Application) onWindowClosed() {
 }
 
 func (app *Application) initWindowCallbacks() {
+	app.window.OnCloseRequest(app.onWindowCloseRequest)
 	app.window.OnClosing(app.onWindowClosing)
 	app.window.OnClosed(app.onWindowClosed)
-	app.window.OnFileDropCallback(app.onFilesDropped)
+	app.window.OnFileDrop(app.onFilesDropped, app.fileDropNotice.show)
 
 	app.window.OnResize(app.onWindowResize)
 
@@ -144,13 +172,25 @@ func (app *Application) initWindowCallbacks() {
 
 func (app *Application) render() {
 	app.dispatchEvents()
+	app.textureCache.UploadPending()
+	if app.textureCache.UploadsPending() {
+		app.window.RequestRender()
+	}
 	app.guiContext.NewFrame()
+	defer func() {
+		if r := recover(); r != nil {
+			app.guiContext.AbortFrame()
+			panic(r)
+		}
+	}()
 
 	app.gl.Clear(opengl.COLOR_BUFFER_BIT)
 
+	app.windowFocus.NewFrame()
 	app.renderMainMenu()
 
 	app.projectView.Render()
+	app.integrityView.Render()
 	app.archiveView.Render()
 	activeLevel := app.levels[app.levelControlView.SelectedLevel()]
 	app.levelControlView.Render(activeLevel)
@@ -163,18 +203,24 @@ func (app *Application) render() {
 	app.animationsView.Render()
 	app.objectsView.Render()
 
-	paletteTexture, _ := app.paletteCache.Palette(0)
+	paletteTexture, _ := app.paletteCache.PaletteFor(graphics.LevelPaletteKey(activeLevel.ID()))
 	app.mapDisplay.Render(app.mod.ObjectProperties(), activeLevel,
 		paletteTexture, app.textureCache.Texture,
-		app.levelTilesView.TextureDisplay(), app.levelTilesView.ColorDisplay(activeLevel))
+		app.levelTilesView.TextureDisplay(), app.levelTilesView.ColorDisplay(activeLevel),
+		app.levelTilesView.ColorScheme(), app.levelTilesView.GridOverlay())
 
 	app.handleFailure()
+	app.handleQuitConfirmation()
 	app.aboutView.Render()
 	app.licensesView.Render()
 
 	app.modalState.Render()
+	_, displayHeight := app.window.Size()
+	app.fileDropNotice.render(float32(displayHeight))
 
 	app.guiContext.Render(app.bitmapTextureForUI)
+	// Keyboard edits are finished once the edited control is deactivated, further commands shall not be merged.
+	app.cmdStack.Interacting(app.guiContext.IsUsingKeyboard())
 }
 
 func (app *Application) initOpenGL() {
@@ -217,17 +263,46 @@ func (app *Application) bitmapTextureForUI(textureID imgui.TextureID) (palette u
 	lang := resource.Language((textureID >> 32) & 0xFF)
 	resourceID := resource.ID((textureID >> 16) & 0xFFFF)
 	blockIndex := int(textureID & 0xFFFF)
+	thumbnailSize := int((textureID >> 40) & 0xFFFF)
 	key := resource.KeyOf(resourceID, lang, blockIndex)
-	tex, err := app.textureCache.Texture(key)
-	if err != nil {
+	var tex *graphics.BitmapTexture
+	var err error
+	if thumbnailSize > 0 {
+		tex, err = app.textureCache.Thumbnails().GetThumbnail(key, thumbnailSize)
+	} else {
+		tex, err = app.textureCache.Texture(key)
+	}
+	if (err != nil) || (tex == nil) {
 		return 0, 0
 	}
 
 	return paletteTexture.Handle(), tex.Handle()
 }
 
-func (app *Application) onWindowClosing() {
+func (app *Application) onWindowCloseRequest() bool {
+	if !app.mod.HasUnsavedChanges() {
+		return true
+	}
+	app.quitConfirmationPending = true
+	return false
+}
+
+func (app *Application) requestQuit() {
+	if app.onWindowCloseRequest() {
+		app.quit()
+	}
+}
+
+// quit closes the window without further confirmation.
+// The window system does not report closing in this case, so the session is finished here.
+func (app *Application) quit() {
+	app.onWindowClosing()
+	app.window.SetCloseRequest(true)
+}
 
+func (app *Application) onWindowClosing() {
+	app.saveLayout()
+	app.projectView.RemoveRecovery()
 }
 
 func (app *Application) onWindowResize(width int, height int) {
@@ -235,8 +310,8 @@ func (app *Application) onWindowResize(width int, height int) {
 	app.gl.Viewport(0, 0, int32(width), int32(height))
 }
 
-func (app *Application) onFilesDropped(names []string) {
-	app.modalState.HandleFiles(names)
+func (app *Application) onFilesDropped(names []string) []opengl.FileDropResult {
+	return app.modalState.HandleFilesWithResults(names)
 }
 
 func (app *Application) onKey(key input.Key, modifier input.Modifier) {
@@ -244,6 +319,9 @@ func (app *Application) onKey(key input.Key, modifier input.Modifier) {
 	switch {
 	case key == input.KeyEscape:
 		app.modalState.SetState(nil)
+	case (key == input.KeyEnter) || (key == input.KeyTab):
+		// Committing an entry finishes the edit, further commands shall not be merged.
+		app.cmdStack.Seal()
 	case key == input.KeyUndo:
 		app.tryUndo()
 	case key == input.KeyRedo:
@@ -286,23 +364,61 @@ func (app *Application) modalActive() bool {
 }
 
 func (app *Application) tryUndo() {
-	if !app.cmdStack.CanUndo() || app.modalActive() {
+	app.tryUndoIn(app.activeUndoContext())
+}
+
+func (app *Application) tryUndoIn(context string) {
+	if !app.cmdStack.CanUndoIn(context) || app.modalActive() {
 		return
 	}
-	err := app.modifyModByCommand(app.cmdStack.Undo)
+	app.undoContext = context
+	err := app.modifyModByCommand(func(modder world.Modder) error {
+		return app.cmdStack.UndoIn(context, modder)
+	})
 	if err != nil {
-		app.onFailure("Undo", "", err)
+		app.onFailure("Undo", context, err)
 	}
 }
 
 func (app *Application) tryRedo() {
-	if !app.cmdStack.CanRedo() || app.modalActive() {
+	app.tryRedoIn(app.activeUndoContext())
+}
+
+// activeUndoContext returns the context of the focused window.
+// As long as no window has been focused, the context of the last queued command is used.
+func (app *Application) activeUndoContext() string {
+	if focused := app.windowFocus.Focused(); len(focused) > 0 {
+		return focused
+	}
+	return app.undoContext
+}
+
+func (app *Application) tryRedoIn(context string) {
+	if !app.cmdStack.CanRedoIn(context) || app.modalActive() {
 		return
 	}
-	err := app.modifyModByCommand(app.cmdStack.Redo)
+	app.undoContext = context
+	err := app.modifyModByCommand(func(modder world.Modder) error {
+		return app.cmdStack.RedoIn(context, modder)
+	})
 	if err != nil {
-		app.onFailure("Redo", "", err)
+		app.onFailure("Redo", context, err)
+	}
+}
+
+func (app *Application) resetCommandStack() {
+	app.cmdStack = new(cmd.Stack)
+	app.cmdStack.SetScope(app.undoScope)
+	app.undoContext = ""
+}
+
+func (app *Application) toggleUndoScope() {
+	if app.undoScope == cmd.UndoScopeContext {
+		app.undoScope = cmd.UndoScopeGlobal
+	} else {
+		app.undoScope = cmd.UndoScopeContext
 	}
+	app.cmdStack.SetScope(app.undoScope)
 }
 
 func (app *Application) modifyModByCommand(modifier func(world.Modder) error) (err error) {
@@ -331,6 +447,9 @@ func (app *Application) onMouseScroll(dx, dy float32) {
 func (app *Application) onMouseButtonDown(buttonMask uint32, modifier input.Modifier) {
 	if !app.guiContext.IsUsingMouse() {
 		app.mapDisplay.MouseButtonDown(app.lastMouseX, app.lastMouseY, buttonMask)
+		app.windowFocus.Focus(undoContextLevels)
+	} else {
+		app.windowFocus.MouseButtonPressed()
 	}
 	app.reportButtonChange(buttonMask, true)
 }
@@ -340,6 +459,8 @@ func (app *Application) onMouseButtonUp(buttonMask uint32, modifier input.Modifi
 		app.mapDisplay.MouseButtonUp(app.lastMouseX, app.lastMouseY, buttonMask, modifier)
 	}
 	app.reportButtonChange(buttonMask, false)
+	// Any drag operation is finished, further commands shall not be merged.
+	app.cmdStack.Seal()
 }
 
 func (app *Application) reportButtonChange(buttonMask uint32, down bool) {
@@ -412,16 +533,16 @@ func (app *Application) initGuiStyle() {
 
 func (app *Application) initSignalling() {
 	app.eventDispatcher = event.NewDispatcher()
-	app.cmdStack = new(cmd.Stack)
+	app.resetCommandStack()
 }
 
 func (app *Application) initModel() {
 	app.mod = world.NewMod(app.resourcesChanged, app.modReset)
 
-	app.cp = text.DefaultCodepage()
-	app.textLineCache = text.NewLineCache(app.cp, app.mod)
-	app.textPageCache = text.NewPageCache(app.cp, app.mod)
-	app.messagesCache = text.NewElectronicMessageCache(app.cp, app.mod)
+	app.codepages = text.NewCodepages(text.DefaultCodepage())
+	app.textLineCache = text.NewLineCache(app.codepages, app.mod)
+	app.textPageCache = text.NewPageCache(app.codepages, app.mod)
+	app.messagesCache = text.NewElectronicMessageCache(app.codepages, app.mod)
 	app.movieCache = movie.NewCache(app.mod)
 
 	for i := 0; i < archive.MaxLevels; i++ {
@@ -430,6 +551,7 @@ func (app *Application) initModel() {
 
 	app.paletteCache = graphics.NewPaletteCache(app.gl, app.mod)
 	app.textureCache = graphics.NewTextureCache(app.gl, app.mod)
+	app.textureCache.SetUploadsPerFrame(textureUploadsPerFrame)
 	app.animationCache = bitmap.NewAnimationCache(app.mod)
 }
 
@@ -441,34 +563,42 @@ func (app *Application) resourcesChanged(modifiedIDs []resource.ID, failedIDs []
 	for _, lvl := range app.levels {
 		lvl.InvalidateResources(modifiedIDs)
 	}
+	if app.levelControlView != nil {
+		app.levelControlView.InvalidateResources(modifiedIDs)
+	}
+	if app.objectsView != nil {
+		app.objectsView.InvalidateResources(modifiedIDs)
+	}
 	app.paletteCache.InvalidateResources(modifiedIDs)
 	app.textureCache.InvalidateResources(modifiedIDs)
 	app.animationCache.InvalidateResources(modifiedIDs)
 }
 
 func (app *Application) modReset() {
-	app.cmdStack = new(cmd.Stack)
+	app.resetCommandStack()
 }
 
 // nolint: lll
 func (app *Application) initView() {
 	textViewer := media.NewTextViewerService(app.textLineCache, app.textPageCache, app.mod)
-	textSetter := media.NewTextSetterService(app.cp)
+	textSetter := media.NewTextSetterService(app.codepages)
 	audioViewer := media.NewAudioViewerService(app.movieCache, app.mod)
 	audioSetter := media.NewAudioSetterService()
-	augmentedTextService := undoable.NewAugmentedTextService(edit.NewAugmentedTextService(textViewer, textSetter, audioViewer, audioSetter), app)
-
-	app.projectView = project.NewView(app.mod, &app.modalState, app.GuiScale, app)
-	app.archiveView = archives.NewArchiveView(app.mod, app.GuiScale, app)
-	app.levelControlView = levels.NewControlView(app.mod, app.GuiScale, app.textLineCache, app.textureCache, app, &app.eventQueue, app.eventDispatcher)
-	app.levelTilesView = levels.NewTilesView(app.mod, app.GuiScale, app.textLineCache, app.textureCache, app, &app.eventQueue, app.eventDispatcher)
-	app.levelObjectsView = levels.NewObjectsView(app.mod, app.GuiScale, app.textLineCache, app.textureCache, app, &app.eventQueue, app.eventDispatcher)
-	app.messagesView = messages.NewMessagesView(app.mod, app.messagesCache, app.cp, app.movieCache, app.textureCache, &app.modalState, app.clipboard, app.GuiScale, app)
-	app.textsView = texts.NewTextsView(augmentedTextService, &app.modalState, app.clipboard, app.GuiScale)
-	app.bitmapsView = bitmaps.NewBitmapsView(app.mod, app.textureCache, app.paletteCache, &app.modalState, app.clipboard, app.GuiScale, app)
-	app.texturesView = textures.NewTexturesView(app.mod, app.textLineCache, app.cp, app.textureCache, app.paletteCache, &app.modalState, app.clipboard, app.GuiScale, app)
-	app.animationsView = animations.NewAnimationsView(app.mod, app.textureCache, app.paletteCache, app.animationCache, &app.modalState, app.GuiScale, app)
-	app.objectsView = objects.NewView(app.mod, app.textLineCache, app.cp, app.textureCache, app.paletteCache, &app.modalState, app.clipboard, app.GuiScale, app)
+	augmentedTextService := undoable.NewAugmentedTextService(edit.NewAugmentedTextService(textViewer, textSetter, audioViewer, audioSetter), cmd.CommanderFor(undoContextTexts, app))
+
+	app.projectView = project.NewView(app.mod, &app.modalState, app.GuiScale, app.windowFocus.Area(undoContextProject), cmd.CommanderFor(undoContextProject, app), app.RecoveryFile)
+	app.integrityView = project.NewIntegrityView(app.mod, app.GuiScale)
+	app.archiveView = archives.NewArchiveView(app.mod, app.codepages, app.GuiScale, app.windowFocus.Area(undoContextLevels), cmd.CommanderFor(undoContextLevels, app))
+	app.levelControlView = levels.NewControlView(app.mod, app.GuiScale, app.windowFocus.Area(undoContextLevels), app.textLineCache, app.textureCache, &app.modalState, cmd.CommanderFor(undoContextLevels, app), &app.eventQueue, app.eventDispatcher)
+	app.levelTilesView = levels.NewTilesView(app.mod, app.GuiScale, app.windowFocus.Area(undoContextLevels), app.textLineCache, app.textureCache, app.clipboard, cmd.CommanderFor(undoContextLevels, app), &app.eventQueue, app.eventDispatcher)
+	app.levelObjectsView = levels.NewObjectsView(app.mod, app.GuiScale, app.windowFocus.Area(undoContextLevels), app.textLineCache, app.codepages, app.textureCache, cmd.CommanderFor(undoContextLevels, app), &app.eventQueue, app.eventDispatcher)
+	app.messagesView = messages.NewMessagesView(app.mod, app.messagesCache, app.codepages, app.movieCache, app.textureCache, &app.modalState, app.clipboard, app.GuiScale, app.windowFocus.Area(undoContextMessages), cmd.CommanderFor(undoContextMessages, app))
+	app.textsView = texts.NewTextsView(augmentedTextService, &app.modalState, app.clipboard, app.GuiScale, app.windowFocus.Area(undoContextTexts))
+	moviePaletteService := undoable.NewMoviePaletteService(media.NewMoviePaletteService(app.movieCache, app.mod), cmd.CommanderFor(undoContextBitmaps, app))
+	app.bitmapsView = bitmaps.NewBitmapsView(app.mod, app.textureCache, app.paletteCache, moviePaletteService, &app.modalState, app.clipboard, app.GuiScale, app.windowFocus.Area(undoContextBitmaps), cmd.CommanderFor(undoContextBitmaps, app))
+	app.texturesView = textures.NewTexturesView(app.mod, app.textLineCache, app.codepages, app.textureCache, app.paletteCache, &app.modalState, app.clipboard, app.GuiScale, app.windowFocus.Area(undoContextTextures), cmd.CommanderFor(undoContextTextures, app))
+	app.animationsView = animations.NewAnimationsView(app.mod, app.textureCache, app.paletteCache, app.animationCache, &app.modalState, app.GuiScale, app.windowFocus.Area(undoContextAnimations), cmd.CommanderFor(undoContextAnimations, app))
+	app.objectsView = objects.NewView(app.mod, app.textLineCache, app.codepages, app.textureCache, app.paletteCache, &app.modalState, app.clipboard, app.GuiScale, app.windowFocus.Area(undoContextObjects), cmd.CommanderFor(undoContextObjects, app))
 	app.aboutView = about.NewView(app.clipboard, app.GuiScale, app.Version)
 	app.licensesView = about.NewLicensesView(app.GuiScale)
 
@@ -477,12 +607,20 @@ func (app *Application) initView() {
 
 // Queue requests to perform the given command.
 func (app *Application) Queue(command cmd.Command) {
+	app.QueueIn("", command)
+}
+
+// QueueIn requests to perform the given command within given context.
+// The context becomes the one in which undo and redo happen.
+func (app *Application) QueueIn(context string, command cmd.Command) {
 	err := app.modifyModByCommand(func(modder world.Modder) error {
-		return app.cmdStack.Perform(command, modder)
+		return app.cmdStack.PerformIn(context, command, modder)
 	})
 	if err != nil {
-		app.onFailure("command", "", err)
+		app.onFailure("command", context, err)
+		return
 	}
+	app.undoContext = context
 }
 
 func (app *Application) dispatchEvents() {
@@ -511,19 +649,30 @@ func (app *Application) renderMainMenu() {
 	if imgui.BeginMainMenuBar() {
 		if imgui.BeginMenu("File") {
 			windowEntry("Project", "F1", app.projectView.WindowOpen())
+			if imgui.MenuItem("Check Project...") {
+				app.integrityView.Check()
+			}
 			imgui.Separator()
 			if imgui.MenuItem("Exit") {
-				app.window.SetCloseRequest(true)
+				app.requestQuit()
 			}
 			imgui.EndMenu()
 		}
 		if imgui.BeginMenu("Edit") {
-			if imgui.MenuItemV("Undo", "Ctrl+Z", false, app.cmdStack.CanUndo()) {
+			if imgui.MenuItemV(app.undoLabel("Undo"), "Ctrl+Z", false, app.cmdStack.CanUndoIn(app.activeUndoContext())) {
 				app.tryUndo()
 			}
-			if imgui.MenuItemV("Redo", "Ctrl+Y / Ctrl+Shift+Z", false, app.cmdStack.CanRedo()) {
+			if imgui.MenuItemV(app.undoLabel("Redo"), "Ctrl+Y / Ctrl+Shift+Z", false, app.cmdStack.CanRedoIn(app.activeUndoContext())) {
 				app.tryRedo()
 			}
+			imgui.Separator()
+			if imgui.MenuItemV("Undo per Window", "", app.undoScope == cmd.UndoScopeContext, true) {
+				app.toggleUndoScope()
+			}
+			if app.undoScope == cmd.UndoScopeContext {
+				app.renderContextUndoMenu("Undo in", app.cmdStack.CanUndoIn, app.tryUndoIn)
+				app.renderContextUndoMenu("Redo in", app.cmdStack.CanRedoIn, app.tryRedoIn)
+			}
 			imgui.EndMenu()
 		}
 		if imgui.BeginMenu("Window") {
@@ -552,6 +701,52 @@ func (app *Application) renderMainMenu() {
 	}
 }
 
+func (app *Application) undoLabel(action string) string {
+	context := app.activeUndoContext()
+	if (app.undoScope != cmd.UndoScopeContext) || (len(context) == 0) {
+		return action
+	}
+	return action + " (" + context + ")"
+}
+
+func (app *Application) renderContextUndoMenu(label string, possible func(string) bool, action func(string)) {
+	if imgui.BeginMenu(label) {
+		activeContext := app.activeUndoContext()
+		for _, context := range undoContexts {
+			if imgui.MenuItemV(context, "", context == activeContext, possible(context)) {
+				action(context)
+			}
+		}
+		imgui.EndMenu()
+	}
+}
+
+func (app *Application) handleQuitConfirmation() {
+	if app.quitConfirmationPending {
+		imgui.OpenPopup("Unsaved Changes")
+		app.quitConfirmationPending = false
+	}
+	if imgui.BeginPopupModal("Unsaved Changes") {
+		imgui.Text(fmt.Sprintf("The mod has unsaved changes in %d resource(s).", len(app.mod.DirtyKeys())))
+		imgui.Text("Quitting now loses these changes.")
+		imgui.Separator()
+		if imgui.Button("Save") {
+			app.projectView.StartSavingMod()
+			imgui.CloseCurrentPopup()
+		}
+		imgui.SameLine()
+		if imgui.Button("Quit") {
+			imgui.CloseCurrentPopup()
+			app.quit()
+		}
+		imgui.SameLine()
+		if imgui.Button("Cancel") {
+			imgui.CloseCurrentPopup()
+		}
+		imgui.EndPopup()
+	}
+}
+
 func (app *Application) handleFailure() {
 	if app.failurePending {
 		imgui.OpenPopup("Failure Message")
@@ -577,12 +772,12 @@ http://www.systemshock.org forums. Thank you!
 		imgui.Separator()
 		if imgui.Button("Ignore") {
 			app.failureMessage = ""
-			app.cmdStack = new(cmd.Stack)
+			app.resetCommandStack()
 			imgui.CloseCurrentPopup()
 		}
 		imgui.SameLine()
 		if imgui.Button("Exit") {
-			app.window.SetCloseRequest(true)
+			app.quit()
 		}
 		imgui.EndPopup()
 	}
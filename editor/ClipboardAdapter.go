@@ -6,6 +6,10 @@ type clipboardAdapter struct {
 	window opengl.Window
 }
 
+func (adapter clipboardAdapter) Content() opengl.ClipboardContent {
+	return adapter.window.ClipboardContent()
+}
+
 func (adapter clipboardAdapter) String() (string, error) {
 	return adapter.window.ClipboardString()
 }
@@ -0,0 +1,55 @@
+package editor
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/inkyblackness/imgui-go"
+
+	"github.com/inkyblackness/hacked/ui/opengl"
+)
+
+// fileDropNoticeDuration is how long the summary of dropped files is shown.
+const fileDropNoticeDuration = 5 * time.Second
+
+// fileDropNotice shows a short-lived summary of the results of dropped files.
+type fileDropNotice struct {
+	results []opengl.FileDropResult
+	time    time.Time
+}
+
+func (notice *fileDropNotice) show(results []opengl.FileDropResult) {
+	notice.results = results
+	notice.time = time.Now()
+}
+
+// isActive returns true while the notice shall be rendered.
+func (notice fileDropNotice) isActive() bool {
+	return (len(notice.results) > 0) && (time.Since(notice.time) < fileDropNoticeDuration)
+}
+
+func (notice *fileDropNotice) render(displayHeight float32) {
+	if !notice.isActive() {
+		return
+	}
+	imgui.SetNextWindowPosV(imgui.Vec2{X: 10, Y: displayHeight - 10}, imgui.ConditionAlways, imgui.Vec2{X: 0, Y: 1})
+	imgui.SetNextWindowBgAlpha(0.8)
+	flags := imgui.WindowFlagsNoTitleBar | imgui.WindowFlagsNoMove | imgui.WindowFlagsNoResize |
+		imgui.WindowFlagsAlwaysAutoResize | imgui.WindowFlagsNoFocusOnAppearing | imgui.WindowFlagsNoNav |
+		imgui.WindowFlagsNoSavedSettings
+	if imgui.BeginV("File Drop Notice", nil, flags) {
+		imgui.Text("Dropped files: " + opengl.FileDropSummary(notice.results))
+		for _, result := range notice.results {
+			if result.Outcome == opengl.FileDropLoaded {
+				continue
+			}
+			line := fmt.Sprintf("%v: %v", filepath.Base(result.Path), result.Outcome)
+			if result.Reason != nil {
+				line += " - " + result.Reason.Error()
+			}
+			imgui.Text(line)
+		}
+	}
+	imgui.End()
+}
@@ -0,0 +1,28 @@
+package editor
+
+// Contexts in which commands are queued, for undo per window.
+// Windows that modify the same resources share one context, as undoing
+// their commands out of order could corrupt the resources.
+// The archive window adds and removes levels, so it shares the context of the level windows.
+// Movie palettes are edited in the bitmaps window, so their changes are undone in the context of that window.
+const (
+	undoContextProject    = "Project"
+	undoContextLevels     = "Levels"
+	undoContextMessages   = "Messages"
+	undoContextTexts      = "Texts"
+	undoContextBitmaps    = "Bitmaps"
+	undoContextTextures   = "Textures"
+	undoContextAnimations = "Animations"
+	undoContextObjects    = "Game Objects"
+)
+
+var undoContexts = []string{
+	undoContextProject,
+	undoContextLevels,
+	undoContextMessages,
+	undoContextTexts,
+	undoContextBitmaps,
+	undoContextTextures,
+	undoContextAnimations,
+	undoContextObjects,
+}
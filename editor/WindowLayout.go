@@ -0,0 +1,99 @@
+package editor
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/inkyblackness/hacked/ui/opengl"
+)
+
+// WindowLayout is the persisted state of the editor windows.
+// Positions and sizes of the windows are kept by the GUI library itself.
+type WindowLayout struct {
+	// OpenWindows tells for each window, by name, whether it is open.
+	OpenWindows map[string]bool `json:"openWindows"`
+	// MainWindow is the geometry of the native window the editor runs in.
+	MainWindow *opengl.WindowGeometry `json:"mainWindow,omitempty"`
+}
+
+type layoutWindow struct {
+	name string
+	open *bool
+}
+
+func (app *Application) layoutWindows() []layoutWindow {
+	return []layoutWindow{
+		{name: "project", open: app.projectView.WindowOpen()},
+		{name: "archive", open: app.archiveView.WindowOpen()},
+		{name: "levelControl", open: app.levelControlView.WindowOpen()},
+		{name: "levelTiles", open: app.levelTilesView.WindowOpen()},
+		{name: "levelObjects", open: app.levelObjectsView.WindowOpen()},
+		{name: "messages", open: app.messagesView.WindowOpen()},
+		{name: "texts", open: app.textsView.WindowOpen()},
+		{name: "bitmaps", open: app.bitmapsView.WindowOpen()},
+		{name: "textures", open: app.texturesView.WindowOpen()},
+		{name: "animations", open: app.animationsView.WindowOpen()},
+		{name: "gameObjects", open: app.objectsView.WindowOpen()},
+	}
+}
+
+// InitialWindowGeometry returns the geometry of the native window as it was stored in the layout file.
+// If the layout file does not provide a valid geometry, the default geometry is returned.
+// The position is moved onto the connected monitors when the native window is created.
+func (app *Application) InitialWindowGeometry() opengl.WindowGeometry {
+	layout, err := app.readLayout()
+	if (err != nil) || (layout.MainWindow == nil) {
+		return opengl.DefaultWindowGeometry()
+	}
+	return layout.MainWindow.OrDefault(nil)
+}
+
+func (app *Application) readLayout() (WindowLayout, error) {
+	var layout WindowLayout
+	data, err := ioutil.ReadFile(app.LayoutFile)
+	if err != nil {
+		return layout, err
+	}
+	err = json.Unmarshal(data, &layout)
+	return layout, err
+}
+
+// loadLayout restores the state of the windows from the layout file.
+// A missing or invalid file is ignored, as are windows that are not known.
+func (app *Application) loadLayout() {
+	if len(app.LayoutFile) == 0 {
+		return
+	}
+	layout, err := app.readLayout()
+	if err != nil {
+		return
+	}
+	for _, window := range app.layoutWindows() {
+		if open, known := layout.OpenWindows[window.name]; known {
+			*window.open = open
+		}
+	}
+}
+
+// saveLayout stores the state of the windows in the layout file, creating its directory if necessary.
+func (app *Application) saveLayout() {
+	if len(app.LayoutFile) == 0 {
+		return
+	}
+	geometry := app.window.Geometry()
+	layout := WindowLayout{OpenWindows: make(map[string]bool), MainWindow: &geometry}
+	for _, window := range app.layoutWindows() {
+		layout.OpenWindows[window.name] = *window.open
+	}
+	data, err := json.MarshalIndent(&layout, "", "  ")
+	if err != nil {
+		return
+	}
+	err = os.MkdirAll(filepath.Dir(app.LayoutFile), 0750)
+	if err != nil {
+		return
+	}
+	_ = ioutil.WriteFile(app.LayoutFile, data, 0640)
+}
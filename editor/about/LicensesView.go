@@ -2,6 +2,8 @@ package about
 
 import (
 	"github.com/inkyblackness/imgui-go"
+
+	"github.com/inkyblackness/hacked/editor/render"
 )
 
 // LicensesView handles the about display.
@@ -30,7 +32,7 @@ func (view *LicensesView) Render() {
 	if view.model.windowOpen {
 		imgui.OpenPopup("Licenses")
 		view.model.windowOpen = false
-		imgui.SetNextWindowSize(imgui.Vec2{X: 640 * view.guiScale, Y: 480 * view.guiScale})
+		imgui.SetNextWindowSize(render.LayoutMetricsFor(view.guiScale).MediumWindowSize())
 	}
 	if imgui.BeginPopupModalV("Licenses", nil, imgui.WindowFlagsHorizontalScrollbar|imgui.WindowFlagsNoSavedSettings) {
 		view.renderContent()
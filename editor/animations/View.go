@@ -3,6 +3,7 @@ package animations
 import (
 	"bytes"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"image"
 	"image/gif"
@@ -44,6 +45,7 @@ type View struct {
 
 	modalStateMachine gui.ModalStateMachine
 	guiScale          float32
+	focus             render.FocusArea
 	commander         cmd.Commander
 
 	model viewModel
@@ -52,7 +54,7 @@ type View struct {
 // NewAnimationsView returns a new instance.
 func NewAnimationsView(mod *world.Mod, imageCache *graphics.TextureCache, paletteCache *graphics.PaletteCache,
 	animationCache *bitmap.AnimationCache,
-	modalStateMachine gui.ModalStateMachine, guiScale float32, commander cmd.Commander) *View {
+	modalStateMachine gui.ModalStateMachine, guiScale float32, focus render.FocusArea, commander cmd.Commander) *View {
 	view := &View{
 		mod:            mod,
 		imageCache:     imageCache,
@@ -61,6 +63,7 @@ func NewAnimationsView(mod *world.Mod, imageCache *graphics.TextureCache, palett
 
 		modalStateMachine: modalStateMachine,
 		guiScale:          guiScale,
+		focus:             focus,
 		commander:         commander,
 
 		model: freshViewModel(),
@@ -81,17 +84,17 @@ func (view *View) Render() {
 		view.model.windowOpen = true
 	}
 	if view.model.windowOpen {
-		imgui.SetNextWindowSizeV(imgui.Vec2{X: 800 * view.guiScale, Y: 300 * view.guiScale}, imgui.ConditionOnce)
+		imgui.SetNextWindowSizeV(render.LayoutMetricsFor(view.guiScale).WideWindowSize(), imgui.ConditionOnce)
 		if imgui.BeginV("Animations", view.WindowOpen(), imgui.WindowFlagsNoCollapse|imgui.WindowFlagsHorizontalScrollbar) {
-			view.renderContent()
+			view.focus.Render(func() { view.renderContent() })
 		}
 		imgui.End()
 	}
 }
 
 func (view *View) renderContent() {
-	if imgui.BeginChildV("Properties", imgui.Vec2{X: 350 * view.guiScale, Y: 0}, false, 0) {
-		imgui.PushItemWidth(-150 * view.guiScale)
+	if imgui.BeginChildV("Properties", imgui.Vec2{X: render.LayoutMetricsFor(view.guiScale).PropertiesWidth(), Y: 0}, false, 0) {
+		imgui.PushItemWidth(render.LayoutMetricsFor(view.guiScale).LabelSpace())
 		if imgui.BeginCombo("Animation Type", knownAnimationTypes[view.model.currentKey.ID].title) {
 			for _, id := range knownAnimationTypesOrder {
 				if imgui.SelectableV(knownAnimationTypes[id].title, id == view.model.currentKey.ID, 0, imgui.Vec2{}) {
@@ -127,7 +130,7 @@ func (view *View) renderContent() {
 			if view.cacheFrame(frameKey) {
 
 				render.TextureImage("Frame", view.imageCache, frameKey,
-					imgui.Vec2{X: float32(anim.Width) * view.guiScale, Y: float32(anim.Height) * view.guiScale})
+					render.LayoutMetricsFor(view.guiScale).Vec2(float32(anim.Width), float32(anim.Height)))
 				if imgui.Button("Export") {
 					view.requestExport()
 				}
@@ -185,25 +188,20 @@ func (view *View) currentAnimation() (bitmap.Animation, bool, bool) {
 func (view *View) requestImport() {
 	info := "File must be an animated GIF file.\nIdeally, it matches the game palette 1:1,\nothers are mapped closest fitting."
 	types := []external.TypeInfo{{Title: "Animation files (*.gif)", Extensions: []string{"gif"}}}
-	var fileHandler func(string)
-
-	fileHandler = func(filename string) {
+	fileHandler := func(filename string) error {
 		reader, err := os.Open(filename)
 		if err != nil {
-			external.Import(view.modalStateMachine, "Could not open file.\n"+info, types, fileHandler, true)
-			return
+			return errors.New("could not open file")
 		}
 		defer func() { _ = reader.Close() }()
 		data, err := gif.DecodeAll(reader)
 		if err != nil {
-			external.Import(view.modalStateMachine, "File not recognized as GIF.\n"+info, types, fileHandler, true)
-			return
+			return errors.New("file not recognized as GIF")
 		}
 
 		palette, err := view.paletteCache.Palette(0)
 		if err != nil {
-			external.Import(view.modalStateMachine, "Can not import image without having a palette loaded.\n"+info, types, fileHandler, true)
-			return
+			return errors.New("can not import image without having a palette loaded")
 		}
 		anim := bitmap.Animation{
 			Width:      int16(data.Config.Width),
@@ -249,9 +247,10 @@ func (view *View) requestImport() {
 		}
 
 		view.requestSetAnimation(anim, frames)
+		return nil
 	}
 
-	external.Import(view.modalStateMachine, info, types, fileHandler, false)
+	external.Import(view.modalStateMachine, info, types, fileHandler)
 }
 
 func (view *View) requestExport() {
@@ -5,6 +5,7 @@ import (
 
 	"github.com/inkyblackness/imgui-go"
 
+	"github.com/inkyblackness/hacked/editor/render"
 	"github.com/inkyblackness/hacked/ss1/content/archive"
 	"github.com/inkyblackness/hacked/ss1/content/archive/level"
 	"github.com/inkyblackness/hacked/ss1/content/archive/level/lvlids"
@@ -17,20 +18,24 @@ import (
 
 // View provides edit controls for the archive.
 type View struct {
-	mod *world.Mod
+	mod       *world.Mod
+	codepages *text.Codepages
 
 	guiScale  float32
+	focus     render.FocusArea
 	commander cmd.Commander
 
 	model viewModel
 }
 
 // NewArchiveView returns a new instance.
-func NewArchiveView(mod *world.Mod, guiScale float32, commander cmd.Commander) *View {
+func NewArchiveView(mod *world.Mod, codepages *text.Codepages, guiScale float32, focus render.FocusArea, commander cmd.Commander) *View {
 	view := &View{
-		mod: mod,
+		mod:       mod,
+		codepages: codepages,
 
 		guiScale:  guiScale,
+		focus:     focus,
 		commander: commander,
 
 		model: freshViewModel(),
@@ -51,9 +56,9 @@ func (view *View) Render() {
 		view.model.windowOpen = true
 	}
 	if view.model.windowOpen {
-		imgui.SetNextWindowSizeV(imgui.Vec2{X: 350 * view.guiScale, Y: 400 * view.guiScale}, imgui.ConditionOnce)
+		imgui.SetNextWindowSizeV(render.LayoutMetricsFor(view.guiScale).Vec2(350, 400), imgui.ConditionOnce)
 		if imgui.BeginV("Archive", view.WindowOpen(), imgui.WindowFlagsNoCollapse) {
-			view.renderContent()
+			view.focus.Render(func() { view.renderContent() })
 		}
 		imgui.End()
 	}
@@ -61,7 +66,7 @@ func (view *View) Render() {
 
 func (view *View) renderContent() {
 	imgui.Text("Levels")
-	imgui.BeginChildV("Levels", imgui.Vec2{X: -100 * view.guiScale, Y: 0}, true, 0)
+	imgui.BeginChildV("Levels", imgui.Vec2{X: render.LayoutMetricsFor(view.guiScale).ButtonColumnSpace(), Y: 0}, true, 0)
 	for id := 0; id < archive.MaxLevels; id++ {
 		inMod := view.hasLevelInMod(id)
 		info := fmt.Sprintf("%d", id)
@@ -113,7 +118,7 @@ func (view *View) requestClearLevel(id int) {
 		}
 
 		if !view.hasGameStateInMod() {
-			command.newData[ids.ArchiveName] = text.DefaultCodepage().Encode("Starting Game | by InkyBlackness HackEd")
+			command.newData[ids.ArchiveName] = view.codepages.ForLanguage(resource.LangAny).Encode("Starting Game | by InkyBlackness HackEd")
 			command.newData[ids.GameState] = make([]byte, archive.GameStateSize)
 		}
 
@@ -0,0 +1,36 @@
+package bitmaps
+
+import (
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/world"
+)
+
+type bitmapBlockChange struct {
+	key     resource.Key
+	oldData []byte
+	newData []byte
+}
+
+// remapPaletteCommand changes the pixels of many bitmaps at once, as one step.
+type remapPaletteCommand struct {
+	model *viewModel
+
+	changes []bitmapBlockChange
+}
+
+func (cmd remapPaletteCommand) Do(modder world.Modder) error {
+	for _, change := range cmd.changes {
+		modder.SetResourceBlock(change.key.Lang, change.key.ID, change.key.Index, change.newData)
+	}
+	cmd.model.restoreFocus = true
+	return nil
+}
+
+func (cmd remapPaletteCommand) Undo(modder world.Modder) error {
+	for index := len(cmd.changes) - 1; index >= 0; index-- {
+		change := cmd.changes[index]
+		modder.SetResourceBlock(change.key.Lang, change.key.ID, change.key.Index, change.oldData)
+	}
+	cmd.model.restoreFocus = true
+	return nil
+}
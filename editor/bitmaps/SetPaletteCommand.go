@@ -0,0 +1,29 @@
+package bitmaps
+
+import (
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/world"
+)
+
+type setPaletteCommand struct {
+	model *viewModel
+
+	id      resource.ID
+	oldData []byte
+	newData []byte
+}
+
+func (cmd setPaletteCommand) Do(modder world.Modder) error {
+	return cmd.perform(modder, cmd.newData)
+}
+
+func (cmd setPaletteCommand) Undo(modder world.Modder) error {
+	return cmd.perform(modder, cmd.oldData)
+}
+
+func (cmd setPaletteCommand) perform(modder world.Modder, data []byte) error {
+	modder.SetResourceBlock(resource.LangAny, cmd.id, 0, data)
+
+	cmd.model.restoreFocus = true
+	return nil
+}
@@ -1,7 +1,9 @@
 package bitmaps
 
 import (
+	"bytes"
 	"fmt"
+	"strconv"
 
 	"github.com/inkyblackness/imgui-go"
 
@@ -9,6 +11,8 @@ import (
 	"github.com/inkyblackness/hacked/editor/graphics"
 	"github.com/inkyblackness/hacked/editor/render"
 	"github.com/inkyblackness/hacked/ss1/content/bitmap"
+	"github.com/inkyblackness/hacked/ss1/edit"
+	"github.com/inkyblackness/hacked/ss1/edit/undoable"
 	"github.com/inkyblackness/hacked/ss1/edit/undoable/cmd"
 	"github.com/inkyblackness/hacked/ss1/resource"
 	"github.com/inkyblackness/hacked/ss1/world"
@@ -39,15 +43,23 @@ var knownBitmapTypesOrder = []resource.ID{
 	ids.GraffitiBitmaps,
 }
 
+func isKnownBitmapType(id resource.ID) bool {
+	_, known := knownBitmapTypes[id]
+	return known
+}
+
 // View provides edit controls for bitmaps.
 type View struct {
 	mod          *world.Mod
 	imageCache   *graphics.TextureCache
 	paletteCache *graphics.PaletteCache
 
+	moviePalettes undoable.MoviePaletteService
+
 	modalStateMachine gui.ModalStateMachine
 	clipboard         external.Clipboard
 	guiScale          float32
+	focus             render.FocusArea
 	commander         cmd.Commander
 
 	model viewModel
@@ -55,16 +67,20 @@ type View struct {
 
 // NewBitmapsView returns a new instance.
 func NewBitmapsView(mod *world.Mod, imageCache *graphics.TextureCache, paletteCache *graphics.PaletteCache,
+	moviePalettes undoable.MoviePaletteService,
 	modalStateMachine gui.ModalStateMachine, clipboard external.Clipboard,
-	guiScale float32, commander cmd.Commander) *View {
+	guiScale float32, focus render.FocusArea, commander cmd.Commander) *View {
 	view := &View{
 		mod:          mod,
 		imageCache:   imageCache,
 		paletteCache: paletteCache,
 
+		moviePalettes: moviePalettes,
+
 		modalStateMachine: modalStateMachine,
 		clipboard:         clipboard,
 		guiScale:          guiScale,
+		focus:             focus,
 		commander:         commander,
 
 		model: freshViewModel(),
@@ -85,17 +101,17 @@ func (view *View) Render() {
 		view.model.windowOpen = true
 	}
 	if view.model.windowOpen {
-		imgui.SetNextWindowSizeV(imgui.Vec2{X: 800 * view.guiScale, Y: 300 * view.guiScale}, imgui.ConditionOnce)
+		imgui.SetNextWindowSizeV(render.LayoutMetricsFor(view.guiScale).WideWindowSize(), imgui.ConditionOnce)
 		if imgui.BeginV("Bitmaps", view.WindowOpen(), imgui.WindowFlagsNoCollapse|imgui.WindowFlagsHorizontalScrollbar) {
-			view.renderContent()
+			view.focus.Render(func() { view.renderContent() })
 		}
 		imgui.End()
 	}
 }
 
 func (view *View) renderContent() {
-	if imgui.BeginChildV("Properties", imgui.Vec2{X: 350 * view.guiScale, Y: 0}, false, 0) {
-		imgui.PushItemWidth(-150 * view.guiScale)
+	if imgui.BeginChildV("Properties", imgui.Vec2{X: render.LayoutMetricsFor(view.guiScale).PropertiesWidth(), Y: 0}, false, 0) {
+		imgui.PushItemWidth(render.LayoutMetricsFor(view.guiScale).LabelSpace())
 		if imgui.BeginCombo("Bitmap Type", knownBitmapTypes[view.model.currentKey.ID].title) {
 			for _, id := range knownBitmapTypesOrder {
 				if imgui.SelectableV(knownBitmapTypes[id].title, id == view.model.currentKey.ID, 0, imgui.Vec2{}) {
@@ -158,11 +174,25 @@ func (view *View) renderContent() {
 			imgui.LabelText("Height", fmt.Sprintf("%d", int(height)))
 		}
 
+		imgui.Separator()
+		imgui.Text("Game Palette")
+		if imgui.Button("Import Palette") {
+			view.requestImportPalette()
+		}
+		imgui.SameLine()
+		if imgui.Button("Export Palette") {
+			view.requestExportPalette()
+		}
+		view.renderPaletteDuplicates()
+
+		imgui.Separator()
+		view.renderMoviePalette()
+
 		imgui.PopItemWidth()
 	}
 	imgui.EndChild()
 	imgui.SameLine()
-	render.TextureImage("Big texture", view.imageCache, view.currentResourceKey(), imgui.Vec2{X: 320 * view.guiScale, Y: 240 * view.guiScale})
+	render.TextureImage("Big texture", view.imageCache, view.currentResourceKey(), render.LayoutMetricsFor(view.guiScale).PreviewSize())
 }
 
 func (view *View) currentResourceKey() resource.Key {
@@ -219,7 +249,7 @@ func (view *View) requestImport(bmpInfo bitmapInfo) {
 		}
 		return palette.Palette(), nil
 	}
-	external.ImportImage(view.modalStateMachine, paletteRetriever, func(bmp bitmap.Bitmap) {
+	external.ImportImage(view.modalStateMachine, paletteRetriever, &view.model.imageColorMetric, func(bmp bitmap.Bitmap) {
 		view.requestSetBitmap(bmp, bmpInfo)
 	})
 }
@@ -267,3 +297,208 @@ func (view *View) requestSetBitmapData(newData []byte) {
 	}
 	view.commander.Queue(command)
 }
+
+func (view *View) renderPaletteDuplicates() {
+	if imgui.Button("Find Duplicate Colors") {
+		view.requestAnalyzePalette()
+	}
+	if !view.model.paletteAnalyzed {
+		return
+	}
+	if len(view.model.paletteDuplicates) == 0 {
+		imgui.Text("The palette has no duplicate colors.")
+	} else {
+		imgui.Text(fmt.Sprintf("Duplicate colors: %d", len(view.model.paletteDuplicates)))
+		for _, duplicate := range view.model.paletteDuplicates {
+			indices := ""
+			for position, index := range duplicate.Indices {
+				if position > 0 {
+					indices += ", "
+				}
+				indices += fmt.Sprintf("0x%02X", index)
+			}
+			imgui.Text(fmt.Sprintf("- RGB %d/%d/%d at %v",
+				duplicate.Color.Red, duplicate.Color.Green, duplicate.Color.Blue, indices))
+		}
+		if imgui.Button("Merge Duplicates") {
+			view.requestMergePaletteDuplicates()
+		}
+		if imgui.IsItemHovered() {
+			imgui.SetTooltip("Changes the bitmaps of this window to use the first index of each duplicated color.\n" +
+				"Indices used by textures, animations, or other bitmaps are kept.\n" +
+				"The freed indices keep their color, and are no longer used by any bitmap.")
+		}
+	}
+	if len(view.model.paletteRemapResult) > 0 {
+		imgui.Text(view.model.paletteRemapResult)
+	}
+}
+
+func (view *View) requestAnalyzePalette() {
+	view.model.paletteRemapResult = ""
+	palette, err := view.paletteCache.Palette(0)
+	if err != nil {
+		view.model.paletteAnalyzed = false
+		return
+	}
+	view.model.paletteDuplicates = palette.Palette().DuplicateColors()
+	view.model.paletteAnalyzed = true
+}
+
+func (view *View) requestMergePaletteDuplicates() {
+	palette, err := view.paletteCache.Palette(0)
+	if err != nil {
+		return
+	}
+	// Only the bitmaps of this window are remapped, as the others belong to other undo contexts.
+	// Indices that those other bitmaps use are kept.
+	foreignIndices, err := edit.UsedPaletteIndices(view.mod, func(id resource.ID) bool { return !isKnownBitmapType(id) })
+	if err != nil {
+		view.model.paletteRemapResult = "Can not merge: " + err.Error()
+		return
+	}
+	remap := palette.Palette().ConsolidationRemap().Keeping(foreignIndices)
+	freed := remap.Freed()
+	if len(freed) == 0 {
+		view.model.paletteRemapResult = "No duplicates can be merged; only special indices, or indices used by other bitmaps, are duplicated."
+		return
+	}
+	changes, err := edit.RemappedBitmaps(view.mod, remap, isKnownBitmapType)
+	if err != nil {
+		view.model.paletteRemapResult = "Can not merge: " + err.Error()
+		return
+	}
+	command := remapPaletteCommand{model: &view.model}
+	for _, change := range changes {
+		command.changes = append(command.changes, bitmapBlockChange{
+			key:     change.Key,
+			oldData: view.mod.ModifiedBlock(change.Key.Lang, change.Key.ID, change.Key.Index),
+			newData: change.Data,
+		})
+	}
+	if len(command.changes) > 0 {
+		view.commander.Queue(command)
+	}
+	view.model.paletteRemapResult = fmt.Sprintf("Freed %d indices, changed %d bitmaps.", len(freed), len(changes))
+}
+
+func (view *View) requestExportPalette() {
+	palette, err := view.paletteCache.Palette(0)
+	if err != nil {
+		return
+	}
+	external.ExportPalette(view.modalStateMachine, "gamepal.pal", palette.Palette())
+}
+
+func (view *View) requestImportPalette() {
+	external.ImportPalette(view.modalStateMachine, view.requestSetPalette)
+}
+
+func (view *View) requestSetPalette(pal bitmap.Palette) {
+	buf := bytes.NewBuffer(nil)
+	_ = bitmap.PaletteFormatRaw.Encode(buf, pal)
+	command := setPaletteCommand{
+		model: &view.model,
+
+		id:      ids.GamePalettesStart,
+		oldData: view.mod.ModifiedBlock(resource.LangAny, ids.GamePalettesStart, 0),
+		newData: buf.Bytes(),
+	}
+	view.commander.Queue(command)
+}
+
+// renderMoviePalette renders the controls to edit the start palette of a movie.
+// The palette is edited as a draft, separate from the game palette, and is only stored in the movie on request.
+func (view *View) renderMoviePalette() {
+	if !imgui.TreeNode("Movie Palette") {
+		return
+	}
+	imgui.InputText("Movie (hex ID or name)", &view.model.moviePaletteIDText)
+	if imgui.BeginCombo("Movie Language", view.model.moviePaletteLang.String()) {
+		for _, lang := range append([]resource.Language{resource.LangAny}, resource.Languages()...) {
+			if imgui.SelectableV(lang.String(), lang == view.model.moviePaletteLang, 0, imgui.Vec2{}) {
+				view.model.moviePaletteLang = lang
+			}
+		}
+		imgui.EndCombo()
+	}
+	if imgui.Button("Load from Movie") {
+		view.requestLoadMoviePalette()
+	}
+	if view.model.moviePalette != nil {
+		key := view.model.moviePaletteKey
+		imgui.Text(fmt.Sprintf("Editing palette of movie %v %v (%v).\nThe game palette is not affected.",
+			key.ID, ids.NameOf(key.ID).Name, key.Lang))
+		gui.StepSliderInt("Color Index", &view.model.moviePaletteIndex, 0, len(view.model.moviePalette)-1)
+		clr := &view.model.moviePalette[view.model.moviePaletteIndex]
+		renderColorComponent("Red", &clr.Red)
+		renderColorComponent("Green", &clr.Green)
+		renderColorComponent("Blue", &clr.Blue)
+		if inEffect, err := view.paletteCache.PaletteFor(key); err == nil {
+			current := inEffect.Palette()[view.model.moviePaletteIndex]
+			imgui.Text(fmt.Sprintf("In movie: %d, %d, %d", current.Red, current.Green, current.Blue))
+		}
+		if imgui.Button("Import") {
+			external.ImportPalette(view.modalStateMachine, func(pal bitmap.Palette) {
+				*view.model.moviePalette = pal
+			})
+		}
+		imgui.SameLine()
+		if imgui.Button("Export") {
+			external.ExportPalette(view.modalStateMachine, "moviepal.pal", *view.model.moviePalette)
+		}
+		imgui.SameLine()
+		if imgui.Button("Write to Movie") {
+			view.requestSetMoviePalette()
+		}
+		imgui.SameLine()
+		if imgui.Button("Discard") {
+			view.model.moviePalette = nil
+			view.model.moviePaletteResult = ""
+		}
+	}
+	if len(view.model.moviePaletteResult) > 0 {
+		imgui.Text(view.model.moviePaletteResult)
+	}
+	imgui.TreePop()
+}
+
+func renderColorComponent(label string, value *byte) {
+	intValue := int(*value)
+	if gui.StepSliderInt(label, &intValue, 0, 255) {
+		*value = byte(intValue)
+	}
+}
+
+func (view *View) requestLoadMoviePalette() {
+	id, known := ids.IDNamed(view.model.moviePaletteIDText)
+	if !known {
+		value, err := strconv.ParseUint(view.model.moviePaletteIDText, 16, 16)
+		if err != nil {
+			view.model.moviePaletteResult = "Invalid movie ID or name."
+			return
+		}
+		id = resource.ID(value)
+	}
+	key := resource.KeyOf(id, view.model.moviePaletteLang, 0)
+	pal, err := view.moviePalettes.StartPalette(key)
+	if err != nil {
+		view.model.moviePaletteResult = "Can not load movie: " + err.Error()
+		return
+	}
+	view.model.moviePaletteKey = key
+	view.model.moviePalette = &pal
+	view.model.moviePaletteResult = ""
+}
+
+func (view *View) requestSetMoviePalette() {
+	key := view.model.moviePaletteKey
+	err := view.moviePalettes.RequestSetStartPalette(key, view.model.moviePalette[:], func() {
+		view.model.restoreFocus = true
+	})
+	if err != nil {
+		view.model.moviePaletteResult = "Can not write palette: " + err.Error()
+		return
+	}
+	view.model.moviePaletteResult = fmt.Sprintf("Palette written to movie %v %v.", key.ID, ids.NameOf(key.ID).Name)
+}
@@ -1,6 +1,7 @@
 package bitmaps
 
 import (
+	"github.com/inkyblackness/hacked/ss1/content/bitmap"
 	"github.com/inkyblackness/hacked/ss1/resource"
 	"github.com/inkyblackness/hacked/ss1/world/ids"
 )
@@ -9,11 +10,25 @@ type viewModel struct {
 	windowOpen   bool
 	restoreFocus bool
 
-	currentKey resource.Key
+	currentKey       resource.Key
+	imageColorMetric bitmap.ColorMetric
+
+	paletteDuplicates  []bitmap.DuplicateColor
+	paletteAnalyzed    bool
+	paletteRemapResult string
+
+	moviePaletteIDText string
+	moviePaletteLang   resource.Language
+	moviePaletteKey    resource.Key
+	moviePalette       *bitmap.Palette
+	moviePaletteIndex  int
+	moviePaletteResult string
 }
 
 func freshViewModel() viewModel {
 	return viewModel{
 		currentKey: resource.KeyOf(ids.MfdDataBitmaps, resource.LangDefault, 0),
+
+		moviePaletteLang: resource.LangAny,
 	}
 }
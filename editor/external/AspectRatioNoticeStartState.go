@@ -0,0 +1,29 @@
+package external
+
+import (
+	"image"
+
+	"github.com/inkyblackness/imgui-go"
+
+	"github.com/inkyblackness/hacked/ui/gui"
+)
+
+type aspectRatioNoticeStartState struct {
+	machine gui.ModalStateMachine
+	source  image.Point
+	target  image.Point
+	count   int
+}
+
+func (state aspectRatioNoticeStartState) Render() {
+	imgui.OpenPopup("Aspect Ratio Changed")
+	state.machine.SetState(&aspectRatioNoticeWaitingState{
+		machine: state.machine,
+		source:  state.source,
+		target:  state.target,
+		count:   state.count,
+	})
+}
+
+func (state aspectRatioNoticeStartState) HandleFiles(names []string) {
+}
@@ -0,0 +1,42 @@
+package external
+
+import (
+	"fmt"
+	"image"
+
+	"github.com/inkyblackness/imgui-go"
+
+	"github.com/inkyblackness/hacked/ui/gui"
+)
+
+type aspectRatioNoticeWaitingState struct {
+	machine gui.ModalStateMachine
+	source  image.Point
+	target  image.Point
+	count   int
+}
+
+func (state *aspectRatioNoticeWaitingState) Render() {
+	if imgui.BeginPopupModalV("Aspect Ratio Changed", nil,
+		imgui.WindowFlagsNoResize|imgui.WindowFlagsNoMove|imgui.WindowFlagsNoSavedSettings|imgui.WindowFlagsAlwaysAutoResize) {
+		if state.count > 1 {
+			imgui.Text(fmt.Sprintf("%d images were imported, yet they were stretched to fit.", state.count))
+		} else {
+			imgui.Text("The image was imported, yet it was stretched to fit.")
+		}
+		imgui.Text(fmt.Sprintf("Source size: %d x %d px, imported size: %d x %d px",
+			state.source.X, state.source.Y, state.target.X, state.target.Y))
+		imgui.Text("To keep the aspect ratio, import the image again with the \"Letterbox\" option.")
+		imgui.Separator()
+		if imgui.Button("OK") {
+			state.machine.SetState(nil)
+			imgui.CloseCurrentPopup()
+		}
+		imgui.EndPopup()
+	} else {
+		state.machine.SetState(nil)
+	}
+}
+
+func (state *aspectRatioNoticeWaitingState) HandleFiles(names []string) {
+}
@@ -0,0 +1,26 @@
+package external
+
+import (
+	"github.com/inkyblackness/imgui-go"
+
+	"github.com/inkyblackness/hacked/ss1/content/audio"
+	"github.com/inkyblackness/hacked/ui/gui"
+)
+
+type audioTrimReportStartState struct {
+	machine gui.ModalStateMachine
+	trimmed int
+	sound   audio.L8
+}
+
+func (state audioTrimReportStartState) Render() {
+	imgui.OpenPopup("Silence Trimmed")
+	state.machine.SetState(&audioTrimReportWaitingState{
+		machine: state.machine,
+		trimmed: state.trimmed,
+		sound:   state.sound,
+	})
+}
+
+func (state audioTrimReportStartState) HandleFiles(names []string) {
+}
@@ -0,0 +1,36 @@
+package external
+
+import (
+	"fmt"
+
+	"github.com/inkyblackness/imgui-go"
+
+	"github.com/inkyblackness/hacked/ss1/content/audio"
+	"github.com/inkyblackness/hacked/ui/gui"
+)
+
+type audioTrimReportWaitingState struct {
+	machine gui.ModalStateMachine
+	trimmed int
+	sound   audio.L8
+}
+
+func (state *audioTrimReportWaitingState) Render() {
+	if imgui.BeginPopupModalV("Silence Trimmed", nil,
+		imgui.WindowFlagsNoResize|imgui.WindowFlagsNoMove|imgui.WindowFlagsNoSavedSettings|imgui.WindowFlagsAlwaysAutoResize) {
+		imgui.Text("The audio was imported without its leading and trailing silence.")
+		imgui.Text(fmt.Sprintf("Trimmed samples: %d", state.trimmed))
+		imgui.Text(fmt.Sprintf("Remaining samples: %d (%.3f sec)", len(state.sound.Samples), state.sound.Duration()))
+		imgui.Separator()
+		if imgui.Button("OK") {
+			state.machine.SetState(nil)
+			imgui.CloseCurrentPopup()
+		}
+		imgui.EndPopup()
+	} else {
+		state.machine.SetState(nil)
+	}
+}
+
+func (state *audioTrimReportWaitingState) HandleFiles(names []string) {
+}
@@ -1,7 +1,12 @@
 package external
 
+import "github.com/inkyblackness/hacked/ui/opengl"
+
 // Clipboard represents a temporary storage.
 type Clipboard interface {
+	// Content returns what the clipboard currently holds.
+	// It allows to offer only those actions that can work with the current content.
+	Content() opengl.ClipboardContent
 	// String returns the current value of the clipboard, if it is compatible with UTF-8.
 	String() (string, error)
 	// SetString sets the current value of the clipboard as UTF-8 string.
@@ -22,6 +22,19 @@ func Export(machine gui.ModalStateMachine, info string, callback func(string), l
 	})
 }
 
+// ReportWarnings shows the given warnings of a completed operation, below the given info.
+// Nothing is shown if there are no warnings.
+func ReportWarnings(machine gui.ModalStateMachine, info string, warnings []string) {
+	if len(warnings) == 0 {
+		return
+	}
+	machine.SetState(&warningReportStartState{
+		machine:  machine,
+		info:     info,
+		warnings: warnings,
+	})
+}
+
 // ExportAudio is a helper wrapper for exporting audio.
 func ExportAudio(machine gui.ModalStateMachine, filename string, sound audio.L8) {
 	info := "File to be written: " + filename
@@ -69,3 +82,25 @@ func ExportImage(machine gui.ModalStateMachine, filename string, bmp bitmap.Bitm
 
 	Export(machine, info, exportTo, false)
 }
+
+// ExportPalette is a helper wrapper for exporting palettes.
+// The format is determined by the extension of the file name.
+func ExportPalette(machine gui.ModalStateMachine, filename string, pal bitmap.Palette) {
+	info := "File to be written: " + filename
+	var exportTo func(string)
+
+	exportTo = func(dirname string) {
+		writer, err := os.Create(filepath.Join(dirname, filename))
+		if err != nil {
+			Export(machine, "Could not create file.\n"+info, exportTo, true)
+			return
+		}
+		defer func() { _ = writer.Close() }()
+		err = bitmap.PaletteFormatForFilename(filename).Encode(writer, pal)
+		if err != nil {
+			Export(machine, info, exportTo, true)
+		}
+	}
+
+	Export(machine, info, exportTo, false)
+}
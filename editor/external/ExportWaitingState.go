@@ -1,6 +1,7 @@
 package external
 
 import (
+	"errors"
 	"os"
 	"time"
 
@@ -8,6 +9,7 @@ import (
 	"github.com/sqweek/dialog"
 
 	"github.com/inkyblackness/hacked/ui/gui"
+	"github.com/inkyblackness/hacked/ui/opengl"
 )
 
 type exportWaitingState struct {
@@ -58,13 +60,18 @@ where to save the data into the editor window.
 }
 
 func (state *exportWaitingState) HandleFiles(names []string) {
+	state.HandleFilesWithResults(names)
+}
+
+func (state *exportWaitingState) HandleFilesWithResults(names []string) []opengl.FileDropResult {
 	dirPath, ok := state.verifyDir(names)
-	if ok {
-		state.machine.SetState(nil)
-		state.callback(dirPath)
-	} else {
+	if !ok {
 		state.failureTime = time.Now()
+		return opengl.FileDropResultsOf(names, opengl.FileDropIgnored, errors.New("exactly one folder is expected"))
 	}
+	state.machine.SetState(nil)
+	state.callback(dirPath)
+	return opengl.FileDropResultsOf(names, opengl.FileDropLoaded, nil)
 }
 
 func (state exportWaitingState) verifyDir(names []string) (string, bool) {
@@ -1,11 +1,15 @@
 package external
 
 import (
+	"errors"
+	"fmt"
 	"image"
 	"image/color"
 	"math"
 	"os"
 
+	"github.com/inkyblackness/imgui-go"
+
 	"github.com/inkyblackness/hacked/ss1/content/audio"
 	"github.com/inkyblackness/hacked/ss1/content/audio/wav"
 	"github.com/inkyblackness/hacked/ss1/content/bitmap"
@@ -13,65 +17,182 @@ import (
 )
 
 // Import starts an import dialog series, calling the given callback with a file name.
-func Import(machine gui.ModalStateMachine, info string, types []TypeInfo, callback func(string), lastFailed bool) {
+// Should the callback return an error, the dialog stays open and shows the error, allowing another attempt.
+func Import(machine gui.ModalStateMachine, info string, types []TypeInfo, callback func(string) error) {
+	importWithOptions(machine, info, types, nil, callback)
+}
+
+// importWithOptions starts an import dialog series that additionally renders the given options, if not nil.
+func importWithOptions(machine gui.ModalStateMachine, info string, types []TypeInfo, options func(),
+	callback func(string) error) {
 	machine.SetState(&importStartState{
-		machine:   machine,
-		callback:  callback,
-		info:      info,
-		typeInfo:  types,
-		withError: lastFailed,
+		machine:  machine,
+		callback: callback,
+		info:     info,
+		typeInfo: types,
+		options:  options,
 	})
 }
 
+var (
+	errFileNotOpened    = errors.New("could not open file")
+	errNoPaletteForFile = errors.New("can not import image without having a palette loaded")
+)
+
+// renderImageImportOptions lets the user select the metric used to map the colors of imported images to the palette.
+func renderImageImportOptions(colorMetric *bitmap.ColorMetric) {
+	if imgui.BeginCombo("Color Metric", colorMetric.String()) {
+		for _, metric := range bitmap.ColorMetrics() {
+			if imgui.SelectableV(metric.String(), metric == *colorMetric, 0, imgui.Vec2{}) {
+				*colorMetric = metric
+			}
+		}
+		imgui.EndCombo()
+	}
+	imgui.Text("Used for images that do not match the palette.\nCIELAB is perceptually most accurate, RGB is fastest.")
+}
+
+// audioTrimSilence determines whether leading and trailing silence is removed from imported audio.
+// It is kept for the whole session, as is the threshold.
+var audioTrimSilence = false
+
+// audioSilenceThreshold is the amplitude below which samples are considered silent.
+var audioSilenceThreshold int32 = 2
+
+func renderAudioImportOptions() {
+	imgui.Checkbox("Trim Silence", &audioTrimSilence)
+	if audioTrimSilence {
+		imgui.SliderInt("Silence Threshold", &audioSilenceThreshold, 1, 127)
+	}
+	imgui.Text("Removes leading and trailing samples that are quieter than the threshold.")
+}
+
 // ImportAudio is a helper to handle audio file import. The callback is called with the loaded audio.
+// Leading and trailing silence is optionally trimmed. Should samples be trimmed, a report is shown after the import.
 func ImportAudio(machine gui.ModalStateMachine, callback func(l8 audio.L8)) {
 	info := "File must be a WAV file, 22050 Hz, 8-bit or 16-bit, uncompressed."
 	types := []TypeInfo{{Title: "Audio files (*.wav)", Extensions: []string{"wav"}}}
-	var fileHandler func(string)
-
-	fileHandler = func(filename string) {
+	fileHandler := func(filename string) error {
 		reader, err := os.Open(filename)
 		if err != nil {
-			Import(machine, info, types, fileHandler, true)
-			return
+			return errFileNotOpened
 		}
 		defer func() { _ = reader.Close() }()
 		sound, err := wav.Load(reader)
 		if err != nil {
-			Import(machine, info, types, fileHandler, true)
-			return
+			return errors.New("file not recognized as supported WAV")
+		}
+		trimmed := 0
+		if audioTrimSilence {
+			sound, trimmed = sound.SilenceTrimmed(byte(audioSilenceThreshold))
 		}
 		callback(sound)
+		if trimmed > 0 {
+			machine.SetState(&audioTrimReportStartState{machine: machine, trimmed: trimmed, sound: sound})
+		}
+		return nil
+	}
+
+	importWithOptions(machine, info, types, renderAudioImportOptions, fileHandler)
+}
+
+// ImportPalette is a helper to handle palette file import. The callback is called with the loaded palette.
+func ImportPalette(machine gui.ModalStateMachine, callback func(bitmap.Palette)) {
+	info := "File must be a JASC (*.pal), GIMP (*.gpl), or raw RGB (*.act) palette.\nAt most 256 colors are supported."
+	types := []TypeInfo{{Title: "Palette files (*.pal, *.gpl, *.act)", Extensions: []string{"pal", "gpl", "act"}}}
+	fileHandler := func(filename string) error {
+		reader, err := os.Open(filename)
+		if err != nil {
+			return errFileNotOpened
+		}
+		defer func() { _ = reader.Close() }()
+		pal, err := bitmap.PaletteFormatForFilename(filename).Decode(reader)
+		if err != nil {
+			return errors.New("file not recognized as palette")
+		}
+		callback(pal)
+		return nil
 	}
 
-	Import(machine, info, types, fileHandler, false)
+	Import(machine, info, types, fileHandler)
+}
+
+// imageResizeMode is the mode used to scale imported images to a required size.
+// It is kept for the whole session, as is the letterbox option.
+var imageResizeMode = bitmap.ResizeBilinear
+
+// imageLetterbox determines whether images of a different aspect ratio are letterboxed instead of stretched.
+var imageLetterbox = false
+
+func renderImageResizeOptions(colorMetric *bitmap.ColorMetric) {
+	renderImageImportOptions(colorMetric)
+	imgui.Separator()
+	if imgui.BeginCombo("Resize Mode", imageResizeMode.String()) {
+		for _, mode := range bitmap.ResizeModes() {
+			if imgui.SelectableV(mode.String(), mode == imageResizeMode, 0, imgui.Vec2{}) {
+				imageResizeMode = mode
+			}
+		}
+		imgui.EndCombo()
+	}
+	imgui.Checkbox("Letterbox", &imageLetterbox)
+	imgui.Text("Images of other size are scaled to the required size.\nLetterbox keeps the aspect ratio and leaves a transparent border.")
 }
 
 // ImportImage is a helper to handle image file import. The callback is called with the loaded image.
-func ImportImage(machine gui.ModalStateMachine, paletteRetriever func() (bitmap.Palette, error), callback func(bitmap.Bitmap)) {
+// The colors of images that do not match the palette are mapped with the given metric, which the user
+// can change in the dialog.
+// Should the colors of a mapped image not fit the palette well, a warning is shown after the import.
+func ImportImage(machine gui.ModalStateMachine, paletteRetriever func() (bitmap.Palette, error),
+	colorMetric *bitmap.ColorMetric, callback func(bitmap.Bitmap)) {
+	importImage(machine, paletteRetriever, image.Point{}, colorMetric, callback)
+}
+
+// ImportImageSized is a helper to handle image file import for images of a required size.
+// Images of other size are scaled in RGB space before they are mapped to the palette.
+// Should the aspect ratio change by stretching the image, a notice is shown after the import.
+func ImportImageSized(machine gui.ModalStateMachine, paletteRetriever func() (bitmap.Palette, error),
+	size image.Point, colorMetric *bitmap.ColorMetric, callback func(bitmap.Bitmap)) {
+	importImage(machine, paletteRetriever, size, colorMetric, callback)
+}
+
+func importImage(machine gui.ModalStateMachine, paletteRetriever func() (bitmap.Palette, error),
+	size image.Point, colorMetric *bitmap.ColorMetric, callback func(bitmap.Bitmap)) {
 	info := "File should be either a PNG or a GIF file.\nPaletted images matching game palette are taken 1:1,\nothers are mapped closest fitting."
 	types := []TypeInfo{{Title: "Image files (*.gif, *.png)", Extensions: []string{"png", "gif"}}}
-	var fileHandler func(string)
-
-	fileHandler = func(filename string) {
+	options := func() { renderImageImportOptions(colorMetric) }
+	if (size.X > 0) && (size.Y > 0) {
+		info += fmt.Sprintf("\nThe image will be scaled to %d x %d pixels.", size.X, size.Y)
+		options = func() { renderImageResizeOptions(colorMetric) }
+	}
+	fileHandler := func(filename string) error {
 		reader, err := os.Open(filename)
 		if err != nil {
-			Import(machine, "Could not open file.\n"+info, types, fileHandler, true)
-			return
+			return errFileNotOpened
 		}
 		defer func() { _ = reader.Close() }()
 		img, _, err := image.Decode(reader)
 		if err != nil {
-			Import(machine, "File not recognized as image.\n"+info, types, fileHandler, true)
-			return
+			return errors.New("file not recognized as image")
+		}
+		sourceSize := img.Bounds().Size()
+		var followUp gui.ModalState
+		if (size.X > 0) && (size.Y > 0) && (sourceSize != size) {
+			if imageLetterbox {
+				img = bitmap.Letterboxed(img, size.X, size.Y, imageResizeMode)
+			} else {
+				if bitmap.AspectRatioChanged(sourceSize, size) {
+					followUp = &aspectRatioNoticeStartState{machine: machine, source: sourceSize, target: size}
+				}
+				img = bitmap.Resized(img, size.X, size.Y, imageResizeMode)
+			}
 		}
 
 		var bmp bitmap.Bitmap
 		importMapped := true
 		rawPalette, err := paletteRetriever()
 		if err != nil {
-			Import(machine, "Can not import image without having a palette loaded.\n"+info, types, fileHandler, true)
-			return
+			return errNoPaletteForFile
 		}
 		if palettedImg, isPaletted := img.(image.PalettedImage); isPaletted {
 			imgPalette, hasPalette := palettedImg.ColorModel().(color.Palette)
@@ -89,14 +210,23 @@ func ImportImage(machine gui.ModalStateMachine, paletteRetriever func() (bitmap.
 				importMapped = false
 			}
 		}
+		var fit bitmap.PaletteFit
 		if importMapped {
-			bitmapper := bitmap.NewBitmapper(&rawPalette)
+			bitmapper := bitmap.NewBitmapperWithMetric(&rawPalette, *colorMetric)
 			bmp = bitmapper.Map(img)
+			fit = bitmapper.Fit(img)
 		}
 		callback(bmp)
+		if fit.ReindexRecommended {
+			followUp = &paletteFitWarningStartState{machine: machine, fit: fit, next: followUp}
+		}
+		if followUp != nil {
+			machine.SetState(followUp)
+		}
+		return nil
 	}
 
-	Import(machine, info, types, fileHandler, false)
+	importWithOptions(machine, info, types, options, fileHandler)
 }
 
 func paletteMatches(imgPalette color.Palette, rawPalette color.Palette) bool {
@@ -0,0 +1,132 @@
+package external
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/inkyblackness/hacked/ss1/content/bitmap"
+	"github.com/inkyblackness/hacked/ui/gui"
+)
+
+// ImportImageFolder is a helper to import all images of a folder, for images of a required size.
+// The user selects the folder, of which all PNG and GIF files are loaded in alphabetical order,
+// up to the given limit. Images are scaled with the options of the single image import, and are mapped with
+// the given color metric, which the user can change in the dialog.
+// Mapping to the palette is done with the given number of concurrent workers, see bitmap.Bitmapper.MapAll().
+// Files that can not be loaded are skipped and reported after the import.
+// The callback is called with all the mapped images, and is not called if no image could be loaded.
+func ImportImageFolder(machine gui.ModalStateMachine, paletteRetriever func() (bitmap.Palette, error),
+	size image.Point, limit int, concurrency int, colorMetric *bitmap.ColorMetric, callback func([]bitmap.Bitmap)) {
+	info := fmt.Sprintf("All PNG and GIF files of the folder are loaded in alphabetical order, up to %d.\n"+
+		"The images will be scaled to %d x %d pixels.", limit, size.X, size.Y)
+	folderHandler := func(dirname string) error {
+		rawPalette, err := paletteRetriever()
+		if err != nil {
+			return errNoPaletteForFile
+		}
+		loaded, err := loadFolderImages(dirname, size, limit)
+		if err != nil {
+			return err
+		}
+		if len(loaded.images) == 0 {
+			if len(loaded.skipped) > 0 {
+				return fmt.Errorf("none of the %d image files could be loaded", len(loaded.skipped))
+			}
+			return errors.New("folder contains no PNG or GIF files")
+		}
+		bitmapper := bitmap.NewBitmapperWithMetric(&rawPalette, *colorMetric)
+		callback(bitmapper.MapAll(loaded.images, concurrency))
+
+		var followUp gui.ModalState
+		if loaded.stretched > 0 {
+			followUp = &aspectRatioNoticeStartState{
+				machine: machine,
+				source:  loaded.stretchedSource,
+				target:  size,
+				count:   loaded.stretched,
+			}
+		}
+		if len(loaded.skipped) > 0 {
+			followUp = &skippedFilesReportStartState{machine: machine, skipped: loaded.skipped, next: followUp}
+		}
+		if followUp != nil {
+			machine.SetState(followUp)
+		}
+		return nil
+	}
+	folderImportWithOptions(machine, info, func() { renderImageResizeOptions(colorMetric) }, folderHandler)
+}
+
+// folderImportWithOptions starts an import dialog series for a folder, rendering the given options.
+func folderImportWithOptions(machine gui.ModalStateMachine, info string, options func(), callback func(string) error) {
+	machine.SetState(&importStartState{
+		machine:  machine,
+		callback: callback,
+		info:     info,
+		options:  options,
+		folder:   true,
+	})
+}
+
+type folderImages struct {
+	images          []image.Image
+	skipped         []string
+	stretched       int
+	stretchedSource image.Point
+}
+
+func loadFolderImages(dirname string, size image.Point, limit int) (folderImages, error) {
+	var loaded folderImages
+	entries, err := ioutil.ReadDir(dirname)
+	if err != nil {
+		return loaded, err
+	}
+	for _, entry := range entries {
+		if len(loaded.images) >= limit {
+			break
+		}
+		extension := strings.ToLower(filepath.Ext(entry.Name()))
+		if entry.IsDir() || ((extension != ".png") && (extension != ".gif")) {
+			continue
+		}
+		img, err := loadImage(filepath.Join(dirname, entry.Name()))
+		if err != nil {
+			loaded.skipped = append(loaded.skipped, entry.Name()+": "+err.Error())
+			continue
+		}
+		sourceSize := img.Bounds().Size()
+		if sourceSize != size {
+			if imageLetterbox {
+				img = bitmap.Letterboxed(img, size.X, size.Y, imageResizeMode)
+			} else {
+				if bitmap.AspectRatioChanged(sourceSize, size) {
+					if loaded.stretched == 0 {
+						loaded.stretchedSource = sourceSize
+					}
+					loaded.stretched++
+				}
+				img = bitmap.Resized(img, size.X, size.Y, imageResizeMode)
+			}
+		}
+		loaded.images = append(loaded.images, img)
+	}
+	return loaded, nil
+}
+
+func loadImage(filename string) (image.Image, error) {
+	reader, err := os.Open(filename)
+	if err != nil {
+		return nil, errFileNotOpened
+	}
+	defer func() { _ = reader.Close() }()
+	img, _, err := image.Decode(reader)
+	if err != nil {
+		return nil, errors.New("file not recognized as image")
+	}
+	return img, nil
+}
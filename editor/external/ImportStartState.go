@@ -1,33 +1,30 @@
 package external
 
 import (
-	"time"
-
 	"github.com/inkyblackness/imgui-go"
 
 	"github.com/inkyblackness/hacked/ui/gui"
 )
 
 type importStartState struct {
-	machine   gui.ModalStateMachine
-	callback  func(string)
-	info      string
-	typeInfo  []TypeInfo
-	withError bool
+	machine  gui.ModalStateMachine
+	callback func(string) error
+	info     string
+	typeInfo []TypeInfo
+	options  func()
+	folder   bool
 }
 
 func (state importStartState) Render() {
 	imgui.OpenPopup("Import")
-	nextState := &importWaitingState{
+	state.machine.SetState(&importWaitingState{
 		machine:  state.machine,
 		callback: state.callback,
 		info:     state.info,
 		typeInfo: state.typeInfo,
-	}
-	if state.withError {
-		nextState.failureTime = time.Now()
-	}
-	state.machine.SetState(nextState)
+		options:  state.options,
+		folder:   state.folder,
+	})
 }
 
 func (state importStartState) HandleFiles(names []string) {
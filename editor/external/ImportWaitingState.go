@@ -1,6 +1,7 @@
 package external
 
 import (
+	"errors"
 	"os"
 	"time"
 
@@ -8,45 +9,47 @@ import (
 	"github.com/sqweek/dialog"
 
 	"github.com/inkyblackness/hacked/ui/gui"
+	"github.com/inkyblackness/hacked/ui/opengl"
 )
 
 type importWaitingState struct {
 	machine  gui.ModalStateMachine
-	callback func(string)
+	callback func(string) error
 	info     string
 	typeInfo []TypeInfo
+	options  func()
+	folder   bool
 
-	failureTime time.Time
+	failureTime   time.Time
+	failureReason string
 }
 
 func (state *importWaitingState) Render() {
 	if imgui.BeginPopupModalV("Import", nil,
 		imgui.WindowFlagsNoResize|imgui.WindowFlagsNoMove|imgui.WindowFlagsNoSavedSettings|imgui.WindowFlagsAlwaysAutoResize) {
 
-		imgui.Text("Waiting for file.")
+		subject := "file"
+		if state.folder {
+			subject = "folder"
+		}
+		imgui.Text("Waiting for " + subject + ".")
 		if !state.failureTime.IsZero() {
 			imgui.PushStyleColor(imgui.StyleColorText, imgui.Vec4{X: 1, Y: 0, Z: 0, W: 1})
-			imgui.Text("Previous attempt failed, could not load file.\nPlease check and try again.")
+			imgui.Text("Previous attempt failed: " + state.failureReason + ".\nPlease check and try again.")
 			imgui.PopStyleColor()
 			if time.Since(state.failureTime).Seconds() > 5 {
 				state.failureTime = time.Time{}
 			}
 		}
-		imgui.Text(`From your file browser drag'n'drop the file
-that shall be loaded into the editor window.
-`)
+		imgui.Text("From your file browser drag'n'drop the " + subject + "\nthat shall be loaded into the editor window.\n")
 		imgui.Text(state.info)
+		if state.options != nil {
+			imgui.Separator()
+			state.options()
+		}
 		imgui.Separator()
 		if imgui.Button("Browse...") {
-			dlgBuilder := dialog.File()
-			for _, info := range state.typeInfo {
-				dlgBuilder = dlgBuilder.Filter(info.Title, info.Extensions...)
-			}
-			dlgBuilder = dlgBuilder.Filter("All files (*.*)", "*")
-			filename, err := dlgBuilder.Load()
-			if err == nil {
-				state.HandleFiles([]string{filename})
-			}
+			state.browse()
 		}
 		imgui.SameLine()
 		if imgui.Button("Cancel") {
@@ -59,26 +62,69 @@ that shall be loaded into the editor window.
 	}
 }
 
+func (state *importWaitingState) browse() {
+	if state.folder {
+		dirname, err := dialog.Directory().Title("Import Folder").Browse()
+		if err == nil {
+			state.HandleFiles([]string{dirname})
+		}
+		return
+	}
+	dlgBuilder := dialog.File()
+	for _, info := range state.typeInfo {
+		dlgBuilder = dlgBuilder.Filter(info.Title, info.Extensions...)
+	}
+	dlgBuilder = dlgBuilder.Filter("All files (*.*)", "*")
+	filename, err := dlgBuilder.Load()
+	if err == nil {
+		state.HandleFiles([]string{filename})
+	}
+}
+
 func (state *importWaitingState) HandleFiles(names []string) {
-	filename, ok := state.verifyFile(names)
-	if ok {
-		state.machine.SetState(nil)
-		state.callback(filename)
-	} else {
-		state.failureTime = time.Now()
+	state.HandleFilesWithResults(names)
+}
+
+func (state *importWaitingState) HandleFilesWithResults(names []string) []opengl.FileDropResult {
+	filename, err := state.verifyFile(names)
+	if err != nil {
+		state.fail(err)
+		return opengl.FileDropResultsOf(names, opengl.FileDropIgnored, err)
+	}
+	state.machine.SetState(nil)
+	err = state.callback(filename)
+	if err != nil {
+		state.fail(err)
+		state.machine.SetState(state)
+		return opengl.FileDropResultsOf(names, opengl.FileDropFailed, err)
 	}
+	return opengl.FileDropResultsOf(names, opengl.FileDropLoaded, nil)
 }
 
-func (state importWaitingState) verifyFile(names []string) (string, bool) {
+func (state *importWaitingState) fail(err error) {
+	state.failureTime = time.Now()
+	state.failureReason = err.Error()
+}
+
+func (state importWaitingState) verifyFile(names []string) (string, error) {
 	if len(names) != 1 {
-		return "", false
+		if state.folder {
+			return "", errors.New("exactly one folder is expected")
+		}
+		return "", errors.New("exactly one file is expected")
 	}
 	fileInfo, err := os.Stat(names[0])
 	if err != nil {
-		return "", false
+		return "", err
+	}
+	if state.folder {
+		if !fileInfo.IsDir() {
+			return "", errors.New("a folder is expected, not a file")
+		}
+		return names[0], nil
 	}
 	if fileInfo.IsDir() {
-		return "", false
+		return "", errors.New("a file is expected, not a directory")
 	}
-	return names[0], true
+	return names[0], nil
 }
@@ -0,0 +1,26 @@
+package external
+
+import (
+	"github.com/inkyblackness/imgui-go"
+
+	"github.com/inkyblackness/hacked/ss1/content/bitmap"
+	"github.com/inkyblackness/hacked/ui/gui"
+)
+
+type paletteFitWarningStartState struct {
+	machine gui.ModalStateMachine
+	fit     bitmap.PaletteFit
+	next    gui.ModalState
+}
+
+func (state paletteFitWarningStartState) Render() {
+	imgui.OpenPopup("Palette Mismatch")
+	state.machine.SetState(&paletteFitWarningWaitingState{
+		machine: state.machine,
+		fit:     state.fit,
+		next:    state.next,
+	})
+}
+
+func (state paletteFitWarningStartState) HandleFiles(names []string) {
+}
@@ -0,0 +1,36 @@
+package external
+
+import (
+	"fmt"
+
+	"github.com/inkyblackness/imgui-go"
+
+	"github.com/inkyblackness/hacked/ss1/content/bitmap"
+	"github.com/inkyblackness/hacked/ui/gui"
+)
+
+type paletteFitWarningWaitingState struct {
+	machine gui.ModalStateMachine
+	fit     bitmap.PaletteFit
+	next    gui.ModalState
+}
+
+func (state *paletteFitWarningWaitingState) Render() {
+	if imgui.BeginPopupModalV("Palette Mismatch", nil,
+		imgui.WindowFlagsNoResize|imgui.WindowFlagsNoMove|imgui.WindowFlagsNoSavedSettings|imgui.WindowFlagsAlwaysAutoResize) {
+		imgui.Text("The image was imported, yet its colors do not fit the game palette well.")
+		imgui.Text(fmt.Sprintf("Average color error: %.3f (threshold: %.3f)", state.fit.AverageError, bitmap.PaletteFitThreshold))
+		imgui.Text("Consider re-indexing the image to the game palette in an image editor,\nand importing it again.")
+		imgui.Separator()
+		if imgui.Button("OK") {
+			state.machine.SetState(state.next)
+			imgui.CloseCurrentPopup()
+		}
+		imgui.EndPopup()
+	} else {
+		state.machine.SetState(nil)
+	}
+}
+
+func (state *paletteFitWarningWaitingState) HandleFiles(names []string) {
+}
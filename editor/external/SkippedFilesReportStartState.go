@@ -0,0 +1,25 @@
+package external
+
+import (
+	"github.com/inkyblackness/imgui-go"
+
+	"github.com/inkyblackness/hacked/ui/gui"
+)
+
+type skippedFilesReportStartState struct {
+	machine gui.ModalStateMachine
+	skipped []string
+	next    gui.ModalState
+}
+
+func (state skippedFilesReportStartState) Render() {
+	imgui.OpenPopup("Files Skipped")
+	state.machine.SetState(&skippedFilesReportWaitingState{
+		machine: state.machine,
+		skipped: state.skipped,
+		next:    state.next,
+	})
+}
+
+func (state skippedFilesReportStartState) HandleFiles(names []string) {
+}
@@ -0,0 +1,36 @@
+package external
+
+import (
+	"fmt"
+
+	"github.com/inkyblackness/imgui-go"
+
+	"github.com/inkyblackness/hacked/ui/gui"
+)
+
+type skippedFilesReportWaitingState struct {
+	machine gui.ModalStateMachine
+	skipped []string
+	next    gui.ModalState
+}
+
+func (state *skippedFilesReportWaitingState) Render() {
+	if imgui.BeginPopupModalV("Files Skipped", nil,
+		imgui.WindowFlagsNoResize|imgui.WindowFlagsNoMove|imgui.WindowFlagsNoSavedSettings|imgui.WindowFlagsAlwaysAutoResize) {
+		imgui.Text(fmt.Sprintf("The folder was imported, yet %d file(s) could not be loaded:", len(state.skipped)))
+		for _, line := range state.skipped {
+			imgui.Text("- " + line)
+		}
+		imgui.Separator()
+		if imgui.Button("OK") {
+			state.machine.SetState(state.next)
+			imgui.CloseCurrentPopup()
+		}
+		imgui.EndPopup()
+	} else {
+		state.machine.SetState(nil)
+	}
+}
+
+func (state *skippedFilesReportWaitingState) HandleFiles(names []string) {
+}
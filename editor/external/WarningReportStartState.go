@@ -0,0 +1,25 @@
+package external
+
+import (
+	"github.com/inkyblackness/imgui-go"
+
+	"github.com/inkyblackness/hacked/ui/gui"
+)
+
+type warningReportStartState struct {
+	machine  gui.ModalStateMachine
+	info     string
+	warnings []string
+}
+
+func (state warningReportStartState) Render() {
+	imgui.OpenPopup("Warnings")
+	state.machine.SetState(&warningReportWaitingState{
+		machine:  state.machine,
+		info:     state.info,
+		warnings: state.warnings,
+	})
+}
+
+func (state warningReportStartState) HandleFiles(names []string) {
+}
@@ -0,0 +1,43 @@
+package external
+
+import (
+	"fmt"
+
+	"github.com/inkyblackness/imgui-go"
+
+	"github.com/inkyblackness/hacked/ui/gui"
+)
+
+// warningReportLimit is the number of warnings that are listed at most.
+const warningReportLimit = 20
+
+type warningReportWaitingState struct {
+	machine  gui.ModalStateMachine
+	info     string
+	warnings []string
+}
+
+func (state *warningReportWaitingState) Render() {
+	if imgui.BeginPopupModalV("Warnings", nil,
+		imgui.WindowFlagsNoResize|imgui.WindowFlagsNoMove|imgui.WindowFlagsNoSavedSettings|imgui.WindowFlagsAlwaysAutoResize) {
+		imgui.Text(state.info)
+		for index, warning := range state.warnings {
+			if index >= warningReportLimit {
+				imgui.Text(fmt.Sprintf("... and %d more", len(state.warnings)-warningReportLimit))
+				break
+			}
+			imgui.Text("- " + warning)
+		}
+		imgui.Separator()
+		if imgui.Button("OK") {
+			state.machine.SetState(nil)
+			imgui.CloseCurrentPopup()
+		}
+		imgui.EndPopup()
+	} else {
+		state.machine.SetState(nil)
+	}
+}
+
+func (state *warningReportWaitingState) HandleFiles(names []string) {
+}
@@ -4,8 +4,9 @@ import "github.com/inkyblackness/hacked/ui/opengl"
 
 // BitmapTexture wraps an OpenGL handle for a downloaded image.
 type BitmapTexture struct {
-	gl     opengl.OpenGL
-	handle uint32
+	gl      opengl.OpenGL
+	handle  uint32
+	pending bool
 
 	width, height float32
 	u, v          float32
@@ -25,31 +26,51 @@ func powerOfTwo(value int) int {
 
 // NewBitmapTexture downloads the provided raw data to OpenGL and returns a BitmapTexture instance.
 func NewBitmapTexture(gl opengl.OpenGL, width, height int, pixelData []byte) *BitmapTexture {
-	textureWidth := powerOfTwo(width)
-	textureHeight := powerOfTwo(height)
+	tex := newPendingBitmapTexture(gl, width, height, pixelData)
+	tex.upload()
+	return tex
+}
+
+// newPendingBitmapTexture returns a BitmapTexture instance that has not been downloaded to OpenGL yet.
+// Until upload() is called, the texture has no handle.
+func newPendingBitmapTexture(gl opengl.OpenGL, width, height int, pixelData []byte) *BitmapTexture {
 	tex := &BitmapTexture{
-		gl:     gl,
-		handle: gl.GenTextures(1)[0],
+		gl:      gl,
+		pending: true,
 
 		width:  float32(width),
 		height: float32(height),
 
 		pixelData: pixelData,
 	}
-	tex.u = tex.width / float32(textureWidth)
-	tex.v = tex.height / float32(textureHeight)
+	tex.u = tex.width / float32(powerOfTwo(width))
+	tex.v = tex.height / float32(powerOfTwo(height))
+	return tex
+}
 
+func (tex *BitmapTexture) upload() {
+	if !tex.pending {
+		return
+	}
+	tex.pending = false
+
+	width := int(tex.width)
+	height := int(tex.height)
+	textureWidth := powerOfTwo(width)
+	textureHeight := powerOfTwo(height)
 	paddedData := make([]byte, textureWidth*textureHeight)
 	for y := 0; y < height; y++ {
 		inStart := y * width
 		outOffset := y * textureWidth
 		for x := 0; x < width; x++ {
-			value := pixelData[inStart+x]
+			value := tex.pixelData[inStart+x]
 			paddedData[outOffset] = value
 			outOffset++
 		}
 	}
 
+	gl := tex.gl
+	tex.handle = gl.GenTextures(1)[0]
 	gl.BindTexture(opengl.TEXTURE_2D, tex.handle)
 	gl.TexImage2D(opengl.TEXTURE_2D, 0, opengl.RED, int32(textureWidth), int32(textureHeight),
 		0, opengl.RED, opengl.UNSIGNED_BYTE, paddedData)
@@ -57,18 +78,24 @@ func NewBitmapTexture(gl opengl.OpenGL, width, height int, pixelData []byte) *Bi
 	gl.TexParameteri(opengl.TEXTURE_2D, opengl.TEXTURE_MIN_FILTER, opengl.NEAREST)
 	gl.GenerateMipmap(opengl.TEXTURE_2D)
 	gl.BindTexture(opengl.TEXTURE_2D, 0)
-
-	return tex
 }
 
 // Dispose releases the OpenGL texture.
+// A texture that is still pending will not be downloaded anymore.
 func (tex *BitmapTexture) Dispose() {
+	tex.pending = false
 	if tex.handle != 0 {
 		tex.gl.DeleteTextures([]uint32{tex.handle})
 		tex.handle = 0
 	}
 }
 
+// Pending returns true if the texture is still waiting to be downloaded to OpenGL.
+// While pending, the handle is zero and the texture renders empty.
+func (tex *BitmapTexture) Pending() bool {
+	return tex.pending
+}
+
 // Handle returns the texture handle.
 func (tex *BitmapTexture) Handle() uint32 {
 	return tex.handle
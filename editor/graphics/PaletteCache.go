@@ -1,21 +1,35 @@
 package graphics
 
 import (
+	"bytes"
 	"encoding/binary"
-	"errors"
+	"io/ioutil"
 
+	"github.com/inkyblackness/hacked/ss1/content/archive/level/lvlids"
 	"github.com/inkyblackness/hacked/ss1/content/bitmap"
+	"github.com/inkyblackness/hacked/ss1/content/movie"
 	"github.com/inkyblackness/hacked/ss1/resource"
 	"github.com/inkyblackness/hacked/ss1/world/ids"
 	"github.com/inkyblackness/hacked/ui/opengl"
 )
 
+// MainPaletteKey identifies the main game palette, which is in effect for most contexts.
+var MainPaletteKey = resource.KeyOf(ids.GamePalettesStart, resource.LangAny, 0)
+
+// LevelPaletteKey identifies the palette in effect for the level with given ID.
+// The key refers to the information resource of the level. As levels do not provide a palette
+// of their own, PaletteFor() resolves the key to the main palette.
+func LevelPaletteKey(levelID int) resource.Key {
+	return resource.KeyOf(ids.LevelResourcesStart.Plus(levelID*lvlids.PerLevel+lvlids.Information), resource.LangAny, 0)
+}
+
 // PaletteCache loads palettes and provides OpenGL textures.
 type PaletteCache struct {
 	gl        opengl.OpenGL
 	localizer resource.Localizer
 
-	palettes map[resource.Key]*PaletteTexture
+	palettes  map[resource.Key]*PaletteTexture
+	fallbacks map[resource.Key]struct{}
 }
 
 // NewPaletteCache returns a new instance.
@@ -24,6 +38,7 @@ func NewPaletteCache(gl opengl.OpenGL, localizer resource.Localizer) *PaletteCac
 		gl:        gl,
 		localizer: localizer,
 		palettes:  make(map[resource.Key]*PaletteTexture),
+		fallbacks: make(map[resource.Key]struct{}),
 	}
 	return cache
 }
@@ -37,36 +52,86 @@ func (cache *PaletteCache) InvalidateResources(ids []resource.ID) {
 				delete(cache.palettes, key)
 			}
 		}
+		for key := range cache.fallbacks {
+			if key.ID == id {
+				delete(cache.fallbacks, key)
+			}
+		}
+	}
+}
+
+// InvalidatePalette removes the palette with given index, causing it to be loaded again on the next request.
+func (cache *PaletteCache) InvalidatePalette(index int) {
+	key := resource.KeyOf(ids.GamePalettesStart.Plus(index), resource.LangAny, 0)
+	if texture, existing := cache.palettes[key]; existing {
+		texture.Dispose()
+		delete(cache.palettes, key)
 	}
 }
 
 // Palette returns the palette with given index - if available.
 func (cache *PaletteCache) Palette(index int) (*PaletteTexture, error) {
-	key := resource.KeyOf(ids.GamePalettesStart.Plus(index), resource.LangAny, 0)
+	return cache.cached(resource.KeyOf(ids.GamePalettesStart.Plus(index), resource.LangAny, 0))
+}
+
+// PaletteFor returns the palette that is in effect for the resource identified by given key.
+// The key can refer to a palette resource, or to a movie, which has its own palette.
+// Should the resource not provide a palette, the main palette is returned.
+// This fallback is kept until the resource is invalidated.
+func (cache *PaletteCache) PaletteFor(key resource.Key) (*PaletteTexture, error) {
+	if _, isFallback := cache.fallbacks[key]; !isFallback {
+		pal, err := cache.cached(key)
+		if err == nil {
+			return pal, nil
+		}
+		cache.fallbacks[key] = struct{}{}
+	}
+	return cache.cached(MainPaletteKey)
+}
+
+func (cache *PaletteCache) cached(key resource.Key) (*PaletteTexture, error) {
 	pal, existing := cache.palettes[key]
 	if existing {
 		return pal, nil
 	}
-	selector := cache.localizer.LocalizedResources(key.Lang)
-	view, err := selector.Select(key.ID)
+	palette, err := cache.load(key)
 	if err != nil {
 		return nil, err
 	}
-	if view.ContentType() != resource.Palette {
-		return nil, errors.New("resource not a palette")
-	}
-	reader, err := view.Block(key.Index)
-	if err != nil {
-		return nil, err
-	}
-	var palette bitmap.Palette
-	err = binary.Read(reader, binary.LittleEndian, &palette)
-	if err != nil {
-		return nil, err
-	}
-
 	pal = NewPaletteTexture(cache.gl, &palette)
 	cache.palettes[key] = pal
-
 	return pal, nil
 }
+
+func (cache *PaletteCache) load(key resource.Key) (palette bitmap.Palette, err error) {
+	selector := cache.localizer.LocalizedResources(key.Lang)
+	view, err := selector.Select(key.ID)
+	if err != nil {
+		return
+	}
+	switch view.ContentType() {
+	case resource.Palette:
+		reader, blockErr := view.Block(key.Index)
+		if blockErr != nil {
+			return palette, blockErr
+		}
+		err = binary.Read(reader, binary.LittleEndian, &palette)
+	case resource.Movie:
+		reader, blockErr := view.Block(key.Index)
+		if blockErr != nil {
+			return palette, blockErr
+		}
+		data, readErr := ioutil.ReadAll(reader)
+		if readErr != nil {
+			return palette, readErr
+		}
+		container, movieErr := movie.Read(bytes.NewReader(data))
+		if movieErr != nil {
+			return palette, movieErr
+		}
+		palette = container.StartPalette()
+	default:
+		err = resource.ErrContentTypeOf(key.ID, view.ContentType(), resource.Palette)
+	}
+	return
+}
@@ -0,0 +1,165 @@
+package graphics_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/inkyblackness/hacked/editor/graphics"
+	"github.com/inkyblackness/hacked/ss1/content/bitmap"
+	"github.com/inkyblackness/hacked/ss1/content/movie"
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/world"
+	"github.com/inkyblackness/hacked/ss1/world/ids"
+	"github.com/inkyblackness/hacked/ui/opengl"
+)
+
+type PaletteCacheSuite struct {
+	suite.Suite
+
+	gl    *textureRecorder
+	mod   *world.Mod
+	cache *graphics.PaletteCache
+}
+
+func TestPaletteCacheSuite(t *testing.T) {
+	suite.Run(t, new(PaletteCacheSuite))
+}
+
+func (suite *PaletteCacheSuite) SetupTest() {
+	suite.gl = &textureRecorder{}
+	suite.mod = world.NewMod(func([]resource.ID, []resource.ID) {}, func() {})
+	suite.cache = graphics.NewPaletteCache(suite.gl, suite.mod)
+}
+
+func (suite *PaletteCacheSuite) TestPaletteForMainPaletteKey() {
+	suite.givenResource(ids.GamePalettesStart, resource.Palette, paletteData(0x10))
+
+	suite.thenPaletteForShouldStartWith(graphics.MainPaletteKey, 0x10)
+}
+
+func (suite *PaletteCacheSuite) TestPaletteForMovieReturnsItsStartPalette() {
+	suite.givenResource(ids.GamePalettesStart, resource.Palette, paletteData(0x10))
+	suite.givenResource(ids.MailsAudioStart, resource.Movie, movieData(suite.T(), 0x20))
+
+	suite.thenPaletteForShouldStartWith(resource.KeyOf(ids.MailsAudioStart, resource.LangDefault, 0), 0x20)
+}
+
+func (suite *PaletteCacheSuite) TestPaletteForLevelFallsBackToMainPalette() {
+	suite.givenResource(ids.GamePalettesStart, resource.Palette, paletteData(0x10))
+
+	suite.thenPaletteForShouldStartWith(graphics.LevelPaletteKey(1), 0x10)
+}
+
+func (suite *PaletteCacheSuite) TestPaletteForMovieIsReloadedAfterInvalidation() {
+	movieKey := resource.KeyOf(ids.MailsAudioStart, resource.LangDefault, 0)
+	suite.givenResource(ids.GamePalettesStart, resource.Palette, paletteData(0x10))
+	suite.givenResource(ids.MailsAudioStart, resource.Movie, movieData(suite.T(), 0x20))
+	suite.thenPaletteForShouldStartWith(movieKey, 0x20)
+
+	suite.whenMovieIsReplaced(movieData(suite.T(), 0x30))
+
+	suite.thenPaletteForShouldStartWith(movieKey, 0x30)
+	assert.Equal(suite.T(), 1, suite.gl.deleted, "previous texture should be disposed")
+}
+
+func (suite *PaletteCacheSuite) TestPaletteForFallbackIsResolvedAgainAfterInvalidation() {
+	movieKey := resource.KeyOf(ids.MailsAudioStart, resource.LangDefault, 0)
+	suite.givenResource(ids.GamePalettesStart, resource.Palette, paletteData(0x10))
+	suite.thenPaletteForShouldStartWith(movieKey, 0x10)
+
+	suite.whenMovieIsReplaced(movieData(suite.T(), 0x30))
+
+	suite.thenPaletteForShouldStartWith(movieKey, 0x30)
+}
+
+func (suite *PaletteCacheSuite) TestPaletteForKeepsFallbackUntilInvalidation() {
+	movieKey := resource.KeyOf(ids.MailsAudioStart, resource.LangDefault, 0)
+	suite.givenResource(ids.GamePalettesStart, resource.Palette, paletteData(0x10))
+	suite.thenPaletteForShouldStartWith(movieKey, 0x10)
+	suite.thenPaletteForShouldStartWith(movieKey, 0x10)
+
+	assert.Equal(suite.T(), 1, suite.gl.generated, "only the main palette should be loaded")
+}
+
+func (suite *PaletteCacheSuite) givenResource(id resource.ID, contentType resource.ContentType, data []byte) {
+	lang := resource.LangAny
+	if contentType == resource.Movie {
+		lang = resource.LangDefault
+	}
+	var store resource.Store
+	_ = store.Put(id, resource.Resource{
+		Properties: resource.Properties{ContentType: contentType},
+		Blocks:     resource.BlocksFrom([][]byte{data}),
+	})
+	manifest := suite.mod.World()
+	err := manifest.InsertEntry(manifest.EntryCount(), &world.ManifestEntry{
+		ID:        id.String(),
+		Resources: []resource.LocalizedResources{{ID: "test.res", Language: lang, Viewer: store}},
+	})
+	require.Nil(suite.T(), err)
+}
+
+func (suite *PaletteCacheSuite) whenMovieIsReplaced(data []byte) {
+	suite.mod.Modify(func(modder world.Modder) {
+		modder.SetResourceBlock(resource.LangDefault, ids.MailsAudioStart, 0, data)
+	})
+	suite.cache.InvalidateResources([]resource.ID{ids.MailsAudioStart})
+}
+
+func (suite *PaletteCacheSuite) thenPaletteForShouldStartWith(key resource.Key, red byte) {
+	pal, err := suite.cache.PaletteFor(key)
+	require.Nil(suite.T(), err, "no error expected")
+	require.NotNil(suite.T(), pal, "palette expected")
+	assert.Equal(suite.T(), red, pal.Palette()[0].Red, "wrong palette for %v", key)
+}
+
+func paletteData(red byte) []byte {
+	data := make([]byte, 256*3)
+	data[0] = red
+	return data
+}
+
+func movieData(t *testing.T, red byte) []byte {
+	var pal bitmap.Palette
+	pal[0].Red = red
+	container := movie.NewContainerBuilder().VideoWidth(16).VideoHeight(8).StartPalette(&pal).Build()
+	buf := bytes.NewBuffer(nil)
+	err := movie.Write(buf, container)
+	require.Nil(t, err, "no error expected writing movie")
+	return buf.Bytes()
+}
+
+// textureRecorder is an OpenGL that only supports the texture functions needed for palettes.
+type textureRecorder struct {
+	opengl.OpenGL
+
+	generated int
+	deleted   int
+}
+
+func (gl *textureRecorder) GenTextures(n int32) []uint32 {
+	handles := make([]uint32, n)
+	for index := range handles {
+		gl.generated++
+		handles[index] = uint32(gl.generated)
+	}
+	return handles
+}
+
+func (gl *textureRecorder) DeleteTextures(textures []uint32) {
+	gl.deleted += len(textures)
+}
+
+func (gl *textureRecorder) BindTexture(target uint32, texture uint32) {}
+
+func (gl *textureRecorder) TexImage2D(target uint32, level int32, internalFormat uint32, width int32, height int32,
+	border int32, format uint32, xtype uint32, pixels interface{}) {
+}
+
+func (gl *textureRecorder) TexParameteri(target uint32, pname uint32, param int32) {}
+
+func (gl *textureRecorder) GenerateMipmap(target uint32) {}
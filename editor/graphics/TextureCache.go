@@ -13,32 +13,126 @@ type TextureCache struct {
 	gl        opengl.OpenGL
 	localizer resource.Localizer
 
-	textures map[resource.Key]*BitmapTexture
+	textures   map[resource.Key]*BitmapTexture
+	references map[resource.Key]resource.Key
+
+	uploadsPerFrame int
+	uploadQueue     []*BitmapTexture
+
+	thumbnails *ThumbnailCache
 }
 
 // NewTextureCache returns a new instance.
 func NewTextureCache(gl opengl.OpenGL, localizer resource.Localizer) *TextureCache {
 	cache := &TextureCache{
-		gl:        gl,
-		localizer: localizer,
-		textures:  make(map[resource.Key]*BitmapTexture),
+		gl:         gl,
+		localizer:  localizer,
+		textures:   make(map[resource.Key]*BitmapTexture),
+		references: make(map[resource.Key]resource.Key),
 	}
+	cache.thumbnails = newThumbnailCache(gl, func(key resource.Key) (*bitmap.Bitmap, error) {
+		return cache.decodeBitmap(key, nil)
+	})
 	return cache
 }
 
+// Thumbnails returns the cache for thumbnails of the bitmaps.
+// Scheduled thumbnails are generated with UploadPending(), and are invalidated together with the textures.
+func (cache *TextureCache) Thumbnails() *ThumbnailCache {
+	return cache.thumbnails
+}
+
+// SetUploadsPerFrame limits the number of textures that are downloaded to OpenGL per call to UploadPending().
+// With a limit of zero (the default), textures are downloaded immediately when they are loaded.
+// With a positive limit, newly loaded textures are queued and are returned as pending textures
+// until UploadPending() has processed them.
+func (cache *TextureCache) SetUploadsPerFrame(limit int) {
+	cache.uploadsPerFrame = limit
+	if limit <= 0 {
+		cache.uploadAll()
+	}
+}
+
+// UploadPending downloads queued textures to OpenGL, in the order they were loaded.
+// At most the number of textures set via SetUploadsPerFrame() are processed. The same limit applies
+// separately to the generation of scheduled thumbnails.
+// This function must be called while the OpenGL context is current, typically once per frame.
+func (cache *TextureCache) UploadPending() {
+	cache.thumbnails.GeneratePending(cache.uploadsPerFrame)
+	if cache.uploadsPerFrame <= 0 {
+		cache.uploadAll()
+		return
+	}
+	uploaded := 0
+	for (uploaded < cache.uploadsPerFrame) && (len(cache.uploadQueue) > 0) {
+		tex := cache.uploadQueue[0]
+		cache.uploadQueue[0] = nil
+		cache.uploadQueue = cache.uploadQueue[1:]
+		if tex.Pending() {
+			tex.upload()
+			uploaded++
+		}
+	}
+}
+
+// UploadsPending returns true if there are textures waiting to be downloaded to OpenGL,
+// or thumbnails waiting to be generated.
+func (cache *TextureCache) UploadsPending() bool {
+	return (len(cache.uploadQueue) > 0) || cache.thumbnails.GenerationsPending()
+}
+
+func (cache *TextureCache) uploadAll() {
+	for _, tex := range cache.uploadQueue {
+		tex.upload()
+	}
+	cache.uploadQueue = nil
+}
+
+func (cache *TextureCache) removeFromUploadQueue(texture *BitmapTexture) {
+	for index, queued := range cache.uploadQueue {
+		if queued == texture {
+			cache.uploadQueue = append(cache.uploadQueue[:index], cache.uploadQueue[index+1:]...)
+			return
+		}
+	}
+}
+
 // InvalidateResources lets the cache remove any textures from resources that are specified in the given slice.
+// Textures that were based on removed textures are removed as well, as are all thumbnails of the resources.
 func (cache *TextureCache) InvalidateResources(ids []resource.ID) {
+	cache.thumbnails.InvalidateResources(ids)
 	for _, id := range ids {
-		for key, texture := range cache.textures {
+		for key := range cache.textures {
 			if key.ID == id {
-				texture.Dispose()
-				delete(cache.textures, key)
+				cache.InvalidateKey(key)
 			}
 		}
 	}
 }
 
+// InvalidateKey removes the texture of the given key, as well as all textures that were based on it.
+// The next request for these textures will load them again.
+//
+// Textures hold palette indices and are combined with a palette only while rendering.
+// A change in a palette therefore does not require textures to be invalidated, only the palette itself.
+func (cache *TextureCache) InvalidateKey(key resource.Key) {
+	texture, existing := cache.textures[key]
+	if !existing {
+		return
+	}
+	texture.Dispose()
+	cache.removeFromUploadQueue(texture)
+	delete(cache.textures, key)
+	delete(cache.references, key)
+	for dependent, reference := range cache.references {
+		if reference == key {
+			cache.InvalidateKey(dependent)
+		}
+	}
+}
+
 // Texture returns the texture with given key - if available.
+// If uploads are limited per frame, the returned texture may still be pending.
 func (cache *TextureCache) Texture(key resource.Key) (*BitmapTexture, error) {
 	return cache.TextureReferenced(key, nil)
 }
@@ -51,19 +145,39 @@ func (cache *TextureCache) TextureReferenced(key resource.Key, reference *resour
 	if existing {
 		return tex, nil
 	}
+	bmp, err := cache.decodeBitmap(key, reference)
+	if err != nil {
+		return nil, err
+	}
+
+	tex = newPendingBitmapTexture(cache.gl, int(bmp.Header.Width), int(bmp.Header.Height), bmp.Pixels)
+	if cache.uploadsPerFrame > 0 {
+		cache.uploadQueue = append(cache.uploadQueue, tex)
+	} else {
+		tex.upload()
+	}
+	cache.textures[key] = tex
+	if reference != nil {
+		cache.references[key] = *reference
+	}
+
+	return tex, nil
+}
+
+func (cache *TextureCache) decodeBitmap(key resource.Key, reference *resource.Key) (*bitmap.Bitmap, error) {
 	selector := cache.localizer.LocalizedResources(key.Lang)
 	view, err := selector.Select(key.ID)
 	if err != nil {
 		return nil, err
 	}
 	if view.ContentType() != resource.Bitmap {
-		return nil, errors.New("resource not a bitmap")
+		return nil, resource.ErrContentTypeOf(key.ID, view.ContentType(), resource.Bitmap)
 	}
 	reader, err := view.Block(key.Index)
 	if err != nil {
 		return nil, err
 	}
-	bmp, err := bitmap.DecodeReferenced(reader, func(width, height int16) ([]byte, error) {
+	return bitmap.DecodeReferenced(reader, func(width, height int16) ([]byte, error) {
 		buf := make([]byte, int(width)*int(height))
 		if reference == nil {
 			return buf, nil
@@ -79,12 +193,4 @@ func (cache *TextureCache) TextureReferenced(key resource.Key, reference *resour
 		copy(buf, refTex.PixelData())
 		return buf, nil
 	})
-	if err != nil {
-		return nil, err
-	}
-
-	tex = NewBitmapTexture(cache.gl, int(bmp.Header.Width), int(bmp.Header.Height), bmp.Pixels)
-	cache.textures[key] = tex
-
-	return tex, nil
 }
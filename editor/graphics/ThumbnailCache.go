@@ -0,0 +1,182 @@
+package graphics
+
+import (
+	"github.com/inkyblackness/hacked/ss1/content/bitmap"
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ui/opengl"
+)
+
+// DefaultThumbnailPixelLimit is the default amount of pixels all thumbnails may occupy together.
+const DefaultThumbnailPixelLimit = 256 * 64 * 64
+
+// placeholderPixels is the amount of pixels accounted for a thumbnail without texture,
+// which is either pending or failed. This keeps their count bound by the pixel limit as well.
+const placeholderPixels = 16 * 16
+
+type thumbnailKey struct {
+	key  resource.Key
+	size int
+}
+
+type thumbnail struct {
+	texture   *BitmapTexture
+	err       error
+	queued    bool
+	pixels    int
+	lastShown uint64
+}
+
+// ThumbnailCache keeps downscaled versions of bitmaps, for browsing many of them at once.
+// Thumbnails are generated in the background of rendering: Requesting a thumbnail that is not yet
+// available schedules its generation, which happens during GeneratePending().
+//
+// The cache is bound to a limit of pixels. Should the limit be exceeded, the thumbnails
+// that were shown least recently are removed. Pending and failed thumbnails are accounted for as well.
+type ThumbnailCache struct {
+	gl     opengl.OpenGL
+	decode func(key resource.Key) (*bitmap.Bitmap, error)
+
+	pixelLimit int
+	pixelCount int
+	shownStamp uint64
+
+	thumbnails map[thumbnailKey]*thumbnail
+	queue      []thumbnailKey
+}
+
+func newThumbnailCache(gl opengl.OpenGL, decode func(key resource.Key) (*bitmap.Bitmap, error)) *ThumbnailCache {
+	return &ThumbnailCache{
+		gl:         gl,
+		decode:     decode,
+		pixelLimit: DefaultThumbnailPixelLimit,
+		thumbnails: make(map[thumbnailKey]*thumbnail),
+	}
+}
+
+// SetPixelLimit sets the amount of pixels all thumbnails may occupy together.
+// Thumbnails beyond the limit are removed immediately.
+func (cache *ThumbnailCache) SetPixelLimit(limit int) {
+	cache.pixelLimit = limit
+	cache.evict()
+}
+
+// GetThumbnail returns the thumbnail of given key, fitting within a square of given size.
+// If the thumbnail is not yet available, its generation is scheduled and nil is returned.
+// An error is returned if the bitmap could not be loaded.
+func (cache *ThumbnailCache) GetThumbnail(key resource.Key, size int) (*BitmapTexture, error) {
+	thumbKey := thumbnailKey{key: key, size: size}
+	cache.shownStamp++
+	entry, existing := cache.thumbnails[thumbKey]
+	if !existing {
+		entry = &thumbnail{queued: true, pixels: placeholderPixels}
+		cache.thumbnails[thumbKey] = entry
+		cache.pixelCount += entry.pixels
+		cache.queue = append(cache.queue, thumbKey)
+	}
+	entry.lastShown = cache.shownStamp
+	return entry.texture, entry.err
+}
+
+// GenerationsPending returns true if there are thumbnails waiting to be generated.
+func (cache *ThumbnailCache) GenerationsPending() bool {
+	return len(cache.queue) > 0
+}
+
+// GeneratePending creates the scheduled thumbnails, up to the given limit.
+// A limit of zero or less generates all scheduled thumbnails.
+// This function must be called while the OpenGL context is current.
+func (cache *ThumbnailCache) GeneratePending(limit int) {
+	generated := 0
+	for (len(cache.queue) > 0) && ((limit <= 0) || (generated < limit)) {
+		thumbKey := cache.queue[0]
+		cache.queue = cache.queue[1:]
+		entry, existing := cache.thumbnails[thumbKey]
+		if !existing || !entry.queued {
+			continue
+		}
+		entry.queued = false
+		cache.generate(thumbKey, entry)
+		generated++
+	}
+	if generated > 0 {
+		cache.evict()
+	}
+}
+
+// InvalidateResources removes all thumbnails of the resources specified in the given slice.
+func (cache *ThumbnailCache) InvalidateResources(ids []resource.ID) {
+	for _, id := range ids {
+		for thumbKey := range cache.thumbnails {
+			if thumbKey.key.ID == id {
+				cache.remove(thumbKey)
+			}
+		}
+	}
+}
+
+func (cache *ThumbnailCache) generate(thumbKey thumbnailKey, entry *thumbnail) {
+	bmp, err := cache.decode(thumbKey.key)
+	if err != nil {
+		entry.err = err
+		return
+	}
+	width, height, pixels := thumbnailPixels(int(bmp.Header.Width), int(bmp.Header.Height), bmp.Pixels, thumbKey.size)
+	entry.texture = NewBitmapTexture(cache.gl, width, height, pixels)
+	cache.pixelCount += len(pixels) - entry.pixels
+	entry.pixels = len(pixels)
+}
+
+func (cache *ThumbnailCache) remove(thumbKey thumbnailKey) {
+	entry := cache.thumbnails[thumbKey]
+	if entry.texture != nil {
+		entry.texture.Dispose()
+	}
+	cache.pixelCount -= entry.pixels
+	delete(cache.thumbnails, thumbKey)
+}
+
+func (cache *ThumbnailCache) evict() {
+	for cache.pixelCount > cache.pixelLimit {
+		var oldestKey thumbnailKey
+		var oldest *thumbnail
+		for thumbKey, entry := range cache.thumbnails {
+			if (oldest == nil) || (entry.lastShown < oldest.lastShown) {
+				oldestKey = thumbKey
+				oldest = entry
+			}
+		}
+		if oldest == nil {
+			return
+		}
+		cache.remove(oldestKey)
+	}
+}
+
+// thumbnailPixels scales the given paletted pixels to fit within a square of given size, keeping the aspect ratio.
+// As the pixels are palette indices, the nearest pixel is taken. Bitmaps that already fit are returned unchanged.
+func thumbnailPixels(width, height int, pixels []byte, size int) (int, int, []byte) {
+	if (size <= 0) || ((width <= size) && (height <= size)) {
+		return width, height, pixels
+	}
+	newWidth, newHeight := size, size
+	if width > height {
+		newHeight = maxInt(1, height*size/width)
+	} else {
+		newWidth = maxInt(1, width*size/height)
+	}
+	result := make([]byte, newWidth*newHeight)
+	for y := 0; y < newHeight; y++ {
+		srcStart := (y * height / newHeight) * width
+		for x := 0; x < newWidth; x++ {
+			result[y*newWidth+x] = pixels[srcStart+x*width/newWidth]
+		}
+	}
+	return newWidth, newHeight, result
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -1,8 +1,15 @@
 package levels
 
+import "github.com/inkyblackness/hacked/ss1/content/archive/level"
+
 const (
 	fineCoordinatesPerTileSide = 256
 
+	// maxTileAtlasIndex is the highest atlas index a tile can refer to.
+	maxTileAtlasIndex = level.WallTextureLimit - 1
+	// maxListedDanglingTextures limits the number of dangling texture references listed in a tooltip.
+	maxListedDanglingTextures = 20
+
 	hintUnknown  = "???"
 	hintReadOnly = " (read-only)"
 )
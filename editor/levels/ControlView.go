@@ -2,15 +2,19 @@ package levels
 
 import (
 	"fmt"
+	"io"
+	"path/filepath"
 
 	"github.com/inkyblackness/imgui-go"
 
 	"github.com/inkyblackness/hacked/editor/event"
+	"github.com/inkyblackness/hacked/editor/external"
 	"github.com/inkyblackness/hacked/editor/graphics"
 	"github.com/inkyblackness/hacked/editor/render"
 	"github.com/inkyblackness/hacked/ss1/content/archive"
 	"github.com/inkyblackness/hacked/ss1/content/archive/level"
 	"github.com/inkyblackness/hacked/ss1/content/archive/level/lvlids"
+	"github.com/inkyblackness/hacked/ss1/content/archive/level/lvlmesh"
 	"github.com/inkyblackness/hacked/ss1/content/text"
 	"github.com/inkyblackness/hacked/ss1/edit/undoable/cmd"
 	"github.com/inkyblackness/hacked/ss1/resource"
@@ -24,26 +28,41 @@ type ControlView struct {
 	mod *world.Mod
 
 	guiScale      float32
+	focus         render.FocusArea
 	commander     cmd.Commander
 	eventListener event.Listener
 
-	textCache    *text.Cache
-	textureCache *graphics.TextureCache
+	textCache         *text.Cache
+	textureCache      *graphics.TextureCache
+	modalStateMachine gui.ModalStateMachine
 
 	model controlViewModel
+
+	danglingTextures danglingTextureScan
+}
+
+// danglingTextureScan keeps the result of scanning a level for dangling texture references,
+// as the scan covers the whole tile map.
+type danglingTextureScan struct {
+	valid        bool
+	levelID      int
+	textureCount int
+	refs         []level.DanglingTextureReference
 }
 
 // NewControlView returns a new instance.
-func NewControlView(mod *world.Mod, guiScale float32, textCache *text.Cache, textureCache *graphics.TextureCache,
-	commander cmd.Commander, eventListener event.Listener, eventRegistry event.Registry) *ControlView {
+func NewControlView(mod *world.Mod, guiScale float32, focus render.FocusArea, textCache *text.Cache, textureCache *graphics.TextureCache,
+	modalStateMachine gui.ModalStateMachine, commander cmd.Commander, eventListener event.Listener, eventRegistry event.Registry) *ControlView {
 	view := &ControlView{
-		mod:           mod,
-		guiScale:      guiScale,
-		commander:     commander,
-		eventListener: eventListener,
-		textCache:     textCache,
-		textureCache:  textureCache,
-		model:         freshControlViewModel(),
+		mod:               mod,
+		guiScale:          guiScale,
+		focus:             focus,
+		commander:         commander,
+		eventListener:     eventListener,
+		textCache:         textCache,
+		textureCache:      textureCache,
+		modalStateMachine: modalStateMachine,
+		model:             freshControlViewModel(),
 	}
 	eventRegistry.RegisterHandler(view.onLevelSelectionSetEvent)
 	view.setSelectedLevel(view.model.selectedLevel)
@@ -55,6 +74,21 @@ func (view *ControlView) WindowOpen() *bool {
 	return &view.model.windowOpen
 }
 
+// InvalidateResources drops any results derived from the data of given resources.
+func (view *ControlView) InvalidateResources(modifiedIDs []resource.ID) {
+	if !view.danglingTextures.valid {
+		return
+	}
+	levelStart := ids.LevelResourcesStart.Plus(view.danglingTextures.levelID * lvlids.PerLevel)
+	levelEnd := levelStart.Plus(lvlids.PerLevel)
+	for _, id := range modifiedIDs {
+		if (id >= levelStart) && (id < levelEnd) {
+			view.danglingTextures.valid = false
+			return
+		}
+	}
+}
+
 // SelectedLevel returns the currently selected level.
 func (view *ControlView) SelectedLevel() int {
 	return view.model.selectedLevel
@@ -68,14 +102,14 @@ func (view *ControlView) Render(lvl *level.Level) {
 		view.model.windowOpen = true
 	}
 	if view.model.windowOpen {
-		imgui.SetNextWindowSizeV(imgui.Vec2{X: 400 * view.guiScale, Y: 300 * view.guiScale}, imgui.ConditionOnce)
+		imgui.SetNextWindowSizeV(render.LayoutMetricsFor(view.guiScale).CompactWindowSize(), imgui.ConditionOnce)
 		title := "Level Control"
 		readOnly := !view.editingAllowed(lvl.ID())
 		if readOnly {
 			title += hintReadOnly
 		}
 		if imgui.BeginV(title+"###Level Control", view.WindowOpen(), imgui.WindowFlagsNoCollapse) {
-			view.renderContent(lvl, readOnly)
+			view.focus.Render(func() { view.renderContent(lvl, readOnly) })
 		}
 		imgui.End()
 	}
@@ -93,7 +127,7 @@ var levelHeights = []string{
 }
 
 func (view *ControlView) renderContent(lvl *level.Level, readOnly bool) {
-	imgui.PushItemWidth(-200 * view.guiScale)
+	imgui.PushItemWidth(render.LayoutMetricsFor(view.guiScale).WideLabelSpace())
 	selectedLevel := view.model.selectedLevel
 	if gui.StepSliderInt("Active Level", &selectedLevel, 0, archive.MaxLevels-1) {
 		view.eventListener.Event(ObjectSelectionSetEvent{})
@@ -106,9 +140,13 @@ func (view *ControlView) renderContent(lvl *level.Level, readOnly bool) {
 	}
 	imgui.LabelText("Type", levelType)
 	view.renderLevelHeight(lvl, readOnly)
+	if imgui.Button("Export Geometry...") {
+		view.requestExportGeometry(lvl)
+	}
 
 	if !lvl.IsCyberspace() {
 		view.renderTextureAtlas(lvl, readOnly)
+		view.renderTextureReferences(lvl, readOnly)
 		view.renderSurveillanceObjects(lvl, readOnly)
 		view.renderHazards(lvl, readOnly)
 		view.renderTextureAnimations(lvl, readOnly)
@@ -133,6 +171,42 @@ func (view *ControlView) renderLevelHeight(lvl *level.Level, readOnly bool) {
 	}
 }
 
+func (view *ControlView) requestExportGeometry(lvl *level.Level) {
+	filename := fmt.Sprintf("level%02d.obj", lvl.ID())
+	info := "File to be written: " + filename
+	var exportTo func(string)
+
+	exportTo = func(dirname string) {
+		var warnings []lvlmesh.Warning
+		err := world.SaveFile(filepath.Join(dirname, filename), func(writer io.WriteSeeker) error {
+			var writeErr error
+			warnings, writeErr = lvlmesh.WriteOBJ(writer, lvl)
+			if writeErr != nil {
+				return writeErr
+			}
+			for _, warning := range warnings {
+				_, writeErr = fmt.Fprintf(writer, "# %v\n", warning)
+				if writeErr != nil {
+					return writeErr
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			external.Export(view.modalStateMachine, "Could not write file: "+err.Error()+".\n"+info, exportTo, true)
+			return
+		}
+		warningTexts := make([]string, len(warnings))
+		for index, warning := range warnings {
+			warningTexts[index] = warning.String()
+		}
+		external.ReportWarnings(view.modalStateMachine,
+			fmt.Sprintf("The geometry was exported to %v, yet not all tiles could be written:", filename), warningTexts)
+	}
+
+	external.Export(view.modalStateMachine, info, exportTo, false)
+}
+
 func (view *ControlView) textureName(index int) string {
 	key := resource.KeyOf(ids.TextureNames, resource.LangDefault, index)
 	name, err := view.textCache.Text(key)
@@ -186,6 +260,54 @@ func (view *ControlView) renderTextureAtlas(lvl *level.Level, readOnly bool) {
 	}
 }
 
+func (view *ControlView) renderTextureReferences(lvl *level.Level, readOnly bool) {
+	textureCount := len(view.mod.TextureProperties())
+	if textureCount == 0 {
+		textureCount = world.MaxWorldTextures
+	}
+	refs := view.danglingTextureReferences(lvl, textureCount)
+	if len(refs) == 0 {
+		imgui.LabelText("Dangling Textures", "None")
+	} else {
+		imgui.PushStyleColor(imgui.StyleColorText, imgui.Vec4{X: 1.0, Y: 0.4, Z: 0.4, W: 1.0})
+		imgui.LabelText("Dangling Textures", fmt.Sprintf("%d tile surface(s)", len(refs)))
+		imgui.PopStyleColor()
+		if imgui.IsItemHovered() {
+			imgui.BeginTooltip()
+			for index, ref := range refs {
+				if index >= maxListedDanglingTextures {
+					imgui.Text("...")
+					break
+				}
+				imgui.Text(ref.String())
+			}
+			imgui.EndTooltip()
+		}
+	}
+	if readOnly {
+		return
+	}
+	atlasMax := len(lvl.TextureAtlas()) - 1
+	gui.StepSliderInt("Remap From", &view.model.remapFromAtlasIndex, 0, maxTileAtlasIndex)
+	gui.StepSliderInt("Remap To", &view.model.remapToAtlasIndex, 0, atlasMax)
+	if imgui.Button("Remap Tile Textures") {
+		view.requestRemapTileTextures(lvl, view.model.remapFromAtlasIndex, view.model.remapToAtlasIndex)
+	}
+}
+
+func (view *ControlView) danglingTextureReferences(lvl *level.Level, textureCount int) []level.DanglingTextureReference {
+	scan := &view.danglingTextures
+	if !scan.valid || (scan.levelID != lvl.ID()) || (scan.textureCount != textureCount) {
+		*scan = danglingTextureScan{
+			valid:        true,
+			levelID:      lvl.ID(),
+			textureCount: textureCount,
+			refs:         lvl.DanglingTextureReferences(textureCount),
+		}
+	}
+	return scan.refs
+}
+
 func (view *ControlView) renderSurveillanceObjects(lvl *level.Level, readOnly bool) {
 	imgui.Separator()
 
@@ -333,6 +455,13 @@ func (view *ControlView) requestSetLevelTexture(lvl *level.Level, atlasIndex, wo
 	})
 }
 
+func (view *ControlView) requestRemapTileTextures(lvl *level.Level, from, to int) {
+	if lvl.RemapTileTextures(from, to) == 0 {
+		return
+	}
+	view.patchLevelResources(lvl, func() {})
+}
+
 func (view *ControlView) requestSetSurveillanceSource(lvl *level.Level, objectIndex int, objectID level.ObjectID) {
 	lvl.SetSurveillanceSource(objectIndex, objectID)
 	view.patchLevelResources(lvl, func() {
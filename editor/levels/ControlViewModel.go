@@ -7,6 +7,8 @@ type controlViewModel struct {
 	selectedAtlasIndex              int
 	selectedSurveillanceObjectIndex int
 	selectedTextureAnimationIndex   int
+	remapFromAtlasIndex             int
+	remapToAtlasIndex               int
 
 	restoreFocus bool
 	windowOpen   bool
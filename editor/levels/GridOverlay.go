@@ -0,0 +1,206 @@
+package levels
+
+import (
+	"fmt"
+
+	"github.com/inkyblackness/hacked/editor/render"
+	"github.com/inkyblackness/hacked/ui/opengl"
+)
+
+// GridOverlay describes the optional grid that is drawn over the tiles of the map.
+type GridOverlay struct {
+	// Tiles enables lines at the borders of tiles.
+	Tiles bool
+	// SubTiles enables lines within tiles.
+	SubTiles bool
+	// SubTileDivisions is the number of parts each tile side is divided into for sub-tile lines.
+	SubTileDivisions int
+
+	// Red, Green, and Blue specify the color of the lines, in range 0..255.
+	Red, Green, Blue int
+	// Opacity specifies the opacity of the lines, in percent.
+	Opacity int
+}
+
+// GridOverlaySubTileDivisions returns the supported values for sub-tile divisions.
+// They correspond to fine coordinates, the finest being 0x20.
+func GridOverlaySubTileDivisions() []int {
+	return []int{2, 4, 8}
+}
+
+// DefaultGridOverlay returns the initial settings, which have the grid disabled.
+func DefaultGridOverlay() GridOverlay {
+	return GridOverlay{
+		SubTileDivisions: 4,
+		Red:              255,
+		Green:            255,
+		Blue:             255,
+		Opacity:          40,
+	}
+}
+
+// Enabled returns true if any lines are to be drawn.
+func (overlay GridOverlay) Enabled() bool {
+	return (overlay.Tiles || overlay.SubTiles) && (overlay.Opacity > 0)
+}
+
+// Color returns the color of the lines as normalized RGBA values.
+func (overlay GridOverlay) Color() [4]float32 {
+	return [4]float32{
+		float32(overlay.Red) / 255.0,
+		float32(overlay.Green) / 255.0,
+		float32(overlay.Blue) / 255.0,
+		float32(overlay.Opacity) / 100.0,
+	}
+}
+
+var gridOverlayVertexShaderSource = `
+#version 150
+precision mediump float;
+
+in vec3 vertexPosition;
+
+uniform mat4 viewMatrix;
+uniform mat4 projectionMatrix;
+
+out vec2 originalPosition;
+
+void main(void) {
+   gl_Position = projectionMatrix * viewMatrix * vec4(vertexPosition, 1.0);
+   originalPosition = vertexPosition.xy;
+}
+`
+
+var gridOverlayFragmentShaderSource = `
+#version 150
+precision mediump float;
+
+uniform vec4 color;
+// x: step size of tile lines (0 for none), y: step size of sub-tile lines (0 for none),
+// z: width of tile lines in pixels, w: width of sub-tile lines in pixels.
+uniform vec4 lines;
+
+in vec2 originalPosition;
+out vec4 fragColor;
+
+float lineAlpha(float stepSize, float width) {
+   if (stepSize <= 0.0) {
+      return 0.0;
+   }
+   vec2 scaled = originalPosition / stepSize;
+   vec2 perPixel = fwidth(scaled);
+   vec2 pixelDistance = abs(fract(scaled - 0.5) - 0.5) / perPixel;
+   float distance = min(pixelDistance.x, pixelDistance.y);
+   float alpha = 1.0 - smoothstep(width * 0.5 - 0.5, width * 0.5 + 0.5, distance);
+
+   // Fade out lines that are too close together to be distinguished.
+   float spacing = 1.0 / max(perPixel.x, perPixel.y);
+   return alpha * clamp((spacing - width * 4.0) / (width * 4.0), 0.0, 1.0);
+}
+
+void main(void) {
+   float alpha = max(lineAlpha(lines.x, lines.z), lineAlpha(lines.y, lines.w) * 0.6);
+   fragColor = vec4(color.rgb, color.a * alpha);
+}
+`
+
+// MapGridOverlay renders a configurable grid over the tiles of the map.
+// Lines keep their width on screen, regardless of zoom, and are scaled by the GUI scale.
+type MapGridOverlay struct {
+	context   *render.Context
+	lineWidth float32
+
+	program                 uint32
+	vao                     *opengl.VertexArrayObject
+	vertexPositionBuffer    uint32
+	vertexPositionAttrib    int32
+	colorUniform            opengl.Vector4Uniform
+	linesUniform            opengl.Vector4Uniform
+	viewMatrixUniform       opengl.Matrix4Uniform
+	projectionMatrixUniform opengl.Matrix4Uniform
+}
+
+// NewMapGridOverlay returns a new instance.
+func NewMapGridOverlay(context *render.Context, guiScale float32) *MapGridOverlay {
+	gl := context.OpenGL
+	program, programErr := opengl.LinkNewStandardProgram(gl, gridOverlayVertexShaderSource, gridOverlayFragmentShaderSource)
+
+	if programErr != nil {
+		panic(fmt.Errorf("MapGridOverlay shader failed: %v", programErr))
+	}
+	overlay := &MapGridOverlay{
+		context:                 context,
+		lineWidth:               guiScale,
+		program:                 program,
+		vao:                     opengl.NewVertexArrayObject(gl, program),
+		vertexPositionBuffer:    gl.GenBuffers(1)[0],
+		vertexPositionAttrib:    gl.GetAttribLocation(program, "vertexPosition"),
+		colorUniform:            opengl.Vector4Uniform(gl.GetUniformLocation(program, "color")),
+		linesUniform:            opengl.Vector4Uniform(gl.GetUniformLocation(program, "lines")),
+		viewMatrixUniform:       opengl.Matrix4Uniform(gl.GetUniformLocation(program, "viewMatrix")),
+		projectionMatrixUniform: opengl.Matrix4Uniform(gl.GetUniformLocation(program, "projectionMatrix")),
+	}
+	if overlay.lineWidth < 1.0 {
+		overlay.lineWidth = 1.0
+	}
+
+	{
+		tilesPerMapSide := float32(64.0)
+
+		gl.BindBuffer(opengl.ARRAY_BUFFER, overlay.vertexPositionBuffer)
+		limit := fineCoordinatesPerTileSide * tilesPerMapSide
+		var vertices = []float32{
+			0.0, 0.0, 0.0,
+			limit, 0.0, 0.0,
+			limit, limit, 0.0,
+
+			limit, limit, 0.0,
+			0.0, limit, 0.0,
+			0.0, 0.0, 0.0}
+		gl.BufferData(opengl.ARRAY_BUFFER, len(vertices)*4, vertices, opengl.STATIC_DRAW)
+		gl.BindBuffer(opengl.ARRAY_BUFFER, 0)
+	}
+	overlay.vao.WithSetter(func(gl opengl.OpenGL) {
+		gl.EnableVertexAttribArray(uint32(overlay.vertexPositionAttrib))
+		gl.BindBuffer(opengl.ARRAY_BUFFER, overlay.vertexPositionBuffer)
+		gl.VertexAttribOffset(uint32(overlay.vertexPositionAttrib), 3, opengl.FLOAT, false, 0, 0)
+		gl.BindBuffer(opengl.ARRAY_BUFFER, 0)
+	})
+
+	return overlay
+}
+
+// Dispose releases any internal resources.
+func (overlay *MapGridOverlay) Dispose() {
+	gl := overlay.context.OpenGL
+	gl.DeleteProgram(overlay.program)
+	gl.DeleteBuffers([]uint32{overlay.vertexPositionBuffer})
+	overlay.vao.Dispose()
+}
+
+// Render renders the grid according to the given settings. Nothing is drawn if the grid is disabled.
+func (overlay *MapGridOverlay) Render(settings GridOverlay) {
+	if !settings.Enabled() {
+		return
+	}
+	gl := overlay.context.OpenGL
+	var lines [4]float32
+	if settings.Tiles {
+		lines[0] = fineCoordinatesPerTileSide
+	}
+	if settings.SubTiles && (settings.SubTileDivisions > 1) {
+		lines[1] = fineCoordinatesPerTileSide / float32(settings.SubTileDivisions)
+	}
+	lines[2] = overlay.lineWidth
+	lines[3] = overlay.lineWidth
+	color := settings.Color()
+
+	overlay.vao.OnShader(func() {
+		overlay.viewMatrixUniform.Set(gl, overlay.context.ViewMatrix)
+		overlay.projectionMatrixUniform.Set(gl, &overlay.context.ProjectionMatrix)
+		overlay.colorUniform.Set(gl, &color)
+		overlay.linesUniform.Set(gl, &lines)
+
+		gl.DrawArrays(opengl.TRIANGLES, 0, 6)
+	})
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/inkyblackness/hacked/ss1/content/object"
 	"github.com/inkyblackness/hacked/ss1/resource"
 	"github.com/inkyblackness/hacked/ss1/world/ids"
+	"github.com/inkyblackness/hacked/ss1/world/integrity"
 	"github.com/inkyblackness/hacked/ui/input"
 	"github.com/inkyblackness/hacked/ui/opengl"
 )
@@ -60,6 +61,7 @@ type MapDisplay struct {
 	textures    *MapTextures
 	colors      *MapColors
 	mapGrid     *MapGrid
+	gridOverlay *MapGridOverlay
 	highlighter *Highlighter
 	icons       *MapIcons
 
@@ -70,7 +72,7 @@ type MapDisplay struct {
 	positionValid    bool
 	position         MapPosition
 
-	selectedTiles   tileCoordinates
+	selectedTiles   TileCoordinates
 	selectedObjects objectIDs
 
 	activeLevel         *level.Level
@@ -107,6 +109,7 @@ func NewMapDisplay(gl opengl.OpenGL, guiScale float32,
 	display.textures = NewMapTextures(&display.context, textureQuery)
 	display.colors = NewMapColors(&display.context)
 	display.mapGrid = NewMapGrid(&display.context)
+	display.gridOverlay = NewMapGridOverlay(&display.context, guiScale)
 	display.highlighter = NewHighlighter(&display.context)
 	display.icons = NewMapIcons(&display.context)
 
@@ -124,7 +127,7 @@ func NewMapDisplay(gl opengl.OpenGL, guiScale float32,
 // Render renders the whole map display.
 func (display *MapDisplay) Render(properties object.PropertiesTable, lvl *level.Level,
 	paletteTexture *graphics.PaletteTexture, textureRetriever func(resource.Key) (*graphics.BitmapTexture, error),
-	textureDisplay TextureDisplay, colorDisplay ColorDisplay) {
+	textureDisplay TextureDisplay, colorDisplay ColorDisplay, colorScheme TileColorScheme, gridOverlay GridOverlay) {
 	columns, rows, _ := lvl.Size()
 
 	display.selectedObjects.filterInvalid(lvl)
@@ -133,29 +136,27 @@ func (display *MapDisplay) Render(properties object.PropertiesTable, lvl *level.
 	display.background.Render()
 	if lvl.IsCyberspace() {
 		if paletteTexture != nil {
-			var colorQuery ColorQuery
 			palette := paletteTexture.Palette()
-			if colorDisplay == ColorDisplayFloor {
-				colorQuery = display.colorQueryFor(lvl, func(tile *level.TileMapEntry) [4]float32 {
-					rgb := palette[tile.TextureInfo.FloorPaletteIndex()]
-					return [4]float32{float32(rgb.Red) / 255, float32(rgb.Green) / 255, float32(rgb.Blue) / 255, 0.8}
-				})
-			} else if colorDisplay == ColorDisplayCeiling {
-				colorQuery = display.colorQueryFor(lvl, func(tile *level.TileMapEntry) [4]float32 {
-					rgb := palette[tile.TextureInfo.CeilingPaletteIndex()]
-					return [4]float32{float32(rgb.Red) / 255, float32(rgb.Green) / 255, float32(rgb.Blue) / 255, 0.8}
-				})
-			}
-			if colorQuery != nil {
-				display.colors.Render(columns, rows, colorQuery)
+			if colorFunc := colorScheme.Cyberspace[colorDisplay]; colorFunc != nil {
+				paletteIndex := func(tile *level.TileMapEntry) byte {
+					return tile.TextureInfo.FloorPaletteIndex()
+				}
+				if colorDisplay == ColorDisplayCeiling {
+					paletteIndex = func(tile *level.TileMapEntry) byte {
+						return tile.TextureInfo.CeilingPaletteIndex()
+					}
+				}
+				display.colors.Render(columns, rows, display.colorQueryFor(lvl, func(tile *level.TileMapEntry) [4]float32 {
+					return colorFunc(paletteIndex(tile), &palette)
+				}))
 			}
 		}
 	} else {
 		if paletteTexture != nil {
-			display.textures.Render(columns, rows, func(x, y int) (level.TileType, int, int) {
+			display.textures.Render(columns, rows, func(x, y int) (level.TileType, int, int, bool) {
 				tile := lvl.Tile(x, y)
 				if tile == nil {
-					return level.TileTypeSolid, 0, 0
+					return level.TileTypeSolid, 0, 0, false
 				}
 				atlasIndex, textureRotations := textureDisplay.Func()(tile)
 				atlas := lvl.TextureAtlas()
@@ -163,24 +164,26 @@ func (display *MapDisplay) Render(properties object.PropertiesTable, lvl *level.
 				if (atlasIndex >= 0) && (atlasIndex < len(atlas)) {
 					textureIndex = int(atlas[atlasIndex])
 				}
-				return tile.Type, textureIndex, textureRotations
+				flipped := (textureDisplay == TextureDisplayWall) && tile.Flags.ForRealWorld().WallTexturePattern().IsFlippedAt(x, y)
+				return tile.Type, textureIndex, textureRotations, flipped
 			}, paletteTexture)
 		}
 
-		var colorQuery ColorQuery
-		if colorDisplay == ColorDisplayFloor {
-			colorQuery = display.colorQueryFor(lvl, func(tile *level.TileMapEntry) [4]float32 {
-				return [4]float32{0.0, 0.0, 0.0, float32(tile.Flags.ForRealWorld().FloorShadow()) / 15.0}
-			})
-		} else if colorDisplay == ColorDisplayCeiling {
-			colorQuery = display.colorQueryFor(lvl, func(tile *level.TileMapEntry) [4]float32 {
-				return [4]float32{0.0, 0.0, 0.0, float32(tile.Flags.ForRealWorld().CeilingShadow()) / 15.0}
-			})
-		}
-		if colorQuery != nil {
-			display.colors.Render(columns, rows, colorQuery)
+		if colorFunc := colorScheme.Shadow[colorDisplay]; colorFunc != nil {
+			shadow := func(tile *level.TileMapEntry) int {
+				return tile.Flags.ForRealWorld().FloorShadow()
+			}
+			if colorDisplay == ColorDisplayCeiling {
+				shadow = func(tile *level.TileMapEntry) int {
+					return tile.Flags.ForRealWorld().CeilingShadow()
+				}
+			}
+			display.colors.Render(columns, rows, display.colorQueryFor(lvl, func(tile *level.TileMapEntry) [4]float32 {
+				return colorFunc(shadow(tile))
+			}))
 		}
 	}
+	display.gridOverlay.Render(gridOverlay)
 	display.mapGrid.Render(lvl)
 	if display.positionValid {
 		if len(display.availableHoverItems) == 0 {
@@ -198,22 +201,7 @@ func (display *MapDisplay) Render(properties object.PropertiesTable, lvl *level.
 		display.highlighter.Render(objects, fineCoordinatesPerTileSide/4, [4]float32{1.0, 1.0, 1.0, 0.3})
 	}
 	if paletteTexture != nil {
-		tripleOffsets := make(map[object.Triple]int)
-
-		{
-			offset := 0
-			properties.Iterate(func(triple object.Triple, prop *object.Properties) bool {
-				numExtra := int(prop.Common.Bitmap3D.FrameNumber())
-
-				if triple.Class != object.ClassTrap {
-					tripleOffsets[triple] = offset + 2
-				} else {
-					tripleOffsets[triple] = offset
-				}
-				offset += 3 + numExtra
-				return true
-			})
-		}
+		bitmapIndices := integrity.ObjectBitmapIndices(properties)
 		var icons []iconData
 		var highlightIcon iconData
 		var highlightID level.ObjectID
@@ -226,9 +214,14 @@ func (display *MapDisplay) Render(properties object.PropertiesTable, lvl *level.
 		}
 		lvl.ForEachObject(func(id level.ObjectID, entry level.ObjectMasterEntry) {
 			triple := entry.Triple()
-			index, cached := tripleOffsets[triple]
-			if cached {
-				key := resource.KeyOf(ids.ObjectBitmaps, resource.LangAny, index+1)
+			indices, known := bitmapIndices[triple]
+			if known {
+				// Traps are shown with their first bitmap, all others with their inventory icon.
+				index := indices[2]
+				if triple.Class == object.ClassTrap {
+					index = indices[0]
+				}
+				key := resource.KeyOf(ids.ObjectBitmaps, resource.LangAny, index)
 				texture, err := textureRetriever(key)
 				if err == nil {
 					icon := iconData{pos: MapPosition{X: entry.X, Y: entry.Y}, texture: texture}
@@ -421,7 +414,7 @@ func (display *MapDisplay) MouseButtonUp(mouseX, mouseY float32, button uint32,
 			switch {
 			case modifier.Has(input.ModControl):
 				display.toggleSelectionAtActiveHoverItem()
-			case modifier.Has(input.ModShift) && (len(display.selectedTiles.list) > 0):
+			case modifier.Has(input.ModShift) && !display.selectedTiles.Empty():
 				firstPos := display.selectedTiles.list[0]
 
 				fromX := int(firstPos.X.Tile())
@@ -493,7 +486,7 @@ func (display *MapDisplay) setSelectionByActiveHoverItem() {
 func (display *MapDisplay) toggleSelectionAtActiveHoverItem() {
 	if display.activeHoverItem != nil {
 		if tileItem, isTile := display.activeHoverItem.(tileHoverItem); isTile {
-			wasSelected := display.selectedTiles.contains(tileItem.pos)
+			wasSelected := display.selectedTiles.Contains(tileItem.pos)
 			tiles := []MapPosition{tileItem.pos}
 			if wasSelected {
 				display.eventListener.Event(TileSelectionRemoveEvent{tiles: tiles})
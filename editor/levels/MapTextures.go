@@ -55,7 +55,8 @@ void main(void) {
 type TextureQuery func(index int) (*graphics.BitmapTexture, error)
 
 // TileTextureQuery is a getter function to retrieve properties for rendering a texture of a tile.
-type TileTextureQuery func(x, y int) (tileType level.TileType, textureIndex int, textureRotations int)
+// The texture is mirrored horizontally before it is rotated if flipped is set.
+type TileTextureQuery func(x, y int) (tileType level.TileType, textureIndex int, textureRotations int, flipped bool)
 
 // MapTextures is a renderable for textures.
 type MapTextures struct {
@@ -79,15 +80,22 @@ type MapTextures struct {
 }
 
 var uvRotations map[int]*mgl.Mat4
+var uvFlippedRotations map[int]*mgl.Mat4
 
 func init() {
 	uvRotations = make(map[int]*mgl.Mat4)
+	uvFlippedRotations = make(map[int]*mgl.Mat4)
+	flip := mgl.Translate3D(0.5, 0.5, 0.0).
+		Mul4(mgl.Scale3D(-1.0, 1.0, 1.0)).
+		Mul4(mgl.Translate3D(-0.5, -0.5, 0.0))
 	for i := 0; i < 4; i++ {
 		matrix := mgl.Translate3D(0.5, 0.5, 0.0).
 			Mul4(mgl.HomogRotate3DZ(math.Pi * float32(i) / -2.0)).
 			Mul4(mgl.Translate3D(-0.5, -0.5, 0.0)).
 			Mul4(mgl.Scale3D(1.0, -1.0, 1.0))
 		uvRotations[i] = &matrix
+		flipped := matrix.Mul4(flip)
+		uvFlippedRotations[i] = &flipped
 	}
 }
 
@@ -154,7 +162,7 @@ func (renderable *MapTextures) Render(columns, rows int, tileTextureQuery TileTe
 		scaling := mgl.Scale3D(fineCoordinatesPerTileSide, fineCoordinatesPerTileSide, 1.0)
 		for y := 0; y < rows; y++ {
 			for x := 0; x < columns; x++ {
-				tileType, textureIndex, textureRotations := tileTextureQuery(x, y)
+				tileType, textureIndex, textureRotations, flipped := tileTextureQuery(x, y)
 				if tileType != level.TileTypeSolid {
 					texture, _ := renderable.textureQuery(textureIndex)
 					if texture != nil {
@@ -162,6 +170,9 @@ func (renderable *MapTextures) Render(columns, rows int, tileTextureQuery TileTe
 							Mul4(scaling)
 
 						uvMatrix := uvRotations[textureRotations]
+						if flipped {
+							uvMatrix = uvFlippedRotations[textureRotations]
+						}
 						renderable.uvMatrixUniform.Set(gl, uvMatrix)
 						renderable.modelMatrixUniform.Set(gl, &modelMatrix)
 						vertexCount := renderable.ensureTileType(tileType)
@@ -1,6 +1,7 @@
 package levels
 
 import (
+	"bytes"
 	"fmt"
 	"strings"
 
@@ -17,6 +18,7 @@ import (
 	"github.com/inkyblackness/hacked/ss1/content/interpreters"
 	"github.com/inkyblackness/hacked/ss1/content/object"
 	"github.com/inkyblackness/hacked/ss1/content/text"
+	"github.com/inkyblackness/hacked/ss1/edit"
 	"github.com/inkyblackness/hacked/ss1/edit/undoable/cmd"
 	"github.com/inkyblackness/hacked/ss1/resource"
 	"github.com/inkyblackness/hacked/ss1/world"
@@ -27,9 +29,11 @@ import (
 type ObjectsView struct {
 	mod          *world.Mod
 	textCache    *text.Cache
+	codepages    *text.Codepages
 	textureCache *graphics.TextureCache
 
 	guiScale      float32
+	focus         render.FocusArea
 	commander     cmd.Commander
 	eventListener event.Listener
 
@@ -37,14 +41,17 @@ type ObjectsView struct {
 }
 
 // NewObjectsView returns a new instance.
-func NewObjectsView(mod *world.Mod, guiScale float32, textCache *text.Cache, textureCache *graphics.TextureCache,
+func NewObjectsView(mod *world.Mod, guiScale float32, focus render.FocusArea, textCache *text.Cache, codepages *text.Codepages,
+	textureCache *graphics.TextureCache,
 	commander cmd.Commander, eventListener event.Listener, eventRegistry event.Registry) *ObjectsView {
 	view := &ObjectsView{
 		mod:          mod,
 		textCache:    textCache,
+		codepages:    codepages,
 		textureCache: textureCache,
 
 		guiScale:      guiScale,
+		focus:         focus,
 		commander:     commander,
 		eventListener: eventListener,
 
@@ -69,14 +76,14 @@ func (view *ObjectsView) Render(lvl *level.Level) {
 	if view.model.windowOpen {
 		view.model.selectedObjects.filterInvalid(lvl)
 
-		imgui.SetNextWindowSizeV(imgui.Vec2{X: 400 * view.guiScale, Y: 500 * view.guiScale}, imgui.ConditionOnce)
+		imgui.SetNextWindowSizeV(render.LayoutMetricsFor(view.guiScale).TallWindowSize(), imgui.ConditionOnce)
 		title := fmt.Sprintf("Level Objects, %d selected", len(view.model.selectedObjects.list))
 		readOnly := !view.editingAllowed(lvl.ID())
 		if readOnly {
 			title += hintReadOnly
 		}
 		if imgui.BeginV(title+"###Level Objects", view.WindowOpen(), imgui.WindowFlagsHorizontalScrollbar|imgui.WindowFlagsAlwaysVerticalScrollbar) {
-			view.renderContent(lvl, readOnly)
+			view.focus.Render(func() { view.renderContent(lvl, readOnly) })
 		}
 		imgui.End()
 	}
@@ -114,7 +121,7 @@ func (view *ObjectsView) renderContent(lvl *level.Level, readOnly bool) {
 		}
 	}
 
-	imgui.PushItemWidth(-150 * view.guiScale)
+	imgui.PushItemWidth(render.LayoutMetricsFor(view.guiScale).LabelSpace())
 	multiple := len(view.model.selectedObjects.list) > 1
 	columns, rows, levelHeight := lvl.Size()
 
@@ -265,6 +272,7 @@ func (view *ObjectsView) renderContent(lvl *level.Level, readOnly bool) {
 		view.renderBlockPuzzleControl(lvl, readOnly)
 		imgui.TreePop()
 	}
+	view.renderMapNoteControl(lvl, readOnly)
 
 	imgui.PopItemWidth()
 }
@@ -365,11 +373,23 @@ func (view *ObjectsView) renderPropertyControl(lvl *level.Level, readOnly bool,
 	simplifier.SetObjectIDHandler(func() {
 		values.RenderUnifiedSliderInt(readOnly, multiple, label, unifier,
 			func(u values.Unifier) int { return int(u.Unified().(int32)) },
-			func(value int) string { return "%d" },
+			func(value int) string { return view.objectLinkFormat(lvl, level.ObjectID(value)) },
 			0, int(lvl.ObjectLimit()),
 			func(newValue int) {
+				// Links may only refer to existing objects. Zero is the link to no object.
+				if (newValue != 0) && !lvl.HasObject(level.ObjectID(newValue)) {
+					return
+				}
 				updater(func(oldValue uint32) uint32 { return uint32(newValue) })
 			})
+		if unifier.IsUnique() {
+			target := level.ObjectID(unifier.Unified().(int32))
+			if (target != 0) && !lvl.HasObject(target) {
+				imgui.PushStyleColor(imgui.StyleColorText, imgui.Vec4{X: 1.0, Y: 0.0, Z: 0.0, W: 1.0})
+				imgui.Text(fmt.Sprintf("Linked object %d does not exist!", int(target)))
+				imgui.PopStyleColor()
+			}
+		}
 	})
 
 	addVariableKey := func() {
@@ -586,6 +606,50 @@ func (view *ObjectsView) renderPropertyControl(lvl *level.Level, readOnly bool,
 	describer(simplifier)
 }
 
+func (view *ObjectsView) renderMapNoteControl(lvl *level.Level, readOnly bool) {
+	if len(view.model.selectedObjects.list) != 1 {
+		return
+	}
+	id := view.model.selectedObjects.list[0]
+	obj := lvl.Object(id)
+	if (obj == nil) || (obj.InUse == 0) || (obj.Triple() != level.MapNoteTriple) {
+		return
+	}
+	if !imgui.TreeNodeV("Map Note", imgui.TreeNodeFlagsFramed|imgui.TreeNodeFlagsDefaultOpen) {
+		return
+	}
+	note := view.codepages.Default().Decode(lvl.MapNoteText(id))
+	flags := imgui.InputTextFlagsEnterReturnsTrue
+	if readOnly {
+		flags |= imgui.InputTextFlagsReadOnly
+	}
+	if imgui.InputTextV("Text", &note, flags, nil) && !readOnly {
+		view.requestMapNoteText(lvl, id, note)
+	}
+	if imgui.IsItemHovered() {
+		imgui.SetTooltip("Text of the note on the automap. Press Enter to apply.")
+	}
+	imgui.Text(fmt.Sprintf("%d bytes free for notes of this level", lvl.MapNotesFree()))
+	if len(view.model.mapNoteError) > 0 {
+		imgui.PushStyleColor(imgui.StyleColorText, imgui.Vec4{X: 1.0, Y: 0.0, Z: 0.0, W: 1.0})
+		imgui.Text(view.model.mapNoteError)
+		imgui.PopStyleColor()
+	}
+	imgui.TreePop()
+}
+
+func (view *ObjectsView) requestMapNoteText(lvl *level.Level, id level.ObjectID, note string) {
+	encoded := bytes.TrimRight(view.codepages.Default().Encode(note), "\x00")
+	err := lvl.SetMapNoteText(id, encoded)
+	if err != nil {
+		view.model.mapNoteError = err.Error()
+		return
+	}
+	view.model.mapNoteError = ""
+	objectIDs := []level.ObjectID{id}
+	view.patchLevel(lvl, objectIDs, objectIDs)
+}
+
 func (view *ObjectsView) renderBlockPuzzleControl(lvl *level.Level, readOnly bool) {
 	var blockPuzzleData *interpreters.Instance
 
@@ -749,11 +813,12 @@ func (view *ObjectsView) requestCreateObject(lvl *level.Level, triple object.Tri
 	}
 	var objPivot float32
 	obj := lvl.Object(id)
-	prop, err := view.mod.ObjectProperties().ForObject(triple)
-	if err == nil {
-		obj.Hitpoints = prop.Common.Hitpoints
-		objPivot = object.Pivot(prop.Common)
+	common := object.StandardPropertyDefaults().PropertiesFor(triple.Class, 0, 0).Common
+	if prop, err := view.mod.ObjectProperties().ForObject(triple); err == nil {
+		common = prop.Common
 	}
+	obj.Hitpoints = common.Hitpoints
+	objPivot = object.Pivot(common)
 	obj.X = pos.X
 	obj.Y = pos.Y
 	tile := lvl.Tile(int(pos.X.Tile()), int(pos.Y.Tile()))
@@ -895,9 +960,7 @@ func (view *ObjectsView) setSelectedObjects(objectIDs []level.ObjectID) {
 
 func (view *ObjectsView) tripleName(triple object.Triple) string {
 	suffix := hintUnknown
-	linearIndex := view.mod.ObjectProperties().TripleIndex(triple)
-	if linearIndex >= 0 {
-		key := resource.KeyOf(ids.ObjectLongNames, resource.LangDefault, linearIndex)
+	if key, known := edit.ObjectNameKey(view.mod.ObjectProperties(), triple, resource.LangDefault, true); known {
 		objName, err := view.textCache.Text(key)
 		if err == nil {
 			suffix = objName
@@ -906,6 +969,17 @@ func (view *ObjectsView) tripleName(triple object.Triple) string {
 	return triple.String() + ": " + suffix
 }
 
+func (view *ObjectsView) objectLinkFormat(lvl *level.Level, id level.ObjectID) string {
+	switch {
+	case id == 0:
+		return "%d: (none)"
+	case !lvl.HasObject(id):
+		return "%d: (missing)"
+	default:
+		return "%d: " + strings.ReplaceAll(view.tripleName(lvl.Object(id).Triple()), "%", "%%")
+	}
+}
+
 func (view *ObjectsView) textureName(index int) string {
 	key := resource.KeyOf(ids.TextureNames, resource.LangDefault, index)
 	name, err := view.textCache.Text(key)
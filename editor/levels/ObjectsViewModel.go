@@ -7,6 +7,8 @@ type objectsViewModel struct {
 
 	newObjectTriple object.Triple
 
+	mapNoteError string
+
 	restoreFocus bool
 	windowOpen   bool
 }
@@ -0,0 +1,80 @@
+package levels
+
+import (
+	"github.com/inkyblackness/hacked/ss1/content/bitmap"
+)
+
+// ShadowColorFunc maps a shadow level of a real world tile to a color.
+// The level ranges from 0 (no shadow) to 15 (darkest).
+type ShadowColorFunc func(shadow int) [4]float32
+
+// CyberspaceColorFunc maps the palette index of a cyberspace tile to a color.
+type CyberspaceColorFunc func(index byte, palette *bitmap.Palette) [4]float32
+
+// TileColorScheme describes which colors are used for the color displays of the map.
+// Each ColorDisplay has its own mapping; Displays without a mapping are not rendered.
+type TileColorScheme struct {
+	// Name is the displayable name of the scheme.
+	Name string
+	// Shadow has the color mappings for real world levels.
+	Shadow map[ColorDisplay]ShadowColorFunc
+	// Cyberspace has the color mappings for cyberspace levels.
+	Cyberspace map[ColorDisplay]CyberspaceColorFunc
+}
+
+// SetShadowColors sets the color mapping for real world levels for given display.
+func (scheme *TileColorScheme) SetShadowColors(display ColorDisplay, colorFunc ShadowColorFunc) {
+	if scheme.Shadow == nil {
+		scheme.Shadow = make(map[ColorDisplay]ShadowColorFunc)
+	}
+	scheme.Shadow[display] = colorFunc
+}
+
+// SetCyberspaceColors sets the color mapping for cyberspace levels for given display.
+func (scheme *TileColorScheme) SetCyberspaceColors(display ColorDisplay, colorFunc CyberspaceColorFunc) {
+	if scheme.Cyberspace == nil {
+		scheme.Cyberspace = make(map[ColorDisplay]CyberspaceColorFunc)
+	}
+	scheme.Cyberspace[display] = colorFunc
+}
+
+// GradientTileColorScheme returns a scheme with given name.
+// Shadows go from the bright color (no shadow) to the dark color (darkest),
+// cyberspace uses the colors of the palette with given opacity.
+func GradientTileColorScheme(name string, bright, dark [4]float32, cyberspaceOpacity float32) TileColorScheme {
+	scheme := TileColorScheme{Name: name}
+	shadow := func(shadow int) [4]float32 {
+		darkness := float32(shadow) / 15.0
+		var color [4]float32
+		for index := range color {
+			color[index] = bright[index] + (dark[index]-bright[index])*darkness
+		}
+		return color
+	}
+	cyberspace := func(index byte, palette *bitmap.Palette) [4]float32 {
+		rgb := palette[index]
+		return [4]float32{float32(rgb.Red) / 255, float32(rgb.Green) / 255, float32(rgb.Blue) / 255, cyberspaceOpacity}
+	}
+	scheme.SetShadowColors(ColorDisplayFloor, shadow)
+	scheme.SetShadowColors(ColorDisplayCeiling, shadow)
+	scheme.SetCyberspaceColors(ColorDisplayFloor, cyberspace)
+	scheme.SetCyberspaceColors(ColorDisplayCeiling, cyberspace)
+	return scheme
+}
+
+// DefaultTileColorScheme returns the standard scheme.
+// Shadows are darkened by increasing opacity of black, cyberspace uses the colors of the palette.
+func DefaultTileColorScheme() TileColorScheme {
+	return GradientTileColorScheme("Default", [4]float32{0.0, 0.0, 0.0, 0.0}, [4]float32{0.0, 0.0, 0.0, 1.0}, 0.8)
+}
+
+// HighContrastTileColorScheme returns a scheme with strong colors.
+// Shadows go from opaque yellow (bright) to opaque blue (dark), cyberspace uses opaque palette colors.
+func HighContrastTileColorScheme() TileColorScheme {
+	return GradientTileColorScheme("High Contrast", [4]float32{1.0, 1.0, 0.0, 1.0}, [4]float32{0.0, 0.0, 1.0, 1.0}, 1.0)
+}
+
+// TileColorSchemes returns the list of predefined color schemes. The first one is the default.
+func TileColorSchemes() []TileColorScheme {
+	return []TileColorScheme{DefaultTileColorScheme(), HighContrastTileColorScheme()}
+}
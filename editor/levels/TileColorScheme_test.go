@@ -0,0 +1,39 @@
+package levels
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/inkyblackness/hacked/ss1/content/bitmap"
+)
+
+func TestDefaultTileColorSchemeDarkensWithOpacityOfBlack(t *testing.T) {
+	scheme := DefaultTileColorScheme()
+	shadow := scheme.Shadow[ColorDisplayFloor]
+
+	assert.Equal(t, [4]float32{0.0, 0.0, 0.0, 0.0}, shadow(0))
+	assert.InDelta(t, 5.0/15.0, shadow(5)[3], 0.0001)
+	assert.Equal(t, [4]float32{0.0, 0.0, 0.0, 1.0}, shadow(15))
+}
+
+func TestGradientTileColorSchemeInterpolatesShadows(t *testing.T) {
+	scheme := GradientTileColorScheme("Test", [4]float32{1.0, 0.0, 0.0, 1.0}, [4]float32{0.0, 0.0, 1.0, 0.5}, 0.25)
+	shadow := scheme.Shadow[ColorDisplayCeiling]
+
+	assert.Equal(t, "Test", scheme.Name)
+	assert.Equal(t, [4]float32{1.0, 0.0, 0.0, 1.0}, shadow(0))
+	assert.Equal(t, [4]float32{0.0, 0.0, 1.0, 0.5}, shadow(15))
+	mid := shadow(3)
+	assert.InDelta(t, 0.8, mid[0], 0.0001)
+	assert.InDelta(t, 0.2, mid[2], 0.0001)
+	assert.InDelta(t, 0.9, mid[3], 0.0001)
+}
+
+func TestGradientTileColorSchemeUsesPaletteForCyberspace(t *testing.T) {
+	var palette bitmap.Palette
+	palette[7] = bitmap.RGB{Red: 255, Green: 0, Blue: 255}
+	scheme := GradientTileColorScheme("Test", [4]float32{}, [4]float32{}, 0.25)
+
+	assert.Equal(t, [4]float32{1.0, 0.0, 1.0, 0.25}, scheme.Cyberspace[ColorDisplayFloor](7, &palette))
+}
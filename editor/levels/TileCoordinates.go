@@ -1,14 +1,146 @@
 package levels
 
 import (
+	"fmt"
+	"sort"
+	"strings"
+
 	"github.com/inkyblackness/hacked/editor/event"
+	"github.com/inkyblackness/hacked/ss1/content/archive/level"
 )
 
-type tileCoordinates struct {
+// tileMap is the source of tiles the selection helpers work on.
+type tileMap interface {
+	Size() (x, y int, z level.HeightShift)
+	Tile(x, y int) *level.TileMapEntry
+}
+
+// TileCoordinates is a set of tile positions, typically a selection of tiles.
+// The zero value is an empty set. Queries on the set do not allocate memory.
+type TileCoordinates struct {
 	list []MapPosition
 }
 
-func (coords tileCoordinates) contains(pos MapPosition) bool {
+func tilePositionAt(x, y int) MapPosition {
+	return MapPosition{X: level.CoordinateAt(byte(x), 128), Y: level.CoordinateAt(byte(y), 128)}
+}
+
+// tilesInRectangle returns the coordinates of all tiles between the two corners, inclusive.
+func tilesInRectangle(from, to MapPosition) TileCoordinates {
+	fromX, toX := int(from.X.Tile()), int(to.X.Tile())
+	fromY, toY := int(from.Y.Tile()), int(to.Y.Tile())
+	if fromX > toX {
+		fromX, toX = toX, fromX
+	}
+	if fromY > toY {
+		fromY, toY = toY, fromY
+	}
+	var coords TileCoordinates
+	for y := fromY; y <= toY; y++ {
+		for x := fromX; x <= toX; x++ {
+			coords.list = append(coords.list, tilePositionAt(x, y))
+		}
+	}
+	return coords
+}
+
+// tilesConnectedTo returns the coordinates of all tiles that are reachable from the start tile
+// via their four direct neighbours, and for which the matcher returns true.
+// The start tile is part of the result if it matches.
+// If passable is not nil, it is additionally asked whether the step from one matching tile to its neighbour is possible.
+func tilesConnectedTo(tiles tileMap, start MapPosition, matches func(tile *level.TileMapEntry) bool,
+	passable func(from, to *level.TileMapEntry) bool) TileCoordinates {
+	type step struct {
+		from *level.TileMapEntry
+		x, y int
+	}
+	width, height, _ := tiles.Size()
+	var coords TileCoordinates
+	visited := make([]bool, width*height)
+	pending := []step{{x: int(start.X.Tile()), y: int(start.Y.Tile())}}
+	for len(pending) > 0 {
+		next := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		x, y := next.x, next.y
+		if (x < 0) || (x >= width) || (y < 0) || (y >= height) || visited[y*width+x] {
+			continue
+		}
+		tile := tiles.Tile(x, y)
+		if (tile == nil) || !matches(tile) {
+			visited[y*width+x] = true
+			continue
+		}
+		if (next.from != nil) && (passable != nil) && !passable(next.from, tile) {
+			continue
+		}
+		visited[y*width+x] = true
+		coords.list = append(coords.list, tilePositionAt(x, y))
+		pending = append(pending,
+			step{from: tile, x: x - 1, y: y}, step{from: tile, x: x + 1, y: y},
+			step{from: tile, x: x, y: y - 1}, step{from: tile, x: x, y: y + 1})
+	}
+	return coords
+}
+
+// tilesMatchingFloorTexture returns the coordinates of all connected, non-solid tiles that
+// share the floor texture of the start tile. For cyberspace levels, the floor palette index is compared.
+func tilesMatchingFloorTexture(tiles tileMap, start MapPosition, isCyberspace bool) TileCoordinates {
+	startTile := tiles.Tile(int(start.X.Tile()), int(start.Y.Tile()))
+	if startTile == nil {
+		return TileCoordinates{}
+	}
+	floorOf := func(tile *level.TileMapEntry) int {
+		if isCyberspace {
+			return int(tile.TextureInfo.FloorPaletteIndex())
+		}
+		return tile.TextureInfo.FloorTextureIndex()
+	}
+	reference := floorOf(startTile)
+	return tilesConnectedTo(tiles, start, func(tile *level.TileMapEntry) bool {
+		return (tile.Type != level.TileTypeSolid) && (floorOf(tile) == reference)
+	}, nil)
+}
+
+// tilesForFloorTextureFill returns the coordinates of the tiles a flood fill of the floor texture,
+// starting at given position, would affect. The fill spreads to all connected, non-solid tiles that
+// share the floor texture of the start tile.
+// If stopAtHeightChange is set, the fill does not spread between tiles with different floor height.
+func tilesForFloorTextureFill(tiles tileMap, start MapPosition, stopAtHeightChange bool) TileCoordinates {
+	startTile := tiles.Tile(int(start.X.Tile()), int(start.Y.Tile()))
+	if (startTile == nil) || (startTile.Type == level.TileTypeSolid) {
+		return TileCoordinates{}
+	}
+	reference := startTile.TextureInfo.FloorTextureIndex()
+	var passable func(from, to *level.TileMapEntry) bool
+	if stopAtHeightChange {
+		passable = func(from, to *level.TileMapEntry) bool {
+			return from.Floor.AbsoluteHeight() == to.Floor.AbsoluteHeight()
+		}
+	}
+	return tilesConnectedTo(tiles, start, func(tile *level.TileMapEntry) bool {
+		return (tile.Type != level.TileTypeSolid) && (tile.TextureInfo.FloorTextureIndex() == reference)
+	}, passable)
+}
+
+// Len returns the number of contained positions.
+func (coords TileCoordinates) Len() int {
+	return len(coords.list)
+}
+
+// Empty returns true if no position is contained.
+func (coords TileCoordinates) Empty() bool {
+	return len(coords.list) == 0
+}
+
+// ForEach calls the given function for each contained position, in order of their addition.
+func (coords TileCoordinates) ForEach(fn func(pos MapPosition)) {
+	for _, pos := range coords.list {
+		fn(pos)
+	}
+}
+
+// Contains returns true if the given position is part of the set.
+func (coords TileCoordinates) Contains(pos MapPosition) bool {
 	for _, entry := range coords.list {
 		if entry == pos {
 			return true
@@ -17,21 +149,100 @@ func (coords tileCoordinates) contains(pos MapPosition) bool {
 	return false
 }
 
-func (coords *tileCoordinates) registerAt(registry event.Registry) {
+// Bounds returns the smallest and largest coordinates on both axes of all contained positions.
+// The returned flag is false if the set is empty, in which case the positions are zero.
+func (coords TileCoordinates) Bounds() (min, max MapPosition, ok bool) {
+	if len(coords.list) == 0 {
+		return
+	}
+	min, max = coords.list[0], coords.list[0]
+	for _, pos := range coords.list[1:] {
+		if pos.X < min.X {
+			min.X = pos.X
+		}
+		if pos.Y < min.Y {
+			min.Y = pos.Y
+		}
+		if pos.X > max.X {
+			max.X = pos.X
+		}
+		if pos.Y > max.Y {
+			max.Y = pos.Y
+		}
+	}
+	return min, max, true
+}
+
+// describe returns a readable, multi-line description of the contained tiles and their properties.
+// The first line states the number of tiles, followed by one line per tile, ordered by row, then column.
+// Positions outside of the map are listed without properties.
+func (coords TileCoordinates) describe(tiles tileMap, isCyberspace bool) string {
+	sorted := make([]MapPosition, len(coords.list))
+	copy(sorted, coords.list)
+	sort.Slice(sorted, func(a, b int) bool {
+		if sorted[a].Y.Tile() != sorted[b].Y.Tile() {
+			return sorted[a].Y.Tile() < sorted[b].Y.Tile()
+		}
+		return sorted[a].X.Tile() < sorted[b].X.Tile()
+	})
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("%d tiles", len(sorted)))
+	for _, pos := range sorted {
+		x, y := int(pos.X.Tile()), int(pos.Y.Tile())
+		builder.WriteString(fmt.Sprintf("\n%d/%d: ", x, y))
+		tile := tiles.Tile(x, y)
+		if tile == nil {
+			builder.WriteString("outside of map")
+			continue
+		}
+		builder.WriteString(fmt.Sprintf("%v, floor %d, ceiling %d, slope %d",
+			tile.Type, tile.Floor.AbsoluteHeight(), tile.Ceiling.AbsoluteHeight(), tile.SlopeHeight))
+		if isCyberspace {
+			builder.WriteString(fmt.Sprintf(", floor color %d, ceiling color %d",
+				tile.TextureInfo.FloorPaletteIndex(), tile.TextureInfo.CeilingPaletteIndex()))
+		} else {
+			builder.WriteString(fmt.Sprintf(", floor texture %d (rotations %d), ceiling texture %d (rotations %d), wall texture %d",
+				tile.TextureInfo.FloorTextureIndex(), tile.Floor.TextureRotations(),
+				tile.TextureInfo.CeilingTextureIndex(), tile.Ceiling.TextureRotations(),
+				tile.TextureInfo.WallTextureIndex()))
+		}
+	}
+	return builder.String()
+}
+
+// inverted returns the coordinates of all tiles of a map with given size that are not contained.
+func (coords TileCoordinates) inverted(width, height int) TileCoordinates {
+	selected := make(map[MapPosition]bool)
+	for _, pos := range coords.list {
+		selected[tilePositionAt(int(pos.X.Tile()), int(pos.Y.Tile()))] = true
+	}
+	var result TileCoordinates
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			pos := tilePositionAt(x, y)
+			if !selected[pos] {
+				result.list = append(result.list, pos)
+			}
+		}
+	}
+	return result
+}
+
+func (coords *TileCoordinates) registerAt(registry event.Registry) {
 	registry.RegisterHandler(coords.onTileSelectionSetEvent)
 	registry.RegisterHandler(coords.onTileSelectionAddEvent)
 	registry.RegisterHandler(coords.onTileSelectionRemoveEvent)
 }
 
-func (coords *tileCoordinates) onTileSelectionSetEvent(evt TileSelectionSetEvent) {
+func (coords *TileCoordinates) onTileSelectionSetEvent(evt TileSelectionSetEvent) {
 	coords.list = evt.tiles
 }
 
-func (coords *tileCoordinates) onTileSelectionAddEvent(evt TileSelectionAddEvent) {
+func (coords *TileCoordinates) onTileSelectionAddEvent(evt TileSelectionAddEvent) {
 	coords.list = append(coords.list, evt.tiles...)
 }
 
-func (coords *tileCoordinates) onTileSelectionRemoveEvent(evt TileSelectionRemoveEvent) {
+func (coords *TileCoordinates) onTileSelectionRemoveEvent(evt TileSelectionRemoveEvent) {
 	newList := make([]MapPosition, 0, len(coords.list))
 	for _, oldEntry := range coords.list {
 		keep := true
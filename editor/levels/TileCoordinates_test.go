@@ -0,0 +1,223 @@
+package levels
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/inkyblackness/hacked/ss1/content/archive/level"
+)
+
+func TestTileCoordinatesEmpty(t *testing.T) {
+	var coords TileCoordinates
+
+	assert.True(t, coords.Empty(), "zero value should be empty")
+	assert.Equal(t, 0, coords.Len())
+	assert.False(t, coords.Contains(tilePositionAt(0, 0)), "empty set should contain nothing")
+	_, _, ok := coords.Bounds()
+	assert.False(t, ok, "empty set should have no bounds")
+	coords.ForEach(func(pos MapPosition) {
+		assert.Fail(t, "no position should be iterated")
+	})
+}
+
+func TestTileCoordinatesSingle(t *testing.T) {
+	pos := tilePositionAt(3, 5)
+	coords := TileCoordinates{list: []MapPosition{pos}}
+
+	assert.False(t, coords.Empty(), "set should not be empty")
+	assert.Equal(t, 1, coords.Len())
+	assert.True(t, coords.Contains(pos), "position should be contained")
+	assert.False(t, coords.Contains(tilePositionAt(5, 3)), "other position should not be contained")
+	min, max, ok := coords.Bounds()
+	assert.True(t, ok, "bounds expected")
+	assert.Equal(t, pos, min)
+	assert.Equal(t, pos, max)
+}
+
+func TestTileCoordinatesScattered(t *testing.T) {
+	positions := []MapPosition{tilePositionAt(10, 2), tilePositionAt(4, 7), tilePositionAt(6, 1), tilePositionAt(12, 3)}
+	coords := TileCoordinates{list: positions}
+
+	var iterated []MapPosition
+	coords.ForEach(func(pos MapPosition) { iterated = append(iterated, pos) })
+	assert.Equal(t, positions, iterated, "iteration should be in order of addition")
+	for _, pos := range positions {
+		assert.True(t, coords.Contains(pos), "position %v should be contained", pos)
+	}
+	assert.False(t, coords.Contains(tilePositionAt(6, 2)), "position within bounds should not be contained")
+
+	min, max, ok := coords.Bounds()
+	assert.True(t, ok, "bounds expected")
+	assert.Equal(t, tilePositionAt(4, 1), min)
+	assert.Equal(t, tilePositionAt(12, 7), max)
+}
+
+func TestTileCoordinatesQueriesDoNotAllocate(t *testing.T) {
+	coords := tilesInRectangle(tilePositionAt(0, 0), tilePositionAt(7, 7))
+	count := 0
+	allocations := testing.AllocsPerRun(10, func() {
+		coords.ForEach(func(pos MapPosition) { count++ })
+		_ = coords.Contains(tilePositionAt(7, 7))
+		_, _, _ = coords.Bounds()
+	})
+	assert.Equal(t, 0.0, allocations)
+}
+
+type testTileMap struct {
+	level.TileMap
+}
+
+func (tiles testTileMap) Size() (x, y int, z level.HeightShift) {
+	return len(tiles.TileMap[0]), len(tiles.TileMap), 0
+}
+
+func TestTileCoordinatesDescribe(t *testing.T) {
+	tiles := testTileMap{TileMap: level.NewTileMap(4, 4)}
+	tile := tiles.Tile(2, 1)
+	tile.Type = level.TileTypeOpen
+	tile.Floor = tile.Floor.WithAbsoluteHeight(3)
+	tile.Ceiling = tile.Ceiling.WithAbsoluteHeight(20).WithTextureRotations(1)
+	tile.TextureInfo = tile.TextureInfo.WithFloorTextureIndex(5).WithCeilingTextureIndex(6).WithWallTextureIndex(7)
+	coords := TileCoordinates{list: []MapPosition{tilePositionAt(2, 1), tilePositionAt(0, 0), tilePositionAt(9, 9)}}
+
+	description := coords.describe(tiles, false)
+
+	assert.Equal(t, "3 tiles\n"+
+		"0/0: "+level.TileTypeSolid.String()+", floor 0, ceiling 32, slope 0, floor texture 0 (rotations 0), ceiling texture 0 (rotations 0), wall texture 0\n"+
+		"2/1: "+level.TileTypeOpen.String()+", floor 3, ceiling 20, slope 0, floor texture 5 (rotations 0), ceiling texture 6 (rotations 1), wall texture 7\n"+
+		"9/9: outside of map", description)
+}
+
+func TestTileCoordinatesDescribeCyberspace(t *testing.T) {
+	tiles := testTileMap{TileMap: level.NewTileMap(2, 2)}
+	tile := tiles.Tile(1, 1)
+	tile.TextureInfo = tile.TextureInfo.WithFloorPaletteIndex(0x10).WithCeilingPaletteIndex(0x20)
+	coords := TileCoordinates{list: []MapPosition{tilePositionAt(1, 1)}}
+
+	description := coords.describe(tiles, true)
+
+	assert.Equal(t, "1 tiles\n1/1: "+level.TileTypeSolid.String()+", floor 0, ceiling 32, slope 0, floor color 16, ceiling color 32", description)
+}
+
+func givenOpenTile(tiles testTileMap, x, y int, floorTexture int, floorHeight level.TileHeightUnit) {
+	tile := tiles.Tile(x, y)
+	tile.Type = level.TileTypeOpen
+	tile.TextureInfo = tile.TextureInfo.WithFloorTextureIndex(floorTexture)
+	tile.Floor = tile.Floor.WithAbsoluteHeight(floorHeight)
+}
+
+func sortedTiles(coords TileCoordinates) []MapPosition {
+	var result []MapPosition
+	for y := 0; y < 256; y++ {
+		for x := 0; x < 256; x++ {
+			if pos := tilePositionAt(x, y); coords.Contains(pos) {
+				result = append(result, pos)
+			}
+		}
+	}
+	return result
+}
+
+func TestTilesInRectangleIncludesCornersInAnyOrder(t *testing.T) {
+	coords := tilesInRectangle(tilePositionAt(3, 2), tilePositionAt(1, 1))
+
+	assert.Equal(t, []MapPosition{
+		tilePositionAt(1, 1), tilePositionAt(2, 1), tilePositionAt(3, 1),
+		tilePositionAt(1, 2), tilePositionAt(2, 2), tilePositionAt(3, 2),
+	}, coords.list)
+}
+
+func TestTileCoordinatesInvertedSelectsAllOtherTiles(t *testing.T) {
+	coords := TileCoordinates{list: []MapPosition{tilePositionAt(0, 0), tilePositionAt(1, 1)}}
+
+	inverted := coords.inverted(2, 2)
+
+	assert.Equal(t, []MapPosition{tilePositionAt(1, 0), tilePositionAt(0, 1)}, inverted.list)
+	assert.Equal(t, coords.list, inverted.inverted(2, 2).list, "inverting twice should restore the selection")
+}
+
+func TestTilesMatchingFloorTextureSelectsConnectedTilesOnly(t *testing.T) {
+	tiles := testTileMap{TileMap: level.NewTileMap(5, 3)}
+	givenOpenTile(tiles, 0, 0, 5, 0)
+	givenOpenTile(tiles, 1, 0, 5, 0)
+	givenOpenTile(tiles, 1, 1, 5, 4)
+	givenOpenTile(tiles, 2, 0, 6, 0)
+	givenOpenTile(tiles, 4, 0, 5, 0)
+
+	coords := tilesMatchingFloorTexture(tiles, tilePositionAt(0, 0), false)
+
+	assert.Equal(t, []MapPosition{tilePositionAt(0, 0), tilePositionAt(1, 0), tilePositionAt(1, 1)}, sortedTiles(coords))
+}
+
+func TestTilesMatchingFloorTextureComparesColorsInCyberspace(t *testing.T) {
+	tiles := testTileMap{TileMap: level.NewTileMap(3, 1)}
+	for x := 0; x < 3; x++ {
+		tile := tiles.Tile(x, 0)
+		tile.Type = level.TileTypeOpen
+		tile.TextureInfo = tile.TextureInfo.WithFloorPaletteIndex(0x10)
+	}
+	tiles.Tile(2, 0).TextureInfo = tiles.Tile(2, 0).TextureInfo.WithFloorPaletteIndex(0x20)
+
+	coords := tilesMatchingFloorTexture(tiles, tilePositionAt(0, 0), true)
+
+	assert.Equal(t, []MapPosition{tilePositionAt(0, 0), tilePositionAt(1, 0)}, sortedTiles(coords))
+}
+
+func TestTilesMatchingFloorTextureOutsideOfMapIsEmpty(t *testing.T) {
+	tiles := testTileMap{TileMap: level.NewTileMap(2, 2)}
+
+	coords := tilesMatchingFloorTexture(tiles, tilePositionAt(5, 5), false)
+
+	assert.True(t, coords.Empty(), "no tiles expected")
+}
+
+func TestTilesForFloorTextureFill(t *testing.T) {
+	tt := []struct {
+		name               string
+		stopAtHeightChange bool
+		expected           []MapPosition
+	}{
+		{name: "spreading across heights", stopAtHeightChange: false,
+			expected: []MapPosition{tilePositionAt(0, 0), tilePositionAt(1, 0), tilePositionAt(2, 0), tilePositionAt(1, 1)}},
+		{name: "stopping at height changes", stopAtHeightChange: true,
+			expected: []MapPosition{tilePositionAt(0, 0), tilePositionAt(1, 0)}},
+	}
+	for _, tc := range tt {
+		td := tc
+		t.Run(td.name, func(t *testing.T) {
+			tiles := testTileMap{TileMap: level.NewTileMap(4, 3)}
+			givenOpenTile(tiles, 0, 0, 5, 0)
+			givenOpenTile(tiles, 1, 0, 5, 0)
+			givenOpenTile(tiles, 2, 0, 5, 4)
+			givenOpenTile(tiles, 1, 1, 5, 4)
+			givenOpenTile(tiles, 3, 0, 6, 4)
+			givenOpenTile(tiles, 1, 2, 5, 0)
+			tiles.Tile(1, 2).Type = level.TileTypeSolid
+
+			coords := tilesForFloorTextureFill(tiles, tilePositionAt(0, 0), td.stopAtHeightChange)
+
+			assert.Equal(t, td.expected, sortedTiles(coords))
+		})
+	}
+}
+
+func TestTilesForFloorTextureFillStopsAtHeightChangeInBothDirections(t *testing.T) {
+	tiles := testTileMap{TileMap: level.NewTileMap(3, 1)}
+	givenOpenTile(tiles, 0, 0, 5, 2)
+	givenOpenTile(tiles, 1, 0, 5, 0)
+	givenOpenTile(tiles, 2, 0, 5, 2)
+
+	coords := tilesForFloorTextureFill(tiles, tilePositionAt(1, 0), true)
+
+	assert.Equal(t, []MapPosition{tilePositionAt(1, 0)}, sortedTiles(coords))
+}
+
+func TestTilesForFloorTextureFillFromSolidTileIsEmpty(t *testing.T) {
+	tiles := testTileMap{TileMap: level.NewTileMap(2, 2)}
+	givenOpenTile(tiles, 1, 1, 5, 0)
+
+	coords := tilesForFloorTextureFill(tiles, tilePositionAt(0, 0), false)
+
+	assert.True(t, coords.Empty(), "no tiles expected")
+}
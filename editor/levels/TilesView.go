@@ -6,6 +6,7 @@ import (
 	"github.com/inkyblackness/imgui-go"
 
 	"github.com/inkyblackness/hacked/editor/event"
+	"github.com/inkyblackness/hacked/editor/external"
 	"github.com/inkyblackness/hacked/editor/graphics"
 	"github.com/inkyblackness/hacked/editor/render"
 	"github.com/inkyblackness/hacked/editor/values"
@@ -17,6 +18,7 @@ import (
 	"github.com/inkyblackness/hacked/ss1/resource"
 	"github.com/inkyblackness/hacked/ss1/world"
 	"github.com/inkyblackness/hacked/ss1/world/ids"
+	"github.com/inkyblackness/hacked/ui/gui"
 )
 
 // TilesView is for tile properties.
@@ -24,27 +26,36 @@ type TilesView struct {
 	mod          *world.Mod
 	textCache    *text.Cache
 	textureCache *graphics.TextureCache
+	clipboard    external.Clipboard
 
 	guiScale      float32
+	focus         render.FocusArea
 	commander     cmd.Commander
 	eventListener event.Listener
 
+	// colorSchemes are the predefined schemes, followed by the custom one, which is updated when edited.
+	colorSchemes []TileColorScheme
+
 	model tilesViewModel
 }
 
 // NewTilesView returns a new instance.
-func NewTilesView(mod *world.Mod, guiScale float32, textCache *text.Cache, textureCache *graphics.TextureCache,
+func NewTilesView(mod *world.Mod, guiScale float32, focus render.FocusArea, textCache *text.Cache, textureCache *graphics.TextureCache,
+	clipboard external.Clipboard,
 	commander cmd.Commander, eventListener event.Listener, eventRegistry event.Registry) *TilesView {
 	view := &TilesView{
 		mod:          mod,
 		textCache:    textCache,
 		textureCache: textureCache,
+		clipboard:    clipboard,
 
 		guiScale:      guiScale,
+		focus:         focus,
 		commander:     commander,
 		eventListener: eventListener,
 		model:         freshTilesViewModel(),
 	}
+	view.colorSchemes = append(TileColorSchemes(), view.customColorScheme())
 	view.model.selectedTiles.registerAt(eventRegistry)
 	return view
 }
@@ -67,6 +78,24 @@ func (view TilesView) ColorDisplay(lvl *level.Level) ColorDisplay {
 	return view.model.shadowDisplay
 }
 
+// ColorScheme returns the currently selected scheme for the color displays.
+func (view TilesView) ColorScheme() TileColorScheme {
+	return view.colorSchemes[view.model.colorSchemeIndex]
+}
+
+func (view *TilesView) customColorScheme() TileColorScheme {
+	return GradientTileColorScheme("Custom", view.model.customBright, view.model.customDark, view.model.customCyberOpaque)
+}
+
+func (view *TilesView) customColorSchemeIndex() int {
+	return len(view.colorSchemes) - 1
+}
+
+// GridOverlay returns the current settings of the grid drawn over the map.
+func (view TilesView) GridOverlay() GridOverlay {
+	return view.model.gridOverlay
+}
+
 // Render renders the view.
 func (view *TilesView) Render(lvl *level.Level) {
 	if view.model.restoreFocus {
@@ -75,14 +104,14 @@ func (view *TilesView) Render(lvl *level.Level) {
 		view.model.windowOpen = true
 	}
 	if view.model.windowOpen {
-		imgui.SetNextWindowSizeV(imgui.Vec2{X: 400 * view.guiScale, Y: 500 * view.guiScale}, imgui.ConditionOnce)
-		title := fmt.Sprintf("Level Tiles, %d selected", len(view.model.selectedTiles.list))
+		imgui.SetNextWindowSizeV(render.LayoutMetricsFor(view.guiScale).TallWindowSize(), imgui.ConditionOnce)
+		title := fmt.Sprintf("Level Tiles, %d selected", view.model.selectedTiles.Len())
 		readOnly := !view.editingAllowed(lvl.ID())
 		if readOnly {
 			title += hintReadOnly
 		}
 		if imgui.BeginV(title+"###Level Tiles", view.WindowOpen(), 0) {
-			view.renderContent(lvl, readOnly)
+			view.focus.Render(func() { view.renderContent(lvl, readOnly) })
 		}
 		imgui.End()
 	}
@@ -116,7 +145,7 @@ func (view *TilesView) renderContent(lvl *level.Level, readOnly bool) {
 	floorHazardUnifier := values.NewUnifier()
 	ceilingHazardUnifier := values.NewUnifier()
 
-	multiple := len(view.model.selectedTiles.list) > 1
+	multiple := view.model.selectedTiles.Len() > 1
 	for _, pos := range view.model.selectedTiles.list {
 		tile := lvl.Tile(int(pos.X.Tile()), int(pos.Y.Tile()))
 		tileTypeUnifier.Add(tile.Type)
@@ -148,7 +177,15 @@ func (view *TilesView) renderContent(lvl *level.Level, readOnly bool) {
 		}
 	}
 
-	imgui.PushItemWidth(-250 * view.guiScale)
+	view.renderSelectionHelpers(lvl)
+
+	imgui.PushItemWidth(render.LayoutMetricsFor(view.guiScale).ExtraWideLabelSpace())
+
+	view.renderGridOverlay()
+
+	if !readOnly && !view.model.selectedTiles.Empty() {
+		view.renderBulkFlags(lvl)
+	}
 
 	_, _, levelHeight := lvl.Size()
 	tileHeightFormatter := tileHeightFormatterFor(levelHeight)
@@ -212,6 +249,7 @@ func (view *TilesView) renderContent(lvl *level.Level, readOnly bool) {
 			}
 			imgui.EndCombo()
 		}
+		view.renderColorSchemeCombo()
 
 		values.RenderUnifiedSliderInt(readOnly, multiple, "Floor Color", floorPaletteIndexUnifier,
 			func(u values.Unifier) int { return int(u.Unified().(byte)) },
@@ -270,8 +308,17 @@ func (view *TilesView) renderContent(lvl *level.Level, readOnly bool) {
 			})
 		view.renderTextureSelector(readOnly, multiple, "Floor Texture", floorTextureIndexUnifier, atlas, 0, level.FloorCeilingTextureLimit-1,
 			func(newValue int) {
-				view.requestFloorTextureIndex(lvl, view.model.selectedTiles.list, newValue)
+				view.requestFloorTextureIndex(lvl, view.floorTextureTargets(lvl), newValue)
 			})
+		imgui.Checkbox("Flood Fill Floor Texture", &view.model.floodFillFloor)
+		if imgui.IsItemHovered() {
+			imgui.SetTooltip("Selecting a floor texture for a single tile spreads it to all connected tiles\n" +
+				"that share the original floor texture.")
+		}
+		if view.model.floodFillFloor {
+			imgui.SameLine()
+			imgui.Checkbox("Stop at Height Changes", &view.model.floodFillStopAtHeights)
+		}
 		values.RenderUnifiedSliderInt(readOnly, multiple, "Floor Texture Rotations", floorTextureRotationsUnifier,
 			func(u values.Unifier) int { return u.Unified().(int) },
 			func(value int) string { return "%d" },
@@ -330,6 +377,10 @@ func (view *TilesView) renderContent(lvl *level.Level, readOnly bool) {
 			func(newValue int) {
 				view.requestWallTexturePattern(lvl, view.model.selectedTiles.list, wallTexturePatterns[newValue])
 			})
+		if !readOnly && !view.model.selectedTiles.Empty() {
+			view.renderTextureOrientationButtons(lvl)
+			view.renderWallTextureStyle(lvl, atlas, tileHeightFormatter)
+		}
 
 		imgui.Separator()
 
@@ -344,6 +395,7 @@ func (view *TilesView) renderContent(lvl *level.Level, readOnly bool) {
 			}
 			imgui.EndCombo()
 		}
+		view.renderColorSchemeCombo()
 
 		values.RenderUnifiedSliderInt(readOnly, multiple, "Floor Light", floorLightUnifier,
 			func(u values.Unifier) int { return u.Unified().(int) },
@@ -379,6 +431,197 @@ func (view *TilesView) renderContent(lvl *level.Level, readOnly bool) {
 	imgui.PopItemWidth()
 }
 
+func (view *TilesView) renderSelectionHelpers(lvl *level.Level) {
+	selected := view.model.selectedTiles.list
+	imgui.BeginGroup()
+	if len(selected) > 0 {
+		if imgui.Button("Select Matching Floor") {
+			view.setSelectedTiles(tilesMatchingFloorTexture(lvl, selected[len(selected)-1], lvl.IsCyberspace()).list)
+		}
+		if imgui.IsItemHovered() {
+			imgui.SetTooltip("Selects all connected tiles with the same floor texture as the last selected tile.")
+		}
+		imgui.SameLine()
+		if imgui.Button("Select Rectangle") {
+			view.setSelectedTiles(tilesInRectangle(selected[0], selected[len(selected)-1]).list)
+		}
+		if imgui.IsItemHovered() {
+			imgui.SetTooltip("Selects all tiles between the first and the last selected tile.")
+		}
+		imgui.SameLine()
+	}
+	if imgui.Button("Invert Selection") {
+		width, height, _ := lvl.Size()
+		view.setSelectedTiles(view.model.selectedTiles.inverted(width, height).list)
+	}
+	imgui.EndGroup()
+	if imgui.BeginPopupContextItemV("Tile Selection-Popup", 1) {
+		if imgui.MenuItemV("Copy Selection to Clipboard", "", false, len(selected) > 0) {
+			view.clipboard.SetString(view.model.selectedTiles.describe(lvl, lvl.IsCyberspace()))
+		}
+		imgui.EndPopup()
+	}
+	imgui.Separator()
+}
+
+// renderTextureOrientationButtons renders the buttons to rotate and flip the textures of the selection.
+// Cyberspace has no textures, so the buttons are not available there.
+func (view *TilesView) renderTextureOrientationButtons(lvl *level.Level) {
+	if lvl.IsCyberspace() {
+		return
+	}
+	if imgui.Button("Rotate Textures Left") {
+		view.requestTextureRotation(lvl, view.model.selectedTiles.list, -1)
+	}
+	imgui.SameLine()
+	if imgui.Button("Rotate Textures Right") {
+		view.requestTextureRotation(lvl, view.model.selectedTiles.list, 1)
+	}
+	imgui.SameLine()
+	if imgui.Button("Flip Wall Texture") {
+		view.requestWallTextureFlip(lvl, view.model.selectedTiles.list)
+	}
+	if len(view.model.textureOrientationError) > 0 {
+		imgui.PushStyleColor(imgui.StyleColorText, imgui.Vec4{X: 1.0, Y: 0.0, Z: 0.0, W: 1.0})
+		imgui.Text(view.model.textureOrientationError)
+		imgui.PopStyleColor()
+	}
+}
+
+func (view *TilesView) renderWallTextureStyle(lvl *level.Level, atlas level.TextureAtlas, heightFormatter func(int) string) {
+	if !imgui.TreeNode("Wall Texture and Style") {
+		return
+	}
+	selected := view.model.selectedTiles.list
+	if imgui.Button("Take from Last Selected") {
+		last := selected[len(selected)-1]
+		tile := lvl.Tile(int(last.X.Tile()), int(last.Y.Tile()))
+		if tile != nil {
+			view.model.wallTextureIndex = tile.TextureInfo.WallTextureIndex()
+			view.model.wallTextureStyle = level.WallTextureStyleOf(tile)
+		}
+	}
+	style := &view.model.wallTextureStyle
+	if len(atlas) > 0 {
+		gui.StepSliderIntV("Texture (atlas index)", &view.model.wallTextureIndex, 0, len(atlas)-1, "%d")
+	} else {
+		imgui.Text("The level has no textures in its atlas.")
+	}
+	offset := int(style.Offset)
+	if gui.StepSliderIntV("Offset", &offset, 0, int(level.TileHeightUnitMax)-1, heightFormatter(offset)) {
+		style.Offset = level.TileHeightUnit(offset)
+	}
+	imgui.Checkbox("Use Adjacent", &style.UseAdjacent)
+	if imgui.BeginCombo("Pattern", style.Pattern.String()) {
+		for _, pattern := range level.WallTexturePatterns() {
+			if imgui.SelectableV(pattern.String(), pattern == style.Pattern, 0, imgui.Vec2{}) {
+				style.Pattern = pattern
+			}
+		}
+		imgui.EndCombo()
+	}
+	if imgui.Button("Apply to Selection") {
+		view.requestWallTexture(lvl, selected, view.model.wallTextureIndex, view.model.wallTextureStyle)
+	}
+	if imgui.IsItemHovered() {
+		imgui.SetTooltip("Sets the wall texture and its style of all selected tiles in one step.")
+	}
+	if len(view.model.wallTextureError) > 0 {
+		imgui.PushStyleColor(imgui.StyleColorText, imgui.Vec4{X: 1.0, Y: 0.0, Z: 0.0, W: 1.0})
+		imgui.Text(view.model.wallTextureError)
+		imgui.PopStyleColor()
+	}
+	imgui.TreePop()
+}
+
+func (view *TilesView) renderBulkFlags(lvl *level.Level) {
+	isCyberspace := lvl.IsCyberspace()
+	if !view.model.bulkFlag.AvailableIn(isCyberspace) {
+		view.model.bulkFlag = level.EditableTileFlagCeilingMirrored
+	}
+	if imgui.BeginCombo("Bulk Flag", view.model.bulkFlag.String()) {
+		for _, flag := range level.EditableTileFlags() {
+			if !flag.AvailableIn(isCyberspace) {
+				continue
+			}
+			if imgui.SelectableV(flag.String(), flag == view.model.bulkFlag, 0, imgui.Vec2{}) {
+				view.model.bulkFlag = flag
+			}
+		}
+		imgui.EndCombo()
+	}
+	if imgui.Button("Set Flag") {
+		view.requestTileFlags(lvl, view.model.selectedTiles.list, []level.EditableTileFlag{view.model.bulkFlag}, true)
+	}
+	imgui.SameLine()
+	if imgui.Button("Clear Flag") {
+		view.requestTileFlags(lvl, view.model.selectedTiles.list, []level.EditableTileFlag{view.model.bulkFlag}, false)
+	}
+	if len(view.model.bulkFlagError) > 0 {
+		imgui.PushStyleColor(imgui.StyleColorText, imgui.Vec4{X: 1.0, Y: 0.0, Z: 0.0, W: 1.0})
+		imgui.Text(view.model.bulkFlagError)
+		imgui.PopStyleColor()
+	}
+	imgui.Separator()
+}
+
+func (view *TilesView) renderColorSchemeCombo() {
+	if imgui.BeginCombo("Color Scheme", view.colorSchemes[view.model.colorSchemeIndex].Name) {
+		for index, scheme := range view.colorSchemes {
+			if imgui.SelectableV(scheme.Name, index == view.model.colorSchemeIndex, 0, imgui.Vec2{}) {
+				view.model.colorSchemeIndex = index
+			}
+		}
+		imgui.EndCombo()
+	}
+	if view.model.colorSchemeIndex == view.customColorSchemeIndex() {
+		view.renderCustomColorScheme()
+	}
+}
+
+// renderCustomColorScheme renders the controls for the colors of the custom scheme.
+// The scheme is only created anew when one of its colors changes.
+func (view *TilesView) renderCustomColorScheme() {
+	if !imgui.TreeNode("Custom Colors") {
+		return
+	}
+	changed := false
+	components := []string{"Red", "Green", "Blue", "Opacity"}
+	for index, component := range components {
+		changed = imgui.SliderFloat("Bright "+component, &view.model.customBright[index], 0.0, 1.0) || changed
+	}
+	for index, component := range components {
+		changed = imgui.SliderFloat("Dark "+component, &view.model.customDark[index], 0.0, 1.0) || changed
+	}
+	changed = imgui.SliderFloat("Cyberspace Opacity", &view.model.customCyberOpaque, 0.0, 1.0) || changed
+	if changed {
+		view.colorSchemes[view.customColorSchemeIndex()] = view.customColorScheme()
+	}
+	imgui.TreePop()
+}
+
+func (view *TilesView) renderGridOverlay() {
+	if !imgui.TreeNode("Grid Overlay") {
+		return
+	}
+	settings := &view.model.gridOverlay
+	imgui.Checkbox("Tile Lines", &settings.Tiles)
+	imgui.Checkbox("Sub-Tile Lines", &settings.SubTiles)
+	if imgui.BeginCombo("Sub-Tile Divisions", fmt.Sprintf("%d", settings.SubTileDivisions)) {
+		for _, divisions := range GridOverlaySubTileDivisions() {
+			if imgui.SelectableV(fmt.Sprintf("%d", divisions), divisions == settings.SubTileDivisions, 0, imgui.Vec2{}) {
+				settings.SubTileDivisions = divisions
+			}
+		}
+		imgui.EndCombo()
+	}
+	gui.StepSliderInt("Grid Red", &settings.Red, 0, 255)
+	gui.StepSliderInt("Grid Green", &settings.Green, 0, 255)
+	gui.StepSliderInt("Grid Blue", &settings.Blue, 0, 255)
+	gui.StepSliderIntV("Grid Opacity", &settings.Opacity, 0, 100, "%d%%")
+	imgui.TreePop()
+}
+
 func (view *TilesView) renderTextureSelector(readOnly, multiple bool, label string, unifier values.Unifier,
 	atlas level.TextureAtlas, minIndex, maxIndex int, changeHandler func(int)) {
 	selectedIndex := -1
@@ -457,6 +700,14 @@ func (view *TilesView) requestMusicIndex(lvl *level.Level, positions []MapPositi
 	})
 }
 
+func (view *TilesView) floorTextureTargets(lvl *level.Level) []MapPosition {
+	selected := view.model.selectedTiles.list
+	if !view.model.floodFillFloor || (len(selected) != 1) {
+		return selected
+	}
+	return tilesForFloorTextureFill(lvl, selected[0], view.model.floodFillStopAtHeights).list
+}
+
 func (view *TilesView) requestFloorTextureIndex(lvl *level.Level, positions []MapPosition, value int) {
 	view.changeTiles(lvl, positions, func(tile *level.TileMapEntry) {
 		tile.TextureInfo = tile.TextureInfo.WithFloorTextureIndex(value)
@@ -464,8 +715,8 @@ func (view *TilesView) requestFloorTextureIndex(lvl *level.Level, positions []Ma
 }
 
 func (view *TilesView) requestFloorTextureRotations(lvl *level.Level, positions []MapPosition, value int) {
-	view.changeTiles(lvl, positions, func(tile *level.TileMapEntry) {
-		tile.Floor = tile.Floor.WithTextureRotations(value)
+	view.changeTextureOrientation(lvl, positions, func(orientation *level.TextureOrientation) {
+		orientation.FloorRotations = value
 	})
 }
 
@@ -476,8 +727,8 @@ func (view *TilesView) requestCeilingTextureIndex(lvl *level.Level, positions []
 }
 
 func (view *TilesView) requestCeilingTextureRotations(lvl *level.Level, positions []MapPosition, value int) {
-	view.changeTiles(lvl, positions, func(tile *level.TileMapEntry) {
-		tile.Ceiling = tile.Ceiling.WithTextureRotations(value)
+	view.changeTextureOrientation(lvl, positions, func(orientation *level.TextureOrientation) {
+		orientation.CeilingRotations = value
 	})
 }
 
@@ -487,6 +738,21 @@ func (view *TilesView) requestWallTextureIndex(lvl *level.Level, positions []Map
 	})
 }
 
+// requestWallTexture sets the wall texture index together with its style on all given tiles with one command.
+// The request is rejected as a whole if the combination can not be used in the level.
+func (view *TilesView) requestWallTexture(lvl *level.Level, positions []MapPosition, index int, style level.WallTextureStyle) {
+	err := level.ValidateWallTexture(index, style, len(lvl.TextureAtlas()), lvl.IsCyberspace())
+	if err != nil {
+		view.model.wallTextureError = err.Error()
+		return
+	}
+	view.model.wallTextureError = ""
+	view.changeTiles(lvl, positions, func(tile *level.TileMapEntry) {
+		tile.TextureInfo = tile.TextureInfo.WithWallTextureIndex(index)
+		style.ApplyTo(tile)
+	})
+}
+
 func (view *TilesView) requestWallTextureOffset(lvl *level.Level, positions []MapPosition, value level.TileHeightUnit) {
 	view.changeTiles(lvl, positions, func(tile *level.TileMapEntry) {
 		tile.Flags = tile.Flags.ForRealWorld().WithWallTextureOffset(value).AsTileFlag()
@@ -500,8 +766,59 @@ func (view *TilesView) requestUseAdjacentWallTexture(lvl *level.Level, positions
 }
 
 func (view *TilesView) requestWallTexturePattern(lvl *level.Level, positions []MapPosition, value level.WallTexturePattern) {
+	view.changeTextureOrientation(lvl, positions, func(orientation *level.TextureOrientation) {
+		orientation.WallPattern = value
+	})
+}
+
+func (view *TilesView) requestTextureRotation(lvl *level.Level, positions []MapPosition, steps int) {
+	view.changeTextureOrientation(lvl, positions, func(orientation *level.TextureOrientation) {
+		*orientation = orientation.Rotated(steps)
+	})
+}
+
+func (view *TilesView) requestWallTextureFlip(lvl *level.Level, positions []MapPosition) {
+	view.changeTextureOrientation(lvl, positions, func(orientation *level.TextureOrientation) {
+		orientation.WallPattern = orientation.WallPattern.Flipped()
+	})
+}
+
+// requestTileFlags sets or clears the given flags on all given tiles with one command.
+// The request is rejected as a whole if the flags can not be applied together in the level.
+func (view *TilesView) requestTileFlags(lvl *level.Level, positions []MapPosition, flags []level.EditableTileFlag, set bool) {
+	err := level.ValidateEditableTileFlags(flags, lvl.IsCyberspace())
+	if err != nil {
+		view.model.bulkFlagError = err.Error()
+		return
+	}
+	view.model.bulkFlagError = ""
+	view.changeTiles(lvl, positions, func(tile *level.TileMapEntry) {
+		for _, flag := range flags {
+			tile.Flags = flag.AppliedTo(tile.Flags, set)
+		}
+	})
+}
+
+// changeTextureOrientation modifies the texture orientation of all given tiles with one command.
+// The request is rejected as a whole if any of the resulting orientations is not supported.
+func (view *TilesView) changeTextureOrientation(lvl *level.Level, positions []MapPosition, modifier func(*level.TextureOrientation)) {
+	isCyberspace := lvl.IsCyberspace()
+	orientations := make([]level.TextureOrientation, len(positions))
+	for index, pos := range positions {
+		orientation := level.TextureOrientationOf(lvl.Tile(int(pos.X.Tile()), int(pos.Y.Tile())))
+		modifier(&orientation)
+		err := orientation.Validate(isCyberspace)
+		if err != nil {
+			view.model.textureOrientationError = err.Error()
+			return
+		}
+		orientations[index] = orientation
+	}
+	view.model.textureOrientationError = ""
+	next := 0
 	view.changeTiles(lvl, positions, func(tile *level.TileMapEntry) {
-		tile.Flags = tile.Flags.ForRealWorld().WithWallTexturePattern(value).AsTileFlag()
+		orientations[next].ApplyTo(tile)
+		next++
 	})
 }
 
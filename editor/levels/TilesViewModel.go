@@ -1,10 +1,30 @@
 package levels
 
+import "github.com/inkyblackness/hacked/ss1/content/archive/level"
+
 type tilesViewModel struct {
-	selectedTiles     tileCoordinates
+	selectedTiles     TileCoordinates
 	textureDisplay    TextureDisplay
 	shadowDisplay     ColorDisplay
 	cyberColorDisplay ColorDisplay
+	colorSchemeIndex  int
+	gridOverlay       GridOverlay
+
+	customBright      [4]float32
+	customDark        [4]float32
+	customCyberOpaque float32
+
+	floodFillFloor         bool
+	floodFillStopAtHeights bool
+
+	bulkFlag      level.EditableTileFlag
+	bulkFlagError string
+
+	wallTextureIndex int
+	wallTextureStyle level.WallTextureStyle
+	wallTextureError string
+
+	textureOrientationError string
 
 	restoreFocus bool
 	windowOpen   bool
@@ -15,5 +35,12 @@ func freshTilesViewModel() tilesViewModel {
 		textureDisplay:    TextureDisplayFloor,
 		shadowDisplay:     ColorDisplayNone,
 		cyberColorDisplay: ColorDisplayNone,
+		gridOverlay:       DefaultGridOverlay(),
+
+		customBright:      [4]float32{0.0, 0.0, 0.0, 0.0},
+		customDark:        [4]float32{0.0, 0.0, 0.0, 1.0},
+		customCyberOpaque: 0.8,
+
+		floodFillStopAtHeights: true,
 	}
 }
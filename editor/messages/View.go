@@ -17,6 +17,7 @@ import (
 	"github.com/inkyblackness/hacked/ss1/world"
 	"github.com/inkyblackness/hacked/ss1/world/ids"
 	"github.com/inkyblackness/hacked/ui/gui"
+	"github.com/inkyblackness/hacked/ui/opengl"
 )
 
 type messageInfo struct {
@@ -42,37 +43,45 @@ var knownMailTypes = []string{
 
 const videoMailDisplayBase = 256
 
+// Sizes of the message display, which shows the text between the two side images.
+const (
+	sideImageWidth = 250
+	displayHeight  = 190
+)
+
 // View provides edit controls for messages.
 type View struct {
 	mod          *world.Mod
 	messageCache *text.ElectronicMessageCache
-	cp           text.Codepage
+	codepages    *text.Codepages
 	movieCache   *movie.Cache
 	imageCache   *graphics.TextureCache
 
 	modalStateMachine gui.ModalStateMachine
 	clipboard         external.Clipboard
 	guiScale          float32
+	focus             render.FocusArea
 	commander         cmd.Commander
 
 	model viewModel
 }
 
 // NewMessagesView returns a new instance.
-func NewMessagesView(mod *world.Mod, messageCache *text.ElectronicMessageCache, cp text.Codepage,
+func NewMessagesView(mod *world.Mod, messageCache *text.ElectronicMessageCache, codepages *text.Codepages,
 	movieCache *movie.Cache, imageCache *graphics.TextureCache,
 	modalStateMachine gui.ModalStateMachine, clipboard external.Clipboard,
-	guiScale float32, commander cmd.Commander) *View {
+	guiScale float32, focus render.FocusArea, commander cmd.Commander) *View {
 	view := &View{
 		mod:          mod,
 		messageCache: messageCache,
-		cp:           cp,
+		codepages:    codepages,
 		movieCache:   movieCache,
 		imageCache:   imageCache,
 
 		modalStateMachine: modalStateMachine,
 		clipboard:         clipboard,
 		guiScale:          guiScale,
+		focus:             focus,
 		commander:         commander,
 
 		model: freshViewModel(),
@@ -93,17 +102,18 @@ func (view *View) Render() {
 		view.model.windowOpen = true
 	}
 	if view.model.windowOpen {
-		imgui.SetNextWindowSizeV(imgui.Vec2{X: 640 * view.guiScale, Y: 480 * view.guiScale}, imgui.ConditionOnce)
+		imgui.SetNextWindowSizeV(render.LayoutMetricsFor(view.guiScale).MediumWindowSize(), imgui.ConditionOnce)
 		if imgui.BeginV("Messages", view.WindowOpen(), imgui.WindowFlagsNoCollapse) {
-			view.renderContent()
+			view.focus.Render(func() { view.renderContent() })
 		}
 		imgui.End()
 	}
 }
 
 func (view *View) renderContent() {
-	if imgui.BeginChildV("Properties", imgui.Vec2{X: 350 * view.guiScale, Y: -200 * view.guiScale}, false, 0) {
-		imgui.PushItemWidth(-150 * view.guiScale)
+	metrics := render.LayoutMetricsFor(view.guiScale)
+	if imgui.BeginChildV("Properties", imgui.Vec2{X: metrics.PropertiesWidth(), Y: -metrics.Scaled(displayHeight + 10)}, false, 0) {
+		imgui.PushItemWidth(metrics.LabelSpace())
 		if imgui.BeginCombo("Message Type", knownMessageTypes[view.model.currentKey.ID].title) {
 			for _, id := range knownMessageTypesOrder {
 				if imgui.SelectableV(knownMessageTypes[id].title, id == view.model.currentKey.ID, 0, imgui.Vec2{}) {
@@ -253,7 +263,7 @@ func (view *View) renderMFDs() {
 	view.renderSideImage("LeftMFD", message.LeftDisplay)
 	imgui.SameLineV(0, 0)
 	textToDisplay := message.VerboseText
-	if imgui.BeginChildV("Text", imgui.Vec2{X: -250 * view.guiScale, Y: 190 * view.guiScale}, true, 0) {
+	if imgui.BeginChildV("Text", render.LayoutMetricsFor(view.guiScale).Vec2(-sideImageWidth, displayHeight), true, 0) {
 		if !view.model.showVerboseText {
 			textToDisplay = message.TerseText
 		}
@@ -328,7 +338,8 @@ func (view *View) clipboardPopup(readOnly bool, label string, value string, chan
 		if imgui.Selectable("Copy to Clipboard") {
 			view.clipboard.SetString(value)
 		}
-		if !readOnly && imgui.Selectable("Copy from Clipboard") {
+		pasteAvailable := view.clipboard.Content() == opengl.ClipboardContentText
+		if !readOnly && imgui.MenuItemV("Copy from Clipboard", "", false, pasteAvailable) {
 			newValue, err := view.clipboard.String()
 			if err == nil {
 				changeCallback(newValue)
@@ -357,7 +368,7 @@ func (view *View) renderDisplayGallery(readOnly bool, label string, index, avail
 }
 
 func (view *View) renderSideImage(label string, index int) {
-	size := imgui.Vec2{X: 250 * view.guiScale, Y: 190 * view.guiScale}
+	size := render.LayoutMetricsFor(view.guiScale).Vec2(sideImageWidth, displayHeight)
 	imgui.PushStyleVarVec2(imgui.StyleVarWindowPadding, imgui.Vec2{X: size.X, Y: -size.Y})
 	if imgui.BeginChildV(label, size, false, 0) {
 		if index >= 0 {
@@ -385,21 +396,23 @@ func (view *View) requestImportAudio() {
 }
 
 func (view *View) requestClear() {
-	view.requestWipe(text.EmptyElectronicMessage().Encode(view.cp))
+	view.requestWipe(func(lang resource.Language) [][]byte {
+		return text.EmptyElectronicMessage().Encode(view.codepages.ForLanguage(lang))
+	})
 }
 
 func (view *View) requestRemove() {
-	view.requestWipe(nil)
+	view.requestWipe(func(resource.Language) [][]byte { return nil })
 }
 
-func (view *View) requestWipe(newTextData [][]byte) {
+func (view *View) requestWipe(newTextData func(resource.Language) [][]byte) {
 	textEntries := make(map[resource.Language]messageDataEntry)
 	audioEntries := make(map[resource.Language]messageDataEntry)
 	textID := view.model.currentKey.ID.Plus(view.model.currentKey.Index)
 	for lang := resource.Language(0); lang < resource.LanguageCount; lang++ {
 		textEntries[lang] = messageDataEntry{
 			oldData: view.mod.ModifiedBlocks(lang, textID),
-			newData: newTextData,
+			newData: newTextData(lang),
 		}
 		if view.hasAudio() {
 			audioEntries[lang] = messageDataEntry{
@@ -418,7 +431,7 @@ func (view *View) requestTextChange(modifier func(*text.ElectronicMessage)) {
 
 	entries[view.model.currentKey.Lang] = messageDataEntry{
 		oldData: view.mod.ModifiedBlocks(view.model.currentKey.Lang, view.model.currentKey.ID.Plus(view.model.currentKey.Index)),
-		newData: msg.Encode(view.cp),
+		newData: msg.Encode(view.codepages.ForLanguage(view.model.currentKey.Lang)),
 	}
 	view.requestSetMessageData(entries, nil)
 }
@@ -433,7 +446,7 @@ func (view *View) requestPropertyChange(modifier func(*text.ElectronicMessage))
 
 		entries[lang] = messageDataEntry{
 			oldData: view.mod.ModifiedBlocks(lang, key.ID.Plus(key.Index)),
-			newData: msg.Encode(view.cp),
+			newData: msg.Encode(view.codepages.ForLanguage(lang)),
 		}
 	}
 	view.requestSetMessageData(entries, nil)
@@ -0,0 +1,126 @@
+package objects
+
+import (
+	"fmt"
+
+	"github.com/inkyblackness/hacked/ss1/content/object"
+)
+
+// commonPropertyField describes a field of the common properties that can be handled in bulk.
+type commonPropertyField struct {
+	name string
+	// value returns a comparable representation of the field.
+	value func(common object.CommonProperties) interface{}
+	// copy sets the field of target to the one of source.
+	copy func(target *object.CommonProperties, source object.CommonProperties)
+}
+
+func (field commonPropertyField) describe(common object.CommonProperties) string {
+	return fmt.Sprintf("%v", field.value(common))
+}
+
+var commonPropertyFields = []commonPropertyField{
+	{
+		name:  "Mass",
+		value: func(common object.CommonProperties) interface{} { return common.Mass },
+		copy:  func(target *object.CommonProperties, source object.CommonProperties) { target.Mass = source.Mass },
+	},
+	{
+		name:  "Hitpoints",
+		value: func(common object.CommonProperties) interface{} { return common.Hitpoints },
+		copy: func(target *object.CommonProperties, source object.CommonProperties) {
+			target.Hitpoints = source.Hitpoints
+		},
+	},
+	{
+		name:  "Armor",
+		value: func(common object.CommonProperties) interface{} { return common.Armor },
+		copy:  func(target *object.CommonProperties, source object.CommonProperties) { target.Armor = source.Armor },
+	},
+	{
+		name:  "Render Type",
+		value: func(common object.CommonProperties) interface{} { return common.RenderType },
+		copy: func(target *object.CommonProperties, source object.CommonProperties) {
+			target.RenderType = source.RenderType
+		},
+	},
+	{
+		name:  "Physics Model",
+		value: func(common object.CommonProperties) interface{} { return common.PhysicsModel },
+		copy: func(target *object.CommonProperties, source object.CommonProperties) {
+			target.PhysicsModel = source.PhysicsModel
+		},
+	},
+	{
+		name:  "Hardness",
+		value: func(common object.CommonProperties) interface{} { return common.Hardness },
+		copy: func(target *object.CommonProperties, source object.CommonProperties) {
+			target.Hardness = source.Hardness
+		},
+	},
+	{
+		name: "Physics Size",
+		value: func(common object.CommonProperties) interface{} {
+			return fmt.Sprintf("XR: %d, Z: %d", common.PhysicsXR, common.PhysicsZ)
+		},
+		copy: func(target *object.CommonProperties, source object.CommonProperties) {
+			target.PhysicsXR = source.PhysicsXR
+			target.PhysicsZ = source.PhysicsZ
+		},
+	},
+	{
+		name: "Vulnerabilities",
+		value: func(common object.CommonProperties) interface{} {
+			return fmt.Sprintf("0x%02X, special: 0x%02X", int(common.Vulnerabilities), int(common.SpecialVulnerabilities))
+		},
+		copy: func(target *object.CommonProperties, source object.CommonProperties) {
+			target.Vulnerabilities = source.Vulnerabilities
+			target.SpecialVulnerabilities = source.SpecialVulnerabilities
+		},
+	},
+	{
+		name:  "Defense",
+		value: func(common object.CommonProperties) interface{} { return common.Defense },
+		copy:  func(target *object.CommonProperties, source object.CommonProperties) { target.Defense = source.Defense },
+	},
+	{
+		name:  "Toughness",
+		value: func(common object.CommonProperties) interface{} { return common.Toughness },
+		copy: func(target *object.CommonProperties, source object.CommonProperties) {
+			target.Toughness = source.Toughness
+		},
+	},
+	{
+		name:  "Flags",
+		value: func(common object.CommonProperties) interface{} { return fmt.Sprintf("0x%04X", int(common.Flags)) },
+		copy:  func(target *object.CommonProperties, source object.CommonProperties) { target.Flags = source.Flags },
+	},
+	{
+		name:  "MFD or Mesh ID",
+		value: func(common object.CommonProperties) interface{} { return common.MfdOrMeshID },
+		copy: func(target *object.CommonProperties, source object.CommonProperties) {
+			target.MfdOrMeshID = source.MfdOrMeshID
+		},
+	},
+	{
+		name: "Bitmap 3D",
+		value: func(common object.CommonProperties) interface{} {
+			bmp := common.Bitmap3D
+			return fmt.Sprintf("Bitmap %d, frames: %d, animation: %v, repeat: %v",
+				bmp.BitmapNumber(), bmp.FrameNumber(), bmp.Animation(), bmp.Repeat())
+		},
+		copy: func(target *object.CommonProperties, source object.CommonProperties) {
+			target.Bitmap3D = source.Bitmap3D
+		},
+	},
+	{
+		name: "Destroy Effect",
+		value: func(common object.CommonProperties) interface{} {
+			return fmt.Sprintf("%d (sound: %v, explosion: %v)", common.DestroyEffect.Value(),
+				common.DestroyEffect.PlaySound(), common.DestroyEffect.ShowExplosion())
+		},
+		copy: func(target *object.CommonProperties, source object.CommonProperties) {
+			target.DestroyEffect = source.DestroyEffect
+		},
+	},
+}
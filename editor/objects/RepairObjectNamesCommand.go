@@ -0,0 +1,65 @@
+package objects
+
+import (
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/world"
+)
+
+type objectNameTableKey struct {
+	lang resource.Language
+	id   resource.ID
+}
+
+type objectNameTableChange struct {
+	oldBlocks [][]byte
+	newBlocks [][]byte
+}
+
+type repairObjectNamesCommand struct {
+	model *viewModel
+
+	changes map[objectNameTableKey]*objectNameTableChange
+}
+
+func (command repairObjectNamesCommand) Do(modder world.Modder) error {
+	for key, change := range command.changes {
+		modder.SetResourceBlocks(key.lang, key.id, change.newBlocks)
+	}
+	command.model.restoreFocus = true
+	return nil
+}
+
+func (command repairObjectNamesCommand) Undo(modder world.Modder) error {
+	for key, change := range command.changes {
+		if change.oldBlocks == nil {
+			modder.DelResource(key.lang, key.id)
+		} else {
+			modder.SetResourceBlocks(key.lang, key.id, change.oldBlocks)
+		}
+	}
+	command.model.restoreFocus = true
+	return nil
+}
+
+// objectNameTableCollector records block changes on top of the currently modified blocks of a mod.
+type objectNameTableCollector struct {
+	mod     *world.Mod
+	changes map[objectNameTableKey]*objectNameTableChange
+}
+
+func (collector objectNameTableCollector) SetResourceBlock(lang resource.Language, id resource.ID, index int, data []byte) {
+	key := objectNameTableKey{lang: lang, id: id}
+	change, existing := collector.changes[key]
+	if !existing {
+		oldBlocks := collector.mod.ModifiedBlocks(lang, id)
+		change = &objectNameTableChange{
+			oldBlocks: oldBlocks,
+			newBlocks: append([][]byte{}, oldBlocks...),
+		}
+		collector.changes[key] = change
+	}
+	for len(change.newBlocks) <= index {
+		change.newBlocks = append(change.newBlocks, nil)
+	}
+	change.newBlocks[index] = data
+}
@@ -0,0 +1,55 @@
+package objects
+
+import (
+	"github.com/inkyblackness/hacked/ss1/content/object"
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/world"
+)
+
+type objectPropertiesChange struct {
+	triple        object.Triple
+	oldProperties object.Properties
+	newProperties object.Properties
+}
+
+type objectNameChange struct {
+	key     resource.Key
+	oldData []byte
+	newData []byte
+}
+
+// setMultipleObjectsCommand changes the properties and names of several objects at once.
+type setMultipleObjectsCommand struct {
+	model *viewModel
+
+	properties []objectPropertiesChange
+	names      []objectNameChange
+}
+
+func (command setMultipleObjectsCommand) isEmpty() bool {
+	return (len(command.properties) == 0) && (len(command.names) == 0)
+}
+
+func (command setMultipleObjectsCommand) Do(modder world.Modder) error {
+	for _, entry := range command.properties {
+		modder.SetObjectProperties(entry.triple, entry.newProperties)
+	}
+	for _, entry := range command.names {
+		modder.SetResourceBlock(entry.key.Lang, entry.key.ID, entry.key.Index, entry.newData)
+	}
+	command.model.restoreFocus = true
+	return nil
+}
+
+func (command setMultipleObjectsCommand) Undo(modder world.Modder) error {
+	for index := len(command.names) - 1; index >= 0; index-- {
+		entry := command.names[index]
+		modder.SetResourceBlock(entry.key.Lang, entry.key.ID, entry.key.Index, entry.oldData)
+	}
+	for index := len(command.properties) - 1; index >= 0; index-- {
+		entry := command.properties[index]
+		modder.SetObjectProperties(entry.triple, entry.oldProperties)
+	}
+	command.model.restoreFocus = true
+	return nil
+}
@@ -2,6 +2,7 @@ package objects
 
 import (
 	"github.com/inkyblackness/hacked/ss1/content/object"
+	"github.com/inkyblackness/hacked/ss1/edit/undoable/cmd"
 	"github.com/inkyblackness/hacked/ss1/world"
 )
 
@@ -10,6 +11,7 @@ type setObjectPropertiesCommand struct {
 
 	triple object.Triple
 	bitmap int
+	field  string
 
 	oldProperties object.Properties
 	newProperties object.Properties
@@ -23,6 +25,17 @@ func (command setObjectPropertiesCommand) Undo(modder world.Modder) error {
 	return command.perform(modder, command.oldProperties)
 }
 
+// Merge combines consecutive changes of the same field of the same object.
+func (command setObjectPropertiesCommand) Merge(next cmd.Command) (cmd.Command, bool) {
+	other, isSet := next.(setObjectPropertiesCommand)
+	if !isSet || (len(command.field) == 0) || (other.field != command.field) || (other.triple != command.triple) {
+		return nil, false
+	}
+	command.newProperties = other.newProperties
+	command.bitmap = other.bitmap
+	return command, true
+}
+
 func (command setObjectPropertiesCommand) perform(modder world.Modder, properties object.Properties) error {
 	modder.SetObjectProperties(command.triple, properties)
 
@@ -2,12 +2,14 @@ package objects
 
 import (
 	"github.com/inkyblackness/hacked/ss1/content/object"
+	"github.com/inkyblackness/hacked/ss1/content/text"
 	"github.com/inkyblackness/hacked/ss1/resource"
 	"github.com/inkyblackness/hacked/ss1/world"
 )
 
 type setObjectTextCommand struct {
-	model *viewModel
+	model     *viewModel
+	textCache *text.Cache
 
 	triple object.Triple
 	bitmap int
@@ -27,6 +29,7 @@ func (command setObjectTextCommand) Undo(modder world.Modder) error {
 
 func (command setObjectTextCommand) perform(modder world.Modder, data []byte) error {
 	modder.SetResourceBlock(command.key.Lang, command.key.ID, command.key.Index, data)
+	command.textCache.InvalidateKey(command.key)
 	command.model.restoreFocus = true
 	command.model.currentObject = command.triple
 	command.model.currentBitmap = command.bitmap
@@ -1,7 +1,10 @@
 package objects
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/inkyblackness/imgui-go"
@@ -15,44 +18,59 @@ import (
 	"github.com/inkyblackness/hacked/ss1/content/object"
 	"github.com/inkyblackness/hacked/ss1/content/object/objprop"
 	"github.com/inkyblackness/hacked/ss1/content/text"
+	"github.com/inkyblackness/hacked/ss1/edit"
 	"github.com/inkyblackness/hacked/ss1/edit/undoable/cmd"
 	"github.com/inkyblackness/hacked/ss1/resource"
 	"github.com/inkyblackness/hacked/ss1/world"
 	"github.com/inkyblackness/hacked/ss1/world/ids"
+	"github.com/inkyblackness/hacked/ss1/world/integrity"
 	"github.com/inkyblackness/hacked/ui/gui"
+	"github.com/inkyblackness/hacked/ui/opengl"
 )
 
 // View provides edit controls for game objects.
 type View struct {
 	mod          *world.Mod
 	textCache    *text.Cache
-	cp           text.Codepage
+	codepages    *text.Codepages
 	imageCache   *graphics.TextureCache
 	paletteCache *graphics.PaletteCache
 
 	modalStateMachine gui.ModalStateMachine
 	clipboard         external.Clipboard
 	guiScale          float32
+	focus             render.FocusArea
 	commander         cmd.Commander
 
 	model viewModel
+
+	nameDiscrepancies nameDiscrepancyCheck
+}
+
+// nameDiscrepancyCheck keeps the result of comparing the name tables against the object types,
+// as the comparison covers the name tables of all languages.
+type nameDiscrepancyCheck struct {
+	valid         bool
+	typeCount     int
+	discrepancies []edit.ObjectNameDiscrepancy
 }
 
 // NewView returns a new instance.
-func NewView(mod *world.Mod, textCache *text.Cache, cp text.Codepage,
+func NewView(mod *world.Mod, textCache *text.Cache, codepages *text.Codepages,
 	imageCache *graphics.TextureCache, paletteCache *graphics.PaletteCache,
 	modalStateMachine gui.ModalStateMachine,
-	clipboard external.Clipboard, guiScale float32, commander cmd.Commander) *View {
+	clipboard external.Clipboard, guiScale float32, focus render.FocusArea, commander cmd.Commander) *View {
 	view := &View{
 		mod:          mod,
 		textCache:    textCache,
-		cp:           cp,
+		codepages:    codepages,
 		imageCache:   imageCache,
 		paletteCache: paletteCache,
 
 		modalStateMachine: modalStateMachine,
 		clipboard:         clipboard,
 		guiScale:          guiScale,
+		focus:             focus,
 		commander:         commander,
 
 		model: freshViewModel(),
@@ -73,20 +91,28 @@ func (view *View) Render() {
 		view.model.windowOpen = true
 	}
 	if view.model.windowOpen {
-		imgui.SetNextWindowSizeV(imgui.Vec2{X: 800 * view.guiScale, Y: 600 * view.guiScale}, imgui.ConditionOnce)
+		imgui.SetNextWindowSizeV(render.LayoutMetricsFor(view.guiScale).LargeWindowSize(), imgui.ConditionOnce)
 		if imgui.BeginV("Game Objects", view.WindowOpen(), imgui.WindowFlagsNoCollapse|imgui.WindowFlagsHorizontalScrollbar) {
-			view.renderContent()
+			view.focus.Render(func() { view.renderContent() })
 		}
 		imgui.End()
 	}
 }
 
 func (view *View) renderContent() {
-	if imgui.BeginChildV("Properties", imgui.Vec2{X: -330 * view.guiScale, Y: 0}, false, imgui.WindowFlagsHorizontalScrollbar) {
-		imgui.PushItemWidth(-260 * view.guiScale)
+	metrics := render.LayoutMetricsFor(view.guiScale)
+	if imgui.BeginChildV("Properties", imgui.Vec2{X: metrics.PreviewSpace(), Y: 0}, false, imgui.WindowFlagsHorizontalScrollbar) {
+		imgui.PushItemWidth(metrics.PropertyLabelSpace())
 		classString := func(class object.Class) string {
 			return fmt.Sprintf("%2d: %v", int(class), class)
 		}
+		if imgui.InputTextV("Name Search", &view.model.nameSearch, imgui.InputTextFlagsEnterReturnsTrue, nil) {
+			view.findNextObjectByName(view.model.nameSearch)
+		}
+		imgui.SameLine()
+		if imgui.Button("Find Next") {
+			view.findNextObjectByName(view.model.nameSearch)
+		}
 		if imgui.BeginCombo("Object Class", classString(view.model.currentObject.Class)) {
 			for _, class := range object.Classes() {
 				if imgui.SelectableV(classString(class), class == view.model.currentObject.Class, 0, imgui.Vec2{}) {
@@ -96,9 +122,14 @@ func (view *View) renderContent() {
 			}
 			imgui.EndCombo()
 		}
+		imgui.InputText("Type Filter", &view.model.typeFilter)
 		if imgui.BeginCombo("Object Type", view.tripleName(view.model.currentObject)) {
 			allTypes := view.mod.ObjectProperties().TriplesInClass(view.model.currentObject.Class)
+			filter := strings.ToLower(view.model.typeFilter)
 			for _, triple := range allTypes {
+				if (triple != view.model.currentObject) && !strings.Contains(strings.ToLower(view.tripleName(triple)), filter) {
+					continue
+				}
 				if imgui.SelectableV(view.tripleName(triple), triple == view.model.currentObject, 0, imgui.Vec2{}) {
 					view.model.currentObject = triple
 					view.model.currentBitmap = 0
@@ -123,15 +154,7 @@ func (view *View) renderContent() {
 
 		imgui.Separator()
 
-		if imgui.BeginCombo("Language", view.model.currentLang.String()) {
-			languages := resource.Languages()
-			for _, lang := range languages {
-				if imgui.SelectableV(lang.String(), lang == view.model.currentLang, 0, imgui.Vec2{}) {
-					view.model.currentLang = lang
-				}
-			}
-			imgui.EndCombo()
-		}
+		view.renderLanguageCombo()
 		view.renderText(readOnly, "Long Name",
 			view.objectName(view.model.currentObject, view.model.currentLang, true),
 			func(newValue string) {
@@ -142,8 +165,33 @@ func (view *View) renderContent() {
 			func(newValue string) {
 				view.requestSetObjectName(view.model.currentObject, false, newValue)
 			})
+		if len(view.model.nameError) > 0 {
+			imgui.PushStyleColor(imgui.StyleColorText, imgui.Vec4{X: 1.0, Y: 0.0, Z: 0.0, W: 1.0})
+			imgui.Text(view.model.nameError)
+			imgui.PopStyleColor()
+		}
+
+		view.renderNameTableDiscrepancies(readOnly)
+		if imgui.Button("Export All Properties") {
+			view.requestExportPropertySheets()
+		}
+		if !readOnly {
+			imgui.SameLine()
+			if imgui.Button("Import Properties") {
+				view.requestImportPropertySheets()
+			}
+		}
+		view.renderImportIssues()
+		view.renderBulkOperations(readOnly)
 
 		if propErr == nil {
+			imgui.Separator()
+			imgui.PushTextWrapPos()
+			imgui.Text(objprop.Summary(view.model.currentObject, *properties))
+			imgui.PopTextWrapPos()
+			if imgui.IsItemHovered() {
+				imgui.SetTooltip("Summary of the in-game behaviour, derived from the properties.")
+			}
 			if imgui.TreeNodeV("Common Properties", imgui.TreeNodeFlagsDefaultOpen|imgui.TreeNodeFlagsFramed) {
 				view.renderCommonProperties(readOnly, properties)
 				imgui.TreePop()
@@ -170,6 +218,305 @@ func (view *View) renderContent() {
 	imgui.EndGroup()
 }
 
+// InvalidateResources drops any results derived from the data of given resources.
+func (view *View) InvalidateResources(modifiedIDs []resource.ID) {
+	for _, id := range modifiedIDs {
+		if (id == ids.ObjectLongNames) || (id == ids.ObjectShortNames) {
+			view.nameDiscrepancies.valid = false
+			return
+		}
+	}
+}
+
+func (view *View) nameTableDiscrepancies() []edit.ObjectNameDiscrepancy {
+	table := view.mod.ObjectProperties()
+	typeCount := 0
+	for _, class := range table {
+		for _, subclass := range class {
+			typeCount += len(subclass)
+		}
+	}
+	check := &view.nameDiscrepancies
+	if !check.valid || (check.typeCount != typeCount) {
+		*check = nameDiscrepancyCheck{
+			valid:         true,
+			typeCount:     typeCount,
+			discrepancies: edit.ObjectNameDiscrepancies(table, view.mod),
+		}
+	}
+	return check.discrepancies
+}
+
+func (view *View) renderNameTableDiscrepancies(readOnly bool) {
+	discrepancies := view.nameTableDiscrepancies()
+	if len(discrepancies) == 0 {
+		return
+	}
+	if imgui.TreeNodeV("Name Table Discrepancies", imgui.TreeNodeFlagsFramed) {
+		for _, discrepancy := range discrepancies {
+			imgui.Text(discrepancy.String())
+		}
+		if !readOnly && imgui.Button("Add Missing Names") {
+			view.requestRepairObjectNames(discrepancies)
+		}
+		imgui.TreePop()
+	}
+}
+
+func (view *View) requestRepairObjectNames(discrepancies []edit.ObjectNameDiscrepancy) {
+	collector := objectNameTableCollector{
+		mod:     view.mod,
+		changes: make(map[objectNameTableKey]*objectNameTableChange),
+	}
+	edit.RepairObjectNames(collector, view.codepages, view.mod.ObjectProperties(), discrepancies)
+	if len(collector.changes) > 0 {
+		view.commander.Queue(repairObjectNamesCommand{
+			model:   &view.model,
+			changes: collector.changes,
+		})
+	}
+}
+
+func (view *View) requestExportPropertySheets() {
+	filename := "objprop.json"
+	info := "File to be written: " + filename
+	var exportTo func(string)
+
+	exportTo = func(dirname string) {
+		writer, err := os.Create(filepath.Join(dirname, filename))
+		if err != nil {
+			external.Export(view.modalStateMachine, "Could not create file.\n"+info, exportTo, true)
+			return
+		}
+		defer func() { _ = writer.Close() }()
+		sheets := edit.ObjectPropertySheetsFrom(view.mod.ObjectProperties(), view.textCache)
+		err = sheets.Encode(writer)
+		if err != nil {
+			external.Export(view.modalStateMachine, info, exportTo, true)
+		}
+	}
+
+	external.Export(view.modalStateMachine, info, exportTo, false)
+}
+
+func (view *View) requestImportPropertySheets() {
+	info := "File must be a JSON file, as created by the export."
+	types := []external.TypeInfo{{Title: "Object properties (*.json)", Extensions: []string{"json"}}}
+	fileHandler := func(filename string) error {
+		reader, err := os.Open(filename)
+		if err != nil {
+			return errors.New("could not open file")
+		}
+		defer func() { _ = reader.Close() }()
+		sheets, err := edit.DecodeObjectPropertySheets(reader)
+		if err != nil {
+			return errors.New("could not read file")
+		}
+		view.applyPropertySheets(sheets)
+		return nil
+	}
+
+	external.Import(view.modalStateMachine, info, types, fileHandler)
+}
+
+func (view *View) applyPropertySheets(sheets edit.ObjectPropertySheets) {
+	if !view.mod.HasModifyableObjectProperties() {
+		view.model.importIssues = []string{"Object properties are not modifiable in this project."}
+		return
+	}
+	changes, issues := sheets.Changes(view.mod.ObjectProperties(), view.textCache)
+	view.model.importIssues = nil
+	for _, issue := range issues {
+		view.model.importIssues = append(view.model.importIssues, issue.String())
+	}
+	command := setMultipleObjectsCommand{model: &view.model}
+	for _, change := range changes {
+		if change.PropertiesChanged() {
+			command.properties = append(command.properties, objectPropertiesChange{
+				triple:        change.Triple,
+				oldProperties: change.OldProperties,
+				newProperties: change.NewProperties,
+			})
+		}
+		for _, name := range change.Names {
+			// Names that did not exist before are cleared again on undo.
+			var oldData []byte
+			if name.OldExisting {
+				oldData = view.mod.ModifiedBlock(name.Key.Lang, name.Key.ID, name.Key.Index)
+			}
+			command.names = append(command.names, objectNameChange{
+				key:     name.Key,
+				oldData: oldData,
+				newData: view.codepages.ForLanguage(name.Key.Lang).Encode(text.Blocked(name.NewName)[0]),
+			})
+		}
+	}
+	if !command.isEmpty() {
+		view.commander.Queue(command)
+	}
+}
+
+func (view *View) renderImportIssues() {
+	if len(view.model.importIssues) == 0 {
+		return
+	}
+	if imgui.TreeNodeV("Import Issues", imgui.TreeNodeFlagsFramed) {
+		for _, issue := range view.model.importIssues {
+			imgui.Text(issue)
+		}
+		if imgui.Button("Clear") {
+			view.model.importIssues = nil
+		}
+		imgui.TreePop()
+	}
+}
+
+func (view *View) renderBulkOperations(readOnly bool) {
+	current := view.model.currentObject
+	title := fmt.Sprintf("Bulk Operations (%d selected)###Bulk Operations", len(view.model.selectedObjects))
+	if !imgui.TreeNodeV(title, imgui.TreeNodeFlagsFramed) {
+		return
+	}
+	selectLabel := "Add Current to Selection"
+	if view.model.isSelected(current) {
+		selectLabel = "Remove Current from Selection"
+	}
+	if imgui.Button(selectLabel) {
+		view.model.toggleSelection(current)
+	}
+	imgui.SameLine()
+	if imgui.Button("Clear Selection") {
+		view.model.selectedObjects = nil
+	}
+	for _, triple := range view.model.selectedObjects {
+		if imgui.SelectableV(view.tripleName(triple), triple == current, 0, imgui.Vec2{}) {
+			view.model.currentObject = triple
+			view.model.currentBitmap = 0
+		}
+	}
+	if len(view.model.selectedObjects) > 0 {
+		imgui.Separator()
+		table := view.mod.ObjectProperties()
+		for _, field := range commonPropertyFields {
+			unifier := values.NewUnifier()
+			for _, triple := range view.model.selectedObjects {
+				if prop, err := table.ForObject(triple); err == nil {
+					unifier.Add(field.value(prop.Common))
+				}
+			}
+			valueText := "(mixed)"
+			if unifier.IsUnique() {
+				valueText = fmt.Sprintf("%v", unifier.Unified())
+			}
+			imgui.LabelText(field.name, valueText)
+		}
+	}
+	if !readOnly && (len(view.model.selectedObjects) > 0) {
+		imgui.Separator()
+		if imgui.BeginCombo("Field", commonPropertyFields[view.model.bulkFieldIndex].name) {
+			for index, field := range commonPropertyFields {
+				if imgui.SelectableV(field.name, index == view.model.bulkFieldIndex, 0, imgui.Vec2{}) {
+					view.model.bulkFieldIndex = index
+				}
+			}
+			imgui.EndCombo()
+		}
+		if imgui.Button("Copy Field from Current to Selected") {
+			view.requestCopyFieldToSelected(commonPropertyFields[view.model.bulkFieldIndex])
+		}
+		if imgui.Button("Clear Names of Selected") {
+			view.requestClearNamesOfSelected()
+		}
+		if imgui.IsItemHovered() {
+			imgui.SetTooltip("Clears long and short names in the current language.")
+		}
+	}
+	imgui.TreePop()
+}
+
+func (view *View) requestCopyFieldToSelected(field commonPropertyField) {
+	table := view.mod.ObjectProperties()
+	source, err := table.ForObject(view.model.currentObject)
+	if err != nil {
+		return
+	}
+	command := setMultipleObjectsCommand{model: &view.model}
+	for _, triple := range view.model.selectedObjects {
+		prop, err := table.ForObject(triple)
+		if err != nil {
+			continue
+		}
+		change := objectPropertiesChange{
+			triple:        triple,
+			oldProperties: prop.Clone(),
+			newProperties: prop.Clone(),
+		}
+		field.copy(&change.newProperties.Common, source.Common)
+		if change.newProperties.Common != change.oldProperties.Common {
+			command.properties = append(command.properties, change)
+		}
+	}
+	if !command.isEmpty() {
+		view.commander.Queue(command)
+	}
+}
+
+func (view *View) requestClearNamesOfSelected() {
+	table := view.mod.ObjectProperties()
+	command := setMultipleObjectsCommand{model: &view.model}
+	for _, triple := range view.model.selectedObjects {
+		for _, longName := range []bool{true, false} {
+			key, known := edit.ObjectNameKey(table, triple, view.model.currentLang, longName)
+			if !known {
+				continue
+			}
+			oldValue, err := view.textCache.Text(key)
+			if (err != nil) || (len(oldValue) == 0) {
+				continue
+			}
+			command.names = append(command.names, objectNameChange{
+				key:     key,
+				oldData: view.codepages.ForLanguage(key.Lang).Encode(oldValue),
+				newData: view.codepages.ForLanguage(key.Lang).Encode(text.Blocked("")[0]),
+			})
+		}
+	}
+	if !command.isEmpty() {
+		view.commander.Queue(command)
+	}
+}
+
+// renderLanguageCombo renders the selection of the language for names.
+// Languages without name tables are marked, yet remain selectable.
+func (view *View) renderLanguageCombo() {
+	label := func(lang resource.Language, available bool) string {
+		if !available {
+			return lang.String() + " (no names loaded)"
+		}
+		return lang.String()
+	}
+	currentAvailable := edit.ObjectNamesAvailable(view.mod, view.model.currentLang)
+	if imgui.BeginCombo("Language", label(view.model.currentLang, currentAvailable)) {
+		for _, lang := range resource.Languages() {
+			available := edit.ObjectNamesAvailable(view.mod, lang)
+			if !available {
+				imgui.PushStyleColor(imgui.StyleColorText, imgui.Vec4{X: 0.6, Y: 0.6, Z: 0.6, W: 1.0})
+			}
+			if imgui.SelectableV(label(lang, available), lang == view.model.currentLang, 0, imgui.Vec2{}) {
+				view.model.currentLang = lang
+			}
+			if !available {
+				imgui.PopStyleColor()
+				if imgui.IsItemHovered() {
+					imgui.SetTooltip("No name tables are loaded for this language.\n" +
+						"Changing a name creates the tables in the mod.")
+				}
+			}
+		}
+		imgui.EndCombo()
+	}
+}
+
 func (view *View) renderText(readOnly bool, label string, value string, changeCallback func(string)) {
 	imgui.LabelText(label, value)
 	view.clipboardPopup(readOnly, label, value, changeCallback)
@@ -180,20 +527,55 @@ func (view *View) tripleName(triple object.Triple) string {
 }
 
 func (view *View) objectName(triple object.Triple, lang resource.Language, longName bool) string {
-	result := "???"
-	linearIndex := view.mod.ObjectProperties().TripleIndex(triple)
-	if linearIndex >= 0 {
-		nameID := ids.ObjectShortNames
-		if longName {
-			nameID = ids.ObjectLongNames
+	objName, known := view.knownObjectName(triple, lang, longName)
+	if !known {
+		return "???"
+	}
+	return objName
+}
+
+func (view *View) knownObjectName(triple object.Triple, lang resource.Language, longName bool) (string, bool) {
+	key, known := edit.ObjectNameKey(view.mod.ObjectProperties(), triple, lang, longName)
+	if !known {
+		return "", false
+	}
+	objName, err := view.textCache.Text(key)
+	return objName, err == nil
+}
+
+// findNextObjectByName selects the next object after the current one that has the given text
+// in its long or short name. The search wraps around, so repeated searches cycle through all matches.
+func (view *View) findNextObjectByName(search string) {
+	filter := strings.ToLower(search)
+	if len(filter) == 0 {
+		return
+	}
+	triples := view.mod.ObjectProperties().Triples()
+	start := 0
+	for index, triple := range triples {
+		if triple == view.model.currentObject {
+			start = index + 1
 		}
-		key := resource.KeyOf(nameID, lang, linearIndex)
-		objName, err := view.textCache.Text(key)
-		if err == nil {
-			result = objName
+	}
+	for offset := 0; offset < len(triples); offset++ {
+		triple := triples[(start+offset)%len(triples)]
+		if strings.Contains(strings.ToLower(view.localizedObjectName(triple, true)), filter) ||
+			strings.Contains(strings.ToLower(view.localizedObjectName(triple, false)), filter) {
+			view.model.currentObject = triple
+			view.model.currentBitmap = 0
+			return
 		}
 	}
-	return result
+}
+
+// localizedObjectName returns the name of the object in the current language.
+// If the name is not translated, the name in the default language is returned.
+func (view *View) localizedObjectName(triple object.Triple, longName bool) string {
+	objName, known := view.knownObjectName(triple, view.model.currentLang, longName)
+	if (!known || (len(objName) == 0)) && (view.model.currentLang != resource.LangDefault) {
+		objName, _ = view.knownObjectName(triple, resource.LangDefault, longName)
+	}
+	return objName
 }
 
 func (view *View) clipboardPopup(readOnly bool, label string, value string, changeCallback func(string)) {
@@ -201,7 +583,8 @@ func (view *View) clipboardPopup(readOnly bool, label string, value string, chan
 		if imgui.Selectable("Copy to Clipboard") {
 			view.clipboard.SetString(value)
 		}
-		if !readOnly && imgui.Selectable("Copy from Clipboard") {
+		pasteAvailable := view.clipboard.Content() == opengl.ClipboardContentText
+		if !readOnly && imgui.MenuItemV("Copy from Clipboard", "", false, pasteAvailable) {
 			newValue, err := view.clipboard.String()
 			if err == nil {
 				changeCallback(newValue)
@@ -212,23 +595,25 @@ func (view *View) clipboardPopup(readOnly bool, label string, value string, chan
 }
 
 func (view *View) requestSetObjectName(triple object.Triple, longName bool, newValue string) {
-	linearIndex := view.mod.ObjectProperties().TripleIndex(triple)
-	if linearIndex >= 0 {
-		id := ids.ObjectShortNames
-		if longName {
-			id = ids.ObjectLongNames
-		}
-		key := resource.KeyOf(id, view.model.currentLang, linearIndex)
+	newValue = text.Blocked(newValue)[0]
+	if err := edit.ValidateObjectName(newValue, longName); err != nil {
+		view.model.nameError = err.Error()
+		return
+	}
+	view.model.nameError = ""
+	key, known := edit.ObjectNameKey(view.mod.ObjectProperties(), triple, view.model.currentLang, longName)
+	if known {
 		oldValue, _ := view.textCache.Text(key)
 
 		if oldValue != newValue {
 			command := setObjectTextCommand{
-				model:   &view.model,
-				triple:  view.model.currentObject,
-				bitmap:  view.model.currentBitmap,
-				key:     key,
-				oldData: view.cp.Encode(oldValue),
-				newData: view.cp.Encode(text.Blocked(newValue)[0]),
+				model:     &view.model,
+				textCache: view.textCache,
+				triple:    view.model.currentObject,
+				bitmap:    view.model.currentBitmap,
+				key:       key,
+				oldData:   view.codepages.ForLanguage(key.Lang).Encode(oldValue),
+				newData:   view.codepages.ForLanguage(key.Lang).Encode(newValue),
 			}
 			view.commander.Queue(command)
 		}
@@ -236,10 +621,17 @@ func (view *View) requestSetObjectName(triple object.Triple, longName bool, newV
 }
 
 func (view *View) requestSetObjectProperties(modifier func(*object.Properties)) {
+	view.requestSetObjectPropertyField("", modifier)
+}
+
+// requestSetObjectPropertyField sets the properties of the current object, modifying only the given field.
+// Consecutive changes of the same field, such as those of dragging a slider, are merged into one undo step.
+func (view *View) requestSetObjectPropertyField(field string, modifier func(*object.Properties)) {
 	command := setObjectPropertiesCommand{
 		model:  &view.model,
 		triple: view.model.currentObject,
 		bitmap: view.model.currentBitmap,
+		field:  field,
 	}
 	currentProp, err := view.mod.ObjectProperties().ForObject(command.triple)
 	if err != nil {
@@ -303,7 +695,7 @@ func (view *View) renderCommonProperties(readOnly bool, properties *object.Prope
 	massUnifier.Add(int(properties.Common.Mass))
 	values.RenderUnifiedSliderInt(readOnly, false, "Mass", massUnifier, intIdentity, intFormat, -1, 5000,
 		func(newValue int) {
-			view.requestSetObjectProperties(func(prop *object.Properties) {
+			view.requestSetObjectPropertyField("common.Mass", func(prop *object.Properties) {
 				prop.Common.Mass = int32(newValue)
 			})
 		})
@@ -312,7 +704,7 @@ func (view *View) renderCommonProperties(readOnly bool, properties *object.Prope
 	hitpointsUnifier.Add(int(properties.Common.Hitpoints))
 	values.RenderUnifiedSliderInt(readOnly, false, "Hitpoints", hitpointsUnifier, intIdentity, intFormat, 0, 10000,
 		func(newValue int) {
-			view.requestSetObjectProperties(func(prop *object.Properties) {
+			view.requestSetObjectPropertyField("common.Hitpoints", func(prop *object.Properties) {
 				prop.Common.Hitpoints = int16(newValue)
 			})
 		})
@@ -321,7 +713,7 @@ func (view *View) renderCommonProperties(readOnly bool, properties *object.Prope
 	armorUnifier.Add(int(properties.Common.Armor))
 	values.RenderUnifiedSliderInt(readOnly, false, "Armor", armorUnifier, intIdentity, intFormat, 0, 255,
 		func(newValue int) {
-			view.requestSetObjectProperties(func(prop *object.Properties) {
+			view.requestSetObjectPropertyField("common.Armor", func(prop *object.Properties) {
 				prop.Common.Armor = byte(newValue)
 			})
 		})
@@ -350,7 +742,7 @@ func (view *View) renderCommonProperties(readOnly bool, properties *object.Prope
 	hardnessUnifier.Add(int(properties.Common.Hardness))
 	values.RenderUnifiedSliderInt(readOnly, false, "Hardness", hardnessUnifier, intIdentity, intFormat, 0, object.HardnessLimit,
 		func(newValue int) {
-			view.requestSetObjectProperties(func(prop *object.Properties) {
+			view.requestSetObjectPropertyField("common.Hardness", func(prop *object.Properties) {
 				prop.Common.Hardness = byte(newValue)
 			})
 		})
@@ -359,7 +751,7 @@ func (view *View) renderCommonProperties(readOnly bool, properties *object.Prope
 	physicsXRUnifier.Add(int(properties.Common.PhysicsXR))
 	values.RenderUnifiedSliderInt(readOnly, false, "Physics XR", physicsXRUnifier, intIdentity, intFormat, 0, object.PhysicsXRLimit,
 		func(newValue int) {
-			view.requestSetObjectProperties(func(prop *object.Properties) {
+			view.requestSetObjectPropertyField("common.Physics XR", func(prop *object.Properties) {
 				prop.Common.PhysicsXR = byte(newValue)
 			})
 		})
@@ -368,7 +760,7 @@ func (view *View) renderCommonProperties(readOnly bool, properties *object.Prope
 	physicsZUnifier.Add(int(properties.Common.PhysicsZ))
 	values.RenderUnifiedSliderInt(readOnly, false, "Physics Z", physicsZUnifier, intIdentity, intFormat, 0, 255,
 		func(newValue int) {
-			view.requestSetObjectProperties(func(prop *object.Properties) {
+			view.requestSetObjectPropertyField("common.Physics Z", func(prop *object.Properties) {
 				prop.Common.PhysicsZ = byte(newValue)
 			})
 		})
@@ -395,7 +787,7 @@ func (view *View) renderCommonProperties(readOnly bool, properties *object.Prope
 		primaryUnifier.Add(properties.Common.SpecialVulnerabilities.PrimaryValue())
 		values.RenderUnifiedSliderInt(readOnly, false, "Primary (double dmg)", primaryUnifier, intIdentity, intFormat, 0, object.SpecialDamageTypeLimit,
 			func(newValue int) {
-				view.requestSetObjectProperties(func(prop *object.Properties) {
+				view.requestSetObjectPropertyField("common.Primary (double dmg)", func(prop *object.Properties) {
 					prop.Common.SpecialVulnerabilities = prop.Common.SpecialVulnerabilities.WithPrimaryValue(newValue)
 				})
 			})
@@ -404,7 +796,7 @@ func (view *View) renderCommonProperties(readOnly bool, properties *object.Prope
 		superUnifier.Add(properties.Common.SpecialVulnerabilities.SuperValue())
 		values.RenderUnifiedSliderInt(readOnly, false, "Super (quad dmg)", superUnifier, intIdentity, intFormat, 0, object.SpecialDamageTypeLimit,
 			func(newValue int) {
-				view.requestSetObjectProperties(func(prop *object.Properties) {
+				view.requestSetObjectPropertyField("common.Super (quad dmg)", func(prop *object.Properties) {
 					prop.Common.SpecialVulnerabilities = prop.Common.SpecialVulnerabilities.WithSuperValue(newValue)
 				})
 			})
@@ -422,7 +814,7 @@ func (view *View) renderCommonProperties(readOnly bool, properties *object.Prope
 			return "%d"
 		}, 0, 255,
 		func(newValue int) {
-			view.requestSetObjectProperties(func(prop *object.Properties) {
+			view.requestSetObjectPropertyField("common.Defense", func(prop *object.Properties) {
 				prop.Common.Defense = byte(newValue)
 			})
 		})
@@ -438,7 +830,7 @@ func (view *View) renderCommonProperties(readOnly bool, properties *object.Prope
 		},
 		0, 7,
 		func(newValue int) {
-			view.requestSetObjectProperties(func(prop *object.Properties) {
+			view.requestSetObjectPropertyField("common.Toughness", func(prop *object.Properties) {
 				prop.Common.Toughness = byte(newValue)
 			})
 		})
@@ -447,7 +839,7 @@ func (view *View) renderCommonProperties(readOnly bool, properties *object.Prope
 	mfdOrMeshIDUnifier.Add(int(properties.Common.MfdOrMeshID))
 	values.RenderUnifiedSliderInt(readOnly, false, "MFD/Mesh ID", mfdOrMeshIDUnifier, intIdentity, intFormat, 0, 1000,
 		func(newValue int) {
-			view.requestSetObjectProperties(func(prop *object.Properties) {
+			view.requestSetObjectPropertyField("common.MFD/Mesh ID", func(prop *object.Properties) {
 				prop.Common.MfdOrMeshID = uint16(newValue)
 			})
 		})
@@ -456,7 +848,7 @@ func (view *View) renderCommonProperties(readOnly bool, properties *object.Prope
 	bitmap3DBitmapNumUnifier.Add(int(properties.Common.Bitmap3D.BitmapNumber()))
 	values.RenderUnifiedSliderInt(readOnly, false, "Bitmap Number", bitmap3DBitmapNumUnifier, intIdentity, intFormat, 0, int(object.Bitmap3DBitmapNumberLimit),
 		func(newValue int) {
-			view.requestSetObjectProperties(func(prop *object.Properties) {
+			view.requestSetObjectPropertyField("common.Bitmap Number", func(prop *object.Properties) {
 				prop.Common.Bitmap3D = prop.Common.Bitmap3D.WithBitmapNumber(uint16(newValue))
 			})
 		})
@@ -464,7 +856,7 @@ func (view *View) renderCommonProperties(readOnly bool, properties *object.Prope
 	bitmap3DFrameNumUnifier.Add(int(properties.Common.Bitmap3D.FrameNumber()))
 	values.RenderUnifiedSliderInt(readOnly, false, "Frame Number", bitmap3DFrameNumUnifier, intIdentity, intFormat, 0, int(object.Bitmap3DFrameNumberLimit),
 		func(newValue int) {
-			view.requestSetObjectProperties(func(prop *object.Properties) {
+			view.requestSetObjectPropertyField("common.Frame Number", func(prop *object.Properties) {
 				prop.Common.Bitmap3D = prop.Common.Bitmap3D.WithFrameNumber(uint16(newValue))
 			})
 		})
@@ -490,7 +882,7 @@ func (view *View) renderCommonProperties(readOnly bool, properties *object.Prope
 	values.RenderUnifiedSliderInt(readOnly, false, "DestroyEffect Value", destroyEffectValueUnifier,
 		intIdentity, intFormat, 0, int(object.DestroyEffectValueLimit),
 		func(newValue int) {
-			view.requestSetObjectProperties(func(prop *object.Properties) {
+			view.requestSetObjectPropertyField("common.DestroyEffect Value", func(prop *object.Properties) {
 				prop.Common.DestroyEffect = prop.Common.DestroyEffect.WithValue(byte(newValue))
 			})
 		})
@@ -515,7 +907,7 @@ func (view *View) renderCommonProperties(readOnly bool, properties *object.Prope
 func (view *View) renderGenericProperties(readOnly bool, properties *object.Properties) {
 	readInterpreter := objprop.GenericProperties(view.model.currentObject.Class, properties.Generic)
 	view.createPropertyControls(readOnly, readInterpreter, func(key string, modifier func(uint32) uint32) {
-		view.requestSetObjectProperties(func(prop *object.Properties) {
+		view.requestSetObjectPropertyField("generic."+key, func(prop *object.Properties) {
 			writeInterpreter := objprop.GenericProperties(view.model.currentObject.Class, prop.Generic)
 			view.setInterpreterValueKeyed(writeInterpreter, key, modifier)
 		})
@@ -525,7 +917,7 @@ func (view *View) renderGenericProperties(readOnly bool, properties *object.Prop
 func (view *View) renderSpecificProperties(readOnly bool, properties *object.Properties) {
 	readInterpreter := objprop.SpecificProperties(view.model.currentObject, properties.Specific)
 	view.createPropertyControls(readOnly, readInterpreter, func(key string, modifier func(uint32) uint32) {
-		view.requestSetObjectProperties(func(prop *object.Properties) {
+		view.requestSetObjectPropertyField("specific."+key, func(prop *object.Properties) {
 			writeInterpreter := objprop.SpecificProperties(view.model.currentObject, prop.Specific)
 			view.setInterpreterValueKeyed(writeInterpreter, key, modifier)
 		})
@@ -578,7 +970,7 @@ func (view *View) setInterpreterValueKeyed(instance *interpreters.Instance, key
 }
 
 func (view *View) renderObjectBitmap() {
-	render.TextureImage("BitmapImage", view.imageCache, view.currentBitmapKey(), imgui.Vec2{X: 320 * view.guiScale, Y: 240 * view.guiScale})
+	render.TextureImage("BitmapImage", view.imageCache, view.currentBitmapKey(), render.LayoutMetricsFor(view.guiScale).PreviewSize())
 	if imgui.Button("Clear") {
 		view.requestClearBitmap()
 	}
@@ -617,7 +1009,7 @@ func (view *View) requestImportBitmap() {
 		}
 		return palette.Palette(), nil
 	}
-	external.ImportImage(view.modalStateMachine, paletteRetriever, func(bmp bitmap.Bitmap) {
+	external.ImportImage(view.modalStateMachine, paletteRetriever, &view.model.imageColorMetric, func(bmp bitmap.Bitmap) {
 		view.requestSetBitmap(bmp)
 	})
 }
@@ -692,19 +1084,9 @@ func (view *View) currentBitmapKey() resource.Key {
 }
 
 func (view *View) currentBitmapKeyFor(offset int) resource.Key {
-	baseOffset := 1
-	done := false
-	view.mod.ObjectProperties().Iterate(func(triple object.Triple, prop *object.Properties) bool {
-		if !done && triple == view.model.currentObject {
-			done = true
-		}
-		if done {
-			return false
-		}
-		numExtra := int(prop.Common.Bitmap3D.FrameNumber())
-		baseOffset += 3 + numExtra
-		return true
-	})
-
-	return resource.KeyOf(ids.ObjectBitmaps, resource.LangAny, baseOffset+offset)
+	indices := integrity.ObjectBitmapIndices(view.mod.ObjectProperties())[view.model.currentObject]
+	if len(indices) == 0 {
+		return resource.KeyOf(ids.ObjectBitmaps, resource.LangAny, 0)
+	}
+	return resource.KeyOf(ids.ObjectBitmaps, resource.LangAny, indices[0]+offset)
 }
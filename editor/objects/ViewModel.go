@@ -1,6 +1,7 @@
 package objects
 
 import (
+	"github.com/inkyblackness/hacked/ss1/content/bitmap"
 	"github.com/inkyblackness/hacked/ss1/content/object"
 	"github.com/inkyblackness/hacked/ss1/resource"
 )
@@ -9,9 +10,37 @@ type viewModel struct {
 	windowOpen   bool
 	restoreFocus bool
 
+	nameSearch    string
+	typeFilter    string
 	currentObject object.Triple
 	currentBitmap int
 	currentLang   resource.Language
+	nameError     string
+
+	importIssues     []string
+	imageColorMetric bitmap.ColorMetric
+
+	selectedObjects []object.Triple
+	bulkFieldIndex  int
+}
+
+func (model viewModel) isSelected(triple object.Triple) bool {
+	for _, selected := range model.selectedObjects {
+		if selected == triple {
+			return true
+		}
+	}
+	return false
+}
+
+func (model *viewModel) toggleSelection(triple object.Triple) {
+	for index, selected := range model.selectedObjects {
+		if selected == triple {
+			model.selectedObjects = append(model.selectedObjects[:index:index], model.selectedObjects[index+1:]...)
+			return
+		}
+	}
+	model.selectedObjects = append(model.selectedObjects, triple)
 }
 
 func freshViewModel() viewModel {
@@ -6,10 +6,9 @@ import (
 	"github.com/inkyblackness/imgui-go"
 	"github.com/sqweek/dialog"
 
-	"github.com/inkyblackness/hacked/ss1/resource"
-	"github.com/inkyblackness/hacked/ss1/world"
-	"github.com/inkyblackness/hacked/ss1/world/ids"
+	"github.com/inkyblackness/hacked/ss1/world/persist"
 	"github.com/inkyblackness/hacked/ui/gui"
+	"github.com/inkyblackness/hacked/ui/opengl"
 )
 
 type addManifestEntryWaitingState struct {
@@ -56,29 +55,16 @@ Typically, you would use the main "data" directory of the game
 }
 
 func (state *addManifestEntryWaitingState) HandleFiles(names []string) {
-	staging := newFileStaging()
-
-	staging.stageAll(names)
-
-	if len(staging.resources) > 0 {
-		entry := &world.ManifestEntry{
-			ID: names[0],
-		}
-
-		for filename, viewer := range staging.resources {
-			localized := resource.LocalizedResources{
-				ID:       filename,
-				Language: ids.LocalizeFilename(filename),
-				Viewer:   viewer,
-			}
-			entry.Resources = append(entry.Resources, localized)
-		}
-		entry.ObjectProperties = staging.objectProperties
-		entry.TextureProperties = staging.textureProperties
+	state.HandleFilesWithResults(names)
+}
 
-		state.view.requestAddManifestEntry(entry)
-		state.machine.SetState(nil)
-	} else {
+func (state *addManifestEntryWaitingState) HandleFilesWithResults(names []string) []opengl.FileDropResult {
+	entry, err := persist.LoadManifestEntry(names)
+	if err != nil {
 		state.failureTime = time.Now()
+		return opengl.FileDropResultsOf(names, opengl.FileDropFailed, err)
 	}
+	state.view.requestAddManifestEntry(entry)
+	state.machine.SetState(nil)
+	return opengl.FileDropResultsOf(names, opengl.FileDropLoaded, nil)
 }
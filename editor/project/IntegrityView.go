@@ -0,0 +1,86 @@
+package project
+
+import (
+	"fmt"
+
+	"github.com/inkyblackness/imgui-go"
+
+	"github.com/inkyblackness/hacked/editor/render"
+	"github.com/inkyblackness/hacked/ss1/world"
+	"github.com/inkyblackness/hacked/ss1/world/ids"
+	"github.com/inkyblackness/hacked/ss1/world/integrity"
+)
+
+// IntegrityView shows the findings of checking the mod for problems.
+type IntegrityView struct {
+	mod      *world.Mod
+	guiScale float32
+
+	windowOpen bool
+	report     integrity.Report
+}
+
+// NewIntegrityView returns a new instance.
+func NewIntegrityView(mod *world.Mod, guiScale float32) *IntegrityView {
+	return &IntegrityView{
+		mod:      mod,
+		guiScale: guiScale,
+	}
+}
+
+// Check runs all validators on the mod and shows the resulting report.
+func (view *IntegrityView) Check() {
+	view.report = integrity.Check(view.mod)
+	view.windowOpen = true
+}
+
+// Render requests to render the view.
+func (view *IntegrityView) Render() {
+	if !view.windowOpen {
+		return
+	}
+	imgui.SetNextWindowSizeV(render.LayoutMetricsFor(view.guiScale).Vec2(600, 400), imgui.ConditionOnce)
+	if imgui.BeginV("Project Check", &view.windowOpen, imgui.WindowFlagsHorizontalScrollbar) {
+		view.renderContent()
+	}
+	imgui.End()
+}
+
+func (view *IntegrityView) renderContent() {
+	if imgui.Button("Check Again") {
+		view.Check()
+	}
+	imgui.SameLine()
+	imgui.Text(fmt.Sprintf("Errors: %d, Warnings: %d, Infos: %d",
+		view.report.Count(integrity.SeverityError),
+		view.report.Count(integrity.SeverityWarning),
+		view.report.Count(integrity.SeverityInfo)))
+	imgui.Separator()
+	if len(view.report.Findings) == 0 {
+		imgui.Text("No problems found.")
+		return
+	}
+	for _, category := range view.report.Categories() {
+		findings := view.report.InCategory(category)
+		if imgui.TreeNodeV(fmt.Sprintf("%v (%d)", category, len(findings)), imgui.TreeNodeFlagsDefaultOpen|imgui.TreeNodeFlagsFramed) {
+			for _, finding := range findings {
+				imgui.PushStyleColor(imgui.StyleColorText, severityColor(finding.Severity))
+				imgui.Text(fmt.Sprintf("%-7v %v/%v %v: %v", finding.Severity, finding.Resource.ID, finding.Resource.Lang,
+					ids.NameOf(finding.Resource.ID).Name, finding.Message))
+				imgui.PopStyleColor()
+			}
+			imgui.TreePop()
+		}
+	}
+}
+
+func severityColor(severity integrity.Severity) imgui.Vec4 {
+	switch severity {
+	case integrity.SeverityError:
+		return imgui.Vec4{X: 1.0, Y: 0.0, Z: 0.0, W: 1.0}
+	case integrity.SeverityWarning:
+		return imgui.Vec4{X: 1.0, Y: 1.0, Z: 0.0, W: 1.0}
+	default:
+		return imgui.Vec4{X: 1.0, Y: 1.0, Z: 1.0, W: 0.8}
+	}
+}
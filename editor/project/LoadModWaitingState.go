@@ -1,87 +1,144 @@
 package project
 
 import (
+	"errors"
+	"fmt"
+	"sync"
 	"time"
 
 	"github.com/inkyblackness/imgui-go"
 	"github.com/sqweek/dialog"
 
-	"github.com/inkyblackness/hacked/ss1/world"
-	"github.com/inkyblackness/hacked/ss1/world/ids"
+	"github.com/inkyblackness/hacked/ss1/world/persist"
 	"github.com/inkyblackness/hacked/ui/gui"
+	"github.com/inkyblackness/hacked/ui/opengl"
 )
 
+var errLoadInProgress = errors.New("a mod is already being loaded")
+
 type loadModWaitingState struct {
 	machine     gui.ModalStateMachine
 	view        *View
 	failureTime time.Time
+
+	loading  bool
+	result   chan loadModResult
+	progress loadModProgress
+}
+
+type loadModResult struct {
+	staged *persist.StagedMod
+	err    error
+}
+
+// loadModProgress holds the latest progress of loading, as reported from the loading goroutines.
+type loadModProgress struct {
+	mutex    sync.Mutex
+	filename string
+	loaded   int
+	total    int
+}
+
+func (progress *loadModProgress) update(filename string, loaded, total int) {
+	progress.mutex.Lock()
+	defer progress.mutex.Unlock()
+	progress.filename = filename
+	progress.loaded = loaded
+	progress.total = total
+}
+
+func (progress *loadModProgress) current() (string, int, int) {
+	progress.mutex.Lock()
+	defer progress.mutex.Unlock()
+	return progress.filename, progress.loaded, progress.total
 }
 
 func (state *loadModWaitingState) Render() {
 	if imgui.BeginPopupModalV("Load mod", nil,
 		imgui.WindowFlagsNoResize|imgui.WindowFlagsNoMove|imgui.WindowFlagsNoSavedSettings|imgui.WindowFlagsAlwaysAutoResize) {
+		if state.loading {
+			state.renderLoading()
+		} else {
+			state.renderWaiting()
+		}
+		imgui.EndPopup()
+	} else {
+		state.machine.SetState(nil)
+	}
+}
 
-		imgui.Text("Waiting for folder.")
-		if !state.failureTime.IsZero() {
-			imgui.PushStyleColor(imgui.StyleColorText, imgui.Vec4{X: 1, Y: 0, Z: 0, W: 1})
-			imgui.Text("Previous attempt failed, no usable data detected.\nPlease check and try again.")
-			imgui.PopStyleColor()
-			if time.Since(state.failureTime).Seconds() > 5 {
-				state.failureTime = time.Time{}
-			}
+func (state *loadModWaitingState) renderWaiting() {
+	imgui.Text("Waiting for folder.")
+	if !state.failureTime.IsZero() {
+		imgui.PushStyleColor(imgui.StyleColorText, imgui.Vec4{X: 1, Y: 0, Z: 0, W: 1})
+		imgui.Text("Previous attempt failed, no usable data detected.\nPlease check and try again.")
+		imgui.PopStyleColor()
+		if time.Since(state.failureTime).Seconds() > 5 {
+			state.failureTime = time.Time{}
 		}
-		imgui.Text(`From your file browser drag'n'drop the folder
+	}
+	imgui.Text(`From your file browser drag'n'drop the folder
 of the mod you want to work on into the editor window.
 If you want to modify the main game files,
 use the main "data" directory of the game.
 `)
-		imgui.Text("This action will clear the undo/redo buffer\nand you will lose any unsaved changes.")
-		imgui.Separator()
-		if imgui.Button("Browse...") {
-			dlgBuilder := dialog.Directory()
-			filename, err := dlgBuilder.Browse()
-			if err == nil {
-				state.HandleFiles([]string{filename})
-			}
+	imgui.Text("This action will clear the undo/redo buffer\nand you will lose any unsaved changes.")
+	imgui.Separator()
+	if imgui.Button("Browse...") {
+		dlgBuilder := dialog.Directory()
+		filename, err := dlgBuilder.Browse()
+		if err == nil {
+			state.HandleFiles([]string{filename})
 		}
-		imgui.SameLine()
-		if imgui.Button("Cancel") {
-			state.machine.SetState(nil)
-			imgui.CloseCurrentPopup()
+	}
+	imgui.SameLine()
+	if imgui.Button("Cancel") {
+		state.machine.SetState(nil)
+		imgui.CloseCurrentPopup()
+	}
+}
+
+func (state *loadModWaitingState) renderLoading() {
+	select {
+	case result := <-state.result:
+		state.loading = false
+		if result.err != nil {
+			state.failureTime = time.Now()
+			return
 		}
-		imgui.EndPopup()
-	} else {
+		state.view.applyStagedMod(result.staged)
 		state.machine.SetState(nil)
+		imgui.CloseCurrentPopup()
+		return
+	default:
 	}
+	filename, loaded, total := state.progress.current()
+	imgui.Text("Loading mod...")
+	fraction := float32(0)
+	if total > 0 {
+		fraction = float32(loaded) / float32(total)
+	}
+	imgui.ProgressBarV(fraction, imgui.Vec2{X: -1, Y: 0}, fmt.Sprintf("%d / %d", loaded, total))
+	imgui.Text(filename)
 }
 
 func (state *loadModWaitingState) HandleFiles(names []string) {
-	staging := newFileStaging()
-
-	staging.stageAll(names)
-
-	if len(staging.resources) > 0 {
-		var locs []*world.LocalizedResources
-
-		for filename, viewer := range staging.resources {
-			lang := ids.LocalizeFilename(filename)
-			loc := &world.LocalizedResources{
-				Filename: filename,
-				Language: lang,
-			}
-			for _, id := range viewer.IDs() {
-				view, err := viewer.View(id)
-				if err == nil {
-					_ = loc.Store.Put(id, view)
-				}
-				// TODO: handle error?
-			}
-			locs = append(locs, loc)
-		}
+	state.HandleFilesWithResults(names)
+}
 
-		state.machine.SetState(nil)
-		state.view.requestLoadMod(names[0], locs, staging.objectProperties, staging.textureProperties)
-	} else {
-		state.failureTime = time.Now()
+// HandleFilesWithResults starts to load the mod from the given files in the background.
+// The files are reported as loaded once loading started. Should they not contain a mod, the state shows the failure.
+func (state *loadModWaitingState) HandleFilesWithResults(names []string) []opengl.FileDropResult {
+	if state.loading {
+		return opengl.FileDropResultsOf(names, opengl.FileDropIgnored, errLoadInProgress)
 	}
+	state.loading = true
+	state.progress.update("", 0, len(names))
+	result := make(chan loadModResult, 1)
+	state.result = result
+	go func() {
+		staged, err := persist.StageMod(names, state.progress.update)
+		result <- loadModResult{staged: staged, err: err}
+	}()
+	return opengl.FileDropResultsOf(names, opengl.FileDropLoaded, nil)
 }
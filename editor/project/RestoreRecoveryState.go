@@ -0,0 +1,68 @@
+package project
+
+import (
+	"github.com/inkyblackness/imgui-go"
+
+	"github.com/inkyblackness/hacked/ss1/world"
+	"github.com/inkyblackness/hacked/ui/gui"
+)
+
+type restoreRecoveryStartState struct {
+	machine gui.ModalStateMachine
+	view    *View
+}
+
+func (state restoreRecoveryStartState) Render() {
+	imgui.OpenPopup("Restore unsaved changes")
+	state.machine.SetState(&restoreRecoveryWaitingState{
+		machine: state.machine,
+		view:    state.view,
+	})
+}
+
+func (state restoreRecoveryStartState) HandleFiles(names []string) {
+}
+
+type restoreRecoveryWaitingState struct {
+	machine   gui.ModalStateMachine
+	view      *View
+	errorInfo string
+}
+
+func (state *restoreRecoveryWaitingState) Render() {
+	if imgui.BeginPopupModalV("Restore unsaved changes", nil,
+		imgui.WindowFlagsNoResize|imgui.WindowFlagsNoMove|imgui.WindowFlagsNoSavedSettings|imgui.WindowFlagsAlwaysAutoResize) {
+
+		imgui.Text(`The previous session was not closed properly
+and left unsaved changes behind.
+Restored changes are not saved automatically,
+please check and save them yourself.`)
+		if len(state.errorInfo) > 0 {
+			imgui.PushStyleColor(imgui.StyleColorText, imgui.Vec4{X: 1, Y: 0, Z: 0, W: 1})
+			imgui.Text("The changes could not be restored:\n" + state.errorInfo)
+			imgui.PopStyleColor()
+		}
+		imgui.Separator()
+		if (len(state.errorInfo) == 0) && imgui.Button("Restore") {
+			err := world.LoadRecovery(state.view.recoveryFile, state.view.mod)
+			if err != nil {
+				state.errorInfo = err.Error()
+			} else {
+				state.machine.SetState(nil)
+				imgui.CloseCurrentPopup()
+			}
+		}
+		imgui.SameLine()
+		if imgui.Button("Discard") {
+			state.view.RemoveRecovery()
+			state.machine.SetState(nil)
+			imgui.CloseCurrentPopup()
+		}
+		imgui.EndPopup()
+	} else {
+		state.machine.SetState(nil)
+	}
+}
+
+func (state *restoreRecoveryWaitingState) HandleFiles(names []string) {
+}
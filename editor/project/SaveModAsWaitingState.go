@@ -1,6 +1,7 @@
 package project
 
 import (
+	"errors"
 	"os"
 	"time"
 
@@ -8,6 +9,7 @@ import (
 	"github.com/sqweek/dialog"
 
 	"github.com/inkyblackness/hacked/ui/gui"
+	"github.com/inkyblackness/hacked/ui/opengl"
 )
 
 type saveModAsWaitingState struct {
@@ -59,13 +61,18 @@ where to save the data into the editor window.
 }
 
 func (state *saveModAsWaitingState) HandleFiles(names []string) {
+	state.HandleFilesWithResults(names)
+}
+
+func (state *saveModAsWaitingState) HandleFilesWithResults(names []string) []opengl.FileDropResult {
 	modPath, ok := state.verifyDir(names)
-	if ok {
-		state.machine.SetState(nil)
-		state.view.requestSaveMod(modPath)
-	} else {
+	if !ok {
 		state.failureTime = time.Now()
+		return opengl.FileDropResultsOf(names, opengl.FileDropIgnored, errors.New("exactly one folder is expected"))
 	}
+	state.machine.SetState(nil)
+	state.view.requestSaveMod(modPath)
+	return opengl.FileDropResultsOf(names, opengl.FileDropLoaded, nil)
 }
 
 func (state saveModAsWaitingState) verifyDir(names []string) (string, bool) {
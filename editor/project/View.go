@@ -7,32 +7,57 @@ import (
 
 	"github.com/inkyblackness/imgui-go"
 
-	"github.com/inkyblackness/hacked/ss1/content/object"
-	"github.com/inkyblackness/hacked/ss1/content/texture"
+	"github.com/inkyblackness/hacked/editor/render"
 	"github.com/inkyblackness/hacked/ss1/edit/undoable/cmd"
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/resource/lgres"
 	"github.com/inkyblackness/hacked/ss1/world"
+	"github.com/inkyblackness/hacked/ss1/world/persist"
 	"github.com/inkyblackness/hacked/ui/gui"
 )
 
+// maxBackupCount is the maximum number of backups that can be kept for each saved file.
+const maxBackupCount = 10
+
+// maxCompressionThreshold is the maximum size, in bytes, that can be set as compression threshold.
+const maxCompressionThreshold = 1024
+
+// maxRecoveryIntervalMin is the maximum time, in minutes, between two saves of the recovery file.
+const maxRecoveryIntervalMin = 30
+
 // View handles the project display.
 type View struct {
 	mod *world.Mod
 
 	modalStateMachine gui.ModalStateMachine
 	guiScale          float32
+	focus             render.FocusArea
 	commander         cmd.Commander
 
+	backup      world.FileBackup
+	saveOptions persist.SaveOptions
+
+	recoveryFile        string
+	recoverySaved       bool
+	recoveryTime        time.Time
+	recoveryChangeCount uint64
+
 	model viewModel
 }
 
 // NewView creates a new instance for the project display.
+// If recoveryFile is not empty, unsaved changes are periodically kept in that file.
 func NewView(mod *world.Mod, modalStateMachine gui.ModalStateMachine,
-	guiScale float32, commander cmd.Commander) *View {
+	guiScale float32, focus render.FocusArea, commander cmd.Commander, recoveryFile string) *View {
 	return &View{
 		mod: mod,
 
+		saveOptions:  persist.DefaultSaveOptions(),
+		recoveryFile: recoveryFile,
+
 		modalStateMachine: modalStateMachine,
 		guiScale:          guiScale,
+		focus:             focus,
 		commander:         commander,
 
 		model: freshViewModel(),
@@ -52,6 +77,9 @@ func (view *View) Render() {
 		view.model.windowOpen = true
 	}
 	title := "Project"
+	if view.mod.HasUnsavedChanges() {
+		title += fmt.Sprintf(" (modified: %d resource(s))", len(view.mod.DirtyKeys()))
+	}
 	changedFiles := len(view.mod.ModifiedFilenames())
 	if changedFiles > 0 {
 		title += fmt.Sprintf(" - %d file(s) pending save", changedFiles)
@@ -69,19 +97,21 @@ func (view *View) Render() {
 			}
 		}
 	}
+	view.updateRecovery()
 	if view.model.windowOpen {
-		imgui.SetNextWindowSizeV(imgui.Vec2{X: 400 * view.guiScale, Y: 300 * view.guiScale}, imgui.ConditionOnce)
+		imgui.SetNextWindowSizeV(render.LayoutMetricsFor(view.guiScale).CompactWindowSize(), imgui.ConditionOnce)
 		if imgui.BeginV(title+"###Project", view.WindowOpen(), 0) {
-			view.renderContent()
+			view.focus.Render(func() { view.renderContent() })
 		}
 		imgui.End()
 	}
 }
 
 func (view *View) renderContent() {
+	metrics := render.LayoutMetricsFor(view.guiScale)
 	imgui.Text("Mod Location")
 	imgui.PushStyleVarVec2(imgui.StyleVarWindowPadding, imgui.Vec2{X: 1, Y: 0})
-	imgui.BeginChildV("ModLocation", imgui.Vec2{X: -200*view.guiScale - 10*view.guiScale, Y: imgui.TextLineHeight() * 1.5}, true,
+	imgui.BeginChildV("ModLocation", imgui.Vec2{X: -2*metrics.ButtonWidth() - metrics.Scaled(10), Y: imgui.TextLineHeight() * 1.5}, true,
 		imgui.WindowFlagsNoScrollbar|imgui.WindowFlagsNoScrollWithMouse)
 	modPath := view.mod.Path()
 	if len(modPath) > 0 {
@@ -95,17 +125,39 @@ func (view *View) renderContent() {
 	imgui.PopStyleVar()
 	imgui.BeginGroup()
 	imgui.SameLine()
-	if imgui.ButtonV("Save", imgui.Vec2{X: 100 * view.guiScale, Y: 0}) {
+	if imgui.ButtonV("Save", imgui.Vec2{X: metrics.ButtonWidth(), Y: 0}) {
 		view.StartSavingMod()
 	}
 	imgui.SameLine()
-	if imgui.ButtonV("Load...", imgui.Vec2{X: 100 * view.guiScale, Y: 0}) {
+	if imgui.ButtonV("Load...", imgui.Vec2{X: metrics.ButtonWidth(), Y: 0}) {
 		view.startLoadingMod()
 	}
 	imgui.EndGroup()
+	gui.StepSliderInt("Backups", &view.model.backupCount, 0, maxBackupCount)
+	if imgui.IsItemHovered() {
+		imgui.SetTooltip("Number of backups to keep of files that are overwritten.\nBackups are only created once per file per session.")
+	}
+	view.renderCompressionOptions()
+	if len(view.recoveryFile) > 0 {
+		if imgui.Checkbox("Recovery Autosave", &view.model.recoveryEnabled) && !view.model.recoveryEnabled {
+			view.RemoveRecovery()
+		}
+		if imgui.IsItemHovered() {
+			imgui.SetTooltip("Periodically keep unsaved changes in a recovery file:\n" + view.recoveryFile)
+		}
+		if view.model.recoveryEnabled {
+			imgui.SameLine()
+			gui.StepSliderIntV("Interval", &view.model.recoveryIntervalMin, 1, maxRecoveryIntervalMin, "%d min")
+		}
+		if len(view.model.recoveryError) > 0 {
+			imgui.PushStyleColor(imgui.StyleColorText, imgui.Vec4{X: 1, Y: 0, Z: 0, W: 1})
+			imgui.Text("Recovery autosave failed: " + view.model.recoveryError)
+			imgui.PopStyleColor()
+		}
+	}
 
 	imgui.Text("Static World Data")
-	imgui.BeginChildV("ManifestEntries", imgui.Vec2{X: -100 * view.guiScale, Y: 0}, true, 0)
+	imgui.BeginChildV("ManifestEntries", imgui.Vec2{X: render.LayoutMetricsFor(view.guiScale).ButtonColumnSpace(), Y: 0}, true, 0)
 	manifest := view.mod.World()
 	entries := manifest.EntryCount()
 	for i := entries - 1; i >= 0; i-- {
@@ -218,17 +270,93 @@ func (view *View) requestRemoveManifestEntry() {
 	view.commander.Queue(command)
 }
 
-func (view *View) requestLoadMod(modPath string, resources []*world.LocalizedResources,
-	objectProperties object.PropertiesTable, textureProperties texture.PropertiesList) {
-	view.mod.SetPath(modPath)
-	view.mod.Reset(resources, objectProperties, textureProperties)
-	// fix list resources for any "old" mod.
-	view.mod.FixListResources()
+func (view *View) applyStagedMod(staged *persist.StagedMod) {
+	staged.Apply(view.mod)
+}
+
+func (view *View) updateRecovery() {
+	if (len(view.recoveryFile) == 0) || !view.model.recoveryEnabled {
+		return
+	}
+	if len(view.mod.ModifiedFilenames()) == 0 {
+		if view.recoverySaved {
+			view.RemoveRecovery()
+		}
+		return
+	}
+	changeCount := view.mod.ChangeCount()
+	interval := time.Duration(view.model.recoveryIntervalMin) * time.Minute
+	if (changeCount == view.recoveryChangeCount) || (time.Since(view.recoveryTime) < interval) {
+		return
+	}
+	view.recoveryTime = time.Now()
+	err := world.SaveRecovery(view.recoveryFile, view.mod)
+	if err != nil {
+		view.model.recoveryError = err.Error()
+		return
+	}
+	view.model.recoveryError = ""
+	view.recoverySaved = true
+	view.recoveryChangeCount = changeCount
+}
+
+// OfferRecovery asks whether to restore unsaved changes of a previous session, if a recovery file exists.
+func (view *View) OfferRecovery() {
+	if (len(view.recoveryFile) == 0) || !world.RecoveryExists(view.recoveryFile) {
+		return
+	}
+	view.modalStateMachine.SetState(&restoreRecoveryStartState{
+		machine: view.modalStateMachine,
+		view:    view,
+	})
+}
+
+// RemoveRecovery deletes the recovery file of unsaved changes, if one was written.
+// This is meant to be called on regular shutdown.
+func (view *View) RemoveRecovery() {
+	if len(view.recoveryFile) == 0 {
+		return
+	}
+	_ = world.RemoveRecovery(view.recoveryFile)
+	view.recoverySaved = false
+	view.recoveryChangeCount = 0
+}
+
+func (view *View) renderCompressionOptions() {
+	if !imgui.TreeNode("Compression") {
+		return
+	}
+	compression := &view.saveOptions.Compression
+	gui.StepSliderIntV("Threshold", &compression.Threshold, 0, maxCompressionThreshold, "%d bytes")
+	if imgui.IsItemHovered() {
+		imgui.SetTooltip("Resources smaller than this are stored uncompressed in auto mode.\nTiny resources become larger when compressed.")
+	}
+	for _, contentType := range resource.ContentTypes() {
+		policy := compression.PolicyFor(contentType)
+		if imgui.BeginCombo(contentType.String(), policy.String()) {
+			for _, option := range lgres.CompressionPolicies() {
+				if imgui.SelectableV(option.String(), option == policy, 0, imgui.Vec2{}) {
+					compression.Policies[contentType] = option
+				}
+			}
+			imgui.EndCombo()
+		}
+	}
+	imgui.Text("Auto keeps the compression of each resource, unless it is below the threshold.")
+	for _, contentType := range lgres.UncompressedContentTypes() {
+		if compression.PolicyFor(contentType) != lgres.CompressionNever {
+			imgui.PushStyleColor(imgui.StyleColorText, imgui.Vec4{X: 1, Y: 1, Z: 0, W: 1})
+			imgui.Text(contentType.String() + " resources must be uncompressed for the engine.")
+			imgui.PopStyleColor()
+		}
+	}
+	imgui.TreePop()
 }
 
 func (view *View) requestSaveMod(modPath string) {
 	view.mod.FixListResources()
-	err := saveModResourcesTo(view.mod, modPath)
+	view.backup.Retain = view.model.backupCount
+	err := persist.SaveModTo(view.mod, modPath, &view.backup, view.saveOptions)
 	if err != nil {
 		view.modalStateMachine.SetState(&saveModFailedState{
 			machine:   view.modalStateMachine,
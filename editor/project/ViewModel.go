@@ -6,6 +6,11 @@ type viewModel struct {
 	selectedManifestEntry int
 
 	autosaveTimeoutSec int
+	backupCount        int
+
+	recoveryEnabled     bool
+	recoveryIntervalMin int
+	recoveryError       string
 }
 
 func freshViewModel() viewModel {
@@ -13,5 +18,7 @@ func freshViewModel() viewModel {
 		windowOpen:            true,
 		selectedManifestEntry: -1,
 		autosaveTimeoutSec:    5,
+		recoveryEnabled:       true,
+		recoveryIntervalMin:   2,
 	}
 }
@@ -0,0 +1,120 @@
+package render
+
+import (
+	"github.com/inkyblackness/imgui-go"
+)
+
+// LayoutMetrics provides the sizes for laying out views, derived from the scale of the user interface.
+// Views use these metrics instead of own constants, so that they scale consistently.
+// The metrics are cheap to create and are meant to be derived from the current scale for each frame.
+type LayoutMetrics struct {
+	scale float32
+}
+
+// LayoutMetricsFor returns the metrics for given scale of the user interface.
+func LayoutMetricsFor(guiScale float32) LayoutMetrics {
+	return LayoutMetrics{scale: guiScale}
+}
+
+// Scaled returns the given length, specified for a scale of 1.0, in the current scale.
+func (metrics LayoutMetrics) Scaled(length float32) float32 {
+	return length * metrics.scale
+}
+
+// Vec2 returns the given size, specified for a scale of 1.0, in the current scale.
+func (metrics LayoutMetrics) Vec2(x, y float32) imgui.Vec2 {
+	return imgui.Vec2{X: metrics.Scaled(x), Y: metrics.Scaled(y)}
+}
+
+// CompactWindowSize is the initial size for windows with few controls.
+func (metrics LayoutMetrics) CompactWindowSize() imgui.Vec2 {
+	return metrics.Vec2(400, 300)
+}
+
+// TallWindowSize is the initial size for windows with a long list of controls.
+func (metrics LayoutMetrics) TallWindowSize() imgui.Vec2 {
+	return metrics.Vec2(400, 500)
+}
+
+// MediumWindowSize is the initial size for windows showing larger content.
+func (metrics LayoutMetrics) MediumWindowSize() imgui.Vec2 {
+	return metrics.Vec2(640, 480)
+}
+
+// WideWindowSize is the initial size for windows with properties next to a preview.
+func (metrics LayoutMetrics) WideWindowSize() imgui.Vec2 {
+	return metrics.Vec2(800, 300)
+}
+
+// LargeWindowSize is the initial size for windows with properties next to a large preview.
+func (metrics LayoutMetrics) LargeWindowSize() imgui.Vec2 {
+	return metrics.Vec2(800, 600)
+}
+
+// PropertiesWidth is the width of a properties pane next to a preview.
+func (metrics LayoutMetrics) PropertiesWidth() float32 {
+	return metrics.Scaled(350)
+}
+
+// ButtonWidth is the width of buttons that are laid out next to each other.
+func (metrics LayoutMetrics) ButtonWidth() float32 {
+	return metrics.Scaled(100)
+}
+
+// PreviewSize is the size of a bitmap preview.
+func (metrics LayoutMetrics) PreviewSize() imgui.Vec2 {
+	return metrics.Vec2(320, 240)
+}
+
+// NarrowLabelSpace returns the item width that leaves space for short labels.
+// The value is negative, as to be used for imgui.PushItemWidth().
+func (metrics LayoutMetrics) NarrowLabelSpace() float32 {
+	return metrics.Scaled(-100)
+}
+
+// LabelSpace returns the item width that leaves space for regular labels.
+// The value is negative, as to be used for imgui.PushItemWidth().
+func (metrics LayoutMetrics) LabelSpace() float32 {
+	return metrics.Scaled(-150)
+}
+
+// WideLabelSpace returns the item width that leaves space for long labels.
+// The value is negative, as to be used for imgui.PushItemWidth().
+func (metrics LayoutMetrics) WideLabelSpace() float32 {
+	return metrics.Scaled(-200)
+}
+
+// ExtraWideLabelSpace returns the item width that leaves space for labels with additional controls.
+// The value is negative, as to be used for imgui.PushItemWidth().
+func (metrics LayoutMetrics) ExtraWideLabelSpace() float32 {
+	return metrics.Scaled(-250)
+}
+
+// PropertyLabelSpace returns the item width that leaves space for the labels of detailed properties.
+// The value is negative, as to be used for imgui.PushItemWidth().
+func (metrics LayoutMetrics) PropertyLabelSpace() float32 {
+	return metrics.Scaled(-260)
+}
+
+// ButtonColumnSpace returns the width of a list that leaves space for a column of buttons next to it.
+// The value is negative, as to be used for the size of a child window.
+func (metrics LayoutMetrics) ButtonColumnSpace() float32 {
+	return metrics.Scaled(-100)
+}
+
+// PreviewSpace returns the width of a properties pane that leaves space for a preview next to it.
+// The value is negative, as to be used for the size of a child window.
+func (metrics LayoutMetrics) PreviewSpace() float32 {
+	return metrics.Scaled(-330)
+}
+
+// SampleSpace returns the width of a properties pane that leaves space for texture samples next to it.
+// The value is negative, as to be used for the size of a child window.
+func (metrics LayoutMetrics) SampleSpace() float32 {
+	return metrics.Scaled(-300)
+}
+
+// ThumbnailSize is the size of a thumbnail in a selector.
+func (metrics LayoutMetrics) ThumbnailSize() imgui.Vec2 {
+	return metrics.Vec2(64, 64)
+}
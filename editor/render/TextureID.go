@@ -15,3 +15,9 @@ func TextureIDForBitmapTexture(key resource.Key) imgui.TextureID {
 	id |= imgui.TextureID(key.Index & 0xFFFF)
 	return id
 }
+
+// TextureIDForThumbnail returns a texture ID that identifies the thumbnail of a bitmap texture
+// with given size. The size is limited to 16 bits.
+func TextureIDForThumbnail(key resource.Key, size int) imgui.TextureID {
+	return TextureIDForBitmapTexture(key) | imgui.TextureID(size&0xFFFF)<<40
+}
@@ -14,19 +14,20 @@ func TextureSelector(label string, width float32, guiScale float32,
 	count int, selectedIndex int, cache *graphics.TextureCache, keyResolver func(int) resource.Key,
 	tooltipText func(int) string,
 	changeCallback func(int)) {
-	if imgui.BeginChildV(label, imgui.Vec2{X: width * guiScale, Y: 100 * guiScale}, true,
+	metrics := LayoutMetricsFor(guiScale)
+	if imgui.BeginChildV(label, metrics.Vec2(width, 100), true,
 		imgui.WindowFlagsHorizontalScrollbar|imgui.WindowFlagsNoScrollWithMouse) {
 		for i := 0; i < count; i++ {
 			key := keyResolver(i)
 			imgui.PushStyleVarVec2(imgui.StyleVarWindowPadding, imgui.Vec2{X: 0, Y: 0})
-			if imgui.BeginChildV(fmt.Sprintf("%3d", i), imgui.Vec2{X: 80 * guiScale, Y: 64 * guiScale}, false,
+			if imgui.BeginChildV(fmt.Sprintf("%3d", i), metrics.Vec2(80, 64), false,
 				imgui.WindowFlagsNoNav|imgui.WindowFlagsNoScrollWithMouse) {
 				imgui.BeginGroup()
-				if imgui.SelectableV("", selectedIndex == i, 0, imgui.Vec2{X: 0, Y: 64 * guiScale}) {
+				if imgui.SelectableV("", selectedIndex == i, 0, imgui.Vec2{X: 0, Y: metrics.Scaled(64)}) {
 					changeCallback(i)
 				}
 				imgui.SameLine()
-				TextureImage(fmt.Sprintf("%3d", i), cache, key, imgui.Vec2{X: 64 * guiScale, Y: 64 * guiScale})
+				ThumbnailImage(fmt.Sprintf("%3d", i), cache.Thumbnails(), key, metrics.ThumbnailSize())
 				imgui.EndGroup()
 				if imgui.IsItemHovered() {
 					text := tooltipText(i)
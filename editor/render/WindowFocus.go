@@ -0,0 +1,63 @@
+package render
+
+import "github.com/inkyblackness/imgui-go"
+
+// WindowFocus determines the window the user works in.
+// Windows report whether the mouse hovers their content while they are rendered.
+// Pressing a mouse button makes the hovered window the focused one.
+type WindowFocus struct {
+	hovered string
+	focused string
+}
+
+// NewFrame must be called before any window is rendered.
+func (focus *WindowFocus) NewFrame() {
+	focus.hovered = ""
+}
+
+// MouseButtonPressed makes the currently hovered window the focused one.
+// Presses outside of any window, such as in the main menu, keep the current focus.
+func (focus *WindowFocus) MouseButtonPressed() {
+	if len(focus.hovered) > 0 {
+		focus.focused = focus.hovered
+	}
+}
+
+// Focus makes the window with given name the focused one.
+// This is for areas that are not rendered as windows, such as the background.
+func (focus *WindowFocus) Focus(name string) {
+	focus.focused = name
+}
+
+// Focused returns the name of the focused window. It is empty if no window has been focused yet.
+func (focus *WindowFocus) Focused() string {
+	return focus.focused
+}
+
+// Area returns the area of the window with given name, used to render its content.
+// Several windows may share a name if they belong together.
+func (focus *WindowFocus) Area(name string) FocusArea {
+	return FocusArea{focus: focus, name: name}
+}
+
+// FocusArea is the content of a window that reports to a WindowFocus.
+type FocusArea struct {
+	focus *WindowFocus
+	name  string
+}
+
+// Render renders the given content as one group of the current window
+// and registers the window as hovered should the mouse be above the content.
+// A zero area renders the content without reporting.
+func (area FocusArea) Render(content func()) {
+	if area.focus == nil {
+		content()
+		return
+	}
+	imgui.BeginGroup()
+	content()
+	imgui.EndGroup()
+	if imgui.IsItemHoveredV(imgui.HoveredFlagsAllowWhenBlockedByActiveItem) {
+		area.focus.hovered = area.name
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/inkyblackness/imgui-go"
 
 	"github.com/inkyblackness/hacked/editor/external"
+	"github.com/inkyblackness/hacked/editor/render"
 	"github.com/inkyblackness/hacked/ss1/content/audio"
 	"github.com/inkyblackness/hacked/ss1/edit"
 	"github.com/inkyblackness/hacked/ss1/edit/undoable"
@@ -21,6 +22,7 @@ type View struct {
 	modalStateMachine gui.ModalStateMachine
 	clipboard         external.Clipboard
 	guiScale          float32
+	focus             render.FocusArea
 
 	model viewModel
 }
@@ -28,13 +30,14 @@ type View struct {
 // NewTextsView returns a new instance.
 func NewTextsView(textService undoable.AugmentedTextService,
 	modalStateMachine gui.ModalStateMachine, clipboard external.Clipboard,
-	guiScale float32) *View {
+	guiScale float32, focus render.FocusArea) *View {
 	view := &View{
 		textService: textService,
 
 		modalStateMachine: modalStateMachine,
 		clipboard:         clipboard,
 		guiScale:          guiScale,
+		focus:             focus,
 
 		model: freshViewModel(),
 	}
@@ -54,9 +57,9 @@ func (view *View) Render() {
 		view.model.windowOpen = true
 	}
 	if view.model.windowOpen {
-		imgui.SetNextWindowSizeV(imgui.Vec2{X: 400 * view.guiScale, Y: 300 * view.guiScale}, imgui.ConditionOnce)
+		imgui.SetNextWindowSizeV(render.LayoutMetricsFor(view.guiScale).CompactWindowSize(), imgui.ConditionOnce)
 		if imgui.BeginV("Texts", view.WindowOpen(), imgui.WindowFlagsNoCollapse) {
-			view.renderContent()
+			view.focus.Render(func() { view.renderContent() })
 		}
 		imgui.End()
 	}
@@ -64,7 +67,7 @@ func (view *View) Render() {
 
 func (view *View) renderContent() {
 	knownTexts := edit.KnownTexts()
-	imgui.PushItemWidth(-100 * view.guiScale)
+	imgui.PushItemWidth(render.LayoutMetricsFor(view.guiScale).NarrowLabelSpace())
 	if imgui.BeginCombo("Text Type", knownTexts.Title(view.model.currentKey.ID)) {
 		for _, info := range knownTexts {
 			if imgui.SelectableV(info.Title, info.ID == view.model.currentKey.ID, 0, imgui.Vec2{}) {
@@ -108,7 +111,7 @@ func (view *View) renderContent() {
 	imgui.PopItemWidth()
 
 	currentText := view.currentText()
-	imgui.BeginChildV("Text", imgui.Vec2{X: -100 * view.guiScale, Y: 0}, true, 0)
+	imgui.BeginChildV("Text", imgui.Vec2{X: render.LayoutMetricsFor(view.guiScale).ButtonColumnSpace(), Y: 0}, true, 0)
 	imgui.PushTextWrapPos()
 	if len(currentText) == 0 {
 		imgui.PushStyleColor(imgui.StyleColorText, imgui.Vec4{X: 1.0, Y: 1.0, Z: 1.0, W: 0.5})
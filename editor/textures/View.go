@@ -2,6 +2,7 @@ package textures
 
 import (
 	"fmt"
+	"image"
 	"math"
 
 	"github.com/inkyblackness/imgui-go"
@@ -18,39 +19,45 @@ import (
 	"github.com/inkyblackness/hacked/ss1/world"
 	"github.com/inkyblackness/hacked/ss1/world/ids"
 	"github.com/inkyblackness/hacked/ui/gui"
+	"github.com/inkyblackness/hacked/ui/opengl"
 )
 
+// importConcurrency is the number of workers mapping images of a folder import. Zero uses one worker per CPU.
+const importConcurrency = 0
+
 // View provides edit controls for textures.
 type View struct {
 	mod          *world.Mod
 	textCache    *text.Cache
-	cp           text.Codepage
+	codepages    *text.Codepages
 	imageCache   *graphics.TextureCache
 	paletteCache *graphics.PaletteCache
 
 	modalStateMachine gui.ModalStateMachine
 	clipboard         external.Clipboard
 	guiScale          float32
+	focus             render.FocusArea
 	commander         cmd.Commander
 
 	model viewModel
 }
 
 // NewTexturesView returns a new instance.
-func NewTexturesView(mod *world.Mod, textCache *text.Cache, cp text.Codepage,
+func NewTexturesView(mod *world.Mod, textCache *text.Cache, codepages *text.Codepages,
 	imageCache *graphics.TextureCache, paletteCache *graphics.PaletteCache,
 	modalStateMachine gui.ModalStateMachine,
-	clipboard external.Clipboard, guiScale float32, commander cmd.Commander) *View {
+	clipboard external.Clipboard, guiScale float32, focus render.FocusArea, commander cmd.Commander) *View {
 	view := &View{
 		mod:          mod,
 		textCache:    textCache,
-		cp:           cp,
+		codepages:    codepages,
 		imageCache:   imageCache,
 		paletteCache: paletteCache,
 
 		modalStateMachine: modalStateMachine,
 		clipboard:         clipboard,
 		guiScale:          guiScale,
+		focus:             focus,
 		commander:         commander,
 
 		model: freshViewModel(),
@@ -71,17 +78,17 @@ func (view *View) Render() {
 		view.model.windowOpen = true
 	}
 	if view.model.windowOpen {
-		imgui.SetNextWindowSizeV(imgui.Vec2{X: 800 * view.guiScale, Y: 300 * view.guiScale}, imgui.ConditionOnce)
+		imgui.SetNextWindowSizeV(render.LayoutMetricsFor(view.guiScale).WideWindowSize(), imgui.ConditionOnce)
 		if imgui.BeginV("Textures", view.WindowOpen(), imgui.WindowFlagsNoCollapse|imgui.WindowFlagsHorizontalScrollbar) {
-			view.renderContent()
+			view.focus.Render(func() { view.renderContent() })
 		}
 		imgui.End()
 	}
 }
 
 func (view *View) renderContent() {
-	if imgui.BeginChildV("Properties", imgui.Vec2{X: -300 * view.guiScale, Y: 0}, false, imgui.WindowFlagsHorizontalScrollbar) {
-		imgui.PushItemWidth(-200 * view.guiScale)
+	if imgui.BeginChildV("Properties", imgui.Vec2{X: render.LayoutMetricsFor(view.guiScale).SampleSpace(), Y: 0}, false, imgui.WindowFlagsHorizontalScrollbar) {
+		imgui.PushItemWidth(render.LayoutMetricsFor(view.guiScale).WideLabelSpace())
 
 		gui.StepSliderInt("Index", &view.model.currentIndex, 0, world.MaxWorldTextures-1)
 
@@ -142,7 +149,8 @@ func (view *View) clipboardPopup(readOnly bool, label string, value string, chan
 		if imgui.Selectable("Copy to Clipboard") {
 			view.clipboard.SetString(value)
 		}
-		if !readOnly && imgui.Selectable("Copy from Clipboard") {
+		pasteAvailable := view.clipboard.Content() == opengl.ClipboardContentText
+		if !readOnly && imgui.MenuItemV("Copy from Clipboard", "", false, pasteAvailable) {
 			newValue, err := view.clipboard.String()
 			if err == nil {
 				changeCallback(newValue)
@@ -219,10 +227,11 @@ func (view *View) renderTextureProperties(readOnly bool) {
 }
 
 func (view *View) renderTextureSample(label string, id resource.ID, sideLength float32, sizeID string) {
-	if imgui.BeginChildV(label, imgui.Vec2{X: -1, Y: (128 + 7) * view.guiScale}, true, imgui.WindowFlagsNoScrollbar) {
+	metrics := render.LayoutMetricsFor(view.guiScale)
+	if imgui.BeginChildV(label, imgui.Vec2{X: -1, Y: metrics.Scaled(128 + 7)}, true, imgui.WindowFlagsNoScrollbar) {
 		key := view.indexedResourceKey(id, view.model.currentIndex)
 		render.TextureImage("Texture Bitmap", view.imageCache, key,
-			imgui.Vec2{X: sideLength * view.guiScale, Y: sideLength * view.guiScale})
+			metrics.Vec2(sideLength, sideLength))
 
 		imgui.SameLine()
 		imgui.BeginGroup()
@@ -234,7 +243,14 @@ func (view *View) renderTextureSample(label string, id resource.ID, sideLength f
 		}
 		imgui.SameLine()
 		if imgui.Button("Import") {
-			view.requestImport(id, view.model.currentIndex)
+			view.requestImport(id, view.model.currentIndex, int(sideLength))
+		}
+		imgui.SameLine()
+		if imgui.Button("Import Folder") {
+			view.requestImportFolder(id, view.model.currentIndex, int(sideLength))
+		}
+		if imgui.IsItemHovered() {
+			imgui.SetTooltip("Import all images of a folder, starting with the current texture.")
 		}
 		if err == nil {
 			if imgui.Button("Export") {
@@ -313,8 +329,8 @@ func (view *View) requestSetTextureText(id resource.ID, newValue string) {
 		command := setTextureTextCommand{
 			model:   &view.model,
 			key:     key,
-			oldData: view.cp.Encode(oldValue),
-			newData: view.cp.Encode(text.Blocked(newValue)[0]),
+			oldData: view.codepages.ForLanguage(key.Lang).Encode(oldValue),
+			newData: view.codepages.ForLanguage(key.Lang).Encode(text.Blocked(newValue)[0]),
 		}
 		view.commander.Queue(command)
 	}
@@ -345,7 +361,7 @@ func (view *View) requestExport(id resource.ID, index int, sizeID string) {
 	external.ExportImage(view.modalStateMachine, filename, bmp)
 }
 
-func (view *View) requestImport(id resource.ID, index int) {
+func (view *View) requestImport(id resource.ID, index int, sideLength int) {
 	paletteRetriever := func() (bitmap.Palette, error) {
 		palette, err := view.paletteCache.Palette(0)
 		if err != nil {
@@ -354,9 +370,32 @@ func (view *View) requestImport(id resource.ID, index int) {
 		return palette.Palette(), nil
 	}
 
-	external.ImportImage(view.modalStateMachine, paletteRetriever, func(bmp bitmap.Bitmap) {
-		view.requestSetBitmap(id, index, bmp)
-	})
+	external.ImportImageSized(view.modalStateMachine, paletteRetriever, image.Pt(sideLength, sideLength),
+		&view.model.imageColorMetric, func(bmp bitmap.Bitmap) {
+			view.requestSetBitmap(id, index, bmp)
+		})
+}
+
+func (view *View) requestImportFolder(id resource.ID, index int, sideLength int) {
+	paletteRetriever := func() (bitmap.Palette, error) {
+		palette, err := view.paletteCache.Palette(0)
+		if err != nil {
+			return bitmap.Palette{}, err
+		}
+		return palette.Palette(), nil
+	}
+	limit := world.MaxWorldTextures - index
+	external.ImportImageFolder(view.modalStateMachine, paletteRetriever, image.Pt(sideLength, sideLength),
+		limit, importConcurrency, &view.model.folderColorMetric,
+		func(bitmaps []bitmap.Bitmap) {
+			var commands cmd.List
+			for offset := range bitmaps {
+				commands = append(commands, view.setBitmapDataCommand(id, index+offset, textureBitmapData(bitmaps[offset])))
+			}
+			if len(commands) > 0 {
+				view.commander.Queue(commands)
+			}
+		})
 }
 
 func (view *View) requestClear(id resource.ID, index int, sideLength int) {
@@ -371,6 +410,10 @@ func (view *View) requestClear(id resource.ID, index int, sideLength int) {
 }
 
 func (view *View) requestSetBitmap(id resource.ID, index int, bmp bitmap.Bitmap) {
+	view.requestSetBitmapData(id, index, textureBitmapData(bmp))
+}
+
+func textureBitmapData(bmp bitmap.Bitmap) []byte {
 	highestBitShift := func(value int16) (result byte) {
 		if value != 0 {
 			for (value >> result) != 1 {
@@ -385,18 +428,20 @@ func (view *View) requestSetBitmap(id resource.ID, index int, bmp bitmap.Bitmap)
 	bmp.Header.WidthFactor = highestBitShift(bmp.Header.Width)
 	bmp.Header.HeightFactor = highestBitShift(bmp.Header.Height)
 	bmp.Header.Stride = uint16(bmp.Header.Width)
-	data := bitmap.Encode(&bmp, 0)
-	view.requestSetBitmapData(id, index, data)
+	return bitmap.Encode(&bmp, 0)
 }
 
 func (view *View) requestSetBitmapData(id resource.ID, index int, newData []byte) {
+	view.commander.Queue(view.setBitmapDataCommand(id, index, newData))
+}
+
+func (view *View) setBitmapDataCommand(id resource.ID, index int, newData []byte) setTextureBitmapCommand {
 	resourceKey := view.indexedResourceKey(id, index)
-	command := setTextureBitmapCommand{
+	return setTextureBitmapCommand{
 		model:        &view.model,
 		id:           id,
 		textureIndex: index,
 		oldData:      view.mod.ModifiedBlock(resource.LangAny, resourceKey.ID, resourceKey.Index),
 		newData:      newData,
 	}
-	view.commander.Queue(command)
 }
@@ -1,6 +1,7 @@
 package textures
 
 import (
+	"github.com/inkyblackness/hacked/ss1/content/bitmap"
 	"github.com/inkyblackness/hacked/ss1/resource"
 )
 
@@ -10,6 +11,9 @@ type viewModel struct {
 
 	currentLang  resource.Language
 	currentIndex int
+
+	imageColorMetric  bitmap.ColorMetric
+	folderColorMetric bitmap.ColorMetric
 }
 
 func freshViewModel() viewModel {
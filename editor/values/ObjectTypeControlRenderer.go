@@ -5,8 +5,8 @@ import (
 
 	"github.com/inkyblackness/hacked/ss1/content/object"
 	"github.com/inkyblackness/hacked/ss1/content/text"
+	"github.com/inkyblackness/hacked/ss1/edit"
 	"github.com/inkyblackness/hacked/ss1/resource"
-	"github.com/inkyblackness/hacked/ss1/world/ids"
 )
 
 // ObjectTypeControlRenderer renders a control to select an object.
@@ -59,9 +59,8 @@ func (renderer ObjectTypeControlRenderer) Render(readOnly, multiple bool, label
 
 func (renderer ObjectTypeControlRenderer) tripleName(triple object.Triple) string {
 	suffix := "???"
-	linearIndex := renderer.Meta.TripleIndex(triple)
-	if (linearIndex >= 0) && (renderer.TextCache != nil) {
-		key := resource.KeyOf(ids.ObjectLongNames, resource.LangDefault, linearIndex)
+	key, known := edit.ObjectNameKey(renderer.Meta, triple, resource.LangDefault, true)
+	if known && (renderer.TextCache != nil) {
 		objName, err := renderer.TextCache.Text(key)
 		if err == nil {
 			suffix = objName
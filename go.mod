@@ -1,14 +1,20 @@
 module github.com/inkyblackness/hacked
 
-go 1.12
+go 1.18
 
 require (
 	github.com/go-gl/gl v0.0.0-20181026044259-55b76b7df9d2
 	github.com/go-gl/glfw v0.0.0-20190217072633-93b30450e032
 	github.com/go-gl/mathgl v0.0.0-20180804195959-cdf14b6b8f8a
-	github.com/gotk3/gotk3 v0.0.0-20190302104302-a9edcaa2ef15 // indirect
 	github.com/inkyblackness/imgui-go v1.7.0
 	github.com/sqweek/dialog v0.0.0-20190209060818-302ed2f52949
 	github.com/stretchr/testify v1.3.0
+)
+
+require (
+	github.com/TheTitanrain/w32 v0.0.0-20180517000239-4f5cfb03fabf // indirect
+	github.com/davecgh/go-spew v1.1.0 // indirect
+	github.com/gotk3/gotk3 v0.0.0-20190302104302-a9edcaa2ef15 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
 	golang.org/x/image v0.0.0-20190227222117-0694c2d4d067 // indirect
 )
@@ -0,0 +1,108 @@
+package headless
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/inkyblackness/hacked/ss1/edit/undoable/cmd"
+)
+
+// ErrUnknownOperation is returned for batch commands with an operation that has no registered builder.
+var ErrUnknownOperation = errors.New("unknown operation")
+
+// BatchCommand is the serializable representation of one edit.
+type BatchCommand struct {
+	// Op names the operation, such as "setObjectName".
+	Op string `json:"op"`
+	// Args contains the arguments of the operation. Their layout depends on the operation.
+	Args json.RawMessage `json:"args,omitempty"`
+}
+
+// Batch is a sequence of edits that are applied in order.
+type Batch []BatchCommand
+
+// ReadBatch decodes a batch from its JSON representation, which is an array of commands.
+func ReadBatch(reader io.Reader) (Batch, error) {
+	var batch Batch
+	decoder := json.NewDecoder(reader)
+	decoder.DisallowUnknownFields()
+	err := decoder.Decode(&batch)
+	if err != nil {
+		return nil, err
+	}
+	return batch, nil
+}
+
+// BatchError describes the command that failed a batch.
+type BatchError struct {
+	// Index is the position of the failed command within the batch.
+	Index int
+	// Op is the operation of the failed command.
+	Op string
+	// Err is the reason for the failure.
+	Err error
+}
+
+// Error returns the description of the failure.
+func (err BatchError) Error() string {
+	return fmt.Sprintf("batch command %d (%v): %v", err.Index, err.Op, err.Err)
+}
+
+// Unwrap returns the reason for the failure.
+func (err BatchError) Unwrap() error {
+	return err.Err
+}
+
+// CommandBuilder creates a concrete command from the arguments of a batch command.
+// The builder is called right before the command is performed, so the current state of the session
+// reflects all previous commands of the batch.
+type CommandBuilder func(session *Session, args json.RawMessage) (cmd.Command, error)
+
+// Dispatcher builds concrete commands from batch commands and applies them to a session.
+type Dispatcher struct {
+	builders map[string]CommandBuilder
+}
+
+// NewDispatcher returns a dispatcher that knows all standard operations.
+func NewDispatcher() *Dispatcher {
+	dispatcher := &Dispatcher{builders: make(map[string]CommandBuilder)}
+	for op, builder := range standardBuilders {
+		dispatcher.Register(op, builder)
+	}
+	return dispatcher
+}
+
+// Register sets the builder for given operation, replacing any previous one.
+func (dispatcher *Dispatcher) Register(op string, builder CommandBuilder) {
+	dispatcher.builders[op] = builder
+}
+
+// Build returns the concrete command for given batch command.
+func (dispatcher *Dispatcher) Build(session *Session, command BatchCommand) (cmd.Command, error) {
+	builder, known := dispatcher.builders[command.Op]
+	if !known {
+		return nil, ErrUnknownOperation
+	}
+	return builder(session, command.Args)
+}
+
+// Apply builds and performs all commands of the batch in order.
+// If any command can not be built or performed, the commands performed so far are undone
+// and a BatchError is returned that identifies the failed command.
+func (dispatcher *Dispatcher) Apply(session *Session, batch Batch) error {
+	for index, entry := range batch {
+		command, err := dispatcher.Build(session, entry)
+		if err == nil {
+			err = session.Perform(command)
+		}
+		if err != nil {
+			for performed := 0; performed < index; performed++ {
+				_ = session.Undo()
+			}
+			return BatchError{Index: index, Op: entry.Op, Err: err}
+		}
+	}
+	return nil
+}
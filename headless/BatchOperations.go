@@ -0,0 +1,270 @@
+package headless
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/inkyblackness/hacked/ss1/content/archive"
+	"github.com/inkyblackness/hacked/ss1/content/archive/level"
+	"github.com/inkyblackness/hacked/ss1/content/archive/level/lvlids"
+	"github.com/inkyblackness/hacked/ss1/content/audio/wav"
+	"github.com/inkyblackness/hacked/ss1/content/object"
+	"github.com/inkyblackness/hacked/ss1/content/text"
+	"github.com/inkyblackness/hacked/ss1/edit"
+	"github.com/inkyblackness/hacked/ss1/edit/undoable/cmd"
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/world"
+	"github.com/inkyblackness/hacked/ss1/world/ids"
+)
+
+var standardBuilders = map[string]CommandBuilder{
+	"setText":         buildSetText,
+	"setObjectName":   buildSetObjectName,
+	"setTextureName":  buildSetTextureName,
+	"assignTexture":   buildAssignTexture,
+	"importTextAudio": buildImportTextAudio,
+}
+
+// modderCommand performs modifications given as functions.
+type modderCommand struct {
+	forward func(world.Modder)
+	reverse func(world.Modder)
+}
+
+func (command modderCommand) Do(modder world.Modder) error {
+	command.forward(modder)
+	return nil
+}
+
+func (command modderCommand) Undo(modder world.Modder) error {
+	command.reverse(modder)
+	return nil
+}
+
+func decodeArgs(raw json.RawMessage, args interface{}) error {
+	if len(raw) == 0 {
+		return fmt.Errorf("missing arguments")
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	err := decoder.Decode(args)
+	if err != nil {
+		return fmt.Errorf("invalid arguments: %v", err)
+	}
+	return nil
+}
+
+// batchLanguage resolves the name of a language, such as "German". An empty name is the default language.
+func batchLanguage(name string) (resource.Language, error) {
+	if len(name) == 0 {
+		return resource.LangDefault, nil
+	}
+	for _, lang := range resource.Languages() {
+		if lang.String() == name {
+			return lang, nil
+		}
+	}
+	return resource.LangAny, fmt.Errorf("unknown language %q", name)
+}
+
+// setTextLineCommand creates a command that sets one entry of a text list resource.
+func (session *Session) setTextLineCommand(key resource.Key, value string) cmd.Command {
+	oldData := session.mod.ModifiedBlock(key.Lang, key.ID, key.Index)
+	newData := session.codepages.ForLanguage(key.Lang).Encode(text.Blocked(value)[0])
+	return modderCommand{
+		forward: func(modder world.Modder) {
+			modder.SetResourceBlock(key.Lang, key.ID, key.Index, newData)
+		},
+		reverse: func(modder world.Modder) {
+			modder.SetResourceBlock(key.Lang, key.ID, key.Index, oldData)
+		},
+	}
+}
+
+type setTextArgs struct {
+	ID    int    `json:"id"`
+	Index int    `json:"index"`
+	Lang  string `json:"lang"`
+	Text  string `json:"text"`
+}
+
+func buildSetText(session *Session, raw json.RawMessage) (cmd.Command, error) {
+	var args setTextArgs
+	err := decodeArgs(raw, &args)
+	if err != nil {
+		return nil, err
+	}
+	lang, err := batchLanguage(args.Lang)
+	if err != nil {
+		return nil, err
+	}
+	id := resource.ID(args.ID)
+	resourceInfo, known := ids.Info(id)
+	if !known || (resourceInfo.ContentType != resource.Text) {
+		return nil, fmt.Errorf("resource %v is not a text", id)
+	}
+	if (args.Index < 0) || ((resourceInfo.MaxCount > 0) && (args.Index >= resourceInfo.MaxCount)) {
+		return nil, fmt.Errorf("index %d out of range for resource %v", args.Index, id)
+	}
+	key := resource.KeyOf(id, lang, args.Index)
+	restore := session.textService.RestoreTextFunc(key)
+	return modderCommand{
+		forward: func(modder world.Modder) {
+			session.textService.SetText(modder, key, args.Text)
+		},
+		reverse: func(modder world.Modder) {
+			restore(modder)
+		},
+	}, nil
+}
+
+type setObjectNameArgs struct {
+	Class    int     `json:"class"`
+	Subclass int     `json:"subclass"`
+	Type     int     `json:"type"`
+	Lang     string  `json:"lang"`
+	Long     *string `json:"long"`
+	Short    *string `json:"short"`
+}
+
+func buildSetObjectName(session *Session, raw json.RawMessage) (cmd.Command, error) {
+	var args setObjectNameArgs
+	err := decodeArgs(raw, &args)
+	if err != nil {
+		return nil, err
+	}
+	lang, err := batchLanguage(args.Lang)
+	if err != nil {
+		return nil, err
+	}
+	if (args.Long == nil) && (args.Short == nil) {
+		return nil, fmt.Errorf("neither long nor short name given")
+	}
+	triple := object.TripleFrom(args.Class, args.Subclass, args.Type)
+	table := session.mod.ObjectProperties()
+	longKey, known := edit.ObjectNameKey(table, triple, lang, true)
+	if !known {
+		return nil, fmt.Errorf("unknown object %v", triple)
+	}
+	shortKey, _ := edit.ObjectNameKey(table, triple, lang, false)
+	var list cmd.List
+	if args.Long != nil {
+		list = append(list, session.setTextLineCommand(longKey, *args.Long))
+	}
+	if args.Short != nil {
+		list = append(list, session.setTextLineCommand(shortKey, *args.Short))
+	}
+	return list, nil
+}
+
+type setTextureNameArgs struct {
+	Index int     `json:"index"`
+	Lang  string  `json:"lang"`
+	Name  *string `json:"name"`
+	Usage *string `json:"usage"`
+}
+
+func buildSetTextureName(session *Session, raw json.RawMessage) (cmd.Command, error) {
+	var args setTextureNameArgs
+	err := decodeArgs(raw, &args)
+	if err != nil {
+		return nil, err
+	}
+	lang, err := batchLanguage(args.Lang)
+	if err != nil {
+		return nil, err
+	}
+	if (args.Name == nil) && (args.Usage == nil) {
+		return nil, fmt.Errorf("neither name nor usage given")
+	}
+	if (args.Index < 0) || (args.Index >= len(session.mod.TextureProperties())) {
+		return nil, fmt.Errorf("texture index %d out of range", args.Index)
+	}
+	var list cmd.List
+	if args.Name != nil {
+		list = append(list, session.setTextLineCommand(resource.KeyOf(ids.TextureNames, lang, args.Index), *args.Name))
+	}
+	if args.Usage != nil {
+		list = append(list, session.setTextLineCommand(resource.KeyOf(ids.TextureUsages, lang, args.Index), *args.Usage))
+	}
+	return list, nil
+}
+
+type assignTextureArgs struct {
+	Level      int `json:"level"`
+	AtlasIndex int `json:"atlasIndex"`
+	Texture    int `json:"texture"`
+}
+
+// buildAssignTexture sets an entry of the texture atlas of a level to a texture of the world.
+func buildAssignTexture(session *Session, raw json.RawMessage) (cmd.Command, error) {
+	var args assignTextureArgs
+	err := decodeArgs(raw, &args)
+	if err != nil {
+		return nil, err
+	}
+	if (args.Level < 0) || (args.Level >= archive.MaxLevels) {
+		return nil, fmt.Errorf("level %d out of range", args.Level)
+	}
+	if (args.Texture < 0) || (args.Texture >= len(session.mod.TextureProperties())) {
+		return nil, fmt.Errorf("texture index %d out of range", args.Texture)
+	}
+	lvl := level.NewLevel(ids.LevelResourcesStart, args.Level, session.mod)
+	if (args.AtlasIndex < 0) || (args.AtlasIndex >= len(lvl.TextureAtlas())) {
+		return nil, fmt.Errorf("atlas index %d out of range for level %d", args.AtlasIndex, args.Level)
+	}
+	lvl.SetTextureAtlasEntry(args.AtlasIndex, level.TextureIndex(args.Texture))
+	id := ids.LevelResourcesStart.Plus(lvlids.PerLevel*args.Level + lvlids.TextureAtlas)
+	oldData := session.mod.ModifiedBlock(resource.LangAny, id, 0)
+	newData := lvl.EncodeState()[lvlids.TextureAtlas]
+	return modderCommand{
+		forward: func(modder world.Modder) {
+			modder.SetResourceBlock(resource.LangAny, id, 0, newData)
+		},
+		reverse: func(modder world.Modder) {
+			modder.SetResourceBlock(resource.LangAny, id, 0, oldData)
+		},
+	}, nil
+}
+
+type importTextAudioArgs struct {
+	ID    int    `json:"id"`
+	Index int    `json:"index"`
+	Lang  string `json:"lang"`
+	File  string `json:"file"`
+}
+
+func buildImportTextAudio(session *Session, raw json.RawMessage) (cmd.Command, error) {
+	var args importTextAudioArgs
+	err := decodeArgs(raw, &args)
+	if err != nil {
+		return nil, err
+	}
+	lang, err := batchLanguage(args.Lang)
+	if err != nil {
+		return nil, err
+	}
+	key := resource.KeyOf(resource.ID(args.ID), lang, args.Index)
+	if !session.textService.WithAudio(key) {
+		return nil, fmt.Errorf("text %v has no audio", key.ID)
+	}
+	fileData, err := ioutil.ReadFile(args.File)
+	if err != nil {
+		return nil, err
+	}
+	sound, err := wav.Load(bytes.NewReader(fileData))
+	if err != nil {
+		return nil, fmt.Errorf("invalid sound file %v: %v", args.File, err)
+	}
+	restore := session.textService.RestoreSoundFunc(key)
+	return modderCommand{
+		forward: func(modder world.Modder) {
+			session.textService.SetSound(modder, key, sound)
+		},
+		reverse: func(modder world.Modder) {
+			restore(modder)
+		},
+	}, nil
+}
@@ -0,0 +1,124 @@
+package headless_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/headless"
+	"github.com/inkyblackness/hacked/ss1/content/archive/level"
+	"github.com/inkyblackness/hacked/ss1/content/object"
+	"github.com/inkyblackness/hacked/ss1/content/texture"
+	"github.com/inkyblackness/hacked/ss1/edit/undoable/cmd"
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/world"
+	"github.com/inkyblackness/hacked/ss1/world/ids"
+)
+
+func batchSession() *headless.Session {
+	session := headless.NewSession()
+	session.Mod().Reset(nil, object.StandardPropertiesTable(), make(texture.PropertiesList, 10))
+	return session
+}
+
+func readBatch(t *testing.T, source string) headless.Batch {
+	t.Helper()
+	batch, err := headless.ReadBatch(strings.NewReader(source))
+	require.Nil(t, err, "no error expected reading batch")
+	return batch
+}
+
+func TestDispatcherAppliesBatch(t *testing.T) {
+	session := batchSession()
+	batch := readBatch(t, `[
+		{"op": "setText", "args": {"id": 2151, "index": 1, "text": "trap"}},
+		{"op": "setObjectName", "args": {"class": 0, "subclass": 0, "type": 1, "lang": "German", "long": "Lang", "short": "Kurz"}},
+		{"op": "setTextureName", "args": {"index": 3, "name": "wall", "usage": "use it"}}
+	]`)
+
+	err := headless.NewDispatcher().Apply(session, batch)
+	require.Nil(t, err, "no error expected applying batch")
+
+	texts := session.TextService()
+	objectIndex := session.Mod().ObjectProperties().TripleIndex(object.TripleFrom(0, 0, 1))
+	assert.Equal(t, "trap", texts.Text(resource.KeyOf(ids.TrapMessageTexts, resource.LangDefault, 1)))
+	assert.Equal(t, "Lang", texts.Text(resource.KeyOf(ids.ObjectLongNames, resource.LangGerman, objectIndex)))
+	assert.Equal(t, "Kurz", texts.Text(resource.KeyOf(ids.ObjectShortNames, resource.LangGerman, objectIndex)))
+	assert.Equal(t, "wall", texts.Text(resource.KeyOf(ids.TextureNames, resource.LangDefault, 3)))
+	assert.Equal(t, "use it", texts.Text(resource.KeyOf(ids.TextureUsages, resource.LangDefault, 3)))
+}
+
+func TestDispatcherFailsBatchWithIndexAndReason(t *testing.T) {
+	tt := []struct {
+		name    string
+		command string
+		reason  string
+	}{
+		{name: "unknown operation", command: `{"op": "explode"}`, reason: "unknown operation"},
+		{name: "missing arguments", command: `{"op": "setText"}`, reason: "missing arguments"},
+		{name: "unknown argument", command: `{"op": "setText", "args": {"id": 2151, "colour": 1}}`, reason: "invalid arguments"},
+		{name: "unknown language", command: `{"op": "setText", "args": {"id": 2151, "lang": "Klingon"}}`, reason: "unknown language"},
+		{name: "not a text", command: `{"op": "setText", "args": {"id": 4000}}`, reason: "not a text"},
+		{name: "index out of range", command: `{"op": "setText", "args": {"id": 2151, "index": 256}}`, reason: "out of range"},
+		{name: "unknown object", command: `{"op": "setObjectName", "args": {"class": 0, "subclass": 0, "type": 99, "long": "x"}}`, reason: "unknown object"},
+		{name: "no names", command: `{"op": "setObjectName", "args": {"class": 0}}`, reason: "neither"},
+		{name: "texture out of range", command: `{"op": "setTextureName", "args": {"index": 10, "name": "x"}}`, reason: "out of range"},
+		{name: "level out of range", command: `{"op": "assignTexture", "args": {"level": 16}}`, reason: "out of range"},
+		{name: "atlas of missing level", command: `{"op": "assignTexture", "args": {"level": 1, "atlasIndex": 0, "texture": 0}}`, reason: "out of range"},
+		{name: "assigned texture out of range", command: `{"op": "assignTexture", "args": {"level": 0, "texture": 10}}`, reason: "out of range"},
+		{name: "text without audio", command: `{"op": "importTextAudio", "args": {"id": 2152, "file": "x.wav"}}`, reason: "no audio"},
+	}
+
+	for _, tc := range tt {
+		td := tc
+		t.Run(td.name, func(t *testing.T) {
+			session := batchSession()
+			batch := readBatch(t, `[{"op": "setText", "args": {"id": 2151, "text": "first"}}, `+td.command+`]`)
+
+			err := headless.NewDispatcher().Apply(session, batch)
+			var batchErr headless.BatchError
+			require.True(t, errors.As(err, &batchErr), "batch error expected, got %v", err)
+			assert.Equal(t, 1, batchErr.Index)
+			assert.Contains(t, batchErr.Error(), td.reason)
+			assert.Equal(t, "", session.TextService().Text(resource.KeyOf(ids.TrapMessageTexts, resource.LangDefault, 0)),
+				"previous commands should be undone")
+		})
+	}
+}
+
+func TestDispatcherAssignsLevelTexture(t *testing.T) {
+	session := batchSession()
+	session.Mod().Modify(func(modder world.Modder) {
+		for id, data := range level.EmptyLevelData(level.EmptyLevelParameters{MapModifier: func(level.TileMap) {}}) {
+			if len(data) > 0 {
+				modder.SetResourceBlock(resource.LangAny, ids.LevelResourcesStart.Plus(id), 0, data)
+			}
+		}
+	})
+	batch := readBatch(t, `[{"op": "assignTexture", "args": {"level": 0, "atlasIndex": 5, "texture": 7}}]`)
+
+	err := headless.NewDispatcher().Apply(session, batch)
+	require.Nil(t, err, "no error expected applying batch")
+	lvl := level.NewLevel(ids.LevelResourcesStart, 0, session.Mod())
+	assert.Equal(t, level.TextureIndex(7), lvl.TextureAtlas()[5])
+
+	err = session.Undo()
+	require.Nil(t, err, "no error expected undoing")
+	lvl = level.NewLevel(ids.LevelResourcesStart, 0, session.Mod())
+	assert.Equal(t, level.TextureIndex(0), lvl.TextureAtlas()[5])
+}
+
+func TestDispatcherUsesRegisteredBuilders(t *testing.T) {
+	dispatcher := headless.NewDispatcher()
+	failure := errors.New("custom failure")
+	dispatcher.Register("custom", func(*headless.Session, json.RawMessage) (cmd.Command, error) {
+		return nil, failure
+	})
+
+	err := dispatcher.Apply(batchSession(), headless.Batch{{Op: "custom"}})
+	assert.True(t, errors.Is(err, failure), "custom error expected, got %v", err)
+}
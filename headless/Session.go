@@ -0,0 +1,169 @@
+package headless
+
+import (
+	"github.com/inkyblackness/hacked/ss1/content/movie"
+	"github.com/inkyblackness/hacked/ss1/content/text"
+	"github.com/inkyblackness/hacked/ss1/edit"
+	"github.com/inkyblackness/hacked/ss1/edit/media"
+	"github.com/inkyblackness/hacked/ss1/edit/undoable"
+	"github.com/inkyblackness/hacked/ss1/edit/undoable/cmd"
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/world"
+	"github.com/inkyblackness/hacked/ss1/world/persist"
+)
+
+// Session keeps a mod with its caches and command stack, the same way the editor does.
+type Session struct {
+	mod      *world.Mod
+	cmdStack *cmd.Stack
+	backup   world.FileBackup
+
+	saveOptions persist.SaveOptions
+
+	codepages     *text.Codepages
+	textLineCache *text.Cache
+	textPageCache *text.Cache
+	movieCache    *movie.Cache
+	textService   edit.AugmentedTextService
+
+	queueErr     error
+	loadProgress persist.ProgressFunc
+}
+
+// NewSession returns a new session with an empty mod.
+func NewSession() *Session {
+	session := &Session{
+		cmdStack:    new(cmd.Stack),
+		saveOptions: persist.DefaultSaveOptions(),
+	}
+	session.mod = world.NewMod(session.resourcesChanged, session.modReset)
+	session.codepages = text.NewCodepages(text.DefaultCodepage())
+	session.textLineCache = text.NewLineCache(session.codepages, session.mod)
+	session.textPageCache = text.NewPageCache(session.codepages, session.mod)
+	session.movieCache = movie.NewCache(session.mod)
+
+	textViewer := media.NewTextViewerService(session.textLineCache, session.textPageCache, session.mod)
+	textSetter := media.NewTextSetterService(session.codepages)
+	audioViewer := media.NewAudioViewerService(session.movieCache, session.mod)
+	audioSetter := media.NewAudioSetterService()
+	session.textService = edit.NewAugmentedTextService(textViewer, textSetter, audioViewer, audioSetter)
+	return session
+}
+
+// Mod returns the mod of the session.
+func (session *Session) Mod() *world.Mod {
+	return session.mod
+}
+
+// Codepages returns the codepages used to encode and decode texts.
+func (session *Session) Codepages() *text.Codepages {
+	return session.codepages
+}
+
+// SetLoadProgress sets the function that receives the progress of loading files.
+// It is used by AddManifestEntry and LoadMod. The function is optional and may be nil.
+func (session *Session) SetLoadProgress(progress persist.ProgressFunc) {
+	session.loadProgress = progress
+}
+
+// SetSaveOptions sets the options used by Save. A new session uses persist.DefaultSaveOptions().
+func (session *Session) SetSaveOptions(options persist.SaveOptions) {
+	session.saveOptions = options
+}
+
+// AddManifestEntry loads the given files and appends them as a new entry to the world manifest.
+// This is typically used to add the main data directory of the game.
+func (session *Session) AddManifestEntry(names ...string) error {
+	entry, err := persist.LoadManifestEntryWithProgress(names, session.loadProgress)
+	if err != nil {
+		return err
+	}
+	manifest := session.mod.World()
+	return manifest.InsertEntry(manifest.EntryCount(), entry)
+}
+
+// LoadMod loads the given files as the mod to work on.
+// Any previous changes, including the command history, are discarded.
+func (session *Session) LoadMod(names ...string) error {
+	return persist.LoadModWithProgress(session.mod, names, session.loadProgress)
+}
+
+// Save writes all modified files of the mod into the given path.
+// If the path is empty, the path the mod was loaded from is used.
+func (session *Session) Save(modPath string) error {
+	if len(modPath) == 0 {
+		modPath = session.mod.Path()
+	}
+	session.mod.FixListResources()
+	err := persist.SaveModTo(session.mod, modPath, &session.backup, session.saveOptions)
+	if err != nil {
+		return err
+	}
+	session.mod.SetPath(modPath)
+	session.mod.MarkSave()
+	return nil
+}
+
+// Perform executes the given command on the mod and keeps it for undo.
+func (session *Session) Perform(command cmd.Command) error {
+	// Without interaction, there is nothing to merge: each command is a step of its own.
+	defer session.cmdStack.Seal()
+	return session.modifyModByCommand(func(modder world.Modder) error {
+		return session.cmdStack.Perform(command, modder)
+	})
+}
+
+// Undo reverses the last performed command, if possible.
+func (session *Session) Undo() error {
+	if !session.cmdStack.CanUndo() {
+		return nil
+	}
+	return session.modifyModByCommand(session.cmdStack.Undo)
+}
+
+// Redo performs the last undone command again, if possible.
+func (session *Session) Redo() error {
+	if !session.cmdStack.CanRedo() {
+		return nil
+	}
+	return session.modifyModByCommand(session.cmdStack.Redo)
+}
+
+// Queue performs the given command immediately. This implements cmd.Commander for the undoable services.
+// The first error of any queued command is kept and can be retrieved with QueueError().
+func (session *Session) Queue(command cmd.Command) {
+	err := session.Perform(command)
+	if (err != nil) && (session.queueErr == nil) {
+		session.queueErr = err
+	}
+}
+
+// QueueError returns the first error of any queued command, and resets it.
+func (session *Session) QueueError() error {
+	err := session.queueErr
+	session.queueErr = nil
+	return err
+}
+
+// TextService returns a service for reading and modifying texts.
+// Requests of this service are queued to the session.
+func (session *Session) TextService() undoable.AugmentedTextService {
+	return undoable.NewAugmentedTextService(session.textService, session)
+}
+
+func (session *Session) modifyModByCommand(modifier func(world.Modder) error) (err error) {
+	session.mod.Modify(func(modder world.Modder) {
+		err = modifier(modder)
+	})
+	return
+}
+
+func (session *Session) resourcesChanged(modifiedIDs []resource.ID, failedIDs []resource.ID) {
+	session.textLineCache.InvalidateResources(modifiedIDs)
+	session.textPageCache.InvalidateResources(modifiedIDs)
+	session.movieCache.InvalidateResources(modifiedIDs)
+}
+
+func (session *Session) modReset() {
+	session.cmdStack = new(cmd.Stack)
+}
@@ -0,0 +1,59 @@
+package headless_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/headless"
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/world/ids"
+	"github.com/inkyblackness/hacked/ss1/world/persist"
+)
+
+func TestSessionSavesAndLoadsModifiedTexts(t *testing.T) {
+	dir, err := ioutil.TempDir("", "headless")
+	require.Nil(t, err, "no error expected creating temp dir")
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	key := resource.KeyOf(ids.TrapMessageTexts, resource.LangDefault, 2)
+	session := headless.NewSession()
+	session.TextService().RequestSetText(key, "headless text", func() {})
+	require.Nil(t, session.QueueError(), "no error expected setting text")
+	require.True(t, session.Mod().HasUnsavedChanges(), "changes expected")
+
+	err = session.Save(dir)
+	require.Nil(t, err, "no error expected saving")
+	assert.False(t, session.Mod().HasUnsavedChanges(), "no changes expected after save")
+
+	loaded := headless.NewSession()
+	err = loaded.LoadMod(dir)
+	require.Nil(t, err, "no error expected loading")
+	assert.Equal(t, "headless text", loaded.TextService().Text(key))
+	assert.Equal(t, dir, loaded.Mod().Path())
+}
+
+func TestSessionUndoRevertsTextChange(t *testing.T) {
+	key := resource.KeyOf(ids.TrapMessageTexts, resource.LangDefault, 0)
+	session := headless.NewSession()
+	service := session.TextService()
+	service.RequestSetText(key, "first", func() {})
+	service.RequestSetText(key, "second", func() {})
+
+	require.Nil(t, session.Undo(), "no error expected undoing")
+	assert.Equal(t, "first", service.Text(key))
+	require.Nil(t, session.Redo(), "no error expected redoing")
+	assert.Equal(t, "second", service.Text(key))
+}
+
+func TestSessionLoadModFailsWithoutResources(t *testing.T) {
+	dir, err := ioutil.TempDir("", "headless")
+	require.Nil(t, err, "no error expected creating temp dir")
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	err = headless.NewSession().LoadMod(dir)
+	assert.Equal(t, persist.ErrNoResources, err)
+}
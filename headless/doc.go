@@ -0,0 +1,3 @@
+// Package headless provides access to the model and command layer of the editor without any user interface.
+// It is meant for scripts and tools that load a world, apply commands, and save the result.
+package headless
@@ -4,6 +4,7 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
 	"runtime/pprof"
 	"time"
 
@@ -21,12 +22,18 @@ func main() {
 	scale := flag.Float64("scale", 1.0, "factor for scaling the UI (0.5 .. 10.0). 1080p displays should use default. 4K most likely 2.0.")
 	fontFile := flag.String("fontfile", "", "Path to font file (.TTF) to use instead of the default font. Useful for HiDPI displays.")
 	fontSize := flag.Float64("fontsize", 0.0, "Size of the font to use. If not specified, a default height will be used.")
+	renderOnDemand := flag.Bool("ondemand", false, "Render only after input or changes, instead of continuously. Reduces idle CPU/GPU usage.")
+	layoutFile := flag.String("layout", userFile(os.UserConfigDir, "hacked-layout.json"), "Path to the file that keeps the state of the windows between sessions. Empty to disable.")
+	recoveryFile := flag.String("recovery", userFile(os.UserCacheDir, "hacked-recovery.zip"), "Path to the file that periodically keeps unsaved changes, for recovery after a crash. Empty to disable.")
 	cpuprofile := flag.String("cpuprofile", "", "write cpu profile to file")
 	flag.Parse()
 	var app editor.Application
 	app.FontFile = *fontFile
 	app.FontSize = float32(*fontSize)
 	app.GuiScale = float32(*scale)
+	app.LayoutFile = *layoutFile
+	app.RecoveryFile = *recoveryFile
+	app.RenderOnDemand = *renderOnDemand
 	if len(version) > 0 {
 		app.Version = version
 	} else {
@@ -43,12 +50,22 @@ func main() {
 	}
 	defer profileFin()
 
-	err = native.Run(app.InitializeWindow, versionInfo, 30.0, deferrer)
+	err = native.Run(app.InitializeWindow, versionInfo, app.InitialWindowGeometry(), 30.0, deferrer)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to run application: %v\n", err)
 	}
 }
 
+// userFile returns the path of given file within the per-user directory that dirFunc provides.
+// If that directory is not known, the filename is returned as is, which refers to the working directory.
+func userFile(dirFunc func() (string, error), filename string) string {
+	dir, err := dirFunc()
+	if err != nil {
+		return filename
+	}
+	return filepath.Join(dir, "InkyBlackness", "HackEd", filename)
+}
+
 func initProfiling(filename string) (func(), error) {
 	if filename != "" {
 		f, err := os.Create(filename)
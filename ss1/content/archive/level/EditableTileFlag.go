@@ -0,0 +1,238 @@
+package level
+
+import (
+	"fmt"
+	"strings"
+)
+
+// EditableTileFlag identifies a flag of a tile that can be set or cleared on its own.
+// Some flags share storage with others and are thus mutually exclusive: Setting one of them clears the others.
+//
+// Multi-valued properties, such as the flight pull in cyberspace and the music index, are represented
+// with one flag per value, see EditableTileFlagForFlightPull() and EditableTileFlagForMusicIndex().
+// Clearing such a flag resets the property to its default, if the flag was set.
+type EditableTileFlag byte
+
+// EditableTileFlag constants.
+const (
+	EditableTileFlagUseAdjacentWallTexture EditableTileFlag = 0
+	EditableTileFlagDeconstructed          EditableTileFlag = 1
+	EditableTileFlagVisited                EditableTileFlag = 2
+	EditableTileFlagCeilingMirrored        EditableTileFlag = 3
+	EditableTileFlagCeilingFlat            EditableTileFlag = 4
+	EditableTileFlagFloorFlat              EditableTileFlag = 5
+)
+
+const (
+	editableTileFlagFlightPullBase EditableTileFlag = 0x20
+	editableTileFlagMusicIndexBase EditableTileFlag = 0x40
+	editableTileFlagMusicIndexMax                   = 15
+)
+
+// EditableTileFlagForFlightPull returns the flag for the given flight pull in cyberspace.
+// The pull must not be CyberspaceFlightPullNone, which is the default the flags are cleared to.
+func EditableTileFlagForFlightPull(pull CyberspaceFlightPull) EditableTileFlag {
+	return editableTileFlagFlightPullBase + EditableTileFlag(pull)
+}
+
+// EditableTileFlagForMusicIndex returns the flag for the given music index. Range: [1..15].
+// Music index 0 is the default the flags are cleared to.
+func EditableTileFlagForMusicIndex(index int) EditableTileFlag {
+	return editableTileFlagMusicIndexBase + EditableTileFlag(index)
+}
+
+// EditableTileFlags returns all EditableTileFlag constants.
+func EditableTileFlags() []EditableTileFlag {
+	flags := []EditableTileFlag{
+		EditableTileFlagUseAdjacentWallTexture,
+		EditableTileFlagDeconstructed,
+		EditableTileFlagVisited,
+		EditableTileFlagCeilingMirrored,
+		EditableTileFlagCeilingFlat,
+		EditableTileFlagFloorFlat,
+	}
+	for _, pull := range CyberspaceFlightPulls() {
+		if pull != CyberspaceFlightPullNone {
+			flags = append(flags, EditableTileFlagForFlightPull(pull))
+		}
+	}
+	for index := 1; index <= editableTileFlagMusicIndexMax; index++ {
+		flags = append(flags, EditableTileFlagForMusicIndex(index))
+	}
+	return flags
+}
+
+// String returns the textual representation of the value.
+func (flag EditableTileFlag) String() string {
+	if pull, isPull := flag.flightPull(); isPull {
+		return "FlightPull" + strings.ReplaceAll(pull.String(), " ", "")
+	}
+	if index, isMusic := flag.musicIndex(); isMusic {
+		return fmt.Sprintf("MusicIndex%d", index)
+	}
+	switch flag {
+	case EditableTileFlagUseAdjacentWallTexture:
+		return "UseAdjacentWallTexture"
+	case EditableTileFlagDeconstructed:
+		return "Deconstructed"
+	case EditableTileFlagVisited:
+		return "Visited"
+	case EditableTileFlagCeilingMirrored:
+		return "CeilingMirrored"
+	case EditableTileFlagCeilingFlat:
+		return "CeilingFlat"
+	case EditableTileFlagFloorFlat:
+		return "FloorFlat"
+	default:
+		return fmt.Sprintf("Unknown%02X", int(flag))
+	}
+}
+
+// AvailableIn returns whether the flag has a meaning in the given kind of level.
+func (flag EditableTileFlag) AvailableIn(cyberspace bool) bool {
+	if _, isPull := flag.flightPull(); isPull {
+		return cyberspace
+	}
+	if _, isMusic := flag.musicIndex(); isMusic {
+		return true
+	}
+	switch flag {
+	case EditableTileFlagUseAdjacentWallTexture, EditableTileFlagDeconstructed, EditableTileFlagVisited:
+		return !cyberspace
+	case EditableTileFlagCeilingMirrored, EditableTileFlagCeilingFlat, EditableTileFlagFloorFlat:
+		return true
+	default:
+		return false
+	}
+}
+
+// ExclusiveWith returns true if the two flags can not be set at the same time.
+func (flag EditableTileFlag) ExclusiveWith(other EditableTileFlag) bool {
+	if flag == other {
+		return false
+	}
+	_, isSlope := flag.slopeControl()
+	_, otherIsSlope := other.slopeControl()
+	_, isPull := flag.flightPull()
+	_, otherIsPull := other.flightPull()
+	_, isMusic := flag.musicIndex()
+	_, otherIsMusic := other.musicIndex()
+	return (isSlope && otherIsSlope) || (isPull && otherIsPull) || (isMusic && otherIsMusic)
+}
+
+// IsSetIn returns whether the flag is set in given tile flags.
+func (flag EditableTileFlag) IsSetIn(value TileFlag) bool {
+	if ctrl, isSlope := flag.slopeControl(); isSlope {
+		return value.SlopeControl() == ctrl
+	}
+	if pull, isPull := flag.flightPull(); isPull {
+		return value.ForCyberspace().FlightPull() == pull
+	}
+	if index, isMusic := flag.musicIndex(); isMusic {
+		return value.MusicIndex() == index
+	}
+	realWorld := value.ForRealWorld()
+	switch flag {
+	case EditableTileFlagUseAdjacentWallTexture:
+		return realWorld.UseAdjacentWallTexture()
+	case EditableTileFlagDeconstructed:
+		return realWorld.Deconstructed()
+	case EditableTileFlagVisited:
+		return realWorld.TileVisited()
+	default:
+		return false
+	}
+}
+
+// AppliedTo returns the given tile flags with this flag set or cleared.
+// Clearing a slope control flag resets the slope control to its default, if the flag was set.
+func (flag EditableTileFlag) AppliedTo(value TileFlag, set bool) TileFlag {
+	if ctrl, isSlope := flag.slopeControl(); isSlope {
+		switch {
+		case set:
+			return value.WithSlopeControl(ctrl)
+		case value.SlopeControl() == ctrl:
+			return value.WithSlopeControl(TileSlopeControlCeilingInverted)
+		default:
+			return value
+		}
+	}
+	if pull, isPull := flag.flightPull(); isPull {
+		cyberspace := value.ForCyberspace()
+		switch {
+		case set:
+			return cyberspace.WithFlightPull(pull).AsTileFlag()
+		case cyberspace.FlightPull() == pull:
+			return cyberspace.WithFlightPull(CyberspaceFlightPullNone).AsTileFlag()
+		default:
+			return value
+		}
+	}
+	if index, isMusic := flag.musicIndex(); isMusic {
+		switch {
+		case set:
+			return value.WithMusicIndex(index)
+		case value.MusicIndex() == index:
+			return value.WithMusicIndex(0)
+		default:
+			return value
+		}
+	}
+	realWorld := value.ForRealWorld()
+	switch flag {
+	case EditableTileFlagUseAdjacentWallTexture:
+		return realWorld.WithUseAdjacentWallTexture(set).AsTileFlag()
+	case EditableTileFlagDeconstructed:
+		return realWorld.WithDeconstructed(set).AsTileFlag()
+	case EditableTileFlagVisited:
+		return realWorld.WithTileVisited(set).AsTileFlag()
+	default:
+		return value
+	}
+}
+
+func (flag EditableTileFlag) slopeControl() (TileSlopeControl, bool) {
+	switch flag {
+	case EditableTileFlagCeilingMirrored:
+		return TileSlopeControlCeilingMirrored, true
+	case EditableTileFlagCeilingFlat:
+		return TileSlopeControlCeilingFlat, true
+	case EditableTileFlagFloorFlat:
+		return TileSlopeControlFloorFlat, true
+	default:
+		return TileSlopeControlCeilingInverted, false
+	}
+}
+
+func (flag EditableTileFlag) flightPull() (CyberspaceFlightPull, bool) {
+	if (flag <= editableTileFlagFlightPullBase) || (flag > editableTileFlagFlightPullBase+EditableTileFlag(CyberspaceFlightPullStrongFloor)) {
+		return CyberspaceFlightPullNone, false
+	}
+	return CyberspaceFlightPull(flag - editableTileFlagFlightPullBase), true
+}
+
+func (flag EditableTileFlag) musicIndex() (int, bool) {
+	if (flag <= editableTileFlagMusicIndexBase) || (flag > editableTileFlagMusicIndexBase+editableTileFlagMusicIndexMax) {
+		return 0, false
+	}
+	return int(flag - editableTileFlagMusicIndexBase), true
+}
+
+// ValidateEditableTileFlags verifies that the given flags can be set together in the given kind of level.
+// An error is returned for unknown flags, flags not available in the level, or mutually exclusive flags.
+func ValidateEditableTileFlags(flags []EditableTileFlag, cyberspace bool) error {
+	for index, flag := range flags {
+		if !flag.AvailableIn(cyberspace) {
+			if cyberspace {
+				return fmt.Errorf("flag %v not available in cyberspace", flag)
+			}
+			return fmt.Errorf("flag %v not available", flag)
+		}
+		for _, other := range flags[index+1:] {
+			if flag.ExclusiveWith(other) {
+				return fmt.Errorf("flags %v and %v are mutually exclusive", flag, other)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package level_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/inkyblackness/hacked/ss1/content/archive/level"
+)
+
+func TestEditableTileFlagsCanBeSetAndCleared(t *testing.T) {
+	for _, flag := range level.EditableTileFlags() {
+		var value level.TileFlag
+		value = flag.AppliedTo(value, true)
+		assert.True(t, flag.IsSetIn(value), "flag %v should be set", flag)
+		value = flag.AppliedTo(value, false)
+		assert.False(t, flag.IsSetIn(value), "flag %v should be cleared", flag)
+		assert.Equal(t, level.TileFlag(0), value, "flag %v should leave no traces", flag)
+	}
+}
+
+func TestEditableTileFlagsKeepOtherBits(t *testing.T) {
+	value := level.TileFlag(0).WithMusicIndex(5)
+
+	value = level.EditableTileFlagDeconstructed.AppliedTo(value, true)
+
+	assert.Equal(t, 5, value.MusicIndex())
+}
+
+func TestEditableSlopeControlFlagsReplaceEachOther(t *testing.T) {
+	value := level.EditableTileFlagFloorFlat.AppliedTo(0, true)
+	value = level.EditableTileFlagCeilingFlat.AppliedTo(value, true)
+
+	assert.False(t, level.EditableTileFlagFloorFlat.IsSetIn(value))
+	assert.True(t, level.EditableTileFlagCeilingFlat.IsSetIn(value))
+	assert.Equal(t, value, level.EditableTileFlagFloorFlat.AppliedTo(value, false), "clearing unset flag should not change")
+}
+
+func TestValidateEditableTileFlags(t *testing.T) {
+	tt := []struct {
+		name       string
+		flags      []level.EditableTileFlag
+		cyberspace bool
+		valid      bool
+	}{
+		{name: "empty", valid: true},
+		{name: "independent", flags: []level.EditableTileFlag{level.EditableTileFlagDeconstructed, level.EditableTileFlagFloorFlat}, valid: true},
+		{name: "exclusive", flags: []level.EditableTileFlag{level.EditableTileFlagCeilingFlat, level.EditableTileFlagFloorFlat}, valid: false},
+		{name: "real world only in cyberspace", flags: []level.EditableTileFlag{level.EditableTileFlagVisited}, cyberspace: true, valid: false},
+		{name: "slope in cyberspace", flags: []level.EditableTileFlag{level.EditableTileFlagCeilingMirrored}, cyberspace: true, valid: true},
+		{name: "pull in real world", flags: []level.EditableTileFlag{level.EditableTileFlagForFlightPull(level.CyberspaceFlightPullWeakWest)}, valid: false},
+		{name: "pull and music in cyberspace", flags: []level.EditableTileFlag{
+			level.EditableTileFlagForFlightPull(level.CyberspaceFlightPullWeakWest), level.EditableTileFlagForMusicIndex(2)}, cyberspace: true, valid: true},
+		{name: "two music indices", flags: []level.EditableTileFlag{
+			level.EditableTileFlagForMusicIndex(1), level.EditableTileFlagForMusicIndex(2)}, valid: false},
+		{name: "unknown", flags: []level.EditableTileFlag{level.EditableTileFlag(0xFF)}, valid: false},
+	}
+
+	for _, tc := range tt {
+		td := tc
+		t.Run(td.name, func(t *testing.T) {
+			err := level.ValidateEditableTileFlags(td.flags, td.cyberspace)
+			assert.Equal(t, td.valid, err == nil, "unexpected result: %v", err)
+		})
+	}
+}
+
+func TestEditableTileFlagsForMultiValuedPropertiesReplaceEachOther(t *testing.T) {
+	weakEast := level.EditableTileFlagForFlightPull(level.CyberspaceFlightPullWeakEast)
+	strongFloor := level.EditableTileFlagForFlightPull(level.CyberspaceFlightPullStrongFloor)
+	value := weakEast.AppliedTo(0, true)
+	value = strongFloor.AppliedTo(value, true)
+
+	assert.Equal(t, level.CyberspaceFlightPullStrongFloor, value.ForCyberspace().FlightPull())
+	assert.False(t, weakEast.IsSetIn(value))
+	assert.True(t, weakEast.ExclusiveWith(strongFloor))
+	assert.Equal(t, value, weakEast.AppliedTo(value, false), "clearing unset flag should not change")
+
+	music := level.EditableTileFlagForMusicIndex(3)
+	value = music.AppliedTo(value, true)
+	assert.Equal(t, 3, value.MusicIndex())
+	assert.Equal(t, level.CyberspaceFlightPullStrongFloor, value.ForCyberspace().FlightPull(), "pull should be kept")
+	assert.True(t, music.ExclusiveWith(level.EditableTileFlagForMusicIndex(4)))
+	assert.False(t, music.ExclusiveWith(strongFloor))
+}
+
+func TestEditableTileFlagsOfCyberspaceAreOnlyAvailableThere(t *testing.T) {
+	pull := level.EditableTileFlagForFlightPull(level.CyberspaceFlightPullMediumNorth)
+	music := level.EditableTileFlagForMusicIndex(1)
+
+	assert.True(t, pull.AvailableIn(true))
+	assert.False(t, pull.AvailableIn(false))
+	assert.True(t, music.AvailableIn(true))
+	assert.True(t, music.AvailableIn(false))
+	assert.Equal(t, "FlightPullMediumNorth", pull.String())
+	assert.Equal(t, "MusicIndex1", music.String())
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/binary"
 	"errors"
+	"fmt"
 	"io"
 	"io/ioutil"
 
@@ -34,6 +35,9 @@ type Level struct {
 	surveillanceSources    [SurveillanceObjectCount]ObjectID
 	surveillanceSurrogates [SurveillanceObjectCount]ObjectID
 	parameters             Parameters
+
+	mapNotes        []byte
+	mapNotesPointer MapNotesPointer
 }
 
 // NewLevel returns a new instance.
@@ -59,6 +63,8 @@ func NewLevel(resourceBase resource.ID, id int, localizer resource.Localizer) *L
 	lvl.reloadSurveillanceSources()
 	lvl.reloadSurveillanceSurrogates()
 	lvl.reloadParameters()
+	lvl.reloadMapNotes()
+	lvl.reloadMapNotesPointer()
 
 	return lvl
 }
@@ -207,7 +213,7 @@ func (lvl *Level) NewObject(class object.Class) (ObjectID, error) {
 	id := lvl.objectMasterTable.Allocate()
 	if id == 0 {
 		classTable.Release(classIndex)
-		return 0, errors.New("no more room for objects")
+		return 0, fmt.Errorf("no more room for objects, the limit is %d", MaxObjectsPerLevel)
 	}
 	obj := &lvl.objectMasterTable[id]
 	classEntry := &classTable[classIndex]
@@ -329,6 +335,12 @@ func (lvl *Level) Object(id ObjectID) *ObjectMasterEntry {
 	return entry
 }
 
+// HasObject returns true if the identified object exists and is in use.
+func (lvl *Level) HasObject(id ObjectID) bool {
+	obj := lvl.Object(id)
+	return (obj != nil) && (obj.InUse != 0)
+}
+
 // ObjectClassData returns the raw class data for the given object.
 func (lvl *Level) ObjectClassData(id ObjectID) []byte {
 	obj := lvl.Object(id)
@@ -365,6 +377,10 @@ func (lvl *Level) EncodeState() [lvlids.PerLevel][]byte {
 	levelData[lvlids.SurveillanceSources] = encode(&lvl.surveillanceSources)
 	levelData[lvlids.SurveillanceSurrogates] = encode(&lvl.surveillanceSurrogates)
 	levelData[lvlids.Parameters] = encode(&lvl.parameters)
+	if lvl.mapNotes != nil {
+		levelData[lvlids.MapNotes] = encode(lvl.mapNotes)
+		levelData[lvlids.MapNotesPointer] = encode(lvl.mapNotesPointer)
+	}
 
 	return levelData
 }
@@ -389,6 +405,10 @@ func (lvl *Level) onLevelResourceDataChanged(id int) {
 		lvl.reloadSurveillanceSurrogates()
 	case lvlids.Parameters:
 		lvl.reloadParameters()
+	case lvlids.MapNotes:
+		lvl.reloadMapNotes()
+	case lvlids.MapNotesPointer:
+		lvl.reloadMapNotesPointer()
 	}
 	if (id >= lvlids.ObjectClassTablesStart) && (id < (lvlids.ObjectClassTablesStart + len(lvl.objectClassTables))) {
 		lvl.reloadObjectClassTable(object.Class(id - lvlids.ObjectClassTablesStart))
@@ -551,6 +571,29 @@ func (lvl *Level) reloadParameters() {
 	}
 }
 
+func (lvl *Level) reloadMapNotes() {
+	reader, err := lvl.reader(lvlids.MapNotes)
+	var data []byte
+	if err == nil {
+		data, err = ioutil.ReadAll(reader)
+	}
+	if err != nil {
+		lvl.mapNotes = nil
+		return
+	}
+	lvl.mapNotes = data
+}
+
+func (lvl *Level) reloadMapNotesPointer() {
+	reader, err := lvl.reader(lvlids.MapNotesPointer)
+	if err == nil {
+		err = binary.Read(reader, binary.LittleEndian, &lvl.mapNotesPointer)
+	}
+	if err != nil {
+		lvl.mapNotesPointer = 0
+	}
+}
+
 func (lvl *Level) clearTileMap() {
 	for _, row := range lvl.tileMap {
 		for i := 0; i < len(row); i++ {
@@ -0,0 +1,50 @@
+package level_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/content/archive/level"
+	"github.com/inkyblackness/hacked/ss1/content/archive/level/leveltest"
+	"github.com/inkyblackness/hacked/ss1/content/object"
+)
+
+func TestLevelHasObject(t *testing.T) {
+	lvl := leveltest.NewLevel(t, level.EmptyLevelParameters{})
+	used, err := lvl.NewObject(object.ClassTrap)
+	require.Nil(t, err, "no error expected creating object")
+	deleted, err := lvl.NewObject(object.ClassTrap)
+	require.Nil(t, err, "no error expected creating object")
+	lvl.DelObject(deleted)
+	limit := lvl.ObjectLimit()
+	require.True(t, limit > deleted, "limit must be beyond the created objects")
+
+	tt := []struct {
+		name     string
+		id       level.ObjectID
+		expected bool
+	}{
+		{name: "zero", id: 0, expected: false},
+		{name: "used", id: used, expected: true},
+		{name: "deleted", id: deleted, expected: false},
+		{name: "never used", id: deleted + 1, expected: false},
+		{name: "unused limit", id: limit, expected: false},
+		{name: "beyond limit", id: limit + 1, expected: false},
+	}
+	for _, tc := range tt {
+		td := tc
+		t.Run(td.name, func(t *testing.T) {
+			assert.Equal(t, td.expected, lvl.HasObject(td.id))
+		})
+	}
+}
+
+func TestLevelHasObjectAtLimitIfInUse(t *testing.T) {
+	lvl := leveltest.NewLevel(t, level.EmptyLevelParameters{})
+	limit := lvl.ObjectLimit()
+	lvl.Object(limit).InUse = 1
+
+	assert.True(t, lvl.HasObject(limit))
+}
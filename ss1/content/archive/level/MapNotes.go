@@ -1,9 +1,122 @@
 package level
 
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/inkyblackness/hacked/ss1/content/object"
+)
+
 const (
 	// MapNotesSize is the size, in bytes, of the map notes resource.
 	MapNotesSize = 0x0800
+
+	// mapNoteEntryOffsetStart is the position of the offset into the map notes within the class data of map notes.
+	mapNoteEntryOffsetStart = 18
 )
 
 // MapNotesPointer is an offset into the map notes resource.
 type MapNotesPointer uint32
+
+// MapNoteTriple identifies the objects that place a note on the automap.
+// Their class data refers to the text of the note, which is stored in the map notes resource.
+var MapNoteTriple = object.TripleFrom(int(object.ClassTrap), 2, 3)
+
+// ErrMapNotesExhausted is returned if texts of map notes would exceed the storage of the map notes resource.
+var ErrMapNotesExhausted = errors.New("map notes storage exhausted")
+
+// MapNote is an annotation on the automap of a level.
+type MapNote struct {
+	// ID identifies the map note object.
+	ID ObjectID
+	// X is the horizontal position of the note.
+	X Coordinate
+	// Y is the vertical position of the note.
+	Y Coordinate
+	// Text is the raw, encoded, text of the note, without terminator.
+	Text []byte
+}
+
+// MapNotes returns all notes of the level, in order of their object identifier.
+func (lvl *Level) MapNotes() []MapNote {
+	var notes []MapNote
+	for id := ObjectID(1); id <= lvl.ObjectLimit(); id++ {
+		if !lvl.isMapNote(id) {
+			continue
+		}
+		obj := lvl.Object(id)
+		notes = append(notes, MapNote{ID: id, X: obj.X, Y: obj.Y, Text: lvl.MapNoteText(id)})
+	}
+	return notes
+}
+
+// MapNoteText returns the raw text of the identified map note.
+// Returns nil if the object is not a map note, or the level has no map notes.
+func (lvl *Level) MapNoteText(id ObjectID) []byte {
+	if !lvl.isMapNote(id) {
+		return nil
+	}
+	offset := int(binary.LittleEndian.Uint32(lvl.ObjectClassData(id)[mapNoteEntryOffsetStart:]))
+	if offset >= len(lvl.mapNotes) {
+		return nil
+	}
+	text := lvl.mapNotes[offset:]
+	if end := bytes.IndexByte(text, 0x00); end >= 0 {
+		text = text[:end]
+	}
+	result := make([]byte, len(text))
+	copy(result, text)
+	return result
+}
+
+// MapNotesFree returns the number of bytes that are available for further text, including terminators.
+func (lvl *Level) MapNotesFree() int {
+	used := 0
+	for _, note := range lvl.MapNotes() {
+		used += len(note.Text) + 1
+	}
+	return MapNotesSize - used
+}
+
+// SetMapNoteText sets the raw text of the identified map note.
+// The map notes resource is created if the level has none. All texts are stored anew, without gaps.
+// An error is returned if the object is not a map note, the text contains a terminator,
+// or all texts would exceed the storage.
+func (lvl *Level) SetMapNoteText(id ObjectID, text []byte) error {
+	if !lvl.isMapNote(id) {
+		return fmt.Errorf("object %d is not a map note", id)
+	}
+	if bytes.IndexByte(text, 0x00) >= 0 {
+		return errors.New("text of map note must not contain a zero byte")
+	}
+	notes := lvl.MapNotes()
+	required := 0
+	for index := range notes {
+		if notes[index].ID == id {
+			notes[index].Text = text
+		}
+		required += len(notes[index].Text) + 1
+	}
+	if required > MapNotesSize {
+		return fmt.Errorf("%w: %d bytes required, %d available", ErrMapNotesExhausted, required, MapNotesSize)
+	}
+
+	buffer := make([]byte, MapNotesSize)
+	offset := 0
+	for _, note := range notes {
+		binary.LittleEndian.PutUint32(lvl.ObjectClassData(note.ID)[mapNoteEntryOffsetStart:], uint32(offset))
+		offset += copy(buffer[offset:], note.Text) + 1
+	}
+	lvl.mapNotes = buffer
+	lvl.mapNotesPointer = MapNotesPointer(offset)
+	return nil
+}
+
+func (lvl *Level) isMapNote(id ObjectID) bool {
+	if !lvl.HasObject(id) || (lvl.Object(id).Triple() != MapNoteTriple) {
+		return false
+	}
+	return len(lvl.ObjectClassData(id)) >= mapNoteEntryOffsetStart+4
+}
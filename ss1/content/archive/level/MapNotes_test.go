@@ -0,0 +1,95 @@
+package level_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/content/archive/level"
+	"github.com/inkyblackness/hacked/ss1/content/archive/level/leveltest"
+	"github.com/inkyblackness/hacked/ss1/content/archive/level/lvlids"
+	"github.com/inkyblackness/hacked/ss1/content/object"
+)
+
+func newMapNote(t *testing.T, lvl *level.Level, x, y byte) level.ObjectID {
+	t.Helper()
+	id, err := lvl.NewObject(object.ClassTrap)
+	require.Nil(t, err, "no error expected creating object")
+	obj := lvl.Object(id)
+	obj.Subclass = level.MapNoteTriple.Subclass
+	obj.Type = level.MapNoteTriple.Type
+	obj.X = level.CoordinateAt(x, 0x80)
+	obj.Y = level.CoordinateAt(y, 0x80)
+	return id
+}
+
+func TestLevelMapNotesCanBeSet(t *testing.T) {
+	lvl := leveltest.NewLevel(t, level.EmptyLevelParameters{})
+	first := newMapNote(t, lvl, 1, 2)
+	second := newMapNote(t, lvl, 3, 4)
+
+	require.Nil(t, lvl.SetMapNoteText(first, []byte("door code")), "no error expected setting first")
+	require.Nil(t, lvl.SetMapNoteText(second, []byte("beware")), "no error expected setting second")
+	require.Nil(t, lvl.SetMapNoteText(first, []byte("code 451")), "no error expected changing first")
+
+	notes := lvl.MapNotes()
+	require.Equal(t, 2, len(notes), "two notes expected")
+	assert.Equal(t, []byte("code 451"), notes[0].Text)
+	assert.Equal(t, byte(1), notes[0].X.Tile())
+	assert.Equal(t, []byte("beware"), notes[1].Text)
+	assert.Equal(t, byte(4), notes[1].Y.Tile())
+	assert.Equal(t, level.MapNotesSize-len("code 451")-len("beware")-2, lvl.MapNotesFree())
+}
+
+func TestLevelMapNotesAreEncoded(t *testing.T) {
+	lvl := leveltest.NewLevel(t, level.EmptyLevelParameters{})
+	id := newMapNote(t, lvl, 1, 2)
+	require.Nil(t, lvl.SetMapNoteText(id, []byte("hint")), "no error expected setting text")
+
+	state := lvl.EncodeState()
+
+	assert.Equal(t, level.MapNotesSize, len(state[lvlids.MapNotes]))
+	assert.Equal(t, []byte("hint\x00"), state[lvlids.MapNotes][:5])
+	assert.Equal(t, []byte{5, 0, 0, 0}, state[lvlids.MapNotesPointer])
+}
+
+func TestLevelSetMapNoteTextValidatesStorage(t *testing.T) {
+	lvl := leveltest.NewLevel(t, level.EmptyLevelParameters{})
+	first := newMapNote(t, lvl, 1, 2)
+	second := newMapNote(t, lvl, 3, 4)
+	require.Nil(t, lvl.SetMapNoteText(first, []byte{}), "no error expected for empty text")
+	long := make([]byte, level.MapNotesSize-2)
+	for index := range long {
+		long[index] = 'a'
+	}
+	require.Nil(t, lvl.SetMapNoteText(first, long), "no error expected for text filling storage")
+
+	err := lvl.SetMapNoteText(second, []byte("x"))
+
+	assert.True(t, errors.Is(err, level.ErrMapNotesExhausted), "exhausted storage expected, got %v", err)
+	assert.Equal(t, long, lvl.MapNoteText(first), "text should be unchanged")
+	assert.NotNil(t, lvl.SetMapNoteText(second, []byte{'a', 0x00}), "error expected for terminator in text")
+}
+
+func TestLevelSetMapNoteTextRejectsOtherObjects(t *testing.T) {
+	lvl := leveltest.NewLevel(t, level.EmptyLevelParameters{})
+	id, err := lvl.NewObject(object.ClassTrap)
+	require.Nil(t, err, "no error expected creating object")
+
+	assert.NotNil(t, lvl.SetMapNoteText(id, []byte("text")), "error expected")
+	assert.Nil(t, lvl.MapNoteText(id))
+}
+
+func TestLevelMapNotesAreCreatedIfMissing(t *testing.T) {
+	lvl := leveltest.NewLevel(t, level.EmptyLevelParameters{}, lvlids.MapNotes, lvlids.MapNotesPointer)
+	assert.Empty(t, lvl.EncodeState()[lvlids.MapNotes], "no map notes expected initially")
+	id := newMapNote(t, lvl, 1, 2)
+
+	require.Nil(t, lvl.SetMapNoteText(id, []byte("new")), "no error expected")
+
+	state := lvl.EncodeState()
+	assert.Equal(t, level.MapNotesSize, len(state[lvlids.MapNotes]))
+	assert.Equal(t, []byte("new"), lvl.MapNoteText(id))
+}
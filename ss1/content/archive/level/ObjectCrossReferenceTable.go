@@ -4,7 +4,8 @@ const (
 	// ObjectCrossReferenceEntrySize describes the size, in bytes, of a ObjectCrossReferenceEntry.
 	ObjectCrossReferenceEntrySize = 10
 
-	defaultObjectCrossReferenceEntryCount = 1600
+	// ObjectCrossReferenceEntryCount is the amount of entries in an object cross reference table of a level.
+	ObjectCrossReferenceEntryCount = 1600
 )
 
 // ObjectCrossReferenceEntry links objects and tiles.
@@ -29,7 +30,7 @@ type ObjectCrossReferenceTable []ObjectCrossReferenceEntry
 
 // DefaultObjectCrossReferenceTable returns an initialized table with a default size.
 func DefaultObjectCrossReferenceTable() ObjectCrossReferenceTable {
-	table := make(ObjectCrossReferenceTable, defaultObjectCrossReferenceEntryCount)
+	table := make(ObjectCrossReferenceTable, ObjectCrossReferenceEntryCount)
 	table.Reset()
 	return table
 }
@@ -6,7 +6,11 @@ const (
 	// ObjectMasterEntrySize describes the size, in bytes, of a ObjectMasterEntry.
 	ObjectMasterEntrySize = 27
 
-	defaultObjectMasterEntryCount = 872
+	// ObjectMasterEntryCount is the amount of entries in an object master table of a level.
+	ObjectMasterEntryCount = 872
+	// MaxObjectsPerLevel is the amount of objects a level can hold.
+	// It is one less than the amount of entries, as the first entry is reserved.
+	MaxObjectsPerLevel = ObjectMasterEntryCount - 1
 )
 
 // ObjectMasterEntry describes an object in the level.
@@ -55,7 +59,7 @@ type ObjectMasterTable []ObjectMasterEntry
 
 // DefaultObjectMasterTable returns an initialized table with a default size.
 func DefaultObjectMasterTable() ObjectMasterTable {
-	table := make(ObjectMasterTable, defaultObjectMasterEntryCount)
+	table := make(ObjectMasterTable, ObjectMasterEntryCount)
 	table.Reset()
 	return table
 }
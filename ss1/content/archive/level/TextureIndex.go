@@ -5,6 +5,8 @@ const (
 	DefaultTextureAtlasSize = 54
 	// FloorCeilingTextureLimit describes the amount of textures available for floors/ceilings.
 	FloorCeilingTextureLimit = 32
+	// WallTextureLimit describes the amount of textures available for walls.
+	WallTextureLimit = 64
 )
 
 // TextureIndex identifies one game texture.
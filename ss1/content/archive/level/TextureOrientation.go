@@ -0,0 +1,61 @@
+package level
+
+import (
+	"errors"
+	"fmt"
+)
+
+// TextureOrientation describes how the textures of a tile in the real world are oriented.
+type TextureOrientation struct {
+	// FloorRotations is the number of rotation steps for the floor texture. Valid range: [0..3].
+	FloorRotations int
+	// CeilingRotations is the number of rotation steps for the ceiling texture. Valid range: [0..3].
+	CeilingRotations int
+	// WallPattern specifies how the wall textures are mirrored.
+	WallPattern WallTexturePattern
+}
+
+// TextureOrientationOf returns the current orientation of the textures of given tile.
+func TextureOrientationOf(tile *TileMapEntry) TextureOrientation {
+	return TextureOrientation{
+		FloorRotations:   tile.Floor.TextureRotations(),
+		CeilingRotations: tile.Ceiling.TextureRotations(),
+		WallPattern:      tile.Flags.ForRealWorld().WallTexturePattern(),
+	}
+}
+
+// Rotated returns an orientation with the floor and ceiling textures rotated by given steps.
+// Positive steps rotate in the same direction as the rotations of the tile. The result is normalized to the valid range.
+func (orientation TextureOrientation) Rotated(steps int) TextureOrientation {
+	rotate := func(value int) int { return (4 + ((value + steps) % 4)) % 4 }
+	result := orientation
+	result.FloorRotations = rotate(orientation.FloorRotations)
+	result.CeilingRotations = rotate(orientation.CeilingRotations)
+	return result
+}
+
+// Validate returns an error if the orientation can not be used by the engine.
+// Cyberspace does not have textures, and the corresponding bits of the tile are used for other properties.
+func (orientation TextureOrientation) Validate(cyberspace bool) error {
+	if cyberspace {
+		return errors.New("texture orientation is not supported in cyberspace")
+	}
+	if (orientation.FloorRotations < 0) || (orientation.FloorRotations > 3) {
+		return fmt.Errorf("floor texture rotations %d out of range [0..3]", orientation.FloorRotations)
+	}
+	if (orientation.CeilingRotations < 0) || (orientation.CeilingRotations > 3) {
+		return fmt.Errorf("ceiling texture rotations %d out of range [0..3]", orientation.CeilingRotations)
+	}
+	if orientation.WallPattern > WallTexturePatternFlipAlternatingInverted {
+		return fmt.Errorf("wall texture pattern %v not supported", orientation.WallPattern)
+	}
+	return nil
+}
+
+// ApplyTo sets the orientation in given tile.
+// The orientation should be validated before, as values are otherwise normalized or cut off.
+func (orientation TextureOrientation) ApplyTo(tile *TileMapEntry) {
+	tile.Floor = tile.Floor.WithTextureRotations(orientation.FloorRotations)
+	tile.Ceiling = tile.Ceiling.WithTextureRotations(orientation.CeilingRotations)
+	tile.Flags = tile.Flags.ForRealWorld().WithWallTexturePattern(orientation.WallPattern).AsTileFlag()
+}
@@ -0,0 +1,119 @@
+package level_test
+
+import (
+	"testing"
+
+	"github.com/inkyblackness/hacked/ss1/content/archive/level"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTextureOrientationCanBeAppliedToTile(t *testing.T) {
+	var tile level.TileMapEntry
+	tile.Reset()
+	tile.Floor = tile.Floor.WithAbsoluteHeight(5)
+	tile.Flags = tile.Flags.ForRealWorld().WithWallTextureOffset(3).AsTileFlag()
+	orientation := level.TextureOrientation{
+		FloorRotations:   1,
+		CeilingRotations: 3,
+		WallPattern:      level.WallTexturePatternFlipAlternating,
+	}
+
+	orientation.ApplyTo(&tile)
+
+	assert.Equal(t, orientation, level.TextureOrientationOf(&tile))
+	assert.Equal(t, level.TileHeightUnit(5), tile.Floor.AbsoluteHeight(), "floor height should be kept")
+	assert.Equal(t, level.TileHeightUnit(3), tile.Flags.ForRealWorld().WallTextureOffset(), "wall offset should be kept")
+}
+
+func TestTextureOrientationRotatedIsNormalized(t *testing.T) {
+	orientation := level.TextureOrientation{FloorRotations: 3, CeilingRotations: 0}
+
+	assert.Equal(t, level.TextureOrientation{FloorRotations: 0, CeilingRotations: 1}, orientation.Rotated(1))
+	assert.Equal(t, level.TextureOrientation{FloorRotations: 2, CeilingRotations: 3}, orientation.Rotated(-1))
+}
+
+func TestTextureOrientationValidate(t *testing.T) {
+	tt := []struct {
+		name        string
+		orientation level.TextureOrientation
+		cyberspace  bool
+		valid       bool
+	}{
+		{name: "regular", orientation: level.TextureOrientation{FloorRotations: 3, CeilingRotations: 2}, valid: true},
+		{name: "flipped wall", orientation: level.TextureOrientation{WallPattern: level.WallTexturePatternFlipAlternatingInverted}, valid: true},
+		{name: "negative floor", orientation: level.TextureOrientation{FloorRotations: -1}, valid: false},
+		{name: "excess ceiling", orientation: level.TextureOrientation{CeilingRotations: 4}, valid: false},
+		{name: "unknown pattern", orientation: level.TextureOrientation{WallPattern: level.WallTexturePattern(4)}, valid: false},
+		{name: "cyberspace", orientation: level.TextureOrientation{}, cyberspace: true, valid: false},
+	}
+
+	for _, tc := range tt {
+		td := tc
+		t.Run(td.name, func(t *testing.T) {
+			err := td.orientation.Validate(td.cyberspace)
+			if td.valid {
+				assert.Nil(t, err, "no error expected")
+			} else {
+				assert.Error(t, err, "error expected")
+			}
+		})
+	}
+}
+
+func TestWallTexturePatternFlipped(t *testing.T) {
+	for _, pattern := range level.WallTexturePatterns() {
+		flipped := pattern.Flipped()
+		assert.NotEqual(t, pattern, flipped, "pattern %v should change", pattern)
+		assert.Equal(t, pattern, flipped.Flipped(), "flipping twice should restore %v", pattern)
+		for x := 0; x < 2; x++ {
+			assert.NotEqual(t, pattern.IsFlippedAt(x, 1), flipped.IsFlippedAt(x, 1), "mirroring of %v at %d should invert", pattern, x)
+		}
+	}
+}
+
+func TestWallTextureStyleCanBeAppliedToTile(t *testing.T) {
+	var tile level.TileMapEntry
+	tile.Reset()
+	tile.Flags = tile.Flags.ForRealWorld().WithFloorShadow(7).AsTileFlag()
+	style := level.WallTextureStyle{
+		Offset:      12,
+		UseAdjacent: true,
+		Pattern:     level.WallTexturePatternFlipHorizontal,
+	}
+
+	style.ApplyTo(&tile)
+
+	assert.Equal(t, style, level.WallTextureStyleOf(&tile))
+	assert.Equal(t, 7, tile.Flags.ForRealWorld().FloorShadow(), "floor shadow should be kept")
+}
+
+func TestValidateWallTexture(t *testing.T) {
+	tt := []struct {
+		name       string
+		index      int
+		style      level.WallTextureStyle
+		cyberspace bool
+		valid      bool
+	}{
+		{name: "regular", index: 10, style: level.WallTextureStyle{Offset: 31, UseAdjacent: true}, valid: true},
+		{name: "last atlas entry", index: 53, valid: true},
+		{name: "beyond atlas", index: 54, valid: false},
+		{name: "negative index", index: -1, valid: false},
+		{name: "excess offset", style: level.WallTextureStyle{Offset: 32}, valid: false},
+		{name: "unknown pattern", style: level.WallTextureStyle{Pattern: level.WallTexturePattern(4)}, valid: false},
+		{name: "cyberspace", cyberspace: true, valid: false},
+	}
+
+	for _, tc := range tt {
+		td := tc
+		t.Run(td.name, func(t *testing.T) {
+			err := level.ValidateWallTexture(td.index, td.style, level.DefaultTextureAtlasSize, td.cyberspace)
+			if td.valid {
+				assert.Nil(t, err, "no error expected")
+			} else {
+				assert.Error(t, err, "error expected")
+			}
+		})
+	}
+}
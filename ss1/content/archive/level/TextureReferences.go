@@ -0,0 +1,140 @@
+package level
+
+import "fmt"
+
+// TextureSurface identifies the surface of a tile that refers to a texture.
+type TextureSurface int
+
+// TextureSurface constants.
+const (
+	TextureSurfaceFloor TextureSurface = iota
+	TextureSurfaceCeiling
+	TextureSurfaceWall
+)
+
+// TextureSurfaces returns all surfaces.
+func TextureSurfaces() []TextureSurface {
+	return []TextureSurface{TextureSurfaceFloor, TextureSurfaceCeiling, TextureSurfaceWall}
+}
+
+// String returns the textual representation of the value.
+func (surface TextureSurface) String() string {
+	switch surface {
+	case TextureSurfaceFloor:
+		return "Floor"
+	case TextureSurfaceCeiling:
+		return "Ceiling"
+	case TextureSurfaceWall:
+		return "Wall"
+	default:
+		return fmt.Sprintf("Unknown%d", int(surface))
+	}
+}
+
+// AtlasIndexOf returns the atlas index the given texture info specifies for the surface.
+func (surface TextureSurface) AtlasIndexOf(info TileTextureInfo) int {
+	switch surface {
+	case TextureSurfaceFloor:
+		return info.FloorTextureIndex()
+	case TextureSurfaceCeiling:
+		return info.CeilingTextureIndex()
+	default:
+		return info.WallTextureIndex()
+	}
+}
+
+// WithAtlasIndex returns an info that specifies given atlas index for the surface.
+// Values outside the valid range of the surface are ignored.
+func (surface TextureSurface) WithAtlasIndex(info TileTextureInfo, atlasIndex int) TileTextureInfo {
+	switch surface {
+	case TextureSurfaceFloor:
+		return info.WithFloorTextureIndex(atlasIndex)
+	case TextureSurfaceCeiling:
+		return info.WithCeilingTextureIndex(atlasIndex)
+	default:
+		return info.WithWallTextureIndex(atlasIndex)
+	}
+}
+
+// DanglingTextureReference describes a tile surface that refers to a texture which is not available.
+type DanglingTextureReference struct {
+	X       int
+	Y       int
+	Surface TextureSurface
+	// AtlasIndex is the index into the texture atlas of the level, as specified by the tile.
+	AtlasIndex int
+	// Texture is the game texture the atlas refers to. It is -1 if the atlas index is beyond the atlas.
+	Texture TextureIndex
+}
+
+// String returns a one-line description of the reference.
+func (ref DanglingTextureReference) String() string {
+	if ref.Texture < 0 {
+		return fmt.Sprintf("tile %d/%d %v: atlas index %d beyond texture atlas", ref.X, ref.Y, ref.Surface, ref.AtlasIndex)
+	}
+	return fmt.Sprintf("tile %d/%d %v: atlas index %d refers to unavailable texture %d",
+		ref.X, ref.Y, ref.Surface, ref.AtlasIndex, ref.Texture)
+}
+
+// DanglingTextureReferences returns all tile surfaces of the level that refer to textures
+// outside of the given number of available game textures, or outside of the texture atlas.
+// Solid tiles are not considered, as are cyberspace levels, which do not use textures.
+// The scan only inspects the already decoded tile map and is cheap enough to be repeated after every change.
+func (lvl *Level) DanglingTextureReferences(textureCount int) []DanglingTextureReference {
+	if lvl.IsCyberspace() {
+		return nil
+	}
+	var refs []DanglingTextureReference
+	for y, row := range lvl.tileMap {
+		for x := range row {
+			tile := &row[x]
+			if tile.Type == TileTypeSolid {
+				continue
+			}
+			for _, surface := range TextureSurfaces() {
+				atlasIndex := surface.AtlasIndexOf(tile.TextureInfo)
+				texture := TextureIndex(-1)
+				if atlasIndex < len(lvl.textureAtlas) {
+					texture = lvl.textureAtlas[atlasIndex]
+					if (texture >= 0) && (int(texture) < textureCount) {
+						continue
+					}
+				}
+				refs = append(refs, DanglingTextureReference{
+					X:          x,
+					Y:          y,
+					Surface:    surface,
+					AtlasIndex: atlasIndex,
+					Texture:    texture,
+				})
+			}
+		}
+	}
+	return refs
+}
+
+// RemapTileTextures replaces the atlas index `from` with `to` for all tile surfaces of the level.
+// Surfaces for which `to` is outside their valid range are kept unchanged.
+// It returns the number of changed surfaces.
+func (lvl *Level) RemapTileTextures(from, to int) int {
+	if from == to {
+		return 0
+	}
+	changed := 0
+	for _, row := range lvl.tileMap {
+		for x := range row {
+			tile := &row[x]
+			for _, surface := range TextureSurfaces() {
+				if surface.AtlasIndexOf(tile.TextureInfo) != from {
+					continue
+				}
+				newInfo := surface.WithAtlasIndex(tile.TextureInfo, to)
+				if surface.AtlasIndexOf(newInfo) == to {
+					tile.TextureInfo = newInfo
+					changed++
+				}
+			}
+		}
+	}
+	return changed
+}
@@ -0,0 +1,53 @@
+package level_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/inkyblackness/hacked/ss1/content/archive/level"
+	"github.com/inkyblackness/hacked/ss1/content/archive/level/leveltest"
+)
+
+func openTile(info level.TileTextureInfo) func(level.TileMap) {
+	return func(tiles level.TileMap) {
+		tile := tiles.Tile(3, 4)
+		tile.Type = level.TileTypeOpen
+		tile.TextureInfo = info
+	}
+}
+
+func TestLevelDanglingTextureReferences(t *testing.T) {
+	info := level.TileTextureInfo(0).WithFloorTextureIndex(5).WithCeilingTextureIndex(1).WithWallTextureIndex(60)
+	lvl := leveltest.NewLevel(t, level.EmptyLevelParameters{MapModifier: openTile(info)})
+	lvl.SetTextureAtlasEntry(5, 300)
+
+	refs := lvl.DanglingTextureReferences(273)
+
+	assert.Equal(t, []level.DanglingTextureReference{
+		{X: 3, Y: 4, Surface: level.TextureSurfaceFloor, AtlasIndex: 5, Texture: 300},
+		{X: 3, Y: 4, Surface: level.TextureSurfaceWall, AtlasIndex: 60, Texture: -1},
+	}, refs)
+}
+
+func TestLevelDanglingTextureReferencesIgnoresSolidTiles(t *testing.T) {
+	lvl := leveltest.NewLevel(t, level.EmptyLevelParameters{})
+	lvl.SetTextureAtlasEntry(0, 300)
+
+	assert.Empty(t, lvl.DanglingTextureReferences(273))
+}
+
+func TestLevelRemapTileTextures(t *testing.T) {
+	info := level.TileTextureInfo(0).WithFloorTextureIndex(5).WithCeilingTextureIndex(5).WithWallTextureIndex(40)
+	lvl := leveltest.NewLevel(t, level.EmptyLevelParameters{MapModifier: openTile(info)})
+
+	assert.Equal(t, 2, lvl.RemapTileTextures(5, 7), "floor and ceiling expected to be changed")
+	assert.Equal(t, 0, lvl.RemapTileTextures(40, 40), "no change expected for same index")
+	assert.Equal(t, 0, lvl.RemapTileTextures(7, 33), "no change expected for index beyond floor/ceiling limit")
+	assert.Equal(t, 1, lvl.RemapTileTextures(40, 10), "wall expected to be changed")
+
+	result := lvl.Tile(3, 4).TextureInfo
+	assert.Equal(t, 7, result.FloorTextureIndex())
+	assert.Equal(t, 7, result.CeilingTextureIndex())
+	assert.Equal(t, 10, result.WallTextureIndex())
+}
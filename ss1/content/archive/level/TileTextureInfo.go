@@ -13,7 +13,7 @@ func (info TileTextureInfo) WallTextureIndex() int {
 // WithWallTextureIndex returns an info with given index set.
 // Values outside valid range are ignored.
 func (info TileTextureInfo) WithWallTextureIndex(value int) TileTextureInfo {
-	if (value < 0) || (value >= 64) {
+	if (value < 0) || (value >= WallTextureLimit) {
 		return info
 	}
 	return TileTextureInfo(uint16(info&^0x003F) | uint16(value&0x003F))
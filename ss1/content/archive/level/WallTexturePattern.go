@@ -38,3 +38,34 @@ func WallTexturePatterns() []WallTexturePattern {
 		WallTexturePatternFlipAlternatingInverted,
 	}
 }
+
+// Flipped returns the pattern that mirrors the wall texture opposite to this pattern.
+func (pattern WallTexturePattern) Flipped() WallTexturePattern {
+	switch pattern {
+	case WallTexturePatternRegular:
+		return WallTexturePatternFlipHorizontal
+	case WallTexturePatternFlipHorizontal:
+		return WallTexturePatternRegular
+	case WallTexturePatternFlipAlternating:
+		return WallTexturePatternFlipAlternatingInverted
+	case WallTexturePatternFlipAlternatingInverted:
+		return WallTexturePatternFlipAlternating
+	default:
+		return pattern
+	}
+}
+
+// IsFlippedAt returns true if the wall texture is mirrored horizontally for the tile at given position.
+// The alternating patterns mirror every other tile, based on the sum of the coordinates.
+func (pattern WallTexturePattern) IsFlippedAt(x, y int) bool {
+	switch pattern {
+	case WallTexturePatternFlipHorizontal:
+		return true
+	case WallTexturePatternFlipAlternating:
+		return ((x + y) % 2) != 0
+	case WallTexturePatternFlipAlternatingInverted:
+		return ((x + y) % 2) == 0
+	default:
+		return false
+	}
+}
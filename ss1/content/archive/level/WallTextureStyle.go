@@ -0,0 +1,64 @@
+package level
+
+import (
+	"errors"
+	"fmt"
+)
+
+// WallTextureStyle describes how the wall texture of a tile in the real world is applied.
+type WallTextureStyle struct {
+	// Offset is the vertical offset of the texture. Valid range: [0..TileHeightUnitMax-1].
+	Offset TileHeightUnit
+	// UseAdjacent specifies whether each side uses the wall texture of the adjacent tile.
+	UseAdjacent bool
+	// Pattern specifies how the wall textures are mirrored.
+	Pattern WallTexturePattern
+}
+
+// WallTextureStyleOf returns the current wall texture style of given tile.
+func WallTextureStyleOf(tile *TileMapEntry) WallTextureStyle {
+	flags := tile.Flags.ForRealWorld()
+	return WallTextureStyle{
+		Offset:      flags.WallTextureOffset(),
+		UseAdjacent: flags.UseAdjacentWallTexture(),
+		Pattern:     flags.WallTexturePattern(),
+	}
+}
+
+// Validate returns an error if the style can not be used by the engine.
+// Cyberspace does not have textures, and the corresponding bits of the tile are used for other properties.
+func (style WallTextureStyle) Validate(cyberspace bool) error {
+	if cyberspace {
+		return errors.New("wall texture style is not supported in cyberspace")
+	}
+	if style.Offset >= TileHeightUnitMax {
+		return fmt.Errorf("wall texture offset %d out of range [0..%d]", style.Offset, TileHeightUnitMax-1)
+	}
+	if style.Pattern > WallTexturePatternFlipAlternatingInverted {
+		return fmt.Errorf("wall texture pattern %v not supported", style.Pattern)
+	}
+	return nil
+}
+
+// ApplyTo sets the style in given tile.
+// The style should be validated before, as values are otherwise cut off.
+func (style WallTextureStyle) ApplyTo(tile *TileMapEntry) {
+	tile.Flags = tile.Flags.ForRealWorld().
+		WithWallTextureOffset(style.Offset).
+		WithUseAdjacentWallTexture(style.UseAdjacent).
+		WithWallTexturePattern(style.Pattern).
+		AsTileFlag()
+}
+
+// ValidateWallTexture returns an error if the combination of wall texture index and style can not be used
+// by the engine. The index must refer to an entry of a texture atlas with given size.
+func ValidateWallTexture(index int, style WallTextureStyle, atlasSize int, cyberspace bool) error {
+	limit := atlasSize
+	if limit > WallTextureLimit {
+		limit = WallTextureLimit
+	}
+	if (index < 0) || (index >= limit) {
+		return fmt.Errorf("wall texture index %d out of range [0..%d]", index, limit-1)
+	}
+	return style.Validate(cyberspace)
+}
@@ -0,0 +1,54 @@
+// Package leveltest provides fixtures for tests that work with levels.
+package leveltest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/content/archive/level"
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/world/ids"
+)
+
+// LevelID is the identifier of the level that NewLevel creates.
+const LevelID = 0
+
+// ResourceBase is the first resource ID of the level that NewLevel creates.
+const ResourceBase = ids.LevelResourcesStart
+
+type localizer struct {
+	list resource.LocalizedResourcesList
+}
+
+func (localizer localizer) LocalizedResources(lang resource.Language) resource.Selector {
+	return resource.Selector{From: localizer.list, Lang: lang}
+}
+
+// NewLevel returns a level that is based on the empty level data of given parameters.
+// A nil map modifier keeps the map unchanged.
+// The level resources listed as omitted, given as offsets from lvlids, are not stored.
+func NewLevel(t *testing.T, param level.EmptyLevelParameters, omitted ...int) *level.Level {
+	t.Helper()
+	if param.MapModifier == nil {
+		param.MapModifier = func(level.TileMap) {}
+	}
+	skip := make(map[int]bool)
+	for _, id := range omitted {
+		skip[id] = true
+	}
+	var store resource.Store
+	for id, data := range level.EmptyLevelData(param) {
+		if (len(data) == 0) || skip[id] {
+			continue
+		}
+		err := store.Put(ResourceBase.Plus(id), resource.Resource{
+			Properties: resource.Properties{ContentType: resource.Archive},
+			Blocks:     resource.BlocksFrom([][]byte{data}),
+		})
+		require.Nil(t, err, "no error expected storing level data")
+	}
+	return level.NewLevel(ResourceBase, LevelID, localizer{list: resource.LocalizedResourcesList{
+		{ID: "archive", Language: resource.LangAny, Viewer: store},
+	}})
+}
@@ -0,0 +1,75 @@
+package lvlmesh
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+type vertex [3]float32
+
+// meshWriter writes the elements of an OBJ file. Vertices are written only once, and faces
+// that collapse to less than three distinct vertices are dropped.
+type meshWriter struct {
+	writer   *bufio.Writer
+	err      error
+	vertices map[vertex]int
+	material string
+}
+
+func newMeshWriter(writer io.Writer) *meshWriter {
+	return &meshWriter{
+		writer:   bufio.NewWriter(writer),
+		vertices: make(map[vertex]int),
+	}
+}
+
+func (mesh *meshWriter) printf(format string, args ...interface{}) {
+	if mesh.err != nil {
+		return
+	}
+	_, mesh.err = fmt.Fprintf(mesh.writer, format, args...)
+}
+
+func (mesh *meshWriter) comment(text string) {
+	mesh.printf("# %s\n", text)
+}
+
+func (mesh *meshWriter) face(material string, corners ...vertex) {
+	indices := make([]int, 0, len(corners))
+	for _, corner := range corners {
+		index := mesh.vertexIndex(corner)
+		if (len(indices) == 0) || ((indices[len(indices)-1] != index) && (indices[0] != index)) {
+			indices = append(indices, index)
+		}
+	}
+	if len(indices) < 3 {
+		return
+	}
+	if material != mesh.material {
+		mesh.printf("usemtl %s\n", material)
+		mesh.material = material
+	}
+	mesh.printf("f")
+	for _, index := range indices {
+		mesh.printf(" %d", index)
+	}
+	mesh.printf("\n")
+}
+
+func (mesh *meshWriter) vertexIndex(v vertex) int {
+	index, known := mesh.vertices[v]
+	if !known {
+		index = len(mesh.vertices) + 1
+		mesh.vertices[v] = index
+		mesh.printf("v %g %g %g\n", v[0], v[1], v[2])
+	}
+	return index
+}
+
+func (mesh *meshWriter) flush() error {
+	if mesh.err != nil {
+		return mesh.err
+	}
+	return mesh.writer.Flush()
+}
@@ -0,0 +1,124 @@
+package lvlmesh
+
+import (
+	"fmt"
+
+	"github.com/inkyblackness/hacked/ss1/content/archive/level"
+)
+
+type tileGeometry struct {
+	lvl         Level
+	x, y        int
+	tile        *level.TileMapEntry
+	heightScale float32
+}
+
+var sideNeighbors = map[level.Direction][2]int{
+	level.DirNorth: {0, 1},
+	level.DirEast:  {1, 0},
+	level.DirSouth: {0, -1},
+	level.DirWest:  {-1, 0},
+}
+
+func (geometry tileGeometry) writeTo(mesh *meshWriter) {
+	info := geometry.tile.TextureInfo
+	floor := cornerHeights(geometry.tile, floorHeights)
+	ceiling := cornerHeights(geometry.tile, ceilingHeights)
+	corners := openCorners[geometry.tile.Type]
+
+	floorFace := make([]vertex, 0, len(corners))
+	ceilingFace := make([]vertex, 0, len(corners))
+	for index, corner := range corners {
+		floorFace = append(floorFace, geometry.vertexAt(corner, floor[corner]))
+		reversed := corners[len(corners)-1-index]
+		ceilingFace = append(ceilingFace, geometry.vertexAt(reversed, ceiling[reversed]))
+	}
+	mesh.face(geometry.material(info.FloorTextureIndex()), floorFace...)
+	mesh.face(geometry.material(info.CeilingTextureIndex()), ceilingFace...)
+
+	wallMaterial := geometry.material(info.WallTextureIndex())
+	if facing, diagonal := diagonalWalls[geometry.tile.Type]; diagonal {
+		left, right := facing.Offset(-2), facing.Offset(2)
+		geometry.wall(mesh, wallMaterial, left, right,
+			[2]float32{floor[left], floor[right]}, [2]float32{ceiling[left], ceiling[right]})
+	}
+	for _, side := range []level.Direction{level.DirNorth, level.DirEast, level.DirSouth, level.DirWest} {
+		if (geometry.tile.Type.Info().SolidSides & side.AsMask()) != 0 {
+			continue
+		}
+		geometry.sideWalls(mesh, wallMaterial, side, floor, ceiling)
+	}
+}
+
+// sideWalls writes the walls that are visible from within the tile towards the given side.
+// These are the full wall if the neighbor is closed, or the steps to the floor and ceiling of the neighbor.
+func (geometry tileGeometry) sideWalls(mesh *meshWriter, material string, side level.Direction, floor, ceiling [8]float32) {
+	left, right := side.Offset(-1), side.Offset(1)
+	ownFloor := [2]float32{floor[left], floor[right]}
+	ownCeiling := [2]float32{ceiling[left], ceiling[right]}
+
+	offset := sideNeighbors[side]
+	neighbor := geometry.lvl.Tile(geometry.x+offset[0], geometry.y+offset[1])
+	if (neighbor == nil) || ((neighbor.Type.Info().SolidSides & side.Offset(4).AsMask()) != 0) ||
+		(len(tileSkipReason(neighbor)) > 0) {
+		geometry.wall(mesh, material, left, right, ownFloor, ownCeiling)
+		return
+	}
+	neighborFloorHeights := cornerHeights(neighbor, floorHeights)
+	neighborCeilingHeights := cornerHeights(neighbor, ceilingHeights)
+	mirrored := func(corner level.Direction) level.Direction {
+		return level.Direction((2*int(side) + 4 - int(corner) + 16) % 8)
+	}
+	neighborFloor := [2]float32{neighborFloorHeights[mirrored(left)], neighborFloorHeights[mirrored(right)]}
+	neighborCeiling := [2]float32{neighborCeilingHeights[mirrored(left)], neighborCeilingHeights[mirrored(right)]}
+
+	var lowerTop, upperBottom [2]float32
+	for i := 0; i < 2; i++ {
+		lowerTop[i] = clamp(neighborFloor[i], ownFloor[i], ownCeiling[i])
+		upperBottom[i] = clamp(neighborCeiling[i], ownFloor[i], ownCeiling[i])
+	}
+	geometry.wall(mesh, material, left, right, ownFloor, lowerTop)
+	geometry.wall(mesh, material, left, right, upperBottom, ownCeiling)
+}
+
+// wall writes a wall between two corners, as seen from within the tile.
+func (geometry tileGeometry) wall(mesh *meshWriter, material string, left, right level.Direction, bottom, top [2]float32) {
+	if (top[0] <= bottom[0]) && (top[1] <= bottom[1]) {
+		return
+	}
+	mesh.face(material,
+		geometry.vertexAt(left, bottom[0]),
+		geometry.vertexAt(right, bottom[1]),
+		geometry.vertexAt(right, top[1]),
+		geometry.vertexAt(left, top[0]))
+}
+
+func (geometry tileGeometry) vertexAt(corner level.Direction, height float32) vertex {
+	offset := cornerOffsets[corner]
+	return vertex{
+		float32(geometry.x + offset[0]),
+		height * geometry.heightScale,
+		float32(-(geometry.y + offset[1])),
+	}
+}
+
+func (geometry tileGeometry) material(atlasIndex int) string {
+	if geometry.lvl.IsCyberspace() {
+		return ""
+	}
+	atlas := geometry.lvl.TextureAtlas()
+	if (atlasIndex < 0) || (atlasIndex >= len(atlas)) {
+		return "unknown"
+	}
+	return fmt.Sprintf("texture%03d", int(atlas[atlasIndex]))
+}
+
+func clamp(value, min, max float32) float32 {
+	if value < min {
+		return min
+	}
+	if value > max {
+		return max
+	}
+	return value
+}
@@ -0,0 +1,14 @@
+package lvlmesh
+
+import "fmt"
+
+// Warning describes a tile that could not be exported.
+type Warning struct {
+	X, Y   int
+	Reason string
+}
+
+// String returns the textual representation of the warning.
+func (warning Warning) String() string {
+	return fmt.Sprintf("tile %d/%d skipped: %s", warning.X, warning.Y, warning.Reason)
+}
@@ -0,0 +1,130 @@
+package lvlmesh
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/inkyblackness/hacked/ss1/content/archive/level"
+)
+
+// Level describes the source of the geometry.
+type Level interface {
+	Size() (x, y int, z level.HeightShift)
+	Tile(x, y int) *level.TileMapEntry
+	TextureAtlas() level.TextureAtlas
+	IsCyberspace() bool
+}
+
+// WriteOBJ writes the geometry of given level as Wavefront OBJ to the writer.
+// Floors, ceilings, and the visible parts of walls are written as separate faces.
+// Faces refer to materials named after the texture they use; No material library is written.
+// Cyberspace levels have no textures and are written without materials.
+//
+// Tiles that are closed off, because their ceiling is not above their floor, or those of unknown type,
+// are skipped and reported in the returned list of warnings.
+func WriteOBJ(writer io.Writer, lvl Level) ([]Warning, error) {
+	columns, rows, heightShift := lvl.Size()
+	heightScale, err := heightShift.ValueFromTileHeight(1)
+	if err != nil {
+		return nil, err
+	}
+	mesh := newMeshWriter(writer)
+	mesh.comment(fmt.Sprintf("level geometry, %d x %d tiles", columns, rows))
+	var warnings []Warning
+	for y := 0; y < rows; y++ {
+		for x := 0; x < columns; x++ {
+			tile := lvl.Tile(x, y)
+			if (tile == nil) || (tile.Type == level.TileTypeSolid) {
+				continue
+			}
+			reason := tileSkipReason(tile)
+			if len(reason) > 0 {
+				warnings = append(warnings, Warning{X: x, Y: y, Reason: reason})
+				continue
+			}
+			geometry := tileGeometry{lvl: lvl, x: x, y: y, tile: tile, heightScale: heightScale}
+			geometry.writeTo(mesh)
+		}
+	}
+	return warnings, mesh.flush()
+}
+
+func tileSkipReason(tile *level.TileMapEntry) string {
+	if _, known := openCorners[tile.Type]; !known {
+		return fmt.Sprintf("unknown tile type %v", tile.Type)
+	}
+	floor := cornerHeights(tile, floorHeights)
+	ceiling := cornerHeights(tile, ceilingHeights)
+	for _, corner := range openCorners[tile.Type] {
+		if ceiling[corner] > floor[corner] {
+			return ""
+		}
+	}
+	return "tile is closed off"
+}
+
+// openCorners lists the corners of the open area of a tile, in counter-clockwise order when seen from above.
+var openCorners = map[level.TileType][]level.Direction{
+	level.TileTypeOpen:                  {level.DirSouthWest, level.DirSouthEast, level.DirNorthEast, level.DirNorthWest},
+	level.TileTypeDiagonalOpenSouthEast: {level.DirSouthWest, level.DirSouthEast, level.DirNorthEast},
+	level.TileTypeDiagonalOpenSouthWest: {level.DirSouthWest, level.DirSouthEast, level.DirNorthWest},
+	level.TileTypeDiagonalOpenNorthWest: {level.DirSouthWest, level.DirNorthEast, level.DirNorthWest},
+	level.TileTypeDiagonalOpenNorthEast: {level.DirSouthEast, level.DirNorthEast, level.DirNorthWest},
+
+	level.TileTypeSlopeSouthToNorth: {level.DirSouthWest, level.DirSouthEast, level.DirNorthEast, level.DirNorthWest},
+	level.TileTypeSlopeWestToEast:   {level.DirSouthWest, level.DirSouthEast, level.DirNorthEast, level.DirNorthWest},
+	level.TileTypeSlopeNorthToSouth: {level.DirSouthWest, level.DirSouthEast, level.DirNorthEast, level.DirNorthWest},
+	level.TileTypeSlopeEastToWest:   {level.DirSouthWest, level.DirSouthEast, level.DirNorthEast, level.DirNorthWest},
+
+	level.TileTypeValleySouthEastToNorthWest: {level.DirSouthWest, level.DirSouthEast, level.DirNorthEast, level.DirNorthWest},
+	level.TileTypeValleySouthWestToNorthEast: {level.DirSouthWest, level.DirSouthEast, level.DirNorthEast, level.DirNorthWest},
+	level.TileTypeValleyNorthWestToSouthEast: {level.DirSouthWest, level.DirSouthEast, level.DirNorthEast, level.DirNorthWest},
+	level.TileTypeValleyNorthEastToSouthWest: {level.DirSouthWest, level.DirSouthEast, level.DirNorthEast, level.DirNorthWest},
+
+	level.TileTypeRidgeNorthWestToSouthEast: {level.DirSouthWest, level.DirSouthEast, level.DirNorthEast, level.DirNorthWest},
+	level.TileTypeRidgeNorthEastToSouthWest: {level.DirSouthWest, level.DirSouthEast, level.DirNorthEast, level.DirNorthWest},
+	level.TileTypeRidgeSouthEastToNorthWest: {level.DirSouthWest, level.DirSouthEast, level.DirNorthEast, level.DirNorthWest},
+	level.TileTypeRidgeSouthWestToNorthEast: {level.DirSouthWest, level.DirSouthEast, level.DirNorthEast, level.DirNorthWest},
+}
+
+// diagonalWalls specifies for diagonal tiles the direction the diagonal wall faces, seen from within the open area.
+var diagonalWalls = map[level.TileType]level.Direction{
+	level.TileTypeDiagonalOpenSouthEast: level.DirNorthWest,
+	level.TileTypeDiagonalOpenSouthWest: level.DirNorthEast,
+	level.TileTypeDiagonalOpenNorthWest: level.DirSouthEast,
+	level.TileTypeDiagonalOpenNorthEast: level.DirSouthWest,
+}
+
+// cornerOffsets are the offsets of the corners, in map coordinates, relative to the south-west corner of a tile.
+var cornerOffsets = map[level.Direction][2]int{
+	level.DirNorthEast: {1, 1},
+	level.DirSouthEast: {1, 0},
+	level.DirSouthWest: {0, 0},
+	level.DirNorthWest: {0, 1},
+}
+
+type surface int
+
+const (
+	floorHeights   surface = 0
+	ceilingHeights surface = 1
+)
+
+// cornerHeights returns the heights, in tile height units, of all directions of a tile.
+// Solid sides of the tile are not considered.
+func cornerHeights(tile *level.TileMapEntry, kind surface) [8]float32 {
+	slopeControl := tile.Flags.SlopeControl()
+	var result [8]float32
+	if kind == floorHeights {
+		factors := slopeControl.FloorSlopeFactors(tile.Type)
+		for dir := range result {
+			result[dir] = float32(tile.Floor.AbsoluteHeight()) + factors[dir]*float32(tile.SlopeHeight)
+		}
+	} else {
+		factors := slopeControl.CeilingSlopeFactors(tile.Type)
+		for dir := range result {
+			result[dir] = float32(tile.Ceiling.AbsoluteHeight()) - factors[dir]*float32(tile.SlopeHeight)
+		}
+	}
+	return result
+}
@@ -0,0 +1,78 @@
+package lvlmesh_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/content/archive/level"
+	"github.com/inkyblackness/hacked/ss1/content/archive/level/lvlmesh"
+)
+
+type testingLevel struct {
+	tiles level.TileMap
+	atlas level.TextureAtlas
+}
+
+func (lvl testingLevel) Size() (x, y int, z level.HeightShift) {
+	return len(lvl.tiles[0]), len(lvl.tiles), level.HeightShift(3)
+}
+
+func (lvl testingLevel) Tile(x, y int) *level.TileMapEntry {
+	return lvl.tiles.Tile(x, y)
+}
+
+func (lvl testingLevel) TextureAtlas() level.TextureAtlas {
+	return lvl.atlas
+}
+
+func (lvl testingLevel) IsCyberspace() bool {
+	return false
+}
+
+func newTestingLevel() testingLevel {
+	lvl := testingLevel{tiles: level.NewTileMap(3, 1), atlas: level.TextureAtlas{10, 20, 30}}
+	heights := [][2]level.TileHeightUnit{{0, 16}, {4, 16}, {8, 8}}
+	for x, height := range heights {
+		tile := lvl.tiles.Tile(x, 0)
+		tile.Type = level.TileTypeOpen
+		tile.Floor = tile.Floor.WithAbsoluteHeight(height[0])
+		tile.Ceiling = tile.Ceiling.WithAbsoluteHeight(height[1])
+		tile.TextureInfo = tile.TextureInfo.WithFloorTextureIndex(0).WithCeilingTextureIndex(1).WithWallTextureIndex(2)
+	}
+	return lvl
+}
+
+func TestWriteOBJSkipsClosedTiles(t *testing.T) {
+	warnings, err := lvlmesh.WriteOBJ(&bytes.Buffer{}, newTestingLevel())
+	require.Nil(t, err, "no error expected")
+
+	assert.Equal(t, []lvlmesh.Warning{{X: 2, Y: 0, Reason: "tile is closed off"}}, warnings)
+}
+
+func TestWriteOBJWritesFacesOfOpenTiles(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	_, err := lvlmesh.WriteOBJ(buf, newTestingLevel())
+	require.Nil(t, err, "no error expected")
+	lines := strings.Split(buf.String(), "\n")
+
+	faces := 0
+	materials := make(map[string]bool)
+	for _, line := range lines {
+		if strings.HasPrefix(line, "f ") {
+			faces++
+		}
+		if strings.HasPrefix(line, "usemtl ") {
+			materials[strings.TrimPrefix(line, "usemtl ")] = true
+		}
+	}
+	// first tile: floor, ceiling, three full walls, one step to second tile.
+	// second tile: floor, ceiling, three full walls.
+	assert.Equal(t, 6+5, faces, "face count mismatch")
+	assert.Equal(t, map[string]bool{"texture010": true, "texture020": true, "texture030": true}, materials)
+	assert.Contains(t, lines, "v 1 0.5 0", "step of second tile expected in scaled height")
+	assert.Contains(t, lines, "v 0 2 -1", "ceiling of first tile expected in scaled height")
+}
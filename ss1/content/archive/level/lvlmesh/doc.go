@@ -0,0 +1,7 @@
+// Package lvlmesh creates simple meshes from the geometry of a level, for previews in external 3D tools.
+//
+// The geometry is written as Wavefront OBJ, with one unit being the side length of a tile.
+// The X axis points east, the Y axis points up, and the Z axis points south; The map tile at (x, y)
+// covers the area from (x, -y) to (x+1, -(y+1)) on the X-Z plane. Heights are scaled according to the
+// height shift of the level, so that they are in the same unit as the tiles.
+package lvlmesh
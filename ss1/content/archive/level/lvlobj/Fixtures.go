@@ -3,6 +3,7 @@ package lvlobj
 import (
 	"fmt"
 
+	"github.com/inkyblackness/hacked/ss1/content/archive/level"
 	"github.com/inkyblackness/hacked/ss1/content/archive/level/lvlobj/actions"
 	"github.com/inkyblackness/hacked/ss1/content/archive/level/lvlobj/conditions"
 	"github.com/inkyblackness/hacked/ss1/content/interpreters"
@@ -97,12 +98,12 @@ var puzzlePanel = inputPanel.
 	Refining("Puzzle", 6, 18, puzzleSpecificData, interpreters.Always)
 
 var elevatorPanel = inputPanel.
-	With("DestinationObjectIndex2", 6, 2).As(interpreters.RangedValue(0, 871)).
-	With("DestinationObjectIndex1", 8, 2).As(interpreters.RangedValue(0, 871)).
-	With("DestinationObjectIndex4", 10, 2).As(interpreters.RangedValue(0, 871)).
-	With("DestinationObjectIndex3", 12, 2).As(interpreters.RangedValue(0, 871)).
-	With("DestinationObjectIndex6", 14, 2).As(interpreters.RangedValue(0, 871)).
-	With("DestinationObjectIndex5", 16, 2).As(interpreters.RangedValue(0, 871)).
+	With("DestinationObjectIndex2", 6, 2).As(interpreters.RangedValue(0, level.MaxObjectsPerLevel)).
+	With("DestinationObjectIndex1", 8, 2).As(interpreters.RangedValue(0, level.MaxObjectsPerLevel)).
+	With("DestinationObjectIndex4", 10, 2).As(interpreters.RangedValue(0, level.MaxObjectsPerLevel)).
+	With("DestinationObjectIndex3", 12, 2).As(interpreters.RangedValue(0, level.MaxObjectsPerLevel)).
+	With("DestinationObjectIndex6", 14, 2).As(interpreters.RangedValue(0, level.MaxObjectsPerLevel)).
+	With("DestinationObjectIndex5", 16, 2).As(interpreters.RangedValue(0, level.MaxObjectsPerLevel)).
 	With("AccessibleBitmask", 18, 2).As(interpreters.Bitfield(map[uint32]string{
 	0x0001: "Level  0",
 	0x0002: "Level  1",
@@ -0,0 +1,38 @@
+package lvlobj
+
+import (
+	"github.com/inkyblackness/hacked/ss1/content/interpreters"
+)
+
+// Link is a reference from the class data of one object to another object.
+type Link struct {
+	// Key is the path of the field holding the reference. Refinements are separated by dots.
+	Key string
+	// Target is the raw value of the field. Zero refers to no object.
+	Target uint32
+}
+
+// LinksOf returns all object references of the given interpreter, including its active refinements.
+// Fields are considered object references if they are described as object identifier.
+// References with a value of zero are returned as well, allowing to detect fields that are not linked.
+func LinksOf(inst *interpreters.Instance) []Link {
+	var links []Link
+	collectLinks(&links, "", inst)
+	return links
+}
+
+func collectLinks(links *[]Link, path string, inst *interpreters.Instance) {
+	isObjectID := false
+	simplifier := interpreters.NewSimplifier(func(minValue, maxValue int64, formatter interpreters.RawValueFormatter) {})
+	simplifier.SetObjectIDHandler(func() { isObjectID = true })
+	for _, key := range inst.Keys() {
+		isObjectID = false
+		inst.Describe(key, simplifier)
+		if isObjectID {
+			*links = append(*links, Link{Key: path + key, Target: inst.Get(key)})
+		}
+	}
+	for _, key := range inst.ActiveRefinements() {
+		collectLinks(links, path+key+".", inst.Refined(key))
+	}
+}
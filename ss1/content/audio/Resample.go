@@ -0,0 +1,30 @@
+package audio
+
+// Resampled returns the sound converted to the given sample rate.
+// New samples are linearly interpolated from the neighbouring source samples.
+// If the rates already match, or either of them is not positive, the sound is returned unchanged.
+func (sound L8) Resampled(sampleRate float32) L8 {
+	if (sampleRate <= 0) || (sound.SampleRate <= 0) || (sampleRate == sound.SampleRate) || sound.Empty() {
+		return sound
+	}
+	ratio := float64(sound.SampleRate) / float64(sampleRate)
+	sourceCount := len(sound.Samples)
+	targetCount := int(float64(sourceCount) / ratio)
+	if targetCount < 1 {
+		targetCount = 1
+	}
+	samples := make([]byte, targetCount)
+	for index := range samples {
+		position := float64(index) * ratio
+		base := int(position)
+		if base >= sourceCount-1 {
+			samples[index] = sound.Samples[sourceCount-1]
+			continue
+		}
+		fraction := position - float64(base)
+		from := float64(sound.Samples[base])
+		to := float64(sound.Samples[base+1])
+		samples[index] = byte(from + (to-from)*fraction + 0.5)
+	}
+	return L8{SampleRate: sampleRate, Samples: samples}
+}
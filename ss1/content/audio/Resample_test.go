@@ -0,0 +1,42 @@
+package audio_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/inkyblackness/hacked/ss1/content/audio"
+)
+
+func TestResampledKeepsSoundOfSameRate(t *testing.T) {
+	sound := audio.L8{SampleRate: 22050, Samples: []byte{0x10, 0x20}}
+
+	result := sound.Resampled(22050)
+
+	assert.Equal(t, sound, result)
+}
+
+func TestResampledInterpolatesWhenUpsampling(t *testing.T) {
+	sound := audio.L8{SampleRate: 11025, Samples: []byte{0x00, 0x40, 0x80}}
+
+	result := sound.Resampled(22050)
+
+	assert.Equal(t, float32(22050), result.SampleRate)
+	assert.Equal(t, []byte{0x00, 0x20, 0x40, 0x60, 0x80, 0x80}, result.Samples)
+}
+
+func TestResampledSkipsWhenDownsampling(t *testing.T) {
+	sound := audio.L8{SampleRate: 44100, Samples: []byte{0x00, 0x10, 0x20, 0x30, 0x40, 0x50, 0x60, 0x70}}
+
+	result := sound.Resampled(22050)
+
+	assert.Equal(t, []byte{0x00, 0x20, 0x40, 0x60}, result.Samples)
+}
+
+func TestResampledKeepsAtLeastOneSample(t *testing.T) {
+	sound := audio.L8{SampleRate: 44100, Samples: []byte{0x42}}
+
+	result := sound.Resampled(11025)
+
+	assert.Equal(t, []byte{0x42}, result.Samples)
+}
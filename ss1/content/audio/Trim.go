@@ -0,0 +1,40 @@
+package audio
+
+// silenceLevel is the sample value of a linear 8-bit sound that represents silence.
+const silenceLevel = 0x80
+
+// Amplitude returns the distance of the given sample from silence.
+func Amplitude(sample byte) byte {
+	if sample < silenceLevel {
+		return silenceLevel - sample
+	}
+	return sample - silenceLevel
+}
+
+// SilenceTrimmed returns the sound without the leading and trailing samples that have an amplitude
+// below the given threshold. The second return value is the number of removed samples.
+// If the whole sound is silent, the result has a single sample of silence,
+// so that the sound remains valid.
+func (sound L8) SilenceTrimmed(threshold byte) (L8, int) {
+	start := 0
+	end := len(sound.Samples)
+	for (start < end) && (Amplitude(sound.Samples[start]) < threshold) {
+		start++
+	}
+	for (end > start) && (Amplitude(sound.Samples[end-1]) < threshold) {
+		end--
+	}
+	if start == end {
+		if len(sound.Samples) == 0 {
+			return sound, 0
+		}
+		return L8{SampleRate: sound.SampleRate, Samples: []byte{silenceLevel}}, len(sound.Samples) - 1
+	}
+	trimmed := len(sound.Samples) - (end - start)
+	if trimmed == 0 {
+		return sound, 0
+	}
+	samples := make([]byte, end-start)
+	copy(samples, sound.Samples[start:end])
+	return L8{SampleRate: sound.SampleRate, Samples: samples}, trimmed
+}
@@ -0,0 +1,64 @@
+package audio_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/inkyblackness/hacked/ss1/content/audio"
+)
+
+func TestAmplitude(t *testing.T) {
+	assert.Equal(t, byte(0), audio.Amplitude(0x80))
+	assert.Equal(t, byte(0x10), audio.Amplitude(0x70))
+	assert.Equal(t, byte(0x10), audio.Amplitude(0x90))
+	assert.Equal(t, byte(0x80), audio.Amplitude(0x00))
+	assert.Equal(t, byte(0x7F), audio.Amplitude(0xFF))
+}
+
+func TestSilenceTrimmedRemovesLeadingAndTrailingQuietSamples(t *testing.T) {
+	sound := audio.L8{SampleRate: 22050, Samples: []byte{0x80, 0x81, 0x7E, 0xA0, 0x80, 0x60, 0x82, 0x80}}
+
+	result, trimmed := sound.SilenceTrimmed(4)
+
+	assert.Equal(t, audio.L8{SampleRate: 22050, Samples: []byte{0xA0, 0x80, 0x60}}, result)
+	assert.Equal(t, 5, trimmed)
+}
+
+func TestSilenceTrimmedKeepsSamplesAtThreshold(t *testing.T) {
+	sound := audio.L8{SampleRate: 22050, Samples: []byte{0x84, 0x80, 0x7C}}
+
+	result, trimmed := sound.SilenceTrimmed(4)
+
+	assert.Equal(t, sound, result)
+	assert.Equal(t, 0, trimmed)
+}
+
+func TestSilenceTrimmedDoesNotModifySource(t *testing.T) {
+	samples := []byte{0x80, 0xA0, 0x80}
+	sound := audio.L8{SampleRate: 22050, Samples: samples}
+
+	result, _ := sound.SilenceTrimmed(1)
+	result.Samples[0] = 0x00
+
+	assert.Equal(t, []byte{0x80, 0xA0, 0x80}, samples)
+}
+
+func TestSilenceTrimmedKeepsOneSampleOfSilentSound(t *testing.T) {
+	sound := audio.L8{SampleRate: 11025, Samples: []byte{0x80, 0x81, 0x7F, 0x80}}
+
+	result, trimmed := sound.SilenceTrimmed(2)
+
+	assert.Equal(t, audio.L8{SampleRate: 11025, Samples: []byte{0x80}}, result)
+	assert.Equal(t, 3, trimmed)
+	assert.False(t, result.Empty())
+}
+
+func TestSilenceTrimmedKeepsEmptySound(t *testing.T) {
+	sound := audio.L8{SampleRate: 22050}
+
+	result, trimmed := sound.SilenceTrimmed(2)
+
+	assert.Equal(t, sound, result)
+	assert.Equal(t, 0, trimmed)
+}
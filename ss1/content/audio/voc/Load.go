@@ -77,6 +77,9 @@ func readSoundData(source io.Reader) (data audio.L8, err error) {
 				sampleRate = divisorToSampleRate(meta[0])
 
 				newCount := lengthFromBlockStart(blockStart) - len(meta)
+				if newCount < 0 {
+					return data, fmt.Errorf("invalid sound data block length")
+				}
 				buf := make([]byte, newCount)
 				_, err = source.Read(buf)
 				if err != nil {
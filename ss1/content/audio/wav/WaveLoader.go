@@ -22,6 +22,30 @@ func l8FromL16(input []byte) []byte {
 	return output
 }
 
+func l8FromStereoL8(input []byte) []byte {
+	samples := len(input) / 2
+	output := make([]byte, samples)
+
+	for i := 0; i < samples; i++ {
+		output[i] = byte((int(input[i*2]) + int(input[i*2+1])) / 2)
+	}
+
+	return output
+}
+
+func l8FromStereoL16(input []byte) []byte {
+	samples := len(input) / 4
+	output := make([]byte, samples)
+
+	for i := 0; i < samples; i++ {
+		left := int(int16(uint16(input[i*4+0]) | uint16(input[i*4+1])<<8))
+		right := int(int16(uint16(input[i*4+2]) | uint16(input[i*4+3])<<8))
+		output[i] = byte(((left+right)/2)>>8 + 0x80)
+	}
+
+	return output
+}
+
 type waveLoader struct {
 	dataRead   bool
 	formatRead bool
@@ -107,15 +131,18 @@ func (loader *waveLoader) loadFormat(size uint32) {
 	loader.err = binary.Read(headerReader, binary.LittleEndian, &header.extension.BitsPerSample)
 	loader.sampleRate = float32(header.base.SamplesPerSec)
 
-	if header.extension.BitsPerSample == 16 {
-		loader.dataConverter = l8FromL16
+	converters := map[uint16]map[uint16]func([]byte) []byte{
+		1: {8: l8FromL8, 16: l8FromL16},
+		2: {8: l8FromStereoL8, 16: l8FromStereoL16},
 	}
-
-	if (header.base.FormatType != waveFormatTypePcm) ||
-		(header.base.Channels != 1) ||
-		((header.extension.BitsPerSample != 8) && (header.extension.BitsPerSample != 16)) {
-		loader.err = fmt.Errorf("unsupported WAVE format")
+	converter, supported := converters[header.base.Channels][header.extension.BitsPerSample]
+	if (header.base.FormatType != waveFormatTypePcm) || !supported {
+		loader.err = fmt.Errorf("unsupported WAVE format: type %d, %d channel(s), %d bits per sample; "+
+			"only PCM with one or two channels and 8 or 16 bits per sample can be converted",
+			header.base.FormatType, header.base.Channels, header.extension.BitsPerSample)
+		return
 	}
+	loader.dataConverter = converter
 }
 
 func (loader *waveLoader) loadData(size uint32) {
@@ -44,7 +44,10 @@ func (cache *AnimationCache) Animation(key resource.Key) (anim Animation, err er
 	if err != nil {
 		return
 	}
-	if (view.ContentType() != resource.Animation) || (view.BlockCount() != 1) {
+	if view.ContentType() != resource.Animation {
+		return anim, resource.ErrContentTypeOf(key.ID, view.ContentType(), resource.Animation)
+	}
+	if view.BlockCount() != 1 {
 		return anim, errors.New("resource is not an animation")
 	}
 	reader, err := view.Block(0)
@@ -4,6 +4,8 @@ import (
 	"image"
 	"image/color"
 	"math"
+	"runtime"
+	"sync"
 )
 
 // reference white point
@@ -20,13 +22,7 @@ func square(value float64) float64 {
 	return value * value
 }
 
-type labEntry struct {
-	l float64
-	a float64
-	b float64
-}
-
-func labEntryFromColor(clr color.Color) labEntry {
+func labPointFromColor(clr color.Color) colorPoint {
 	rLinear, gLinear, bLinear, _ := clr.RGBA()
 	r, g, b := float64(rLinear)/float64(0xFFFF), float64(gLinear)/float64(0xFFFF), float64(bLinear)/float64(0xFFFF)
 	x := 0.4124564*r + 0.3575761*g + 0.1804375*b
@@ -34,34 +30,42 @@ func labEntryFromColor(clr color.Color) labEntry {
 	z := 0.0193339*r + 0.1191920*g + 0.9503041*b
 	whiteRef := d65
 	fy := labF(y / whiteRef[1])
-	entry := labEntry{
-		l: 1.16*fy - 0.16,
-		a: 5.0 * (labF(x/whiteRef[0]) - fy),
-		b: 2.0 * (fy - labF(z/whiteRef[2]))}
-
-	return entry
-}
-
-func (entry labEntry) distanceTo(other labEntry) float64 {
-	return math.Sqrt(square(entry.l-other.l) + square(entry.a-other.a) + square(entry.b-other.b))
+	return colorPoint{
+		1.16*fy - 0.16,
+		5.0 * (labF(x/whiteRef[0]) - fy),
+		2.0 * (fy - labF(z/whiteRef[2]))}
 }
 
 // Bitmapper creates bitmap images from generic images.
+// A bitmapper is not modified after its creation and is safe for concurrent use.
 type Bitmapper struct {
-	pal []labEntry
+	metric ColorMetric
+	pal    []colorPoint
 }
 
 // NewBitmapper returns a new bitmapper instance based on the given palette.
+// Colors are compared with the CIELAB metric.
 func NewBitmapper(palette *Palette) *Bitmapper {
-	bitmapper := &Bitmapper{}
+	return NewBitmapperWithMetric(palette, ColorMetricCIELAB)
+}
+
+// NewBitmapperWithMetric returns a new bitmapper instance based on the given palette,
+// which compares colors with the given metric.
+func NewBitmapperWithMetric(palette *Palette, metric ColorMetric) *Bitmapper {
+	bitmapper := &Bitmapper{metric: metric}
 
 	for _, clr := range palette {
-		bitmapper.pal = append(bitmapper.pal, labEntryFromColor(clr.Color(0xFF)))
+		bitmapper.pal = append(bitmapper.pal, metric.pointFromColor(clr.Color(0xFF)))
 	}
 
 	return bitmapper
 }
 
+// Metric returns the metric used to compare colors.
+func (bitmapper *Bitmapper) Metric() ColorMetric {
+	return bitmapper.metric
+}
+
 // Map maps the provided image to a bitmap based on the internal palette.
 func (bitmapper *Bitmapper) Map(img image.Image) Bitmap {
 	var bmp Bitmap
@@ -70,30 +74,112 @@ func (bitmapper *Bitmapper) Map(img image.Image) Bitmap {
 	bmp.Header.Width = int16(math.Max(0, math.Min(float64(bounds.Dx()), math.MaxInt16)))
 	bmp.Header.Height = int16(math.Max(0, math.Min(float64(bounds.Dy()), math.MaxInt16)))
 	bmp.Pixels = make([]byte, int(bmp.Header.Width)*int(bmp.Header.Height))
+	indices := make(map[color.RGBA64]byte)
 	for row := 0; row < int(bmp.Header.Height); row++ {
 		for column := 0; column < int(bmp.Header.Width); column++ {
-			bmp.Pixels[row*int(bmp.Header.Width)+column] = bitmapper.MapColor(img.At(column, row))
+			r, g, b, a := img.At(column, row).RGBA()
+			clr := color.RGBA64{R: uint16(r), G: uint16(g), B: uint16(b), A: uint16(a)}
+			palIndex, known := indices[clr]
+			if !known {
+				palIndex = bitmapper.MapColor(clr)
+				indices[clr] = palIndex
+			}
+			bmp.Pixels[row*int(bmp.Header.Width)+column] = palIndex
 		}
 	}
 
 	return bmp
 }
 
+// MapAll maps the provided images to bitmaps, using up to the given number of concurrent workers.
+// A concurrency of less than one uses one worker per available CPU.
+// The returned bitmaps are in the same order as the images.
+func (bitmapper *Bitmapper) MapAll(images []image.Image, concurrency int) []Bitmap {
+	if concurrency < 1 {
+		concurrency = runtime.NumCPU()
+	}
+	if concurrency > len(images) {
+		concurrency = len(images)
+	}
+	bitmaps := make([]Bitmap, len(images))
+	indices := make(chan int)
+	var workers sync.WaitGroup
+	workers.Add(concurrency)
+	for worker := 0; worker < concurrency; worker++ {
+		go func() {
+			defer workers.Done()
+			for index := range indices {
+				bitmaps[index] = bitmapper.Map(images[index])
+			}
+		}()
+	}
+	for index := range images {
+		indices <- index
+	}
+	close(indices)
+	workers.Wait()
+	return bitmaps
+}
+
 // MapColor maps the provided color to the nearest index in the palette.
 func (bitmapper *Bitmapper) MapColor(clr color.Color) (palIndex byte) {
-	_, _, _, a := clr.RGBA()
-	indexWithin := func(index, from, to int) bool {
-		return (index >= from) && (index <= to)
+	palIndex, _ = bitmapper.nearestColor(clr)
+	return
+}
+
+// PaletteFitThreshold is the average color error above which an image is considered to not fit a palette.
+// The error is the distance of the metric of the bitmapper. For the default CIELAB metric, this is the
+// euclidean distance in the CIELAB color space, with lightness scaled to the range [0, 1].
+const PaletteFitThreshold = 0.05
+
+// PaletteFit describes how well the colors of an image are represented by a palette.
+type PaletteFit struct {
+	// AverageError is the average distance of all opaque pixels to their nearest palette color.
+	AverageError float64
+	// ReindexRecommended is set if the average error exceeds PaletteFitThreshold.
+	// Such images should be re-indexed to the palette in an image editor, before they are mapped.
+	ReindexRecommended bool
+}
+
+// Fit determines how well the colors of given image are represented by the internal palette.
+func (bitmapper *Bitmapper) Fit(img image.Image) PaletteFit {
+	var fit PaletteFit
+	bounds := img.Bounds()
+	distances := make(map[color.RGBA64]float64)
+	pixelCount := 0
+	totalError := 0.0
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			r, g, b, a := img.At(x, y).RGBA()
+			if a == 0 {
+				continue
+			}
+			clr := color.RGBA64{R: uint16(r), G: uint16(g), B: uint16(b), A: uint16(a)}
+			distance, known := distances[clr]
+			if !known {
+				_, distance = bitmapper.nearestColor(clr)
+				distances[clr] = distance
+			}
+			totalError += distance
+			pixelCount++
+		}
 	}
+	if pixelCount > 0 {
+		fit.AverageError = totalError / float64(pixelCount)
+	}
+	fit.ReindexRecommended = fit.AverageError > PaletteFitThreshold
+	return fit
+}
 
+func (bitmapper *Bitmapper) nearestColor(clr color.Color) (palIndex byte, palDistance float64) {
+	_, _, _, a := clr.RGBA()
 	if a > 0 {
-		clrEntry := labEntryFromColor(clr)
-		palDistance := 1000.0
+		clrPoint := bitmapper.metric.pointFromColor(clr)
+		palDistance = 1000.0
 
-		for colorIndex, palEntry := range bitmapper.pal {
-			isRegularColor := indexWithin(colorIndex, 0x01, 0x02) || indexWithin(colorIndex, 0x08, 0x0A) || indexWithin(colorIndex, 0x20, 0xFF)
-			if isRegularColor {
-				distance := palEntry.distanceTo(clrEntry)
+		for colorIndex, palPoint := range bitmapper.pal {
+			if IsRegularColorIndex(colorIndex) {
+				distance := bitmapper.metric.distance(palPoint, clrPoint)
 				if distance < palDistance {
 					palDistance = distance
 					palIndex = byte(colorIndex)
@@ -0,0 +1,160 @@
+package bitmap_test
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/inkyblackness/hacked/ss1/content/bitmap"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func greyPalette() bitmap.Palette {
+	var pal bitmap.Palette
+	for index := range pal {
+		pal[index] = bitmap.RGB{Red: byte(index), Green: byte(index), Blue: byte(index)}
+	}
+	return pal
+}
+
+func uniformImage(clr color.Color) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x++ {
+			img.Set(x, y, clr)
+		}
+	}
+	return img
+}
+
+func TestBitmapperFitOfMatchingImage(t *testing.T) {
+	pal := greyPalette()
+	bitmapper := bitmap.NewBitmapper(&pal)
+
+	fit := bitmapper.Fit(uniformImage(color.RGBA{R: 0x80, G: 0x80, B: 0x80, A: 0xFF}))
+
+	assert.InDelta(t, 0.0, fit.AverageError, 0.0001, "no error expected")
+	assert.False(t, fit.ReindexRecommended, "no reindex recommendation expected")
+}
+
+func TestBitmapperFitOfMismatchingImage(t *testing.T) {
+	pal := greyPalette()
+	bitmapper := bitmap.NewBitmapper(&pal)
+
+	fit := bitmapper.Fit(uniformImage(color.RGBA{R: 0xFF, G: 0x00, B: 0x00, A: 0xFF}))
+
+	assert.True(t, fit.AverageError > bitmap.PaletteFitThreshold, "error expected to exceed threshold: %v", fit.AverageError)
+	assert.True(t, fit.ReindexRecommended, "reindex recommendation expected")
+}
+
+func TestBitmapperFitIgnoresTransparentPixels(t *testing.T) {
+	pal := greyPalette()
+	bitmapper := bitmap.NewBitmapper(&pal)
+
+	fit := bitmapper.Fit(uniformImage(color.RGBA{}))
+
+	assert.Equal(t, bitmap.PaletteFit{}, fit)
+}
+
+func primaryPalette() bitmap.Palette {
+	var pal bitmap.Palette
+	for index := range pal {
+		pal[index] = bitmap.RGB{Red: 0xFF, Green: 0xFF, Blue: 0xFF}
+	}
+	pal[0x20] = bitmap.RGB{Red: 0x80, Green: 0x80, Blue: 0x80}
+	pal[0x21] = bitmap.RGB{Red: 0x00, Green: 0x00, Blue: 0xFF}
+	pal[0x22] = bitmap.RGB{Red: 0x00, Green: 0xFF, Blue: 0x00}
+	pal[0x23] = bitmap.RGB{Red: 0xFF, Green: 0x00, Blue: 0x00}
+	return pal
+}
+
+func TestBitmapperMapColorWithMetric(t *testing.T) {
+	tt := []struct {
+		metric   bitmap.ColorMetric
+		expected byte
+	}{
+		{metric: bitmap.ColorMetricCIELAB, expected: 0x21},
+		{metric: bitmap.ColorMetricRGB, expected: 0x20},
+		{metric: bitmap.ColorMetricWeightedRGB, expected: 0x23},
+	}
+
+	pal := primaryPalette()
+	for _, tc := range tt {
+		td := tc
+		t.Run(td.metric.String(), func(t *testing.T) {
+			bitmapper := bitmap.NewBitmapperWithMetric(&pal, td.metric)
+			assert.Equal(t, td.metric, bitmapper.Metric())
+			assert.Equal(t, td.expected, bitmapper.MapColor(color.RGBA{R: 0xFF, G: 0x00, B: 0xC0, A: 0xFF}))
+			assert.Equal(t, byte(0x22), bitmapper.MapColor(color.RGBA{R: 0x00, G: 0xFF, B: 0x00, A: 0xFF}), "exact match expected")
+		})
+	}
+}
+
+func TestBitmapperMapUsesMetric(t *testing.T) {
+	pal := primaryPalette()
+	bitmapper := bitmap.NewBitmapperWithMetric(&pal, bitmap.ColorMetricRGB)
+
+	bmp := bitmapper.Map(uniformImage(color.RGBA{R: 0xFF, G: 0x00, B: 0xC0, A: 0xFF}))
+
+	assert.Equal(t, []byte{
+		0x20, 0x20, 0x20, 0x20,
+		0x20, 0x20, 0x20, 0x20,
+		0x20, 0x20, 0x20, 0x20,
+		0x20, 0x20, 0x20, 0x20}, bmp.Pixels)
+}
+
+func noiseImages(count int, size int) []image.Image {
+	images := make([]image.Image, count)
+	seed := uint32(1)
+	for index := range images {
+		img := image.NewRGBA(image.Rect(0, 0, size, size))
+		for offset := range img.Pix {
+			seed = seed*1664525 + 1013904223
+			img.Pix[offset] = byte(seed >> 24)
+		}
+		images[index] = img
+	}
+	return images
+}
+
+func TestBitmapperMapAllKeepsOrderOfImages(t *testing.T) {
+	pal := greyPalette()
+	bitmapper := bitmap.NewBitmapper(&pal)
+	images := noiseImages(10, 8)
+
+	bitmaps := bitmapper.MapAll(images, 3)
+
+	require.Equal(t, len(images), len(bitmaps))
+	for index, img := range images {
+		assert.Equal(t, bitmapper.Map(img), bitmaps[index], "bitmap %d differs", index)
+	}
+}
+
+func TestBitmapperMapAllWithoutImages(t *testing.T) {
+	pal := greyPalette()
+	bitmapper := bitmap.NewBitmapper(&pal)
+
+	bitmaps := bitmapper.MapAll(nil, 0)
+
+	assert.Equal(t, 0, len(bitmaps))
+}
+
+func benchmarkMapAll(b *testing.B, concurrency int) {
+	pal := greyPalette()
+	bitmapper := bitmap.NewBitmapper(&pal)
+	images := noiseImages(8, 32)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		bitmapper.MapAll(images, concurrency)
+	}
+}
+
+func BenchmarkMapAllSerial(b *testing.B) {
+	benchmarkMapAll(b, 1)
+}
+
+func BenchmarkMapAllConcurrent(b *testing.B) {
+	benchmarkMapAll(b, 0)
+}
@@ -0,0 +1,70 @@
+package bitmap
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+)
+
+// ColorMetric selects how the distance between two colors is measured when mapping colors to a palette.
+//
+// All metrics convert each palette color once, when the bitmapper is created, and each distinct
+// query color once per mapping. The RGB metrics are cheap. The CIELAB metric requires a conversion
+// with cube roots per color, making it several times slower for images with many distinct colors,
+// yet it matches human perception best, especially for subtle gradients such as skin tones and skies.
+type ColorMetric int
+
+const (
+	// ColorMetricCIELAB measures the euclidean distance in the CIELAB color space.
+	// This is the default metric.
+	ColorMetricCIELAB ColorMetric = 0
+	// ColorMetricRGB measures the plain euclidean distance of the red, green, and blue components.
+	ColorMetricRGB ColorMetric = 1
+	// ColorMetricWeightedRGB measures the euclidean distance of the red, green, and blue components,
+	// weighted to approximate the sensitivity of the human eye ("redmean" approximation).
+	ColorMetricWeightedRGB ColorMetric = 2
+)
+
+// ColorMetrics returns all available metrics.
+func ColorMetrics() []ColorMetric {
+	return []ColorMetric{ColorMetricCIELAB, ColorMetricRGB, ColorMetricWeightedRGB}
+}
+
+// String returns the name of the metric.
+func (metric ColorMetric) String() string {
+	switch metric {
+	case ColorMetricCIELAB:
+		return "CIELAB"
+	case ColorMetricRGB:
+		return "RGB"
+	case ColorMetricWeightedRGB:
+		return "Weighted RGB"
+	default:
+		return fmt.Sprintf("Unknown%d", int(metric))
+	}
+}
+
+// colorPoint is a color converted into the space of a metric.
+type colorPoint [3]float64
+
+func (metric ColorMetric) pointFromColor(clr color.Color) colorPoint {
+	if metric == ColorMetricCIELAB {
+		return labPointFromColor(clr)
+	}
+	r, g, b, _ := clr.RGBA()
+	return colorPoint{float64(r) / float64(0xFFFF), float64(g) / float64(0xFFFF), float64(b) / float64(0xFFFF)}
+}
+
+// distance returns the distance between two points of the metric.
+// For all metrics, the distance between black and white is about 1.0.
+func (metric ColorMetric) distance(a, b colorPoint) float64 {
+	switch metric {
+	case ColorMetricRGB:
+		return math.Sqrt((square(a[0]-b[0]) + square(a[1]-b[1]) + square(a[2]-b[2])) / 3.0)
+	case ColorMetricWeightedRGB:
+		redMean := (a[0] + b[0]) / 2.0
+		return math.Sqrt(((2.0+redMean)*square(a[0]-b[0]) + 4.0*square(a[1]-b[1]) + (3.0-redMean)*square(a[2]-b[2])) / 9.0)
+	default:
+		return math.Sqrt(square(a[0]-b[0]) + square(a[1]-b[1]) + square(a[2]-b[2]))
+	}
+}
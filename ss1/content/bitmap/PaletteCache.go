@@ -52,7 +52,10 @@ func (cache *PaletteCache) Palette(key resource.Key) (pal Palette, err error) {
 	if err != nil {
 		return cache.defaultPalette, err
 	}
-	if (view.ContentType() != resource.Palette) || (view.BlockCount() != 1) {
+	if view.ContentType() != resource.Palette {
+		return cache.defaultPalette, resource.ErrContentTypeOf(key.ID, view.ContentType(), resource.Palette)
+	}
+	if view.BlockCount() != 1 {
 		return cache.defaultPalette, errors.New("resource is not a palette")
 	}
 	reader, err := view.Block(0)
@@ -0,0 +1,212 @@
+package bitmap
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// PaletteFormat identifies a file format for palettes.
+type PaletteFormat int
+
+// PaletteFormat constants.
+const (
+	// PaletteFormatRaw is a sequence of RGB triplets, as used in the game resources and Adobe .act files.
+	PaletteFormatRaw PaletteFormat = iota
+	// PaletteFormatJASC is the text format of Paint Shop Pro, typically with the extension .pal.
+	PaletteFormatJASC
+	// PaletteFormatGIMP is the text format of GIMP, typically with the extension .gpl.
+	PaletteFormatGIMP
+)
+
+const (
+	paletteSize       = len(Palette{})
+	rawPaletteSize    = paletteSize * 3
+	actFooterSize     = 4
+	jascHeader        = "JASC-PAL"
+	jascVersion       = "0100"
+	gimpHeader        = "GIMP Palette"
+	gimpColumnsPrefix = "Columns:"
+	gimpNamePrefix    = "Name:"
+)
+
+// PaletteFormatForFilename returns the format that is typically used for the extension of given file name.
+// Unknown extensions result in PaletteFormatRaw.
+func PaletteFormatForFilename(filename string) PaletteFormat {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".pal":
+		return PaletteFormatJASC
+	case ".gpl":
+		return PaletteFormatGIMP
+	default:
+		return PaletteFormatRaw
+	}
+}
+
+// String returns the textual representation of the value.
+func (format PaletteFormat) String() string {
+	switch format {
+	case PaletteFormatRaw:
+		return "Raw"
+	case PaletteFormatJASC:
+		return "JASC"
+	case PaletteFormatGIMP:
+		return "GIMP"
+	default:
+		return fmt.Sprintf("Unknown%d", int(format))
+	}
+}
+
+// Encode writes the given palette in this format.
+func (format PaletteFormat) Encode(writer io.Writer, pal Palette) error {
+	buf := bytes.NewBuffer(nil)
+	switch format {
+	case PaletteFormatRaw:
+		for _, col := range pal {
+			buf.Write([]byte{col.Red, col.Green, col.Blue})
+		}
+	case PaletteFormatJASC:
+		fmt.Fprintf(buf, "%s\r\n%s\r\n%d\r\n", jascHeader, jascVersion, paletteSize)
+		for _, col := range pal {
+			fmt.Fprintf(buf, "%d %d %d\r\n", col.Red, col.Green, col.Blue)
+		}
+	case PaletteFormatGIMP:
+		fmt.Fprintf(buf, "%s\n%s System Shock\n%s 16\n#\n", gimpHeader, gimpNamePrefix, gimpColumnsPrefix)
+		for index, col := range pal {
+			fmt.Fprintf(buf, "%3d %3d %3d\tIndex %d\n", col.Red, col.Green, col.Blue, index)
+		}
+	default:
+		return fmt.Errorf("unsupported palette format %v", format)
+	}
+	_, err := writer.Write(buf.Bytes())
+	return err
+}
+
+// Decode reads a palette in this format.
+// Palettes with fewer than 256 entries are padded with black. Palettes with more entries are rejected.
+func (format PaletteFormat) Decode(reader io.Reader) (Palette, error) {
+	var colors []RGB
+	var err error
+	switch format {
+	case PaletteFormatRaw:
+		colors, err = decodeRawPalette(reader)
+	case PaletteFormatJASC:
+		colors, err = decodeJASCPalette(reader)
+	case PaletteFormatGIMP:
+		colors, err = decodeGIMPPalette(reader)
+	default:
+		err = fmt.Errorf("unsupported palette format %v", format)
+	}
+	if err != nil {
+		return Palette{}, err
+	}
+	if len(colors) > paletteSize {
+		return Palette{}, fmt.Errorf("palette has %d entries, more than %d", len(colors), paletteSize)
+	}
+	var pal Palette
+	copy(pal[:], colors)
+	return pal, nil
+}
+
+func decodeRawPalette(reader io.Reader) ([]RGB, error) {
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == rawPaletteSize+actFooterSize {
+		// Adobe .act files may have a footer with the number of colors and the transparent index.
+		count := int(data[rawPaletteSize])<<8 | int(data[rawPaletteSize+1])
+		if (count > 0) && (count <= paletteSize) {
+			data = data[:count*3]
+		} else {
+			data = data[:rawPaletteSize]
+		}
+	}
+	if (len(data) % 3) != 0 {
+		return nil, errors.New("raw palette size is not a multiple of three")
+	}
+	colors := make([]RGB, len(data)/3)
+	for index := range colors {
+		colors[index] = RGB{Red: data[index*3], Green: data[index*3+1], Blue: data[index*3+2]}
+	}
+	return colors, nil
+}
+
+func decodeJASCPalette(reader io.Reader) ([]RGB, error) {
+	lines, err := paletteTextLines(reader)
+	if err != nil {
+		return nil, err
+	}
+	if (len(lines) < 3) || (lines[0] != jascHeader) {
+		return nil, errors.New("missing JASC palette header")
+	}
+	if lines[1] != jascVersion {
+		return nil, fmt.Errorf("unsupported JASC palette version %q", lines[1])
+	}
+	count, err := strconv.Atoi(lines[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid JASC palette color count: %v", err)
+	}
+	entries := lines[3:]
+	if count != len(entries) {
+		return nil, fmt.Errorf("JASC palette specifies %d colors, but has %d", count, len(entries))
+	}
+	return parsePaletteEntries(entries)
+}
+
+func decodeGIMPPalette(reader io.Reader) ([]RGB, error) {
+	lines, err := paletteTextLines(reader)
+	if err != nil {
+		return nil, err
+	}
+	if (len(lines) < 1) || (lines[0] != gimpHeader) {
+		return nil, errors.New("missing GIMP palette header")
+	}
+	var entries []string
+	for _, line := range lines[1:] {
+		if strings.HasPrefix(line, "#") || strings.HasPrefix(line, gimpNamePrefix) ||
+			strings.HasPrefix(line, gimpColumnsPrefix) {
+			continue
+		}
+		entries = append(entries, line)
+	}
+	return parsePaletteEntries(entries)
+}
+
+func paletteTextLines(reader io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) > 0 {
+			lines = append(lines, line)
+		}
+	}
+	return lines, scanner.Err()
+}
+
+func parsePaletteEntries(entries []string) ([]RGB, error) {
+	colors := make([]RGB, len(entries))
+	for index, entry := range entries {
+		fields := strings.Fields(entry)
+		if len(fields) < 3 {
+			return nil, fmt.Errorf("palette entry %d is incomplete", index)
+		}
+		var values [3]byte
+		for channel := range values {
+			value, err := strconv.ParseUint(fields[channel], 10, 8)
+			if err != nil {
+				return nil, fmt.Errorf("palette entry %d has invalid value: %v", index, err)
+			}
+			values[channel] = byte(value)
+		}
+		colors[index] = RGB{Red: values[0], Green: values[1], Blue: values[2]}
+	}
+	return colors, nil
+}
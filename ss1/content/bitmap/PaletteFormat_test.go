@@ -0,0 +1,99 @@
+package bitmap_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/inkyblackness/hacked/ss1/content/bitmap"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testPalette() bitmap.Palette {
+	var pal bitmap.Palette
+	for index := range pal {
+		pal[index] = bitmap.RGB{Red: byte(index), Green: byte(255 - index), Blue: byte(index * 3)}
+	}
+	return pal
+}
+
+func TestPaletteFormatsRoundTrip(t *testing.T) {
+	for _, format := range []bitmap.PaletteFormat{bitmap.PaletteFormatRaw, bitmap.PaletteFormatJASC, bitmap.PaletteFormatGIMP} {
+		t.Run(format.String(), func(t *testing.T) {
+			buf := bytes.NewBuffer(nil)
+			err := format.Encode(buf, testPalette())
+			require.Nil(t, err, "no error expected encoding")
+			pal, err := format.Decode(bytes.NewReader(buf.Bytes()))
+			require.Nil(t, err, "no error expected decoding")
+			assert.Equal(t, testPalette(), pal)
+		})
+	}
+}
+
+func TestPaletteFormatDecodePadsShortPalettes(t *testing.T) {
+	tt := []struct {
+		format bitmap.PaletteFormat
+		data   string
+	}{
+		{format: bitmap.PaletteFormatRaw, data: "\x01\x02\x03\x04\x05\x06"},
+		{format: bitmap.PaletteFormatJASC, data: "JASC-PAL\r\n0100\r\n2\r\n1 2 3\r\n4 5 6\r\n"},
+		{format: bitmap.PaletteFormatGIMP, data: "GIMP Palette\nName: Test\nColumns: 2\n#\n1 2 3\tfirst\n4 5 6\n"},
+	}
+
+	for _, tc := range tt {
+		td := tc
+		t.Run(td.format.String(), func(t *testing.T) {
+			pal, err := td.format.Decode(strings.NewReader(td.data))
+			require.Nil(t, err, "no error expected")
+			assert.Equal(t, bitmap.RGB{Red: 1, Green: 2, Blue: 3}, pal[0])
+			assert.Equal(t, bitmap.RGB{Red: 4, Green: 5, Blue: 6}, pal[1])
+			assert.Equal(t, bitmap.RGB{}, pal[2])
+			assert.Equal(t, bitmap.RGB{}, pal[255])
+		})
+	}
+}
+
+func TestPaletteFormatDecodeRejectsLargePalettes(t *testing.T) {
+	_, err := bitmap.PaletteFormatRaw.Decode(bytes.NewReader(make([]byte, 257*3)))
+	assert.Error(t, err, "error expected")
+}
+
+func TestPaletteFormatDecodeRejectsInvalidData(t *testing.T) {
+	tt := []struct {
+		format bitmap.PaletteFormat
+		data   string
+	}{
+		{format: bitmap.PaletteFormatRaw, data: "\x01\x02"},
+		{format: bitmap.PaletteFormatJASC, data: "JASC-PAL\r\n0100\r\n2\r\n1 2 3\r\n"},
+		{format: bitmap.PaletteFormatJASC, data: "JASC-PAL\r\n0100\r\n1\r\n1 2 300\r\n"},
+		{format: bitmap.PaletteFormatGIMP, data: "Not a palette\n"},
+	}
+
+	for _, tc := range tt {
+		td := tc
+		t.Run(td.format.String(), func(t *testing.T) {
+			_, err := td.format.Decode(strings.NewReader(td.data))
+			assert.Error(t, err, "error expected")
+		})
+	}
+}
+
+func TestPaletteFormatDecodeHandlesAdobeColorTableFooter(t *testing.T) {
+	data := make([]byte, 256*3+4)
+	data[0] = 0x10
+	data[3] = 0x20
+	data[768] = 0x00
+	data[769] = 0x01
+	pal, err := bitmap.PaletteFormatRaw.Decode(bytes.NewReader(data))
+	require.Nil(t, err, "no error expected")
+	assert.Equal(t, byte(0x10), pal[0].Red)
+	assert.Equal(t, byte(0x00), pal[1].Red, "only one color expected")
+}
+
+func TestPaletteFormatForFilename(t *testing.T) {
+	assert.Equal(t, bitmap.PaletteFormatJASC, bitmap.PaletteFormatForFilename("test.PAL"))
+	assert.Equal(t, bitmap.PaletteFormatGIMP, bitmap.PaletteFormatForFilename("dir/test.gpl"))
+	assert.Equal(t, bitmap.PaletteFormatRaw, bitmap.PaletteFormatForFilename("test.act"))
+}
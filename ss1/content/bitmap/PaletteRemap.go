@@ -0,0 +1,103 @@
+package bitmap
+
+// IsRegularColorIndex returns true for palette indices that hold a fixed color.
+// Other indices are either transparent, or are cycled by the game to animate colors.
+func IsRegularColorIndex(index int) bool {
+	indexWithin := func(from, to int) bool {
+		return (index >= from) && (index <= to)
+	}
+	return indexWithin(0x01, 0x02) || indexWithin(0x08, 0x0A) || indexWithin(0x20, 0xFF)
+}
+
+// DuplicateColor describes a color that is stored at more than one index of a palette.
+type DuplicateColor struct {
+	Color   RGB
+	Indices []byte
+}
+
+// DuplicateColors returns all colors of the palette that are stored at more than one index.
+// The result is ordered by the first index of each color.
+func (pal Palette) DuplicateColors() []DuplicateColor {
+	var result []DuplicateColor
+	positions := make(map[RGB]int)
+	for index, clr := range pal {
+		position, known := positions[clr]
+		if !known {
+			positions[clr] = len(result)
+			result = append(result, DuplicateColor{Color: clr, Indices: []byte{byte(index)}})
+			continue
+		}
+		result[position].Indices = append(result[position].Indices, byte(index))
+	}
+	duplicates := result[:0]
+	for _, entry := range result {
+		if len(entry.Indices) > 1 {
+			duplicates = append(duplicates, entry)
+		}
+	}
+	return duplicates
+}
+
+// PaletteRemap maps each palette index to a new one.
+type PaletteRemap [256]byte
+
+// IdentityPaletteRemap returns a remap that keeps all indices.
+func IdentityPaletteRemap() PaletteRemap {
+	var remap PaletteRemap
+	for index := range remap {
+		remap[index] = byte(index)
+	}
+	return remap
+}
+
+// ConsolidationRemap returns a remap that maps each duplicated color to the lowest index it is stored at.
+// Only regular color indices are considered, see IsRegularColorIndex().
+func (pal Palette) ConsolidationRemap() PaletteRemap {
+	remap := IdentityPaletteRemap()
+	for _, duplicate := range pal.DuplicateColors() {
+		target := -1
+		for _, index := range duplicate.Indices {
+			if !IsRegularColorIndex(int(index)) {
+				continue
+			}
+			if target < 0 {
+				target = int(index)
+			} else {
+				remap[index] = byte(target)
+			}
+		}
+	}
+	return remap
+}
+
+// Freed returns the indices that are mapped to another index, in ascending order.
+func (remap PaletteRemap) Freed() []byte {
+	var freed []byte
+	for index, target := range remap {
+		if int(target) != index {
+			freed = append(freed, byte(index))
+		}
+	}
+	return freed
+}
+
+// Keeping returns a remap that maps the given indices to themselves, and all others as before.
+func (remap PaletteRemap) Keeping(indices []byte) PaletteRemap {
+	result := remap
+	for _, index := range indices {
+		result[index] = index
+	}
+	return result
+}
+
+// Apply replaces the given pixels with their mapped indices. It returns true if any pixel was changed.
+func (remap PaletteRemap) Apply(pixels []byte) bool {
+	changed := false
+	for index, pixel := range pixels {
+		if target := remap[pixel]; target != pixel {
+			pixels[index] = target
+			changed = true
+		}
+	}
+	return changed
+}
@@ -0,0 +1,80 @@
+package bitmap_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/inkyblackness/hacked/ss1/content/bitmap"
+)
+
+func uniquePalette() bitmap.Palette {
+	var pal bitmap.Palette
+	for index := range pal {
+		pal[index] = bitmap.RGB{Red: byte(index), Green: byte(255 - index), Blue: 0x10}
+	}
+	return pal
+}
+
+func TestDuplicateColorsOfUniquePaletteIsEmpty(t *testing.T) {
+	assert.Equal(t, 0, len(uniquePalette().DuplicateColors()))
+}
+
+func TestDuplicateColorsReportsAllIndicesOfColor(t *testing.T) {
+	pal := uniquePalette()
+	pal[0x40] = pal[0x30]
+	pal[0x50] = pal[0x30]
+	pal[0x41] = pal[0x21]
+
+	duplicates := pal.DuplicateColors()
+
+	assert.Equal(t, []bitmap.DuplicateColor{
+		{Color: pal[0x21], Indices: []byte{0x21, 0x41}},
+		{Color: pal[0x30], Indices: []byte{0x30, 0x40, 0x50}},
+	}, duplicates)
+}
+
+func TestConsolidationRemapMapsToLowestRegularIndex(t *testing.T) {
+	pal := uniquePalette()
+	pal[0x40] = pal[0x30]
+	pal[0x50] = pal[0x30]
+	pal[0x60] = pal[0x03]
+
+	remap := pal.ConsolidationRemap()
+
+	assert.Equal(t, byte(0x30), remap[0x40])
+	assert.Equal(t, byte(0x30), remap[0x50])
+	assert.Equal(t, byte(0x03), remap[0x03], "special index should be kept")
+	assert.Equal(t, byte(0x60), remap[0x60], "regular index should not map to special index")
+	assert.Equal(t, []byte{0x40, 0x50}, remap.Freed())
+}
+
+func TestConsolidationRemapKeepsTransparentIndex(t *testing.T) {
+	pal := uniquePalette()
+	pal[0x00] = pal[0x20]
+
+	remap := pal.ConsolidationRemap()
+
+	assert.Equal(t, 0, len(remap.Freed()))
+}
+
+func TestPaletteRemapApply(t *testing.T) {
+	remap := bitmap.IdentityPaletteRemap()
+	remap[0x40] = 0x30
+	pixels := []byte{0x00, 0x40, 0x41, 0x40}
+
+	assert.True(t, remap.Apply(pixels), "change expected")
+	assert.Equal(t, []byte{0x00, 0x30, 0x41, 0x30}, pixels)
+	assert.False(t, remap.Apply(pixels), "no further change expected")
+}
+
+func TestPaletteRemapKeeping(t *testing.T) {
+	remap := bitmap.IdentityPaletteRemap()
+	remap[0x40] = 0x30
+	remap[0x50] = 0x31
+
+	kept := remap.Keeping([]byte{0x40, 0x60})
+
+	assert.Equal(t, []byte{0x50}, kept.Freed())
+	assert.Equal(t, []byte{0x40, 0x50}, remap.Freed(), "original remap must not be modified")
+}
@@ -0,0 +1,87 @@
+package bitmap
+
+import "math/bits"
+
+const paletteSetWordBits = 64
+
+// PaletteSet is a set of palette indices. The zero value is an empty set.
+// Instances are comparable and can be used as map keys.
+type PaletteSet struct {
+	words [4]uint64
+}
+
+// PaletteSetOf returns a set containing the given indices.
+func PaletteSetOf(indices ...byte) PaletteSet {
+	var set PaletteSet
+	for _, index := range indices {
+		set.Add(index)
+	}
+	return set
+}
+
+// Add includes the given index in the set.
+func (set *PaletteSet) Add(index byte) {
+	set.words[index/paletteSetWordBits] |= 1 << (index % paletteSetWordBits)
+}
+
+// Has returns true if the given index is part of the set.
+func (set PaletteSet) Has(index byte) bool {
+	return (set.words[index/paletteSetWordBits] & (1 << (index % paletteSetWordBits))) != 0
+}
+
+// Contains returns true if all indices of the other set are part of this set.
+func (set PaletteSet) Contains(other PaletteSet) bool {
+	for i, word := range set.words {
+		if (^word & other.words[i]) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Subtract returns a set with all indices of this set that are not part of the other set.
+func (set PaletteSet) Subtract(other PaletteSet) PaletteSet {
+	var result PaletteSet
+	for i, word := range set.words {
+		result.words[i] = word & ^other.words[i]
+	}
+	return result
+}
+
+// Size returns the number of indices in the set.
+func (set PaletteSet) Size() int {
+	size := 0
+	for _, word := range set.words {
+		size += bits.OnesCount64(word)
+	}
+	return size
+}
+
+// Slice returns the indices of the set in ascending order.
+func (set PaletteSet) Slice() []byte {
+	result := make([]byte, 0, set.Size())
+	for i, word := range set.words {
+		for word != 0 {
+			bit := bits.TrailingZeros64(word)
+			result = append(result, byte(i*paletteSetWordBits+bit))
+			word &= word - 1
+		}
+	}
+	return result
+}
+
+// Words returns the set as four words of 64 bits. Bit n of word i is set if index i*64+n is part of the set.
+func (set PaletteSet) Words() [4]uint64 {
+	return set.words
+}
+
+// Less provides an ordering of sets, for sorting.
+// Sets are compared as if they were 256-bit numbers, with each index being a bit of the number.
+func (set PaletteSet) Less(other PaletteSet) bool {
+	for i := len(set.words) - 1; i >= 0; i-- {
+		if set.words[i] != other.words[i] {
+			return set.words[i] < other.words[i]
+		}
+	}
+	return false
+}
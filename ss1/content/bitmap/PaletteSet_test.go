@@ -0,0 +1,122 @@
+package bitmap_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/inkyblackness/hacked/ss1/content/bitmap"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var paletteSetBoundaryIndices = []byte{0, 1, 62, 63, 64, 65, 126, 127, 128, 129, 190, 191, 192, 193, 254, 255}
+
+func TestPaletteSetZeroValueIsEmpty(t *testing.T) {
+	var set bitmap.PaletteSet
+	for index := 0; index < 256; index++ {
+		assert.False(t, set.Has(byte(index)), "index %v should not be set", index)
+	}
+	assert.Equal(t, 0, set.Size())
+	assert.Equal(t, []byte{}, set.Slice())
+}
+
+func TestPaletteSetHasOnlyAddedIndexAtBoundaries(t *testing.T) {
+	for _, index := range paletteSetBoundaryIndices {
+		td := index
+		t.Run(fmt.Sprintf("%v", td), func(t *testing.T) {
+			set := bitmap.PaletteSetOf(td)
+			for other := 0; other < 256; other++ {
+				assert.Equal(t, int(td) == other, set.Has(byte(other)), "wrong result for %v", other)
+			}
+			assert.Equal(t, 1, set.Size())
+			assert.Equal(t, []byte{td}, set.Slice())
+		})
+	}
+}
+
+func TestPaletteSetCanHoldAllIndices(t *testing.T) {
+	var set bitmap.PaletteSet
+	for index := 255; index >= 0; index-- {
+		set.Add(byte(index))
+		set.Add(byte(index))
+	}
+	assert.Equal(t, 256, set.Size())
+	slice := set.Slice()
+	for index, value := range slice {
+		assert.Equal(t, byte(index), value)
+	}
+}
+
+func TestPaletteSetContains(t *testing.T) {
+	base := bitmap.PaletteSetOf(paletteSetBoundaryIndices...)
+	tt := []struct {
+		other    []byte
+		expected bool
+	}{
+		{other: paletteSetBoundaryIndices, expected: true},
+		{other: []byte{}, expected: true},
+		{other: []byte{63, 64}, expected: true},
+		{other: []byte{127, 128, 255}, expected: true},
+		{other: []byte{2}, expected: false},
+		{other: []byte{63, 66}, expected: false},
+		{other: []byte{0, 189}, expected: false},
+		{other: []byte{253}, expected: false},
+	}
+
+	for index, tc := range tt {
+		td := tc
+		t.Run(fmt.Sprintf("%v", index), func(t *testing.T) {
+			assert.Equal(t, td.expected, base.Contains(bitmap.PaletteSetOf(td.other...)))
+		})
+	}
+}
+
+func TestPaletteSetSubtract(t *testing.T) {
+	base := bitmap.PaletteSetOf(0, 63, 64, 127, 128, 191, 192, 255)
+	tt := []struct {
+		other    []byte
+		expected []byte
+	}{
+		{other: []byte{}, expected: []byte{0, 63, 64, 127, 128, 191, 192, 255}},
+		{other: []byte{63, 64}, expected: []byte{0, 127, 128, 191, 192, 255}},
+		{other: []byte{0, 255, 1, 254}, expected: []byte{63, 64, 127, 128, 191, 192}},
+		{other: []byte{0, 63, 64, 127, 128, 191, 192, 255}, expected: []byte{}},
+	}
+
+	for index, tc := range tt {
+		td := tc
+		t.Run(fmt.Sprintf("%v", index), func(t *testing.T) {
+			result := base.Subtract(bitmap.PaletteSetOf(td.other...))
+			assert.Equal(t, bitmap.PaletteSetOf(td.expected...), result)
+			assert.Equal(t, td.expected, result.Slice())
+			assert.Equal(t, len(td.expected), result.Size())
+		})
+	}
+}
+
+func TestPaletteSetWords(t *testing.T) {
+	words := bitmap.PaletteSetOf(0, 63, 64, 130, 255).Words()
+
+	assert.Equal(t, [4]uint64{0x8000000000000001, 0x0000000000000001, 0x0000000000000004, 0x8000000000000000}, words)
+}
+
+func TestPaletteSetLess(t *testing.T) {
+	tt := []struct {
+		a, b     []byte
+		expected bool
+	}{
+		{a: []byte{}, b: []byte{0}, expected: true},
+		{a: []byte{0}, b: []byte{}, expected: false},
+		{a: []byte{63}, b: []byte{64}, expected: true},
+		{a: []byte{200}, b: []byte{0, 1, 2, 100}, expected: false},
+		{a: []byte{0, 255}, b: []byte{1, 255}, expected: true},
+		{a: []byte{5}, b: []byte{5}, expected: false},
+	}
+
+	for index, tc := range tt {
+		td := tc
+		t.Run(fmt.Sprintf("%v", index), func(t *testing.T) {
+			assert.Equal(t, td.expected, bitmap.PaletteSetOf(td.a...).Less(bitmap.PaletteSetOf(td.b...)))
+		})
+	}
+}
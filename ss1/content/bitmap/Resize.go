@@ -0,0 +1,137 @@
+package bitmap
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+)
+
+// ResizeMode selects how the pixels of a resized image are determined.
+type ResizeMode int
+
+const (
+	// ResizeNearest takes the color of the nearest source pixel. This keeps hard edges, as in pixel art.
+	ResizeNearest ResizeMode = 0
+	// ResizeBilinear interpolates the colors of the four nearest source pixels.
+	ResizeBilinear ResizeMode = 1
+)
+
+// ResizeModes returns all available modes.
+func ResizeModes() []ResizeMode {
+	return []ResizeMode{ResizeNearest, ResizeBilinear}
+}
+
+// String returns the name of the mode.
+func (mode ResizeMode) String() string {
+	switch mode {
+	case ResizeNearest:
+		return "Nearest"
+	case ResizeBilinear:
+		return "Bilinear"
+	default:
+		return fmt.Sprintf("Unknown%d", int(mode))
+	}
+}
+
+// aspectRatioTolerance is the relative difference of aspect ratios that is still considered equal.
+const aspectRatioTolerance = 0.01
+
+// AspectRatioChanged returns true if an image of given source size would be distorted
+// when resized to the given target size.
+func AspectRatioChanged(source, target image.Point) bool {
+	if (source.X <= 0) || (source.Y <= 0) || (target.X <= 0) || (target.Y <= 0) {
+		return false
+	}
+	sourceRatio := float64(source.X) / float64(source.Y)
+	targetRatio := float64(target.X) / float64(target.Y)
+	return math.Abs(sourceRatio-targetRatio) > (sourceRatio * aspectRatioTolerance)
+}
+
+// Resized returns the image scaled to the given size.
+// The colors are determined in RGB space, which makes this also suitable for paletted images:
+// Their result is meant to be mapped to a palette again, for example with a Bitmapper.
+func Resized(img image.Image, width, height int, mode ResizeMode) *image.RGBA64 {
+	result := image.NewRGBA64(image.Rect(0, 0, width, height))
+	drawResized(result, result.Bounds(), img, mode)
+	return result
+}
+
+// Letterboxed returns the image scaled to fit into the given size while keeping its aspect ratio.
+// The scaled image is centered; the remaining border is transparent.
+func Letterboxed(img image.Image, width, height int, mode ResizeMode) *image.RGBA64 {
+	result := image.NewRGBA64(image.Rect(0, 0, width, height))
+	source := img.Bounds().Size()
+	if (source.X <= 0) || (source.Y <= 0) {
+		return result
+	}
+	scale := math.Min(float64(width)/float64(source.X), float64(height)/float64(source.Y))
+	scaledWidth := int(math.Max(1, math.Round(float64(source.X)*scale)))
+	scaledHeight := int(math.Max(1, math.Round(float64(source.Y)*scale)))
+	left := (width - scaledWidth) / 2
+	top := (height - scaledHeight) / 2
+	drawResized(result, image.Rect(left, top, left+scaledWidth, top+scaledHeight), img, mode)
+	return result
+}
+
+func drawResized(target *image.RGBA64, area image.Rectangle, img image.Image, mode ResizeMode) {
+	bounds := img.Bounds()
+	if bounds.Empty() || area.Empty() {
+		return
+	}
+	scaleX := float64(bounds.Dx()) / float64(area.Dx())
+	scaleY := float64(bounds.Dy()) / float64(area.Dy())
+	for y := 0; y < area.Dy(); y++ {
+		sourceY := (float64(y)+0.5)*scaleY - 0.5
+		for x := 0; x < area.Dx(); x++ {
+			sourceX := (float64(x)+0.5)*scaleX - 0.5
+			var clr color.RGBA64
+			if mode == ResizeBilinear {
+				clr = bilinearColorAt(img, bounds, sourceX, sourceY)
+			} else {
+				clr = color.RGBA64Model.Convert(img.At(
+					bounds.Min.X+clampedIndex(int(math.Round(sourceX)), bounds.Dx()),
+					bounds.Min.Y+clampedIndex(int(math.Round(sourceY)), bounds.Dy()))).(color.RGBA64)
+			}
+			target.SetRGBA64(area.Min.X+x, area.Min.Y+y, clr)
+		}
+	}
+}
+
+func bilinearColorAt(img image.Image, bounds image.Rectangle, x, y float64) color.RGBA64 {
+	baseX, baseY := math.Floor(x), math.Floor(y)
+	fractionX, fractionY := x-baseX, y-baseY
+	left := clampedIndex(int(baseX), bounds.Dx())
+	right := clampedIndex(int(baseX)+1, bounds.Dx())
+	top := clampedIndex(int(baseY), bounds.Dy())
+	bottom := clampedIndex(int(baseY)+1, bounds.Dy())
+
+	var sum [4]float64
+	add := func(column, row int, weight float64) {
+		r, g, b, a := img.At(bounds.Min.X+column, bounds.Min.Y+row).RGBA()
+		sum[0] += float64(r) * weight
+		sum[1] += float64(g) * weight
+		sum[2] += float64(b) * weight
+		sum[3] += float64(a) * weight
+	}
+	add(left, top, (1-fractionX)*(1-fractionY))
+	add(right, top, fractionX*(1-fractionY))
+	add(left, bottom, (1-fractionX)*fractionY)
+	add(right, bottom, fractionX*fractionY)
+	return color.RGBA64{
+		R: uint16(math.Round(sum[0])),
+		G: uint16(math.Round(sum[1])),
+		B: uint16(math.Round(sum[2])),
+		A: uint16(math.Round(sum[3])),
+	}
+}
+
+func clampedIndex(index, count int) int {
+	if index < 0 {
+		return 0
+	}
+	if index >= count {
+		return count - 1
+	}
+	return index
+}
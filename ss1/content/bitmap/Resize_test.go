@@ -0,0 +1,80 @@
+package bitmap_test
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/inkyblackness/hacked/ss1/content/bitmap"
+)
+
+func twoColorPalettedImage() *image.Paletted {
+	pal := color.Palette{color.RGBA{A: 0xFF}, color.RGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF}}
+	img := image.NewPaletted(image.Rect(0, 0, 2, 1), pal)
+	img.SetColorIndex(0, 0, 0)
+	img.SetColorIndex(1, 0, 1)
+	return img
+}
+
+func TestResizedNearestKeepsSourceColors(t *testing.T) {
+	result := bitmap.Resized(twoColorPalettedImage(), 4, 2, bitmap.ResizeNearest)
+
+	assert.Equal(t, image.Rect(0, 0, 4, 2), result.Bounds())
+	for y := 0; y < 2; y++ {
+		assert.Equal(t, color.RGBA64{A: 0xFFFF}, result.RGBA64At(0, y))
+		assert.Equal(t, color.RGBA64{A: 0xFFFF}, result.RGBA64At(1, y))
+		assert.Equal(t, color.RGBA64{R: 0xFFFF, G: 0xFFFF, B: 0xFFFF, A: 0xFFFF}, result.RGBA64At(2, y))
+		assert.Equal(t, color.RGBA64{R: 0xFFFF, G: 0xFFFF, B: 0xFFFF, A: 0xFFFF}, result.RGBA64At(3, y))
+	}
+}
+
+func TestResizedBilinearInterpolatesInRGBSpace(t *testing.T) {
+	result := bitmap.Resized(twoColorPalettedImage(), 4, 1, bitmap.ResizeBilinear)
+
+	assert.Equal(t, color.RGBA64{A: 0xFFFF}, result.RGBA64At(0, 0))
+	assert.Equal(t, color.RGBA64{R: 0x4000, G: 0x4000, B: 0x4000, A: 0xFFFF}, result.RGBA64At(1, 0))
+	assert.Equal(t, color.RGBA64{R: 0xBFFF, G: 0xBFFF, B: 0xBFFF, A: 0xFFFF}, result.RGBA64At(2, 0))
+	assert.Equal(t, color.RGBA64{R: 0xFFFF, G: 0xFFFF, B: 0xFFFF, A: 0xFFFF}, result.RGBA64At(3, 0))
+}
+
+func TestResizedResultCanBeMappedToPalette(t *testing.T) {
+	pal := greyPalette()
+	bitmapper := bitmap.NewBitmapperWithMetric(&pal, bitmap.ColorMetricRGB)
+
+	bmp := bitmapper.Map(bitmap.Resized(twoColorPalettedImage(), 4, 1, bitmap.ResizeBilinear))
+
+	assert.Equal(t, []byte{0x01, 0x40, 0xBF, 0xFF}, bmp.Pixels)
+}
+
+func TestLetterboxedKeepsAspectRatio(t *testing.T) {
+	result := bitmap.Letterboxed(twoColorPalettedImage(), 4, 4, bitmap.ResizeNearest)
+
+	assert.Equal(t, image.Rect(0, 0, 4, 4), result.Bounds())
+	assert.Equal(t, color.RGBA64{}, result.RGBA64At(0, 0), "border should be transparent")
+	assert.Equal(t, color.RGBA64{}, result.RGBA64At(3, 3), "border should be transparent")
+	assert.Equal(t, color.RGBA64{A: 0xFFFF}, result.RGBA64At(0, 1))
+	assert.Equal(t, color.RGBA64{R: 0xFFFF, G: 0xFFFF, B: 0xFFFF, A: 0xFFFF}, result.RGBA64At(3, 2))
+}
+
+func TestAspectRatioChanged(t *testing.T) {
+	tt := []struct {
+		source   image.Point
+		target   image.Point
+		expected bool
+	}{
+		{source: image.Pt(64, 64), target: image.Pt(128, 128), expected: false},
+		{source: image.Pt(640, 480), target: image.Pt(320, 240), expected: false},
+		{source: image.Pt(640, 480), target: image.Pt(128, 128), expected: true},
+		{source: image.Pt(200, 201), target: image.Pt(64, 64), expected: false},
+		{source: image.Pt(0, 10), target: image.Pt(64, 64), expected: false},
+	}
+	for _, tc := range tt {
+		td := tc
+		t.Run("", func(t *testing.T) {
+			assert.Equal(t, td.expected, bitmap.AspectRatioChanged(td.source, td.target),
+				"mismatch for %v -> %v", td.source, td.target)
+		})
+	}
+}
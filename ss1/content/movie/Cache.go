@@ -44,11 +44,15 @@ func (cache *Cache) Movie(key resource.Key) (Container, error) {
 		return value, nil
 	}
 	selector := cache.localizer.LocalizedResources(key.Lang)
-	view, err := selector.Select(key.ID.Plus(key.Index))
+	id := key.ID.Plus(key.Index)
+	view, err := selector.Select(id)
 	if err != nil {
-		return nil, errors.New("no movie found")
+		return nil, err
+	}
+	if view.ContentType() != resource.Movie {
+		return nil, resource.ErrContentTypeOf(id, view.ContentType(), resource.Movie)
 	}
-	if (view.ContentType() != resource.Movie) || view.Compound() || (view.BlockCount() != 1) {
+	if view.Compound() || (view.BlockCount() != 1) {
 		return nil, errors.New("invalid resource type")
 	}
 	reader, err := view.Block(0)
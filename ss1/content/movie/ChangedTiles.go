@@ -0,0 +1,38 @@
+package movie
+
+import (
+	"github.com/inkyblackness/hacked/ss1/content/movie/internal/compression"
+)
+
+// TileSideLength is the number of pixel per side of the tiles video frames are encoded in.
+const TileSideLength = compression.TileSideLength
+
+// TileDelta contains the pixel of one frame tile that changed compared to the previous frame, row by row.
+// Unchanged pixel have the value zero, which is the transparent color. As a result, a pixel that
+// changes to color zero is not considered a change.
+type TileDelta [compression.PixelPerTile]byte
+
+// ChangedTile describes a tile that differs between two frames.
+type ChangedTile struct {
+	// HTile is the horizontal position of the tile, in tiles.
+	HTile int
+	// VTile is the vertical position of the tile, in tiles.
+	VTile int
+	// Delta contains the changed pixel.
+	Delta TileDelta
+}
+
+// ChangedTiles compares two consecutive, paletted frames of given size and returns all tiles that differ.
+// The deltas are the same the encoder determines, from which it creates the palette lookup of a scene.
+// Only complete tiles are considered, should the dimensions not be a multiple of TileSideLength.
+func ChangedTiles(previous, next []byte, width, height int) ([]ChangedTile, error) {
+	tiles, err := compression.ChangedTiles(previous, next, width, height)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]ChangedTile, len(tiles))
+	for index, tile := range tiles {
+		result[index] = ChangedTile{HTile: tile.HTile, VTile: tile.VTile, Delta: TileDelta(tile.Delta)}
+	}
+	return result, nil
+}
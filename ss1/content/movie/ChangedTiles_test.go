@@ -0,0 +1,35 @@
+package movie_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/content/movie"
+)
+
+func TestChangedTilesReturnsSingleChangedTile(t *testing.T) {
+	width, height := 16, 8
+	previous := make([]byte, width*height)
+	for index := range previous {
+		previous[index] = 0x10
+	}
+	next := make([]byte, len(previous))
+	copy(next, previous)
+	next[4*width+12] = 0x20
+	next[7*width+15] = 0x30
+
+	changed, err := movie.ChangedTiles(previous, next, width, height)
+	require.Nil(t, err, "no error expected")
+
+	var expectedDelta movie.TileDelta
+	expectedDelta[0] = 0x20
+	expectedDelta[3*movie.TileSideLength+3] = 0x30
+	assert.Equal(t, []movie.ChangedTile{{HTile: 3, VTile: 1, Delta: expectedDelta}}, changed)
+}
+
+func TestChangedTilesReturnsErrorForMismatchingFrames(t *testing.T) {
+	_, err := movie.ChangedTiles(make([]byte, 16), make([]byte, 12), 4, 4)
+	assert.NotNil(t, err, "error expected")
+}
@@ -0,0 +1,92 @@
+package movie
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/inkyblackness/hacked/ss1/content/movie/internal/format"
+)
+
+// ChunkInfo describes one raw entry of a MOVI container, without its decoded content.
+type ChunkInfo struct {
+	// Type is the type of the entry. It may be a type that is not known to this package.
+	Type DataType
+	// Timestamp is the time, in seconds, at which the entry is used.
+	Timestamp float32
+	// Offset is the position of the data, relative to the start of the container.
+	Offset int64
+	// Length is the number of data bytes, as given by the offset of the following entry.
+	// It is zero for the last entry. A negative length indicates offsets in wrong order.
+	Length int64
+	// InBounds is set if the data lies completely within the container.
+	InBounds bool
+}
+
+// String returns a one-line description of the chunk.
+func (info ChunkInfo) String() string {
+	bounds := ""
+	if !info.InBounds {
+		bounds = " (out of bounds)"
+	}
+	return fmt.Sprintf("%8.3f %-20v @%08X %6d bytes%v", info.Timestamp, info.Type, info.Offset, info.Length, bounds)
+}
+
+// ReadChunks lists the entries of a MOVI container from the provided reader, without reading their data.
+// Only the header and the index are read. Entries of unknown type, or with invalid offsets, are listed
+// nevertheless, so that malformed movies can be inspected.
+// An error is only returned if the header or the index can not be read.
+// On return, the position of the reader is undefined.
+func ReadChunks(source io.ReadSeeker) ([]ChunkInfo, error) {
+	if source == nil {
+		return nil, fmt.Errorf("source is nil")
+	}
+	startPos, err := source.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, err
+	}
+	endPos, err := source.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, err
+	}
+	_, err = source.Seek(startPos, io.SeekStart)
+	if err != nil {
+		return nil, err
+	}
+	var header format.Header
+	err = binary.Read(source, binary.LittleEndian, &header)
+	if err != nil {
+		return nil, errFormat("header truncated: %v", err)
+	}
+	if string(header.Tag[:]) != format.Tag {
+		return nil, errFormat("not a MOVI format")
+	}
+	indexPos := startPos + format.HeaderSize + startPaletteSize
+	if (header.IndexEntryCount < 0) ||
+		(int64(header.IndexEntryCount)*format.IndexTableEntrySize > endPos-indexPos) {
+		return nil, errFormat("invalid index entry count %d", header.IndexEntryCount)
+	}
+	_, err = source.Seek(indexPos, io.SeekStart)
+	if err != nil {
+		return nil, err
+	}
+	indexEntries := make([]format.IndexTableEntry, header.IndexEntryCount)
+	err = binary.Read(source, binary.LittleEndian, indexEntries)
+	if err != nil {
+		return nil, errFormat("index truncated: %v", err)
+	}
+
+	containerSize := endPos - startPos
+	chunks := make([]ChunkInfo, len(indexEntries))
+	for index, indexEntry := range indexEntries {
+		chunk := &chunks[index]
+		chunk.Type = DataType(indexEntry.Type)
+		chunk.Timestamp = timeFromRaw(indexEntry.TimestampSecond, indexEntry.TimestampFraction)
+		chunk.Offset = int64(indexEntry.DataOffset)
+		if index+1 < len(indexEntries) {
+			chunk.Length = int64(indexEntries[index+1].DataOffset) - chunk.Offset
+		}
+		chunk.InBounds = (chunk.Offset >= 0) && (chunk.Length >= 0) && (chunk.Offset+chunk.Length <= containerSize)
+	}
+	return chunks, nil
+}
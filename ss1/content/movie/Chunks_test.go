@@ -0,0 +1,63 @@
+package movie_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/content/movie"
+)
+
+func writtenContainer(t *testing.T, entries ...movie.Entry) []byte {
+	t.Helper()
+	builder := movie.NewContainerBuilder()
+	for _, entry := range entries {
+		builder.AddEntry(entry)
+	}
+	buf := bytes.NewBuffer(nil)
+	err := movie.Write(buf, builder.Build())
+	require.Nil(t, err, "no error expected writing container")
+	return buf.Bytes()
+}
+
+func TestReadChunksListsEntries(t *testing.T) {
+	data := writtenContainer(t,
+		movie.NewMemoryEntry(0.0, movie.Audio, []byte{0x80, 0x80, 0x80}),
+		movie.NewMemoryEntry(0.5, movie.DataType(0x77), []byte{0x01}))
+
+	chunks, err := movie.ReadChunks(bytes.NewReader(data))
+	require.Nil(t, err, "no error expected")
+	require.Equal(t, 3, len(chunks))
+
+	assert.Equal(t, movie.Audio, chunks[0].Type)
+	assert.Equal(t, int64(3), chunks[0].Length)
+	assert.True(t, chunks[0].InBounds, "first chunk should be in bounds")
+	assert.Equal(t, float32(0.5), chunks[1].Timestamp)
+	assert.Equal(t, "unknown (0x77)", chunks[1].Type.String())
+	assert.False(t, chunks[1].Type.Known(), "type should be unknown")
+	assert.Equal(t, chunks[0].Offset+3, chunks[1].Offset)
+	assert.Equal(t, "EndOfMedia", chunks[2].Type.String())
+	assert.Equal(t, int64(len(data)), chunks[2].Offset)
+}
+
+func TestReadChunksReportsOutOfBoundsEntries(t *testing.T) {
+	data := writtenContainer(t, movie.NewMemoryEntry(0.0, movie.Audio, []byte{0x80, 0x80}))
+	indexStart := 0x100 + 0x300
+	binary.LittleEndian.PutUint32(data[indexStart+8+4:], 0x7FFFFFFF)
+
+	chunks, err := movie.ReadChunks(bytes.NewReader(data))
+	require.Nil(t, err, "no error expected")
+	require.Equal(t, 2, len(chunks))
+	assert.False(t, chunks[0].InBounds, "chunk should be out of bounds")
+	assert.False(t, chunks[1].InBounds, "end should be out of bounds")
+}
+
+func TestReadChunksReturnsErrorForMissingTag(t *testing.T) {
+	_, err := movie.ReadChunks(bytes.NewReader(make([]byte, 0x800)))
+
+	assert.True(t, errors.Is(err, movie.ErrMalformed), "malformed error expected, got %v", err)
+}
@@ -16,6 +16,18 @@ func NewContainerBuilder() *ContainerBuilder {
 	return builder
 }
 
+// NewContainerBuilderWithHeaderOf returns a new builder for creating a new container,
+// with the header properties initialized from the given container. Entries are not copied.
+func NewContainerBuilderWithHeaderOf(container Container) *ContainerBuilder {
+	palette := container.StartPalette()
+	return NewContainerBuilder().
+		MediaDuration(container.MediaDuration()).
+		VideoWidth(container.VideoWidth()).
+		VideoHeight(container.VideoHeight()).
+		StartPalette(&palette).
+		AudioSampleRate(container.AudioSampleRate())
+}
+
 // Build returns the immutable instance of a new container.
 func (builder *ContainerBuilder) Build() Container {
 	return builder.container
@@ -56,3 +68,9 @@ func (builder *ContainerBuilder) AddEntry(entry Entry) *ContainerBuilder {
 	builder.container.entries = append(builder.container.entries, entry)
 	return builder
 }
+
+// WriteEntry adds the given entry to the list. It allows the builder to be used as an EntryWriter.
+func (builder *ContainerBuilder) WriteEntry(entry Entry) error {
+	builder.AddEntry(entry)
+	return nil
+}
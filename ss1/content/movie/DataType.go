@@ -1,5 +1,7 @@
 package movie
 
+import "fmt"
+
 // DataType identifies entries
 type DataType byte
 
@@ -23,3 +25,39 @@ const (
 	// ControlDictionary for high compression video
 	ControlDictionary = DataType(0x0D)
 )
+
+// Known returns true for the types this package understands.
+func (dataType DataType) Known() bool {
+	switch dataType {
+	case endOfMedia, LowResVideo, HighResVideo, Audio, Subtitle, Palette, PaletteReset, PaletteLookupList, ControlDictionary:
+		return true
+	default:
+		return false
+	}
+}
+
+// String returns the name of the type. Types that are not known are named "unknown".
+func (dataType DataType) String() string {
+	switch dataType {
+	case endOfMedia:
+		return "EndOfMedia"
+	case LowResVideo:
+		return "LowResVideo"
+	case HighResVideo:
+		return "HighResVideo"
+	case Audio:
+		return "Audio"
+	case Subtitle:
+		return "Subtitle"
+	case Palette:
+		return "Palette"
+	case PaletteReset:
+		return "PaletteReset"
+	case PaletteLookupList:
+		return "PaletteLookupList"
+	case ControlDictionary:
+		return "ControlDictionary"
+	default:
+		return fmt.Sprintf("unknown (0x%02X)", byte(dataType))
+	}
+}
@@ -0,0 +1,7 @@
+package movie
+
+// EntryWriter receives entries one at a time, as they are produced.
+type EntryWriter interface {
+	// WriteEntry stores the given entry.
+	WriteEntry(entry Entry) error
+}
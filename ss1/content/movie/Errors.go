@@ -0,0 +1,67 @@
+package movie
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrMalformed is wrapped by errors for data that does not follow the MOVI format.
+// Use errors.Is() to determine whether a read or decode failed due to broken data.
+var ErrMalformed = errors.New("malformed movie")
+
+// FormatError describes a violation of the MOVI format. It wraps ErrMalformed.
+type FormatError struct {
+	// Detail describes what is wrong with the data.
+	Detail string
+}
+
+// Error returns the textual description of the error.
+func (err *FormatError) Error() string {
+	return fmt.Sprintf("%v: %v", ErrMalformed, err.Detail)
+}
+
+// Unwrap returns ErrMalformed.
+func (err *FormatError) Unwrap() error {
+	return ErrMalformed
+}
+
+// EntryError is an error concerning a specific entry of a container.
+// Use errors.As() to retrieve the index of the entry.
+type EntryError struct {
+	Index int
+	Err   error
+}
+
+// Error returns the textual description of the error, including the entry index.
+func (err *EntryError) Error() string {
+	return fmt.Sprintf("entry %d: %v", err.Index, err.Err)
+}
+
+// Unwrap returns the wrapped error.
+func (err *EntryError) Unwrap() error {
+	return err.Err
+}
+
+// SceneError collects the errors of a scene that was dispatched in best-effort mode.
+// A scene starts with a control dictionary, entries before the first dictionary belong to scene 0.
+type SceneError struct {
+	Scene int
+	// Err is the error of the first entry of the scene that could not be decoded. It is an EntryError.
+	Err error
+	// Failures is the number of entries of the scene that could not be decoded.
+	Failures int
+}
+
+// Error returns the textual description of the error, including the scene index.
+func (err *SceneError) Error() string {
+	return fmt.Sprintf("scene %d: %v (%d failed entries)", err.Scene, err.Err, err.Failures)
+}
+
+// Unwrap returns the error of the first failed entry.
+func (err *SceneError) Unwrap() error {
+	return err.Err
+}
+
+func errFormat(format string, args ...interface{}) error {
+	return &FormatError{Detail: fmt.Sprintf(format, args...)}
+}
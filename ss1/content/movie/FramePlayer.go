@@ -0,0 +1,207 @@
+package movie
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/inkyblackness/hacked/ss1/content/bitmap"
+)
+
+// DefaultKeyframeInterval is the number of frames between two keyframes of a FramePlayer.
+const DefaultKeyframeInterval = 32
+
+// FramePlayer provides frame-accurate access to the video frames of a movie.
+//
+// Video frames are stored as changes to their predecessors, and palettes change in between scenes.
+// To seek to a frame, the frames before it have to be decoded. The player keeps the decoding state
+// every keyframe interval, so that seeking backwards only needs to decode from the closest keyframe.
+// Keyframes are recorded while frames are decoded. Furthermore, as palette entries clear the frame,
+// seeking may skip all scenes before the most recent palette change without decoding them.
+type FramePlayer struct {
+	dispatcher *MediaDispatcher
+	collector  playerFrameCollector
+
+	frameEntries   []int
+	paletteEntries []int
+	interval       int
+	keyframes      map[int]dispatcherState
+
+	current   int
+	timestamp float32
+	palette   bitmap.Palette
+	pixels    []byte
+}
+
+// NewFramePlayer returns a player for the given container, positioned before the first frame.
+// The keyframe interval specifies after how many frames the decoding state is kept.
+// Values less than one use DefaultKeyframeInterval.
+func NewFramePlayer(container Container, keyframeInterval int) *FramePlayer {
+	if keyframeInterval < 1 {
+		keyframeInterval = DefaultKeyframeInterval
+	}
+	player := &FramePlayer{
+		interval:  keyframeInterval,
+		keyframes: make(map[int]dispatcherState),
+		current:   -1,
+		palette:   container.StartPalette(),
+		pixels:    make([]byte, int(container.VideoWidth())*int(container.VideoHeight())),
+	}
+	player.collector.header = bitmap.Header{
+		Type:   bitmap.TypeFlat8Bit,
+		Width:  int16(container.VideoWidth()),
+		Height: int16(container.VideoHeight()),
+		Stride: container.VideoWidth(),
+	}
+	player.dispatcher = NewMediaDispatcher(container, nil, &player.collector)
+	player.keyframes[0] = player.dispatcher.state()
+	for index := 0; index < container.EntryCount(); index++ {
+		switch container.Entry(index).Type() {
+		case LowResVideo, HighResVideo:
+			player.frameEntries = append(player.frameEntries, index)
+		case Palette:
+			player.paletteEntries = append(player.paletteEntries, index)
+		}
+	}
+	return player
+}
+
+// SetBestEffort determines whether frames that can not be decoded are substituted.
+// See MediaDispatcher.SetBestEffort().
+func (player *FramePlayer) SetBestEffort(enabled bool) {
+	player.dispatcher.SetBestEffort(enabled)
+}
+
+// FrameCount returns the number of video frames of the movie.
+func (player *FramePlayer) FrameCount() int {
+	return len(player.frameEntries)
+}
+
+// FrameIndex returns the index of the current frame. It is -1 before the first frame was decoded.
+func (player *FramePlayer) FrameIndex() int {
+	return player.current
+}
+
+// Timestamp returns the time, in seconds, of the current frame.
+func (player *FramePlayer) Timestamp() float32 {
+	return player.timestamp
+}
+
+// Frame returns the current frame, with the palette in effect for it.
+// The returned bitmap is a copy and remains valid after further decoding.
+func (player *FramePlayer) Frame() bitmap.Bitmap {
+	pixels := make([]byte, len(player.pixels))
+	copy(pixels, player.pixels)
+	palette := player.palette
+	return bitmap.Bitmap{
+		Header:  player.collector.header,
+		Palette: &palette,
+		Pixels:  pixels,
+	}
+}
+
+// Next decodes the following frame. Returns false if there are no further frames.
+func (player *FramePlayer) Next() (bool, error) {
+	if (player.current + 1) >= player.FrameCount() {
+		return false, nil
+	}
+	next := player.current + 1
+	if (next % player.interval) == 0 {
+		if _, existing := player.keyframes[next/player.interval]; !existing {
+			player.keyframes[next/player.interval] = player.dispatcher.state()
+		}
+	}
+	player.collector.received = false
+	for !player.collector.received {
+		more, err := player.dispatcher.DispatchNext()
+		if err != nil {
+			return false, err
+		}
+		if !more {
+			return false, nil
+		}
+	}
+	player.current = next
+	player.timestamp = player.collector.timestamp
+	player.palette = *player.collector.frame.Palette
+	copy(player.pixels, player.collector.frame.Pixels)
+	return true, nil
+}
+
+// Seek decodes the frame of given index, which then becomes the current frame.
+// Decoding continues from the closest point before the requested frame: the current frame,
+// the most recent keyframe, or the start of the scene with the most recent palette change.
+func (player *FramePlayer) Seek(frame int) error {
+	frameCount := player.FrameCount()
+	if (frame < 0) || (frame >= frameCount) {
+		return fmt.Errorf("frame %d out of range [0, %d)", frame, frameCount)
+	}
+	if frame == player.current {
+		return nil
+	}
+	continueStart := -1
+	if frame > player.current {
+		continueStart = player.current + 1
+	}
+	keyframe := frame / player.interval
+	for ; keyframe > 0; keyframe-- {
+		if _, existing := player.keyframes[keyframe]; existing {
+			break
+		}
+	}
+	keyframeStart := keyframe * player.interval
+	sceneStart, sceneEntry := player.sceneStartBefore(frame)
+
+	switch {
+	case (continueStart >= keyframeStart) && (continueStart >= sceneStart):
+	case keyframeStart >= sceneStart:
+		player.dispatcher.restore(player.keyframes[keyframe])
+		player.current = keyframeStart - 1
+	default:
+		err := player.dispatcher.skipToScene(sceneEntry)
+		if err != nil {
+			return err
+		}
+		player.current = sceneStart - 1
+	}
+	for player.current < frame {
+		more, err := player.Next()
+		if err != nil {
+			return err
+		}
+		if !more {
+			return fmt.Errorf("frame %d not available", frame)
+		}
+	}
+	return nil
+}
+
+// sceneStartBefore returns the index of the first frame after the most recent palette entry before the given frame,
+// together with the index of that palette entry. The frame index is -1 if there is no such palette entry.
+func (player *FramePlayer) sceneStartBefore(frame int) (int, int) {
+	frameEntry := player.frameEntries[frame]
+	paletteIndex := sort.SearchInts(player.paletteEntries, frameEntry) - 1
+	if paletteIndex < 0 {
+		return -1, 0
+	}
+	paletteEntry := player.paletteEntries[paletteIndex]
+	return sort.SearchInts(player.frameEntries, paletteEntry), paletteEntry
+}
+
+type playerFrameCollector struct {
+	received  bool
+	timestamp float32
+	header    bitmap.Header
+	frame     bitmap.Bitmap
+}
+
+func (collector *playerFrameCollector) OnAudio(timestamp float32, samples []byte) {}
+
+func (collector *playerFrameCollector) OnSubtitle(timestamp float32, control SubtitleControl, text string) {
+}
+
+func (collector *playerFrameCollector) OnVideo(timestamp float32, frame bitmap.Bitmap) {
+	collector.received = true
+	collector.timestamp = timestamp
+	collector.header = frame.Header
+	collector.frame = frame
+}
@@ -0,0 +1,86 @@
+package movie_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/content/bitmap"
+	"github.com/inkyblackness/hacked/ss1/content/movie"
+)
+
+func sequentialFrames(t *testing.T, container movie.Container) []bitmap.Bitmap {
+	player := movie.NewFramePlayer(container, 0)
+	var frames []bitmap.Bitmap
+	for {
+		more, err := player.Next()
+		require.Nil(t, err, "no error expected")
+		if !more {
+			return frames
+		}
+		frames = append(frames, player.Frame())
+	}
+}
+
+func TestFramePlayerCountsFrames(t *testing.T) {
+	player := movie.NewFramePlayer(gifTestContainer(t), 0)
+
+	assert.Equal(t, 4, player.FrameCount())
+	assert.Equal(t, -1, player.FrameIndex())
+}
+
+func TestFramePlayerSeekMatchesSequentialDecoding(t *testing.T) {
+	container := gifTestContainer(t)
+	expected := sequentialFrames(t, container)
+	require.Equal(t, 4, len(expected))
+
+	tt := []struct {
+		interval int
+		order    []int
+	}{
+		{interval: 1, order: []int{3, 0, 2, 1, 3, 3, 0}},
+		{interval: 2, order: []int{1, 3, 0, 2, 1}},
+		{interval: 32, order: []int{2, 1, 3, 0}},
+	}
+	for _, tc := range tt {
+		td := tc
+		player := movie.NewFramePlayer(container, td.interval)
+		for _, frame := range td.order {
+			require.Nil(t, player.Seek(frame), "no error expected seeking to %d", frame)
+			assert.Equal(t, frame, player.FrameIndex())
+			current := player.Frame()
+			assert.Equal(t, expected[frame].Pixels, current.Pixels, "pixels of frame %d, interval %d", frame, td.interval)
+			assert.Equal(t, *expected[frame].Palette, *current.Palette, "palette of frame %d, interval %d", frame, td.interval)
+		}
+	}
+}
+
+func TestFramePlayerSeekAppliesPaletteOfScene(t *testing.T) {
+	player := movie.NewFramePlayer(gifTestContainer(t), 1)
+
+	require.Nil(t, player.Seek(3))
+	assert.Equal(t, gifTestPalette(200), *player.Frame().Palette)
+	assert.Equal(t, float32(2.0), player.Timestamp())
+	require.Nil(t, player.Seek(0))
+	assert.Equal(t, gifTestPalette(10), *player.Frame().Palette)
+	assert.Equal(t, float32(0.0), player.Timestamp())
+}
+
+func TestFramePlayerSeekRejectsFramesOutOfRange(t *testing.T) {
+	player := movie.NewFramePlayer(gifTestContainer(t), 0)
+
+	assert.NotNil(t, player.Seek(-1))
+	assert.NotNil(t, player.Seek(4))
+	assert.Equal(t, -1, player.FrameIndex())
+}
+
+func TestFramePlayerSeekSkipsScenesBeforePaletteChange(t *testing.T) {
+	expected := sequentialFrames(t, gifTestContainer(t))
+	player := movie.NewFramePlayer(corruptedGifTestContainer(t), 0)
+
+	require.Nil(t, player.Seek(3), "corrupted frame should not be decoded")
+	assert.Equal(t, expected[3].Pixels, player.Frame().Pixels)
+	assert.Equal(t, gifTestPalette(200), *player.Frame().Palette)
+	assert.NotNil(t, player.Seek(1), "error expected for corrupted frame")
+}
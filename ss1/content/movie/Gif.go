@@ -0,0 +1,122 @@
+package movie
+
+import (
+	"image"
+	"image/gif"
+	"math"
+
+	"github.com/inkyblackness/hacked/ss1/content/bitmap"
+)
+
+// GifSizeWarningLimit is the estimated size, in bytes, above which an animated GIF
+// is considered too large to be shared comfortably.
+const GifSizeWarningLimit = 8 * 1024 * 1024
+
+// ToGif decodes the video frames of the container and returns them as an animated GIF.
+// Only the frames within the time range [from, to) are considered. If to is not greater
+// than from, all frames from the start time until the end of the movie are considered.
+//
+// The delay of each frame is derived from the timestamp of the following frame. The palette
+// in effect at the first frame is the global palette of the GIF. Frames shown with another palette
+// carry it as their local palette.
+func ToGif(container Container, from, to float32) (*gif.GIF, error) {
+	animation, _, err := toGif(container, from, to, false)
+	return animation, err
+}
+
+// ToGifBestEffort is like ToGif, yet continues past entries that can not be decoded.
+// Frames that fail to decode are substituted with the last good frame, see MediaDispatcher.SetBestEffort().
+// The errors are returned per scene, alongside the partial result. They are empty if the movie could be fully decoded.
+func ToGifBestEffort(container Container, from, to float32) (*gif.GIF, []*SceneError) {
+	animation, sceneErrors, _ := toGif(container, from, to, true)
+	return animation, sceneErrors
+}
+
+func toGif(container Container, from, to float32, bestEffort bool) (*gif.GIF, []*SceneError, error) {
+	if to <= from {
+		to = float32(math.Inf(1))
+	}
+	collector := gifFrameCollector{from: from, to: to}
+	dispatcher := NewMediaDispatcher(container, nil, &collector)
+	dispatcher.SetBestEffort(bestEffort)
+	for more := true; more; {
+		var err error
+		more, err = dispatcher.DispatchNext()
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	end := container.MediaDuration()
+	if end > to {
+		end = to
+	}
+	result := &gif.GIF{
+		Config: image.Config{
+			Width:  int(container.VideoWidth()),
+			Height: int(container.VideoHeight()),
+		},
+	}
+	for index, frame := range collector.frames {
+		nextTimestamp := end
+		if (index + 1) < len(collector.frames) {
+			nextTimestamp = collector.timestamps[index+1]
+		}
+		result.Image = append(result.Image, frame)
+		result.Delay = append(result.Delay, gifDelay(nextTimestamp-collector.timestamps[index]))
+	}
+	if len(result.Image) > 0 {
+		result.Config.ColorModel = result.Image[0].Palette
+	}
+	return result, dispatcher.SceneErrors(), nil
+}
+
+// EstimatedGifSize returns an upper estimate of the size, in bytes, the given animation needs when encoded.
+func EstimatedGifSize(animation *gif.GIF) int {
+	size := 0
+	for _, frame := range animation.Image {
+		size += len(frame.Pix) + len(frame.Palette)*3
+	}
+	return size
+}
+
+// gifDelay returns the given duration in the unit of GIF delays, a hundredth of a second.
+func gifDelay(seconds float32) int {
+	delay := int(math.Round(float64(seconds) * 100))
+	if delay < 1 {
+		delay = 1
+	}
+	return delay
+}
+
+type gifFrameCollector struct {
+	from float32
+	to   float32
+
+	timestamps []float32
+	frames     []*image.Paletted
+}
+
+func (collector *gifFrameCollector) OnAudio(timestamp float32, samples []byte) {}
+
+func (collector *gifFrameCollector) OnSubtitle(timestamp float32, control SubtitleControl, text string) {
+}
+
+func (collector *gifFrameCollector) OnVideo(timestamp float32, frame bitmap.Bitmap) {
+	if (timestamp < collector.from) || (timestamp >= collector.to) {
+		return
+	}
+	collector.timestamps = append(collector.timestamps, timestamp)
+	collector.frames = append(collector.frames, palettedFrame(frame))
+}
+
+// palettedFrame returns a copy of given video frame as paletted image, using the palette of the frame.
+func palettedFrame(frame bitmap.Bitmap) *image.Paletted {
+	width := int(frame.Header.Width)
+	height := int(frame.Header.Height)
+	img := image.NewPaletted(image.Rect(0, 0, width, height), frame.Palette.ColorPalette(false))
+	for y := 0; y < height; y++ {
+		copy(img.Pix[y*img.Stride:y*img.Stride+width], frame.Pixels[y*int(frame.Header.Stride):])
+	}
+	return img
+}
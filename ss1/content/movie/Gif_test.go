@@ -0,0 +1,124 @@
+package movie_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image/gif"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/content/bitmap"
+	"github.com/inkyblackness/hacked/ss1/content/movie"
+)
+
+func gifTestPalette(red byte) bitmap.Palette {
+	var pal bitmap.Palette
+	for index := range pal {
+		pal[index] = bitmap.RGB{Red: red, Green: byte(index), Blue: byte(255 - index)}
+	}
+	return pal
+}
+
+func gifTestContainer(t *testing.T) movie.Container {
+	width, height := 16, 8
+	startPalette := gifTestPalette(10)
+	builder := movie.NewContainerBuilder()
+	builder.VideoWidth(uint16(width)).VideoHeight(uint16(height)).MediaDuration(4.0).StartPalette(&startPalette)
+	encoder := movie.NewStreamEncoder(width, height, builder)
+	encoder.SetSceneLength(1)
+	push := func(timestamp float32, seed int) {
+		require.Nil(t, encoder.Push(timestamp, streamTestFrame(width, height, seed)), "no error expected pushing frame")
+	}
+	push(0.0, 0)
+	push(0.5, 1)
+	push(1.5, 2)
+	require.Nil(t, encoder.Close(), "no error expected closing")
+
+	otherPalette := gifTestPalette(200)
+	buf := bytes.NewBuffer(nil)
+	require.Nil(t, binary.Write(buf, binary.LittleEndian, &otherPalette))
+	builder.AddEntry(movie.NewMemoryEntry(2.0, movie.Palette, buf.Bytes()))
+	encoder = movie.NewStreamEncoder(width, height, builder)
+	require.Nil(t, encoder.Push(2.0, streamTestFrame(width, height, 3)), "no error expected pushing frame")
+	require.Nil(t, encoder.Close(), "no error expected closing")
+	return builder.Build()
+}
+
+func TestToGifDerivesDelaysFromTimestamps(t *testing.T) {
+	animation, err := movie.ToGif(gifTestContainer(t), 0, 0)
+	require.Nil(t, err, "no error expected")
+
+	assert.Equal(t, []int{50, 100, 50, 200}, animation.Delay)
+	assert.Equal(t, streamTestFrame(16, 8, 1), animation.Image[1].Pix)
+}
+
+func TestToGifUsesLocalPaletteForChangedPalette(t *testing.T) {
+	animation, err := movie.ToGif(gifTestContainer(t), 0, 0)
+	require.Nil(t, err, "no error expected")
+	require.Equal(t, 4, len(animation.Image))
+
+	startPalette := gifTestPalette(10)
+	otherPalette := gifTestPalette(200)
+	assert.Equal(t, startPalette.ColorPalette(false), animation.Config.ColorModel)
+	assert.Equal(t, startPalette.ColorPalette(false), animation.Image[2].Palette)
+	assert.Equal(t, otherPalette.ColorPalette(false), animation.Image[3].Palette)
+
+	buf := bytes.NewBuffer(nil)
+	require.Nil(t, gif.EncodeAll(buf, animation), "no error expected encoding")
+	decoded, err := gif.DecodeAll(buf)
+	require.Nil(t, err, "no error expected decoding")
+	assert.Equal(t, otherPalette.ColorPalette(false), decoded.Image[3].Palette)
+}
+
+func TestToGifLimitsFramesToRange(t *testing.T) {
+	animation, err := movie.ToGif(gifTestContainer(t), 0.5, 2.0)
+	require.Nil(t, err, "no error expected")
+
+	assert.Equal(t, []int{100, 50}, animation.Delay)
+	assert.True(t, movie.EstimatedGifSize(animation) >= 2*16*8, "estimate should include all pixels")
+}
+
+func corruptedGifTestContainer(t *testing.T) movie.Container {
+	source := gifTestContainer(t)
+	builder := movie.NewContainerBuilderWithHeaderOf(source)
+	videoEntries := 0
+	for index := 0; index < source.EntryCount(); index++ {
+		entry := source.Entry(index)
+		if entry.Type() == movie.HighResVideo {
+			videoEntries++
+			if videoEntries == 2 {
+				entry = movie.NewMemoryEntry(entry.Timestamp(), entry.Type(), entry.Data()[:2])
+			}
+		}
+		builder.AddEntry(entry)
+	}
+	return builder.Build()
+}
+
+func TestToGifFailsForCorruptedScene(t *testing.T) {
+	_, err := movie.ToGif(corruptedGifTestContainer(t), 0, 0)
+
+	assert.True(t, errors.Is(err, movie.ErrMalformed), "malformed error expected")
+}
+
+func TestToGifBestEffortSubstitutesCorruptedFrames(t *testing.T) {
+	animation, sceneErrors := movie.ToGifBestEffort(corruptedGifTestContainer(t), 0, 0)
+
+	require.Equal(t, 4, len(animation.Image), "all frames expected")
+	assert.Equal(t, streamTestFrame(16, 8, 0), animation.Image[1].Pix, "last good frame expected")
+	assert.Equal(t, streamTestFrame(16, 8, 2), animation.Image[2].Pix, "following scene should be decoded")
+	require.Equal(t, 1, len(sceneErrors), "one scene error expected")
+	assert.Equal(t, 1, sceneErrors[0].Scene)
+	var entryErr *movie.EntryError
+	assert.True(t, errors.As(sceneErrors[0], &entryErr), "entry error expected")
+}
+
+func TestToGifBestEffortWithoutErrors(t *testing.T) {
+	animation, sceneErrors := movie.ToGifBestEffort(gifTestContainer(t), 0, 0)
+
+	assert.Equal(t, 4, len(animation.Image))
+	assert.Equal(t, 0, len(sceneErrors))
+}
@@ -24,17 +24,25 @@ type MediaDispatcher struct {
 	palette        bitmap.Palette
 	decoderBuilder *compression.FrameDecoderBuilder
 	frameBuffer    []byte
+
+	bestEffort    bool
+	dictionaries  int
+	lastGoodFrame []byte
+	sceneErrors   []*SceneError
 }
 
 // NewMediaDispatcher returns a new instance of a dispatcher reading the provided container.
-func NewMediaDispatcher(container Container, handler MediaHandler) *MediaDispatcher {
+// The codepage is used to decode subtitles. Handlers that ignore subtitles may pass nil,
+// in which case subtitles are dispatched without text.
+func NewMediaDispatcher(container Container, cp text.Codepage, handler MediaHandler) *MediaDispatcher {
 	width := int(container.VideoWidth())
 	height := int(container.VideoHeight())
 	dispatcher := &MediaDispatcher{
 		handler:        handler,
 		container:      container,
-		codepage:       text.DefaultCodepage(),
+		codepage:       cp,
 		frameBuffer:    make([]byte, width*height),
+		lastGoodFrame:  make([]byte, width*height),
 		decoderBuilder: compression.NewFrameDecoderBuilder(width, height)}
 
 	startPalette := container.StartPalette()
@@ -44,18 +52,59 @@ func NewMediaDispatcher(container Container, handler MediaHandler) *MediaDispatc
 	return dispatcher
 }
 
+// SetBestEffort determines whether the dispatcher continues past entries that can not be decoded.
+// In best-effort mode, a video frame that can not be decoded is substituted with the last good frame,
+// or a blank frame if there was none yet. The errors are collected per scene, see SceneErrors().
+func (dispatcher *MediaDispatcher) SetBestEffort(enabled bool) {
+	dispatcher.bestEffort = enabled
+}
+
+// SceneErrors returns the errors that were collected in best-effort mode, in order of the scenes.
+func (dispatcher *MediaDispatcher) SceneErrors() []*SceneError {
+	return dispatcher.sceneErrors
+}
+
 // DispatchNext processes the next entries from the container to call the handler.
 // Returns false if the dispatcher reached the end of the container.
+// An entry that can not be decoded results in an EntryError, unless the dispatcher is in best-effort mode.
 func (dispatcher *MediaDispatcher) DispatchNext() (result bool, err error) {
-	for !result && (dispatcher.nextIndex < dispatcher.container.EntryCount()) {
+	for !result && (err == nil) && (dispatcher.nextIndex < dispatcher.container.EntryCount()) {
 		entry := dispatcher.container.Entry(dispatcher.nextIndex)
+		if entry.Type() == ControlDictionary {
+			dispatcher.dictionaries++
+		}
 		result, err = dispatcher.process(entry)
+		if err != nil {
+			err = &EntryError{Index: dispatcher.nextIndex, Err: err}
+		}
+		if (err != nil) && dispatcher.bestEffort {
+			dispatcher.recordSceneError(err)
+			err = nil
+			if (entry.Type() == LowResVideo) || (entry.Type() == HighResVideo) {
+				copy(dispatcher.frameBuffer, dispatcher.lastGoodFrame)
+				dispatcher.notifyVideoFrame(entry.Timestamp())
+				result = true
+			}
+		}
 		dispatcher.nextIndex++
 	}
 
 	return
 }
 
+func (dispatcher *MediaDispatcher) recordSceneError(err error) {
+	scene := 0
+	if dispatcher.dictionaries > 0 {
+		scene = dispatcher.dictionaries - 1
+	}
+	errorCount := len(dispatcher.sceneErrors)
+	if (errorCount > 0) && (dispatcher.sceneErrors[errorCount-1].Scene == scene) {
+		dispatcher.sceneErrors[errorCount-1].Failures++
+		return
+	}
+	dispatcher.sceneErrors = append(dispatcher.sceneErrors, &SceneError{Scene: scene, Err: err, Failures: 1})
+}
+
 func (dispatcher *MediaDispatcher) process(entry Entry) (dispatched bool, err error) {
 	switch entry.Type() {
 	case Audio:
@@ -69,9 +118,12 @@ func (dispatcher *MediaDispatcher) process(entry Entry) (dispatched bool, err er
 
 			err = binary.Read(bytes.NewReader(entry.Data()), binary.LittleEndian, &subtitleHeader)
 			if err != nil {
-				return
+				return false, errFormat("subtitle header truncated")
+			}
+			var subtitle string
+			if dispatcher.codepage != nil {
+				subtitle = dispatcher.codepage.Decode(entry.Data()[SubtitleHeaderSize:])
 			}
-			subtitle := dispatcher.codepage.Decode(entry.Data()[SubtitleHeaderSize:])
 			dispatcher.handler.OnSubtitle(entry.Timestamp(), subtitleHeader.Control, subtitle)
 			dispatched = true
 		}
@@ -84,8 +136,9 @@ func (dispatcher *MediaDispatcher) process(entry Entry) (dispatched bool, err er
 			if decoder.FirstError() == nil {
 				dispatcher.setPalette(&pal)
 				dispatcher.clearFrameBuffer()
+				copy(dispatcher.lastGoodFrame, dispatcher.frameBuffer)
 			} else {
-				err = decoder.FirstError()
+				err = errFormat("palette truncated")
 			}
 		}
 	case ControlDictionary:
@@ -95,7 +148,7 @@ func (dispatcher *MediaDispatcher) process(entry Entry) (dispatched bool, err er
 			if wordsErr == nil {
 				dispatcher.decoderBuilder.WithControlWords(words)
 			} else {
-				err = wordsErr
+				err = errFormat("invalid control dictionary: %v", wordsErr)
 			}
 		}
 	case PaletteLookupList:
@@ -110,14 +163,15 @@ func (dispatcher *MediaDispatcher) process(entry Entry) (dispatched bool, err er
 
 			err = binary.Read(reader, binary.LittleEndian, &videoHeader)
 			if err != nil {
-				return
+				return false, errFormat("video header truncated")
 			}
 			frameErr := rle.Decompress(reader, dispatcher.frameBuffer)
 			if frameErr == nil {
+				copy(dispatcher.lastGoodFrame, dispatcher.frameBuffer)
 				dispatcher.notifyVideoFrame(entry.Timestamp())
 				dispatched = true
 			} else {
-				err = frameErr
+				err = errFormat("invalid frame data: %v", frameErr)
 			}
 		}
 	case HighResVideo:
@@ -127,16 +181,21 @@ func (dispatcher *MediaDispatcher) process(entry Entry) (dispatched bool, err er
 
 			err = binary.Read(reader, binary.LittleEndian, &videoHeader)
 			if err != nil {
-				return
+				return false, errFormat("video header truncated")
+			}
+			data := entry.Data()
+			if (int(videoHeader.PixelDataOffset) < HighResVideoHeaderSize) || (int(videoHeader.PixelDataOffset) > len(data)) {
+				return false, errFormat("pixel data offset %d out of bounds", videoHeader.PixelDataOffset)
 			}
-			bitstreamData := entry.Data()[HighResVideoHeaderSize:videoHeader.PixelDataOffset]
-			maskstreamData := entry.Data()[videoHeader.PixelDataOffset:]
+			bitstreamData := data[HighResVideoHeaderSize:videoHeader.PixelDataOffset]
+			maskstreamData := data[videoHeader.PixelDataOffset:]
 			decoder := dispatcher.decoderBuilder.Build()
 
 			err = decoder.Decode(bitstreamData, maskstreamData)
 			if err != nil {
-				return
+				return false, errFormat("invalid frame data: %v", err)
 			}
+			copy(dispatcher.lastGoodFrame, dispatcher.frameBuffer)
 			dispatcher.notifyVideoFrame(entry.Timestamp())
 			dispatched = true
 		}
@@ -168,3 +227,73 @@ func (dispatcher *MediaDispatcher) notifyVideoFrame(timestamp float32) {
 	}
 	dispatcher.handler.OnVideo(timestamp, bmp)
 }
+
+// dispatcherState is a snapshot of the decoding state of a dispatcher.
+// It allows to continue dispatching from an earlier position, without processing all previous entries again.
+type dispatcherState struct {
+	nextIndex      int
+	palette        bitmap.Palette
+	frameBuffer    []byte
+	lastGoodFrame  []byte
+	decoderBuilder compression.FrameDecoderBuilder
+	dictionaries   int
+	sceneErrors    int
+}
+
+func (dispatcher *MediaDispatcher) state() dispatcherState {
+	state := dispatcherState{
+		nextIndex:      dispatcher.nextIndex,
+		palette:        dispatcher.palette,
+		frameBuffer:    make([]byte, len(dispatcher.frameBuffer)),
+		lastGoodFrame:  make([]byte, len(dispatcher.lastGoodFrame)),
+		decoderBuilder: *dispatcher.decoderBuilder,
+		dictionaries:   dispatcher.dictionaries,
+		sceneErrors:    len(dispatcher.sceneErrors),
+	}
+	copy(state.frameBuffer, dispatcher.frameBuffer)
+	copy(state.lastGoodFrame, dispatcher.lastGoodFrame)
+	return state
+}
+
+// restore continues from the given state. Scene errors that were recorded after the state are dropped,
+// as they are recorded again when the entries are dispatched again.
+func (dispatcher *MediaDispatcher) restore(state dispatcherState) {
+	dispatcher.nextIndex = state.nextIndex
+	dispatcher.palette = state.palette
+	copy(dispatcher.frameBuffer, state.frameBuffer)
+	copy(dispatcher.lastGoodFrame, state.lastGoodFrame)
+	*dispatcher.decoderBuilder = state.decoderBuilder
+	dispatcher.dictionaries = state.dictionaries
+	dispatcher.sceneErrors = dispatcher.sceneErrors[:state.sceneErrors]
+}
+
+// skipToScene continues dispatching at the given entry index, which is expected to be a palette entry.
+// As palette entries clear the frame, the following frames do not depend on any frame before.
+// The most recent control dictionary and palette lookup list before the index are processed, in case
+// the following scene does not provide its own. No other entries before the index are processed.
+func (dispatcher *MediaDispatcher) skipToScene(index int) error {
+	var dictionary, lookupList Entry
+	dictionaryIndex := 0
+	dispatcher.dictionaries = 0
+	for entryIndex := 0; entryIndex < index; entryIndex++ {
+		entry := dispatcher.container.Entry(entryIndex)
+		switch entry.Type() {
+		case ControlDictionary:
+			dispatcher.dictionaries++
+			dictionary = entry
+			dictionaryIndex = entryIndex
+		case PaletteLookupList:
+			lookupList = entry
+		}
+	}
+	if dictionary != nil {
+		if _, err := dispatcher.process(dictionary); (err != nil) && !dispatcher.bestEffort {
+			return &EntryError{Index: dictionaryIndex, Err: err}
+		}
+	}
+	if lookupList != nil {
+		_, _ = dispatcher.process(lookupList)
+	}
+	dispatcher.nextIndex = index
+	return nil
+}
@@ -0,0 +1,50 @@
+package movie
+
+import (
+	"fmt"
+
+	"github.com/inkyblackness/hacked/ss1/content/movie/internal/compression"
+)
+
+// PaletteLookupMode selects how the palette lookup of a scene is created while encoding.
+// The palette lookup contains the colors of all tiles of a scene. All modes result in movies
+// that are decoded the same way, they only differ in encoding time and size.
+type PaletteLookupMode int
+
+const (
+	// PaletteLookupCompact searches for palettes that can be shared among tiles, and for overlaps of palettes.
+	// This results in the smallest movies, at the cost of encoding time, which grows considerably
+	// with the number of distinct tiles. This is the default mode.
+	PaletteLookupCompact = PaletteLookupMode(compression.PaletteLookupCompact)
+	// PaletteLookupDirect stores the palette of each distinct tile as is. This is the fastest mode,
+	// yet results in the largest movies. As the size of a lookup is limited, scenes with many distinct
+	// tiles may not be encodable in this mode. Shorter scenes help in this case.
+	PaletteLookupDirect = PaletteLookupMode(compression.PaletteLookupDirect)
+	// PaletteLookupGreedy reuses sections of larger palettes for smaller ones. It is considerably faster
+	// than PaletteLookupCompact, with movies being larger, yet smaller than those of PaletteLookupDirect.
+	PaletteLookupGreedy = PaletteLookupMode(compression.PaletteLookupGreedy)
+	// PaletteLookupAutomatic uses PaletteLookupGreedy for scenes with few distinct palettes,
+	// and PaletteLookupCompact otherwise.
+	PaletteLookupAutomatic = PaletteLookupMode(compression.PaletteLookupAutomatic)
+)
+
+// PaletteLookupModes returns all available modes.
+func PaletteLookupModes() []PaletteLookupMode {
+	return []PaletteLookupMode{PaletteLookupCompact, PaletteLookupDirect, PaletteLookupGreedy, PaletteLookupAutomatic}
+}
+
+// String returns the name of the mode.
+func (mode PaletteLookupMode) String() string {
+	switch mode {
+	case PaletteLookupCompact:
+		return "Compact"
+	case PaletteLookupDirect:
+		return "Direct"
+	case PaletteLookupGreedy:
+		return "Greedy"
+	case PaletteLookupAutomatic:
+		return "Automatic"
+	default:
+		return fmt.Sprintf("Unknown%d", int(mode))
+	}
+}
@@ -3,7 +3,6 @@ package movie
 import (
 	"bytes"
 	"encoding/binary"
-	"errors"
 	"fmt"
 	"io"
 
@@ -12,20 +11,36 @@ import (
 	"github.com/inkyblackness/hacked/ss1/serial"
 )
 
+// maxVideoDimension limits the width and height of videos to protect against excessive frame buffers.
+const maxVideoDimension = 2048
+
 // Read tries to extract a MOVI container from the provided reader.
 // On success the position of the reader is past the last data entry.
 // On failure the position of the reader is undefined.
+//
+// Data that does not follow the format results in an error wrapping ErrMalformed.
 func Read(source io.ReadSeeker) (container Container, err error) {
 	if source == nil {
 		return nil, fmt.Errorf("source is nil")
 	}
 
 	var header format.Header
-	startPos, _ := source.Seek(0, io.SeekCurrent)
+	startPos, err := source.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return
+	}
+	endPos, err := source.Seek(0, io.SeekEnd)
+	if err != nil {
+		return
+	}
+	_, err = source.Seek(startPos, io.SeekStart)
+	if err != nil {
+		return
+	}
 
 	err = binary.Read(source, binary.LittleEndian, &header)
 	if err != nil {
-		return
+		return nil, errFormat("header truncated: %v", err)
 	}
 	builder := NewContainerBuilder()
 	err = verifyAndExtractHeader(builder, &header)
@@ -36,7 +51,7 @@ func Read(source io.ReadSeeker) (container Container, err error) {
 	if err != nil {
 		return
 	}
-	err = readIndexAndEntries(source, startPos, builder, &header)
+	err = readIndexAndEntries(source, startPos, endPos, builder, &header)
 	if err != nil {
 		return
 	}
@@ -46,7 +61,10 @@ func Read(source io.ReadSeeker) (container Container, err error) {
 
 func verifyAndExtractHeader(builder *ContainerBuilder, header *format.Header) error {
 	if !bytes.Equal(header.Tag[:], bytes.NewBufferString(format.Tag).Bytes()) {
-		return errors.New("not a MOVI format")
+		return errFormat("not a MOVI format")
+	}
+	if (header.VideoWidth > maxVideoDimension) || (header.VideoHeight > maxVideoDimension) {
+		return errFormat("video size %dx%d exceeds limit", header.VideoWidth, header.VideoHeight)
 	}
 
 	builder.MediaDuration(timeFromRaw(header.DurationSeconds, header.DurationFraction))
@@ -62,34 +80,49 @@ func readPalette(source io.Reader, builder *ContainerBuilder) error {
 	decoder.Code(&pal)
 
 	if decoder.FirstError() != nil {
-		return decoder.FirstError()
+		return errFormat("palette truncated: %v", decoder.FirstError())
 	}
 
 	builder.StartPalette(&pal)
 	return nil
 }
 
-func readIndexAndEntries(source io.ReadSeeker, startPos int64, builder *ContainerBuilder, header *format.Header) error {
-	indexEntries := make([]format.IndexTableEntry, header.IndexEntryCount)
-	err := binary.Read(source, binary.LittleEndian, indexEntries)
+func readIndexAndEntries(source io.ReadSeeker, startPos, endPos int64, builder *ContainerBuilder, header *format.Header) error {
+	indexPos, err := source.Seek(0, io.SeekCurrent)
 	if err != nil {
 		return err
 	}
+	if (header.IndexEntryCount < 0) ||
+		(int64(header.IndexEntryCount)*format.IndexTableEntrySize > endPos-indexPos) {
+		return errFormat("invalid index entry count %d", header.IndexEntryCount)
+	}
+	indexEntries := make([]format.IndexTableEntry, header.IndexEntryCount)
+	err = binary.Read(source, binary.LittleEndian, indexEntries)
+	if err != nil {
+		return errFormat("index truncated: %v", err)
+	}
 	for index, indexEntry := range indexEntries {
 		entryType := DataType(indexEntry.Type)
 
 		if entryType != endOfMedia {
+			if index+1 >= len(indexEntries) {
+				return &EntryError{Index: index, Err: errFormat("missing end of data")}
+			}
 			timestamp := timeFromRaw(indexEntry.TimestampSecond, indexEntry.TimestampFraction)
-			length := int(indexEntries[index+1].DataOffset - indexEntry.DataOffset)
-			data := make([]byte, length)
+			dataStart := int64(indexEntry.DataOffset)
+			dataEnd := int64(indexEntries[index+1].DataOffset)
+			if (dataStart < 0) || (dataEnd < dataStart) || (startPos+dataEnd > endPos) {
+				return &EntryError{Index: index, Err: errFormat("data range [%d, %d) out of bounds", dataStart, dataEnd)}
+			}
+			data := make([]byte, dataEnd-dataStart)
 
-			_, err = source.Seek(startPos+int64(indexEntry.DataOffset), 0)
+			_, err = source.Seek(startPos+dataStart, io.SeekStart)
 			if err != nil {
 				return err
 			}
-			_, err = source.Read(data)
+			_, err = io.ReadFull(source, data)
 			if err != nil {
-				return err
+				return &EntryError{Index: index, Err: errFormat("data truncated: %v", err)}
 			}
 
 			builder.AddEntry(NewMemoryEntry(timestamp, entryType, data))
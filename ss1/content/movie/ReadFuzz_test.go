@@ -0,0 +1,83 @@
+package movie_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/content/bitmap"
+	"github.com/inkyblackness/hacked/ss1/content/movie"
+)
+
+type nullMediaHandler struct{}
+
+func (nullMediaHandler) OnAudio(float32, []byte)                           {}
+func (nullMediaHandler) OnSubtitle(float32, movie.SubtitleControl, string) {}
+func (nullMediaHandler) OnVideo(float32, bitmap.Bitmap)                    {}
+
+func fuzzSeedContainer(t testing.TB) []byte {
+	builder := movie.NewContainerBuilder()
+	builder.VideoWidth(16).VideoHeight(8).AudioSampleRate(22050)
+	builder.AddEntry(movie.NewMemoryEntry(0.0, movie.Audio, []byte{0x80, 0x81}))
+	builder.AddEntry(movie.NewMemoryEntry(0.0, movie.ControlDictionary, []byte{0x03, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00}))
+	builder.AddEntry(movie.NewMemoryEntry(0.5, movie.LowResVideo, make([]byte, 12)))
+	builder.AddEntry(movie.NewMemoryEntry(1.0, movie.HighResVideo, []byte{0x04, 0x00, 0x00, 0x00}))
+	builder.AddEntry(movie.NewMemoryEntry(1.0, movie.Subtitle, make([]byte, 0x10)))
+	buffer := bytes.NewBuffer(nil)
+	err := movie.Write(buffer, builder.Build())
+	if err != nil {
+		t.Fatalf("seed container could not be written: %v", err)
+	}
+	return buffer.Bytes()
+}
+
+// overrunFrameContainer has a low resolution frame that skips past the frame buffer and then writes into it.
+func overrunFrameContainer(t testing.TB) []byte {
+	builder := movie.NewContainerBuilder()
+	builder.VideoWidth(16).VideoHeight(8).AudioSampleRate(22050)
+	frame := make([]byte, movie.LowResVideoHeaderSize)
+	frame = append(frame, 0x80, 0x00, 0x40, 0x04, 0x01, 0x02, 0x03, 0x04, 0x80, 0x00, 0x00)
+	builder.AddEntry(movie.NewMemoryEntry(0.0, movie.LowResVideo, frame))
+	buffer := bytes.NewBuffer(nil)
+	err := movie.Write(buffer, builder.Build())
+	if err != nil {
+		t.Fatalf("overrun container could not be written: %v", err)
+	}
+	return buffer.Bytes()
+}
+
+func TestMediaDispatcherReportsFrameOverrunAsMalformed(t *testing.T) {
+	container, err := movie.Read(bytes.NewReader(overrunFrameContainer(t)))
+	require.Nil(t, err, "no error expected reading container")
+
+	_, err = movie.NewMediaDispatcher(container, nil, nullMediaHandler{}).DispatchNext()
+	assert.True(t, errors.Is(err, movie.ErrMalformed), "malformed error expected, got %v", err)
+
+	dispatcher := movie.NewMediaDispatcher(container, nil, nullMediaHandler{})
+	dispatcher.SetBestEffort(true)
+	_, err = dispatcher.DispatchNext()
+	assert.Nil(t, err, "no error expected in best-effort mode")
+	assert.Equal(t, 1, len(dispatcher.SceneErrors()), "scene error expected")
+}
+
+func FuzzRead(f *testing.F) {
+	f.Add(fuzzSeedContainer(f))
+	f.Add(overrunFrameContainer(f))
+	f.Add([]byte("MOVI"))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		container, err := movie.Read(bytes.NewReader(data))
+		if err != nil {
+			return
+		}
+		dispatcher := movie.NewMediaDispatcher(container, nil, nullMediaHandler{})
+		for more := true; more; {
+			more, err = dispatcher.DispatchNext()
+			if err != nil {
+				return
+			}
+		}
+	})
+}
@@ -2,6 +2,7 @@ package movie_test
 
 import (
 	"bytes"
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -87,3 +88,40 @@ func TestReadReturnsContainerWithDataEntriesExceptTerminator(t *testing.T) {
 	assert.Equal(t, movie.Audio, container.Entry(0).Type())
 	assert.Equal(t, testData, container.Entry(0).Data())
 }
+
+func TestReadReturnsMalformedErrorOnBrokenData(t *testing.T) {
+	validFile := func() []byte {
+		buffer := bytes.NewBufferString(format.Tag)
+		buffer.Write(make([]byte, 0x100+0x300-len(format.Tag)))
+		buffer.Write(make([]byte, 0xC00))
+		buffer.Write([]byte{0x01, 0x02, 0x03, 0x04, 0x05})
+		raw := buffer.Bytes()
+		raw[0x04] = 2
+		raw[0x0400+3] = 0x02
+		raw[0x0400+5] = 0x10
+		raw[0x0408+4] = 0x05
+		raw[0x0408+5] = 0x10
+		return raw
+	}
+	tt := []struct {
+		name   string
+		modify func([]byte) []byte
+	}{
+		{name: "truncated header", modify: func(raw []byte) []byte { return raw[:0x80] }},
+		{name: "truncated palette", modify: func(raw []byte) []byte { return raw[:0x200] }},
+		{name: "truncated data", modify: func(raw []byte) []byte { return raw[:len(raw)-2] }},
+		{name: "negative entry count", modify: func(raw []byte) []byte { raw[0x07] = 0x80; return raw }},
+		{name: "excessive entry count", modify: func(raw []byte) []byte { raw[0x06] = 0x10; return raw }},
+		{name: "missing end entry", modify: func(raw []byte) []byte { raw[0x0408+3] = 0x02; return raw }},
+		{name: "reversed offsets", modify: func(raw []byte) []byte { raw[0x0408+5] = 0x0F; return raw }},
+		{name: "excessive video size", modify: func(raw []byte) []byte { raw[0x19] = 0x40; return raw }},
+	}
+
+	for _, tc := range tt {
+		td := tc
+		t.Run(td.name, func(t *testing.T) {
+			_, err := movie.Read(bytes.NewReader(td.modify(validFile())))
+			assert.True(t, errors.Is(err, movie.ErrMalformed), "malformed error expected, got %v", err)
+		})
+	}
+}
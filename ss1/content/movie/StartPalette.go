@@ -0,0 +1,16 @@
+package movie
+
+import (
+	"github.com/inkyblackness/hacked/ss1/content/bitmap"
+)
+
+// WithStartPalette returns a new container that has the initial palette replaced with the given one.
+// All entries are kept as they are. As the frames refer to colors by their index, they are shown in
+// the colors of the new palette - until a palette entry within the movie changes the palette.
+func WithStartPalette(container Container, palette *bitmap.Palette) Container {
+	builder := NewContainerBuilderWithHeaderOf(container).StartPalette(palette)
+	for index := 0; index < container.EntryCount(); index++ {
+		builder.AddEntry(container.Entry(index))
+	}
+	return builder.Build()
+}
@@ -0,0 +1,39 @@
+package movie_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/content/bitmap"
+	"github.com/inkyblackness/hacked/ss1/content/movie"
+)
+
+func TestWithStartPaletteKeepsEntries(t *testing.T) {
+	container := subtitledContainer()
+	var pal bitmap.Palette
+	pal[1] = bitmap.RGB{Red: 10, Green: 20, Blue: 30}
+
+	result := movie.WithStartPalette(container, &pal)
+
+	assert.Equal(t, pal, result.StartPalette())
+	assert.Equal(t, bitmap.Palette{}, container.StartPalette(), "original should be unchanged")
+	require.Equal(t, container.EntryCount(), result.EntryCount())
+	for index := 0; index < container.EntryCount(); index++ {
+		assert.Equal(t, container.Entry(index).Data(), result.Entry(index).Data())
+	}
+}
+
+func TestWithStartPaletteIsKeptWhenWritten(t *testing.T) {
+	var pal bitmap.Palette
+	pal[255] = bitmap.RGB{Red: 1, Green: 2, Blue: 3}
+	buf := bytes.NewBuffer(nil)
+	err := movie.Write(buf, movie.WithStartPalette(subtitledContainer(), &pal))
+	require.Nil(t, err, "no error expected writing")
+
+	result, err := movie.Read(bytes.NewReader(buf.Bytes()))
+	require.Nil(t, err, "no error expected reading")
+	assert.Equal(t, pal, result.StartPalette())
+}
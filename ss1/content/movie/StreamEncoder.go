@@ -0,0 +1,147 @@
+package movie
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/inkyblackness/hacked/ss1/content/movie/internal/compression"
+)
+
+// DefaultSceneLength is the number of frames a StreamEncoder collects for one scene.
+const DefaultSceneLength = 16
+
+// StreamEncoder encodes a sequence of frames into high-resolution video entries, one frame at a time.
+//
+// Frames are collected in scenes of limited length. Each scene is written with its own control dictionary and
+// palette lookup list, followed by the video entries of its frames. Apart from the current scene, only the
+// previous frame is kept in memory, as reference for the changes of the next.
+// Together with a StreamWriter, the entries of completed scenes are written directly to a file.
+type StreamEncoder struct {
+	out         EntryWriter
+	scene       *compression.SceneEncoder
+	sceneLength int
+	frameSize   int
+	timestamps  []float32
+	closed      bool
+}
+
+// StreamEntryCount returns the number of entries a StreamEncoder writes for given number of frames and scene length.
+// This is the number of entries a StreamWriter has to be prepared for.
+func StreamEntryCount(frameCount, sceneLength int) int {
+	if sceneLength < 1 {
+		sceneLength = DefaultSceneLength
+	}
+	sceneCount := (frameCount + sceneLength - 1) / sceneLength
+	return frameCount + sceneCount*2
+}
+
+// NewStreamEncoder returns a new encoder for frames of given dimension, writing the resulting entries to out.
+// Width and height are expected to be multiples of four.
+func NewStreamEncoder(width, height int, out EntryWriter) *StreamEncoder {
+	return &StreamEncoder{
+		out:         out,
+		scene:       compression.NewSceneEncoder(width, height),
+		sceneLength: DefaultSceneLength,
+		frameSize:   width * height,
+	}
+}
+
+// SetSceneLength determines how many frames are collected before a scene is written.
+// Shorter scenes need less memory, longer scenes allow a better compression. Values below 1 are ignored.
+func (e *StreamEncoder) SetSceneLength(frames int) {
+	if frames > 0 {
+		e.sceneLength = frames
+	}
+}
+
+// SetPaletteLookupMode determines how the palette lookups of the scenes are created.
+// The default is PaletteLookupCompact. See the modes for the tradeoff between encoding time and size.
+func (e *StreamEncoder) SetPaletteLookupMode(mode PaletteLookupMode) {
+	e.scene.SetPaletteLookupMode(compression.PaletteLookupMode(mode))
+}
+
+// SetSeed sets the basis for choices between otherwise equivalent encodings. The default is zero.
+// Encoding the same frames with the same seed always produces the same entries, which allows reproducible builds.
+// Only PaletteLookupCompact has such choices, which PaletteLookupAutomatic also uses for scenes with many palettes.
+// The other modes produce the same entries regardless of the seed.
+func (e *StreamEncoder) SetSeed(seed uint64) {
+	e.scene.SetSeed(seed)
+}
+
+// Push adds the next frame, to be displayed at given timestamp.
+// The frame is a bitmap of palette indices, with a stride equal to the width.
+// Color index zero is transparent: such pixel keep the color of the previous frame.
+// If this frame completes a scene, the scene is written.
+func (e *StreamEncoder) Push(timestamp float32, frame []byte) error {
+	if e.closed {
+		return errors.New("encoder is closed")
+	}
+	if len(frame) != e.frameSize {
+		return fmt.Errorf("invalid frame size: %v, expected %v", len(frame), e.frameSize)
+	}
+	err := e.scene.AddFrame(frame)
+	if err != nil {
+		return err
+	}
+	e.timestamps = append(e.timestamps, timestamp)
+	if e.scene.FrameCount() >= e.sceneLength {
+		return e.flush()
+	}
+	return nil
+}
+
+// Close writes any pending frames. Further pushed frames are rejected.
+func (e *StreamEncoder) Close() error {
+	if e.closed {
+		return nil
+	}
+	e.closed = true
+	return e.flush()
+}
+
+func (e *StreamEncoder) flush() error {
+	if e.scene.FrameCount() == 0 {
+		return nil
+	}
+	words, paletteLookup, frames, err := e.scene.Encode()
+	if err != nil {
+		return err
+	}
+	sceneStart := e.timestamps[0]
+	err = e.out.WriteEntry(NewMemoryEntry(sceneStart, ControlDictionary, compression.PackControlWords(words)))
+	if err != nil {
+		return err
+	}
+	err = e.out.WriteEntry(NewMemoryEntry(sceneStart, PaletteLookupList, paletteLookup))
+	if err != nil {
+		return err
+	}
+	for index, frame := range frames {
+		data, frameErr := highResVideoData(frame)
+		if frameErr != nil {
+			return frameErr
+		}
+		err = e.out.WriteEntry(NewMemoryEntry(e.timestamps[index], HighResVideo, data))
+		if err != nil {
+			return err
+		}
+	}
+	e.scene.ClearFrames()
+	e.timestamps = e.timestamps[:0]
+	return nil
+}
+
+func highResVideoData(frame compression.EncodedFrame) ([]byte, error) {
+	pixelDataOffset := HighResVideoHeaderSize + len(frame.Bitstream)
+	if pixelDataOffset > 0xFFFF {
+		return nil, fmt.Errorf("bitstream of frame is too big: %vB", len(frame.Bitstream))
+	}
+	buf := bytes.NewBuffer(nil)
+	header := HighResVideoHeader{PixelDataOffset: uint16(pixelDataOffset)}
+	_ = binary.Write(buf, binary.LittleEndian, &header)
+	buf.Write(frame.Bitstream)
+	buf.Write(frame.Maskstream)
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,121 @@
+package movie_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/content/bitmap"
+	"github.com/inkyblackness/hacked/ss1/content/movie"
+	"github.com/inkyblackness/hacked/ss1/content/text"
+)
+
+type capturingMediaHandler struct {
+	nullMediaHandler
+	timestamps []float32
+	frames     [][]byte
+}
+
+func (handler *capturingMediaHandler) OnVideo(timestamp float32, frame bitmap.Bitmap) {
+	handler.timestamps = append(handler.timestamps, timestamp)
+	handler.frames = append(handler.frames, append([]byte{}, frame.Pixels...))
+}
+
+func streamTestFrame(width, height, seed int) []byte {
+	frame := make([]byte, width*height)
+	for index := range frame {
+		frame[index] = 1 + byte(((index/4)+seed*(index%3))%7)
+	}
+	return frame
+}
+
+func TestStreamEncoderProducesDecodableScenes(t *testing.T) {
+	width, height := 16, 8
+	builder := movie.NewContainerBuilder()
+	builder.VideoWidth(uint16(width)).VideoHeight(uint16(height))
+	encoder := movie.NewStreamEncoder(width, height, builder)
+	encoder.SetSceneLength(2)
+
+	var frames [][]byte
+	for index := 0; index < 5; index++ {
+		frame := streamTestFrame(width, height, index)
+		frames = append(frames, frame)
+		err := encoder.Push(float32(index), frame)
+		require.Nil(t, err, "no error expected pushing frame %d", index)
+	}
+	require.Nil(t, encoder.Close(), "no error expected closing")
+
+	container := builder.Build()
+	handler := &capturingMediaHandler{}
+	dispatcher := movie.NewMediaDispatcher(container, text.DefaultCodepage(), handler)
+	for more := true; more; {
+		var err error
+		more, err = dispatcher.DispatchNext()
+		require.Nil(t, err, "no error expected decoding")
+	}
+
+	assert.Equal(t, []float32{0, 1, 2, 3, 4}, handler.timestamps)
+	assert.Equal(t, frames, handler.frames)
+	assert.Equal(t, 3*2+5, container.EntryCount(), "three scenes expected")
+}
+
+func TestStreamEncoderSupportsAllPaletteLookupModes(t *testing.T) {
+	width, height := 16, 8
+	for _, mode := range movie.PaletteLookupModes() {
+		builder := movie.NewContainerBuilder()
+		builder.VideoWidth(uint16(width)).VideoHeight(uint16(height))
+		encoder := movie.NewStreamEncoder(width, height, builder)
+		encoder.SetPaletteLookupMode(mode)
+		var frames [][]byte
+		for index := 0; index < 3; index++ {
+			frame := streamTestFrame(width, height, index)
+			frames = append(frames, frame)
+			require.Nil(t, encoder.Push(float32(index), frame), "no error expected pushing frame in mode %v", mode)
+		}
+		require.Nil(t, encoder.Close(), "no error expected closing in mode %v", mode)
+
+		handler := &capturingMediaHandler{}
+		dispatcher := movie.NewMediaDispatcher(builder.Build(), text.DefaultCodepage(), handler)
+		for more := true; more; {
+			var err error
+			more, err = dispatcher.DispatchNext()
+			require.Nil(t, err, "no error expected decoding in mode %v", mode)
+		}
+		assert.Equal(t, frames, handler.frames, "frames should be restored in mode %v", mode)
+	}
+}
+
+func TestStreamEncoderIsReproducibleWithSeed(t *testing.T) {
+	width, height := 16, 8
+	encode := func() movie.Container {
+		builder := movie.NewContainerBuilder()
+		encoder := movie.NewStreamEncoder(width, height, builder)
+		encoder.SetSeed(7)
+		for index := 0; index < 4; index++ {
+			require.Nil(t, encoder.Push(float32(index), streamTestFrame(width, height, index)), "no error expected")
+		}
+		require.Nil(t, encoder.Close(), "no error expected closing")
+		return builder.Build()
+	}
+	first := encode()
+	second := encode()
+
+	require.Equal(t, first.EntryCount(), second.EntryCount())
+	for index := 0; index < first.EntryCount(); index++ {
+		assert.Equal(t, first.Entry(index).Data(), second.Entry(index).Data(), "entry %d differs", index)
+	}
+}
+
+func TestStreamEncoderRejectsFramesAfterClose(t *testing.T) {
+	encoder := movie.NewStreamEncoder(4, 4, movie.NewContainerBuilder())
+	require.Nil(t, encoder.Close())
+
+	assert.Error(t, encoder.Push(0, make([]byte, 16)))
+}
+
+func TestStreamEncoderRejectsFramesOfWrongSize(t *testing.T) {
+	encoder := movie.NewStreamEncoder(4, 4, movie.NewContainerBuilder())
+
+	assert.Error(t, encoder.Push(0, make([]byte, 15)))
+}
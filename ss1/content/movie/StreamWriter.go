@@ -0,0 +1,111 @@
+package movie
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/inkyblackness/hacked/ss1/content/movie/internal/format"
+)
+
+// StreamWriter writes a MOVI container incrementally, one entry at a time. It is an EntryWriter.
+//
+// The data of each entry is written as soon as it is received, only the index is kept in memory.
+// As the index precedes the data in a container, space for it is reserved when the writer is created.
+// Close completes the container by writing header and index into the reserved space.
+type StreamWriter struct {
+	dest          io.WriteSeeker
+	start         int64
+	header        format.Header
+	palette       []byte
+	maxEntryCount int
+	dataStart     int32
+	indexEntries  []format.IndexTableEntry
+	closed        bool
+}
+
+// NewStreamWriter starts a container at the current position of dest.
+// The header properties, such as video size, start palette, and media duration, are taken from the template.
+// Entries of the template are ignored. maxEntryCount is the number of entries the index is reserved for.
+func NewStreamWriter(dest io.WriteSeeker, template Container, maxEntryCount int) (*StreamWriter, error) {
+	if maxEntryCount < 0 {
+		return nil, fmt.Errorf("invalid entry count %d", maxEntryCount)
+	}
+	start, err := dest.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, err
+	}
+	writer := &StreamWriter{
+		dest:          dest,
+		start:         start,
+		header:        headerFromContainer(template),
+		palette:       paletteDataFromContainer(template),
+		maxEntryCount: maxEntryCount,
+	}
+	writer.header.IndexSize = int32(indexTableSizeFor(maxEntryCount + 1))
+	writer.dataStart = format.HeaderSize + int32(len(writer.palette)) + writer.header.IndexSize
+	_, err = dest.Write(make([]byte, writer.dataStart))
+	if err != nil {
+		return nil, err
+	}
+	return writer, nil
+}
+
+// WriteEntry writes the data of the given entry and registers it in the index.
+func (writer *StreamWriter) WriteEntry(entry Entry) error {
+	if writer.closed {
+		return errors.New("writer is closed")
+	}
+	if len(writer.indexEntries) >= writer.maxEntryCount {
+		return fmt.Errorf("index is limited to %d entries", writer.maxEntryCount)
+	}
+	data := entry.Data()
+	indexEntry := format.IndexTableEntry{
+		Type:       byte(entry.Type()),
+		DataOffset: writer.dataStart + writer.header.ContentSize,
+	}
+	indexEntry.TimestampSecond, indexEntry.TimestampFraction = timeToRaw(entry.Timestamp())
+	_, err := writer.dest.Write(data)
+	if err != nil {
+		return err
+	}
+	writer.header.ContentSize += int32(len(data))
+	writer.indexEntries = append(writer.indexEntries, indexEntry)
+	return nil
+}
+
+// Close completes the container by writing header and index.
+// Afterwards, the position of dest is at the end of the container. Further entries are rejected.
+func (writer *StreamWriter) Close() error {
+	if writer.closed {
+		return nil
+	}
+	writer.closed = true
+	indexEntries := append(writer.indexEntries, format.IndexTableEntry{
+		TimestampSecond:   writer.header.DurationSeconds,
+		TimestampFraction: writer.header.DurationFraction,
+		Type:              byte(endOfMedia),
+		DataOffset:        writer.dataStart + writer.header.ContentSize,
+	})
+	writer.header.IndexEntryCount = int32(len(indexEntries))
+
+	_, err := writer.dest.Seek(writer.start, io.SeekStart)
+	if err != nil {
+		return err
+	}
+	err = binary.Write(writer.dest, binary.LittleEndian, &writer.header)
+	if err != nil {
+		return err
+	}
+	_, err = writer.dest.Write(writer.palette)
+	if err != nil {
+		return err
+	}
+	err = binary.Write(writer.dest, binary.LittleEndian, indexEntries)
+	if err != nil {
+		return err
+	}
+	_, err = writer.dest.Seek(writer.start+int64(writer.dataStart)+int64(writer.header.ContentSize), io.SeekStart)
+	return err
+}
@@ -0,0 +1,101 @@
+package movie_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/content/movie"
+	"github.com/inkyblackness/hacked/ss1/content/text"
+	"github.com/inkyblackness/hacked/ss1/serial"
+)
+
+func streamWriterTestTemplate() *movie.ContainerBuilder {
+	palette := gifTestPalette(10)
+	return movie.NewContainerBuilder().VideoWidth(16).VideoHeight(8).MediaDuration(3.0).StartPalette(&palette)
+}
+
+func TestStreamWriterProducesSameDataAsWrite(t *testing.T) {
+	entries := []movie.Entry{
+		movie.NewMemoryEntry(0.0, movie.Palette, make([]byte, 256*3)),
+		movie.NewMemoryEntry(0.5, movie.Audio, []byte{0x01, 0x02, 0x03}),
+		movie.NewMemoryEntry(1.5, movie.Subtitle, []byte{0x04}),
+	}
+	builder := streamWriterTestTemplate()
+	for _, entry := range entries {
+		builder.AddEntry(entry)
+	}
+	expected := bytes.NewBuffer(nil)
+	require.Nil(t, movie.Write(expected, builder.Build()), "no error expected writing container")
+
+	store := serial.NewByteStore()
+	writer, err := movie.NewStreamWriter(store, streamWriterTestTemplate().Build(), len(entries))
+	require.Nil(t, err, "no error expected creating writer")
+	for _, entry := range entries {
+		require.Nil(t, writer.WriteEntry(entry), "no error expected writing entry")
+	}
+	require.Nil(t, writer.Close(), "no error expected closing")
+
+	assert.Equal(t, expected.Bytes(), store.Data())
+}
+
+func TestStreamWriterReservesIndexForMoreEntries(t *testing.T) {
+	store := serial.NewByteStore()
+	writer, err := movie.NewStreamWriter(store, streamWriterTestTemplate().Build(), 1000)
+	require.Nil(t, err, "no error expected creating writer")
+	require.Nil(t, writer.WriteEntry(movie.NewMemoryEntry(1.0, movie.Audio, []byte{0xAA, 0xBB})))
+	require.Nil(t, writer.Close(), "no error expected closing")
+
+	container, err := movie.Read(bytes.NewReader(store.Data()))
+	require.Nil(t, err, "no error expected reading")
+	require.Equal(t, 1, container.EntryCount())
+	assert.Equal(t, []byte{0xAA, 0xBB}, container.Entry(0).Data())
+	assert.Equal(t, float32(3.0), container.MediaDuration())
+	expectedPalette := gifTestPalette(10)
+	assert.Equal(t, expectedPalette, container.StartPalette())
+}
+
+func TestStreamEntryCount(t *testing.T) {
+	assert.Equal(t, 0, movie.StreamEntryCount(0, 4))
+	assert.Equal(t, 3, movie.StreamEntryCount(1, 4))
+	assert.Equal(t, 6, movie.StreamEntryCount(4, 4))
+	assert.Equal(t, 9, movie.StreamEntryCount(5, 4))
+}
+
+func TestStreamWriterRejectsEntriesBeyondReservation(t *testing.T) {
+	writer, err := movie.NewStreamWriter(serial.NewByteStore(), movie.NewContainerBuilder().Build(), 1)
+	require.Nil(t, err, "no error expected creating writer")
+	require.Nil(t, writer.WriteEntry(movie.NewMemoryEntry(0.0, movie.Audio, []byte{0x01})))
+
+	assert.NotNil(t, writer.WriteEntry(movie.NewMemoryEntry(0.0, movie.Audio, []byte{0x02})), "error expected")
+}
+
+func TestStreamWriterStoresEncodedFrames(t *testing.T) {
+	width, height := 16, 8
+	frameCount := 5
+	store := serial.NewByteStore()
+	writer, err := movie.NewStreamWriter(store, streamWriterTestTemplate().Build(), movie.StreamEntryCount(frameCount, 2))
+	require.Nil(t, err, "no error expected creating writer")
+	encoder := movie.NewStreamEncoder(width, height, writer)
+	encoder.SetSceneLength(2)
+	var frames [][]byte
+	for index := 0; index < frameCount; index++ {
+		frame := streamTestFrame(width, height, index)
+		frames = append(frames, frame)
+		require.Nil(t, encoder.Push(float32(index)*0.5, frame), "no error expected pushing frame %d", index)
+	}
+	require.Nil(t, encoder.Close(), "no error expected closing encoder")
+	require.Nil(t, writer.Close(), "no error expected closing writer")
+
+	container, err := movie.Read(bytes.NewReader(store.Data()))
+	require.Nil(t, err, "no error expected reading")
+	handler := &capturingMediaHandler{}
+	dispatcher := movie.NewMediaDispatcher(container, text.DefaultCodepage(), handler)
+	for more := true; more; {
+		more, err = dispatcher.DispatchNext()
+		require.Nil(t, err, "no error expected decoding")
+	}
+	assert.Equal(t, frames, handler.frames)
+}
@@ -1,5 +1,7 @@
 package movie
 
+import "github.com/inkyblackness/hacked/ss1/resource"
+
 // SubtitleControl specifies how to interpret a subtitle entry.
 type SubtitleControl uint32
 
@@ -15,3 +17,29 @@ const (
 func (ctrl SubtitleControl) String() string {
 	return string([]rune{rune((ctrl >> 0) & 0xFF), rune((ctrl >> 8) & 0xFF), rune((ctrl >> 16) & 0xFF), rune((ctrl >> 24) & 0xFF)})
 }
+
+// SubtitleControlForLanguage returns the control value of subtitle texts in given language.
+// Returns false for languages without subtitle texts, such as resource.LangAny.
+func SubtitleControlForLanguage(lang resource.Language) (SubtitleControl, bool) {
+	switch lang {
+	case resource.LangDefault:
+		return SubtitleTextStd, true
+	case resource.LangFrench:
+		return SubtitleTextFrn, true
+	case resource.LangGerman:
+		return SubtitleTextGer, true
+	default:
+		return SubtitleArea, false
+	}
+}
+
+// Language returns the language of subtitle texts with this control value.
+// Returns false if the control value does not describe a subtitle text.
+func (ctrl SubtitleControl) Language() (resource.Language, bool) {
+	for _, lang := range resource.Languages() {
+		if langCtrl, _ := SubtitleControlForLanguage(lang); langCtrl == ctrl {
+			return lang, true
+		}
+	}
+	return resource.LangAny, false
+}
@@ -0,0 +1,68 @@
+package movie
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// SubtitleTiming specifies how subtitle texts are distributed over the duration of a movie.
+type SubtitleTiming int
+
+// SubtitleTiming constants.
+const (
+	// SubtitleTimingEven shows each text for the same amount of time.
+	SubtitleTimingEven SubtitleTiming = iota
+	// SubtitleTimingProportional shows each text for an amount of time proportional to its length.
+	SubtitleTimingProportional
+)
+
+// String returns the textual representation of the value.
+func (timing SubtitleTiming) String() string {
+	switch timing {
+	case SubtitleTimingEven:
+		return "Even"
+	case SubtitleTimingProportional:
+		return "Proportional"
+	default:
+		return fmt.Sprintf("Unknown%d", int(timing))
+	}
+}
+
+// DistributeSubtitles creates timed subtitle entries for the given texts, spread over the given duration in seconds.
+// As a subtitle is shown until the next one, the returned entries are in order of the texts and do not overlap.
+// All timestamps are within the duration, the last text is shown until the end of the movie.
+func DistributeSubtitles(duration float32, texts []string, timing SubtitleTiming) []SubtitleEntry {
+	if len(texts) == 0 {
+		return nil
+	}
+	if duration < 0 {
+		duration = 0
+	}
+	weights := make([]int, len(texts))
+	total := 0
+	for index, value := range texts {
+		weight := 1
+		if timing == SubtitleTimingProportional {
+			weight = utf8.RuneCountInString(value)
+			if weight < 1 {
+				weight = 1
+			}
+		}
+		weights[index] = weight
+		total += weight
+	}
+	entries := make([]SubtitleEntry, len(texts))
+	passed := 0
+	for index, value := range texts {
+		timestamp := duration * float32(passed) / float32(total)
+		if timestamp > duration {
+			timestamp = duration
+		}
+		if (index > 0) && (timestamp < entries[index-1].Timestamp) {
+			timestamp = entries[index-1].Timestamp
+		}
+		entries[index] = SubtitleEntry{Timestamp: timestamp, Text: value}
+		passed += weights[index]
+	}
+	return entries
+}
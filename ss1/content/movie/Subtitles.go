@@ -0,0 +1,102 @@
+package movie
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/inkyblackness/hacked/ss1/content/text"
+	"github.com/inkyblackness/hacked/ss1/resource"
+)
+
+// SubtitleEntry is one subtitle text, shown from its timestamp on until the next text of the same language.
+type SubtitleEntry struct {
+	// Timestamp is the time the text is shown, in seconds.
+	Timestamp float32
+	Text      string
+}
+
+// SubtitleLanguages returns the languages the container has subtitle texts for.
+// The languages are returned in the order of resource.Languages().
+func SubtitleLanguages(container Container) []resource.Language {
+	present := make(map[resource.Language]bool)
+	for index := 0; index < container.EntryCount(); index++ {
+		if lang, isText := subtitleLanguageOf(container.Entry(index)); isText {
+			present[lang] = true
+		}
+	}
+	var result []resource.Language
+	for _, lang := range resource.Languages() {
+		if present[lang] {
+			result = append(result, lang)
+		}
+	}
+	return result
+}
+
+// Subtitles returns the subtitle texts of given language, in order of the entries in the container.
+func Subtitles(container Container, lang resource.Language, cp text.Codepage) []SubtitleEntry {
+	var result []SubtitleEntry
+	for index := 0; index < container.EntryCount(); index++ {
+		entry := container.Entry(index)
+		if entryLang, isText := subtitleLanguageOf(entry); isText && (entryLang == lang) {
+			result = append(result, SubtitleEntry{
+				Timestamp: entry.Timestamp(),
+				Text:      cp.Decode(entry.Data()[SubtitleHeaderSize:]),
+			})
+		}
+	}
+	return result
+}
+
+// WithSubtitles returns a new container that has the subtitle texts of given language replaced with the given ones.
+// All other entries, including the subtitle texts of other languages and the subtitle area, are kept as they are.
+// The new texts are placed according to their timestamp, after any other entries of the same time.
+func WithSubtitles(container Container, lang resource.Language, cp text.Codepage, subtitles []SubtitleEntry) (Container, error) {
+	control, isTextLanguage := SubtitleControlForLanguage(lang)
+	if !isTextLanguage {
+		return nil, fmt.Errorf("language %v can not have subtitles", lang)
+	}
+	sorted := make([]SubtitleEntry, len(subtitles))
+	copy(sorted, subtitles)
+	sort.SliceStable(sorted, func(a, b int) bool { return sorted[a].Timestamp < sorted[b].Timestamp })
+
+	builder := NewContainerBuilderWithHeaderOf(container)
+	addSubtitlesBefore := func(limit float32) {
+		for (len(sorted) > 0) && (sorted[0].Timestamp < limit) {
+			builder.AddEntry(NewMemoryEntry(sorted[0].Timestamp, Subtitle, subtitleData(control, sorted[0].Text, cp)))
+			sorted = sorted[1:]
+		}
+	}
+	for index := 0; index < container.EntryCount(); index++ {
+		entry := container.Entry(index)
+		if entryLang, isText := subtitleLanguageOf(entry); isText && (entryLang == lang) {
+			continue
+		}
+		addSubtitlesBefore(entry.Timestamp())
+		builder.AddEntry(entry)
+	}
+	addSubtitlesBefore(float32(math.Inf(1)))
+	return builder.Build(), nil
+}
+
+func subtitleLanguageOf(entry Entry) (resource.Language, bool) {
+	if (entry.Type() != Subtitle) || (len(entry.Data()) < SubtitleHeaderSize) {
+		return resource.LangAny, false
+	}
+	var header SubtitleHeader
+	err := binary.Read(bytes.NewReader(entry.Data()), binary.LittleEndian, &header)
+	if err != nil {
+		return resource.LangAny, false
+	}
+	return header.Control.Language()
+}
+
+func subtitleData(control SubtitleControl, value string, cp text.Codepage) []byte {
+	buf := bytes.NewBuffer(nil)
+	_ = binary.Write(buf, binary.LittleEndian, &SubtitleHeader{Control: control})
+	buf.Write(cp.Encode(value))
+	return buf.Bytes()
+}
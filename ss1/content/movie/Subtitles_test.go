@@ -0,0 +1,111 @@
+package movie_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/content/movie"
+	"github.com/inkyblackness/hacked/ss1/content/text"
+	"github.com/inkyblackness/hacked/ss1/resource"
+)
+
+func subtitledContainer() movie.Container {
+	builder := movie.NewContainerBuilder()
+	builder.AddEntry(movie.NewMemoryEntry(0.0, movie.Subtitle, subtitleData(movie.SubtitleArea, "")))
+	builder.AddEntry(movie.NewMemoryEntry(0.0, movie.Audio, []byte{0x80}))
+	builder.AddEntry(movie.NewMemoryEntry(1.0, movie.Subtitle, subtitleData(movie.SubtitleTextStd, "Hello")))
+	builder.AddEntry(movie.NewMemoryEntry(1.0, movie.Subtitle, subtitleData(movie.SubtitleTextGer, "Hallo")))
+	builder.AddEntry(movie.NewMemoryEntry(2.0, movie.Audio, []byte{0x81}))
+	return builder.Build()
+}
+
+func TestSubtitleLanguagesListsPresentTracks(t *testing.T) {
+	langs := movie.SubtitleLanguages(subtitledContainer())
+
+	assert.Equal(t, []resource.Language{resource.LangDefault, resource.LangGerman}, langs)
+}
+
+func TestSubtitlesAreReadPerLanguage(t *testing.T) {
+	cp := text.DefaultCodepage()
+	container := subtitledContainer()
+
+	assert.Equal(t, []movie.SubtitleEntry{{Timestamp: 1.0, Text: "Hallo"}},
+		movie.Subtitles(container, resource.LangGerman, cp))
+	assert.Empty(t, movie.Subtitles(container, resource.LangFrench, cp))
+}
+
+func TestWithSubtitlesKeepsOtherLanguages(t *testing.T) {
+	cp := text.DefaultCodepage()
+	container, err := movie.WithSubtitles(subtitledContainer(), resource.LangFrench, cp, []movie.SubtitleEntry{
+		{Timestamp: 3.0, Text: "Au revoir"},
+		{Timestamp: 0.0, Text: "Bonjour"},
+	})
+	require.Nil(t, err, "no error expected")
+
+	assert.Equal(t, []movie.SubtitleEntry{{Timestamp: 0.0, Text: "Bonjour"}, {Timestamp: 3.0, Text: "Au revoir"}},
+		movie.Subtitles(container, resource.LangFrench, cp))
+	assert.Equal(t, []movie.SubtitleEntry{{Timestamp: 1.0, Text: "Hello"}},
+		movie.Subtitles(container, resource.LangDefault, cp))
+	assert.Equal(t, []movie.SubtitleEntry{{Timestamp: 1.0, Text: "Hallo"}},
+		movie.Subtitles(container, resource.LangGerman, cp))
+	assert.Equal(t, 7, container.EntryCount())
+	assert.Equal(t, movie.Audio, container.Entry(1).Type(), "existing entries of same time expected first")
+}
+
+func TestWithSubtitlesReplacesLanguage(t *testing.T) {
+	cp := text.DefaultCodepage()
+	container, err := movie.WithSubtitles(subtitledContainer(), resource.LangDefault, cp, nil)
+	require.Nil(t, err, "no error expected")
+
+	assert.Equal(t, []resource.Language{resource.LangGerman}, movie.SubtitleLanguages(container))
+	assert.Equal(t, 4, container.EntryCount())
+}
+
+func TestWithSubtitlesRequiresTextLanguage(t *testing.T) {
+	_, err := movie.WithSubtitles(subtitledContainer(), resource.LangAny, text.DefaultCodepage(), nil)
+	assert.Error(t, err)
+}
+
+func TestDistributeSubtitles(t *testing.T) {
+	tt := []struct {
+		name     string
+		duration float32
+		texts    []string
+		timing   movie.SubtitleTiming
+		expected []float32
+	}{
+		{name: "even", duration: 9.0, texts: []string{"a", "bbbbbb", "cc"}, timing: movie.SubtitleTimingEven,
+			expected: []float32{0.0, 3.0, 6.0}},
+		{name: "proportional", duration: 9.0, texts: []string{"a", "bbbbbb", "cc"}, timing: movie.SubtitleTimingProportional,
+			expected: []float32{0.0, 1.0, 7.0}},
+		{name: "empty texts have minimum weight", duration: 4.0, texts: []string{"", "abc"}, timing: movie.SubtitleTimingProportional,
+			expected: []float32{0.0, 1.0}},
+		{name: "negative duration", duration: -1.0, texts: []string{"a", "b"}, timing: movie.SubtitleTimingEven,
+			expected: []float32{0.0, 0.0}},
+	}
+
+	for _, tc := range tt {
+		td := tc
+		t.Run(td.name, func(t *testing.T) {
+			entries := movie.DistributeSubtitles(td.duration, td.texts, td.timing)
+			require.Equal(t, len(td.texts), len(entries))
+			for index, entry := range entries {
+				assert.Equal(t, td.texts[index], entry.Text)
+				assert.InDelta(t, td.expected[index], entry.Timestamp, 0.0001, "timestamp of entry %d", index)
+			}
+		})
+	}
+}
+
+func TestDistributeSubtitlesKeepsEntriesWithinDuration(t *testing.T) {
+	entries := movie.DistributeSubtitles(10.0, []string{"one", "two", "three", "four"}, movie.SubtitleTimingProportional)
+
+	for index, entry := range entries {
+		assert.True(t, entry.Timestamp < 10.0, "entry %d beyond end", index)
+		if index > 0 {
+			assert.True(t, entry.Timestamp > entries[index-1].Timestamp, "entry %d overlaps previous", index)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package movie
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/inkyblackness/hacked/ss1/content/movie/internal/format"
+)
+
+// startPaletteSize is the length of the palette following the header.
+const startPaletteSize = 0x300
+
+// Summary contains the basic information of a movie, which can be retrieved without decoding the media.
+type Summary struct {
+	// Duration is the length of the media in seconds.
+	Duration float32
+	// VideoWidth is the width of the video in pixel.
+	VideoWidth uint16
+	// VideoHeight is the height of the video in pixel.
+	VideoHeight uint16
+
+	// FrameCount is the number of video frames.
+	FrameCount int
+	// HasAudio is true if the movie contains any audio entries.
+	HasAudio bool
+	// SubtitleCount is the number of subtitle texts, across all languages.
+	SubtitleCount int
+}
+
+// ReadSummary extracts the summary of a MOVI container from the provided reader.
+// Only the header, the index, and the headers of subtitle entries are read.
+// On return, the position of the reader is undefined.
+func ReadSummary(source io.ReadSeeker) (summary Summary, err error) {
+	if source == nil {
+		return summary, fmt.Errorf("source is nil")
+	}
+	startPos, err := source.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return
+	}
+	var header format.Header
+	err = binary.Read(source, binary.LittleEndian, &header)
+	if err != nil {
+		return
+	}
+	if string(header.Tag[:]) != format.Tag {
+		return summary, errors.New("not a MOVI format")
+	}
+	summary.Duration = timeFromRaw(header.DurationSeconds, header.DurationFraction)
+	summary.VideoWidth = header.VideoWidth
+	summary.VideoHeight = header.VideoHeight
+
+	endPos, err := source.Seek(0, io.SeekEnd)
+	if err != nil {
+		return
+	}
+	indexPos, err := source.Seek(startPos+format.HeaderSize+startPaletteSize, io.SeekStart)
+	if err != nil {
+		return
+	}
+	if (header.IndexEntryCount < 0) ||
+		(int64(header.IndexEntryCount)*format.IndexTableEntrySize > endPos-indexPos) {
+		return summary, errFormat("invalid index entry count %d", header.IndexEntryCount)
+	}
+	indexEntries := make([]format.IndexTableEntry, header.IndexEntryCount)
+	err = binary.Read(source, binary.LittleEndian, indexEntries)
+	if err != nil {
+		return
+	}
+	for _, indexEntry := range indexEntries {
+		switch DataType(indexEntry.Type) {
+		case LowResVideo, HighResVideo:
+			summary.FrameCount++
+		case Audio:
+			summary.HasAudio = true
+		case Subtitle:
+			var control SubtitleControl
+			_, err = source.Seek(startPos+int64(indexEntry.DataOffset), io.SeekStart)
+			if err != nil {
+				return
+			}
+			err = binary.Read(source, binary.LittleEndian, &control)
+			if err != nil {
+				return
+			}
+			if control != SubtitleArea {
+				summary.SubtitleCount++
+			}
+		}
+	}
+	return summary, nil
+}
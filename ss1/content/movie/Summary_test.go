@@ -0,0 +1,80 @@
+package movie_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/content/movie"
+)
+
+func subtitleData(control movie.SubtitleControl, text string) []byte {
+	buf := bytes.NewBuffer(nil)
+	_ = binary.Write(buf, binary.LittleEndian, &movie.SubtitleHeader{Control: control})
+	buf.WriteString(text)
+	buf.WriteByte(0x00)
+	return buf.Bytes()
+}
+
+func TestReadSummaryReturnsErrorOnNil(t *testing.T) {
+	_, err := movie.ReadSummary(nil)
+
+	assert.Error(t, err)
+}
+
+func TestReadSummaryReturnsErrorOnMissingTag(t *testing.T) {
+	_, err := movie.ReadSummary(bytes.NewReader(make([]byte, 0x800)))
+
+	assert.Error(t, err)
+}
+
+func TestReadSummaryDescribesContent(t *testing.T) {
+	builder := movie.NewContainerBuilder()
+	builder.MediaDuration(2.5).VideoWidth(600).VideoHeight(300).AudioSampleRate(22050)
+	builder.AddEntry(movie.NewMemoryEntry(0.0, movie.Subtitle, subtitleData(movie.SubtitleArea, "")))
+	builder.AddEntry(movie.NewMemoryEntry(0.0, movie.LowResVideo, []byte{0x00}))
+	builder.AddEntry(movie.NewMemoryEntry(0.0, movie.Audio, []byte{0x80, 0x80}))
+	builder.AddEntry(movie.NewMemoryEntry(1.0, movie.Subtitle, subtitleData(movie.SubtitleTextStd, "Hello")))
+	builder.AddEntry(movie.NewMemoryEntry(1.0, movie.Subtitle, subtitleData(movie.SubtitleTextGer, "Hallo")))
+	builder.AddEntry(movie.NewMemoryEntry(1.5, movie.HighResVideo, []byte{0x00}))
+	buffer := bytes.NewBuffer(nil)
+	err := movie.Write(buffer, builder.Build())
+	require.Nil(t, err, "no error expected writing")
+
+	summary, err := movie.ReadSummary(bytes.NewReader(buffer.Bytes()))
+
+	require.Nil(t, err, "no error expected")
+	assert.InDelta(t, 2.5, summary.Duration, 0.001)
+	assert.Equal(t, uint16(600), summary.VideoWidth)
+	assert.Equal(t, uint16(300), summary.VideoHeight)
+	assert.Equal(t, 2, summary.FrameCount)
+	assert.True(t, summary.HasAudio)
+	assert.Equal(t, 2, summary.SubtitleCount)
+}
+
+func TestReadSummaryOfEmptyContainer(t *testing.T) {
+	buffer := bytes.NewBuffer(nil)
+	err := movie.Write(buffer, movie.NewContainerBuilder().Build())
+	require.Nil(t, err, "no error expected writing")
+
+	summary, err := movie.ReadSummary(bytes.NewReader(buffer.Bytes()))
+
+	require.Nil(t, err, "no error expected")
+	assert.Equal(t, movie.Summary{}, summary)
+}
+
+func TestReadSummaryReturnsErrorForOversizedIndexEntryCount(t *testing.T) {
+	buffer := bytes.NewBuffer(nil)
+	err := movie.Write(buffer, movie.NewContainerBuilder().Build())
+	require.Nil(t, err, "no error expected writing")
+	data := buffer.Bytes()
+	binary.LittleEndian.PutUint32(data[4:8], 0x7FFFFFF0)
+
+	_, err = movie.ReadSummary(bytes.NewReader(data))
+
+	assert.True(t, errors.Is(err, movie.ErrMalformed), "malformed error expected, got %v", err)
+}
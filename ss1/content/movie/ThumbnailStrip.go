@@ -0,0 +1,50 @@
+package movie
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+
+	"github.com/inkyblackness/hacked/ss1/content/bitmap"
+)
+
+// ThumbnailFrames returns the indices of count frames, evenly spaced over the given number of frames.
+// Each index is the center of its share of the movie. At most frameCount indices are returned.
+func ThumbnailFrames(frameCount, count int) []int {
+	if count > frameCount {
+		count = frameCount
+	}
+	if count <= 0 {
+		return nil
+	}
+	indices := make([]int, count)
+	for index := range indices {
+		indices[index] = ((2*index + 1) * frameCount) / (2 * count)
+	}
+	return indices
+}
+
+// ThumbnailStrip returns an image with thumbnails of count frames of the movie, placed side by side.
+// The frames are evenly spaced, see ThumbnailFrames(), and each is scaled to the given size.
+// Movies with fewer frames than requested result in a correspondingly narrower image.
+//
+// Only the frames up to the last thumbnail are decoded, skipping scenes before palette changes
+// where possible. Only the current frame is kept in memory while decoding.
+func ThumbnailStrip(container Container, count int, size image.Point) (*image.RGBA, error) {
+	if (size.X <= 0) || (size.Y <= 0) {
+		return nil, fmt.Errorf("invalid thumbnail size %v", size)
+	}
+	player := NewFramePlayer(container, container.EntryCount()+1)
+	frames := ThumbnailFrames(player.FrameCount(), count)
+	strip := image.NewRGBA(image.Rect(0, 0, len(frames)*size.X, size.Y))
+	for position, frame := range frames {
+		err := player.Seek(frame)
+		if err != nil {
+			return nil, err
+		}
+		thumbnail := bitmap.Resized(palettedFrame(player.Frame()), size.X, size.Y, bitmap.ResizeBilinear)
+		target := image.Rect(position*size.X, 0, (position+1)*size.X, size.Y)
+		draw.Draw(strip, target, thumbnail, image.Point{}, draw.Src)
+	}
+	return strip, nil
+}
@@ -0,0 +1,62 @@
+package movie_test
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/content/movie"
+)
+
+func TestThumbnailFrames(t *testing.T) {
+	tt := []struct {
+		frameCount int
+		count      int
+		expected   []int
+	}{
+		{frameCount: 10, count: 2, expected: []int{2, 7}},
+		{frameCount: 10, count: 5, expected: []int{1, 3, 5, 7, 9}},
+		{frameCount: 4, count: 4, expected: []int{0, 1, 2, 3}},
+		{frameCount: 3, count: 5, expected: []int{0, 1, 2}},
+		{frameCount: 100, count: 1, expected: []int{50}},
+		{frameCount: 0, count: 3, expected: nil},
+		{frameCount: 10, count: 0, expected: nil},
+	}
+	for _, tc := range tt {
+		td := tc
+		assert.Equal(t, td.expected, movie.ThumbnailFrames(td.frameCount, td.count),
+			"wrong frames for %d of %d", td.count, td.frameCount)
+	}
+}
+
+func TestThumbnailStripPlacesFramesSideBySide(t *testing.T) {
+	strip, err := movie.ThumbnailStrip(gifTestContainer(t), 4, image.Point{X: 16, Y: 8})
+	require.Nil(t, err, "no error expected")
+	assert.Equal(t, image.Rect(0, 0, 64, 8), strip.Bounds())
+
+	startPalette := gifTestPalette(10)
+	otherPalette := gifTestPalette(200)
+	expectPixel := func(frame, seed int, palette color.Palette) {
+		pixels := streamTestFrame(16, 8, seed)
+		for _, pos := range []image.Point{{X: 0, Y: 0}, {X: 5, Y: 3}, {X: 15, Y: 7}} {
+			expected := color.RGBAModel.Convert(palette[pixels[pos.Y*16+pos.X]])
+			assert.Equal(t, expected, strip.At(frame*16+pos.X, pos.Y), "wrong pixel of frame %d at %v", frame, pos)
+		}
+	}
+	expectPixel(1, 1, startPalette.ColorPalette(false))
+	expectPixel(3, 3, otherPalette.ColorPalette(false))
+}
+
+func TestThumbnailStripLimitsCountToFrames(t *testing.T) {
+	strip, err := movie.ThumbnailStrip(gifTestContainer(t), 10, image.Point{X: 4, Y: 2})
+	require.Nil(t, err, "no error expected")
+	assert.Equal(t, image.Rect(0, 0, 16, 2), strip.Bounds())
+}
+
+func TestThumbnailStripRequiresValidSize(t *testing.T) {
+	_, err := movie.ThumbnailStrip(gifTestContainer(t), 2, image.Point{X: 0, Y: 2})
+	assert.NotNil(t, err, "error expected")
+}
@@ -13,23 +13,9 @@ const indexHeaderSizeIncrement = 0x0400
 // Write encodes the provided container into the given writer.
 func Write(dest io.Writer, container Container) error {
 	var indexEntries []format.IndexTableEntry
-	var header format.Header
+	header := headerFromContainer(container)
 	palette := paletteDataFromContainer(container)
 
-	// setup header
-	copy(header.Tag[:], bytes.NewBufferString(format.Tag).Bytes())
-	header.DurationSeconds, header.DurationFraction = timeToRaw(container.MediaDuration())
-	header.VideoWidth = container.VideoWidth()
-	header.VideoHeight = container.VideoHeight()
-	header.SampleRate = container.AudioSampleRate()
-
-	if header.VideoWidth != 0 {
-		header.Unknown001C = 0x0008
-		header.Unknown001E = 0x0001
-	}
-	header.Unknown0020 = 0x0001
-	header.Unknown0022 = 0x00000001
-
 	// create index
 	for i := 0; i < container.EntryCount(); i++ {
 		dataEntry := container.Entry(i)
@@ -84,6 +70,25 @@ func Write(dest io.Writer, container Container) error {
 	return nil
 }
 
+// headerFromContainer returns a header with the properties of the container.
+// The fields describing index and content are left empty.
+func headerFromContainer(container Container) format.Header {
+	var header format.Header
+	copy(header.Tag[:], bytes.NewBufferString(format.Tag).Bytes())
+	header.DurationSeconds, header.DurationFraction = timeToRaw(container.MediaDuration())
+	header.VideoWidth = container.VideoWidth()
+	header.VideoHeight = container.VideoHeight()
+	header.SampleRate = container.AudioSampleRate()
+
+	if header.VideoWidth != 0 {
+		header.Unknown001C = 0x0008
+		header.Unknown001E = 0x0001
+	}
+	header.Unknown0020 = 0x0001
+	header.Unknown0022 = 0x00000001
+	return header
+}
+
 func paletteDataFromContainer(container Container) []byte {
 	palette := container.StartPalette()
 	buf := bytes.NewBuffer(nil)
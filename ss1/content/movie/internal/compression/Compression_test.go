@@ -67,6 +67,20 @@ func (suite *CompressionSuite) TestTwoTiles() {
 	suite.verifyCompression(8, 4, frame0)
 }
 
+func (suite *CompressionSuite) TestDirectPaletteLookup() {
+	r := rand.New(rand.NewSource(0))
+	width, height := 16, 8
+	frames := make([][]byte, 3)
+	for frameIndex := range frames {
+		frame := make([]byte, width*height)
+		for index := range frame {
+			frame[index] = byte(1 + r.Intn(12))
+		}
+		frames[frameIndex] = frame
+	}
+	verifyCompressionInMode(suite.T(), compression.PaletteLookupDirect, width, height, frames...)
+}
+
 func (suite *CompressionSuite) verifyCompression(width, height int, inFrames ...[]byte) {
 	suite.T().Helper()
 	verifyCompression(suite.T(), width, height, inFrames...)
@@ -85,6 +99,14 @@ func BenchmarkRecompressionDeth02(b *testing.B) {
 }
 
 func recompress(t testing.TB, dir1, dir2 string) {
+	t.Helper()
+	width, height, frames := decodeTestScene(t, dir1, dir2)
+	fmt.Printf("verifying compression of %v frames\n", len(frames))
+	verifyCompression(t, width, height, frames...)
+}
+
+// decodeTestScene decodes the frames of a scene of the test data.
+func decodeTestScene(t testing.TB, dir1, dir2 string) (width, height int, frames [][]byte) {
 	t.Helper()
 	scenepath := filepath.Join(".", "_testdata", dir1, dir2)
 	read := func(name string) []byte {
@@ -97,8 +119,8 @@ func recompress(t testing.TB, dir1, dir2 string) {
 	require.Nil(t, err, "no error expected unpacking control words")
 	paletteLookup := read("paletteLookup.bin")
 
-	width := 600
-	height := 300
+	width = 600
+	height = 300
 	frame := make([]byte, width*height)
 	decoderBuilder := compression.NewFrameDecoderBuilder(600, 300)
 
@@ -127,16 +149,13 @@ func recompress(t testing.TB, dir1, dir2 string) {
 		return true
 	}
 	frameIndex := 0
-	var frames [][]byte
 	for canDecodeFrame(frameIndex) {
 		frameCopy := make([]byte, len(frame))
 		copy(frameCopy, frame)
 		frames = append(frames, frameCopy)
 		frameIndex++
 	}
-
-	fmt.Printf("verifying compression of %v frames\n", len(frames))
-	verifyCompression(t, width, height, frames...)
+	return
 }
 
 func BenchmarkRandomFrames(b *testing.B) {
@@ -183,8 +202,16 @@ func BenchmarkRandomFrames(b *testing.B) {
 }
 
 func verifyCompression(t testing.TB, width, height int, inFrames ...[]byte) {
+	t.Helper()
+	verifyCompressionInMode(t, compression.PaletteLookupCompact, width, height, inFrames...)
+}
+
+func verifyCompressionInMode(t testing.TB, mode compression.PaletteLookupMode, width, height int, inFrames ...[]byte) {
 	t.Helper()
 	encoder := compression.NewSceneEncoder(width, height)
+	encoder.SetPaletteLookupMode(mode)
+	_, isBenchmark := t.(*testing.B)
+	encoder.SetLookupVerification(!isBenchmark)
 	for frameIndex, frame := range inFrames {
 		require.Equal(t, width*height, len(frame), fmt.Sprintf("Length of frame %d is wrong for dimension", frameIndex))
 		err := encoder.AddFrame(frame)
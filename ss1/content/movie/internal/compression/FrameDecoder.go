@@ -63,7 +63,10 @@ func (decoder *FrameDecoder) Decode(bitstreamData []byte, maskstreamData []byte)
 					hTile += int(skipCount)
 				}
 			} else {
-				decoder.colorTile(hTile, vTile, control, maskstream)
+				err = decoder.colorTile(hTile, vTile, control, maskstream)
+				if err != nil {
+					return err
+				}
 			}
 
 			lastControl = control
@@ -75,7 +78,7 @@ func (decoder *FrameDecoder) Decode(bitstreamData []byte, maskstreamData []byte)
 func (decoder *FrameDecoder) readNextControlWord(bitstream *BitstreamReader) (ControlWord, error) {
 	availableWords := uint32(len(decoder.controlWords))
 	controlIndex := bitstream.Read(12)
-	if controlIndex > availableWords {
+	if controlIndex >= availableWords {
 		return ControlWordOf(0, CtrlUnknown, 0),
 			fmt.Errorf("control word index out of range: %v/%v", controlIndex, availableWords)
 	}
@@ -92,7 +95,7 @@ func (decoder *FrameDecoder) readNextControlWord(bitstream *BitstreamReader) (Co
 			bitstream.Advance(4)
 			offset := bitstream.Read(4)
 			controlIndex = control.LongOffset() + offset
-			if controlIndex > availableWords {
+			if controlIndex >= availableWords {
 				return ControlWordOf(0, CtrlUnknown, 0),
 					fmt.Errorf("control word index out of range: %v/%v", controlIndex, availableWords)
 			}
@@ -103,8 +106,14 @@ func (decoder *FrameDecoder) readNextControlWord(bitstream *BitstreamReader) (Co
 	return control, nil
 }
 
-func (decoder *FrameDecoder) colorTile(hTile, vTile int, control ControlWord, maskstream *MaskstreamReader) {
+func (decoder *FrameDecoder) colorTile(hTile, vTile int, control ControlWord, maskstream *MaskstreamReader) error {
 	param := control.Parameter()
+	lookup := func(count uint32) ([]byte, error) {
+		if uint64(param)+uint64(count) > uint64(len(decoder.paletteLookupList)) {
+			return nil, fmt.Errorf("palette lookup index out of range: %v/%v", param, len(decoder.paletteLookupList))
+		}
+		return decoder.paletteLookupList[param : param+count], nil
+	}
 
 	switch control.Type() {
 	case CtrlColorTile2ColorsStatic:
@@ -112,10 +121,23 @@ func (decoder *FrameDecoder) colorTile(hTile, vTile int, control ControlWord, ma
 	case CtrlColorTile2ColorsMasked:
 		decoder.colorer(hTile, vTile, []byte{byte(param & 0xFF), byte(param >> 8 & 0xFF)}, maskstream.Read(2), 1)
 	case CtrlColorTile4ColorsMasked:
-		decoder.colorer(hTile, vTile, decoder.paletteLookupList[param:param+4], maskstream.Read(4), 2)
+		colors, err := lookup(4)
+		if err != nil {
+			return err
+		}
+		decoder.colorer(hTile, vTile, colors, maskstream.Read(4), 2)
 	case CtrlColorTile8ColorsMasked:
-		decoder.colorer(hTile, vTile, decoder.paletteLookupList[param:param+8], maskstream.Read(6), 3)
+		colors, err := lookup(8)
+		if err != nil {
+			return err
+		}
+		decoder.colorer(hTile, vTile, colors, maskstream.Read(6), 3)
 	case CtrlColorTile16ColorsMasked:
-		decoder.colorer(hTile, vTile, decoder.paletteLookupList[param:param+16], maskstream.Read(8), 4)
+		colors, err := lookup(16)
+		if err != nil {
+			return err
+		}
+		decoder.colorer(hTile, vTile, colors, maskstream.Read(8), 4)
 	}
+	return nil
 }
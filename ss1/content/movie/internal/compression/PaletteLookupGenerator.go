@@ -15,6 +15,39 @@ type paletteLookupEntry struct {
 type PaletteLookup struct {
 	buffer  []byte
 	entries map[tilePaletteKey]paletteLookupEntry
+	stats   PaletteLookupStats
+}
+
+// PaletteLookupStats describes how a palette lookup was generated.
+// Palettes of registered tiles are either found within the buffer, sharing the bytes of other palettes,
+// or they are appended to the buffer. A high number of appended bytes compared to the shared ones
+// indicates that the generator could not find many overlaps.
+//
+// The remainder is the tail of the buffer, made up of the last appended palettes that no other palette shares.
+// A palette that is appended early may still be shared by a later one, so the remainder is not derived from
+// the appended bytes. A large remainder indicates that the generator gave up on finding overlaps.
+type PaletteLookupStats struct {
+	// SharedKeys is the number of palettes that were found within the buffer.
+	SharedKeys int
+	// SharedBytes is the sum of the sizes of the palettes that were found within the buffer.
+	// These are the bytes saved compared to appending every palette.
+	SharedBytes int
+	// AppendedKeys is the number of palettes that were appended to the buffer.
+	AppendedKeys int
+	// AppendedBytes is the sum of the sizes of the palettes that were appended. This equals the size of the buffer.
+	AppendedBytes int
+	// RemainderStart is the offset in the buffer at which the remainder starts.
+	// It equals the size of the buffer if there is no remainder.
+	RemainderStart int
+	// RemainderBytes is the size of the remainder.
+	RemainderBytes int
+}
+
+// String returns a textual representation of the statistics.
+func (stats PaletteLookupStats) String() string {
+	return fmt.Sprintf("Shared: %v keys, %vB; Appended: %v keys, %vB; Remainder: %vB at %v",
+		stats.SharedKeys, stats.SharedBytes, stats.AppendedKeys, stats.AppendedBytes,
+		stats.RemainderBytes, stats.RemainderStart)
 }
 
 // Buffer returns the underlying slice.
@@ -22,8 +55,33 @@ func (lookup *PaletteLookup) Buffer() []byte {
 	return lookup.buffer
 }
 
+// Stats returns the statistics of the generation of this lookup.
+func (lookup *PaletteLookup) Stats() PaletteLookupStats {
+	return lookup.stats
+}
+
+// measureRemainder determines the tail of the buffer that is only used by the given appended palettes,
+// which are in order of the buffer.
+func (lookup *PaletteLookup) measureRemainder(appended []paletteLookupEntry) {
+	isAppended := make(map[paletteLookupEntry]bool, len(appended))
+	for _, entry := range appended {
+		isAppended[entry] = true
+	}
+	sharedEnd := 0
+	for _, entry := range lookup.entries {
+		if !isAppended[entry] && (entry.start+entry.size > sharedEnd) {
+			sharedEnd = entry.start + entry.size
+		}
+	}
+	lookup.stats.RemainderStart = len(lookup.buffer)
+	for index := len(appended) - 1; (index >= 0) && (appended[index].start >= sharedEnd); index-- {
+		lookup.stats.RemainderStart = appended[index].start
+	}
+	lookup.stats.RemainderBytes = len(lookup.buffer) - lookup.stats.RemainderStart
+}
+
 // Lookup finds the given tile again and returns the properties where and how to reproduce it.
-func (lookup *PaletteLookup) Lookup(tile tileDelta) (index int, pal []byte, mask uint64) {
+func (lookup *PaletteLookup) Lookup(tile TileDelta) (index int, pal []byte, mask uint64) {
 	key := tilePaletteKeyFrom(tile[:])
 	entry, inLookup := lookup.entries[key]
 	if inLookup {
@@ -44,15 +102,61 @@ func (lookup *PaletteLookup) Lookup(tile tileDelta) (index int, pal []byte, mask
 	return
 }
 
+// PaletteLookupMode specifies how a palette lookup is generated.
+type PaletteLookupMode int
+
+// PaletteLookupMode constants.
+const (
+	// PaletteLookupCompact searches for palettes that can be shared among tiles, and for overlaps of palettes.
+	// This results in the smallest lookup, at the cost of encoding time, which grows considerably
+	// with the number of distinct tiles.
+	PaletteLookupCompact PaletteLookupMode = iota
+	// PaletteLookupDirect stores the palette of each distinct tile as is, in order of their colors.
+	// This is fast, yet the lookup becomes larger. As the size of a lookup is limited, scenes with many distinct
+	// tiles may no longer be encodable in this mode.
+	PaletteLookupDirect
+	// PaletteLookupGreedy stores the palettes in order of decreasing size, and reuses a section of a stored palette
+	// for any smaller palette that fits within. This is fast and works well for few distinct palettes,
+	// yet does not search for overlaps of palettes.
+	PaletteLookupGreedy
+	// PaletteLookupAutomatic selects the mode based on the number of distinct palettes:
+	// PaletteLookupGreedy is used for fewer than GreedyPaletteLookupLimit palettes, PaletteLookupCompact otherwise.
+	PaletteLookupAutomatic
+)
+
+// GreedyPaletteLookupLimit is the number of distinct palettes from which on PaletteLookupAutomatic
+// uses PaletteLookupCompact instead of PaletteLookupGreedy.
+const GreedyPaletteLookupLimit = 256
+
 // PaletteLookupGenerator creates palette lookups based on a set of registered tiles.
 type PaletteLookupGenerator struct {
+	// Mode specifies how the lookup is generated. All modes produce lookups that are decoded the same way.
+	Mode PaletteLookupMode
+	// Seed is the basis for choices between otherwise equivalent options. Any seed, including the default of zero,
+	// produces the same lookup for the same registered tiles. Different seeds may produce different lookups.
+	Seed uint64
+
 	keyUses map[tilePaletteKey]int
 }
 
 // Generate creates a lookup based on all currently registered tile deltas.
 func (gen *PaletteLookupGenerator) Generate() PaletteLookup {
+	mode := gen.Mode
+	if mode == PaletteLookupAutomatic {
+		mode = PaletteLookupCompact
+		if len(gen.keyUses) < GreedyPaletteLookupLimit {
+			mode = PaletteLookupGreedy
+		}
+	}
+	switch mode {
+	case PaletteLookupDirect:
+		return gen.generateDirect()
+	case PaletteLookupGreedy:
+		return gen.generateGreedy()
+	}
 	var lookup PaletteLookup
 	lookup.entries = make(map[tilePaletteKey]paletteLookupEntry)
+	var appended []paletteLookupEntry
 
 	remainder := make(map[tilePaletteKey]struct{})
 	for key := range gen.keyUses {
@@ -112,12 +216,26 @@ func (gen *PaletteLookupGenerator) Generate() PaletteLookup {
 		for _, fitSize := range knownSizes {
 			if key.size <= fitSize && fitSize <= limitSize {
 				entry := sizedEntries[fitSize]
+				found := false
+				var chosen paletteLookupEntry
+				var chosenRank uint64
 				for tempKey, paletteEntry := range entry.entries {
 					if tempKey.contains(&key) && (!key.hasColor(0x00) || (lookup.buffer[paletteEntry.start] == 0x00)) {
-						lookup.entries[key] = paletteEntry
-						return true
+						// Of all candidates, the one with the lowest rank is taken, independent of map order.
+						rank := seededRank(gen.Seed, uint64(paletteEntry.start))
+						if !found || (rank < chosenRank) {
+							found = true
+							chosen = paletteEntry
+							chosenRank = rank
+						}
 					}
 				}
+				if found {
+					lookup.entries[key] = chosen
+					lookup.stats.SharedKeys++
+					lookup.stats.SharedBytes += key.size
+					return true
+				}
 			}
 		}
 		return false
@@ -132,7 +250,9 @@ func (gen *PaletteLookupGenerator) Generate() PaletteLookup {
 				keysInSize = append(keysInSize, key)
 			}
 		}
-		sort.Slice(keysInSize, func(a, b int) bool { return keysInSize[a].lessThan(&keysInSize[b]) })
+		// The keys are first brought into a defined order, as sorting with lessThan() depends on the initial order.
+		sort.Slice(keysInSize, func(a, b int) bool { return keysInSize[a].usedColors.Less(keysInSize[b].usedColors) })
+		sort.SliceStable(keysInSize, func(a, b int) bool { return keysInSize[a].lessThan(&keysInSize[b]) })
 
 		fmt.Printf("Working on key size %v, have %v sized, %v total remaining\n", size, len(keysInSize), len(remainder))
 		for _, sizedKey := range keysInSize {
@@ -149,19 +269,98 @@ func (gen *PaletteLookupGenerator) Generate() PaletteLookup {
 			}
 			if _, stillRemaining := remainder[sizedKey]; stillRemaining {
 				bytes := sizedKey.buffer()
-				lookup.entries[sizedKey] = paletteLookupEntry{start: len(lookup.buffer), size: len(bytes)}
+				entry := paletteLookupEntry{start: len(lookup.buffer), size: len(bytes)}
+				lookup.entries[sizedKey] = entry
+				appended = append(appended, entry)
 				addToBuffer(bytes)
+				lookup.stats.AppendedKeys++
+				lookup.stats.AppendedBytes += len(bytes)
 
 				delete(remainder, sizedKey)
 			}
 		}
 	}
+	lookup.measureRemainder(appended)
 
 	return lookup
 }
 
+func (gen *PaletteLookupGenerator) generateDirect() PaletteLookup {
+	var lookup PaletteLookup
+	lookup.entries = make(map[tilePaletteKey]paletteLookupEntry)
+	var appended []paletteLookupEntry
+	for _, key := range gen.sortedKeys() {
+		bytes := key.buffer()
+		entry := paletteLookupEntry{start: len(lookup.buffer), size: len(bytes)}
+		lookup.entries[key] = entry
+		appended = append(appended, entry)
+		lookup.buffer = append(lookup.buffer, bytes...)
+		lookup.stats.AppendedKeys++
+		lookup.stats.AppendedBytes += len(bytes)
+	}
+	lookup.measureRemainder(appended)
+	return lookup
+}
+
+func (gen *PaletteLookupGenerator) generateGreedy() PaletteLookup {
+	var lookup PaletteLookup
+	lookup.entries = make(map[tilePaletteKey]paletteLookupEntry)
+	keys := gen.sortedKeys()
+	sort.SliceStable(keys, func(a, b int) bool { return keys[a].size > keys[b].size })
+	var appended []paletteLookupEntry
+	for _, key := range keys {
+		sizeLimit := 16
+		if key.size <= 3 {
+			sizeLimit = 4
+		} else if key.size <= 8 {
+			sizeLimit = 8
+		}
+		shared := false
+		for _, entry := range appended {
+			// Stored palettes are in order of their colors, so a contained color 0x00 is always the first of the section.
+			section, contained := key.sectionIn(lookup.buffer[entry.start : entry.start+entry.size])
+			if contained && (section.size <= sizeLimit) {
+				lookup.entries[key] = paletteLookupEntry{start: entry.start + section.start, size: section.size}
+				lookup.stats.SharedKeys++
+				lookup.stats.SharedBytes += key.size
+				shared = true
+				break
+			}
+		}
+		if !shared {
+			bytes := key.buffer()
+			entry := paletteLookupEntry{start: len(lookup.buffer), size: len(bytes)}
+			lookup.entries[key] = entry
+			lookup.buffer = append(lookup.buffer, bytes...)
+			lookup.stats.AppendedKeys++
+			lookup.stats.AppendedBytes += len(bytes)
+			appended = append(appended, entry)
+		}
+	}
+	lookup.measureRemainder(appended)
+	return lookup
+}
+
+func (gen *PaletteLookupGenerator) sortedKeys() []tilePaletteKey {
+	keys := make([]tilePaletteKey, 0, len(gen.keyUses))
+	for key := range gen.keyUses {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(a, b int) bool { return keys[a].usedColors.Less(keys[b].usedColors) })
+	return keys
+}
+
+// seededRank returns a pseudo-random, yet reproducible, rank of given value.
+// The mix function is that of the SplitMix64 generator.
+func seededRank(seed uint64, value uint64) uint64 {
+	z := seed + (value+1)*0x9E3779B97F4A7C15
+	z = (z ^ (z >> 30)) * 0xBF58476D1CE4E5B9
+	z = (z ^ (z >> 27)) * 0x94D049BB133111EB
+	return z ^ (z >> 31)
+}
+
 // Add registers a further delta to the generator.
-func (gen *PaletteLookupGenerator) Add(delta tileDelta) {
+func (gen *PaletteLookupGenerator) Add(delta TileDelta) {
 	key := tilePaletteKeyFrom(delta[:])
 	if key.size > 2 {
 		if gen.keyUses == nil {
@@ -0,0 +1,191 @@
+package compression_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/inkyblackness/hacked/ss1/content/movie/internal/compression"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaletteLookupStatsReportSharedAndAppendedPalettes(t *testing.T) {
+	tiles := []compression.TileDelta{
+		{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+		{21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31, 32, 33, 34, 35, 36},
+		{1, 2, 3, 1, 2, 3, 1, 2, 3, 1, 2, 3, 1, 2, 3, 1},
+		{21, 22, 23, 24, 25, 21, 22, 23, 24, 25, 21, 22, 23, 24, 25, 21},
+		{40, 41, 42, 40, 41, 42, 40, 41, 42, 40, 41, 42, 40, 41, 42, 40},
+	}
+	var gen compression.PaletteLookupGenerator
+	for _, tile := range tiles {
+		gen.Add(tile)
+	}
+	lookup := gen.Generate()
+	require.Nil(t, lookup.Verify(tiles), "no error expected verifying")
+
+	assert.Equal(t, compression.PaletteLookupStats{
+		SharedKeys:     2,
+		SharedBytes:    3 + 5,
+		AppendedKeys:   3,
+		AppendedBytes:  16 + 16 + 3,
+		RemainderStart: 16 + 16,
+		RemainderBytes: 3,
+	}, lookup.Stats())
+	assert.Equal(t, lookup.Stats().AppendedBytes, len(lookup.Buffer()))
+}
+
+func TestPaletteLookupStatsOfDirectModeOnlyContainAppendedPalettes(t *testing.T) {
+	gen := compression.PaletteLookupGenerator{Mode: compression.PaletteLookupDirect}
+	gen.Add(compression.TileDelta{1, 2, 3, 4, 1, 2, 3, 4, 1, 2, 3, 4, 1, 2, 3, 4})
+	gen.Add(compression.TileDelta{1, 2, 3, 1, 2, 3, 1, 2, 3, 1, 2, 3, 1, 2, 3, 1})
+	lookup := gen.Generate()
+
+	assert.Equal(t, compression.PaletteLookupStats{
+		AppendedKeys:   2,
+		AppendedBytes:  7,
+		RemainderStart: 0,
+		RemainderBytes: 7,
+	}, lookup.Stats())
+}
+
+func TestPaletteLookupStatsReportNoRemainderIfLastPaletteIsShared(t *testing.T) {
+	tiles := []compression.TileDelta{
+		{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+		{21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31, 32, 33, 34, 35, 36},
+		{21, 22, 23, 21, 22, 23, 21, 22, 23, 21, 22, 23, 21, 22, 23, 21},
+	}
+	var gen compression.PaletteLookupGenerator
+	for _, tile := range tiles {
+		gen.Add(tile)
+	}
+	lookup := gen.Generate()
+	require.Nil(t, lookup.Verify(tiles), "no error expected verifying")
+
+	stats := lookup.Stats()
+	assert.Equal(t, 2, stats.AppendedKeys)
+	assert.Equal(t, 1, stats.SharedKeys)
+	assert.Equal(t, len(lookup.Buffer()), stats.RemainderStart, "remainder should start at end of buffer")
+	assert.Equal(t, 0, stats.RemainderBytes)
+}
+
+func TestPaletteLookupGreedyModeSharesSectionsOfStoredPalettes(t *testing.T) {
+	tiles := []compression.TileDelta{
+		{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+		{2, 3, 4, 2, 3, 4, 2, 3, 4, 2, 3, 4, 2, 3, 4, 2},
+		{1, 5, 9, 1, 5, 9, 1, 5, 9, 1, 5, 9, 1, 5, 9, 1},
+	}
+	gen := compression.PaletteLookupGenerator{Mode: compression.PaletteLookupGreedy}
+	for _, tile := range tiles {
+		gen.Add(tile)
+	}
+	lookup := gen.Generate()
+	require.Nil(t, lookup.Verify(tiles), "no error expected verifying")
+
+	assert.Equal(t, compression.PaletteLookupStats{
+		SharedKeys:     1,
+		SharedBytes:    3,
+		AppendedKeys:   2,
+		AppendedBytes:  16 + 3,
+		RemainderStart: 16,
+		RemainderBytes: 3,
+	}, lookup.Stats())
+	index, _, _ := lookup.Lookup(tiles[1])
+	assert.Equal(t, 1, index, "section of first palette expected")
+}
+
+func TestPaletteLookupModesProduceVerifiableLookups(t *testing.T) {
+	tt := []struct {
+		name string
+		mode compression.PaletteLookupMode
+	}{
+		{name: "compact", mode: compression.PaletteLookupCompact},
+		{name: "direct", mode: compression.PaletteLookupDirect},
+		{name: "greedy", mode: compression.PaletteLookupGreedy},
+		{name: "automatic", mode: compression.PaletteLookupAutomatic},
+	}
+
+	for _, tc := range tt {
+		td := tc
+		t.Run(td.name, func(t *testing.T) {
+			tiles := randomTileDeltas(rand.New(rand.NewSource(1)), 200)
+			gen := compression.PaletteLookupGenerator{Mode: td.mode}
+			for _, tile := range tiles {
+				gen.Add(tile)
+			}
+			lookup := gen.Generate()
+			assert.Nil(t, lookup.Verify(tiles), "no error expected")
+			stats := lookup.Stats()
+			assert.Equal(t, stats.AppendedBytes, len(lookup.Buffer()))
+			assert.Equal(t, len(lookup.Buffer()), stats.RemainderStart+stats.RemainderBytes)
+		})
+	}
+}
+
+// BenchmarkPaletteLookupGenerator compares the modes on the tiles of the scenes of the test data.
+// The deltas are those the encoder would register, based on the decoded frames of each scene.
+func BenchmarkPaletteLookupGenerator(b *testing.B) {
+	for _, scene := range []string{"scene00", "scene01", "scene02"} {
+		width, height, frames := decodeTestScene(b, "deth", scene)
+		previous := make([]byte, width*height)
+		var tiles []compression.TileDelta
+		for _, frame := range frames {
+			changed, err := compression.ChangedTiles(previous, frame, width, height)
+			require.Nil(b, err, "no error expected")
+			for _, tile := range changed {
+				tiles = append(tiles, tile.Delta)
+			}
+			previous = frame
+		}
+		modes := []struct {
+			name string
+			mode compression.PaletteLookupMode
+		}{
+			{name: "compact", mode: compression.PaletteLookupCompact},
+			{name: "greedy", mode: compression.PaletteLookupGreedy},
+		}
+		for _, entry := range modes {
+			mode := entry.mode
+			b.Run(fmt.Sprintf("%s-%s", scene, entry.name), func(b *testing.B) {
+				var size int
+				for i := 0; i < b.N; i++ {
+					gen := compression.PaletteLookupGenerator{Mode: mode}
+					for _, tile := range tiles {
+						gen.Add(tile)
+					}
+					lookup := gen.Generate()
+					size = len(lookup.Buffer())
+				}
+				b.Logf("lookup size for %d tiles: %d bytes", len(tiles), size)
+			})
+		}
+	}
+}
+
+func TestPaletteLookupCompactModeIsReproducibleForSameSeed(t *testing.T) {
+	tiles := randomTileDeltas(rand.New(rand.NewSource(2)), 300)
+	generate := func(seed uint64) compression.PaletteLookup {
+		gen := compression.PaletteLookupGenerator{Mode: compression.PaletteLookupCompact, Seed: seed}
+		for _, tile := range tiles {
+			gen.Add(tile)
+		}
+		return gen.Generate()
+	}
+
+	for _, seed := range []uint64{0, 1234} {
+		first := generate(seed)
+		require.Nil(t, first.Verify(tiles), "no error expected verifying for seed %d", seed)
+		for attempt := 0; attempt < 5; attempt++ {
+			again := generate(seed)
+			assert.Equal(t, first.Buffer(), again.Buffer(), "buffer differs for seed %d", seed)
+			for _, tile := range tiles {
+				index, _, mask := first.Lookup(tile)
+				againIndex, _, againMask := again.Lookup(tile)
+				assert.Equal(t, index, againIndex, "index differs for seed %d", seed)
+				assert.Equal(t, mask, againMask, "mask differs for seed %d", seed)
+			}
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package compression
+
+import "fmt"
+
+// Verify checks that every given tile can be reproduced from the lookup.
+// For each tile, the palette and mask returned by Lookup() are decoded the same way a decoder
+// would, using the mask bit size of the matching color operation.
+// The first tile that can not be reproduced is reported as error, together with its colors.
+func (lookup *PaletteLookup) Verify(tiles []TileDelta) error {
+	for tileIndex, tile := range tiles {
+		index, pal, mask := lookup.Lookup(tile)
+		reconstructed, err := tileFromLookup(pal, mask)
+		if err == nil && reconstructed != tile {
+			err = fmt.Errorf("reconstructed tile differs: %v", reconstructed)
+		}
+		if err != nil {
+			key := tilePaletteKeyFrom(tile[:])
+			return fmt.Errorf("tile %d %v (colors %v) failed verification at lookup index %d with palette %v: %v",
+				tileIndex, tile, key.buffer(), index, pal, err)
+		}
+	}
+	return nil
+}
+
+func tileFromLookup(pal []byte, mask uint64) (TileDelta, error) {
+	var tile TileDelta
+	bitSize := maskBitSizeFor(len(pal))
+	if bitSize == 0 {
+		return tile, fmt.Errorf("unsupported palette size %d", len(pal))
+	}
+	indexMask := uint64(1)<<bitSize - 1
+	for pixelIndex := 0; pixelIndex < PixelPerTile; pixelIndex++ {
+		palIndex := int(mask & indexMask)
+		mask >>= bitSize
+		if palIndex >= len(pal) {
+			return tile, fmt.Errorf("pixel %d refers to palette index %d beyond palette size %d", pixelIndex, palIndex, len(pal))
+		}
+		tile[pixelIndex] = pal[palIndex]
+	}
+	if mask != 0 {
+		return tile, fmt.Errorf("mask has excess bits 0x%X", mask)
+	}
+	return tile, nil
+}
+
+// maskBitSizeFor returns the amount of bits per pixel a tile color operation uses for given palette size.
+// Zero is returned for unsupported sizes.
+func maskBitSizeFor(palSize int) uint {
+	switch {
+	case palSize < 1:
+		return 0
+	case palSize <= 2:
+		return 1
+	case palSize <= 4:
+		return 2
+	case palSize <= 8:
+		return 3
+	case palSize <= 16:
+		return 4
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,55 @@
+package compression_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/inkyblackness/hacked/ss1/content/movie/internal/compression"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func randomTileDeltas(r *rand.Rand, count int) []compression.TileDelta {
+	tiles := make([]compression.TileDelta, count)
+	for index := range tiles {
+		colorCount := 1 + r.Intn(compression.PixelPerTile)
+		colors := make([]byte, colorCount)
+		for colorIndex := range colors {
+			colors[colorIndex] = byte(r.Intn(256))
+		}
+		for pixel := range tiles[index] {
+			tiles[index][pixel] = colors[r.Intn(colorCount)]
+		}
+	}
+	return tiles
+}
+
+func TestPaletteLookupVerifiesGeneratedTiles(t *testing.T) {
+	r := rand.New(rand.NewSource(0))
+	tiles := randomTileDeltas(r, 500)
+	var gen compression.PaletteLookupGenerator
+	for _, tile := range tiles {
+		gen.Add(tile)
+	}
+	lookup := gen.Generate()
+
+	err := lookup.Verify(tiles)
+	assert.Nil(t, err, "no error expected")
+}
+
+func TestPaletteLookupVerificationReportsCorruptedLookup(t *testing.T) {
+	tile := compression.TileDelta{1, 2, 3, 4, 1, 2, 3, 4, 1, 2, 3, 4, 1, 2, 3, 4}
+	var gen compression.PaletteLookupGenerator
+	gen.Add(tile)
+	lookup := gen.Generate()
+	require.Nil(t, lookup.Verify([]compression.TileDelta{tile}), "no error expected before corruption")
+
+	buffer := lookup.Buffer()
+	for index := range buffer {
+		buffer[index] = 0xFF
+	}
+	err := lookup.Verify([]compression.TileDelta{tile})
+	require.NotNil(t, err, "error expected")
+	assert.Contains(t, err.Error(), "[1 2 3 4]", "colors of tile expected in error")
+}
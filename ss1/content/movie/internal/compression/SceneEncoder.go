@@ -11,10 +11,8 @@ type EncodedFrame struct {
 	Maskstream []byte
 }
 
-type tileDelta [PixelPerTile]byte
-
 type frameDelta struct {
-	tiles []tileDelta
+	tiles []TileDelta
 }
 
 // SceneEncoder encodes an entire scene of bitmaps sharing the same palette.
@@ -24,8 +22,13 @@ type SceneEncoder struct {
 	lineStride int
 	tileStride int
 
-	lastFrame []byte
-	deltas    []frameDelta
+	lastFrame    []byte
+	hasLastFrame bool
+	deltas       []frameDelta
+
+	lookupMode   PaletteLookupMode
+	seed         uint64
+	verifyLookup bool
 }
 
 // NewSceneEncoder returns a new instance.
@@ -34,19 +37,39 @@ func NewSceneEncoder(width, height int) *SceneEncoder {
 		hTiles:     width / TileSideLength,
 		vTiles:     height / TileSideLength,
 		lineStride: width,
+		lookupMode: PaletteLookupCompact,
 	}
 	e.tileStride = e.lineStride * TileSideLength
 	e.lastFrame = make([]byte, e.vTiles*TileSideLength*e.lineStride)
 	return e
 }
 
+// SetPaletteLookupMode determines how the palette lookup is generated. The default is PaletteLookupCompact.
+// PaletteLookupDirect encodes faster, yet results in a larger palette lookup; See the modes for details.
+func (e *SceneEncoder) SetPaletteLookupMode(mode PaletteLookupMode) {
+	e.lookupMode = mode
+}
+
+// SetSeed sets the basis for choices between otherwise equivalent options. The default is zero.
+// Encoding the same frames with the same seed always produces the same result.
+func (e *SceneEncoder) SetSeed(seed uint64) {
+	e.seed = seed
+}
+
+// SetLookupVerification enables or disables the self-check of the generated palette lookup.
+// If enabled, Encode() fails if any tile can not be reproduced from the lookup.
+// This is meant for debugging, as it verifies every tile of the scene.
+func (e *SceneEncoder) SetLookupVerification(enabled bool) {
+	e.verifyLookup = enabled
+}
+
 // AddFrame registers a further frame to the scene.
 func (e *SceneEncoder) AddFrame(frame []byte) error {
 	if len(frame) != len(e.lastFrame) {
 		return errors.New("invalid frame size")
 	}
 	var delta frameDelta
-	isFirstFrame := len(e.deltas) == 0
+	isFirstFrame := !e.hasLastFrame
 	vStart := 0
 	for vTile := 0; vTile < e.vTiles; vTile++ {
 		tileStart := vStart
@@ -58,20 +81,24 @@ func (e *SceneEncoder) AddFrame(frame []byte) error {
 	}
 	e.deltas = append(e.deltas, delta)
 	copy(e.lastFrame, frame)
+	e.hasLastFrame = true
 	return nil
 }
 
-func (e *SceneEncoder) deltaTile(isFirstFrame bool, offset int, frame []byte) tileDelta {
-	var delta tileDelta
-	for y := 0; y < TileSideLength; y++ {
-		start := offset + (y * e.lineStride)
-		for x := 0; x < TileSideLength; x++ {
-			pixel := frame[start+x]
-			if isFirstFrame || (pixel != e.lastFrame[start+x]) {
-				delta[y*TileSideLength+x] = pixel
-			}
-		}
-	}
+// FrameCount returns the number of frames registered since creation or the last call to ClearFrames().
+func (e *SceneEncoder) FrameCount() int {
+	return len(e.deltas)
+}
+
+// ClearFrames drops all registered frames, typically after they were encoded.
+// The last added frame is kept as reference, so that further frames are encoded as changes to it.
+// This allows encoding a long sequence in several scenes, without keeping all frames in memory.
+func (e *SceneEncoder) ClearFrames() {
+	e.deltas = nil
+}
+
+func (e *SceneEncoder) deltaTile(isFirstFrame bool, offset int, frame []byte) TileDelta {
+	delta, _ := tileDeltaAt(isFirstFrame, offset, e.lineStride, e.lastFrame, frame)
 	return delta
 }
 
@@ -80,6 +107,14 @@ func (e *SceneEncoder) Encode() (words []ControlWord, paletteLookupBuffer []byte
 	var wordSequencer ControlWordSequencer
 	tileColorOpsPerFrame := make([][]TileColorOp, len(e.deltas))
 	paletteLookup := e.createPaletteLookup()
+	if e.verifyLookup {
+		for _, delta := range e.deltas {
+			err = paletteLookup.Verify(delta.tiles)
+			if err != nil {
+				return
+			}
+		}
+	}
 
 	paletteLookupBuffer = paletteLookup.Buffer()
 	if len(paletteLookupBuffer) > 0x1FFFF {
@@ -167,7 +202,7 @@ func (e *SceneEncoder) Encode() (words []ControlWord, paletteLookupBuffer []byte
 }
 
 func (e *SceneEncoder) createPaletteLookup() PaletteLookup {
-	var paletteLookupGenerator PaletteLookupGenerator
+	paletteLookupGenerator := PaletteLookupGenerator{Mode: e.lookupMode, Seed: e.seed}
 	for _, delta := range e.deltas {
 		for _, tile := range delta.tiles {
 			paletteLookupGenerator.Add(tile)
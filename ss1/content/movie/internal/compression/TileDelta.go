@@ -0,0 +1,54 @@
+package compression
+
+import "fmt"
+
+// TileDelta contains the pixel of one frame tile that changed compared to the previous frame.
+// Unchanged pixel have the value zero, which is the transparent color. As a result, a pixel that
+// changes to color zero is not considered a change.
+type TileDelta [PixelPerTile]byte
+
+// ChangedTile describes a tile that differs between two frames.
+type ChangedTile struct {
+	// HTile is the horizontal position of the tile, in tiles.
+	HTile int
+	// VTile is the vertical position of the tile, in tiles.
+	VTile int
+	// Delta contains the changed pixel.
+	Delta TileDelta
+}
+
+// ChangedTiles compares two consecutive, paletted frames of given size and returns all tiles that differ.
+// The deltas of the tiles are the same as those used for encoding, and can be registered at a PaletteLookupGenerator.
+// Only complete tiles are considered, should the dimensions not be a multiple of TileSideLength.
+func ChangedTiles(previous, next []byte, width, height int) ([]ChangedTile, error) {
+	if (width < 0) || (height < 0) || (len(previous) != width*height) || (len(next) != width*height) {
+		return nil, fmt.Errorf("frames must have a size of %dx%d", width, height)
+	}
+	var changed []ChangedTile
+	hTiles := width / TileSideLength
+	vTiles := height / TileSideLength
+	for vTile := 0; vTile < vTiles; vTile++ {
+		for hTile := 0; hTile < hTiles; hTile++ {
+			offset := vTile*TileSideLength*width + hTile*TileSideLength
+			delta, hasChanges := tileDeltaAt(false, offset, width, previous, next)
+			if hasChanges {
+				changed = append(changed, ChangedTile{HTile: hTile, VTile: vTile, Delta: delta})
+			}
+		}
+	}
+	return changed, nil
+}
+
+func tileDeltaAt(isFirstFrame bool, offset int, lineStride int, previous, next []byte) (delta TileDelta, hasChanges bool) {
+	for y := 0; y < TileSideLength; y++ {
+		start := offset + (y * lineStride)
+		for x := 0; x < TileSideLength; x++ {
+			pixel := next[start+x]
+			if isFirstFrame || (pixel != previous[start+x]) {
+				delta[y*TileSideLength+x] = pixel
+				hasChanges = hasChanges || (pixel != 0x00)
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,43 @@
+package compression_test
+
+import (
+	"testing"
+
+	"github.com/inkyblackness/hacked/ss1/content/movie/internal/compression"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangedTilesReturnsSingleChangedTile(t *testing.T) {
+	width, height := 16, 8
+	previous := make([]byte, width*height)
+	for index := range previous {
+		previous[index] = 0x10
+	}
+	next := make([]byte, len(previous))
+	copy(next, previous)
+	next[5*width+9] = 0x20
+	next[6*width+10] = 0x30
+
+	changed, err := compression.ChangedTiles(previous, next, width, height)
+	require.Nil(t, err, "no error expected")
+
+	var expectedDelta compression.TileDelta
+	expectedDelta[1*compression.TileSideLength+1] = 0x20
+	expectedDelta[2*compression.TileSideLength+2] = 0x30
+	assert.Equal(t, []compression.ChangedTile{{HTile: 2, VTile: 1, Delta: expectedDelta}}, changed)
+}
+
+func TestChangedTilesReturnsNothingForIdenticalFrames(t *testing.T) {
+	frame := []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+
+	changed, err := compression.ChangedTiles(frame, frame, 4, 4)
+	require.Nil(t, err, "no error expected")
+	assert.Empty(t, changed)
+}
+
+func TestChangedTilesReturnsErrorForMismatchingFrames(t *testing.T) {
+	_, err := compression.ChangedTiles(make([]byte, 16), make([]byte, 12), 4, 4)
+	assert.Error(t, err, "error expected")
+}
@@ -1,9 +1,9 @@
 package compression
 
-import "math/bits"
+import "github.com/inkyblackness/hacked/ss1/content/bitmap"
 
 type tilePaletteKey struct {
-	usedColors [4]uint64
+	usedColors bitmap.PaletteSet
 	size       int
 }
 
@@ -16,60 +16,68 @@ func tilePaletteKeyFrom(colors []byte) tilePaletteKey {
 }
 
 func (key *tilePaletteKey) buffer() []byte {
-	result := make([]byte, 0, key.size)
-	for i := 0; i < 256; i++ {
-		if key.hasColor(byte(i)) {
-			result = append(result, byte(i))
-		}
-	}
-	return result
+	return key.usedColors.Slice()
 }
 
 func (key *tilePaletteKey) joinedBuffer(source []byte) []byte {
 	result := make([]byte, 0, key.size)
-	var addedColors tilePaletteKey
+	var addedColors bitmap.PaletteSet
 	for _, color := range source {
-		addedColors.useColor(color)
+		addedColors.Add(color)
 		result = append(result, color)
 	}
-	for color := 0; color < 256; color++ {
-		if key.hasColor(byte(color)) && !addedColors.hasColor(byte(color)) {
-			result = append(result, byte(color))
-		}
-	}
-	return result
+	return append(result, key.usedColors.Subtract(addedColors).Slice()...)
 }
 
 func (key *tilePaletteKey) useColor(index byte) {
 	if !key.hasColor(index) {
-		key.usedColors[index/64] |= 1 << uint(index%64)
+		key.usedColors.Add(index)
 		key.size++
 	}
 }
 
 func (key *tilePaletteKey) hasColor(index byte) bool {
-	return (key.usedColors[index/64] & (1 << uint(index%64))) != 0
+	return key.usedColors.Has(index)
 }
 
 func (key *tilePaletteKey) contains(other *tilePaletteKey) bool {
-	return ((^key.usedColors[0] & other.usedColors[0]) == 0) &&
-		((^key.usedColors[1] & other.usedColors[1]) == 0) &&
-		((^key.usedColors[2] & other.usedColors[2]) == 0) &&
-		((^key.usedColors[3] & other.usedColors[3]) == 0)
+	return key.usedColors.Contains(other.usedColors)
 }
 
-func (key *tilePaletteKey) without(other *tilePaletteKey) tilePaletteKey {
-	var result tilePaletteKey
-	for i := 0; i < 4; i++ {
-		result.usedColors[i] = key.usedColors[i] & ^other.usedColors[i]
-		result.size += bits.OnesCount64(result.usedColors[i])
+// sectionIn returns the smallest section of source that contains all colors of the key.
+// The returned boolean is false if source does not contain all colors.
+func (key *tilePaletteKey) sectionIn(source []byte) (paletteLookupEntry, bool) {
+	first, last := -1, -1
+	var found bitmap.PaletteSet
+	for index, color := range source {
+		if key.hasColor(color) {
+			if first < 0 {
+				first = index
+			}
+			last = index
+			found.Add(color)
+		}
+	}
+	if found.Size() != key.size {
+		return paletteLookupEntry{}, false
 	}
+	return paletteLookupEntry{start: first, size: last - first + 1}, true
+}
+
+func (key *tilePaletteKey) without(other *tilePaletteKey) tilePaletteKey {
+	result := tilePaletteKey{usedColors: key.usedColors.Subtract(other.usedColors)}
+	result.size = result.usedColors.Size()
 	return result
 }
 
+// lessThan is the order in which the compact generator processes keys of equal size.
+// A key is less if any word of its colors is less than the same word of the other key.
+// This is not a strict ordering, yet it determines the generated lookup and is therefore kept.
 func (key *tilePaletteKey) lessThan(other *tilePaletteKey) bool {
-	for i := 0; i < 4; i++ {
-		if key.usedColors[i] < other.usedColors[i] {
+	keyWords := key.usedColors.Words()
+	otherWords := other.usedColors.Words()
+	for i := range keyWords {
+		if keyWords[i] < otherWords[i] {
 			return true
 		}
 	}
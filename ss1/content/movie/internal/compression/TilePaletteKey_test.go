@@ -90,3 +90,27 @@ func TestTilePaletteKeyWithout(t *testing.T) {
 		})
 	}
 }
+
+func TestTilePaletteKeyLessThan(t *testing.T) {
+	tt := []struct {
+		key      []byte
+		other    []byte
+		expected bool
+	}{
+		{key: []byte{1}, other: []byte{2}, expected: true},
+		{key: []byte{2}, other: []byte{1}, expected: false},
+		{key: []byte{1}, other: []byte{1}, expected: false},
+		{key: []byte{0, 64}, other: []byte{1}, expected: true},
+		{key: []byte{1}, other: []byte{0, 64}, expected: true},
+		{key: []byte{200}, other: []byte{0, 1, 2}, expected: true},
+	}
+
+	for index, tc := range tt {
+		td := tc
+		t.Run(fmt.Sprintf("%v", index), func(t *testing.T) {
+			key := tilePaletteKeyFrom(td.key)
+			other := tilePaletteKeyFrom(td.other)
+			assert.Equal(t, td.expected, key.lessThan(&other), "Mismatch for %v < %v", td.key, td.other)
+		})
+	}
+}
@@ -24,6 +24,9 @@ func UnpackControlWords(data []byte) (words []ControlWord, err error) {
 		return nil, ErrFormat
 	}
 	wordCount := int(controlBytes / bytesPerControlWord)
+	if wordCount > ((len(data)-4)/4)*0xFF {
+		return nil, ErrFormat
+	}
 	unpacked := 0
 
 	words = make([]ControlWord, wordCount)
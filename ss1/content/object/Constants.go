@@ -3,6 +3,8 @@ package object
 const (
 	// ClassCount describes how many object classes there are.
 	ClassCount = 15
+	// MaxTypesPerClass is the amount of object types a class can have, over all its subclasses.
+	MaxTypesPerClass = 256
 
 	propertiesFileVersion uint32 = 0x0000002D
 )
@@ -114,6 +114,21 @@ func (table PropertiesTable) TriplesInClass(class Class) []Triple {
 	return triples
 }
 
+// Triples returns all triples that are available in the table, in sequential order.
+func (table PropertiesTable) Triples() []Triple {
+	var triples []Triple
+	table.Iterate(func(triple Triple, _ *Properties) bool {
+		triples = append(triples, triple)
+		return true
+	})
+	return triples
+}
+
+// Defines returns true if the table has properties for the given triple.
+func (table PropertiesTable) Defines(triple Triple) bool {
+	return table.TripleIndex(triple) >= 0
+}
+
 // TripleIndex returns the linear index of the given index.
 func (table PropertiesTable) TripleIndex(triple Triple) int {
 	if int(triple.Class) >= len(table) {
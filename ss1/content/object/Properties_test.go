@@ -56,3 +56,35 @@ func TestPropertiesEncoding(t *testing.T) {
 	result := buf.Bytes()
 	assert.Equal(t, 17951, len(result)) // as taken from original CD
 }
+
+func TestPropertiesTriplesListsAllTriplesInOrder(t *testing.T) {
+	table := object.StandardPropertiesTable()
+
+	triples := table.Triples()
+
+	assert.Equal(t, 476, len(triples), "wrong number of triples")
+	assert.Equal(t, object.TripleFrom(0, 0, 0), triples[0], "wrong first triple")
+	assert.Equal(t, object.TripleFrom(14, 4, 1), triples[len(triples)-1], "wrong last triple")
+	for index, triple := range triples {
+		assert.Equal(t, index, table.TripleIndex(triple), "wrong index for "+triple.String())
+	}
+}
+
+func TestPropertiesDefines(t *testing.T) {
+	tt := []struct {
+		triple  object.Triple
+		defined bool
+	}{
+		{object.TripleFrom(0, 0, 0), true},
+		{object.TripleFrom(14, 4, 1), true},
+		{object.TripleFrom(10, 3, 4), true},
+		{object.TripleFrom(20, 0, 2), false},
+		{object.TripleFrom(1, 40, 0), false},
+		{object.TripleFrom(1, 0, 20), false},
+	}
+	table := object.StandardPropertiesTable()
+
+	for _, tc := range tt {
+		assert.Equal(t, tc.defined, table.Defines(tc.triple), "wrong result for "+tc.triple.String())
+	}
+}
@@ -0,0 +1,95 @@
+package object
+
+// PropertyDefaults provides the initial properties of new object types, per class.
+//
+// The common properties are taken as they are. The generic and specific data of an entry are
+// copied as a prefix into the data of the new type; Remaining bytes stay zero.
+// Classes without an entry result in properties with all values zero.
+type PropertyDefaults map[Class]Properties
+
+// StandardPropertyDefaults returns defaults that make new types of all standard classes usable
+// without further editing: They can be seen, collided with, and take damage before being destroyed.
+func StandardPropertyDefaults() PropertyDefaults {
+	item := func(renderType RenderType, hitpoints int16) Properties {
+		return Properties{Common: CommonProperties{
+			Mass:         10,
+			Hitpoints:    hitpoints,
+			RenderType:   renderType,
+			PhysicsModel: PhysicsModelRegular,
+			Hardness:     10,
+			PhysicsXR:    16,
+			PhysicsZ:     16,
+			Defense:      10,
+		}}
+	}
+	defaults := PropertyDefaults{
+		ClassGun:        item(RenderTypeBitmap, 10),
+		ClassAmmo:       item(RenderTypeBitmap, 5),
+		ClassPhysics:    item(RenderTypeBitmap, 1),
+		ClassGrenade:    item(RenderTypeBitmap, 5),
+		ClassDrug:       item(RenderTypeBitmap, 5),
+		ClassHardware:   item(RenderTypeBitmap, 10),
+		ClassSoftware:   item(RenderTypeBitmap, 5),
+		ClassBigStuff:   item(RenderTypeBitmap, 50),
+		ClassSmallStuff: item(RenderTypeBitmap, 10),
+		ClassFixture:    item(RenderTypeBitmap, 100),
+		ClassDoor:       item(RenderTypeTextPoly, 100),
+		ClassAnimating:  item(RenderTypeBitmap, 10),
+		ClassTrap:       item(RenderTypeNoObject, 1),
+		ClassContainer:  item(RenderTypeTextPoly, 50),
+		ClassCritter:    item(RenderTypeCritter, 50),
+	}
+	physics := defaults[ClassPhysics]
+	physics.Common.PhysicsModel = PhysicsModelInsubstantial
+	defaults[ClassPhysics] = physics
+	trap := defaults[ClassTrap]
+	trap.Common.PhysicsModel = PhysicsModelInsubstantial
+	trap.Common.Toughness = ToughnessNoDamage
+	trap.Common.Defense = DefenseNoCriticals
+	defaults[ClassTrap] = trap
+	critter := defaults[ClassCritter]
+	critter.Common.Mass = 800
+	critter.Common.PhysicsXR = 32
+	critter.Common.PhysicsZ = 48
+	defaults[ClassCritter] = critter
+	return defaults
+}
+
+// With returns a copy of the defaults in which the entry of given class is replaced.
+// This allows callers to override the defaults without modifying the original.
+func (defaults PropertyDefaults) With(class Class, prop Properties) PropertyDefaults {
+	result := make(PropertyDefaults, len(defaults)+1)
+	for key, value := range defaults {
+		result[key] = value
+	}
+	result[class] = prop.Clone()
+	return result
+}
+
+// PropertiesFor returns new properties for a type of given class, with data of given sizes.
+func (defaults PropertyDefaults) PropertiesFor(class Class, genericDataSize, specificDataSize int) Properties {
+	base := defaults[class]
+	prop := Properties{
+		Common:   base.Common,
+		Generic:  make([]byte, genericDataSize),
+		Specific: make([]byte, specificDataSize),
+	}
+	copy(prop.Generic, base.Generic)
+	copy(prop.Specific, base.Specific)
+	return prop
+}
+
+// NewPropertiesTableWithDefaults returns a new instance based on given descriptors,
+// with all types initialized from the given defaults.
+func NewPropertiesTableWithDefaults(desc Descriptors, defaults PropertyDefaults) PropertiesTable {
+	table := NewPropertiesTable(desc)
+	for class, classEntry := range table {
+		for subclass, subclassEntry := range classEntry {
+			for objType := range subclassEntry {
+				subclassEntry[objType] = defaults.PropertiesFor(Class(class),
+					desc[class].GenericDataSize, desc[class].Subclasses[subclass].SpecificDataSize)
+			}
+		}
+	}
+	return table
+}
@@ -0,0 +1,61 @@
+package object_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/content/object"
+)
+
+func TestStandardPropertyDefaultsCoverAllClasses(t *testing.T) {
+	defaults := object.StandardPropertyDefaults()
+	for class := object.Class(0); class < object.ClassCount; class++ {
+		prop := defaults.PropertiesFor(class, 0, 0)
+		assert.True(t, prop.Common.Hitpoints > 0, "class %v should have hitpoints", class)
+		assert.NotEqual(t, object.RenderTypeUnknown, prop.Common.RenderType, "class %v should have a render type", class)
+	}
+}
+
+func TestPropertyDefaultsPropertiesForSizesData(t *testing.T) {
+	defaults := object.PropertyDefaults{
+		object.ClassGun: {Generic: []byte{0x01, 0x02}, Specific: []byte{0x03, 0x04, 0x05}},
+	}
+
+	prop := defaults.PropertiesFor(object.ClassGun, 3, 2)
+
+	assert.Equal(t, []byte{0x01, 0x02, 0x00}, prop.Generic)
+	assert.Equal(t, []byte{0x03, 0x04}, prop.Specific)
+}
+
+func TestPropertyDefaultsWithOverridesOnlyCopy(t *testing.T) {
+	original := object.StandardPropertyDefaults()
+	override := object.Properties{Common: object.CommonProperties{Hitpoints: 1234}}
+
+	modified := original.With(object.ClassCritter, override)
+
+	assert.Equal(t, int16(1234), modified.PropertiesFor(object.ClassCritter, 0, 0).Common.Hitpoints)
+	assert.NotEqual(t, int16(1234), original.PropertiesFor(object.ClassCritter, 0, 0).Common.Hitpoints)
+}
+
+func TestNewPropertiesTableWithDefaultsInitializesAllTypes(t *testing.T) {
+	desc := object.StandardDescriptors()
+	table := object.NewPropertiesTableWithDefaults(desc, object.StandardPropertyDefaults())
+
+	prop, err := table.ForObject(object.TripleFrom(int(object.ClassCritter), 0, 0))
+	require.Nil(t, err)
+	assert.True(t, prop.Common.Hitpoints > 0)
+	assert.Equal(t, desc[object.ClassCritter].Subclasses[0].SpecificDataSize, len(prop.Specific))
+}
+
+func TestStandardPropertiesTableIsInitializedWithDefaults(t *testing.T) {
+	table := object.StandardPropertiesTable()
+	defaults := object.StandardPropertyDefaults()
+
+	for class := object.Class(0); class < object.ClassCount; class++ {
+		prop, err := table.ForObject(object.TripleFrom(int(class), 0, 0))
+		require.Nil(t, err)
+		assert.Equal(t, defaults.PropertiesFor(class, 0, 0).Common, prop.Common, "wrong common properties for class %v", class)
+	}
+}
@@ -2,8 +2,9 @@ package object
 
 // StandardPropertiesTable returns a properties table based on the standard
 // configuration of the existing objprop.dat file.
+// All types are initialized with the standard property defaults.
 func StandardPropertiesTable() PropertiesTable {
-	return NewPropertiesTable(StandardDescriptors())
+	return NewPropertiesTableWithDefaults(StandardDescriptors(), StandardPropertyDefaults())
 }
 
 // StandardDescriptors returns an array of class descriptors that represent the standard
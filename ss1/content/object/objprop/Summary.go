@@ -0,0 +1,178 @@
+package objprop
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/inkyblackness/hacked/ss1/content/interpreters"
+	"github.com/inkyblackness/hacked/ss1/content/object"
+)
+
+// Summary returns a human-readable description of the in-game behaviour of an object, derived from its properties.
+// The description is best-effort. Classes without a dedicated summary list the raw values of their
+// generic and specific properties instead.
+func Summary(triple object.Triple, properties object.Properties) string {
+	generic := GenericProperties(triple.Class, properties.Generic)
+	specific := SpecificProperties(triple, properties.Specific)
+	var parts []string
+	switch triple.Class {
+	case object.ClassGun:
+		parts = gunSummary(generic, specific)
+	case object.ClassAmmo:
+		parts = ammoSummary(generic)
+	case object.ClassGrenade:
+		parts = grenadeSummary(generic, specific)
+	case object.ClassCritter:
+		parts = critterSummary(properties.Common, generic)
+	default:
+		parts = append(rawSummary("", generic), rawSummary("", specific)...)
+	}
+	if len(parts) == 0 {
+		parts = []string{"(no properties)"}
+	}
+	return triple.Class.String() + ": " + strings.Join(parts, ", ")
+}
+
+func gunSummary(generic, specific *interpreters.Instance) []string {
+	var parts []string
+	if hasRefinement(specific, "BasicWeapon") {
+		parts = append(parts, weaponSummary(specific.Refined("BasicWeapon"))...)
+	}
+	parts = appendValue(parts, generic, "FireRate", "fire rate %d")
+	parts = appendValue(parts, specific, "Range", "range %d")
+	parts = appendValue(parts, specific, "ProjectileTravelSpeed", "projectile speed %d")
+	parts = appendValue(parts, specific, "MaxCharge", "max charge %d")
+	parts = appendValue(parts, specific, "EnergyUsage", "energy usage %d")
+	parts = append(parts, lowerNames(flagNames(specific, "Flags"))...)
+	return parts
+}
+
+func ammoSummary(generic *interpreters.Instance) []string {
+	var parts []string
+	parts = append(parts, weaponSummary(generic.Refined("BasicWeapon"))...)
+	parts = appendValue(parts, generic, "CartrigeSize", "%d rounds")
+	parts = appendValue(parts, generic, "Range", "range %d")
+	return parts
+}
+
+func grenadeSummary(generic, specific *interpreters.Instance) []string {
+	var parts []string
+	parts = append(parts, weaponSummary(generic.Refined("BasicWeapon"))...)
+	parts = appendValue(parts, generic, "BlastDamage", "%d blast damage")
+	parts = appendValue(parts, generic, "BlastRadius", "blast radius %d")
+	if hasKey(specific, "MinimumTime") {
+		minTime := specific.Get("MinimumTime")
+		maxTime := specific.Get("MaximumTime")
+		if minTime == maxTime {
+			parts = append(parts, fmt.Sprintf("fuse %d", minTime))
+		} else {
+			parts = append(parts, fmt.Sprintf("fuse %d-%d", minTime, maxTime))
+		}
+	}
+	parts = append(parts, lowerNames(flagNames(generic, "Flags"))...)
+	return parts
+}
+
+func critterSummary(common object.CommonProperties, generic *interpreters.Instance) []string {
+	parts := []string{fmt.Sprintf("%d hitpoints", common.Hitpoints)}
+	for _, attack := range []string{"PrimaryAttack", "SecondaryAttack"} {
+		info := generic.Refined(attack)
+		damage := info.Get("DamageModifier")
+		if damage == 0 {
+			continue
+		}
+		text := fmt.Sprintf("%s %d damage", strings.ToLower(strings.TrimSuffix(attack, "Attack")), damage)
+		if types := flagNames(info, "DamageType"); len(types) > 0 {
+			text += " (" + strings.Join(types, ", ") + ")"
+		}
+		parts = append(parts, text)
+	}
+	parts = appendValue(parts, generic, "Perception", "perception %d")
+	parts = append(parts, lowerNames(flagNames(generic, "Flags"))...)
+	return parts
+}
+
+func weaponSummary(basic *interpreters.Instance) []string {
+	var parts []string
+	if !hasKey(basic, "Damage") {
+		return parts
+	}
+	text := fmt.Sprintf("%d damage", basic.Get("Damage"))
+	if types := flagNames(basic, "DamageType"); len(types) > 0 {
+		text += " (" + strings.Join(types, ", ") + ")"
+	}
+	parts = append(parts, text)
+	parts = appendValue(parts, basic, "ArmorPenetration", "penetration %d")
+	return parts
+}
+
+func rawSummary(path string, inst *interpreters.Instance) []string {
+	var parts []string
+	for _, key := range inst.Keys() {
+		parts = append(parts, fmt.Sprintf("%s%s=%d", path, key, inst.Get(key)))
+	}
+	for _, key := range inst.ActiveRefinements() {
+		parts = append(parts, rawSummary(path+key+".", inst.Refined(key))...)
+	}
+	return parts
+}
+
+// appendValue adds the formatted value of given key, if the key exists and its value is not zero.
+func appendValue(parts []string, inst *interpreters.Instance, key string, format string) []string {
+	if !hasKey(inst, key) {
+		return parts
+	}
+	value := inst.Get(key)
+	if value == 0 {
+		return parts
+	}
+	return append(parts, fmt.Sprintf(format, value))
+}
+
+func hasKey(inst *interpreters.Instance, key string) bool {
+	for _, existing := range inst.Keys() {
+		if existing == key {
+			return true
+		}
+	}
+	return false
+}
+
+func hasRefinement(inst *interpreters.Instance, key string) bool {
+	for _, existing := range inst.ActiveRefinements() {
+		if existing == key {
+			return true
+		}
+	}
+	return false
+}
+
+// flagNames returns the names of all set bits of a bitfield, in order of their mask.
+func flagNames(inst *interpreters.Instance, key string) []string {
+	var names []string
+	value := inst.Get(key)
+	simplifier := interpreters.NewSimplifier(func(minValue, maxValue int64, formatter interpreters.RawValueFormatter) {})
+	simplifier.SetBitfieldHandler(func(values map[uint32]string) {
+		masks := make([]uint32, 0, len(values))
+		for mask := range values {
+			masks = append(masks, mask)
+		}
+		sort.Slice(masks, func(a, b int) bool { return masks[a] < masks[b] })
+		for _, mask := range masks {
+			if (value & mask) != 0 {
+				names = append(names, values[mask])
+			}
+		}
+	})
+	inst.Describe(key, simplifier)
+	return names
+}
+
+func lowerNames(names []string) []string {
+	result := make([]string, len(names))
+	for index, name := range names {
+		result[index] = strings.ToLower(name)
+	}
+	return result
+}
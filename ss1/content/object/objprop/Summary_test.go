@@ -0,0 +1,49 @@
+package objprop_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/inkyblackness/hacked/ss1/content/object"
+	"github.com/inkyblackness/hacked/ss1/content/object/objprop"
+)
+
+func TestSummaryOfTimedGrenade(t *testing.T) {
+	generic := make([]byte, 15)
+	generic[0] = 30   // damage
+	generic[3] = 0x01 // explosion
+	generic[9] = 5    // blast radius
+	generic[11] = 40  // blast damage
+	generic[13] = 0x04
+	specific := []byte{3, 3, 0}
+
+	summary := objprop.Summary(object.TripleFrom(3, 1, 0), object.Properties{Generic: generic, Specific: specific})
+
+	assert.Equal(t, "Grenade: 30 damage (Explosion), 40 blast damage, blast radius 5, fuse 3, explode by timer", summary)
+}
+
+func TestSummaryOfCritter(t *testing.T) {
+	generic := make([]byte, 75)
+	generic[0x0001] = 0x02 // energy
+	generic[0x0005] = 12   // damage modifier
+	generic[0x002E] = 0x01 // flying
+	properties := object.Properties{Generic: generic}
+	properties.Common.Hitpoints = 80
+
+	summary := objprop.Summary(object.TripleFrom(14, 1, 0), properties)
+
+	assert.Equal(t, "Critter: 80 hitpoints, primary 12 damage (Energy), flying", summary)
+}
+
+func TestSummaryFallsBackToRawValues(t *testing.T) {
+	summary := objprop.Summary(object.TripleFrom(11, 0, 0), object.Properties{Generic: []byte{10, 1}})
+
+	assert.Equal(t, "Animating: FrameTime=10, Flags=1", summary)
+}
+
+func TestSummaryOfObjectWithoutProperties(t *testing.T) {
+	summary := objprop.Summary(object.TripleFrom(4, 0, 0), object.Properties{})
+
+	assert.Equal(t, "Drug: (no properties)", summary)
+}
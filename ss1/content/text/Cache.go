@@ -1,8 +1,8 @@
 package text
 
 import (
-	"errors"
 	"io/ioutil"
+	"sync"
 
 	"github.com/inkyblackness/hacked/ss1/resource"
 )
@@ -11,18 +11,22 @@ type keyResolver func(resource.Key) resource.Key
 type textReader func(resource.Selector, resource.Key, Codepage) (string, error)
 
 // Cache retrieves texts from a localizer and keeps them decoded until they are invalidated.
+//
+// A cache is safe for concurrent use, as long as the localizer is safe for concurrent reads.
+// Concurrent requests for the same uncached text may decode it more than once.
 type Cache struct {
-	cp        Codepage
+	codepages *Codepages
 	localizer resource.Localizer
 	reader    textReader
 
 	keyResolver keyResolver
+	mutex       sync.RWMutex
 	texts       map[resource.Key]string
 }
 
-func newCache(cp Codepage, localizer resource.Localizer, keyResolver keyResolver, reader textReader) *Cache {
+func newCache(codepages *Codepages, localizer resource.Localizer, keyResolver keyResolver, reader textReader) *Cache {
 	cache := &Cache{
-		cp:        cp,
+		codepages: codepages,
 		localizer: localizer,
 		reader:    reader,
 
@@ -33,13 +37,15 @@ func newCache(cp Codepage, localizer resource.Localizer, keyResolver keyResolver
 }
 
 // NewLineCache returns a cache for single-block texts.
-func NewLineCache(cp Codepage, localizer resource.Localizer) *Cache {
-	return newCache(cp, localizer, func(key resource.Key) resource.Key { return key }, readLine)
+// Texts are decoded with the codepage of their language.
+func NewLineCache(codepages *Codepages, localizer resource.Localizer) *Cache {
+	return newCache(codepages, localizer, func(key resource.Key) resource.Key { return key }, readLine)
 }
 
 // NewPageCache returns a cache for resource-based texts.
-func NewPageCache(cp Codepage, localizer resource.Localizer) *Cache {
-	return newCache(cp, localizer, func(key resource.Key) resource.Key {
+// Texts are decoded with the codepage of their language.
+func NewPageCache(codepages *Codepages, localizer resource.Localizer) *Cache {
+	return newCache(codepages, localizer, func(key resource.Key) resource.Key {
 		return resource.KeyOf(key.ID.Plus(key.Index), key.Lang, 0)
 	}, readPage)
 }
@@ -50,7 +56,7 @@ func readLine(selector resource.Selector, key resource.Key, cp Codepage) (string
 		return "", err
 	}
 	if view.ContentType() != resource.Text {
-		return "", errors.New("resource is not a text")
+		return "", resource.ErrContentTypeOf(key.ID, view.ContentType(), resource.Text)
 	}
 	reader, err := view.Block(key.Index)
 	if err != nil {
@@ -64,12 +70,13 @@ func readLine(selector resource.Selector, key resource.Key, cp Codepage) (string
 }
 
 func readPage(selector resource.Selector, key resource.Key, cp Codepage) (string, error) {
-	view, err := selector.Select(key.ID.Plus(key.Index))
+	id := key.ID.Plus(key.Index)
+	view, err := selector.Select(id)
 	if err != nil {
 		return "", err
 	}
 	if view.ContentType() != resource.Text {
-		return "", errors.New("resource is not a text")
+		return "", resource.ErrContentTypeOf(id, view.ContentType(), resource.Text)
 	}
 	blockCount := view.BlockCount()
 	value := ""
@@ -89,6 +96,8 @@ func readPage(selector resource.Selector, key resource.Key, cp Codepage) (string
 
 // InvalidateResources lets the cache remove any texts from resources that are specified in the given slice.
 func (cache *Cache) InvalidateResources(ids []resource.ID) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
 	for _, id := range ids {
 		for key := range cache.texts {
 			if key.ID == id {
@@ -98,18 +107,30 @@ func (cache *Cache) InvalidateResources(ids []resource.ID) {
 	}
 }
 
+// InvalidateKey lets the cache remove the text of given key, keeping all other texts of the same resource.
+func (cache *Cache) InvalidateKey(key resource.Key) {
+	cacheKey := cache.keyResolver(key)
+	cache.mutex.Lock()
+	delete(cache.texts, cacheKey)
+	cache.mutex.Unlock()
+}
+
 // Text retrieves and caches the text of given key.
 func (cache *Cache) Text(key resource.Key) (string, error) {
 	cacheKey := cache.keyResolver(key)
+	cache.mutex.RLock()
 	value, existing := cache.texts[cacheKey]
+	cache.mutex.RUnlock()
 	if existing {
 		return value, nil
 	}
 	selector := cache.localizer.LocalizedResources(key.Lang)
-	value, err := cache.reader(selector, key, cache.cp)
+	value, err := cache.reader(selector, key, cache.codepages.ForLanguage(key.Lang))
 	if err != nil {
 		return "", err
 	}
+	cache.mutex.Lock()
 	cache.texts[cacheKey] = value
+	cache.mutex.Unlock()
 	return value, nil
 }
@@ -1,6 +1,7 @@
 package text_test
 
 import (
+	"sync"
 	"testing"
 
 	"github.com/inkyblackness/hacked/ss1/content/text"
@@ -16,8 +17,9 @@ type CacheSuite struct {
 
 	localizedResources resource.LocalizedResourcesList
 
-	cp       text.Codepage
-	instance *text.Cache
+	cp        text.Codepage
+	codepages *text.Codepages
+	instance  *text.Cache
 }
 
 func TestCacheSuite(t *testing.T) {
@@ -26,6 +28,7 @@ func TestCacheSuite(t *testing.T) {
 
 func (suite *CacheSuite) SetupTest() {
 	suite.cp = text.DefaultCodepage()
+	suite.codepages = text.NewCodepages(suite.cp)
 	suite.instance = nil
 }
 
@@ -100,6 +103,37 @@ func (suite *CacheSuite) TestInvalidationConsidersOnlyAffectedIDs() {
 	suite.thenTextShouldReturn("test", key)
 }
 
+func (suite *CacheSuite) TestInvalidateKeyConsidersOnlyGivenKey() {
+	suite.givenALineCache()
+	key0 := resource.KeyOf(0x1000, resource.LangGerman, 0)
+	key1 := resource.KeyOf(0x1000, resource.LangGerman, 1)
+	suite.givenResourcesAre(
+		suite.someLocalizedResources(resource.LangGerman,
+			suite.storing(0x1000, "zero", "one")))
+	suite.givenTextWasRetrieved(key0)
+	suite.givenTextWasRetrieved(key1)
+	suite.givenResourcesAre(
+		suite.someLocalizedResources(resource.LangGerman,
+			suite.storing(0x1000, "changed", "other")))
+	suite.whenCacheKeyIsInvalidated(key1)
+	suite.thenTextShouldReturn("zero", key0)
+	suite.thenTextShouldReturn("other", key1)
+}
+
+func (suite *CacheSuite) TestInvalidateKeyResolvesKeyForPageCache() {
+	suite.givenAPageCache()
+	key := resource.KeyOf(0x1000, resource.LangGerman, 1)
+	suite.givenResourcesAre(
+		suite.someLocalizedResources(resource.LangGerman,
+			suite.storing(0x1001, "page")))
+	suite.givenTextWasRetrieved(key)
+	suite.givenResourcesAre(
+		suite.someLocalizedResources(resource.LangGerman,
+			suite.storing(0x1001, "changed")))
+	suite.whenCacheKeyIsInvalidated(key)
+	suite.thenTextShouldReturn("changed", key)
+}
+
 func (suite *CacheSuite) TestTextReturnsErrorIfResourceIsNotATextForLineCache() {
 	suite.givenALineCache()
 	suite.whenResourcesAre(suite.someLocalizedResources(resource.LangDefault,
@@ -114,12 +148,52 @@ func (suite *CacheSuite) TestTextReturnsErrorIfResourceIsNotATextForPageCache()
 	suite.thenTextShouldReturnError(resource.KeyOf(0x1000, resource.LangDefault, 0))
 }
 
+func (suite *CacheSuite) TestTextCanBeRetrievedConcurrently() {
+	suite.givenAPageCache()
+	suite.givenResourcesAre(
+		suite.someLocalizedResources(resource.LangGerman,
+			suite.storing(0x1000, "first"), suite.storing(0x1001, "second"), suite.storing(0x1002, "third")))
+	expected := []string{"first", "second", "third"}
+
+	var wg sync.WaitGroup
+	for routine := 0; routine < 16; routine++ {
+		wg.Add(1)
+		go func(routine int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				index := (routine + i) % len(expected)
+				result, err := suite.instance.Text(resource.KeyOf(0x1000, resource.LangGerman, index))
+				assert.Nil(suite.T(), err, "no error expected")
+				assert.Equal(suite.T(), expected[index], result)
+				if (i % 10) == 0 {
+					suite.instance.InvalidateResources([]resource.ID{resource.ID(0x1000 + index)})
+				}
+			}
+		}(routine)
+	}
+	wg.Wait()
+}
+
+func (suite *CacheSuite) TestTextIsDecodedWithCodepageOfLanguage() {
+	suite.givenCodepageFor(resource.LangGerman, upperCaseCodepage{})
+	suite.givenALineCache()
+	suite.whenResourcesAre(
+		suite.someLocalizedResources(resource.LangGerman, suite.storing(0x1000, "german")),
+		suite.someLocalizedResources(resource.LangFrench, suite.storing(0x1000, "french")))
+	suite.thenTextShouldReturn("GERMAN", resource.KeyOf(0x1000, resource.LangGerman, 0))
+	suite.thenTextShouldReturn("french", resource.KeyOf(0x1000, resource.LangFrench, 0))
+}
+
+func (suite *CacheSuite) givenCodepageFor(lang resource.Language, cp text.Codepage) {
+	suite.codepages.Register(lang, cp)
+}
+
 func (suite *CacheSuite) givenALineCache() {
-	suite.instance = text.NewLineCache(suite.cp, suite)
+	suite.instance = text.NewLineCache(suite.codepages, suite)
 }
 
 func (suite *CacheSuite) givenAPageCache() {
-	suite.instance = text.NewPageCache(suite.cp, suite)
+	suite.instance = text.NewPageCache(suite.codepages, suite)
 }
 
 func (suite *CacheSuite) givenResourcesAre(resources ...resource.LocalizedResources) {
@@ -139,6 +213,10 @@ func (suite *CacheSuite) whenCacheResourcesAreInvalidated(ids ...resource.ID) {
 	suite.instance.InvalidateResources(ids)
 }
 
+func (suite *CacheSuite) whenCacheKeyIsInvalidated(key resource.Key) {
+	suite.instance.InvalidateKey(key)
+}
+
 func (suite *CacheSuite) thenTextShouldReturn(expected string, key resource.Key) {
 	result, err := suite.instance.Text(key)
 	require.Nil(suite.T(), err, "No error expected")
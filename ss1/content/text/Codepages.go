@@ -0,0 +1,44 @@
+package text
+
+import "github.com/inkyblackness/hacked/ss1/resource"
+
+// Codepages selects the codepage to use for each language.
+// Languages without a registered codepage use the default codepage.
+//
+// Codepages are meant to be registered during setup. Reading is safe for concurrent use,
+// as long as no codepage is registered at the same time.
+type Codepages struct {
+	defaultCodepage Codepage
+	perLanguage     map[resource.Language]Codepage
+}
+
+// NewCodepages returns a new registry that uses given codepage for all languages.
+func NewCodepages(defaultCodepage Codepage) *Codepages {
+	return &Codepages{
+		defaultCodepage: defaultCodepage,
+		perLanguage:     make(map[resource.Language]Codepage),
+	}
+}
+
+// Register sets the codepage for given language. A nil codepage removes a previous registration,
+// so that the language uses the default codepage again.
+func (codepages *Codepages) Register(lang resource.Language, cp Codepage) {
+	if cp == nil {
+		delete(codepages.perLanguage, lang)
+		return
+	}
+	codepages.perLanguage[lang] = cp
+}
+
+// Default returns the codepage used for languages without a registered one.
+func (codepages *Codepages) Default() Codepage {
+	return codepages.defaultCodepage
+}
+
+// ForLanguage returns the codepage to use for given language.
+func (codepages *Codepages) ForLanguage(lang resource.Language) Codepage {
+	if cp, registered := codepages.perLanguage[lang]; registered {
+		return cp
+	}
+	return codepages.defaultCodepage
+}
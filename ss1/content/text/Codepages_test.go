@@ -0,0 +1,40 @@
+package text_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/inkyblackness/hacked/ss1/content/text"
+	"github.com/inkyblackness/hacked/ss1/resource"
+)
+
+type upperCaseCodepage struct{}
+
+func (cp upperCaseCodepage) Encode(value string) []byte {
+	return text.DefaultCodepage().Encode(strings.ToUpper(value))
+}
+
+func (cp upperCaseCodepage) Decode(data []byte) string {
+	return strings.ToUpper(text.DefaultCodepage().Decode(data))
+}
+
+func TestCodepagesFallBackToDefault(t *testing.T) {
+	defaultCodepage := text.DefaultCodepage()
+	codepages := text.NewCodepages(defaultCodepage)
+	codepages.Register(resource.LangGerman, upperCaseCodepage{})
+
+	assert.Equal(t, upperCaseCodepage{}, codepages.ForLanguage(resource.LangGerman))
+	assert.Equal(t, defaultCodepage, codepages.ForLanguage(resource.LangFrench))
+	assert.Equal(t, defaultCodepage, codepages.Default())
+}
+
+func TestCodepagesRegistrationCanBeRemoved(t *testing.T) {
+	defaultCodepage := text.DefaultCodepage()
+	codepages := text.NewCodepages(defaultCodepage)
+	codepages.Register(resource.LangGerman, upperCaseCodepage{})
+	codepages.Register(resource.LangGerman, nil)
+
+	assert.Equal(t, defaultCodepage, codepages.ForLanguage(resource.LangGerman))
+}
@@ -8,16 +8,17 @@ import (
 
 // ElectronicMessageCache retrieves messages from a localizer and keeps them decoded until they are invalidated.
 type ElectronicMessageCache struct {
-	cp        Codepage
+	codepages *Codepages
 	localizer resource.Localizer
 
 	messages map[resource.Key]ElectronicMessage
 }
 
 // NewElectronicMessageCache returns a new instance.
-func NewElectronicMessageCache(cp Codepage, localizer resource.Localizer) *ElectronicMessageCache {
+// Messages are decoded with the codepage of their language.
+func NewElectronicMessageCache(codepages *Codepages, localizer resource.Localizer) *ElectronicMessageCache {
 	cache := &ElectronicMessageCache{
-		cp:        cp,
+		codepages: codepages,
 		localizer: localizer,
 
 		messages: make(map[resource.Key]ElectronicMessage),
@@ -46,12 +47,15 @@ func (cache *ElectronicMessageCache) Message(key resource.Key) (ElectronicMessag
 	selector := cache.localizer.LocalizedResources(key.Lang)
 	view, err := selector.Select(cacheKey.ID)
 	if err != nil {
-		return EmptyElectronicMessage(), errors.New("no message found")
+		return EmptyElectronicMessage(), err
+	}
+	if view.ContentType() != resource.Text {
+		return EmptyElectronicMessage(), resource.ErrContentTypeOf(cacheKey.ID, view.ContentType(), resource.Text)
 	}
-	if (view.ContentType() != resource.Text) || !view.Compound() {
+	if !view.Compound() {
 		return EmptyElectronicMessage(), errors.New("invalid resource type")
 	}
-	value, err = DecodeElectronicMessage(cache.cp, view)
+	value, err = DecodeElectronicMessage(cache.codepages.ForLanguage(key.Lang), view)
 	if err != nil {
 		return EmptyElectronicMessage(), err
 	}
@@ -0,0 +1,155 @@
+package edit
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/inkyblackness/hacked/ss1/content/archive/level"
+	"github.com/inkyblackness/hacked/ss1/content/archive/level/lvlobj"
+	"github.com/inkyblackness/hacked/ss1/content/interpreters"
+	"github.com/inkyblackness/hacked/ss1/content/object"
+)
+
+// ObjectNamer returns a readable name for given object type.
+type ObjectNamer func(object.Triple) string
+
+// LevelTriggerTarget is an object that is referenced by a logic object.
+type LevelTriggerTarget struct {
+	// Key is the path of the property holding the reference.
+	Key string
+	// ID is the raw identifier of the referenced object.
+	ID uint32
+	// Exists is true if the identifier refers to an object in use within the level.
+	Exists bool
+	// Triple is the type of the referenced object. Only valid if Exists is true.
+	Triple object.Triple
+	// Name is the name of the referenced object type. Empty if Exists is false.
+	Name string
+}
+
+// String returns a textual representation of the target.
+func (target LevelTriggerTarget) String() string {
+	if !target.Exists {
+		return fmt.Sprintf("%s -> %d (missing)", target.Key, target.ID)
+	}
+	return fmt.Sprintf("%s -> %d: %v %s", target.Key, target.ID, target.Triple, target.Name)
+}
+
+// LevelTrigger describes one logic object of a level.
+type LevelTrigger struct {
+	ID     level.ObjectID
+	Triple object.Triple
+	Name   string
+	// Action is the name of the action the trigger performs. Empty if the object has no action.
+	Action string
+
+	X level.Coordinate
+	Y level.Coordinate
+	Z level.HeightUnit
+
+	// Targets are all the objects that are referenced. References to no object are not listed.
+	Targets []LevelTriggerTarget
+	// Unlinked is true if the object has properties to reference other objects, yet none are set.
+	Unlinked bool
+}
+
+// Dangling returns the targets that refer to objects not in use within the level.
+func (trigger LevelTrigger) Dangling() []LevelTriggerTarget {
+	var result []LevelTriggerTarget
+	for _, target := range trigger.Targets {
+		if !target.Exists {
+			result = append(result, target)
+		}
+	}
+	return result
+}
+
+// String returns a textual representation of the trigger, with one line per target.
+func (trigger LevelTrigger) String() string {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("%d: %v %s at %d.%d/%d.%d/%d",
+		trigger.ID, trigger.Triple, trigger.Name,
+		trigger.X.Tile(), trigger.X.Fine(), trigger.Y.Tile(), trigger.Y.Fine(), trigger.Z))
+	if len(trigger.Action) > 0 {
+		builder.WriteString(" - " + trigger.Action)
+	}
+	if trigger.Unlinked {
+		builder.WriteString(" (unlinked)")
+	}
+	for _, target := range trigger.Targets {
+		builder.WriteString("\n  " + target.String())
+	}
+	return builder.String()
+}
+
+// LevelTriggers returns all the logic objects (traps) of the given level, in order of their identifier.
+// The namer is used to resolve the names of the triggers and their targets. It may be nil.
+func LevelTriggers(lvl *level.Level, namer ObjectNamer) []LevelTrigger {
+	interpreterFactory := lvlobj.InterpreterFactory(lvlobj.ForRealWorld)
+	if lvl.IsCyberspace() {
+		interpreterFactory = lvlobj.ForCyberspace
+	}
+	nameOf := func(triple object.Triple) string {
+		if namer == nil {
+			return ""
+		}
+		return namer(triple)
+	}
+	objectLimit := uint32(lvl.ObjectLimit())
+
+	var result []LevelTrigger
+	for id := level.ObjectID(1); uint32(id) <= objectLimit; id++ {
+		obj := lvl.Object(id)
+		if (obj.InUse == 0) || (obj.Class != object.ClassTrap) {
+			continue
+		}
+		triple := obj.Triple()
+		interpreter := interpreterFactory(triple, lvl.ObjectClassData(id))
+		trigger := LevelTrigger{
+			ID:     id,
+			Triple: triple,
+			Name:   nameOf(triple),
+			Action: actionName(interpreter),
+			X:      obj.X,
+			Y:      obj.Y,
+			Z:      obj.Z,
+		}
+		links := lvlobj.LinksOf(interpreter)
+		for _, link := range links {
+			if link.Target == 0 {
+				continue
+			}
+			target := LevelTriggerTarget{Key: link.Key, ID: link.Target}
+			if (link.Target <= objectLimit) && lvl.HasObject(level.ObjectID(link.Target)) {
+				target.Exists = true
+				target.Triple = lvl.Object(level.ObjectID(link.Target)).Triple()
+				target.Name = nameOf(target.Triple)
+			}
+			trigger.Targets = append(trigger.Targets, target)
+		}
+		trigger.Unlinked = (len(links) > 0) && (len(trigger.Targets) == 0)
+		result = append(result, trigger)
+	}
+	return result
+}
+
+func actionName(interpreter *interpreters.Instance) string {
+	hasAction := false
+	for _, key := range interpreter.ActiveRefinements() {
+		hasAction = hasAction || (key == "Action")
+	}
+	if !hasAction {
+		return ""
+	}
+	action := interpreter.Refined("Action")
+	actionType := action.Get("Type")
+	name := fmt.Sprintf("Action %d", actionType)
+	simplifier := interpreters.NewSimplifier(func(minValue, maxValue int64, formatter interpreters.RawValueFormatter) {})
+	simplifier.SetEnumValueHandler(func(values map[uint32]string) {
+		if text, known := values[actionType]; known {
+			name = text
+		}
+	})
+	action.Describe("Type", simplifier)
+	return name
+}
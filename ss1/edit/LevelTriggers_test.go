@@ -0,0 +1,81 @@
+package edit_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/content/archive/level"
+	"github.com/inkyblackness/hacked/ss1/content/archive/level/leveltest"
+	"github.com/inkyblackness/hacked/ss1/content/object"
+	"github.com/inkyblackness/hacked/ss1/edit"
+)
+
+func newTriggerTestObject(t *testing.T, lvl *level.Level, triple object.Triple, x, y byte) level.ObjectID {
+	t.Helper()
+	id, err := lvl.NewObject(triple.Class)
+	require.Nil(t, err, "no error expected creating object")
+	obj := lvl.Object(id)
+	obj.Subclass = triple.Subclass
+	obj.Type = triple.Type
+	obj.X = level.CoordinateAt(x, 0x80)
+	obj.Y = level.CoordinateAt(y, 0x80)
+	return id
+}
+
+func triggerTestNamer(triple object.Triple) string {
+	return map[object.Triple]string{
+		object.TripleFrom(12, 0, 0): "tile entry trigger",
+		object.TripleFrom(7, 2, 0):  "door",
+		object.TripleFrom(12, 2, 4): "music voodoo",
+	}[triple]
+}
+
+func TestLevelTriggersListsTargets(t *testing.T) {
+	lvl := leveltest.NewLevel(t, level.EmptyLevelParameters{})
+	door := newTriggerTestObject(t, lvl, object.TripleFrom(7, 2, 0), 2, 2)
+	trigger := newTriggerTestObject(t, lvl, object.TripleFrom(12, 0, 0), 3, 4)
+	data := lvl.ObjectClassData(trigger)
+	data[0] = 6 // Trigger Other Objects
+	data[6] = byte(door)
+	data[10] = 200 // not existing
+
+	triggers := edit.LevelTriggers(lvl, triggerTestNamer)
+
+	require.Equal(t, 1, len(triggers), "one trigger expected")
+	result := triggers[0]
+	assert.Equal(t, trigger, result.ID)
+	assert.Equal(t, "tile entry trigger", result.Name)
+	assert.Equal(t, "Trigger Other Objects", result.Action)
+	assert.Equal(t, byte(3), result.X.Tile())
+	assert.Equal(t, byte(4), result.Y.Tile())
+	assert.False(t, result.Unlinked, "trigger should be linked")
+	assert.Equal(t, []edit.LevelTriggerTarget{
+		{Key: "Action.TriggerOtherObjects.Object1ID", ID: uint32(door), Exists: true, Triple: object.TripleFrom(7, 2, 0), Name: "door"},
+		{Key: "Action.TriggerOtherObjects.Object2ID", ID: 200},
+	}, result.Targets)
+	assert.Equal(t, []edit.LevelTriggerTarget{result.Targets[1]}, result.Dangling())
+}
+
+func TestLevelTriggersFlagsUnlinkedTriggers(t *testing.T) {
+	lvl := leveltest.NewLevel(t, level.EmptyLevelParameters{})
+	trigger := newTriggerTestObject(t, lvl, object.TripleFrom(12, 0, 0), 3, 4)
+	lvl.ObjectClassData(trigger)[0] = 6 // Trigger Other Objects
+	newTriggerTestObject(t, lvl, object.TripleFrom(12, 2, 4), 5, 5)
+
+	triggers := edit.LevelTriggers(lvl, nil)
+
+	require.Equal(t, 2, len(triggers), "two triggers expected")
+	assert.True(t, triggers[0].Unlinked, "trigger without targets should be unlinked")
+	assert.Empty(t, triggers[0].Dangling())
+	assert.False(t, triggers[1].Unlinked, "object without link properties should not be unlinked")
+	assert.Equal(t, "", triggers[1].Action)
+}
+
+func TestLevelTriggersIgnoresOtherClasses(t *testing.T) {
+	lvl := leveltest.NewLevel(t, level.EmptyLevelParameters{})
+	newTriggerTestObject(t, lvl, object.TripleFrom(7, 2, 0), 2, 2)
+
+	assert.Empty(t, edit.LevelTriggers(lvl, nil))
+}
@@ -0,0 +1,151 @@
+package edit
+
+import (
+	"fmt"
+
+	"github.com/inkyblackness/hacked/ss1/content/object"
+	"github.com/inkyblackness/hacked/ss1/content/text"
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/world/ids"
+)
+
+// Name slot limits. Names that are longer are cut off by the engine.
+const (
+	// MaxObjectLongNameLength is the amount of characters a long object name can have.
+	MaxObjectLongNameLength = 49
+	// MaxObjectShortNameLength is the amount of characters a short object name can have.
+	MaxObjectShortNameLength = 19
+)
+
+// ObjectNameLimit returns the amount of characters the name slot of a long or short name can hold.
+func ObjectNameLimit(longName bool) int {
+	if longName {
+		return MaxObjectLongNameLength
+	}
+	return MaxObjectShortNameLength
+}
+
+// ValidateObjectName verifies that the given name fits the name slot of a long or short name.
+func ValidateObjectName(name string, longName bool) error {
+	kind := "short"
+	if longName {
+		kind = "long"
+	}
+	if length, limit := len([]rune(name)), ObjectNameLimit(longName); length > limit {
+		return fmt.Errorf("%s name has %d characters, the limit is %d", kind, length, limit)
+	}
+	return nil
+}
+
+// ObjectNameBlockSetter modifies storage of raw resource data.
+type ObjectNameBlockSetter interface {
+	SetResourceBlock(lang resource.Language, id resource.ID, index int, data []byte)
+}
+
+// ObjectNameDiscrepancy describes a name table that does not have as many entries as there are object types.
+type ObjectNameDiscrepancy struct {
+	// ID identifies the name table, either ids.ObjectLongNames or ids.ObjectShortNames.
+	ID resource.ID
+	// Language is the language of the name table.
+	Language resource.Language
+	// Expected is the number of object types.
+	Expected int
+	// Actual is the number of entries in the name table.
+	Actual int
+}
+
+// String returns a textual description of the discrepancy.
+func (discrepancy ObjectNameDiscrepancy) String() string {
+	tableName := "long names"
+	if discrepancy.ID == ids.ObjectShortNames {
+		tableName = "short names"
+	}
+	return fmt.Sprintf("%v %v: %d entries for %d object types",
+		discrepancy.Language, tableName, discrepancy.Actual, discrepancy.Expected)
+}
+
+// Missing returns the number of entries the name table lacks. Zero if the table has too many entries.
+func (discrepancy ObjectNameDiscrepancy) Missing() int {
+	if discrepancy.Actual >= discrepancy.Expected {
+		return 0
+	}
+	return discrepancy.Expected - discrepancy.Actual
+}
+
+// ObjectNameDiscrepancies compares the number of object types in the given table with the number of
+// entries in the long and short name tables of all languages.
+// Languages that have no name table at all are not reported.
+func ObjectNameDiscrepancies(table object.PropertiesTable, localizer resource.Localizer) []ObjectNameDiscrepancy {
+	var result []ObjectNameDiscrepancy
+	expected := len(table.Triples())
+	for _, lang := range resource.Languages() {
+		selector := localizer.LocalizedResources(lang)
+		for _, id := range []resource.ID{ids.ObjectLongNames, ids.ObjectShortNames} {
+			view, err := selector.Select(id)
+			if err != nil {
+				continue
+			}
+			if actual := view.BlockCount(); actual != expected {
+				result = append(result, ObjectNameDiscrepancy{
+					ID:       id,
+					Language: lang,
+					Expected: expected,
+					Actual:   actual,
+				})
+			}
+		}
+	}
+	return result
+}
+
+// ObjectNameKey returns the key of the name of given object in the name table of given language.
+// The name tables of all languages share the order of the object types in the properties table,
+// the first type of the first class being at index zero. No offset applies for any language.
+// Returns false if the object is not part of the table.
+//
+// Names of a language appear shifted if types were added to the properties table, yet the
+// name table of that language was not extended accordingly: All types after the added ones then
+// show the name of the type that follows them. Such a table is shorter than expected and is reported by
+// ObjectNameDiscrepancies().
+func ObjectNameKey(table object.PropertiesTable, triple object.Triple, lang resource.Language, longName bool) (resource.Key, bool) {
+	index := table.TripleIndex(triple)
+	if index < 0 {
+		return resource.Key{}, false
+	}
+	id := ids.ObjectShortNames
+	if longName {
+		id = ids.ObjectLongNames
+	}
+	return resource.KeyOf(id, lang, index), true
+}
+
+// ObjectNamesAvailable returns true if the long or short name table is provided for the given language.
+// Languages without name tables can still be edited, their tables are created on first change.
+func ObjectNamesAvailable(localizer resource.Localizer, lang resource.Language) bool {
+	selector := localizer.LocalizedResources(lang)
+	for _, id := range []resource.ID{ids.ObjectLongNames, ids.ObjectShortNames} {
+		if _, err := selector.Select(id); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// ObjectNamePlaceholder returns the text that is used for missing names of the given object.
+func ObjectNamePlaceholder(triple object.Triple) string {
+	return fmt.Sprintf("(%d/%d/%d)", triple.Class, triple.Subclass, triple.Type)
+}
+
+// RepairObjectNames extends the name tables of the given discrepancies with placeholders for the missing entries.
+// Name tables with too many entries are left unchanged. Placeholders are encoded with the codepage of their language.
+func RepairObjectNames(setter ObjectNameBlockSetter, codepages *text.Codepages,
+	table object.PropertiesTable, discrepancies []ObjectNameDiscrepancy) {
+	triples := table.Triples()
+	for _, discrepancy := range discrepancies {
+		cp := codepages.ForLanguage(discrepancy.Language)
+		for index := discrepancy.Actual; index < discrepancy.Expected && index < len(triples); index++ {
+			setter.SetResourceBlock(discrepancy.Language, discrepancy.ID, index,
+				cp.Encode(ObjectNamePlaceholder(triples[index])))
+		}
+	}
+}
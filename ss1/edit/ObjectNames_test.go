@@ -0,0 +1,206 @@
+package edit_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/inkyblackness/hacked/ss1/content/object"
+	"github.com/inkyblackness/hacked/ss1/content/text"
+	"github.com/inkyblackness/hacked/ss1/edit"
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/world"
+	"github.com/inkyblackness/hacked/ss1/world/ids"
+)
+
+type ObjectNamesSuite struct {
+	suite.Suite
+
+	cp    text.Codepage
+	table object.PropertiesTable
+	mod   *world.Mod
+}
+
+func TestObjectNamesSuite(t *testing.T) {
+	suite.Run(t, new(ObjectNamesSuite))
+}
+
+func (suite *ObjectNamesSuite) SetupTest() {
+	suite.cp = text.DefaultCodepage()
+	suite.table = object.StandardPropertiesTable()
+	suite.mod = world.NewMod(func([]resource.ID, []resource.ID) {}, func() {})
+}
+
+func (suite *ObjectNamesSuite) TestNoDiscrepanciesForMatchingTables() {
+	suite.givenNameTables(resource.LangDefault, len(suite.table.Triples()), len(suite.table.Triples()))
+
+	discrepancies := edit.ObjectNameDiscrepancies(suite.table, suite.mod)
+
+	assert.Empty(suite.T(), discrepancies)
+}
+
+func (suite *ObjectNamesSuite) TestDiscrepanciesAreReportedPerLanguage() {
+	count := len(suite.table.Triples())
+	suite.givenNameTables(resource.LangDefault, count, count-2)
+	suite.givenNameTables(resource.LangGerman, count-1, count+1)
+
+	discrepancies := edit.ObjectNameDiscrepancies(suite.table, suite.mod)
+
+	assert.Equal(suite.T(), []edit.ObjectNameDiscrepancy{
+		{ID: ids.ObjectShortNames, Language: resource.LangDefault, Expected: count, Actual: count - 2},
+		{ID: ids.ObjectLongNames, Language: resource.LangGerman, Expected: count, Actual: count - 1},
+		{ID: ids.ObjectShortNames, Language: resource.LangGerman, Expected: count, Actual: count + 1},
+	}, discrepancies)
+}
+
+func (suite *ObjectNamesSuite) TestRepairAddsPlaceholdersForMissingEntries() {
+	triples := suite.table.Triples()
+	count := len(triples)
+	suite.givenNameTables(resource.LangDefault, count-2, count)
+
+	suite.mod.Modify(func(modder world.Modder) {
+		edit.RepairObjectNames(modder, text.NewCodepages(suite.cp), suite.table, edit.ObjectNameDiscrepancies(suite.table, suite.mod))
+	})
+
+	assert.Empty(suite.T(), edit.ObjectNameDiscrepancies(suite.table, suite.mod))
+	view, err := suite.mod.LocalizedResources(resource.LangDefault).Select(ids.ObjectLongNames)
+	require.Nil(suite.T(), err)
+	assert.Equal(suite.T(), resource.Text, view.ContentType())
+	data := suite.mod.ModifiedBlock(resource.LangDefault, ids.ObjectLongNames, count-1)
+	assert.Equal(suite.T(), edit.ObjectNamePlaceholder(triples[count-1]), suite.cp.Decode(data))
+}
+
+func (suite *ObjectNamesSuite) TestObjectNameKeyMatchesStockOrderInAllLanguages() {
+	// The stock name tables of all languages have 476 entries, ordered by class, subclass, and type.
+	// The indices are those of the first and last type of each class in the stock tables.
+	stockIndices := []struct {
+		class, subclass, objType int
+		index                    int
+	}{
+		{0, 0, 0, 0}, {0, 5, 1, 15},
+		{1, 0, 0, 16}, {1, 6, 1, 30},
+		{2, 0, 0, 31}, {2, 2, 1, 54},
+		{3, 0, 0, 55}, {3, 1, 2, 62},
+		{4, 0, 0, 63}, {4, 0, 6, 69},
+		{5, 0, 0, 70}, {5, 1, 9, 84},
+		{6, 0, 0, 85}, {6, 4, 2, 106},
+		{7, 0, 0, 107}, {7, 7, 9, 183},
+		{8, 0, 0, 184}, {8, 7, 7, 263},
+		{9, 0, 0, 264}, {9, 5, 2, 298},
+		{10, 0, 0, 299}, {10, 4, 9, 339},
+		{11, 0, 0, 340}, {11, 2, 13, 373},
+		{12, 0, 0, 374}, {12, 2, 4, 392},
+		{13, 0, 0, 393}, {13, 6, 7, 438},
+		{14, 0, 0, 439}, {14, 4, 1, 475},
+	}
+	stockName := func(lang resource.Language, index int, longName bool) string {
+		if longName {
+			return fmt.Sprintf("%v long %d", lang, index)
+		}
+		return fmt.Sprintf("%v short %d", lang, index)
+	}
+	for _, lang := range resource.Languages() {
+		suite.givenStockNameTables(lang, 476, stockName)
+	}
+	cache := text.NewLineCache(text.NewCodepages(suite.cp), suite.mod)
+
+	for _, lang := range resource.Languages() {
+		for _, tc := range stockIndices {
+			triple := object.TripleFrom(tc.class, tc.subclass, tc.objType)
+			for _, longName := range []bool{true, false} {
+				key, known := edit.ObjectNameKey(suite.table, triple, lang, longName)
+				require.True(suite.T(), known, "key expected for %v", triple)
+				name, err := cache.Text(key)
+				require.Nil(suite.T(), err, "no error expected for %v", key)
+				assert.Equal(suite.T(), stockName(lang, tc.index, longName), name, "wrong name for %v in %v", triple, lang)
+			}
+		}
+	}
+}
+
+func (suite *ObjectNamesSuite) TestAddedTypesShiftNamesOfTablesThatWereNotExtended() {
+	desc := object.StandardDescriptors()
+	desc[object.ClassGun].Subclasses[0].TypeCount++
+	table := object.NewPropertiesTable(desc)
+	stockName := func(lang resource.Language, index int, longName bool) string {
+		return fmt.Sprintf("%v %d", lang, index)
+	}
+	suite.givenStockNameTables(resource.LangDefault, 477, stockName)
+	suite.givenStockNameTables(resource.LangGerman, 476, stockName)
+	cache := text.NewLineCache(text.NewCodepages(suite.cp), suite.mod)
+
+	key, _ := edit.ObjectNameKey(table, object.TripleFrom(int(object.ClassAmmo), 0, 0), resource.LangGerman, true)
+	name, err := cache.Text(key)
+	require.Nil(suite.T(), err)
+	assert.Equal(suite.T(), "German 17", name, "name of the following type expected")
+	assert.Equal(suite.T(), []edit.ObjectNameDiscrepancy{
+		{ID: ids.ObjectLongNames, Language: resource.LangGerman, Expected: 477, Actual: 476},
+		{ID: ids.ObjectShortNames, Language: resource.LangGerman, Expected: 477, Actual: 476},
+	}, edit.ObjectNameDiscrepancies(table, suite.mod))
+}
+
+func (suite *ObjectNamesSuite) TestObjectNameKeyIsUnknownForTriplesOutsideTable() {
+	_, known := edit.ObjectNameKey(suite.table, object.TripleFrom(int(object.ClassCritter), 0, 200), resource.LangFrench, true)
+	assert.False(suite.T(), known)
+}
+
+func (suite *ObjectNamesSuite) TestObjectNamesAvailableOnlyForLanguagesWithTables() {
+	suite.givenNameTables(resource.LangGerman, 1, 0)
+
+	assert.True(suite.T(), edit.ObjectNamesAvailable(suite.mod, resource.LangGerman), "German expected")
+	assert.False(suite.T(), edit.ObjectNamesAvailable(suite.mod, resource.LangDefault), "Default not expected")
+	assert.False(suite.T(), edit.ObjectNamesAvailable(suite.mod, resource.LangFrench), "French not expected")
+}
+
+func (suite *ObjectNamesSuite) givenStockNameTables(lang resource.Language, count int,
+	nameOf func(lang resource.Language, index int, longName bool) string) {
+	var store resource.Store
+	for _, longName := range []bool{true, false} {
+		data := make([][]byte, count)
+		for index := range data {
+			data[index] = suite.cp.Encode(nameOf(lang, index, longName))
+		}
+		id := ids.ObjectShortNames
+		if longName {
+			id = ids.ObjectLongNames
+		}
+		_ = store.Put(id, resource.Resource{
+			Properties: resource.Properties{Compound: true, ContentType: resource.Text},
+			Blocks:     resource.BlocksFrom(data),
+		})
+	}
+	manifest := suite.mod.World()
+	err := manifest.InsertEntry(manifest.EntryCount(), &world.ManifestEntry{
+		ID:        lang.String(),
+		Resources: []resource.LocalizedResources{{ID: "cybstrng.res", Language: lang, Viewer: store}},
+	})
+	require.Nil(suite.T(), err)
+}
+
+func (suite *ObjectNamesSuite) givenNameTables(lang resource.Language, longCount, shortCount int) {
+	var store resource.Store
+	names := func(count int) [][]byte {
+		data := make([][]byte, count)
+		for index := range data {
+			data[index] = suite.cp.Encode("name")
+		}
+		return data
+	}
+	_ = store.Put(ids.ObjectLongNames, resource.Resource{
+		Properties: resource.Properties{Compound: true, ContentType: resource.Text},
+		Blocks:     resource.BlocksFrom(names(longCount)),
+	})
+	_ = store.Put(ids.ObjectShortNames, resource.Resource{
+		Properties: resource.Properties{Compound: true, ContentType: resource.Text},
+		Blocks:     resource.BlocksFrom(names(shortCount)),
+	})
+	manifest := suite.mod.World()
+	err := manifest.InsertEntry(manifest.EntryCount(), &world.ManifestEntry{
+		ID:        lang.String(),
+		Resources: []resource.LocalizedResources{{ID: "cybstrng.res", Language: lang, Viewer: store}},
+	})
+	require.Nil(suite.T(), err)
+}
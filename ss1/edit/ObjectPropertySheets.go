@@ -0,0 +1,258 @@
+package edit
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/inkyblackness/hacked/ss1/content/interpreters"
+	"github.com/inkyblackness/hacked/ss1/content/object"
+	"github.com/inkyblackness/hacked/ss1/content/object/objprop"
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/world/ids"
+)
+
+// ObjectNameSource provides the decoded text of object names.
+type ObjectNameSource interface {
+	Text(key resource.Key) (string, error)
+}
+
+// ObjectPropertySheets is the exchange format of all object properties.
+// It is meant to be serialized as JSON, edited externally, and read back.
+type ObjectPropertySheets struct {
+	Objects []ObjectPropertySheet `json:"objects"`
+}
+
+// ObjectPropertySheet describes one object type with its names and properties.
+type ObjectPropertySheet struct {
+	Class    int `json:"class"`
+	Subclass int `json:"subclass"`
+	Type     int `json:"type"`
+
+	// Names are keyed by the language name, such as "Default" or "German".
+	Names map[string]ObjectSheetNames `json:"names,omitempty"`
+
+	Common *ObjectSheetCommon `json:"common,omitempty"`
+	// Generic holds the class properties, keyed by their field name.
+	// Nested fields are keyed by their path, separated with dots.
+	Generic map[string]int64 `json:"generic,omitempty"`
+	// Specific holds the subclass properties, keyed the same way as Generic.
+	Specific map[string]int64 `json:"specific,omitempty"`
+}
+
+// Triple returns the identification of the object type.
+func (sheet ObjectPropertySheet) Triple() object.Triple {
+	return object.TripleFrom(sheet.Class, sheet.Subclass, sheet.Type)
+}
+
+// ObjectSheetNames contains the names of an object type in one language.
+type ObjectSheetNames struct {
+	Long  string `json:"long"`
+	Short string `json:"short"`
+}
+
+// ObjectSheetCommon contains the common properties of an object type.
+// Enumerations and flags are stored by their name.
+type ObjectSheetCommon struct {
+	Mass         int32  `json:"mass"`
+	Hitpoints    int16  `json:"hitpoints"`
+	Armor        byte   `json:"armor"`
+	RenderType   string `json:"renderType"`
+	PhysicsModel string `json:"physicsModel"`
+	Hardness     byte   `json:"hardness"`
+	PhysicsXR    byte   `json:"physicsXR"`
+	PhysicsZ     byte   `json:"physicsZ"`
+
+	Vulnerabilities     []string `json:"vulnerabilities"`
+	PrimaryDoubleDamage int      `json:"primaryDoubleDamage"`
+	SuperQuadDamage     int      `json:"superQuadDamage"`
+	Defense             byte     `json:"defense"`
+	Toughness           byte     `json:"toughness"`
+
+	Flags       []string `json:"flags"`
+	LightType   string   `json:"lightType"`
+	UseMode     string   `json:"useMode"`
+	MfdOrMeshID uint16   `json:"mfdOrMeshID"`
+
+	BitmapNumber    uint16 `json:"bitmapNumber"`
+	FrameNumber     uint16 `json:"frameNumber"`
+	BitmapAnimation bool   `json:"bitmapAnimation"`
+	BitmapRepeat    bool   `json:"bitmapRepeat"`
+
+	DestroyEffect    byte `json:"destroyEffect"`
+	DestroySound     bool `json:"destroySound"`
+	DestroyExplosion bool `json:"destroyExplosion"`
+}
+
+// ObjectPropertySheetsFrom creates the sheets of all object types in given table.
+// The names are retrieved from the source for all languages; Missing names are not listed.
+func ObjectPropertySheetsFrom(table object.PropertiesTable, names ObjectNameSource) ObjectPropertySheets {
+	var sheets ObjectPropertySheets
+	table.Iterate(func(triple object.Triple, prop *object.Properties) bool {
+		sheet := ObjectPropertySheet{
+			Class:    int(triple.Class),
+			Subclass: int(triple.Subclass),
+			Type:     int(triple.Type),
+			Names:    objectSheetNamesOf(table.TripleIndex(triple), names),
+			Common:   objectSheetCommonFrom(prop.Common),
+			Generic:  objectSheetFieldsOf(objprop.GenericProperties(triple.Class, prop.Generic)),
+			Specific: objectSheetFieldsOf(objprop.SpecificProperties(triple, prop.Specific)),
+		}
+		sheets.Objects = append(sheets.Objects, sheet)
+		return true
+	})
+	return sheets
+}
+
+// Encode writes the sheets as indented JSON to the given writer.
+func (sheets ObjectPropertySheets) Encode(writer io.Writer) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(sheets)
+}
+
+// DecodeObjectPropertySheets reads sheets in JSON format from the given reader.
+func DecodeObjectPropertySheets(reader io.Reader) (ObjectPropertySheets, error) {
+	var sheets ObjectPropertySheets
+	decoder := json.NewDecoder(reader)
+	decoder.DisallowUnknownFields()
+	err := decoder.Decode(&sheets)
+	return sheets, err
+}
+
+func objectSheetNamesOf(index int, source ObjectNameSource) map[string]ObjectSheetNames {
+	if index < 0 {
+		return nil
+	}
+	result := make(map[string]ObjectSheetNames)
+	for _, lang := range resource.Languages() {
+		longName, longErr := source.Text(resource.KeyOf(ids.ObjectLongNames, lang, index))
+		shortName, shortErr := source.Text(resource.KeyOf(ids.ObjectShortNames, lang, index))
+		if (longErr == nil) || (shortErr == nil) {
+			result[lang.String()] = ObjectSheetNames{Long: longName, Short: shortName}
+		}
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
+
+func objectSheetCommonFrom(common object.CommonProperties) *ObjectSheetCommon {
+	sheet := &ObjectSheetCommon{
+		Mass:         common.Mass,
+		Hitpoints:    common.Hitpoints,
+		Armor:        common.Armor,
+		RenderType:   common.RenderType.String(),
+		PhysicsModel: common.PhysicsModel.String(),
+		Hardness:     common.Hardness,
+		PhysicsXR:    common.PhysicsXR,
+		PhysicsZ:     common.PhysicsZ,
+
+		Vulnerabilities:     []string{},
+		PrimaryDoubleDamage: common.SpecialVulnerabilities.PrimaryValue(),
+		SuperQuadDamage:     common.SpecialVulnerabilities.SuperValue(),
+		Defense:             common.Defense,
+		Toughness:           common.Toughness,
+
+		Flags:       []string{},
+		LightType:   common.Flags.LightType().String(),
+		UseMode:     common.Flags.UseMode().String(),
+		MfdOrMeshID: common.MfdOrMeshID,
+
+		BitmapNumber:    common.Bitmap3D.BitmapNumber(),
+		FrameNumber:     common.Bitmap3D.FrameNumber(),
+		BitmapAnimation: common.Bitmap3D.Animation(),
+		BitmapRepeat:    common.Bitmap3D.Repeat(),
+
+		DestroyEffect:    common.DestroyEffect.Value(),
+		DestroySound:     common.DestroyEffect.PlaySound(),
+		DestroyExplosion: common.DestroyEffect.ShowExplosion(),
+	}
+	for _, damageType := range object.DamageTypes() {
+		if common.Vulnerabilities.Has(damageType) {
+			sheet.Vulnerabilities = append(sheet.Vulnerabilities, damageType.String())
+		}
+	}
+	for _, flag := range object.CommonFlags() {
+		if common.Flags.Has(flag) {
+			sheet.Flags = append(sheet.Flags, flag.String())
+		}
+	}
+	return sheet
+}
+
+func objectSheetFieldsOf(instance *interpreters.Instance) map[string]int64 {
+	result := make(map[string]int64)
+	var process func(string, *interpreters.Instance)
+	process = func(path string, inst *interpreters.Instance) {
+		for _, key := range inst.Keys() {
+			result[path+key] = objectSheetFieldValue(inst, key)
+		}
+		for _, key := range inst.ActiveRefinements() {
+			process(path+key+".", inst.Refined(key))
+		}
+	}
+	process("", instance)
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
+
+// objectSheetFieldRange describes the valid values of an interpreter field.
+type objectSheetFieldRange struct {
+	minValue, maxValue int64
+	enumValues         map[uint32]string
+	bitMask            uint32
+}
+
+func (fieldRange objectSheetFieldRange) signed(raw uint32) int64 {
+	value := int64(raw)
+	if (fieldRange.minValue >= 0) || (value <= fieldRange.maxValue) {
+		return value
+	}
+	bits := uint(8)
+	for (bits < 32) && (int64(1)<<bits <= value) {
+		bits += 8
+	}
+	return value - int64(1)<<bits
+}
+
+func (fieldRange objectSheetFieldRange) check(value int64) error {
+	switch {
+	case fieldRange.enumValues != nil:
+		if _, known := fieldRange.enumValues[uint32(value)]; !known || (value < 0) {
+			return fmt.Errorf("value %d is not a known enumeration value", value)
+		}
+	case fieldRange.bitMask != 0:
+		if (value < 0) || ((uint32(value) & ^fieldRange.bitMask) != 0) {
+			return fmt.Errorf("value %d has bits outside of mask 0x%X", value, fieldRange.bitMask)
+		}
+	case (value < fieldRange.minValue) || (value > fieldRange.maxValue):
+		return fmt.Errorf("value %d is outside of range [%d, %d]", value, fieldRange.minValue, fieldRange.maxValue)
+	}
+	return nil
+}
+
+func objectSheetFieldRangeOf(inst *interpreters.Instance, key string) objectSheetFieldRange {
+	var fieldRange objectSheetFieldRange
+	simplifier := interpreters.NewSimplifier(func(minValue, maxValue int64, formatter interpreters.RawValueFormatter) {
+		fieldRange.minValue = minValue
+		fieldRange.maxValue = maxValue
+	})
+	simplifier.SetEnumValueHandler(func(values map[uint32]string) {
+		fieldRange.enumValues = values
+	})
+	simplifier.SetBitfieldHandler(func(values map[uint32]string) {
+		for mask := range values {
+			fieldRange.bitMask |= mask
+		}
+	})
+	inst.Describe(key, simplifier)
+	return fieldRange
+}
+
+func objectSheetFieldValue(inst *interpreters.Instance, key string) int64 {
+	return objectSheetFieldRangeOf(inst, key).signed(inst.Get(key))
+}
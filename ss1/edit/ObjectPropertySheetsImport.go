@@ -0,0 +1,319 @@
+package edit
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/inkyblackness/hacked/ss1/content/interpreters"
+	"github.com/inkyblackness/hacked/ss1/content/object"
+	"github.com/inkyblackness/hacked/ss1/content/object/objprop"
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/world/ids"
+)
+
+// ObjectPropertySheetIssue describes a problem of a sheet that prevents it from being applied.
+type ObjectPropertySheetIssue struct {
+	Triple  object.Triple
+	Field   string
+	Message string
+}
+
+// String returns a textual description of the issue.
+func (issue ObjectPropertySheetIssue) String() string {
+	if len(issue.Field) == 0 {
+		return fmt.Sprintf("%v: %v", issue.Triple, issue.Message)
+	}
+	return fmt.Sprintf("%v %v: %v", issue.Triple, issue.Field, issue.Message)
+}
+
+// ObjectNameChange describes a new name for an object type.
+type ObjectNameChange struct {
+	// Key identifies the name. It refers to either ids.ObjectLongNames or ids.ObjectShortNames.
+	Key     resource.Key
+	OldName string
+	NewName string
+	// OldExisting is set if the name was available before. Otherwise, OldName is empty and undoing
+	// the change shall remove the entry again.
+	OldExisting bool
+}
+
+// ObjectPropertySheetChange describes the modifications a sheet applies to one object type.
+type ObjectPropertySheetChange struct {
+	Triple        object.Triple
+	OldProperties object.Properties
+	NewProperties object.Properties
+	Names         []ObjectNameChange
+}
+
+// PropertiesChanged returns true if the new properties differ from the old ones.
+func (change ObjectPropertySheetChange) PropertiesChanged() bool {
+	return !objectPropertiesEqual(change.OldProperties, change.NewProperties)
+}
+
+// Changes compares the sheets against the given table and names, and returns the resulting modifications.
+// Object types without any difference are not returned.
+//
+// Each sheet is validated against the object property schema. Sheets with unknown triples, unknown
+// field names, or values out of range, are not returned as change and are reported as issues instead.
+// Fields not listed in a sheet keep their current value. If the common properties are listed, they must be complete.
+func (sheets ObjectPropertySheets) Changes(table object.PropertiesTable,
+	names ObjectNameSource) ([]ObjectPropertySheetChange, []ObjectPropertySheetIssue) {
+	var changes []ObjectPropertySheetChange
+	var issues []ObjectPropertySheetIssue
+	handled := make(map[object.Triple]bool)
+	for _, sheet := range sheets.Objects {
+		triple := sheet.Triple()
+		if (sheet.Class > 0xFF) || (sheet.Subclass > 0xFF) || (sheet.Type > 0xFF) || !table.Defines(triple) {
+			issues = append(issues, ObjectPropertySheetIssue{
+				Triple:  triple,
+				Message: fmt.Sprintf("unknown object type %d/%d/%d", sheet.Class, sheet.Subclass, sheet.Type),
+			})
+			continue
+		}
+		if handled[triple] {
+			issues = append(issues, ObjectPropertySheetIssue{Triple: triple, Message: "listed more than once"})
+			continue
+		}
+		handled[triple] = true
+		change, sheetIssues := sheet.changeFor(table, names)
+		if len(sheetIssues) > 0 {
+			issues = append(issues, sheetIssues...)
+			continue
+		}
+		if change.PropertiesChanged() || (len(change.Names) > 0) {
+			changes = append(changes, change)
+		}
+	}
+	return changes, issues
+}
+
+func (sheet ObjectPropertySheet) changeFor(table object.PropertiesTable,
+	names ObjectNameSource) (ObjectPropertySheetChange, []ObjectPropertySheetIssue) {
+	triple := sheet.Triple()
+	prop, _ := table.ForObject(triple)
+	change := ObjectPropertySheetChange{
+		Triple:        triple,
+		OldProperties: prop.Clone(),
+		NewProperties: prop.Clone(),
+	}
+	var issues []ObjectPropertySheetIssue
+	addIssue := func(field string, err error) {
+		issues = append(issues, ObjectPropertySheetIssue{Triple: triple, Field: field, Message: err.Error()})
+	}
+
+	if sheet.Common != nil {
+		for field, err := range sheet.Common.applyTo(&change.NewProperties.Common) {
+			addIssue("common."+field, err)
+		}
+	}
+	for field, err := range applyObjectSheetFields(objprop.GenericProperties(triple.Class, change.NewProperties.Generic), sheet.Generic) {
+		addIssue("generic."+field, err)
+	}
+	for field, err := range applyObjectSheetFields(objprop.SpecificProperties(triple, change.NewProperties.Specific), sheet.Specific) {
+		addIssue("specific."+field, err)
+	}
+	nameChanges, nameIssues := sheet.nameChanges(table.TripleIndex(triple), names)
+	change.Names = nameChanges
+	for field, err := range nameIssues {
+		addIssue("names."+field, err)
+	}
+	sort.Slice(issues, func(a, b int) bool { return issues[a].Field < issues[b].Field })
+	return change, issues
+}
+
+func (sheet ObjectPropertySheet) nameChanges(index int, source ObjectNameSource) ([]ObjectNameChange, map[string]error) {
+	var changes []ObjectNameChange
+	issues := make(map[string]error)
+	langNames := make([]string, 0, len(sheet.Names))
+	for langName := range sheet.Names {
+		langNames = append(langNames, langName)
+	}
+	sort.Strings(langNames)
+	for _, langName := range langNames {
+		lang, known := objectSheetLanguage(langName)
+		if !known {
+			issues[langName] = fmt.Errorf("unknown language")
+			continue
+		}
+		entry := sheet.Names[langName]
+		for _, name := range []struct {
+			id    resource.ID
+			field string
+			value string
+		}{{id: ids.ObjectLongNames, field: "long", value: entry.Long}, {id: ids.ObjectShortNames, field: "short", value: entry.Short}} {
+			if err := ValidateObjectName(name.value, name.id == ids.ObjectLongNames); err != nil {
+				issues[langName+"."+name.field] = err
+				continue
+			}
+			key := resource.KeyOf(name.id, lang, index)
+			oldName, err := source.Text(key)
+			if ((err != nil) && (len(name.value) == 0)) || (oldName == name.value) {
+				continue
+			}
+			changes = append(changes, ObjectNameChange{Key: key, OldName: oldName, NewName: name.value, OldExisting: err == nil})
+		}
+	}
+	return changes, issues
+}
+
+func objectSheetLanguage(name string) (resource.Language, bool) {
+	for _, lang := range resource.Languages() {
+		if lang.String() == name {
+			return lang, true
+		}
+	}
+	return resource.LangAny, false
+}
+
+func applyObjectSheetFields(instance *interpreters.Instance, fields map[string]int64) map[string]error {
+	issues := make(map[string]error)
+	for path, value := range fields {
+		keys := strings.Split(path, ".")
+		inst := instance
+		for _, subKey := range keys[:len(keys)-1] {
+			inst = inst.Refined(subKey)
+		}
+		key := keys[len(keys)-1]
+		if !objectSheetHasKey(inst, key) {
+			issues[path] = fmt.Errorf("unknown field")
+			continue
+		}
+		fieldRange := objectSheetFieldRangeOf(inst, key)
+		if fieldRange.signed(inst.Get(key)) == value {
+			continue
+		}
+		if err := fieldRange.check(value); err != nil {
+			issues[path] = err
+			continue
+		}
+		inst.Set(key, uint32(value))
+	}
+	return issues
+}
+
+func objectSheetHasKey(inst *interpreters.Instance, key string) bool {
+	for _, existing := range inst.Keys() {
+		if existing == key {
+			return true
+		}
+	}
+	return false
+}
+
+func (sheet ObjectSheetCommon) applyTo(common *object.CommonProperties) map[string]error {
+	issues := make(map[string]error)
+	checkLimit := func(field string, value, current, limit int) bool {
+		if value == current {
+			return false
+		}
+		if (value < 0) || (value > limit) {
+			issues[field] = fmt.Errorf("value %d is outside of range [0, %d]", value, limit)
+			return false
+		}
+		return true
+	}
+	enumValue := func(field string, name string, limit int, stringer func(int) string) (int, bool) {
+		for value := 0; value < limit; value++ {
+			if stringer(value) == name {
+				return value, true
+			}
+		}
+		issues[field] = fmt.Errorf("unknown value %q", name)
+		return 0, false
+	}
+
+	common.Mass = sheet.Mass
+	common.Hitpoints = sheet.Hitpoints
+	common.Armor = sheet.Armor
+	if value, ok := enumValue("renderType", sheet.RenderType, 0x100,
+		func(value int) string { return object.RenderType(value).String() }); ok {
+		common.RenderType = object.RenderType(value)
+	}
+	if value, ok := enumValue("physicsModel", sheet.PhysicsModel, 0x100,
+		func(value int) string { return object.PhysicsModel(value).String() }); ok {
+		common.PhysicsModel = object.PhysicsModel(value)
+	}
+	if checkLimit("hardness", int(sheet.Hardness), int(common.Hardness), object.HardnessLimit) {
+		common.Hardness = sheet.Hardness
+	}
+	if checkLimit("physicsXR", int(sheet.PhysicsXR), int(common.PhysicsXR), object.PhysicsXRLimit) {
+		common.PhysicsXR = sheet.PhysicsXR
+	}
+	common.PhysicsZ = sheet.PhysicsZ
+
+	var vulnerabilities object.DamageTypeMask
+	for _, name := range sheet.Vulnerabilities {
+		if value, ok := enumValue("vulnerabilities", name, len(object.DamageTypes()),
+			func(value int) string { return object.DamageType(value).String() }); ok {
+			vulnerabilities = vulnerabilities.With(object.DamageType(value))
+		}
+	}
+	common.Vulnerabilities = vulnerabilities
+	if checkLimit("primaryDoubleDamage", sheet.PrimaryDoubleDamage,
+		common.SpecialVulnerabilities.PrimaryValue(), object.SpecialDamageTypeLimit) {
+		common.SpecialVulnerabilities = common.SpecialVulnerabilities.WithPrimaryValue(sheet.PrimaryDoubleDamage)
+	}
+	if checkLimit("superQuadDamage", sheet.SuperQuadDamage,
+		common.SpecialVulnerabilities.SuperValue(), object.SpecialDamageTypeLimit) {
+		common.SpecialVulnerabilities = common.SpecialVulnerabilities.WithSuperValue(sheet.SuperQuadDamage)
+	}
+	common.Defense = sheet.Defense
+	common.Toughness = sheet.Toughness
+
+	flags := common.Flags
+	for _, flag := range object.CommonFlags() {
+		flags = flags.Without(flag)
+	}
+	for _, name := range sheet.Flags {
+		found := false
+		for _, flag := range object.CommonFlags() {
+			if flag.String() == name {
+				flags = flags.With(flag)
+				found = true
+			}
+		}
+		if !found {
+			issues["flags"] = fmt.Errorf("unknown flag %q", name)
+		}
+	}
+	if value, ok := enumValue("lightType", sheet.LightType, 4,
+		func(value int) string { return object.LightType(value).String() }); ok {
+		flags = flags.WithLightType(object.LightType(value))
+	}
+	if value, ok := enumValue("useMode", sheet.UseMode, 4,
+		func(value int) string { return object.UseMode(value).String() }); ok {
+		flags = flags.WithUseMode(object.UseMode(value))
+	}
+	common.Flags = flags
+	common.MfdOrMeshID = sheet.MfdOrMeshID
+
+	bmp := common.Bitmap3D
+	if checkLimit("bitmapNumber", int(sheet.BitmapNumber), int(bmp.BitmapNumber()),
+		int(object.Bitmap3DBitmapNumberLimit)) {
+		bmp = bmp.WithBitmapNumber(sheet.BitmapNumber)
+	}
+	if checkLimit("frameNumber", int(sheet.FrameNumber), int(bmp.FrameNumber()), int(object.Bitmap3DFrameNumberLimit)) {
+		bmp = bmp.WithFrameNumber(sheet.FrameNumber)
+	}
+	common.Bitmap3D = bmp.WithAnimation(sheet.BitmapAnimation).WithRepeat(sheet.BitmapRepeat)
+
+	if checkLimit("destroyEffect", int(sheet.DestroyEffect), int(common.DestroyEffect.Value()),
+		int(object.DestroyEffectValueLimit)) {
+		common.DestroyEffect = common.DestroyEffect.WithValue(sheet.DestroyEffect)
+	}
+	if common.DestroyEffect.PlaySound() != sheet.DestroySound {
+		common.DestroyEffect = common.DestroyEffect.WithSound(sheet.DestroySound)
+	}
+	if common.DestroyEffect.ShowExplosion() != sheet.DestroyExplosion {
+		common.DestroyEffect = common.DestroyEffect.WithExplosion(sheet.DestroyExplosion)
+	}
+
+	return issues
+}
+
+func objectPropertiesEqual(a, b object.Properties) bool {
+	return (a.Common == b.Common) &&
+		(string(a.Generic) == string(b.Generic)) &&
+		(string(a.Specific) == string(b.Specific))
+}
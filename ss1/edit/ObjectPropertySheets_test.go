@@ -0,0 +1,248 @@
+package edit_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/inkyblackness/hacked/ss1/content/object"
+	"github.com/inkyblackness/hacked/ss1/edit"
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/world/ids"
+)
+
+type nameMap map[resource.Key]string
+
+func (names nameMap) Text(key resource.Key) (string, error) {
+	text, existing := names[key]
+	if !existing {
+		return "", errors.New("not existing")
+	}
+	return text, nil
+}
+
+type ObjectPropertySheetsSuite struct {
+	suite.Suite
+
+	table  object.PropertiesTable
+	names  nameMap
+	sheets edit.ObjectPropertySheets
+}
+
+func TestObjectPropertySheetsSuite(t *testing.T) {
+	suite.Run(t, new(ObjectPropertySheetsSuite))
+}
+
+func (suite *ObjectPropertySheetsSuite) SetupTest() {
+	suite.table = object.StandardPropertiesTable()
+	suite.names = make(nameMap)
+	suite.sheets = edit.ObjectPropertySheets{}
+}
+
+func (suite *ObjectPropertySheetsSuite) TestSheetsContainAllObjectTypes() {
+	suite.whenCreatingSheets()
+
+	suite.Require().Equal(len(suite.table.Triples()), len(suite.sheets.Objects))
+	for index, triple := range suite.table.Triples() {
+		suite.Assert().Equal(triple, suite.sheets.Objects[index].Triple(), "wrong triple at %d", index)
+	}
+}
+
+func (suite *ObjectPropertySheetsSuite) TestCommonPropertiesAreDecoded() {
+	triple := object.TripleFrom(0, 0, 1)
+	suite.givenProperties(triple, func(prop *object.Properties) {
+		prop.Common.Mass = -20
+		prop.Common.RenderType = object.RenderTypeBitmap
+		prop.Common.Vulnerabilities = object.DamageTypeMask(0).With(object.DamageTypeGas)
+		prop.Common.Flags = object.CommonFlagField(0).With(object.CommonFlagSolid).WithUseMode(object.UseModeUse)
+		prop.Common.Bitmap3D = object.Bitmap3D(0).WithFrameNumber(3)
+	})
+
+	suite.whenCreatingSheets()
+
+	common := suite.thenSheetFor(triple).Common
+	suite.Assert().Equal(int32(-20), common.Mass)
+	suite.Assert().Equal("Bitmap", common.RenderType)
+	suite.Assert().Equal([]string{"Gas"}, common.Vulnerabilities)
+	suite.Assert().Equal([]string{"Solid"}, common.Flags)
+	suite.Assert().Equal("Use", common.UseMode)
+	suite.Assert().Equal(uint16(3), common.FrameNumber)
+}
+
+func (suite *ObjectPropertySheetsSuite) TestClassPropertiesAreNamedFields() {
+	triple := object.TripleFrom(0, 0, 0)
+
+	suite.whenCreatingSheets()
+
+	sheet := suite.thenSheetFor(triple)
+	suite.Assert().NotEmpty(sheet.Generic)
+	for key := range sheet.Generic {
+		suite.Assert().NotEmpty(key)
+	}
+}
+
+func (suite *ObjectPropertySheetsSuite) TestNamesAreListedPerLanguage() {
+	triple := object.TripleFrom(0, 0, 2)
+	index := suite.table.TripleIndex(triple)
+	suite.givenName(resource.KeyOf(ids.ObjectLongNames, resource.LangGerman, index), "Lang")
+	suite.givenName(resource.KeyOf(ids.ObjectShortNames, resource.LangGerman, index), "Kurz")
+
+	suite.whenCreatingSheets()
+
+	sheet := suite.thenSheetFor(triple)
+	suite.Assert().Equal(map[string]edit.ObjectSheetNames{
+		resource.LangGerman.String(): {Long: "Lang", Short: "Kurz"},
+	}, sheet.Names)
+}
+
+func (suite *ObjectPropertySheetsSuite) TestSheetsCanBeEncodedAndDecoded() {
+	suite.givenName(resource.KeyOf(ids.ObjectLongNames, resource.LangDefault, 0), "Name")
+	suite.whenCreatingSheets()
+
+	buf := bytes.NewBuffer(nil)
+	err := suite.sheets.Encode(buf)
+	suite.Require().Nil(err, "no error expected encoding")
+	decoded, err := edit.DecodeObjectPropertySheets(bytes.NewReader(buf.Bytes()))
+	suite.Require().Nil(err, "no error expected decoding")
+
+	suite.Assert().Equal(suite.sheets, decoded)
+}
+
+func (suite *ObjectPropertySheetsSuite) TestUnchangedSheetsResultInNoChanges() {
+	suite.whenCreatingSheets()
+
+	changes, issues := suite.sheets.Changes(suite.table, suite.names)
+
+	suite.Assert().Empty(changes, "no changes expected")
+	suite.Assert().Empty(issues, "no issues expected")
+}
+
+func (suite *ObjectPropertySheetsSuite) TestChangedValuesAreReported() {
+	triple := object.TripleFrom(0, 0, 1)
+	suite.whenCreatingSheets()
+	suite.givenSheetModification(triple, func(sheet *edit.ObjectPropertySheet) {
+		sheet.Common.Mass = 1234
+		sheet.Common.Flags = append(sheet.Common.Flags, "Useless")
+	})
+
+	changes, issues := suite.sheets.Changes(suite.table, suite.names)
+
+	suite.Require().Empty(issues, "no issues expected")
+	suite.Require().Equal(1, len(changes), "one change expected")
+	suite.Assert().Equal(triple, changes[0].Triple)
+	suite.Assert().True(changes[0].PropertiesChanged())
+	suite.Assert().Equal(int32(1234), changes[0].NewProperties.Common.Mass)
+	suite.Assert().True(changes[0].NewProperties.Common.Flags.Has(object.CommonFlagUseless))
+}
+
+func (suite *ObjectPropertySheetsSuite) TestChangedNamesAreReported() {
+	triple := object.TripleFrom(0, 0, 2)
+	key := resource.KeyOf(ids.ObjectShortNames, resource.LangDefault, suite.table.TripleIndex(triple))
+	suite.givenName(key, "old")
+	suite.whenCreatingSheets()
+	suite.givenSheetModification(triple, func(sheet *edit.ObjectPropertySheet) {
+		names := sheet.Names[resource.LangDefault.String()]
+		names.Short = "new"
+		sheet.Names[resource.LangDefault.String()] = names
+	})
+
+	changes, issues := suite.sheets.Changes(suite.table, suite.names)
+
+	suite.Require().Empty(issues, "no issues expected")
+	suite.Require().Equal(1, len(changes), "one change expected")
+	suite.Assert().False(changes[0].PropertiesChanged())
+	suite.Assert().Equal([]edit.ObjectNameChange{{Key: key, OldName: "old", NewName: "new", OldExisting: true}}, changes[0].Names)
+}
+
+func (suite *ObjectPropertySheetsSuite) TestNamesForMissingEntriesAreReportedAsNotExisting() {
+	triple := object.TripleFrom(0, 0, 2)
+	key := resource.KeyOf(ids.ObjectShortNames, resource.LangGerman, suite.table.TripleIndex(triple))
+	suite.whenCreatingSheets()
+	suite.givenSheetModification(triple, func(sheet *edit.ObjectPropertySheet) {
+		sheet.Names = map[string]edit.ObjectSheetNames{resource.LangGerman.String(): {Short: "neu"}}
+	})
+
+	changes, issues := suite.sheets.Changes(suite.table, suite.names)
+
+	suite.Require().Empty(issues, "no issues expected")
+	suite.Require().Equal(1, len(changes), "one change expected")
+	suite.Assert().Equal([]edit.ObjectNameChange{{Key: key, OldName: "", NewName: "neu", OldExisting: false}}, changes[0].Names)
+}
+
+func (suite *ObjectPropertySheetsSuite) TestNamesBeyondSlotLengthAreReportedAsIssues() {
+	triple := object.TripleFrom(0, 0, 2)
+	suite.givenName(resource.KeyOf(ids.ObjectShortNames, resource.LangDefault, suite.table.TripleIndex(triple)), "old")
+	suite.whenCreatingSheets()
+	suite.givenSheetModification(triple, func(sheet *edit.ObjectPropertySheet) {
+		names := sheet.Names[resource.LangDefault.String()]
+		names.Short = strings.Repeat("x", edit.MaxObjectShortNameLength+1)
+		sheet.Names[resource.LangDefault.String()] = names
+	})
+
+	changes, issues := suite.sheets.Changes(suite.table, suite.names)
+
+	suite.Assert().Empty(changes, "no changes expected")
+	suite.Require().Equal(1, len(issues), "one issue expected")
+	suite.Assert().Equal("names."+resource.LangDefault.String()+".short", issues[0].Field)
+}
+
+func (suite *ObjectPropertySheetsSuite) TestInvalidSheetsAreReportedAsIssues() {
+	suite.whenCreatingSheets()
+	suite.givenSheetModification(object.TripleFrom(0, 0, 0), func(sheet *edit.ObjectPropertySheet) {
+		sheet.Common.Hardness = object.HardnessLimit + 1
+	})
+	suite.givenSheetModification(object.TripleFrom(0, 0, 1), func(sheet *edit.ObjectPropertySheet) {
+		sheet.Generic["doesNotExist"] = 1
+	})
+	suite.givenSheetModification(object.TripleFrom(0, 0, 2), func(sheet *edit.ObjectPropertySheet) {
+		sheet.Common.RenderType = "Unheard Of"
+		sheet.Common.Mass = 10
+	})
+	suite.sheets.Objects = append(suite.sheets.Objects, edit.ObjectPropertySheet{Class: 0, Subclass: 20, Type: 0})
+
+	changes, issues := suite.sheets.Changes(suite.table, suite.names)
+
+	suite.Assert().Empty(changes, "no changes expected")
+	fields := make([]string, len(issues))
+	for index, issue := range issues {
+		fields[index] = issue.Field
+	}
+	suite.Assert().Equal([]string{"common.hardness", "generic.doesNotExist", "common.renderType", ""}, fields)
+}
+
+func (suite *ObjectPropertySheetsSuite) givenSheetModification(triple object.Triple, modifier func(*edit.ObjectPropertySheet)) {
+	for index := range suite.sheets.Objects {
+		if suite.sheets.Objects[index].Triple() == triple {
+			modifier(&suite.sheets.Objects[index])
+			return
+		}
+	}
+	suite.Require().Fail("sheet not found", "triple %v", triple)
+}
+
+func (suite *ObjectPropertySheetsSuite) givenProperties(triple object.Triple, modifier func(*object.Properties)) {
+	prop, err := suite.table.ForObject(triple)
+	suite.Require().Nil(err, "no error expected for triple %v", triple)
+	modifier(prop)
+}
+
+func (suite *ObjectPropertySheetsSuite) givenName(key resource.Key, name string) {
+	suite.names[key] = name
+}
+
+func (suite *ObjectPropertySheetsSuite) whenCreatingSheets() {
+	suite.sheets = edit.ObjectPropertySheetsFrom(suite.table, suite.names)
+}
+
+func (suite *ObjectPropertySheetsSuite) thenSheetFor(triple object.Triple) edit.ObjectPropertySheet {
+	for _, sheet := range suite.sheets.Objects {
+		if sheet.Triple() == triple {
+			return sheet
+		}
+	}
+	suite.Require().Fail("sheet not found", "triple %v", triple)
+	return edit.ObjectPropertySheet{}
+}
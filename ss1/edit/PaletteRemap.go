@@ -0,0 +1,123 @@
+package edit
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/inkyblackness/hacked/ss1/content/bitmap"
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/world"
+)
+
+// BitmapBlockChange describes the new data of one block of a bitmap resource.
+type BitmapBlockChange struct {
+	Key  resource.Key
+	Data []byte
+}
+
+// BitmapFilter decides whether the bitmaps of the identified resource are considered.
+// A nil filter considers all bitmap resources.
+type BitmapFilter func(id resource.ID) bool
+
+// RemappedBitmaps applies the remap to the pixels of the bitmaps the mod provides, including those of the world.
+// Only the resources accepted by the filter are considered.
+// It returns the changes for those bitmap blocks that are affected. The mod is not modified.
+//
+// Bitmaps with a private palette are not considered, as they do not use the game palette.
+// An error is returned if a bitmap can not be decoded, or if a changed bitmap would still
+// reference an index that the remap frees.
+func RemappedBitmaps(mod *world.Mod, remap bitmap.PaletteRemap, filter BitmapFilter) ([]BitmapBlockChange, error) {
+	var changes []BitmapBlockChange
+	freed := remap.Freed()
+	err := forEachPaletteBitmap(mod, filter, func(key resource.Key, bmp *bitmap.Bitmap) error {
+		if !remap.Apply(bmp.Pixels) {
+			return nil
+		}
+		data := bitmap.Encode(bmp, 0)
+		check, err := bitmap.Decode(bytes.NewReader(data))
+		if err != nil {
+			return fmt.Errorf("bitmap %v can not be encoded: %v", key, err)
+		}
+		if referencesAny(check.Pixels, freed) {
+			return fmt.Errorf("bitmap %v still references a freed index", key)
+		}
+		changes = append(changes, BitmapBlockChange{Key: key, Data: data})
+		return nil
+	})
+	return changes, err
+}
+
+// UsedPaletteIndices returns the palette indices, in ascending order, that the bitmaps accepted by the filter use.
+// Bitmaps with a private palette are not considered.
+func UsedPaletteIndices(mod *world.Mod, filter BitmapFilter) ([]byte, error) {
+	var used [256]bool
+	err := forEachPaletteBitmap(mod, filter, func(key resource.Key, bmp *bitmap.Bitmap) error {
+		for _, pixel := range bmp.Pixels {
+			used[pixel] = true
+		}
+		return nil
+	})
+	var indices []byte
+	for index, isUsed := range used {
+		if isUsed {
+			indices = append(indices, byte(index))
+		}
+	}
+	return indices, err
+}
+
+// forEachPaletteBitmap calls the handler for each non-empty bitmap block that uses the game palette
+// and is accepted by the filter.
+// Resources are visited in the language of their providing origin, so that language agnostic resources
+// are visited only once.
+func forEachPaletteBitmap(mod *world.Mod, filter BitmapFilter, handler func(key resource.Key, bmp *bitmap.Bitmap) error) error {
+	var err error
+	mod.EnumerateResources(false, func(provided world.ProvidedResource) bool {
+		if (provided.Origin.Language != provided.Language) || ((filter != nil) && !filter(provided.ID)) {
+			return true
+		}
+		view, selectErr := mod.LocalizedResources(provided.Language).Select(provided.ID)
+		if (selectErr != nil) || (view.ContentType() != resource.Bitmap) {
+			return true
+		}
+		for index := 0; index < view.BlockCount(); index++ {
+			key := resource.KeyOf(provided.ID, provided.Language, index)
+			reader, blockErr := view.Block(index)
+			if blockErr != nil {
+				continue
+			}
+			data, readErr := ioutil.ReadAll(reader)
+			if (readErr != nil) || (len(data) == 0) {
+				continue
+			}
+			bmp, decodeErr := bitmap.Decode(bytes.NewReader(data))
+			if decodeErr != nil {
+				err = fmt.Errorf("bitmap %v can not be decoded: %v", key, decodeErr)
+				return false
+			}
+			if bmp.Palette != nil {
+				continue
+			}
+			err = handler(key, bmp)
+			if err != nil {
+				return false
+			}
+		}
+		return true
+	})
+	return err
+}
+
+func referencesAny(pixels []byte, indices []byte) bool {
+	var wanted [256]bool
+	for _, index := range indices {
+		wanted[index] = true
+	}
+	for _, pixel := range pixels {
+		if wanted[pixel] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+package edit_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/content/bitmap"
+	"github.com/inkyblackness/hacked/ss1/edit"
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/world"
+)
+
+func remapTestBitmap(pal *bitmap.Palette, bitmapType bitmap.Type, pixels ...byte) []byte {
+	bmp := bitmap.Bitmap{
+		Header: bitmap.Header{
+			Type:   bitmapType,
+			Width:  int16(len(pixels)),
+			Height: 1,
+			Stride: uint16(len(pixels)),
+		},
+		Pixels:  pixels,
+		Palette: pal,
+	}
+	return bitmap.Encode(&bmp, 0)
+}
+
+func remapTestMod(t *testing.T, blocks ...[]byte) *world.Mod {
+	mod := world.NewMod(func([]resource.ID, []resource.ID) {}, func() {})
+	var store resource.Store
+	_ = store.Put(resource.ID(0x0200), resource.Resource{
+		Properties: resource.Properties{Compound: true, ContentType: resource.Bitmap},
+		Blocks:     resource.BlocksFrom(blocks),
+	})
+	manifest := mod.World()
+	err := manifest.InsertEntry(0, &world.ManifestEntry{
+		ID:        "world",
+		Resources: []resource.LocalizedResources{{ID: "bitmaps.res", Language: resource.LangAny, Viewer: store}},
+	})
+	require.Nil(t, err)
+	return mod
+}
+
+func TestRemappedBitmapsReturnsChangedBlocksOnly(t *testing.T) {
+	var privatePalette bitmap.Palette
+	mod := remapTestMod(t,
+		remapTestBitmap(nil, bitmap.TypeFlat8Bit, 0x00, 0x40, 0x41),
+		remapTestBitmap(nil, bitmap.TypeFlat8Bit, 0x41, 0x42),
+		remapTestBitmap(&privatePalette, bitmap.TypeFlat8Bit, 0x40),
+		nil,
+		remapTestBitmap(nil, bitmap.TypeCompressed8Bit, 0x40, 0x40, 0x40, 0x43))
+	remap := bitmap.IdentityPaletteRemap()
+	remap[0x40] = 0x30
+
+	changes, err := edit.RemappedBitmaps(mod, remap, nil)
+	require.Nil(t, err, "no error expected")
+
+	require.Equal(t, 2, len(changes))
+	assert.Equal(t, resource.KeyOf(0x0200, resource.LangAny, 0), changes[0].Key)
+	assert.Equal(t, resource.KeyOf(0x0200, resource.LangAny, 4), changes[1].Key)
+	bmp, err := bitmap.Decode(bytes.NewReader(changes[1].Data))
+	require.Nil(t, err, "no error expected decoding change")
+	assert.Equal(t, bitmap.TypeCompressed8Bit, bmp.Header.Type)
+	assert.Equal(t, []byte{0x30, 0x30, 0x30, 0x43}, bmp.Pixels)
+}
+
+func TestRemappedBitmapsLeaveNoReferenceToFreedIndices(t *testing.T) {
+	mod := remapTestMod(t,
+		remapTestBitmap(nil, bitmap.TypeFlat8Bit, 0x40, 0x50),
+		remapTestBitmap(nil, bitmap.TypeFlat8Bit, 0x50, 0x51))
+	remap := bitmap.IdentityPaletteRemap()
+	remap[0x40] = 0x30
+	remap[0x50] = 0x31
+
+	used, err := edit.UsedPaletteIndices(mod, nil)
+	require.Nil(t, err, "no error expected")
+	assert.Equal(t, []byte{0x40, 0x50, 0x51}, used, "freed indices expected in use before remap")
+
+	changes, err := edit.RemappedBitmaps(mod, remap, nil)
+	require.Nil(t, err, "no error expected")
+	mod.Modify(func(modder world.Modder) {
+		for _, change := range changes {
+			modder.SetResourceBlock(change.Key.Lang, change.Key.ID, change.Key.Index, change.Data)
+		}
+	})
+
+	used, err = edit.UsedPaletteIndices(mod, nil)
+	require.Nil(t, err, "no error expected")
+	assert.Equal(t, []byte{0x30, 0x31, 0x51}, used)
+}
+
+func TestRemappedBitmapsConsidersFilteredResourcesOnly(t *testing.T) {
+	mod := remapTestMod(t, remapTestBitmap(nil, bitmap.TypeFlat8Bit, 0x40))
+	remap := bitmap.IdentityPaletteRemap()
+	remap[0x40] = 0x30
+
+	changes, err := edit.RemappedBitmaps(mod, remap, func(id resource.ID) bool { return id != resource.ID(0x0200) })
+	require.Nil(t, err, "no error expected")
+	assert.Empty(t, changes)
+
+	used, err := edit.UsedPaletteIndices(mod, func(id resource.ID) bool { return id != resource.ID(0x0200) })
+	require.Nil(t, err, "no error expected")
+	assert.Empty(t, used)
+}
+
+func TestRemappedBitmapsReturnsErrorForUndecodableBitmap(t *testing.T) {
+	mod := remapTestMod(t, []byte{0x01, 0x02})
+
+	_, err := edit.RemappedBitmaps(mod, bitmap.IdentityPaletteRemap(), nil)
+
+	assert.Error(t, err)
+}
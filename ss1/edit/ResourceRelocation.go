@@ -0,0 +1,212 @@
+package edit
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/inkyblackness/hacked/ss1/content/bitmap"
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/world"
+	"github.com/inkyblackness/hacked/ss1/world/ids"
+)
+
+// ErrResourceOccupied is returned if a resource shall be relocated to an identifier that is already in use.
+var ErrResourceOccupied = errors.New("resource identifier is occupied")
+
+// ResourceRelocationSetter modifies resources for a relocation.
+type ResourceRelocationSetter interface {
+	SetResourceBlocks(lang resource.Language, id resource.ID, data [][]byte)
+	SetResourceProperties(lang resource.Language, id resource.ID, properties resource.Properties)
+	DelResource(lang resource.Language, id resource.ID)
+}
+
+// resourceState is the content of one resource of the mod, in one language.
+type resourceState struct {
+	lang       resource.Language
+	id         resource.ID
+	exists     bool
+	properties resource.Properties
+	blocks     [][]byte
+}
+
+func (state resourceState) apply(setter ResourceRelocationSetter) {
+	if !state.exists {
+		setter.DelResource(state.lang, state.id)
+		return
+	}
+	setter.SetResourceBlocks(state.lang, state.id, state.blocks)
+	setter.SetResourceProperties(state.lang, state.id, state.properties)
+}
+
+// ResourceRelocation is a prepared move of a resource to another identifier, including the update
+// of all references to it. Use PrepareResourceRelocation() to create one.
+type ResourceRelocation struct {
+	// From is the identifier the resource is currently stored under.
+	From resource.ID
+	// To is the new identifier of the resource.
+	To resource.ID
+	// Updated lists the blocks that refer to the resource and are updated to refer to the new identifier.
+	Updated []resource.Key
+	// Unresolved describes the references to the resource that can not be updated.
+	Unresolved []string
+
+	oldStates []resourceState
+	newStates []resourceState
+}
+
+// Apply performs the relocation.
+func (relocation ResourceRelocation) Apply(setter ResourceRelocationSetter) {
+	for _, state := range relocation.newStates {
+		state.apply(setter)
+	}
+}
+
+// Revert restores the state before the relocation.
+func (relocation ResourceRelocation) Revert(setter ResourceRelocationSetter) {
+	for _, state := range relocation.oldStates {
+		state.apply(setter)
+	}
+}
+
+// PrepareResourceRelocation determines the changes to move a resource of the mod, in all languages,
+// to another identifier. The mod is not modified.
+//
+// Only the data of the mod is moved. Should the world provide the resource as well, its data becomes
+// visible again under the old identifier. Animations that refer to the resource for their frames are
+// updated to refer to the new identifier. References that are not stored in data, such as the fixed
+// identifiers the engine uses, can not be updated and are listed as unresolved.
+//
+// An error wrapping ErrResourceOccupied is returned if the new identifier is in use, unless
+// overwrite is set. In that case, the data of the mod under the new identifier is replaced.
+func PrepareResourceRelocation(mod *world.Mod, from, to resource.ID, overwrite bool) (ResourceRelocation, error) {
+	relocation := ResourceRelocation{From: from, To: to}
+	if from == to {
+		return relocation, errors.New("resource can not be relocated to itself")
+	}
+	languages := append([]resource.Language{resource.LangAny}, resource.Languages()...)
+	moved := false
+	for _, lang := range languages {
+		if mod.ModifiedResource(lang, from) != nil {
+			moved = true
+		}
+		if !overwrite && (len(mod.ResourceOrigins(lang, to)) > 0) {
+			return relocation, fmt.Errorf("%w: %v", ErrResourceOccupied, to)
+		}
+	}
+	if !moved {
+		return relocation, fmt.Errorf("resource %v is not part of the mod", from)
+	}
+
+	for _, lang := range languages {
+		source := modResourceState(mod, lang, from)
+		target := modResourceState(mod, lang, to)
+		if !source.exists && !target.exists {
+			continue
+		}
+		relocation.oldStates = append(relocation.oldStates, source, target)
+		moving := source
+		moving.id = to
+		relocation.newStates = append(relocation.newStates, resourceState{lang: lang, id: from}, moving)
+		if source.exists && providedByWorld(mod, lang, from) {
+			relocation.Unresolved = append(relocation.Unresolved,
+				fmt.Sprintf("the world provides %v in %v as well, its data becomes visible again", from, lang))
+		}
+	}
+	if _, known := ids.Info(from); known {
+		relocation.Unresolved = append(relocation.Unresolved,
+			fmt.Sprintf("the engine refers to %v (%s) by its identifier", from, ids.NameOf(from).Name))
+	}
+	relocation.updateAnimations(mod)
+	return relocation, nil
+}
+
+// updateAnimations adds the changes for all animations that refer to the relocated resource for their frames.
+func (relocation *ResourceRelocation) updateAnimations(mod *world.Mod) {
+	mod.EnumerateResources(false, func(provided world.ProvidedResource) bool {
+		if (provided.ID == relocation.From) || (provided.ID == relocation.To) ||
+			(provided.Origin.Language != provided.Language) {
+			return true
+		}
+		view, err := mod.LocalizedResources(provided.Language).Select(provided.ID)
+		if (err != nil) || (view.ContentType() != resource.Animation) {
+			return true
+		}
+		state := resourceState{
+			lang:       provided.Language,
+			id:         provided.ID,
+			exists:     true,
+			properties: resource.Properties{Compound: view.Compound(), ContentType: view.ContentType(), Compressed: view.Compressed()},
+			blocks:     make([][]byte, view.BlockCount()),
+		}
+		changed := false
+		for index := range state.blocks {
+			key := resource.KeyOf(provided.ID, provided.Language, index)
+			data := viewBlockData(view, index)
+			state.blocks[index] = data
+			if len(data) == 0 {
+				continue
+			}
+			anim, err := bitmap.ReadAnimation(bytes.NewReader(data))
+			if err != nil {
+				relocation.Unresolved = append(relocation.Unresolved,
+					fmt.Sprintf("animation %v can not be read and may refer to %v: %v", key, relocation.From, err))
+				continue
+			}
+			if anim.ResourceID != relocation.From {
+				continue
+			}
+			anim.ResourceID = relocation.To
+			buf := bytes.NewBuffer(nil)
+			if err := bitmap.WriteAnimation(buf, anim); err != nil {
+				relocation.Unresolved = append(relocation.Unresolved,
+					fmt.Sprintf("animation %v can not be updated: %v", key, err))
+				continue
+			}
+			state.blocks[index] = buf.Bytes()
+			relocation.Updated = append(relocation.Updated, key)
+			changed = true
+		}
+		if changed {
+			relocation.oldStates = append(relocation.oldStates, modResourceState(mod, provided.Language, provided.ID))
+			relocation.newStates = append(relocation.newStates, state)
+		}
+		return true
+	})
+}
+
+// providedByWorld returns true if the world has the identified resource in the given language.
+func providedByWorld(mod *world.Mod, lang resource.Language, id resource.ID) bool {
+	for _, origin := range mod.ResourceOrigins(lang, id) {
+		if (origin.Entry != world.ModOriginEntry) && (origin.Language == lang) {
+			return true
+		}
+	}
+	return false
+}
+
+// modResourceState returns the current content of the identified resource of the mod.
+func modResourceState(mod *world.Mod, lang resource.Language, id resource.ID) resourceState {
+	state := resourceState{lang: lang, id: id}
+	view := mod.ModifiedResource(lang, id)
+	if view == nil {
+		return state
+	}
+	state.exists = true
+	state.properties = resource.Properties{Compound: view.Compound(), ContentType: view.ContentType(), Compressed: view.Compressed()}
+	state.blocks = mod.ModifiedBlocks(lang, id)
+	return state
+}
+
+func viewBlockData(view resource.View, index int) []byte {
+	reader, err := view.Block(index)
+	if err != nil {
+		return nil
+	}
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil
+	}
+	return data
+}
@@ -0,0 +1,126 @@
+package edit_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/content/bitmap"
+	"github.com/inkyblackness/hacked/ss1/edit"
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/world"
+	"github.com/inkyblackness/hacked/ss1/world/ids"
+)
+
+func relocationTestMod(t *testing.T, worldResources map[resource.ID]resource.Resource) *world.Mod {
+	mod := world.NewMod(func([]resource.ID, []resource.ID) {}, func() {})
+	var store resource.Store
+	for id, res := range worldResources {
+		_ = store.Put(id, res)
+	}
+	err := mod.World().InsertEntry(0, &world.ManifestEntry{
+		ID:        "world",
+		Resources: []resource.LocalizedResources{{ID: "world.res", Language: resource.LangAny, Viewer: store}},
+	})
+	require.Nil(t, err)
+	return mod
+}
+
+func relocationTestAnimation(t *testing.T, id resource.ID) []byte {
+	buf := bytes.NewBuffer(nil)
+	err := bitmap.WriteAnimation(buf, bitmap.Animation{Width: 10, Height: 20, ResourceID: id,
+		Entries: []bitmap.AnimationEntry{{FirstFrame: 0, LastFrame: 2, FrameTime: 100}}})
+	require.Nil(t, err)
+	return buf.Bytes()
+}
+
+func TestPrepareResourceRelocationMovesContentAndProperties(t *testing.T) {
+	mod := relocationTestMod(t, nil)
+	properties := resource.Properties{Compound: true, ContentType: resource.Bitmap, Compressed: true}
+	mod.Modify(func(modder world.Modder) {
+		modder.SetResourceBlocks(resource.LangAny, 0x7000, [][]byte{{0x01}, {0x02, 0x03}})
+		modder.SetResourceProperties(resource.LangAny, 0x7000, properties)
+	})
+
+	relocation, err := edit.PrepareResourceRelocation(mod, 0x7000, 0x7100, false)
+	require.Nil(t, err, "no error expected")
+	mod.Modify(func(modder world.Modder) { relocation.Apply(modder) })
+
+	assert.Nil(t, mod.ModifiedResource(resource.LangAny, 0x7000), "old resource should be gone")
+	moved := mod.ModifiedResource(resource.LangAny, 0x7100)
+	require.NotNil(t, moved, "new resource expected")
+	assert.Equal(t, properties.ContentType, moved.ContentType())
+	assert.True(t, moved.Compressed(), "compression should be kept")
+	assert.Equal(t, [][]byte{{0x01}, {0x02, 0x03}}, mod.ModifiedBlocks(resource.LangAny, 0x7100))
+	assert.Empty(t, relocation.Unresolved, "no unresolved references expected")
+
+	mod.Modify(func(modder world.Modder) { relocation.Revert(modder) })
+	assert.Nil(t, mod.ModifiedResource(resource.LangAny, 0x7100), "new resource should be gone after revert")
+	assert.Equal(t, [][]byte{{0x01}, {0x02, 0x03}}, mod.ModifiedBlocks(resource.LangAny, 0x7000))
+}
+
+func TestPrepareResourceRelocationRefusesOccupiedTarget(t *testing.T) {
+	mod := relocationTestMod(t, map[resource.ID]resource.Resource{
+		0x7100: {Properties: resource.Properties{ContentType: resource.Text}, Blocks: resource.BlocksFrom([][]byte{{0xAA}})},
+	})
+	mod.Modify(func(modder world.Modder) {
+		modder.SetResourceBlocks(resource.LangAny, 0x7000, [][]byte{{0x01}})
+	})
+
+	_, err := edit.PrepareResourceRelocation(mod, 0x7000, 0x7100, false)
+	assert.True(t, errors.Is(err, edit.ErrResourceOccupied), "occupied error expected, got %v", err)
+
+	relocation, err := edit.PrepareResourceRelocation(mod, 0x7000, 0x7100, true)
+	require.Nil(t, err, "no error expected with overwrite")
+	mod.Modify(func(modder world.Modder) { relocation.Apply(modder) })
+	assert.Equal(t, [][]byte{{0x01}}, mod.ModifiedBlocks(resource.LangAny, 0x7100))
+}
+
+func TestPrepareResourceRelocationRequiresResourceOfMod(t *testing.T) {
+	mod := relocationTestMod(t, map[resource.ID]resource.Resource{
+		0x7000: {Properties: resource.Properties{ContentType: resource.Text}, Blocks: resource.BlocksFrom([][]byte{{0xAA}})},
+	})
+
+	_, err := edit.PrepareResourceRelocation(mod, 0x7000, 0x7100, false)
+	assert.NotNil(t, err, "error expected for resource of the world")
+	_, err = edit.PrepareResourceRelocation(mod, 0x7000, 0x7000, true)
+	assert.NotNil(t, err, "error expected for relocation to itself")
+}
+
+func TestPrepareResourceRelocationUpdatesAnimations(t *testing.T) {
+	animationProperties := resource.Properties{Compound: true, ContentType: resource.Animation}
+	mod := relocationTestMod(t, map[resource.ID]resource.Resource{
+		0x7200: {Properties: animationProperties, Blocks: resource.BlocksFrom([][]byte{relocationTestAnimation(t, 0x7000)})},
+		0x7201: {Properties: animationProperties, Blocks: resource.BlocksFrom([][]byte{relocationTestAnimation(t, 0x7001)})},
+	})
+	mod.Modify(func(modder world.Modder) {
+		modder.SetResourceBlocks(resource.LangAny, 0x7000, [][]byte{{0x01}})
+	})
+
+	relocation, err := edit.PrepareResourceRelocation(mod, 0x7000, 0x7100, false)
+	require.Nil(t, err, "no error expected")
+	assert.Equal(t, []resource.Key{resource.KeyOf(0x7200, resource.LangAny, 0)}, relocation.Updated)
+	mod.Modify(func(modder world.Modder) { relocation.Apply(modder) })
+
+	anim, err := bitmap.ReadAnimation(bytes.NewReader(mod.ModifiedBlock(resource.LangAny, 0x7200, 0)))
+	require.Nil(t, err, "no error expected reading updated animation")
+	assert.Equal(t, resource.ID(0x7100), anim.ResourceID)
+	assert.Nil(t, mod.ModifiedResource(resource.LangAny, 0x7201), "unrelated animation should not be modified")
+
+	mod.Modify(func(modder world.Modder) { relocation.Revert(modder) })
+	assert.Nil(t, mod.ModifiedResource(resource.LangAny, 0x7200), "animation should be restored to world state")
+}
+
+func TestPrepareResourceRelocationReportsFixedReferences(t *testing.T) {
+	mod := relocationTestMod(t, nil)
+	mod.Modify(func(modder world.Modder) {
+		modder.SetResourceBlocks(resource.LangAny, ids.GamePalettesStart, [][]byte{{0x01}})
+	})
+
+	relocation, err := edit.PrepareResourceRelocation(mod, ids.GamePalettesStart, 0x7100, false)
+	require.Nil(t, err, "no error expected")
+	assert.Equal(t, 1, len(relocation.Unresolved), "engine reference expected")
+}
@@ -0,0 +1,93 @@
+package media
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/inkyblackness/hacked/ss1/content/bitmap"
+	"github.com/inkyblackness/hacked/ss1/content/movie"
+	"github.com/inkyblackness/hacked/ss1/resource"
+)
+
+// MovieBlockGetter provides raw data of blocks.
+type MovieBlockGetter interface {
+	ModifiedBlock(lang resource.Language, id resource.ID, index int) []byte
+}
+
+// MovieBlockSetter modifies storage of raw resource data.
+type MovieBlockSetter interface {
+	SetResourceBlocks(lang resource.Language, id resource.ID, data [][]byte)
+	DelResource(lang resource.Language, id resource.ID)
+}
+
+// MoviePaletteService provides access to the initial palette of movies.
+type MoviePaletteService struct {
+	movieCache *movie.Cache
+	getter     MovieBlockGetter
+}
+
+// NewMoviePaletteService returns a new instance.
+func NewMoviePaletteService(movieCache *movie.Cache, getter MovieBlockGetter) MoviePaletteService {
+	return MoviePaletteService{
+		movieCache: movieCache,
+		getter:     getter,
+	}
+}
+
+// StartPalette returns the initial palette of the identified movie.
+func (service MoviePaletteService) StartPalette(key resource.Key) (bitmap.Palette, error) {
+	container, err := service.movieCache.Movie(key)
+	if err != nil {
+		return bitmap.Palette{}, err
+	}
+	return container.StartPalette(), nil
+}
+
+// SetStartPaletteFunc prepares the replacement of the initial palette of the identified movie with the given colors,
+// and returns a function to apply it.
+// The frames are kept as they are, their color indices refer to the new colors.
+// An error is returned if the colors do not form a complete palette, or the movie could not be loaded.
+func (service MoviePaletteService) SetStartPaletteFunc(key resource.Key, colors []bitmap.RGB) (func(setter MovieBlockSetter), error) {
+	pal, err := startPaletteFrom(colors)
+	if err != nil {
+		return nil, err
+	}
+	container, err := service.movieCache.Movie(key)
+	if err != nil {
+		return nil, err
+	}
+	buf := bytes.NewBuffer(nil)
+	err = movie.Write(buf, movie.WithStartPalette(container, &pal))
+	if err != nil {
+		return nil, err
+	}
+	id := key.ID.Plus(key.Index)
+	newData := buf.Bytes()
+
+	return func(setter MovieBlockSetter) {
+		setter.SetResourceBlocks(key.Lang, id, [][]byte{newData})
+	}, nil
+}
+
+// RestoreFunc creates a snapshot of the current state of the movie and returns a function to restore it.
+func (service MoviePaletteService) RestoreFunc(key resource.Key) func(setter MovieBlockSetter) {
+	id := key.ID.Plus(key.Index)
+	oldData := service.getter.ModifiedBlock(key.Lang, id, 0)
+
+	return func(setter MovieBlockSetter) {
+		if len(oldData) > 0 {
+			setter.SetResourceBlocks(key.Lang, id, [][]byte{oldData})
+		} else {
+			setter.DelResource(key.Lang, id)
+		}
+	}
+}
+
+func startPaletteFrom(colors []bitmap.RGB) (bitmap.Palette, error) {
+	var pal bitmap.Palette
+	if len(colors) != len(pal) {
+		return pal, fmt.Errorf("palette requires %d colors, got %d", len(pal), len(colors))
+	}
+	copy(pal[:], colors)
+	return pal, nil
+}
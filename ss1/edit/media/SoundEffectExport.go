@@ -0,0 +1,103 @@
+package media
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/inkyblackness/hacked/ss1/content/audio"
+	"github.com/inkyblackness/hacked/ss1/content/audio/voc"
+	"github.com/inkyblackness/hacked/ss1/content/audio/wav"
+	"github.com/inkyblackness/hacked/ss1/resource"
+)
+
+// SoundEffectErrors collects the failures of a batch operation on sound effects, keyed by resource ID.
+type SoundEffectErrors map[resource.ID]error
+
+// Error lists all contained errors, ordered by their resource ID.
+func (errs SoundEffectErrors) Error() string {
+	ids := make([]resource.ID, 0, len(errs))
+	for id := range errs {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(a, b int) bool { return ids[a] < ids[b] })
+	messages := make([]string, len(ids))
+	for index, id := range ids {
+		messages[index] = fmt.Sprintf("%v: %v", id, errs[id])
+	}
+	return strings.Join(messages, "; ")
+}
+
+// SoundEffectFilename returns the name of the file a sound effect is exported to.
+func SoundEffectFilename(id resource.ID) string {
+	return id.String() + ".wav"
+}
+
+// DecodeSoundEffect reads the sound stored in given view.
+// The resource must be a simple sound resource, containing a Creative Voice file.
+func DecodeSoundEffect(view resource.View) (audio.L8, error) {
+	if view.ContentType() != resource.Sound {
+		return audio.L8{}, fmt.Errorf("resource is not a sound, but %v", view.ContentType())
+	}
+	if view.BlockCount() != 1 {
+		return audio.L8{}, fmt.Errorf("sound resource has %d blocks instead of one", view.BlockCount())
+	}
+	reader, err := view.Block(0)
+	if err != nil {
+		return audio.L8{}, err
+	}
+	return voc.Load(reader)
+}
+
+// ExportSoundEffect decodes the sound of given view and writes it as WAV to the writer.
+func ExportSoundEffect(writer io.Writer, view resource.View) error {
+	sound, err := DecodeSoundEffect(view)
+	if err != nil {
+		return err
+	}
+	return wav.Save(writer, sound.SampleRate, sound.Samples)
+}
+
+// ExportSoundEffects exports all sound resources of the viewer.
+// For each sound, the create function is called with the name from SoundEffectFilename().
+// Resources that are not sounds are ignored. Sounds that fail to decode or write are skipped and
+// reported in the returned error, which is of type SoundEffectErrors if not nil.
+func ExportSoundEffects(viewer resource.Viewer, create func(filename string) (io.WriteCloser, error)) error {
+	errs := make(SoundEffectErrors)
+	for _, id := range viewer.IDs() {
+		view, err := viewer.View(id)
+		if err != nil {
+			errs[id] = err
+			continue
+		}
+		if view.ContentType() != resource.Sound {
+			continue
+		}
+		err = exportSoundEffectTo(create, id, view)
+		if err != nil {
+			errs[id] = err
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func exportSoundEffectTo(create func(filename string) (io.WriteCloser, error), id resource.ID, view resource.View) error {
+	sound, err := DecodeSoundEffect(view)
+	if err != nil {
+		return err
+	}
+	writer, err := create(SoundEffectFilename(id))
+	if err != nil {
+		return err
+	}
+	err = wav.Save(writer, sound.SampleRate, sound.Samples)
+	closeErr := writer.Close()
+	if err != nil {
+		return err
+	}
+	return closeErr
+}
@@ -0,0 +1,130 @@
+package media_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/inkyblackness/hacked/ss1/content/audio/voc"
+	"github.com/inkyblackness/hacked/ss1/content/audio/wav"
+	"github.com/inkyblackness/hacked/ss1/edit/media"
+	"github.com/inkyblackness/hacked/ss1/resource"
+)
+
+type closableBuffer struct {
+	bytes.Buffer
+}
+
+func (buf *closableBuffer) Close() error {
+	return nil
+}
+
+type SoundEffectExportSuite struct {
+	suite.Suite
+
+	store   resource.Store
+	files   map[string]*closableBuffer
+	lastErr error
+}
+
+func TestSoundEffectExportSuite(t *testing.T) {
+	suite.Run(t, new(SoundEffectExportSuite))
+}
+
+func (suite *SoundEffectExportSuite) SetupTest() {
+	suite.store = resource.Store{}
+	suite.files = make(map[string]*closableBuffer)
+	suite.lastErr = nil
+}
+
+func (suite *SoundEffectExportSuite) TestAllSoundsAreExportedNamedByID() {
+	suite.givenSound(0x00C9, 11025, []byte{0x80, 0x90})
+	suite.givenSound(0x00CA, 22050, []byte{0x70})
+
+	suite.whenExporting()
+
+	suite.thenThereShouldBeNoError()
+	suite.thenFileShouldContainSound("00C9.wav", 11025, []byte{0x80, 0x90})
+	suite.thenFileShouldContainSound("00CA.wav", 22050, []byte{0x70})
+}
+
+func (suite *SoundEffectExportSuite) TestNonSoundResourcesAreIgnored() {
+	suite.givenResource(0x0010, resource.Text, [][]byte{{0x00}})
+
+	suite.whenExporting()
+
+	suite.thenThereShouldBeNoError()
+	suite.Assert().Empty(suite.files)
+}
+
+func (suite *SoundEffectExportSuite) TestMalformedSoundsAreCollectedAsErrors() {
+	suite.givenResource(0x00C9, resource.Sound, [][]byte{{0x01, 0x02}})
+	suite.givenSound(0x00CA, 11025, []byte{0x80})
+	suite.givenResource(0x00CB, resource.Sound, [][]byte{})
+
+	suite.whenExporting()
+
+	suite.thenErrorsShouldBeReportedFor(0x00C9, 0x00CB)
+	suite.thenFileShouldContainSound("00CA.wav", 11025, []byte{0x80})
+}
+
+func (suite *SoundEffectExportSuite) TestFailingFileCreationIsCollected() {
+	suite.givenSound(0x00C9, 11025, []byte{0x80})
+
+	err := media.ExportSoundEffects(suite.store, func(string) (io.WriteCloser, error) {
+		return nil, fmt.Errorf("denied")
+	})
+	suite.lastErr = err
+
+	suite.thenErrorsShouldBeReportedFor(0x00C9)
+}
+
+func (suite *SoundEffectExportSuite) givenSound(id resource.ID, sampleRate float32, samples []byte) {
+	buf := bytes.NewBuffer(nil)
+	err := voc.Save(buf, sampleRate, samples)
+	suite.Require().Nil(err, "no error expected creating sound")
+	suite.givenResource(id, resource.Sound, [][]byte{buf.Bytes()})
+}
+
+func (suite *SoundEffectExportSuite) givenResource(id resource.ID, contentType resource.ContentType, data [][]byte) {
+	res := resource.Resource{
+		Properties: resource.Properties{ContentType: contentType},
+		Blocks:     resource.BlocksFrom(data),
+	}
+	err := suite.store.Put(id, res)
+	suite.Require().Nil(err, "no error expected storing resource")
+}
+
+func (suite *SoundEffectExportSuite) whenExporting() {
+	suite.lastErr = media.ExportSoundEffects(suite.store, func(filename string) (io.WriteCloser, error) {
+		buf := &closableBuffer{}
+		suite.files[filename] = buf
+		return buf, nil
+	})
+}
+
+func (suite *SoundEffectExportSuite) thenThereShouldBeNoError() {
+	suite.Require().Nil(suite.lastErr, "no error expected")
+}
+
+func (suite *SoundEffectExportSuite) thenErrorsShouldBeReportedFor(ids ...resource.ID) {
+	suite.Require().NotNil(suite.lastErr, "error expected")
+	errs, isErrs := suite.lastErr.(media.SoundEffectErrors)
+	suite.Require().True(isErrs, "error of type SoundEffectErrors expected")
+	suite.Assert().Equal(len(ids), len(errs), "wrong number of errors")
+	for _, id := range ids {
+		suite.Assert().NotNil(errs[id], "error expected for %v", id)
+	}
+}
+
+func (suite *SoundEffectExportSuite) thenFileShouldContainSound(filename string, sampleRate float32, samples []byte) {
+	buf, existing := suite.files[filename]
+	suite.Require().True(existing, "file %v expected", filename)
+	sound, err := wav.Load(bytes.NewReader(buf.Bytes()))
+	suite.Require().Nil(err, "no error expected loading exported file")
+	suite.Assert().InDelta(sampleRate, sound.SampleRate, float64(sampleRate)*0.05, "sample rate mismatch")
+	suite.Assert().Equal(samples, sound.Samples, "samples mismatch")
+}
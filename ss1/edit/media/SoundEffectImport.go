@@ -0,0 +1,41 @@
+package media
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/inkyblackness/hacked/ss1/content/audio"
+	"github.com/inkyblackness/hacked/ss1/content/audio/voc"
+	"github.com/inkyblackness/hacked/ss1/content/audio/wav"
+	"github.com/inkyblackness/hacked/ss1/resource"
+)
+
+// SoundEffectSampleRate is the sample rate sound effects are stored with.
+const SoundEffectSampleRate float32 = 22050.0
+
+// EncodeSoundEffect converts the given sound to the sample rate of sound effects and
+// returns the data of a sound resource.
+func EncodeSoundEffect(sound audio.L8) []byte {
+	converted := sound.Resampled(SoundEffectSampleRate)
+	buf := bytes.NewBuffer(nil)
+	_ = voc.Save(buf, converted.SampleRate, converted.Samples)
+	return buf.Bytes()
+}
+
+// ImportSoundEffect reads a WAV from given source and returns the data of a sound resource.
+// Stereo sounds are mixed down to mono, and 16-bit samples are reduced to 8-bit.
+func ImportSoundEffect(source io.Reader) ([]byte, error) {
+	sound, err := wav.Load(source)
+	if err != nil {
+		return nil, err
+	}
+	return EncodeSoundEffect(sound), nil
+}
+
+// SetSoundEffect stores the given sound as the identified sound effect resource.
+// It returns the size, in bytes, of the resulting resource.
+func (service AudioSetterService) SetSoundEffect(setter AudioBlockSetter, key resource.Key, sound audio.L8) int {
+	data := EncodeSoundEffect(sound)
+	setter.SetResourceBlocks(key.Lang, key.ID, [][]byte{data})
+	return len(data)
+}
@@ -0,0 +1,33 @@
+package media_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/content/audio/voc"
+	"github.com/inkyblackness/hacked/ss1/content/audio/wav"
+	"github.com/inkyblackness/hacked/ss1/edit/media"
+)
+
+func TestImportSoundEffectConvertsToSoundEffectSampleRate(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	err := wav.Save(buf, 11025, []byte{0x00, 0x40})
+	require.Nil(t, err)
+
+	data, err := media.ImportSoundEffect(bytes.NewReader(buf.Bytes()))
+	require.Nil(t, err)
+
+	sound, err := voc.Load(bytes.NewReader(data))
+	require.Nil(t, err)
+	assert.InDelta(t, media.SoundEffectSampleRate, sound.SampleRate, 500.0)
+	assert.Equal(t, []byte{0x00, 0x20, 0x40, 0x40}, sound.Samples)
+}
+
+func TestImportSoundEffectReturnsErrorForInvalidInput(t *testing.T) {
+	_, err := media.ImportSoundEffect(bytes.NewReader([]byte{0x01, 0x02, 0x03}))
+
+	assert.NotNil(t, err)
+}
@@ -15,13 +15,14 @@ type TextBlockSetter interface {
 
 // TextSetterService provides methods to change text resources.
 type TextSetterService struct {
-	cp text.Codepage
+	codepages *text.Codepages
 }
 
 // NewTextSetterService returns a new instance.
-func NewTextSetterService(cp text.Codepage) TextSetterService {
+// Texts are encoded with the codepage of their language.
+func NewTextSetterService(codepages *text.Codepages) TextSetterService {
 	return TextSetterService{
-		cp: cp,
+		codepages: codepages,
 	}
 }
 
@@ -44,14 +45,15 @@ func (service TextSetterService) Clear(setter TextBlockSetter, key resource.Key)
 // Set stores the given text as the identified resource.
 func (service TextSetterService) Set(setter TextBlockSetter, key resource.Key, value string) {
 	blockedValue := text.Blocked(value)
+	cp := service.codepages.ForLanguage(key.Lang)
 	info, _ := ids.Info(key.ID)
 	if info.List {
-		newData := service.cp.Encode(blockedValue[0])
+		newData := cp.Encode(blockedValue[0])
 		setter.SetResourceBlock(key.Lang, key.ID, key.Index, newData)
 	} else {
 		newData := make([][]byte, len(blockedValue))
 		for index, blockLine := range blockedValue {
-			newData[index] = service.cp.Encode(blockLine)
+			newData[index] = cp.Encode(blockLine)
 		}
 		id := key.ID.Plus(key.Index)
 		setter.SetResourceBlocks(key.Lang, id, newData)
@@ -32,14 +32,20 @@ func (service AugmentedTextService) Text(key resource.Key) string {
 	return service.wrapped.Text(key)
 }
 
+// textMergeKey lets consecutive changes of the same text be undone as one.
+type textMergeKey struct {
+	key resource.Key
+}
+
 // RequestSetText queues the change to update the text.
+// Consecutive changes of the same text are merged into one undo step.
 func (service AugmentedTextService) RequestSetText(key resource.Key, value string, restoreFunc func()) {
 	service.requestCommand(
 		func(setter edit.AugmentedTextBlockSetter) {
 			service.wrapped.SetText(setter, key, value)
 		},
 		service.wrapped.RestoreTextFunc(key),
-		restoreFunc)
+		restoreFunc, textMergeKey{key: key})
 }
 
 // Sound returns the audio value of the identified text resource.
@@ -55,7 +61,7 @@ func (service AugmentedTextService) RequestSetSound(key resource.Key, sound audi
 			service.wrapped.SetSound(setter, key, sound)
 		},
 		service.wrapped.RestoreSoundFunc(key),
-		restoreFunc)
+		restoreFunc, nil)
 }
 
 // RequestClear queues the change to set both the text and the sound empty.
@@ -65,7 +71,7 @@ func (service AugmentedTextService) RequestClear(key resource.Key, restoreFunc f
 			service.wrapped.Clear(setter, key)
 		},
 		service.wrapped.RestoreFunc(key),
-		restoreFunc)
+		restoreFunc, nil)
 }
 
 // RequestRemove queues the change to remove both the text and the sound from the storage.
@@ -75,17 +81,18 @@ func (service AugmentedTextService) RequestRemove(key resource.Key, restoreFunc
 			service.wrapped.Remove(setter, key)
 		},
 		service.wrapped.RestoreFunc(key),
-		restoreFunc)
+		restoreFunc, nil)
 }
 
 func (service AugmentedTextService) requestCommand(
 	forward func(modder edit.AugmentedTextBlockSetter),
 	reverse func(modder edit.AugmentedTextBlockSetter),
-	restore func()) {
+	restore func(), mergeKey interface{}) {
 	c := command{
-		forward: func(modder world.Modder) { forward(modder) },
-		reverse: func(modder world.Modder) { reverse(modder) },
-		restore: restore,
+		forward:  func(modder world.Modder) { forward(modder) },
+		reverse:  func(modder world.Modder) { reverse(modder) },
+		restore:  restore,
+		mergeKey: mergeKey,
 	}
 	service.commander.Queue(c)
 }
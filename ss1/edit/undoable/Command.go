@@ -1,6 +1,7 @@
 package undoable
 
 import (
+	"github.com/inkyblackness/hacked/ss1/edit/undoable/cmd"
 	"github.com/inkyblackness/hacked/ss1/world"
 )
 
@@ -8,6 +9,23 @@ type command struct {
 	forward func(world.Modder)
 	reverse func(world.Modder)
 	restore func()
+
+	// mergeKey identifies what the command modifies. Consecutive commands with the same key are merged.
+	// A nil key prevents merging.
+	mergeKey interface{}
+}
+
+func (c command) Merge(next cmd.Command) (cmd.Command, bool) {
+	other, isCommand := next.(command)
+	if !isCommand || (c.mergeKey == nil) || (c.mergeKey != other.mergeKey) {
+		return nil, false
+	}
+	return command{
+		forward:  other.forward,
+		reverse:  c.reverse,
+		restore:  other.restore,
+		mergeKey: c.mergeKey,
+	}, true
 }
 
 func (c command) Do(modder world.Modder) error {
@@ -0,0 +1,51 @@
+package undoable
+
+import (
+	"github.com/inkyblackness/hacked/ss1/content/bitmap"
+	"github.com/inkyblackness/hacked/ss1/edit/media"
+	"github.com/inkyblackness/hacked/ss1/edit/undoable/cmd"
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/world"
+)
+
+// MoviePaletteService provides read/write functionality of movie palettes with undo capability.
+type MoviePaletteService struct {
+	wrapped   media.MoviePaletteService
+	commander cmd.Commander
+}
+
+// NewMoviePaletteService returns a new instance of a service.
+func NewMoviePaletteService(wrapped media.MoviePaletteService, commander cmd.Commander) MoviePaletteService {
+	return MoviePaletteService{
+		wrapped:   wrapped,
+		commander: commander,
+	}
+}
+
+// StartPalette returns the initial palette of the identified movie.
+func (service MoviePaletteService) StartPalette(key resource.Key) (bitmap.Palette, error) {
+	return service.wrapped.StartPalette(key)
+}
+
+// RequestSetStartPalette queues the change to replace the initial palette of the identified movie.
+// The new colors are verified immediately, an error is returned if they can not be applied.
+func (service MoviePaletteService) RequestSetStartPalette(key resource.Key, colors []bitmap.RGB, restoreFunc func()) error {
+	forward, err := service.wrapped.SetStartPaletteFunc(key, colors)
+	if err != nil {
+		return err
+	}
+	service.requestCommand(forward, service.wrapped.RestoreFunc(key), restoreFunc)
+	return nil
+}
+
+func (service MoviePaletteService) requestCommand(
+	forward func(setter media.MovieBlockSetter),
+	reverse func(setter media.MovieBlockSetter),
+	restore func()) {
+	c := command{
+		forward: func(modder world.Modder) { forward(modder) },
+		reverse: func(modder world.Modder) { reverse(modder) },
+		restore: restore,
+	}
+	service.commander.Queue(c)
+}
@@ -0,0 +1,41 @@
+package undoable
+
+import (
+	"github.com/inkyblackness/hacked/ss1/edit"
+	"github.com/inkyblackness/hacked/ss1/edit/undoable/cmd"
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/world"
+)
+
+// ResourceRelocationService moves resources to other identifiers with undo capability.
+type ResourceRelocationService struct {
+	mod       *world.Mod
+	commander cmd.Commander
+}
+
+// NewResourceRelocationService returns a new instance of a service.
+func NewResourceRelocationService(mod *world.Mod, commander cmd.Commander) ResourceRelocationService {
+	return ResourceRelocationService{
+		mod:       mod,
+		commander: commander,
+	}
+}
+
+// RequestRelocate queues the change to move a resource of the mod to another identifier,
+// together with the update of all references to it. See edit.PrepareResourceRelocation() for details.
+// The relocation is verified immediately, an error is returned if it can not be performed.
+// The returned relocation lists the updated blocks, as well as the references that could not be updated.
+func (service ResourceRelocationService) RequestRelocate(from, to resource.ID, overwrite bool,
+	restoreFunc func()) (edit.ResourceRelocation, error) {
+	relocation, err := edit.PrepareResourceRelocation(service.mod, from, to, overwrite)
+	if err != nil {
+		return relocation, err
+	}
+	c := command{
+		forward: func(modder world.Modder) { relocation.Apply(modder) },
+		reverse: func(modder world.Modder) { relocation.Revert(modder) },
+		restore: restoreFunc,
+	}
+	service.commander.Queue(c)
+	return relocation, nil
+}
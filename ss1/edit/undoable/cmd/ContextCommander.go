@@ -0,0 +1,20 @@
+package cmd
+
+// ContextCommander tries to execute the given command within a context.
+type ContextCommander interface {
+	QueueIn(context string, command Command)
+}
+
+// CommanderFor returns a Commander that queues all commands within given context.
+func CommanderFor(context string, target ContextCommander) Commander {
+	return contextCommander{context: context, target: target}
+}
+
+type contextCommander struct {
+	context string
+	target  ContextCommander
+}
+
+func (commander contextCommander) Queue(command Command) {
+	commander.target.QueueIn(commander.context, command)
+}
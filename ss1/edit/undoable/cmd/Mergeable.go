@@ -0,0 +1,11 @@
+package cmd
+
+// Mergeable is a command that can absorb a command that directly follows it.
+// Merging allows a sequence of small changes, such as the steps of dragging a slider,
+// to be undone as one.
+type Mergeable interface {
+	// Merge returns a command that has the combined effect of this command, followed by the given one.
+	// The given command has already been performed when this function is called.
+	// Returns false if the commands can not be merged.
+	Merge(next Command) (Command, bool)
+}
@@ -0,0 +1,144 @@
+package cmd_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/edit/undoable/cmd"
+	"github.com/inkyblackness/hacked/ss1/world"
+)
+
+type setFieldCommand struct {
+	fields map[string]int
+	field  string
+
+	oldValue int
+	newValue int
+}
+
+func (command setFieldCommand) Do(modder world.Modder) error {
+	command.fields[command.field] = command.newValue
+	return nil
+}
+
+func (command setFieldCommand) Undo(modder world.Modder) error {
+	command.fields[command.field] = command.oldValue
+	return nil
+}
+
+func (command setFieldCommand) Merge(next cmd.Command) (cmd.Command, bool) {
+	other, isSet := next.(setFieldCommand)
+	if !isSet || (other.field != command.field) {
+		return nil, false
+	}
+	command.newValue = other.newValue
+	return command, true
+}
+
+type fieldDragger struct {
+	stack  *cmd.Stack
+	fields map[string]int
+}
+
+func (dragger fieldDragger) drag(t *testing.T, context string, field string, to int) {
+	t.Helper()
+	for value := dragger.fields[field]; value != to; {
+		if value < to {
+			value++
+		} else {
+			value--
+		}
+		command := setFieldCommand{fields: dragger.fields, field: field, oldValue: dragger.fields[field], newValue: value}
+		require.Nil(t, dragger.stack.PerformIn(context, command, nil))
+	}
+}
+
+func undoCount(stack *cmd.Stack) int {
+	count := 0
+	for stack.CanUndo() {
+		_ = stack.Undo(nil)
+		count++
+	}
+	return count
+}
+
+func TestStackMergesDragOfManyStepsIntoOneUndoEntry(t *testing.T) {
+	var stack cmd.Stack
+	dragger := fieldDragger{stack: &stack, fields: map[string]int{"a": 10}}
+	dragger.drag(t, "", "a", 50)
+
+	require.Nil(t, stack.Undo(nil))
+	assert.Equal(t, 10, dragger.fields["a"], "pre-drag value expected")
+	assert.False(t, stack.CanUndo(), "only one entry expected")
+	require.Nil(t, stack.Redo(nil))
+	assert.Equal(t, 50, dragger.fields["a"], "final value expected")
+}
+
+func TestStackDoesNotMergeDifferentFields(t *testing.T) {
+	var stack cmd.Stack
+	dragger := fieldDragger{stack: &stack, fields: map[string]int{"a": 0, "b": 0}}
+	dragger.drag(t, "", "a", 5)
+	dragger.drag(t, "", "b", 5)
+
+	assert.Equal(t, 2, undoCount(&stack))
+}
+
+func TestStackDoesNotMergeDifferentContexts(t *testing.T) {
+	var stack cmd.Stack
+	dragger := fieldDragger{stack: &stack, fields: map[string]int{"a": 0}}
+	dragger.drag(t, "x", "a", 5)
+	dragger.drag(t, "y", "a", 10)
+
+	assert.Equal(t, 2, undoCount(&stack))
+}
+
+func TestStackDoesNotMergeAfterSeal(t *testing.T) {
+	var stack cmd.Stack
+	dragger := fieldDragger{stack: &stack, fields: map[string]int{"a": 0}}
+	dragger.drag(t, "", "a", 5)
+	stack.Seal()
+	dragger.drag(t, "", "a", 10)
+
+	assert.Equal(t, 2, undoCount(&stack))
+}
+
+func TestStackDoesNotMergeAfterUndo(t *testing.T) {
+	var stack cmd.Stack
+	dragger := fieldDragger{stack: &stack, fields: map[string]int{"a": 0}}
+	dragger.drag(t, "", "a", 5)
+	dragger.drag(t, "", "a", 10)
+	stack.Seal()
+	dragger.drag(t, "", "a", 20)
+	require.Nil(t, stack.Undo(nil))
+	dragger.drag(t, "", "a", 30)
+
+	assert.False(t, stack.CanRedo(), "redo should be dropped")
+	assert.Equal(t, 2, undoCount(&stack))
+	assert.Equal(t, 0, dragger.fields["a"])
+}
+
+func TestStackDoesNotMergeAfterInteractionEnded(t *testing.T) {
+	var stack cmd.Stack
+	dragger := fieldDragger{stack: &stack, fields: map[string]int{"a": 0}}
+	stack.Interacting(true)
+	dragger.drag(t, "", "a", 5)
+	stack.Interacting(true)
+	dragger.drag(t, "", "a", 10)
+	stack.Interacting(false)
+	stack.Interacting(true)
+	dragger.drag(t, "", "a", 20)
+
+	assert.Equal(t, 2, undoCount(&stack))
+}
+
+func TestStackMergesWithoutInteraction(t *testing.T) {
+	var stack cmd.Stack
+	dragger := fieldDragger{stack: &stack, fields: map[string]int{"a": 0}}
+	dragger.drag(t, "", "a", 5)
+	stack.Interacting(false)
+	dragger.drag(t, "", "a", 10)
+
+	assert.Equal(t, 1, undoCount(&stack))
+}
@@ -3,8 +3,9 @@ package cmd
 import "github.com/inkyblackness/hacked/ss1/world"
 
 type stackEntry struct {
-	link *stackEntry
-	cmd  Command
+	link    *stackEntry
+	context string
+	cmd     Command
 }
 
 // Stack describes a list of commands. The stack allows to sequentially
@@ -12,16 +13,49 @@ type stackEntry struct {
 // It essentially stores two lists: a list of commands to undo, and
 // another of commands to redo.
 // Modifying stack functions will panic if they are called while already in use.
+//
+// Commands can be performed within a context, such as the editor that issued them.
+// With UndoScopeGlobal, the default, contexts are only recorded. With UndoScopeContext,
+// undoing and redoing within a context only considers the commands of that context.
+// Undo() and Redo() always work on the most recent command, regardless of the scope.
+//
+// A performed command is merged into the most recent one of the same context if the latter
+// implements Mergeable and accepts it. Merging stops after undo, redo, Seal(), and the end of an
+// interaction reported with Interacting().
 type Stack struct {
-	lockedBy string
-	undoList *stackEntry
-	redoList *stackEntry
+	lockedBy    string
+	sealed      bool
+	interacting bool
+	scope       UndoScope
+	undoList    *stackEntry
+	redoList    *stackEntry
+}
+
+// Scope returns the current undo scope.
+func (stack *Stack) Scope() UndoScope {
+	return stack.scope
+}
+
+// SetScope changes the undo scope. Already stacked commands keep their context.
+func (stack *Stack) SetScope(scope UndoScope) {
+	stack.lock("SetScope")
+	defer stack.unlock()
+
+	stack.scope = scope
 }
 
 // Perform executes the given command and puts it on the stack
 // if the command was successful.
 // This function also clears the list of commands to be redone.
 func (stack *Stack) Perform(cmd Command, modder world.Modder) error {
+	return stack.PerformIn("", cmd, modder)
+}
+
+// PerformIn executes the given command within given context and puts it on the stack
+// if the command was successful.
+// This function also clears the list of commands to be redone. With UndoScopeContext,
+// only the commands of the same context are cleared.
+func (stack *Stack) PerformIn(context string, cmd Command, modder world.Modder) error {
 	stack.lock("Perform")
 	defer stack.unlock()
 
@@ -29,11 +63,50 @@ func (stack *Stack) Perform(cmd Command, modder world.Modder) error {
 	if err != nil {
 		return err
 	}
-	stack.undoList = &stackEntry{link: stack.undoList, cmd: cmd}
-	stack.redoList = nil
+	if !stack.merge(context, cmd) {
+		stack.undoList = &stackEntry{link: stack.undoList, context: context, cmd: cmd}
+	}
+	stack.sealed = false
+	if stack.scope == UndoScopeContext {
+		stack.redoList = withoutContext(stack.redoList, context)
+	} else {
+		stack.redoList = nil
+	}
 	return nil
 }
 
+// Seal prevents the most recent command from absorbing further commands.
+// Call this when a continuous interaction, such as dragging a slider, is finished.
+func (stack *Stack) Seal() {
+	stack.sealed = true
+}
+
+// Interacting reports whether the user currently interacts with a control, such as typing into a text field.
+// Once the interaction ends, for example because the control lost focus, the stack is sealed.
+// This way, separate keyboard edits of the same value are undone separately. Call this once per frame.
+func (stack *Stack) Interacting(active bool) {
+	if stack.interacting && !active {
+		stack.Seal()
+	}
+	stack.interacting = active
+}
+
+func (stack *Stack) merge(context string, cmd Command) bool {
+	top := stack.undoList
+	if stack.sealed || (top == nil) || (top.context != context) {
+		return false
+	}
+	mergeable, isMergeable := top.cmd.(Mergeable)
+	if !isMergeable {
+		return false
+	}
+	merged, ok := mergeable.Merge(cmd)
+	if ok {
+		top.cmd = merged
+	}
+	return ok
+}
+
 // CanUndo returns true if there is at least one more command that can be undone.
 func (stack *Stack) CanUndo() bool {
 	return stack.undoList != nil
@@ -47,17 +120,39 @@ func (stack *Stack) Undo(modder world.Modder) error {
 	stack.lock("Undo")
 	defer stack.unlock()
 
-	if stack.undoList == nil {
+	return stack.undo(&stack.undoList, modder)
+}
+
+// CanUndoIn returns true if there is at least one more command that can be undone within given context.
+func (stack *Stack) CanUndoIn(context string) bool {
+	return *stack.entryIn(&stack.undoList, context) != nil
+}
+
+// UndoIn attempts to undo the previous command of given context.
+// With UndoScopeGlobal, this is the same as Undo().
+// If there is no further command to undo, nothing happens.
+// An error is returned if the command failed. In this case, the stack is
+// unchanged and a further attempt to undo will try the same command again.
+func (stack *Stack) UndoIn(context string, modder world.Modder) error {
+	stack.lock("Undo")
+	defer stack.unlock()
+
+	return stack.undo(stack.entryIn(&stack.undoList, context), modder)
+}
+
+func (stack *Stack) undo(ref **stackEntry, modder world.Modder) error {
+	entry := *ref
+	if entry == nil {
 		return nil
 	}
-	entry := stack.undoList
 	err := entry.cmd.Undo(modder)
 	if err != nil {
 		return err
 	}
-	stack.undoList = entry.link
+	*ref = entry.link
 	entry.link = stack.redoList
 	stack.redoList = entry
+	stack.sealed = true
 	return nil
 }
 
@@ -74,20 +169,67 @@ func (stack *Stack) Redo(modder world.Modder) error {
 	stack.lock("Redo")
 	defer stack.unlock()
 
-	if stack.redoList == nil {
+	return stack.redo(&stack.redoList, modder)
+}
+
+// CanRedoIn returns true if there is at least one more command that can be redone within given context.
+func (stack *Stack) CanRedoIn(context string) bool {
+	return *stack.entryIn(&stack.redoList, context) != nil
+}
+
+// RedoIn attempts to perform the next command of given context.
+// With UndoScopeGlobal, this is the same as Redo().
+// If there is no further command to redo, nothing happens.
+// An error is returned if the command failed. In this case, the stack is
+// unchanged and a further attempt to redo will try the same command again.
+func (stack *Stack) RedoIn(context string, modder world.Modder) error {
+	stack.lock("Redo")
+	defer stack.unlock()
+
+	return stack.redo(stack.entryIn(&stack.redoList, context), modder)
+}
+
+func (stack *Stack) redo(ref **stackEntry, modder world.Modder) error {
+	entry := *ref
+	if entry == nil {
 		return nil
 	}
-	entry := stack.redoList
 	err := entry.cmd.Do(modder)
 	if err != nil {
 		return err
 	}
-	stack.redoList = entry.link
+	*ref = entry.link
 	entry.link = stack.undoList
 	stack.undoList = entry
+	stack.sealed = true
 	return nil
 }
 
+// entryIn returns the reference to the first entry of the list that is relevant for given context.
+// The returned reference points to nil if there is no such entry.
+func (stack *Stack) entryIn(list **stackEntry, context string) **stackEntry {
+	ref := list
+	if stack.scope != UndoScopeContext {
+		return ref
+	}
+	for (*ref != nil) && ((*ref).context != context) {
+		ref = &(*ref).link
+	}
+	return ref
+}
+
+func withoutContext(list *stackEntry, context string) *stackEntry {
+	ref := &list
+	for *ref != nil {
+		if (*ref).context == context {
+			*ref = (*ref).link
+		} else {
+			ref = &(*ref).link
+		}
+	}
+	return list
+}
+
 func (stack *Stack) lock(by string) {
 	if stack.lockedBy != "" {
 		panic("Stack already in use by <" + stack.lockedBy + ">")
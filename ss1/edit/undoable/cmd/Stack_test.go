@@ -41,6 +41,14 @@ func (cmd *TestCommand) run() (err error) {
 	return
 }
 
+type stackCommander struct {
+	stack *cmd.Stack
+}
+
+func (commander stackCommander) QueueIn(context string, command cmd.Command) {
+	_ = commander.stack.PerformIn(context, command, nil)
+}
+
 type StackSuite struct {
 	suite.Suite
 
@@ -224,6 +232,89 @@ func (suite *StackSuite) TestRedoPanicsIfStackIsInUse() {
 	suite.assertPanics(callRedo)
 }
 
+func (suite *StackSuite) TestUndoInGlobalScopeRevertsMostRecentCommand() {
+	suite.givenCommandWasPerformedIn("a", "cmd1")
+	suite.givenCommandWasPerformedIn("b", "cmd2")
+	suite.whenUndoingIn("a")
+	suite.thenCommandShouldHaveBeenRevertedTimes("cmd2", 1)
+	suite.thenCommandShouldHaveBeenRevertedTimes("cmd1", 0)
+}
+
+func (suite *StackSuite) TestUndoInContextScopeRevertsMostRecentCommandOfContext() {
+	suite.givenScope(cmd.UndoScopeContext)
+	suite.givenCommandWasPerformedIn("a", "cmd1")
+	suite.givenCommandWasPerformedIn("b", "cmd2")
+	suite.whenUndoingIn("a")
+	suite.thenCommandShouldHaveBeenRevertedTimes("cmd1", 1)
+	suite.thenCommandShouldHaveBeenRevertedTimes("cmd2", 0)
+	suite.thenStackShouldNotSupportUndoIn("a")
+	suite.thenStackShouldSupportUndoIn("b")
+}
+
+func (suite *StackSuite) TestUndoInContextScopeIgnoresOtherContexts() {
+	suite.givenScope(cmd.UndoScopeContext)
+	suite.givenCommandWasPerformedIn("b", "cmd1")
+	suite.whenUndoingIn("a")
+	suite.thenCommandShouldHaveBeenRevertedTimes("cmd1", 0)
+	suite.thenStackShouldNotSupportRedoIn("a")
+}
+
+func (suite *StackSuite) TestUndoInContextScopeLeavesStackUnchangedIfCommandFails() {
+	suite.givenScope(cmd.UndoScopeContext)
+	suite.givenCommandWasPerformedIn("a", "cmd1")
+	suite.givenCommandWasPerformedIn("b", "cmd2")
+	suite.givenCommandWillFail("cmd1")
+	suite.whenUndoingIn("a")
+	suite.whenUndoingIn("a")
+	suite.thenCommandShouldHaveBeenRevertedTimes("cmd1", 2)
+	suite.thenStackShouldNotSupportUndoIn("a")
+}
+
+func (suite *StackSuite) TestRedoInContextScopeExecutesCommandOfContext() {
+	suite.givenScope(cmd.UndoScopeContext)
+	suite.givenCommandWasPerformedIn("a", "cmd1")
+	suite.givenCommandWasPerformedIn("b", "cmd2")
+	suite.givenUndoWasCalledTimes(2)
+	suite.whenRedoingIn("b")
+	suite.thenCommandShouldHaveBeenExecutedTimes("cmd2", 2)
+	suite.thenCommandShouldHaveBeenExecutedTimes("cmd1", 1)
+	suite.thenStackShouldSupportRedoIn("a")
+}
+
+func (suite *StackSuite) TestPerformInContextScopeDropsPendingRedoOfSameContextOnly() {
+	suite.givenScope(cmd.UndoScopeContext)
+	suite.givenCommandWasPerformedIn("a", "cmd1")
+	suite.givenCommandWasPerformedIn("b", "cmd2")
+	suite.givenUndoWasCalledTimes(2)
+	suite.whenPerformingIn("a", suite.aCommand("cmd3"))
+	suite.thenStackShouldNotSupportRedoIn("a")
+	suite.thenStackShouldSupportRedoIn("b")
+}
+
+func (suite *StackSuite) TestPerformInGlobalScopeDropsAllPendingRedo() {
+	suite.givenCommandWasPerformedIn("a", "cmd1")
+	suite.givenCommandWasPerformedIn("b", "cmd2")
+	suite.givenUndoWasCalledTimes(2)
+	suite.whenPerformingIn("a", suite.aCommand("cmd3"))
+	suite.thenStackShouldNotSupportRedo()
+}
+
+func (suite *StackSuite) TestUndoInContextScopeStillRevertsMostRecentCommandWithoutContext() {
+	suite.givenScope(cmd.UndoScopeContext)
+	suite.givenCommandWasPerformedIn("a", "cmd1")
+	suite.givenCommandWasPerformedIn("b", "cmd2")
+	suite.whenUndoing()
+	suite.thenCommandShouldHaveBeenRevertedTimes("cmd2", 1)
+}
+
+func (suite *StackSuite) TestCommanderForQueuesWithinContext() {
+	suite.givenScope(cmd.UndoScopeContext)
+	commander := cmd.CommanderFor("a", stackCommander{stack: &suite.stack})
+	commander.Queue(suite.aCommand("cmd1"))
+	suite.thenStackShouldSupportUndoIn("a")
+	suite.thenStackShouldNotSupportUndoIn("b")
+}
+
 func (suite *StackSuite) assertPanics(taskFor func(string) func()) {
 	cmd1 := suite.aCommandExecuting("cmd1", taskFor("Perform"))
 	suite.whenPerforming(cmd1)
@@ -238,6 +329,14 @@ func (suite *StackSuite) assertPanics(taskFor func(string) func()) {
 	suite.whenRedoing()
 }
 
+func (suite *StackSuite) givenScope(scope cmd.UndoScope) {
+	suite.stack.SetScope(scope)
+}
+
+func (suite *StackSuite) givenCommandWasPerformedIn(context string, name string) {
+	suite.whenPerformingIn(context, suite.aCommand(name))
+}
+
 func (suite *StackSuite) givenCommandWasPerformed(name string) {
 	suite.whenPerforming(suite.aCommand(name))
 }
@@ -271,6 +370,18 @@ func (suite *StackSuite) whenRedoing() {
 	_ = suite.stack.Redo(nil)
 }
 
+func (suite *StackSuite) whenUndoingIn(context string) {
+	_ = suite.stack.UndoIn(context, nil)
+}
+
+func (suite *StackSuite) whenRedoingIn(context string) {
+	_ = suite.stack.RedoIn(context, nil)
+}
+
+func (suite *StackSuite) whenPerformingIn(context string, command cmd.Command) {
+	_ = suite.stack.PerformIn(context, command, nil)
+}
+
 func (suite *StackSuite) whenPerforming(command cmd.Command) {
 	_ = suite.stack.Perform(command, nil)
 }
@@ -291,6 +402,22 @@ func (suite *StackSuite) thenStackShouldNotSupportUndo() {
 	assert.False(suite.T(), suite.stack.CanUndo(), "Stack should not be able to undo")
 }
 
+func (suite *StackSuite) thenStackShouldSupportUndoIn(context string) {
+	assert.True(suite.T(), suite.stack.CanUndoIn(context), "Stack should be able to undo in <"+context+">")
+}
+
+func (suite *StackSuite) thenStackShouldNotSupportUndoIn(context string) {
+	assert.False(suite.T(), suite.stack.CanUndoIn(context), "Stack should not be able to undo in <"+context+">")
+}
+
+func (suite *StackSuite) thenStackShouldSupportRedoIn(context string) {
+	assert.True(suite.T(), suite.stack.CanRedoIn(context), "Stack should be able to redo in <"+context+">")
+}
+
+func (suite *StackSuite) thenStackShouldNotSupportRedoIn(context string) {
+	assert.False(suite.T(), suite.stack.CanRedoIn(context), "Stack should not be able to redo in <"+context+">")
+}
+
 func (suite *StackSuite) thenCommandShouldHaveBeenExecuted(name string) {
 	cmd := suite.pastCommand(name)
 	assert.True(suite.T(), cmd.executed > 0, "Command <"+name+"> should have been executed at least once")
@@ -0,0 +1,16 @@
+package cmd
+
+// UndoScope specifies which commands a Stack considers when undoing or redoing within a context.
+type UndoScope int
+
+// UndoScope constants.
+const (
+	// UndoScopeGlobal treats all commands as one sequence, regardless of their context.
+	// Undoing and redoing within a context behaves the same as undoing and redoing without one.
+	UndoScopeGlobal UndoScope = iota
+	// UndoScopeContext treats the commands of each context as separate sequences.
+	// Undoing within a context reverts the most recent command of that context, even if
+	// commands of other contexts were performed afterwards. This is only safe if the
+	// commands of different contexts modify independent resources.
+	UndoScopeContext
+)
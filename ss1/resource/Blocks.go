@@ -2,7 +2,6 @@ package resource
 
 import (
 	"bytes"
-	"fmt"
 	"io"
 )
 
@@ -29,7 +28,7 @@ func (blocks Blocks) BlockCount() int {
 func (blocks Blocks) BlockRaw(index int) ([]byte, error) {
 	available := len(blocks.data)
 	if (index < 0) || (index >= available) {
-		return nil, fmt.Errorf("block index wrong: %v/%v", index, available)
+		return nil, ErrBlockIndex(index, available)
 	}
 	return blocks.data[index], nil
 }
@@ -44,6 +43,14 @@ func (blocks Blocks) Block(index int) (io.Reader, error) {
 	return bytes.NewBuffer(raw), nil
 }
 
+// Shared returns a new list of the same blocks. Only the list is copied, the data of the blocks is shared.
+// Setting blocks in either list does not affect the other, as long as the data of blocks is not modified in place.
+func (blocks Blocks) Shared() Blocks {
+	data := make([][]byte, len(blocks.data))
+	copy(data, blocks.data)
+	return Blocks{data: data}
+}
+
 // Set the data of all the blocks.
 func (blocks *Blocks) Set(data [][]byte) {
 	blocks.data = data
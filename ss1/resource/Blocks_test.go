@@ -57,3 +57,16 @@ func TestBlockSetting(t *testing.T) {
 	blocks.SetBlock(5, []byte{0xAA})
 	assert.Equal(t, 6, blocks.BlockCount(), "block count should have been updated")
 }
+
+func TestBlocksSharedKeepsOwnList(t *testing.T) {
+	data := []byte{0x01}
+	blocks := resource.BlocksFrom([][]byte{data})
+	shared := blocks.Shared()
+
+	blocks.SetBlock(0, []byte{0x02})
+
+	raw, err := shared.BlockRaw(0)
+	assert.Nil(t, err, "block should be known")
+	assert.Equal(t, []byte{0x01}, raw, "shared list should keep its block")
+	assert.Equal(t, &data[0], &raw[0], "data should be shared")
+}
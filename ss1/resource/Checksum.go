@@ -0,0 +1,51 @@
+package resource
+
+import (
+	"crypto/sha256"
+	"io"
+	"sort"
+)
+
+// Checksum is the SHA-256 hash of the data of a resource block.
+type Checksum [sha256.Size]byte
+
+// BlockChecksums calculates the checksum of every block of all the resources the viewer provides.
+// The returned map is keyed by the resource keys, which all carry the given language.
+//
+// Checksums only cover the uncompressed block data, which is streamed through the hash function.
+// They are independent of meta-information, such as the content type, and are stable
+// across runs and platforms.
+func BlockChecksums(viewer Viewer, lang Language) (map[Key]Checksum, error) {
+	ids := viewer.IDs()
+	sort.Slice(ids, func(a, b int) bool { return ids[a] < ids[b] })
+	result := make(map[Key]Checksum)
+	for _, id := range ids {
+		view, err := viewer.View(id)
+		if err != nil {
+			return nil, err
+		}
+		for index := 0; index < view.BlockCount(); index++ {
+			checksum, err := blockChecksum(view, index)
+			if err != nil {
+				return nil, err
+			}
+			result[KeyOf(id, lang, index)] = checksum
+		}
+	}
+	return result, nil
+}
+
+func blockChecksum(view View, index int) (Checksum, error) {
+	var checksum Checksum
+	reader, err := view.Block(index)
+	if err != nil {
+		return checksum, err
+	}
+	hasher := sha256.New()
+	_, err = io.Copy(hasher, reader)
+	if err != nil {
+		return checksum, err
+	}
+	copy(checksum[:], hasher.Sum(nil))
+	return checksum, nil
+}
@@ -0,0 +1,84 @@
+package resource_test
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/resource"
+)
+
+func TestBlockChecksumsReturnsHashPerBlock(t *testing.T) {
+	var store resource.Store
+	err := store.Put(resource.ID(0x1000), resource.Resource{
+		Properties: resource.Properties{Compound: true, ContentType: resource.Text},
+		Blocks:     resource.BlocksFrom([][]byte{{0x01, 0x02}, {}}),
+	})
+	require.Nil(t, err)
+	err = store.Put(resource.ID(0x2000), resource.Resource{
+		Properties: resource.Properties{ContentType: resource.Bitmap},
+		Blocks:     resource.BlocksFrom([][]byte{{0x03}}),
+	})
+	require.Nil(t, err)
+
+	checksums, err := resource.BlockChecksums(store, resource.LangGerman)
+	require.Nil(t, err)
+
+	assert.Equal(t, map[resource.Key]resource.Checksum{
+		resource.KeyOf(0x1000, resource.LangGerman, 0): sha256.Sum256([]byte{0x01, 0x02}),
+		resource.KeyOf(0x1000, resource.LangGerman, 1): sha256.Sum256(nil),
+		resource.KeyOf(0x2000, resource.LangGerman, 0): sha256.Sum256([]byte{0x03}),
+	}, checksums)
+}
+
+func TestBlockChecksumsReturnsErrorForUnavailableResources(t *testing.T) {
+	_, err := resource.BlockChecksums(failingViewer{resource.ID(0x0010)}, resource.LangAny)
+
+	assert.NotNil(t, err)
+}
+
+type failingViewer []resource.ID
+
+func (viewer failingViewer) IDs() []resource.ID {
+	return viewer
+}
+
+func (viewer failingViewer) View(id resource.ID) (resource.View, error) {
+	return nil, resource.ErrResourceDoesNotExist(id)
+}
+
+func TestFindDuplicatesGroupsIdenticalBlocks(t *testing.T) {
+	var store resource.Store
+	err := store.Put(resource.ID(0x1000), resource.Resource{
+		Properties: resource.Properties{Compound: true, ContentType: resource.Text},
+		Blocks:     resource.BlocksFrom([][]byte{{0x01, 0x02}, {}, {0x03}}),
+	})
+	require.Nil(t, err)
+	err = store.Put(resource.ID(0x0800), resource.Resource{
+		Properties: resource.Properties{ContentType: resource.Text},
+		Blocks:     resource.BlocksFrom([][]byte{{0x03}}),
+	})
+	require.Nil(t, err)
+	err = store.Put(resource.ID(0x2000), resource.Resource{
+		Properties: resource.Properties{Compound: true, ContentType: resource.Text},
+		Blocks:     resource.BlocksFrom([][]byte{{}, {0x01, 0x02}, {0x03}}),
+	})
+	require.Nil(t, err)
+	checksums, err := resource.BlockChecksums(store, resource.LangAny)
+	require.Nil(t, err)
+
+	groups := resource.FindDuplicates(checksums)
+
+	require.Equal(t, 2, len(groups), "two groups expected, empty blocks should be ignored")
+	assert.Equal(t, resource.KeyOf(0x0800, resource.LangAny, 0), groups[0].Keep())
+	assert.Equal(t, []resource.Key{
+		resource.KeyOf(0x1000, resource.LangAny, 2),
+		resource.KeyOf(0x2000, resource.LangAny, 2),
+	}, groups[0].Redundant())
+	assert.Equal(t, []resource.Key{
+		resource.KeyOf(0x1000, resource.LangAny, 0),
+		resource.KeyOf(0x2000, resource.LangAny, 1),
+	}, groups[1].Keys)
+}
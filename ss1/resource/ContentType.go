@@ -17,6 +17,11 @@ var contentTypeNames = map[ContentType]string{
 	Archive:   "Archive",
 }
 
+// ContentTypes returns all known content types, in order of their value.
+func ContentTypes() []ContentType {
+	return []ContentType{Palette, Text, Bitmap, Font, Animation, Sound, Geometry, Movie, Archive}
+}
+
 // String returns the textual representation of the type.
 func (t ContentType) String() string {
 	s, existing := contentTypeNames[t]
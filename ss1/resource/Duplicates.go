@@ -0,0 +1,63 @@
+package resource
+
+import (
+	"crypto/sha256"
+	"sort"
+)
+
+// DuplicateGroup is a set of resource blocks with identical content.
+type DuplicateGroup struct {
+	// Checksum is the common checksum of the blocks.
+	Checksum Checksum
+	// Keys identify the blocks, sorted by ID, language, and index. There are at least two keys.
+	Keys []Key
+}
+
+// Keep returns the key of the block that is suggested to be kept.
+// This is the first key, which typically refers to the original that the other blocks were copied from.
+func (group DuplicateGroup) Keep() Key {
+	return group.Keys[0]
+}
+
+// Redundant returns the keys of the blocks that are suggested to be removed, or redirected to the kept one.
+func (group DuplicateGroup) Redundant() []Key {
+	return group.Keys[1:]
+}
+
+// FindDuplicates groups the blocks of given checksums by identical content.
+// Only groups of at least two blocks are returned, sorted by the key to keep.
+// Empty blocks are not considered, as they do not take up space.
+//
+// The checksums can be retrieved with BlockChecksums(), which handles compound resources per block.
+// The result is an analysis only; resources typically refer to each other by their ID,
+// which needs to be considered before removing any of the duplicates.
+func FindDuplicates(checksums map[Key]Checksum) []DuplicateGroup {
+	empty := Checksum(sha256.Sum256(nil))
+	keysByChecksum := make(map[Checksum][]Key)
+	for key, checksum := range checksums {
+		if checksum == empty {
+			continue
+		}
+		keysByChecksum[checksum] = append(keysByChecksum[checksum], key)
+	}
+	var groups []DuplicateGroup
+	for checksum, keys := range keysByChecksum {
+		if len(keys) < 2 {
+			continue
+		}
+		sort.Slice(keys, func(a, b int) bool { return keyLess(keys[a], keys[b]) })
+		groups = append(groups, DuplicateGroup{Checksum: checksum, Keys: keys})
+	}
+	sort.Slice(groups, func(a, b int) bool { return keyLess(groups[a].Keep(), groups[b].Keep()) })
+	return groups
+}
+
+func keyLess(a, b Key) bool {
+	if a.ID != b.ID {
+		return a.ID < b.ID
+	}
+	if a.Lang != b.Lang {
+		return a.Lang < b.Lang
+	}
+	return a.Index < b.Index
+}
@@ -1,9 +1,85 @@
 package resource
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrResourceNotFound is wrapped by errors for resources that are not available.
+var ErrResourceNotFound = errors.New("resource not found")
+
+// ErrDecompressionFailed is wrapped by errors for resources of which the compressed data could not be read.
+var ErrDecompressionFailed = errors.New("decompression failed")
+
+// ErrUnexpectedContentType is wrapped by errors for resources that do not have the requested type of content.
+var ErrUnexpectedContentType = errors.New("unexpected content type")
+
+// ErrMalformedData is wrapped by errors for serialized data that does not follow the expected format.
+var ErrMalformedData = errors.New("malformed data")
+
+// ErrBlockIndexOutOfRange is wrapped by errors for blocks that are not available in a resource.
+var ErrBlockIndexOutOfRange = errors.New("block index out of range")
+
+// IDError is an error concerning a specific resource.
+// It wraps one of the error values of this package, possibly with further details.
+// Use errors.Is() to determine the kind of error, and errors.As() to retrieve the ID.
+type IDError struct {
+	ID  ID
+	Err error
+}
+
+// Error returns the textual description of the error, including the resource ID.
+func (err *IDError) Error() string {
+	return fmt.Sprintf("resource with ID %v: %v", err.ID, err.Err)
+}
+
+// Unwrap returns the wrapped error.
+func (err *IDError) Unwrap() error {
+	return err.Err
+}
+
+// BlockIndexError is an error for a block index that is outside the range of available blocks.
+// It wraps ErrBlockIndexOutOfRange.
+type BlockIndexError struct {
+	Index int
+	Count int
+}
+
+// Error returns the textual description of the error.
+func (err *BlockIndexError) Error() string {
+	return fmt.Sprintf("block index wrong: %v/%v", err.Index, err.Count)
+}
+
+// Unwrap returns ErrBlockIndexOutOfRange.
+func (err *BlockIndexError) Unwrap() error {
+	return ErrBlockIndexOutOfRange
+}
+
+// ErrBlockIndex returns an error specifying that the given block index is not within the given count of blocks.
+func ErrBlockIndex(index, count int) error {
+	return &BlockIndexError{Index: index, Count: count}
+}
 
 // ErrResourceDoesNotExist returns an error specifying the given ID doesn't
-// have an associated resource.
+// have an associated resource. The error wraps ErrResourceNotFound.
 func ErrResourceDoesNotExist(id ID) error {
-	return fmt.Errorf("resource with ID %v does not exist", id)
+	return &IDError{ID: id, Err: ErrResourceNotFound}
+}
+
+// ErrDecompressionOf returns an error specifying that the data of given resource could not be decompressed.
+// The error wraps ErrDecompressionFailed, and mentions the given cause.
+func ErrDecompressionOf(id ID, cause error) error {
+	return &IDError{ID: id, Err: fmt.Errorf("%w: %v", ErrDecompressionFailed, cause)}
+}
+
+// ErrContentTypeOf returns an error specifying that the given resource has an unexpected content type.
+// The error wraps ErrUnexpectedContentType.
+func ErrContentTypeOf(id ID, actual, expected ContentType) error {
+	return &IDError{ID: id, Err: fmt.Errorf("%w %v, expected %v", ErrUnexpectedContentType, actual, expected)}
+}
+
+// ErrMalformedResource returns an error specifying that the serialized form of given resource is broken.
+// The error wraps ErrMalformedData, and mentions the given detail.
+func ErrMalformedResource(id ID, detail string) error {
+	return &IDError{ID: id, Err: fmt.Errorf("%w: %v", ErrMalformedData, detail)}
 }
@@ -0,0 +1,41 @@
+package resource_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/inkyblackness/hacked/ss1/resource"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorsCanBeDistinguished(t *testing.T) {
+	id := resource.ID(0x0123)
+	tt := []struct {
+		name     string
+		err      error
+		expected error
+	}{
+		{name: "not found", err: resource.ErrResourceDoesNotExist(id), expected: resource.ErrResourceNotFound},
+		{name: "decompression", err: resource.ErrDecompressionOf(id, errors.New("broken")), expected: resource.ErrDecompressionFailed},
+		{name: "content type", err: resource.ErrContentTypeOf(id, resource.Text, resource.Bitmap), expected: resource.ErrUnexpectedContentType},
+		{name: "malformed", err: resource.ErrMalformedResource(id, "broken"), expected: resource.ErrMalformedData},
+	}
+	all := []error{resource.ErrResourceNotFound, resource.ErrDecompressionFailed, resource.ErrUnexpectedContentType,
+		resource.ErrMalformedData}
+
+	for _, tc := range tt {
+		td := tc
+		t.Run(td.name, func(t *testing.T) {
+			wrapped := fmt.Errorf("loading failed: %w", td.err)
+			for _, other := range all {
+				assert.Equal(t, other == td.expected, errors.Is(wrapped, other), "wrong result for %v", other)
+			}
+			var idErr *resource.IDError
+			require.True(t, errors.As(wrapped, &idErr), "ID error expected")
+			assert.Equal(t, id, idErr.ID)
+		})
+	}
+}
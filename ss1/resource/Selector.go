@@ -1,5 +1,7 @@
 package resource
 
+import "io"
+
 // Filter filters for language and id to produce a list of matching resources.
 type Filter interface {
 	Filter(lang Language, id ID) List
@@ -32,3 +34,13 @@ func (merger Selector) Select(id ID) (view View, err error) {
 
 	return view, nil
 }
+
+// SelectBlock provides the identified block of one resource, without providing a view on the other blocks.
+// An error wrapping ErrBlockIndexOutOfRange is returned if the resource does not have the requested block.
+func (merger Selector) SelectBlock(id ID, index int) (io.Reader, error) {
+	view, err := merger.Select(id)
+	if err != nil {
+		return nil, err
+	}
+	return view.Block(index)
+}
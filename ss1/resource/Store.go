@@ -73,6 +73,24 @@ func (store *Store) Put(id ID, view View) error {
 	return nil
 }
 
+// Shared returns a copy of the store with copies of its resources, which share the data of their blocks.
+// Changing the properties or blocks of resources in either store does not affect the other,
+// as long as the data of blocks is not modified in place.
+func (store Store) Shared() Store {
+	shared := Store{
+		ids:       make([]ID, len(store.ids)),
+		resources: make(map[ID]*Resource, len(store.resources)),
+	}
+	copy(shared.ids, store.ids)
+	for id, res := range store.resources {
+		shared.resources[id] = &Resource{
+			Properties: res.Properties,
+			Blocks:     res.Blocks.Shared(),
+		}
+	}
+	return shared
+}
+
 func (store Store) findIDIndex(id ID) int {
 	for index := 0; index < len(store.ids); index++ {
 		if store.ids[index] == id {
@@ -112,6 +112,21 @@ func (suite *StoreSuite) TestPutRestoresIDAtOldPosition() {
 	suite.thenIDsShouldBe([]resource.ID{resource.ID(2), resource.ID(1)})
 }
 
+func (suite *StoreSuite) TestSharedKeepsResourcesOfTimeOfCopy() {
+	suite.givenAnInstance()
+	suite.givenStoredResource(resource.ID(1), suite.aResource())
+	shared := suite.store.Shared()
+	original, _ := suite.store.Resource(resource.ID(1))
+	original.SetBlock(0, []byte{0xFF})
+	suite.whenResourceIsPut(resource.ID(2), suite.aResource())
+
+	assert.Equal(suite.T(), []resource.ID{resource.ID(1)}, shared.IDs(), "IDs of shared store should not change")
+	sharedRes, err := shared.Resource(resource.ID(1))
+	require.Nil(suite.T(), err, "no error expected")
+	raw, _ := sharedRes.BlockRaw(0)
+	assert.NotEqual(suite.T(), []byte{0xFF}, raw, "block of shared store should not change")
+}
+
 func (suite *StoreSuite) givenAnInstance() {
 	suite.whenInstanceIsCreated()
 }
@@ -0,0 +1,91 @@
+package lgres
+
+import (
+	"fmt"
+
+	"github.com/inkyblackness/hacked/ss1/resource"
+)
+
+// CompressionPolicy specifies whether resources shall be written in compressed form.
+type CompressionPolicy int
+
+// CompressionPolicy constants.
+const (
+	// CompressionAuto compresses resources that request compression, unless they are smaller
+	// than the threshold of the options.
+	CompressionAuto CompressionPolicy = iota
+	// CompressionAlways compresses all resources, regardless of their request and size.
+	CompressionAlways
+	// CompressionNever stores all resources uncompressed.
+	CompressionNever
+)
+
+// CompressionPolicies returns all known policies.
+func CompressionPolicies() []CompressionPolicy {
+	return []CompressionPolicy{CompressionAuto, CompressionAlways, CompressionNever}
+}
+
+// String returns the textual representation of the policy.
+func (policy CompressionPolicy) String() string {
+	switch policy {
+	case CompressionAuto:
+		return "Auto"
+	case CompressionAlways:
+		return "Always"
+	case CompressionNever:
+		return "Never"
+	default:
+		return fmt.Sprintf("Unknown%d", int(policy))
+	}
+}
+
+// DefaultCompressionThreshold is the minimum size, in bytes, of resources to be compressed in auto mode.
+// Smaller resources typically become larger when compressed, due to the overhead of the compression format.
+const DefaultCompressionThreshold = 64
+
+// UncompressedContentTypes lists the content types the engine requires to be stored uncompressed.
+//
+// Movies (cutscenes and audio logs) are streamed from the resource file while they are played,
+// and the engine does not decompress them. None of the movies of the original game are compressed.
+func UncompressedContentTypes() []resource.ContentType {
+	return []resource.ContentType{resource.Movie}
+}
+
+// CompressionOptions control which resources are written in compressed form.
+type CompressionOptions struct {
+	// Policies specify the policy per content type. Content types without entry use CompressionAuto.
+	Policies map[resource.ContentType]CompressionPolicy
+	// Threshold is the minimum size, in bytes, for resources to be compressed in auto mode.
+	Threshold int
+}
+
+// DefaultCompressionOptions returns options that use auto mode with the default threshold,
+// while keeping the content types uncompressed that the engine requires so.
+func DefaultCompressionOptions() CompressionOptions {
+	options := CompressionOptions{
+		Policies:  make(map[resource.ContentType]CompressionPolicy),
+		Threshold: DefaultCompressionThreshold,
+	}
+	for _, contentType := range UncompressedContentTypes() {
+		options.Policies[contentType] = CompressionNever
+	}
+	return options
+}
+
+// PolicyFor returns the policy for given content type.
+func (options CompressionOptions) PolicyFor(contentType resource.ContentType) CompressionPolicy {
+	return options.Policies[contentType]
+}
+
+// Compressed returns true if a resource of given properties shall be written in compressed form.
+// Requested is the compression flag of the resource, size is the total size of its uncompressed data.
+func (options CompressionOptions) Compressed(contentType resource.ContentType, requested bool, size int) bool {
+	switch options.PolicyFor(contentType) {
+	case CompressionAlways:
+		return true
+	case CompressionNever:
+		return false
+	default:
+		return requested && (size >= options.Threshold)
+	}
+}
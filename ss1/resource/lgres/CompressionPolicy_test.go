@@ -0,0 +1,49 @@
+package lgres_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/resource/lgres"
+)
+
+func TestCompressionOptionsCompressed(t *testing.T) {
+	options := lgres.CompressionOptions{
+		Policies: map[resource.ContentType]lgres.CompressionPolicy{
+			resource.Bitmap: lgres.CompressionAlways,
+			resource.Movie:  lgres.CompressionNever,
+		},
+		Threshold: 20,
+	}
+	tt := []struct {
+		contentType resource.ContentType
+		requested   bool
+		size        int
+		expected    bool
+	}{
+		{contentType: resource.Text, requested: true, size: 20, expected: true},
+		{contentType: resource.Text, requested: true, size: 19, expected: false},
+		{contentType: resource.Text, requested: false, size: 1000, expected: false},
+		{contentType: resource.Bitmap, requested: false, size: 1, expected: true},
+		{contentType: resource.Movie, requested: true, size: 1000, expected: false},
+	}
+
+	for _, tc := range tt {
+		td := tc
+		t.Run(td.contentType.String(), func(t *testing.T) {
+			assert.Equal(t, td.expected, options.Compressed(td.contentType, td.requested, td.size))
+		})
+	}
+}
+
+func TestDefaultCompressionOptionsKeepRequiredContentTypesUncompressed(t *testing.T) {
+	options := lgres.DefaultCompressionOptions()
+
+	assert.Equal(t, lgres.DefaultCompressionThreshold, options.Threshold)
+	for _, contentType := range lgres.UncompressedContentTypes() {
+		assert.Equal(t, lgres.CompressionNever, options.PolicyFor(contentType), "wrong policy for %v", contentType)
+	}
+	assert.Equal(t, lgres.CompressionAuto, options.PolicyFor(resource.Text))
+}
@@ -0,0 +1,21 @@
+package lgres
+
+import (
+	"io"
+
+	"github.com/inkyblackness/hacked/ss1/resource"
+)
+
+// decompressingReader reports errors of a decompressor as decompression failure of a resource.
+type decompressingReader struct {
+	id     resource.ID
+	source io.Reader
+}
+
+func (reader *decompressingReader) Read(p []byte) (n int, err error) {
+	n, err = reader.source.Read(p)
+	if (err != nil) && (err != io.EOF) {
+		err = resource.ErrDecompressionOf(reader.id, err)
+	}
+	return
+}
@@ -6,7 +6,6 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"io/ioutil"
 
 	"github.com/inkyblackness/hacked/ss1/resource"
 	"github.com/inkyblackness/hacked/ss1/resource/lgres/compression"
@@ -26,10 +25,14 @@ type Reader struct {
 var errSourceNil = errors.New("source is nil")
 var errFormatMismatch = errors.New("format mismatch")
 
+func errMalformedDirectory(cause error) error {
+	return fmt.Errorf("%w: directory unreadable: %v", resource.ErrMalformedData, cause)
+}
+
 // ReaderFrom accesses the provided source and creates a new Reader instance
 // from it.
 // Should the provided decoder not follow the resource file format, an error
-// is returned.
+// is returned. A broken directory results in an error wrapping resource.ErrMalformedData.
 func ReaderFrom(source io.ReaderAt) (reader *Reader, err error) {
 	if source == nil {
 		return nil, errSourceNil
@@ -80,9 +83,9 @@ func (reader *Reader) View(id resource.ID) (retrievedResource resource.View, err
 	contentType := resource.ContentType(entry.contentType())
 
 	if compound {
-		retrievedResource, err = reader.newCompoundResourceReader(entry, contentType, compressed, resourceStartOffset)
+		retrievedResource, err = reader.newCompoundResourceReader(id, entry, contentType, compressed, resourceStartOffset)
 	} else {
-		retrievedResource, err = reader.newSingleBlockResourceReader(entry, contentType, compressed, resourceStartOffset)
+		retrievedResource, err = reader.newSingleBlockResourceReader(id, entry, contentType, compressed, resourceStartOffset)
 	}
 	if err == nil {
 		reader.cache[id.Value()] = retrievedResource
@@ -90,6 +93,17 @@ func (reader *Reader) View(id resource.ID) (retrievedResource resource.View, err
 	return
 }
 
+// Block returns a reader for the identified block of a resource. Other blocks of the resource are not read.
+// For compressed compound resources, the data is decompressed up to the end of the requested block.
+// An error wrapping resource.ErrBlockIndexOutOfRange is returned if the resource does not have the requested block.
+func (reader *Reader) Block(id resource.ID, index int) (io.Reader, error) {
+	view, err := reader.View(id)
+	if err != nil {
+		return nil, err
+	}
+	return view.Block(index)
+}
+
 func readAndVerifyHeader(source io.ReadSeeker) (dirOffset uint32, err error) {
 	coder := serial.NewPositioningDecoder(source)
 	data := make([]byte, resourceDirectoryFileOffsetPos)
@@ -115,7 +129,7 @@ func readDirectoryAt(dirOffset uint32, source io.ReaderAt) (firstResourceOffset
 		headerCoder := serial.NewDecoder(io.NewSectionReader(source, int64(dirOffset), headerSize))
 		headerCoder.Code(&header)
 		if headerCoder.FirstError() != nil {
-			return 0, nil, headerCoder.FirstError()
+			return 0, nil, errMalformedDirectory(headerCoder.FirstError())
 		}
 	}
 
@@ -124,7 +138,9 @@ func readDirectoryAt(dirOffset uint32, source io.ReaderAt) (firstResourceOffset
 	if header.ResourceCount > 0 {
 		listCoder := serial.NewDecoder(io.NewSectionReader(source, int64(dirOffset)+headerSize, int64(binary.Size(directory))))
 		listCoder.Code(directory)
-		err = listCoder.FirstError()
+		if listCoder.FirstError() != nil {
+			return 0, nil, errMalformedDirectory(listCoder.FirstError())
+		}
 	}
 	return
 }
@@ -148,39 +164,48 @@ type blockListEntry struct {
 	size  uint32
 }
 
-func (reader *Reader) newCompoundResourceReader(entry *resourceDirectoryEntry,
+func (reader *Reader) newCompoundResourceReader(id resource.ID, entry *resourceDirectoryEntry,
 	contentType resource.ContentType, compressed bool, resourceStartOffset uint32) (resource.View, error) {
 	resourceDataReader := io.NewSectionReader(reader.source, int64(resourceStartOffset), int64(entry.packedLength()))
 
 	firstBlockOffset, blockList, err := reader.readBlockList(resourceDataReader)
 	if err != nil {
-		return nil, err
+		return nil, resource.ErrMalformedResource(id, err.Error())
 	}
 	blockCount := len(blockList)
+	unpackedLength := int64(entry.unpackedLength())
 
 	rawBlockDataReader := io.NewSectionReader(resourceDataReader, int64(firstBlockOffset), resourceDataReader.Size()-int64(firstBlockOffset))
-	var uncompressedReader io.ReaderAt
-	if !compressed {
-		uncompressedReader = rawBlockDataReader
+	var uncompressedReader io.ReaderAt = rawBlockDataReader
+	var decompressor io.Reader
+	var decompressedData []byte
+	if compressed {
+		decompressor = compression.NewDecompressor(rawBlockDataReader)
 	}
 
 	blockFunc := func(index int) (io.Reader, error) {
 		if (index < 0) || (index >= blockCount) {
-			return nil, fmt.Errorf("block index wrong: %v/%v", index, blockCount)
+			return nil, &resource.IDError{ID: id, Err: resource.ErrBlockIndex(index, blockCount)}
 		}
 
-		if compressed && (uncompressedReader == nil) {
-			decompressor := compression.NewDecompressor(rawBlockDataReader)
-			decompressedData, err := ioutil.ReadAll(decompressor)
+		entry := blockList[index]
+		blockStart := int64(entry.start) - int64(firstBlockOffset)
+		blockEnd := blockStart + int64(entry.size)
+		if compressed && (int64(firstBlockOffset)+blockEnd > unpackedLength) {
+			return nil, resource.ErrDecompressionOf(id, fmt.Errorf("block %d exceeds unpacked length", index))
+		}
+		if compressed && (int64(len(decompressedData)) < blockEnd) {
+			// The compressed stream spans all blocks. Only the data up to the requested block is decompressed.
+			missing := make([]byte, blockEnd-int64(len(decompressedData)))
+			_, err := io.ReadFull(decompressor, missing)
 			if err != nil {
-				return nil, err
+				return nil, resource.ErrDecompressionOf(id, err)
 			}
+			decompressedData = append(decompressedData, missing...)
 			uncompressedReader = bytes.NewReader(decompressedData)
 		}
 
-		entry := blockList[index]
-		reader := io.NewSectionReader(uncompressedReader, int64(entry.start)-int64(firstBlockOffset), int64(entry.size))
-		return reader, nil
+		return io.NewSectionReader(uncompressedReader, blockStart, int64(entry.size)), nil
 	}
 
 	return &readerResource{
@@ -203,6 +228,9 @@ func (reader *Reader) readBlockList(source io.Reader) (uint32, []blockListEntry,
 	for blockIndex := uint16(0); blockIndex < blockCount; blockIndex++ {
 		var endOffset uint32
 		listDecoder.Code(&endOffset)
+		if endOffset < lastBlockEndOffset {
+			return 0, nil, fmt.Errorf("block %d ends before it starts", blockIndex)
+		}
 		blockList[blockIndex].start = lastBlockEndOffset
 		blockList[blockIndex].size = endOffset - lastBlockEndOffset
 		lastBlockEndOffset = endOffset
@@ -211,17 +239,17 @@ func (reader *Reader) readBlockList(source io.Reader) (uint32, []blockListEntry,
 	return firstBlockOffset, blockList, listDecoder.FirstError()
 }
 
-func (reader *Reader) newSingleBlockResourceReader(entry *resourceDirectoryEntry,
+func (reader *Reader) newSingleBlockResourceReader(id resource.ID, entry *resourceDirectoryEntry,
 	contentType resource.ContentType, compressed bool, resourceStartOffset uint32) (resource.View, error) {
 	blockFunc := func(index int) (io.Reader, error) {
 		if index != 0 {
-			return nil, fmt.Errorf("block index wrong: %v/%v", index, 1)
+			return nil, &resource.IDError{ID: id, Err: resource.ErrBlockIndex(index, 1)}
 		}
 		resourceSize := entry.packedLength()
 		var resourceSource io.Reader = io.NewSectionReader(reader.source, int64(resourceStartOffset), int64(entry.packedLength()))
 		if compressed {
 			resourceSize = entry.unpackedLength()
-			resourceSource = compression.NewDecompressor(resourceSource)
+			resourceSource = &decompressingReader{id: id, source: compression.NewDecompressor(resourceSource)}
 		}
 		return io.LimitReader(resourceSource, int64(resourceSize)), nil
 	}
@@ -0,0 +1,31 @@
+package lgres
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+)
+
+func FuzzReader(f *testing.F) {
+	f.Add(exampleResourceFile())
+	f.Add(emptyResourceFile())
+	f.Fuzz(func(t *testing.T, data []byte) {
+		reader, err := ReaderFrom(bytes.NewReader(data))
+		if err != nil {
+			return
+		}
+		for _, id := range reader.IDs() {
+			view, viewErr := reader.View(id)
+			if viewErr != nil {
+				continue
+			}
+			for index := 0; index < view.BlockCount(); index++ {
+				block, blockErr := view.Block(index)
+				if blockErr != nil {
+					continue
+				}
+				_, _ = ioutil.ReadAll(block)
+			}
+		}
+	})
+}
@@ -2,6 +2,7 @@ package lgres
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"testing"
@@ -72,6 +73,10 @@ func TestReaderResourceReturnsErrorForUnknownID(t *testing.T) {
 	resourceReader, err := reader.View(resource.ID(0x1111))
 	assert.Nil(t, resourceReader, "no reader expected")
 	assert.NotNil(t, err)
+	assert.True(t, errors.Is(err, resource.ErrResourceNotFound), "not-found error expected")
+	var idErr *resource.IDError
+	require.True(t, errors.As(err, &idErr), "ID error expected")
+	assert.Equal(t, resource.ID(0x1111), idErr.ID)
 }
 
 func TestReaderResourceReturnsAResourceReaderForKnownID(t *testing.T) {
@@ -142,6 +147,52 @@ func TestReaderResourceWithCompressedCompoundContent(t *testing.T) {
 	verifyBlockContent(t, resourceReader, 2, []byte{0x42})
 }
 
+func TestReaderBlockOfCompressedCompoundContentInReverseOrder(t *testing.T) {
+	reader, _ := ReaderFrom(bytes.NewReader(exampleResourceFile()))
+	resourceReader, _ := reader.View(exampleResourceIDCompoundResourceCompressed)
+
+	verifyBlockContent(t, resourceReader, 1, []byte{0x41, 0x41, 0x41, 0x41})
+	verifyBlockContent(t, resourceReader, 0, []byte{0x40, 0x40})
+	verifyBlockContent(t, resourceReader, 2, []byte{0x42})
+}
+
+func TestReaderBlockReturnsSingleBlock(t *testing.T) {
+	reader, _ := ReaderFrom(bytes.NewReader(exampleResourceFile()))
+	blockReader, err := reader.Block(exampleResourceIDCompoundResource, 1)
+	require.Nil(t, err, "no error expected")
+	data, _ := ioutil.ReadAll(blockReader)
+	assert.Equal(t, []byte{0x31, 0x31, 0x31}, data)
+}
+
+func TestReaderBlockReturnsTypedErrorForInvalidIndex(t *testing.T) {
+	tt := []struct {
+		id    resource.ID
+		index int
+		count int
+	}{
+		{id: exampleResourceIDSingleBlockResource, index: 1, count: 1},
+		{id: exampleResourceIDCompoundResource, index: 2, count: 2},
+		{id: exampleResourceIDCompoundResourceCompressed, index: -1, count: 3},
+	}
+
+	for _, tc := range tt {
+		td := tc
+		t.Run(fmt.Sprintf("%v-%d", td.id, td.index), func(t *testing.T) {
+			reader, _ := ReaderFrom(bytes.NewReader(exampleResourceFile()))
+			_, err := reader.Block(td.id, td.index)
+			require.NotNil(t, err, "error expected")
+			assert.True(t, errors.Is(err, resource.ErrBlockIndexOutOfRange), "error should wrap sentinel")
+			var indexErr *resource.BlockIndexError
+			require.True(t, errors.As(err, &indexErr), "error should be a BlockIndexError")
+			assert.Equal(t, td.index, indexErr.Index)
+			assert.Equal(t, td.count, indexErr.Count)
+			var idErr *resource.IDError
+			require.True(t, errors.As(err, &idErr), "error should name the ID")
+			assert.Equal(t, td.id, idErr.ID)
+		})
+	}
+}
+
 func verifyBlockContent(t *testing.T, blockProvider resource.BlockProvider, blockIndex int, expected []byte) {
 	blockReader, readerErr := blockProvider.Block(blockIndex)
 	assert.Nil(t, readerErr, "error retrieving reader")
@@ -150,3 +201,23 @@ func verifyBlockContent(t *testing.T, blockProvider resource.BlockProvider, bloc
 	assert.Nil(t, dataErr, "no error expected reading data")
 	assert.Equal(t, expected, data)
 }
+
+func TestReaderFromReturnsMalformedErrorOnTruncatedDirectory(t *testing.T) {
+	sourceData := emptyResourceFile()
+
+	_, err := ReaderFrom(bytes.NewReader(sourceData[:len(sourceData)-2]))
+
+	assert.True(t, errors.Is(err, resource.ErrMalformedData), "malformed error expected")
+}
+
+func TestReaderResourceReturnsMalformedErrorOnReversedBlockList(t *testing.T) {
+	sourceData := exampleResourceFile()
+	reader, _ := ReaderFrom(bytes.NewReader(sourceData))
+	startOffset, _ := reader.findEntry(exampleResourceIDCompoundResource.Value())
+	sourceData[startOffset+2+4] = 0x00
+	reader, _ = ReaderFrom(bytes.NewReader(sourceData))
+
+	_, err := reader.View(exampleResourceIDCompoundResource)
+
+	assert.True(t, errors.Is(err, resource.ErrMalformedData), "malformed error expected")
+}
@@ -3,27 +3,63 @@ package lgres
 import (
 	"fmt"
 	"io"
+	"io/ioutil"
+	"sort"
 
 	"github.com/inkyblackness/hacked/ss1/resource"
 )
 
-// Write serializes the resources from given source into the target.
+// WriteOrder specifies in which order resources are written.
+type WriteOrder int
+
+// WriteOrder constants.
+const (
+	// WriteOrderAscendingID writes the resources sorted by their ID.
+	// This produces the same file for the same set of resources, regardless of how they were collected.
+	WriteOrderAscendingID WriteOrder = iota
+	// WriteOrderOriginal writes the resources in the order the source provides their IDs.
+	WriteOrderOriginal
+)
+
+// WriteOptions specify how resources are serialized.
+type WriteOptions struct {
+	// Order specifies in which order resources are written.
+	Order WriteOrder
+	// Compression is consulted for every resource. If nil, the compression flag of the resources is kept.
+	Compression *CompressionOptions
+}
+
+// Write serializes the resources from given source into the target, in order of ascending ID.
 // It is a convenience function for using Writer.
 func Write(target io.WriteSeeker, source resource.Viewer) error {
+	return WriteInOrder(target, source, WriteOrderAscendingID)
+}
+
+// WriteInOrder serializes the resources from given source into the target, in the given order.
+func WriteInOrder(target io.WriteSeeker, source resource.Viewer, order WriteOrder) error {
+	return WriteWith(target, source, WriteOptions{Order: order})
+}
+
+// WriteWith serializes the resources from given source into the target, according to the given options.
+func WriteWith(target io.WriteSeeker, source resource.Viewer, options WriteOptions) error {
 	writer, writerErr := NewWriter(target)
 	if writerErr != nil {
 		return writerErr
 	}
 
-	for _, id := range source.IDs() {
+	for _, id := range orderedIDs(source.IDs(), options.Order) {
 		entry, resourceErr := source.View(id)
 		if resourceErr != nil {
 			return resourceErr
 		}
+		compressed, compressedErr := options.compressed(entry)
+		if compressedErr != nil {
+			return compressedErr
+		}
 
 		switch {
 		case entry.Compound():
-			resourceWriter, resourceWriterErr := writer.CreateCompoundResource(id, entry.ContentType(), entry.Compressed())
+			resourceWriter, resourceWriterErr := writer.CreateCompoundResource(id, entry.ContentType(), compressed)
 			if resourceWriterErr != nil {
 				return resourceWriterErr
 			}
@@ -32,7 +68,7 @@ func Write(target io.WriteSeeker, source resource.Viewer) error {
 				return copyErr
 			}
 		case entry.BlockCount() == 1:
-			blockWriter, resourceWriterErr := writer.CreateResource(id, entry.ContentType(), entry.Compressed())
+			blockWriter, resourceWriterErr := writer.CreateResource(id, entry.ContentType(), compressed)
 			if resourceWriterErr != nil {
 				return resourceWriterErr
 			}
@@ -48,6 +84,46 @@ func Write(target io.WriteSeeker, source resource.Viewer) error {
 	return writer.Finish()
 }
 
+func (options WriteOptions) compressed(entry resource.View) (bool, error) {
+	if options.Compression == nil {
+		return entry.Compressed(), nil
+	}
+	if !entry.Compressed() || (options.Compression.PolicyFor(entry.ContentType()) != CompressionAuto) {
+		return options.Compression.Compressed(entry.ContentType(), entry.Compressed(), 0), nil
+	}
+	size, err := uncompressedSize(entry)
+	if err != nil {
+		return false, err
+	}
+	return options.Compression.Compressed(entry.ContentType(), true, size), nil
+}
+
+func uncompressedSize(source resource.BlockProvider) (int, error) {
+	size := 0
+	for blockIndex := 0; blockIndex < source.BlockCount(); blockIndex++ {
+		blockReader, blockErr := source.Block(blockIndex)
+		if blockErr != nil {
+			return 0, blockErr
+		}
+		blockSize, copyErr := io.Copy(ioutil.Discard, blockReader)
+		if copyErr != nil {
+			return 0, copyErr
+		}
+		size += int(blockSize)
+	}
+	return size, nil
+}
+
+func orderedIDs(ids []resource.ID, order WriteOrder) []resource.ID {
+	if order == WriteOrderOriginal {
+		return ids
+	}
+	sorted := make([]resource.ID, len(ids))
+	copy(sorted, ids)
+	sort.Slice(sorted, func(a, b int) bool { return sorted[a] < sorted[b] })
+	return sorted
+}
+
 func copyBlocks(source resource.BlockProvider, nextWriter func() io.Writer) error {
 	for blockIndex := 0; blockIndex < source.BlockCount(); blockIndex++ {
 		blockReader, blockErr := source.Block(blockIndex)
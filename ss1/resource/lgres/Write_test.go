@@ -2,6 +2,7 @@ package lgres_test
 
 import (
 	"bytes"
+	"io/ioutil"
 	"testing"
 
 	"github.com/inkyblackness/hacked/ss1/resource"
@@ -9,6 +10,7 @@ import (
 	"github.com/inkyblackness/hacked/ss1/serial"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestWrite(t *testing.T) {
@@ -30,7 +32,7 @@ func TestWrite(t *testing.T) {
 	_ = store.Put(resource.ID(2), aResource(true, resource.Geometry, false, resource.BlocksFrom([][]byte{{0x31}})))
 	_ = store.Put(resource.ID(4), aResource(true, resource.Archive, true, resource.BlocksFrom([][]byte{{0x41}, {0x42, 0x43}})))
 
-	errWrite := lgres.Write(target, store)
+	errWrite := lgres.WriteInOrder(target, store, lgres.WriteOrderOriginal)
 	if errWrite != nil {
 		assert.Nil(t, errWrite, "no error expected writing")
 	}
@@ -42,3 +44,65 @@ func TestWrite(t *testing.T) {
 
 	assert.Equal(t, []resource.ID{resource.ID(1), resource.ID(3), resource.ID(2), resource.ID(4)}, reader.IDs())
 }
+
+func TestWriteSortsResourcesByIDByDefault(t *testing.T) {
+	var store resource.Store
+	for _, id := range []resource.ID{0x0200, 0x0100, 0x0300} {
+		_ = store.Put(id, resource.Resource{
+			Properties: resource.Properties{ContentType: resource.Text},
+			Blocks:     resource.BlocksFrom([][]byte{{byte(id >> 8)}}),
+		})
+	}
+
+	target := serial.NewByteStore()
+	err := lgres.Write(target, store)
+	require.Nil(t, err, "no error expected writing")
+	reader, err := lgres.ReaderFrom(bytes.NewReader(target.Data()))
+	require.Nil(t, err, "no error expected reading")
+	assert.Equal(t, []resource.ID{0x0100, 0x0200, 0x0300}, reader.IDs())
+
+	var reversed resource.Store
+	for _, id := range []resource.ID{0x0300, 0x0200, 0x0100} {
+		view, _ := store.View(id)
+		_ = reversed.Put(id, view)
+	}
+	otherTarget := serial.NewByteStore()
+	err = lgres.Write(otherTarget, reversed)
+	require.Nil(t, err, "no error expected writing again")
+	assert.Equal(t, target.Data(), otherTarget.Data(), "files should be identical")
+}
+
+func TestWriteWithCompressionOptions(t *testing.T) {
+	var store resource.Store
+	put := func(id resource.ID, contentType resource.ContentType, compressed bool, size int) {
+		_ = store.Put(id, resource.Resource{
+			Properties: resource.Properties{ContentType: contentType, Compressed: compressed},
+			Blocks:     resource.BlocksFrom([][]byte{bytes.Repeat([]byte{0xAA}, size)}),
+		})
+	}
+	put(0x0100, resource.Text, true, 10)
+	put(0x0200, resource.Text, true, 100)
+	put(0x0300, resource.Text, false, 100)
+	put(0x0400, resource.Movie, true, 100)
+	put(0x0500, resource.Bitmap, false, 10)
+
+	options := lgres.DefaultCompressionOptions()
+	options.Policies[resource.Bitmap] = lgres.CompressionAlways
+	target := serial.NewByteStore()
+	err := lgres.WriteWith(target, store, lgres.WriteOptions{Compression: &options})
+	require.Nil(t, err, "no error expected writing")
+	reader, err := lgres.ReaderFrom(bytes.NewReader(target.Data()))
+	require.Nil(t, err, "no error expected reading")
+
+	expected := map[resource.ID]bool{0x0100: false, 0x0200: true, 0x0300: false, 0x0400: false, 0x0500: true}
+	for id, compressed := range expected {
+		view, viewErr := reader.View(id)
+		require.Nil(t, viewErr, "no error expected for %v", id)
+		assert.Equal(t, compressed, view.Compressed(), "wrong compression for %v", id)
+		blockReader, blockErr := view.Block(0)
+		require.Nil(t, blockErr, "no error expected for block of %v", id)
+		data, dataErr := ioutil.ReadAll(blockReader)
+		require.Nil(t, dataErr, "no error expected reading data of %v", id)
+		assert.Equal(t, bytes.Repeat([]byte{0xAA}, len(data)), data, "wrong data for %v", id)
+	}
+}
@@ -2,12 +2,14 @@ package rle
 
 import (
 	"errors"
+	"fmt"
 	"io"
 )
 
 // Decompress decompresses from the given reader and writes into the provided output buffer.
 // The output buffer must be pre-allocated.
 // If it contains non-zero data, this data may be preserved if the compressed data specifies to.
+// Should the compressed data write or skip past the end of the output buffer, an error is returned.
 func Decompress(reader io.Reader, output []byte) (err error) {
 	outIndex := 0
 	done := false
@@ -16,6 +18,20 @@ func Decompress(reader io.Reader, output []byte) (err error) {
 		_, err = reader.Read(zz)
 		return zz[0]
 	}
+	run := func(count int) []byte {
+		if outIndex+count > len(output) {
+			err = fmt.Errorf("run of %d bytes at offset %d exceeds output of %d bytes", count, outIndex, len(output))
+			return nil
+		}
+		return output[outIndex : outIndex+count]
+	}
+	skip := func(count int) {
+		if outIndex+count > len(output) {
+			err = fmt.Errorf("skip of %d bytes at offset %d exceeds output of %d bytes", count, outIndex, len(output))
+			return
+		}
+		outIndex += count
+	}
 
 	for !done && (err == nil) {
 		first := nextByte()
@@ -25,9 +41,9 @@ func Decompress(reader io.Reader, output []byte) (err error) {
 			nn := nextByte()
 			zz := nextByte()
 
-			outIndex += writeBytesOfValue(output[outIndex:outIndex+int(nn)], func() byte { return zz })
+			outIndex += writeBytesOfValue(run(int(nn)), func() byte { return zz })
 		case first < 0x80:
-			outIndex += writeBytesOfValue(output[outIndex:outIndex+int(first)], nextByte)
+			outIndex += writeBytesOfValue(run(int(first)), nextByte)
 		case first == 0x80:
 			control := uint16(nextByte())
 			control += uint16(nextByte()) << 8
@@ -35,18 +51,18 @@ func Decompress(reader io.Reader, output []byte) (err error) {
 			case control == 0x0000:
 				done = true
 			case control < 0x8000:
-				outIndex += int(control)
+				skip(int(control))
 			case control < 0xC000:
-				outIndex += writeBytesOfValue(output[outIndex:outIndex+int(control&0x3FFF)], nextByte)
+				outIndex += writeBytesOfValue(run(int(control&0x3FFF)), nextByte)
 			case (control & 0xFF00) == 0xC000:
 				err = errors.New("undefined case 80 nn C0")
 			default:
 				zz := nextByte()
 
-				outIndex += writeBytesOfValue(output[outIndex:outIndex+int(control&0x3FFF)], func() byte { return zz })
+				outIndex += writeBytesOfValue(run(int(control&0x3FFF)), func() byte { return zz })
 			}
 		default:
-			outIndex += int(first & 0x7F)
+			skip(int(first & 0x7F))
 		}
 	}
 
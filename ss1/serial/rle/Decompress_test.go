@@ -144,3 +144,27 @@ func TestDecompressNNMore80WritesZeroes(t *testing.T) {
 	require.Nil(t, err)
 	assert.Equal(t, []byte{0, 0, 0}, result)
 }
+
+func TestDecompressReturnsErrorForRunsExceedingOutput(t *testing.T) {
+	tt := []struct {
+		name  string
+		input []byte
+	}{
+		{name: "fill 00", input: []byte{0x00, 0x05, 0xCC, 0x80, 0x00, 0x00}},
+		{name: "literal", input: []byte{0x03, 0xAA, 0xBB, 0xCC, 0x80, 0x00, 0x00}},
+		{name: "short skip", input: []byte{0x85, 0x80, 0x00, 0x00}},
+		{name: "long skip", input: []byte{0x80, 0x05, 0x00, 0x80, 0x00, 0x00}},
+		{name: "extended literal", input: []byte{0x80, 0x03, 0x80, 0xAA, 0xBB, 0xCC, 0x80, 0x00, 0x00}},
+		{name: "extended fill", input: []byte{0x80, 0x05, 0xC1, 0xCD, 0x80, 0x00, 0x00}},
+		{name: "literal after skip", input: []byte{0x80, 0x00, 0x40, 0x04, 0x01, 0x02, 0x03, 0x04, 0x80, 0x00, 0x00}},
+	}
+
+	for _, tc := range tt {
+		td := tc
+		t.Run(td.name, func(t *testing.T) {
+			result := make([]byte, 2)
+			err := rle.Decompress(bytes.NewReader(td.input), result)
+			assert.NotNil(t, err)
+		})
+	}
+}
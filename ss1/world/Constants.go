@@ -1,5 +1,7 @@
 package world
 
+import "github.com/inkyblackness/hacked/ss1/world/ids"
+
 const (
 	// StartingLevel identifies the level a new game is started in by default.
 	StartingLevel = 1
@@ -10,7 +12,7 @@ const (
 
 	// MaxWorldTextures is the limit of how many textures the engine supports.
 	// Note that this value is equal to that of the resource limits in package ids. It is actually based on them.
-	MaxWorldTextures = 293
+	MaxWorldTextures = ids.MaxTextures
 )
 
 const (
@@ -0,0 +1,95 @@
+package world
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// FileBackupSuffix is the extension of backup files.
+const FileBackupSuffix = ".bak"
+
+// FileBackup saves files and keeps copies of their previous versions.
+// A backup is only created for files that have not been written by the same instance before,
+// so that only the originals, and not intermediate states, are preserved.
+type FileBackup struct {
+	// Retain is the number of backups to keep per file. Older backups are removed.
+	// With a value of zero, no backups are created.
+	Retain int
+
+	written map[string]bool
+}
+
+// BackupFilename returns the name of a backup of given file, created at the given time.
+func BackupFilename(filename string, at time.Time) string {
+	return filename + "." + at.Format("20060102-150405.000") + FileBackupSuffix
+}
+
+// Backups returns the names of all backups of given file, oldest first.
+func Backups(filename string) []string {
+	dir, base := filepath.Split(filename)
+	infos, err := ioutil.ReadDir(filepath.Clean(dir + "."))
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, info := range infos {
+		name := info.Name()
+		if !info.IsDir() && strings.HasPrefix(name, base+".") && strings.HasSuffix(name, FileBackupSuffix) {
+			names = append(names, filepath.Join(dir, name))
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Save creates a backup of the given file, if necessary, and then writes it via SaveFile().
+func (backup *FileBackup) Save(filename string, write func(io.WriteSeeker) error) error {
+	absFilename, err := filepath.Abs(filename)
+	if err != nil {
+		return err
+	}
+	if (backup.Retain > 0) && !backup.written[absFilename] {
+		err = backup.create(filename)
+		if err != nil {
+			return err
+		}
+	}
+	err = SaveFile(filename, write)
+	if err != nil {
+		return err
+	}
+	if backup.written == nil {
+		backup.written = make(map[string]bool)
+	}
+	backup.written[absFilename] = true
+	return nil
+}
+
+func (backup *FileBackup) create(filename string) error {
+	original, err := os.Open(filename)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer func() { _ = original.Close() }()
+	err = SaveFile(BackupFilename(filename, time.Now()), func(writer io.WriteSeeker) error {
+		_, copyErr := io.Copy(writer, original)
+		return copyErr
+	})
+	if err != nil {
+		return err
+	}
+	existing := Backups(filename)
+	for len(existing) > backup.Retain {
+		_ = os.Remove(existing[0])
+		existing = existing[1:]
+	}
+	return nil
+}
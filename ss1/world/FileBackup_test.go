@@ -0,0 +1,78 @@
+package world_test
+
+import (
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/inkyblackness/hacked/ss1/world"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeString(content string) func(io.WriteSeeker) error {
+	return func(writer io.WriteSeeker) error {
+		_, err := writer.Write([]byte(content))
+		return err
+	}
+}
+
+func TestFileBackupCreatesBackupOfOriginal(t *testing.T) {
+	dir, cleanup := tempSaveDir(t)
+	defer cleanup()
+	filename := filepath.Join(dir, "test.res")
+	require.Nil(t, ioutil.WriteFile(filename, []byte("original"), 0600))
+	backup := world.FileBackup{Retain: 2}
+
+	err := backup.Save(filename, writeString("new"))
+
+	require.Nil(t, err, "no error expected")
+	backups := world.Backups(filename)
+	require.Equal(t, 1, len(backups), "one backup expected")
+	data, _ := ioutil.ReadFile(backups[0])
+	assert.Equal(t, "original", string(data))
+}
+
+func TestFileBackupIgnoresFilesWrittenBefore(t *testing.T) {
+	dir, cleanup := tempSaveDir(t)
+	defer cleanup()
+	filename := filepath.Join(dir, "test.res")
+	backup := world.FileBackup{Retain: 2}
+
+	require.Nil(t, backup.Save(filename, writeString("first")))
+	require.Nil(t, backup.Save(filename, writeString("second")))
+
+	assert.Empty(t, world.Backups(filename), "no backups expected")
+}
+
+func TestFileBackupRetainsOnlyLimitedNumber(t *testing.T) {
+	dir, cleanup := tempSaveDir(t)
+	defer cleanup()
+	filename := filepath.Join(dir, "test.res")
+	for index := 0; index < 3; index++ {
+		require.Nil(t, ioutil.WriteFile(filename, []byte{byte('a' + index)}, 0600))
+		backup := world.FileBackup{Retain: 2}
+		require.Nil(t, backup.Save(filename, writeString("new")))
+		time.Sleep(2 * time.Millisecond)
+	}
+
+	backups := world.Backups(filename)
+	require.Equal(t, 2, len(backups), "two backups expected")
+	data, _ := ioutil.ReadFile(backups[0])
+	assert.Equal(t, "b", string(data), "oldest backup should have been removed")
+}
+
+func TestFileBackupCreatesNoBackupsIfDisabled(t *testing.T) {
+	dir, cleanup := tempSaveDir(t)
+	defer cleanup()
+	filename := filepath.Join(dir, "test.res")
+	require.Nil(t, ioutil.WriteFile(filename, []byte("original"), 0600))
+	var backup world.FileBackup
+
+	require.Nil(t, backup.Save(filename, writeString("new")))
+
+	assert.Empty(t, world.Backups(filename), "no backups expected")
+}
@@ -2,7 +2,6 @@ package world
 
 import (
 	"bytes"
-	"errors"
 	"fmt"
 	"time"
 
@@ -27,17 +26,31 @@ type Mod struct {
 
 	modPath        string
 	lastChangeTime time.Time
+	changeCount    uint64
 	changedFiles   map[string]struct{}
 
+	savedStates        map[ResourceKey]savedResourceState
+	dirtyKeys          map[ResourceKey]struct{}
+	propertiesModified bool
+
 	data ModData
 }
 
+// savedResourceState keeps the content of a resource as it was at the last save.
+// An unknown state can never be reached again through modification.
+type savedResourceState struct {
+	known  bool
+	blocks [][]byte
+}
+
 // NewMod returns a new instance.
 func NewMod(resourcesChanged resource.ModificationCallback, resetCallback ModResetCallback) *Mod {
 	mod := &Mod{
 		resourcesChanged: resourcesChanged,
 		resetCallback:    resetCallback,
 		changedFiles:     make(map[string]struct{}),
+		savedStates:      make(map[ResourceKey]savedResourceState),
+		dirtyKeys:        make(map[ResourceKey]struct{}),
 	}
 	mod.worldManifest = NewManifest(mod.worldChanged)
 	mod.data.FileChangeCallback = mod.markFileChanged
@@ -80,26 +93,108 @@ func (mod *Mod) LastChangeTime() time.Time {
 	return mod.lastChangeTime
 }
 
+// ChangeCount returns the number of changes made since the mod was created.
+// The value increases with every change and can be used to detect whether the mod was changed since a previous query.
+func (mod *Mod) ChangeCount() uint64 {
+	return mod.changeCount
+}
+
 // ResetLastChangeTime clears the last change timestamp. It will be set again at the next modification.
 func (mod *Mod) ResetLastChangeTime() {
 	mod.lastChangeTime = time.Time{}
 }
 
-// MarkSave clears the list of modified filenames.
+// MarkSave clears the list of modified filenames and the set of dirty resources.
+// The current state becomes the new reference for detecting unsaved changes.
 func (mod *Mod) MarkSave() {
 	mod.changedFiles = make(map[string]struct{})
 	mod.lastChangeTime = time.Time{}
+	mod.clearDirtyState()
+}
+
+// DirtyKeys returns the keys of all resources that differ from their state at the last save.
+// The keys are sorted by language, then by ID.
+func (mod Mod) DirtyKeys() []ResourceKey {
+	keys := make([]ResourceKey, 0, len(mod.dirtyKeys))
+	for key := range mod.dirtyKeys {
+		keys = append(keys, key)
+	}
+	sortResourceKeys(keys)
+	return keys
+}
+
+// IsDirty returns true if the identified resource has unsaved changes.
+func (mod Mod) IsDirty(key ResourceKey) bool {
+	_, dirty := mod.dirtyKeys[key]
+	return dirty
+}
+
+// HasUnsavedChanges returns true if any resource differs from its state at the last save,
+// or if the object or texture properties were modified since then.
+// Modifications of properties are considered unsaved until the next save, even if reverted.
+func (mod Mod) HasUnsavedChanges() bool {
+	return (len(mod.dirtyKeys) > 0) || mod.propertiesModified
+}
+
+func (mod *Mod) clearDirtyState() {
+	mod.savedStates = make(map[ResourceKey]savedResourceState)
+	mod.dirtyKeys = make(map[ResourceKey]struct{})
+	mod.propertiesModified = false
+}
+
+func (mod *Mod) captureSavedStates(keys []ResourceKey) {
+	for _, key := range keys {
+		if _, captured := mod.savedStates[key]; !captured {
+			mod.savedStates[key] = savedResourceState{known: true, blocks: mod.ModifiedBlocks(key.Lang, key.ID)}
+		}
+	}
+}
+
+func (mod *Mod) updateDirtyKeys(keys []ResourceKey) {
+	for _, key := range keys {
+		state := mod.savedStates[key]
+		if state.known && blocksEqual(state.blocks, mod.ModifiedBlocks(key.Lang, key.ID)) {
+			delete(mod.dirtyKeys, key)
+		} else {
+			mod.dirtyKeys[key] = struct{}{}
+		}
+	}
+}
+
+func (mod *Mod) markUnknownSavedState(key ResourceKey) {
+	mod.savedStates[key] = savedResourceState{known: false}
+	mod.dirtyKeys[key] = struct{}{}
+}
+
+func blocksEqual(a, b [][]byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for index := range a {
+		if !bytes.Equal(a[index], b[index]) {
+			return false
+		}
+	}
+	return true
 }
 
 // ModifiedResource retrieves the resource of given language and ID.
 // There is no fallback lookup, it will return the exact resource stored under the provided identifier.
 // Returns nil if the resource does not exist.
 func (mod Mod) ModifiedResource(lang resource.Language, id resource.ID) resource.View {
-	return mod.modifiedResource(lang, id)
+	res := mod.modifiedResource(lang, id)
+	if res == nil {
+		return nil
+	}
+	return res
 }
 
 func (mod Mod) modifiedResource(lang resource.Language, id resource.ID) *resource.Resource {
-	for _, entry := range mod.data.LocalizedResources {
+	return modifiedResourceIn(mod.data.LocalizedResources, lang, id)
+}
+
+func modifiedResourceIn(localized []*LocalizedResources, lang resource.Language, id resource.ID) *resource.Resource {
+	for _, entry := range localized {
 		if entry.Language == lang {
 			res, err := entry.Store.Resource(id)
 			if err == nil {
@@ -125,7 +220,7 @@ func (mod Mod) CreateBlockPatch(lang resource.Language, id resource.ID, index in
 	}
 	res := mod.modifiedResource(lang, id)
 	if res == nil {
-		return patch, false, errors.New("resource unknown")
+		return patch, false, resource.ErrResourceDoesNotExist(id)
 	}
 	oldData, err := res.BlockRaw(index)
 	if err != nil {
@@ -188,13 +283,16 @@ func (mod Mod) blockCopy(data []byte) []byte {
 
 // Filter returns a list of resources that match the given parameters.
 func (mod Mod) Filter(lang resource.Language, id resource.ID) resource.List {
-	list := mod.worldManifest.Filter(lang, id)
-	if res := mod.modifiedResource(resource.LangAny, id); res != nil {
+	return filterModified(mod.worldManifest.Filter(lang, id), mod.data.LocalizedResources, lang, id)
+}
+
+func filterModified(list resource.List, localized []*LocalizedResources, lang resource.Language, id resource.ID) resource.List {
+	if res := modifiedResourceIn(localized, resource.LangAny, id); res != nil {
 		list = list.With(res)
 	}
 	for _, worldLang := range resource.Languages() {
 		if worldLang.Includes(lang) {
-			if res := mod.modifiedResource(lang, id); res != nil {
+			if res := modifiedResourceIn(localized, lang, id); res != nil {
 				list = list.With(res)
 			}
 		}
@@ -216,11 +314,13 @@ func (mod Mod) LocalizedResources(lang resource.Language) resource.Selector {
 func (mod *Mod) Modify(modifier func(Modder)) {
 	var trans ModTransaction
 	modifier(&trans)
+	mod.captureSavedStates(trans.modifiedKeys)
 	mod.modifyAndNotify(func() {
 		for _, action := range trans.actions {
 			action(&mod.data)
 		}
 	}, trans.modifiedIDs.ToList())
+	mod.updateDirtyKeys(trans.modifiedKeys)
 }
 
 // ObjectProperties returns the table of object properties.
@@ -286,13 +386,18 @@ func (mod *Mod) Reset(newResources []*LocalizedResources, objectProperties objec
 	mod.data.TextureProperties = textureProperties
 	mod.changedFiles = make(map[string]struct{})
 	mod.lastChangeTime = time.Time{}
+	mod.clearDirtyState()
 	mod.resetCallback()
 	mod.resourcesChanged(modifiedIDs.ToList(), nil)
 }
 
 func (mod *Mod) markFileChanged(filename string) {
 	mod.changedFiles[filename] = struct{}{}
+	if (filename == ObjectPropertiesFilename) || (filename == TexturePropertiesFilename) {
+		mod.propertiesModified = true
+	}
 	mod.lastChangeTime = time.Now()
+	mod.changeCount++
 }
 
 // FixListResources ensures all resources that contain resource lists to
@@ -42,11 +42,17 @@ func (data *ModData) SetResourceBlock(lang resource.Language, id resource.ID, in
 // PatchResourceBlock modifies an existing block.
 // This modification assumes the block already exists and can take the given patch data.
 // The patch data is expected to be produced by rle.Compress().
+//
+// The patch is applied to a copy of the block, which then replaces the block. Block data is never modified
+// in place, so that it can be shared, for example by snapshots.
 func (data *ModData) PatchResourceBlock(lang resource.Language, id resource.ID, index int, expectedLength int, patch []byte) {
 	loc, res := data.ensureResource(lang, id)
 	raw, err := res.BlockRaw(index)
 	if (err == nil) && (len(raw) == expectedLength) {
-		_ = rle.Decompress(bytes.NewReader(patch), raw)
+		patched := make([]byte, len(raw))
+		copy(patched, raw)
+		_ = rle.Decompress(bytes.NewReader(patch), patched)
+		res.SetBlock(index, patched)
 		data.notifyFileChanged(loc.Filename)
 	}
 }
@@ -59,6 +65,14 @@ func (data *ModData) SetResourceBlocks(lang resource.Language, id resource.ID, b
 	data.notifyFileChanged(loc.Filename)
 }
 
+// SetResourceProperties changes the meta information of a resource.
+// If the resource does not exist, it will be created without blocks.
+func (data *ModData) SetResourceProperties(lang resource.Language, id resource.ID, properties resource.Properties) {
+	loc, res := data.ensureResource(lang, id)
+	res.Properties = properties
+	data.notifyFileChanged(loc.Filename)
+}
+
 // DelResource removes a resource from the mod in the given language.
 func (data *ModData) DelResource(lang resource.Language, id resource.ID) {
 	for _, loc := range data.LocalizedResources {
@@ -0,0 +1,92 @@
+package world_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/inkyblackness/hacked/ss1/content/texture"
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/world"
+)
+
+func newDirtyTestMod() *world.Mod {
+	return world.NewMod(func([]resource.ID, []resource.ID) {}, func() {})
+}
+
+func TestModIsInitiallyWithoutUnsavedChanges(t *testing.T) {
+	mod := newDirtyTestMod()
+
+	assert.False(t, mod.HasUnsavedChanges())
+	assert.Empty(t, mod.DirtyKeys())
+}
+
+func TestModMarksModifiedResourcesDirty(t *testing.T) {
+	mod := newDirtyTestMod()
+	mod.Modify(func(modder world.Modder) {
+		modder.SetResourceBlock(resource.LangGerman, resource.ID(0x1000), 0, []byte{0x01})
+		modder.SetResourceBlock(resource.LangAny, resource.ID(0x2000), 0, []byte{0x02})
+	})
+
+	assert.True(t, mod.HasUnsavedChanges())
+	assert.Equal(t, []world.ResourceKey{
+		world.ResourceKeyOf(resource.LangGerman, resource.ID(0x1000)),
+		world.ResourceKeyOf(resource.LangAny, resource.ID(0x2000)),
+	}, mod.DirtyKeys())
+	assert.False(t, mod.IsDirty(world.ResourceKeyOf(resource.LangAny, resource.ID(0x1000))), "other language should not be dirty")
+}
+
+func TestModClearsDirtyKeysOnSave(t *testing.T) {
+	mod := newDirtyTestMod()
+	mod.Modify(func(modder world.Modder) {
+		modder.SetResourceBlock(resource.LangAny, resource.ID(0x1000), 0, []byte{0x01})
+	})
+	mod.MarkSave()
+
+	assert.False(t, mod.HasUnsavedChanges())
+	assert.Empty(t, mod.DirtyKeys())
+}
+
+func TestModClearsDirtyKeyWhenReturningToSavedState(t *testing.T) {
+	mod := newDirtyTestMod()
+	key := world.ResourceKeyOf(resource.LangAny, resource.ID(0x1000))
+	mod.Modify(func(modder world.Modder) {
+		modder.SetResourceBlock(key.Lang, key.ID, 0, []byte{0x01})
+	})
+	mod.MarkSave()
+	mod.Modify(func(modder world.Modder) {
+		modder.SetResourceBlock(key.Lang, key.ID, 0, []byte{0x02})
+	})
+	assert.True(t, mod.IsDirty(key), "key should be dirty after change")
+	mod.Modify(func(modder world.Modder) {
+		modder.SetResourceBlock(key.Lang, key.ID, 0, []byte{0x01})
+	})
+
+	assert.False(t, mod.IsDirty(key), "key should be clean after reverting")
+	assert.False(t, mod.HasUnsavedChanges())
+}
+
+func TestModClearsDirtyKeyWhenNewResourceIsRemovedAgain(t *testing.T) {
+	mod := newDirtyTestMod()
+	mod.Modify(func(modder world.Modder) {
+		modder.SetResourceBlock(resource.LangAny, resource.ID(0x1000), 0, []byte{0x01})
+	})
+	mod.Modify(func(modder world.Modder) {
+		modder.DelResource(resource.LangAny, resource.ID(0x1000))
+	})
+
+	assert.False(t, mod.HasUnsavedChanges())
+}
+
+func TestModConsidersModifiedPropertiesUnsaved(t *testing.T) {
+	mod := newDirtyTestMod()
+	mod.Reset(nil, nil, texture.PropertiesList{{}})
+	mod.Modify(func(modder world.Modder) {
+		modder.SetTextureProperties(0, texture.Properties{Climbable: 1})
+	})
+
+	assert.True(t, mod.HasUnsavedChanges())
+	assert.Empty(t, mod.DirtyKeys())
+	mod.MarkSave()
+	assert.False(t, mod.HasUnsavedChanges())
+}
@@ -0,0 +1,69 @@
+package world
+
+import "github.com/inkyblackness/hacked/ss1/resource"
+
+// ModSnapshot is a read-only capture of the resources of a mod at a certain point in time.
+// It does not change when the mod is modified afterwards and can therefore be used
+// concurrently to ongoing edits, for example to compare against, or to export from.
+//
+// A snapshot implements resource.Filter and can be used for resource.Selector, just as the mod itself.
+type ModSnapshot struct {
+	worldEntries []*ManifestEntry
+	modified     []*LocalizedResources
+	changeCount  uint64
+}
+
+// Snapshot captures the current state of the resources of the mod.
+// The resources of the world are shared, as they are not modified. The data of the blocks of the mod
+// are shared as well, as the mod replaces blocks instead of modifying them in place.
+// Only the lists of resources and blocks are copied, which makes taking a snapshot cheap.
+func (mod Mod) Snapshot() ModSnapshot {
+	snapshot := ModSnapshot{
+		worldEntries: make([]*ManifestEntry, len(mod.worldManifest.entries)),
+		modified:     make([]*LocalizedResources, 0, len(mod.data.LocalizedResources)),
+		changeCount:  mod.changeCount,
+	}
+	copy(snapshot.worldEntries, mod.worldManifest.entries)
+	for _, loc := range mod.data.LocalizedResources {
+		snapshot.modified = append(snapshot.modified, &LocalizedResources{
+			Filename: loc.Filename,
+			Language: loc.Language,
+			Store:    loc.Store.Shared(),
+		})
+	}
+	return snapshot
+}
+
+// ChangeCount returns the change count of the mod at the time the snapshot was taken.
+// Comparing this value with the current one of the mod tells whether the snapshot is outdated.
+func (snapshot ModSnapshot) ChangeCount() uint64 {
+	return snapshot.changeCount
+}
+
+// ModifiedResource retrieves the resource of given language and ID, as it was modified by the mod.
+// Returns nil if the resource does not exist.
+func (snapshot ModSnapshot) ModifiedResource(lang resource.Language, id resource.ID) resource.View {
+	res := modifiedResourceIn(snapshot.modified, lang, id)
+	if res == nil {
+		return nil
+	}
+	return res
+}
+
+// Filter returns a list of resources that match the given parameters.
+func (snapshot ModSnapshot) Filter(lang resource.Language, id resource.ID) resource.List {
+	var list resource.List
+	for _, entry := range snapshot.worldEntries {
+		list = list.Joined(entry.Resources.Filter(lang, id))
+	}
+	return filterModified(list, snapshot.modified, lang, id)
+}
+
+// LocalizedResources returns a resource selector for a specific language.
+func (snapshot ModSnapshot) LocalizedResources(lang resource.Language) resource.Selector {
+	return resource.Selector{
+		Lang: lang,
+		From: snapshot,
+		As:   ResourceViewStrategy(),
+	}
+}
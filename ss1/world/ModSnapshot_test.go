@@ -0,0 +1,57 @@
+package world_test
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/world"
+)
+
+func TestModSnapshotKeepsStateOfCapture(t *testing.T) {
+	mod := world.NewMod(func([]resource.ID, []resource.ID) {}, func() {})
+	mod.Modify(func(modder world.Modder) {
+		modder.SetResourceBlock(resource.LangAny, resource.ID(0x1000), 0, []byte{0x01, 0x02})
+	})
+	snapshot := mod.Snapshot()
+
+	patch, changed, err := mod.CreateBlockPatch(resource.LangAny, resource.ID(0x1000), 0, []byte{0x03, 0x04})
+	require.Nil(t, err, "no error expected creating patch")
+	require.True(t, changed, "patch should change data")
+	mod.Modify(func(modder world.Modder) {
+		modder.PatchResourceBlock(resource.LangAny, patch.ID, patch.BlockIndex, patch.BlockLength, patch.ForwardData)
+		modder.SetResourceBlock(resource.LangAny, resource.ID(0x1001), 0, []byte{0x05})
+	})
+
+	assert.Equal(t, []byte{0x01, 0x02}, snapshotBlock(t, snapshot, resource.ID(0x1000)))
+	assert.Nil(t, snapshot.ModifiedResource(resource.LangAny, resource.ID(0x1001)), "new resource should not be in snapshot")
+	assert.Equal(t, []byte{0x03, 0x04}, mod.ModifiedBlock(resource.LangAny, resource.ID(0x1000), 0))
+	assert.True(t, mod.ChangeCount() > snapshot.ChangeCount(), "snapshot should be outdated")
+}
+
+func TestModSnapshotFiltersLikeMod(t *testing.T) {
+	mod := world.NewMod(func([]resource.ID, []resource.ID) {}, func() {})
+	mod.Modify(func(modder world.Modder) {
+		modder.SetResourceBlock(resource.LangAny, resource.ID(0x1000), 0, []byte{0x01})
+		modder.SetResourceBlock(resource.LangGerman, resource.ID(0x1002), 0, []byte{0x02})
+	})
+	snapshot := mod.Snapshot()
+
+	for _, id := range []resource.ID{0x1000, 0x1002, 0x1003} {
+		for _, lang := range []resource.Language{resource.LangAny, resource.LangDefault, resource.LangGerman} {
+			assert.Equal(t, len(mod.Filter(lang, id)), len(snapshot.Filter(lang, id)), "mismatch for %v in %v", id, lang)
+		}
+	}
+}
+
+func snapshotBlock(t *testing.T, snapshot world.ModSnapshot, id resource.ID) []byte {
+	t.Helper()
+	reader, err := snapshot.LocalizedResources(resource.LangAny).SelectBlock(id, 0)
+	require.Nil(t, err, "no error expected selecting block")
+	data, err := ioutil.ReadAll(reader)
+	require.Nil(t, err, "no error expected reading block")
+	return data
+}
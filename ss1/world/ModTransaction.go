@@ -11,8 +11,14 @@ type modAction func(modder Modder)
 // ModTransaction is used to queue a list of modifications.
 // It allows modifications of related resources in one atomic action.
 type ModTransaction struct {
-	actions     []modAction
-	modifiedIDs resource.IDMarkerMap
+	actions      []modAction
+	modifiedIDs  resource.IDMarkerMap
+	modifiedKeys []ResourceKey
+}
+
+func (trans *ModTransaction) markModified(lang resource.Language, id resource.ID) {
+	trans.modifiedIDs.Add(id)
+	trans.modifiedKeys = append(trans.modifiedKeys, ResourceKeyOf(lang, id))
 }
 
 // SetResourceBlock changes the block data of a resource.
@@ -27,7 +33,7 @@ func (trans *ModTransaction) SetResourceBlock(lang resource.Language, id resourc
 	trans.actions = append(trans.actions, func(modder Modder) {
 		modder.SetResourceBlock(lang, id, index, data)
 	})
-	trans.modifiedIDs.Add(id)
+	trans.markModified(lang, id)
 }
 
 // PatchResourceBlock modifies an existing block.
@@ -37,7 +43,7 @@ func (trans *ModTransaction) PatchResourceBlock(lang resource.Language, id resou
 	trans.actions = append(trans.actions, func(modder Modder) {
 		modder.PatchResourceBlock(lang, id, index, expectedLength, patch)
 	})
-	trans.modifiedIDs.Add(id)
+	trans.markModified(lang, id)
 }
 
 // SetResourceBlocks sets the entire list of block data of a resource.
@@ -46,7 +52,16 @@ func (trans *ModTransaction) SetResourceBlocks(lang resource.Language, id resour
 	trans.actions = append(trans.actions, func(modder Modder) {
 		modder.SetResourceBlocks(lang, id, data)
 	})
-	trans.modifiedIDs.Add(id)
+	trans.markModified(lang, id)
+}
+
+// SetResourceProperties changes the meta information of a resource.
+// If the resource does not exist, it will be created without blocks.
+func (trans *ModTransaction) SetResourceProperties(lang resource.Language, id resource.ID, properties resource.Properties) {
+	trans.actions = append(trans.actions, func(modder Modder) {
+		modder.SetResourceProperties(lang, id, properties)
+	})
+	trans.markModified(lang, id)
 }
 
 // DelResource removes a resource from the mod in the given language.
@@ -56,7 +71,7 @@ func (trans *ModTransaction) DelResource(lang resource.Language, id resource.ID)
 	trans.actions = append(trans.actions, func(modder Modder) {
 		modder.DelResource(lang, id)
 	})
-	trans.modifiedIDs.Add(id)
+	trans.markModified(lang, id)
 }
 
 // SetTextureProperties updates the properties of a specific texture.
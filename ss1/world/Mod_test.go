@@ -146,6 +146,114 @@ func (suite *ModSuite) TestModifiedBlocksCanBeRetrievedBulk() {
 	assert.Equal(suite.T(), [][]byte{{0xBB}, {0xCC}}, suite.mod.ModifiedBlocks(resource.LangAny, 0x0800))
 }
 
+func (suite *ModSuite) TestResourceOriginsListWinningOriginFirst() {
+	suite.givenWorldHas(
+		suite.someLocalizedResources(resource.LangAny,
+			suite.storing(0x0800, [][]byte{{0xAA}})))
+	suite.givenWorldHas(
+		suite.someLocalizedResources(resource.LangGerman,
+			suite.storing(0x0800, [][]byte{{0xBB}})),
+		suite.someLocalizedResources(resource.LangFrench,
+			suite.storing(0x0800, [][]byte{{0xCC}})))
+	suite.givenModifiedBy(func(modder world.Modder) {
+		modder.SetResourceBlock(resource.LangGerman, 0x0800, 0, []byte{0xDD})
+	})
+
+	origins := suite.mod.ResourceOrigins(resource.LangGerman, 0x0800)
+
+	assert.Equal(suite.T(), []world.ResourceOrigin{
+		{Entry: world.ModOriginEntry, Filename: "unknown.res", Language: resource.LangGerman},
+		{Entry: "entry-1", Filename: "unnamed", Language: resource.LangGerman},
+		{Entry: "entry-0", Filename: "unnamed", Language: resource.LangAny},
+	}, origins)
+}
+
+func (suite *ModSuite) TestResourceOriginsOfLanguageAgnosticQueryOnlyContainLanguageAgnosticOrigins() {
+	suite.givenWorldHas(
+		suite.someLocalizedResources(resource.LangAny,
+			suite.storing(0x0800, [][]byte{{0xAA}})),
+		suite.someLocalizedResources(resource.LangDefault,
+			suite.storing(0x0800, [][]byte{{0xBB}})))
+
+	origins := suite.mod.ResourceOrigins(resource.LangAny, 0x0800)
+
+	assert.Equal(suite.T(), []world.ResourceOrigin{
+		{Entry: "entry-0", Filename: "unnamed", Language: resource.LangAny},
+	}, origins)
+}
+
+func (suite *ModSuite) TestResourceOriginsAreEmptyForUnknownResources() {
+	suite.givenWorldHas(
+		suite.someLocalizedResources(resource.LangAny,
+			suite.storing(0x0800, [][]byte{{0xAA}})))
+
+	origins := suite.mod.ResourceOrigins(resource.LangDefault, 0x0801)
+
+	assert.Empty(suite.T(), origins)
+}
+
+func (suite *ModSuite) TestEnumerateResourcesListsAgnosticResourcesOnce() {
+	suite.givenWorldHas(
+		suite.someLocalizedResources(resource.LangAny,
+			suite.storing(0x0800, [][]byte{{0xAA}})),
+		suite.someLocalizedResources(resource.LangGerman,
+			suite.storing(0x0800, [][]byte{{0xBB}}),
+			suite.storing(0x0801, [][]byte{{0xCC}})))
+
+	var provided []world.ProvidedResource
+	suite.mod.EnumerateResources(false, func(res world.ProvidedResource) bool {
+		provided = append(provided, res)
+		return true
+	})
+
+	assert.Equal(suite.T(), []world.ProvidedResource{
+		{ID: 0x0800, Language: resource.LangAny,
+			Origin: world.ResourceOrigin{Entry: "entry-0", Filename: "unnamed", Language: resource.LangAny}},
+		{ID: 0x0800, Language: resource.LangGerman,
+			Origin: world.ResourceOrigin{Entry: "entry-0", Filename: "unnamed", Language: resource.LangGerman}},
+		{ID: 0x0801, Language: resource.LangGerman,
+			Origin: world.ResourceOrigin{Entry: "entry-0", Filename: "unnamed", Language: resource.LangGerman}},
+	}, provided)
+}
+
+func (suite *ModSuite) TestEnumerateResourcesCanExpandAgnosticResources() {
+	suite.givenWorldHas(
+		suite.someLocalizedResources(resource.LangAny,
+			suite.storing(0x0800, [][]byte{{0xAA}})))
+	suite.givenModifiedBy(func(modder world.Modder) {
+		modder.SetResourceBlock(resource.LangFrench, 0x0800, 0, []byte{0xDD})
+	})
+
+	var provided []world.ProvidedResource
+	suite.mod.EnumerateResources(true, func(res world.ProvidedResource) bool {
+		provided = append(provided, res)
+		return true
+	})
+
+	worldOrigin := world.ResourceOrigin{Entry: "entry-0", Filename: "unnamed", Language: resource.LangAny}
+	assert.Equal(suite.T(), []world.ProvidedResource{
+		{ID: 0x0800, Language: resource.LangDefault, Origin: worldOrigin},
+		{ID: 0x0800, Language: resource.LangFrench,
+			Origin: world.ResourceOrigin{Entry: world.ModOriginEntry, Filename: "unknown.res", Language: resource.LangFrench}},
+		{ID: 0x0800, Language: resource.LangGerman, Origin: worldOrigin},
+	}, provided)
+}
+
+func (suite *ModSuite) TestEnumerateResourcesStopsWhenHandlerReturnsFalse() {
+	suite.givenWorldHas(
+		suite.someLocalizedResources(resource.LangAny,
+			suite.storing(0x0800, [][]byte{{0xAA}}),
+			suite.storing(0x0801, [][]byte{{0xBB}})))
+
+	calls := 0
+	suite.mod.EnumerateResources(false, func(res world.ProvidedResource) bool {
+		calls++
+		return false
+	})
+
+	assert.Equal(suite.T(), 1, calls)
+}
+
 func (suite *ModSuite) givenWorldHas(res ...resource.LocalizedResources) {
 	suite.whenWorldIsExtendedWith(res...)
 	suite.lastModifiedIDs = nil
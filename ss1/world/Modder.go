@@ -28,6 +28,10 @@ type Modder interface {
 	// This method is primarily meant for compound non-list resources (e.g. text pages).
 	SetResourceBlocks(lang resource.Language, id resource.ID, data [][]byte)
 
+	// SetResourceProperties changes the meta information of a resource.
+	// If the resource does not exist, it will be created without blocks.
+	SetResourceProperties(lang resource.Language, id resource.ID, properties resource.Properties)
+
 	// DelResource removes a resource from the mod in the given language.
 	//
 	// After the deletion, all the underlying data of the world will become visible again.
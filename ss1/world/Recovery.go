@@ -0,0 +1,280 @@
+package world
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/inkyblackness/hacked/ss1/content/object"
+	"github.com/inkyblackness/hacked/ss1/content/texture"
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/resource/lgres"
+	"github.com/inkyblackness/hacked/ss1/serial"
+)
+
+// RecoveryFormatVersion identifies the layout of recovery files written by this package.
+const RecoveryFormatVersion = 1
+
+const (
+	recoveryInfoName      = "recovery.json"
+	recoveryResourcesPath = "resources/"
+)
+
+type recoveryResourceInfo struct {
+	Filename string            `json:"filename"`
+	Language resource.Language `json:"language"`
+}
+
+type recoveryInfo struct {
+	Version      int                    `json:"version"`
+	ModPath      string                 `json:"modPath"`
+	ChangedFiles []string               `json:"changedFiles"`
+	Resources    []recoveryResourceInfo `json:"resources"`
+}
+
+// WriteRecovery serializes the complete state of the mod, including its unsaved changes, to the given writer.
+// The data is a ZIP archive that contains an information file and the mod files in their regular format.
+func WriteRecovery(writer io.Writer, mod *Mod) error {
+	archive := zip.NewWriter(writer)
+	info := recoveryInfo{
+		Version:      RecoveryFormatVersion,
+		ModPath:      mod.Path(),
+		ChangedFiles: mod.ModifiedFilenames(),
+	}
+	sort.Strings(info.ChangedFiles)
+	addEntry := func(name string, data []byte) error {
+		entryWriter, err := archive.Create(name)
+		if err != nil {
+			return err
+		}
+		_, err = entryWriter.Write(data)
+		return err
+	}
+
+	for index, loc := range mod.data.LocalizedResources {
+		store := serial.NewByteStore()
+		err := lgres.Write(store, loc.Store)
+		if err != nil {
+			return err
+		}
+		err = addEntry(recoveryResourceEntryName(index), store.Data())
+		if err != nil {
+			return err
+		}
+		info.Resources = append(info.Resources, recoveryResourceInfo{Filename: loc.Filename, Language: loc.Language})
+	}
+	if mod.HasModifyableObjectProperties() {
+		err := addRecoveryCodable(addEntry, ObjectPropertiesFilename, mod.data.ObjectProperties)
+		if err != nil {
+			return err
+		}
+	}
+	if mod.HasModifyableTextureProperties() {
+		err := addRecoveryCodable(addEntry, TexturePropertiesFilename, mod.data.TextureProperties)
+		if err != nil {
+			return err
+		}
+	}
+
+	infoData, err := json.Marshal(info)
+	if err != nil {
+		return err
+	}
+	err = addEntry(recoveryInfoName, infoData)
+	if err != nil {
+		return err
+	}
+	return archive.Close()
+}
+
+// SaveRecovery writes the state of the mod to the given recovery file.
+// An existing recovery file is only replaced if the new one could be written completely.
+// The directory of the file is created if it does not exist.
+func SaveRecovery(filename string, mod *Mod) error {
+	err := os.MkdirAll(filepath.Dir(filename), 0750)
+	if err != nil {
+		return err
+	}
+	return SaveFile(filename, func(writer io.WriteSeeker) error {
+		return WriteRecovery(writer, mod)
+	})
+}
+
+// RemoveRecovery deletes the given recovery file. It is not an error if the file does not exist.
+func RemoveRecovery(filename string) error {
+	err := os.Remove(filename)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// RecoveryExists returns true if there is a recovery file under given name.
+func RecoveryExists(filename string) bool {
+	info, err := os.Stat(filename)
+	return (err == nil) && !info.IsDir()
+}
+
+// LoadRecovery reads the given recovery file and resets the mod to the state it describes.
+// The files that had unsaved changes when the recovery file was written are reported as unsaved again.
+// If the file is not a valid recovery file, an error is returned and the mod remains unchanged.
+func LoadRecovery(filename string, mod *Mod) error {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	state, err := readRecovery(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return err
+	}
+	mod.SetPath(state.info.ModPath)
+	mod.Reset(state.resources, state.objectProperties, state.textureProperties)
+	for _, changedFile := range state.info.ChangedFiles {
+		mod.markFileChanged(changedFile)
+		for _, loc := range state.resources {
+			if loc.Filename != changedFile {
+				continue
+			}
+			for _, id := range loc.Store.IDs() {
+				mod.markUnknownSavedState(ResourceKeyOf(loc.Language, id))
+			}
+		}
+	}
+	// The restored changes shall only be saved on request, not by automatic saving.
+	mod.lastChangeTime = time.Time{}
+	return nil
+}
+
+type recoveryState struct {
+	info              recoveryInfo
+	resources         []*LocalizedResources
+	objectProperties  object.PropertiesTable
+	textureProperties texture.PropertiesList
+}
+
+func readRecovery(source io.ReaderAt, size int64) (recoveryState, error) {
+	var state recoveryState
+	archive, err := zip.NewReader(source, size)
+	if err != nil {
+		return state, fmt.Errorf("recovery file is not an archive: %v", err)
+	}
+	entries := make(map[string]*zip.File)
+	for _, file := range archive.File {
+		entries[file.Name] = file
+	}
+	readEntry := func(name string) ([]byte, error) {
+		file, existing := entries[name]
+		if !existing {
+			return nil, fmt.Errorf("recovery file is missing entry %q", name)
+		}
+		reader, err := file.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer reader.Close() // nolint: errcheck
+		return ioutil.ReadAll(reader)
+	}
+
+	infoData, err := readEntry(recoveryInfoName)
+	if err != nil {
+		return state, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(infoData))
+	decoder.DisallowUnknownFields()
+	err = decoder.Decode(&state.info)
+	if err != nil {
+		return state, fmt.Errorf("recovery information is invalid: %v", err)
+	}
+	if state.info.Version != RecoveryFormatVersion {
+		return state, fmt.Errorf("recovery file has unsupported version %d", state.info.Version)
+	}
+
+	for index, resInfo := range state.info.Resources {
+		loc, err := readRecoveryResources(readEntry, recoveryResourceEntryName(index), resInfo)
+		if err != nil {
+			return state, err
+		}
+		state.resources = append(state.resources, loc)
+	}
+	if _, existing := entries[ObjectPropertiesFilename]; existing {
+		data, err := readEntry(ObjectPropertiesFilename)
+		if err != nil {
+			return state, err
+		}
+		state.objectProperties = object.StandardPropertiesTable()
+		err = decodeRecoveryCodable(data, state.objectProperties)
+		if err != nil {
+			return state, err
+		}
+	}
+	if _, existing := entries[TexturePropertiesFilename]; existing {
+		data, err := readEntry(TexturePropertiesFilename)
+		if err != nil {
+			return state, err
+		}
+		if len(data) <= 4 {
+			return state, errors.New("recovered texture properties are too short")
+		}
+		state.textureProperties = make(texture.PropertiesList, (len(data)-4)/texture.PropertiesSize)
+		err = decodeRecoveryCodable(data, state.textureProperties)
+		if err != nil {
+			return state, err
+		}
+	}
+	return state, nil
+}
+
+func readRecoveryResources(readEntry func(string) ([]byte, error), name string,
+	info recoveryResourceInfo) (*LocalizedResources, error) {
+	data, err := readEntry(name)
+	if err != nil {
+		return nil, err
+	}
+	reader, err := lgres.ReaderFrom(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("recovered resources of %v are invalid: %v", info.Filename, err)
+	}
+	loc := &LocalizedResources{
+		Filename: info.Filename,
+		Language: info.Language,
+	}
+	for _, id := range reader.IDs() {
+		view, err := reader.View(id)
+		if err == nil {
+			err = loc.Store.Put(id, view)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("recovered resource %v of %v is invalid: %v", id, info.Filename, err)
+		}
+	}
+	return loc, nil
+}
+
+func decodeRecoveryCodable(data []byte, codable serial.Codable) error {
+	decoder := serial.NewDecoder(bytes.NewReader(data))
+	codable.Code(decoder)
+	return decoder.FirstError()
+}
+
+func recoveryResourceEntryName(index int) string {
+	return fmt.Sprintf("%s%03d.res", recoveryResourcesPath, index)
+}
+
+func addRecoveryCodable(addEntry func(string, []byte) error, name string, codable serial.Codable) error {
+	buffer := bytes.NewBuffer(nil)
+	encoder := serial.NewEncoder(buffer)
+	codable.Code(encoder)
+	err := encoder.FirstError()
+	if err != nil {
+		return err
+	}
+	return addEntry(name, buffer.Bytes())
+}
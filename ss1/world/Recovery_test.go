@@ -0,0 +1,161 @@
+package world_test
+
+import (
+	"archive/zip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/world"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveRecoveryWritesArchiveWithModFiles(t *testing.T) {
+	dir, cleanup := tempSaveDir(t)
+	defer cleanup()
+	filename := filepath.Join(dir, "recovery.zip")
+	mod := world.NewMod(func([]resource.ID, []resource.ID) {}, func() {})
+	mod.Modify(func(modder world.Modder) {
+		modder.SetResourceBlock(resource.LangAny, resource.ID(0x1000), 0, []byte{0x01})
+	})
+
+	err := world.SaveRecovery(filename, mod)
+	require.Nil(t, err, "no error expected")
+
+	archive, err := zip.OpenReader(filename)
+	require.Nil(t, err, "recovery should be an archive")
+	defer archive.Close() // nolint: errcheck
+	var names []string
+	for _, file := range archive.File {
+		names = append(names, file.Name)
+	}
+	assert.Contains(t, names, "recovery.json")
+	assert.Equal(t, 2, len(names), "one resource file expected")
+}
+
+func TestSaveRecoveryCreatesDirectory(t *testing.T) {
+	dir, cleanup := tempSaveDir(t)
+	defer cleanup()
+	filename := filepath.Join(dir, "user", "app", "recovery.zip")
+	mod := world.NewMod(func([]resource.ID, []resource.ID) {}, func() {})
+
+	err := world.SaveRecovery(filename, mod)
+	require.Nil(t, err, "no error expected")
+	assert.True(t, world.RecoveryExists(filename), "recovery file expected")
+}
+
+func TestRemoveRecoveryToleratesMissingFile(t *testing.T) {
+	dir, cleanup := tempSaveDir(t)
+	defer cleanup()
+	filename := filepath.Join(dir, "recovery.zip")
+	mod := world.NewMod(func([]resource.ID, []resource.ID) {}, func() {})
+	require.Nil(t, world.SaveRecovery(filename, mod))
+
+	assert.Nil(t, world.RemoveRecovery(filename), "no error expected removing")
+	_, err := os.Stat(filename)
+	assert.True(t, os.IsNotExist(err), "file should be removed")
+	assert.Nil(t, world.RemoveRecovery(filename), "no error expected removing again")
+}
+
+func TestModChangeCountIncreasesWithChanges(t *testing.T) {
+	mod := world.NewMod(func([]resource.ID, []resource.ID) {}, func() {})
+	before := mod.ChangeCount()
+	mod.Modify(func(modder world.Modder) {
+		modder.SetResourceBlock(resource.LangAny, resource.ID(0x1000), 0, []byte{0x01})
+	})
+	assert.True(t, mod.ChangeCount() > before, "count should increase")
+}
+
+func TestLoadRecoveryRestoresUnsavedState(t *testing.T) {
+	dir, cleanup := tempSaveDir(t)
+	defer cleanup()
+	filename := filepath.Join(dir, "recovery.zip")
+	original := world.NewMod(func([]resource.ID, []resource.ID) {}, func() {})
+	original.SetPath("some/path")
+	original.Modify(func(modder world.Modder) {
+		modder.SetResourceBlock(resource.LangAny, resource.ID(0x1000), 0, []byte{0x01, 0x02})
+	})
+	require.Nil(t, world.SaveRecovery(filename, original))
+
+	restored := world.NewMod(func([]resource.ID, []resource.ID) {}, func() {})
+	err := world.LoadRecovery(filename, restored)
+	require.Nil(t, err, "no error expected")
+
+	assert.Equal(t, "some/path", restored.Path())
+	assert.Equal(t, original.ModifiedFilenames(), restored.ModifiedFilenames())
+	assert.True(t, restored.LastChangeTime().IsZero(), "restored changes should not be saved automatically")
+	res := restored.ModifiedResource(resource.LangAny, resource.ID(0x1000))
+	require.NotNil(t, res, "resource should be restored")
+	reader, err := res.Block(0)
+	require.Nil(t, err, "no error expected")
+	data, _ := ioutil.ReadAll(reader)
+	assert.Equal(t, []byte{0x01, 0x02}, data)
+}
+
+func TestLoadRecoveryRejectsInvalidFiles(t *testing.T) {
+	dir, cleanup := tempSaveDir(t)
+	defer cleanup()
+	tt := []struct {
+		name    string
+		entries map[string]string
+	}{
+		{name: "noInfo", entries: map[string]string{"other.txt": "x"}},
+		{name: "badInfo", entries: map[string]string{"recovery.json": "{"}},
+		{name: "wrongVersion", entries: map[string]string{"recovery.json": `{"version":9999}`}},
+		{name: "missingResources", entries: map[string]string{
+			"recovery.json": `{"version":1,"resources":[{"filename":"a.res","language":0}]}`}},
+		{name: "corruptResources", entries: map[string]string{
+			"recovery.json":     `{"version":1,"resources":[{"filename":"a.res","language":0}]}`,
+			"resources/000.res": "not a resource file"}},
+	}
+
+	for _, tc := range tt {
+		td := tc
+		t.Run(td.name, func(t *testing.T) {
+			filename := filepath.Join(dir, td.name+".zip")
+			writeZip(t, filename, td.entries)
+			mod := world.NewMod(func([]resource.ID, []resource.ID) {}, func() {})
+			mod.SetPath("unchanged")
+
+			err := world.LoadRecovery(filename, mod)
+
+			assert.Error(t, err, "error expected")
+			assert.Equal(t, "unchanged", mod.Path())
+		})
+	}
+
+	t.Run("noArchive", func(t *testing.T) {
+		filename := filepath.Join(dir, "plain.zip")
+		require.Nil(t, ioutil.WriteFile(filename, []byte("plain"), 0600))
+		err := world.LoadRecovery(filename, world.NewMod(func([]resource.ID, []resource.ID) {}, func() {}))
+		assert.Error(t, err, "error expected")
+	})
+}
+
+func TestRecoveryExists(t *testing.T) {
+	dir, cleanup := tempSaveDir(t)
+	defer cleanup()
+	filename := filepath.Join(dir, "recovery.zip")
+	assert.False(t, world.RecoveryExists(filename), "should not exist before")
+	assert.False(t, world.RecoveryExists(dir), "directory should not be reported")
+	require.Nil(t, world.SaveRecovery(filename, world.NewMod(func([]resource.ID, []resource.ID) {}, func() {})))
+	assert.True(t, world.RecoveryExists(filename), "should exist after saving")
+}
+
+func writeZip(t *testing.T, filename string, entries map[string]string) {
+	file, err := os.Create(filename)
+	require.Nil(t, err)
+	defer file.Close() // nolint: errcheck
+	writer := zip.NewWriter(file)
+	for name, content := range entries {
+		entryWriter, err := writer.Create(name)
+		require.Nil(t, err)
+		_, err = entryWriter.Write([]byte(content))
+		require.Nil(t, err)
+	}
+	require.Nil(t, writer.Close())
+}
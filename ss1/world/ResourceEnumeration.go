@@ -0,0 +1,97 @@
+package world
+
+import (
+	"sort"
+
+	"github.com/inkyblackness/hacked/ss1/resource"
+)
+
+// ProvidedResource describes one resource in one language, together with the origin providing it.
+type ProvidedResource struct {
+	ID resource.ID
+	// Language is the language the resource is provided for.
+	// It is resource.LangAny for language agnostic resources, unless they are expanded.
+	Language resource.Language
+	// Origin is the winning origin, providing the resource for the language.
+	Origin ResourceOrigin
+}
+
+// ProvidedResourceHandler is called for each enumerated resource. It returns false to stop the enumeration.
+type ProvidedResourceHandler func(ProvidedResource) bool
+
+// EnumerateResources calls the handler for every pair of resource ID and language the manifest provides.
+// See Mod.EnumerateResources() for details.
+func (manifest Manifest) EnumerateResources(expandAgnostic bool, handler ProvidedResourceHandler) {
+	languages := make(resourceLanguages)
+	for _, entry := range manifest.entries {
+		for _, localized := range entry.Resources {
+			languages.add(localized.Language, localized.Viewer.IDs())
+		}
+	}
+	languages.enumerate(expandAgnostic, manifest.ResourceOrigins, handler)
+}
+
+// EnumerateResources calls the handler for every pair of resource ID and language the mod provides,
+// including those of the world. The pairs are enumerated in order of ID, then language.
+//
+// If expandAgnostic is false, language agnostic resources are enumerated once with resource.LangAny,
+// with the winning origin among the language agnostic ones. Resources of specific languages are enumerated
+// for their language, with the winning origin for that language - which may be a language agnostic one.
+// If expandAgnostic is true, all resources are enumerated for each specific language they are available in.
+//
+// Block content is not accessed. The enumeration stops early if the handler returns false.
+func (mod Mod) EnumerateResources(expandAgnostic bool, handler ProvidedResourceHandler) {
+	languages := make(resourceLanguages)
+	for _, entry := range mod.worldManifest.entries {
+		for _, localized := range entry.Resources {
+			languages.add(localized.Language, localized.Viewer.IDs())
+		}
+	}
+	for _, entry := range mod.data.LocalizedResources {
+		languages.add(entry.Language, entry.Store.IDs())
+	}
+	languages.enumerate(expandAgnostic, mod.ResourceOrigins, handler)
+}
+
+type resourceLanguages map[resource.ID]map[resource.Language]struct{}
+
+func (languages resourceLanguages) add(lang resource.Language, ids []resource.ID) {
+	for _, id := range ids {
+		langs, existing := languages[id]
+		if !existing {
+			langs = make(map[resource.Language]struct{})
+			languages[id] = langs
+		}
+		langs[lang] = struct{}{}
+	}
+}
+
+func (languages resourceLanguages) enumerate(expandAgnostic bool,
+	origins func(resource.Language, resource.ID) []ResourceOrigin, handler ProvidedResourceHandler) {
+	ids := make([]resource.ID, 0, len(languages))
+	for id := range languages {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(a, b int) bool { return ids[a] < ids[b] })
+	candidates := append([]resource.Language{resource.LangAny}, resource.Languages()...)
+	for _, id := range ids {
+		langs := languages[id]
+		_, hasAgnostic := langs[resource.LangAny]
+		for _, lang := range candidates {
+			_, hasLang := langs[lang]
+			if expandAgnostic {
+				hasLang = (lang != resource.LangAny) && (hasLang || hasAgnostic)
+			}
+			if !hasLang {
+				continue
+			}
+			found := origins(lang, id)
+			if len(found) == 0 {
+				continue
+			}
+			if !handler(ProvidedResource{ID: id, Language: lang, Origin: found[0]}) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package world
+
+import (
+	"sort"
+
+	"github.com/inkyblackness/hacked/ss1/resource"
+)
+
+// ResourceKey identifies a resource of the mod in a specific language.
+type ResourceKey struct {
+	Lang resource.Language
+	ID   resource.ID
+}
+
+// ResourceKeyOf returns a key for given language and ID.
+func ResourceKeyOf(lang resource.Language, id resource.ID) ResourceKey {
+	return ResourceKey{Lang: lang, ID: id}
+}
+
+func sortResourceKeys(keys []ResourceKey) {
+	sort.Slice(keys, func(a, b int) bool {
+		if keys[a].Lang != keys[b].Lang {
+			return keys[a].Lang < keys[b].Lang
+		}
+		return keys[a].ID < keys[b].ID
+	})
+}
@@ -0,0 +1,72 @@
+package world
+
+import "github.com/inkyblackness/hacked/ss1/resource"
+
+// ModOriginEntry is the entry identifier of resource origins that are part of the mod.
+const ModOriginEntry = "(mod)"
+
+// ResourceOrigin describes where a resource is provided from.
+type ResourceOrigin struct {
+	// Entry is the identifier of the manifest entry, or ModOriginEntry for resources of the mod.
+	Entry string
+	// Filename identifies the set of localized resources within the entry.
+	Filename string
+	// Language is the language of the set of localized resources.
+	// Resources of LangAny are visible to all languages.
+	Language resource.Language
+}
+
+// ResourceOrigins returns the origins of all resources that match the given parameters.
+// The list is ordered by precedence: The first origin is the one that provides the resource, all further
+// origins are shadowed by their predecessors.
+// For compound list resources, such as text lookups, the blocks of all origins are merged,
+// with the first origin providing a block taking precedence.
+func (manifest Manifest) ResourceOrigins(lang resource.Language, id resource.ID) []ResourceOrigin {
+	var origins []ResourceOrigin
+	for _, entry := range manifest.entries {
+		for _, localized := range entry.Resources {
+			if !localized.Language.Includes(lang) {
+				continue
+			}
+			if _, err := localized.Viewer.View(id); err == nil {
+				origins = append(origins, ResourceOrigin{
+					Entry:    entry.ID,
+					Filename: localized.ID,
+					Language: localized.Language,
+				})
+			}
+		}
+	}
+	return reversedOrigins(origins)
+}
+
+// ResourceOrigins returns the origins of all resources that match the given parameters, including
+// those of the world. The list is ordered by precedence, with the first origin providing the resource.
+// See Manifest.ResourceOrigins() for details.
+func (mod Mod) ResourceOrigins(lang resource.Language, id resource.ID) []ResourceOrigin {
+	modOrigins := mod.modifiedOrigins(resource.LangAny, id)
+	if lang != resource.LangAny {
+		modOrigins = append(modOrigins, mod.modifiedOrigins(lang, id)...)
+	}
+	return append(reversedOrigins(modOrigins), mod.worldManifest.ResourceOrigins(lang, id)...)
+}
+
+func (mod Mod) modifiedOrigins(lang resource.Language, id resource.ID) []ResourceOrigin {
+	for _, entry := range mod.data.LocalizedResources {
+		if entry.Language != lang {
+			continue
+		}
+		if _, err := entry.Store.Resource(id); err == nil {
+			return []ResourceOrigin{{Entry: ModOriginEntry, Filename: entry.Filename, Language: entry.Language}}
+		}
+	}
+	return nil
+}
+
+func reversedOrigins(origins []ResourceOrigin) []ResourceOrigin {
+	result := make([]ResourceOrigin, len(origins))
+	for index, origin := range origins {
+		result[len(origins)-1-index] = origin
+	}
+	return result
+}
@@ -0,0 +1,48 @@
+package world
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// SaveFile writes a file via the given function, without leaving a partially written file behind.
+// The content is first written to a temporary file in the same directory, which then replaces the
+// target file only if writing was successful. Should any step fail, an existing target file remains untouched.
+func SaveFile(filename string, write func(io.WriteSeeker) error) (err error) {
+	dir, base := filepath.Split(filename)
+	if len(dir) == 0 {
+		dir = "."
+	}
+	file, err := ioutil.TempFile(dir, "."+base+".tmp")
+	if err != nil {
+		return err
+	}
+	tempName := file.Name()
+	defer func() {
+		if err != nil {
+			_ = file.Close()
+			_ = os.Remove(tempName)
+		}
+	}()
+
+	err = write(file)
+	if err != nil {
+		return err
+	}
+	err = file.Sync()
+	if err != nil {
+		return err
+	}
+	err = file.Close()
+	if err != nil {
+		return err
+	}
+	if info, statErr := os.Stat(filename); statErr == nil {
+		_ = os.Chmod(tempName, info.Mode())
+	} else {
+		_ = os.Chmod(tempName, 0644)
+	}
+	return os.Rename(tempName, filename)
+}
@@ -0,0 +1,80 @@
+package world_test
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/inkyblackness/hacked/ss1/world"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func tempSaveDir(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "hacked-save")
+	require.Nil(t, err, "no error expected creating temp dir")
+	return dir, func() { _ = os.RemoveAll(dir) }
+}
+
+func TestSaveFileReplacesExistingFile(t *testing.T) {
+	dir, cleanup := tempSaveDir(t)
+	defer cleanup()
+	filename := filepath.Join(dir, "test.res")
+	require.Nil(t, ioutil.WriteFile(filename, []byte("original"), 0600))
+
+	err := world.SaveFile(filename, func(writer io.WriteSeeker) error {
+		_, writeErr := writer.Write([]byte("new content"))
+		return writeErr
+	})
+
+	require.Nil(t, err, "no error expected")
+	data, _ := ioutil.ReadFile(filename)
+	assert.Equal(t, "new content", string(data))
+	assertOnlyFileInDir(t, dir, "test.res")
+}
+
+func TestSaveFileKeepsOriginalOnWriteError(t *testing.T) {
+	dir, cleanup := tempSaveDir(t)
+	defer cleanup()
+	filename := filepath.Join(dir, "test.res")
+	require.Nil(t, ioutil.WriteFile(filename, []byte("original"), 0600))
+
+	err := world.SaveFile(filename, func(writer io.WriteSeeker) error {
+		_, _ = writer.Write([]byte("partial"))
+		return errors.New("disk full")
+	})
+
+	assert.Error(t, err, "error expected")
+	data, _ := ioutil.ReadFile(filename)
+	assert.Equal(t, "original", string(data), "original should be preserved")
+	assertOnlyFileInDir(t, dir, "test.res")
+}
+
+func TestSaveFileCreatesNewFile(t *testing.T) {
+	dir, cleanup := tempSaveDir(t)
+	defer cleanup()
+	filename := filepath.Join(dir, "new.res")
+
+	err := world.SaveFile(filename, func(writer io.WriteSeeker) error {
+		_, writeErr := writer.Write([]byte("data"))
+		return writeErr
+	})
+
+	require.Nil(t, err, "no error expected")
+	data, _ := ioutil.ReadFile(filename)
+	assert.Equal(t, "data", string(data))
+}
+
+func assertOnlyFileInDir(t *testing.T, dir string, expected string) {
+	infos, err := ioutil.ReadDir(dir)
+	require.Nil(t, err, "no error expected reading dir")
+	var names []string
+	for _, info := range infos {
+		names = append(names, info.Name())
+	}
+	assert.Equal(t, []string{expected}, names)
+}
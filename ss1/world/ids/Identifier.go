@@ -2,6 +2,32 @@ package ids
 
 import "github.com/inkyblackness/hacked/ss1/resource"
 
+// Limits
+const (
+	// MaxTextures is the amount of textures the engine supports.
+	MaxTextures = 293
+	// MaxObjectTextureBitmaps is the amount of bitmaps objects can use as texture.
+	MaxObjectTextureBitmaps = 64
+	// MaxObjectMaterialBitmaps is the amount of bitmaps objects can use as material.
+	MaxObjectMaterialBitmaps = 32
+	// MaxGamePalettes is the amount of palettes the game uses.
+	MaxGamePalettes = 3
+	// MaxVideoMails is the amount of video mails, each having a bitmap and an animation.
+	MaxVideoMails = 12
+	// MaxPaperTexts is the amount of papers.
+	MaxPaperTexts = 16
+	// MaxMails is the amount of mails, with a text and audio each.
+	MaxMails = 47
+	// MaxLogs is the amount of text logs.
+	MaxLogs = 136
+	// MaxLogAudios is the amount of audio logs.
+	MaxLogAudios = 224
+	// MaxFragments is the amount of fragments.
+	MaxFragments = 16
+	// MaxTrapMessages is the amount of trap messages, with a text and audio each.
+	MaxTrapMessages = 256
+)
+
 // Palettes
 const (
 	GamePalettesStart resource.ID = 0x02BC
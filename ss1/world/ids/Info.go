@@ -105,31 +105,31 @@ var infoByID = map[resource.ID]ResourceInfo{}
 
 // nolint: govet
 var infoList = []ResourceInfo{
-	{GamePalettesStart, GamePalettesStart.Plus(3), resource.Palette, false, false, false, 3, GamePal},
+	{GamePalettesStart, GamePalettesStart.Plus(MaxGamePalettes), resource.Palette, false, false, false, MaxGamePalettes, GamePal},
 
-	{IconTextures, IconTextures.Plus(1), resource.Bitmap, true, false, true, 293, Texture},
-	{SmallTextures, SmallTextures.Plus(1), resource.Bitmap, true, false, true, 293, Texture},
-	{MediumTextures, MediumTextures.Plus(293), resource.Bitmap, true, false, false, 293, Texture},
-	{LargeTextures, LargeTextures.Plus(293), resource.Bitmap, true, false, false, 293, Texture},
-	{TextureNames, TextureNames.Plus(1), resource.Text, true, false, true, 293, CybStrng},
-	{TextureUsages, TextureUsages.Plus(1), resource.Text, true, false, true, 293, CybStrng},
+	{IconTextures, IconTextures.Plus(1), resource.Bitmap, true, false, true, MaxTextures, Texture},
+	{SmallTextures, SmallTextures.Plus(1), resource.Bitmap, true, false, true, MaxTextures, Texture},
+	{MediumTextures, MediumTextures.Plus(MaxTextures), resource.Bitmap, true, false, false, MaxTextures, Texture},
+	{LargeTextures, LargeTextures.Plus(MaxTextures), resource.Bitmap, true, false, false, MaxTextures, Texture},
+	{TextureNames, TextureNames.Plus(1), resource.Text, true, false, true, MaxTextures, CybStrng},
+	{TextureUsages, TextureUsages.Plus(1), resource.Text, true, false, true, MaxTextures, CybStrng},
 
 	{ObjectBitmaps, ObjectBitmaps.Plus(1), resource.Bitmap, true, false, true, 0, ObjArt},
-	{ObjectTextureBitmaps, ObjectTextureBitmaps.Plus(64), resource.Bitmap, true, false, false, 64, CitMat},
-	{ObjectMaterialBitmaps, ObjectMaterialBitmaps.Plus(32), resource.Bitmap, true, false, false, 32, CitMat},
+	{ObjectTextureBitmaps, ObjectTextureBitmaps.Plus(MaxObjectTextureBitmaps), resource.Bitmap, true, false, false, MaxObjectTextureBitmaps, CitMat},
+	{ObjectMaterialBitmaps, ObjectMaterialBitmaps.Plus(MaxObjectMaterialBitmaps), resource.Bitmap, true, false, false, MaxObjectMaterialBitmaps, CitMat},
 
 	{IconBitmaps, IconBitmaps.Plus(1), resource.Bitmap, true, false, true, 64, ObjArt3},
 	{GraffitiBitmaps, GraffitiBitmaps.Plus(1), resource.Bitmap, true, false, true, 64, ObjArt3},
 
 	{MfdDataBitmaps, MfdDataBitmaps.Plus(1), resource.Bitmap, true, true, true, 256, MfdArt},
 
-	{VideoMailBitmapsStart, VideoMailBitmapsStart.Plus(12), resource.Bitmap, true, false, false, 12, VidMail},
-	{VideoMailAnimationsStart, VideoMailAnimationsStart.Plus(12), resource.Animation, true, false, false, 12, VidMail},
+	{VideoMailBitmapsStart, VideoMailBitmapsStart.Plus(MaxVideoMails), resource.Bitmap, true, false, false, MaxVideoMails, VidMail},
+	{VideoMailAnimationsStart, VideoMailAnimationsStart.Plus(MaxVideoMails), resource.Animation, true, false, false, MaxVideoMails, VidMail},
 
-	{PaperTextsStart, PaperTextsStart.Plus(16), resource.Text, true, false, false, 16, CybStrng},
+	{PaperTextsStart, PaperTextsStart.Plus(MaxPaperTexts), resource.Text, true, false, false, MaxPaperTexts, CybStrng},
 
-	{TrapMessageTexts, TrapMessageTexts.Plus(1), resource.Text, true, false, true, 256, CybStrng},
-	{TrapMessagesAudioStart, TrapMessagesAudioStart.Plus(256), resource.Movie, false, false, false, 256, CitBark},
+	{TrapMessageTexts, TrapMessageTexts.Plus(1), resource.Text, true, false, true, MaxTrapMessages, CybStrng},
+	{TrapMessagesAudioStart, TrapMessagesAudioStart.Plus(MaxTrapMessages), resource.Movie, false, false, false, MaxTrapMessages, CitBark},
 
 	{WordTexts, WordTexts.Plus(1), resource.Text, true, false, true, 512, CybStrng},
 	{PanelNameTexts, PanelNameTexts.Plus(1), resource.Text, true, false, true, 256, CybStrng},
@@ -140,13 +140,14 @@ var infoList = []ResourceInfo{
 	{AccessCardNameTexts, AccessCardNameTexts.Plus(1), resource.Text, true, false, true, 32 * 2, CybStrng},
 	{DataletMessageTexts, DataletMessageTexts.Plus(1), resource.Text, true, false, true, 256, CybStrng},
 
-	{MailsStart, MailsStart.Plus(47), resource.Text, true, false, false, 47, CybStrng},
-	{LogsStart, LogsStart.Plus(136), resource.Text, true, false, false, 136, CybStrng},
-	{FragmentsStart, FragmentsStart.Plus(16), resource.Text, true, false, false, 16, CybStrng},
-	{MailsAudioStart, MailsAudioStart.Plus(47), resource.Movie, false, false, false, 47, CitALog},
-	{LogsAudioStart, LogsAudioStart.Plus(224), resource.Movie, false, false, false, 224, CitALog},
+	{MailsStart, MailsStart.Plus(MaxMails), resource.Text, true, false, false, MaxMails, CybStrng},
+	{LogsStart, LogsStart.Plus(MaxLogs), resource.Text, true, false, false, MaxLogs, CybStrng},
+	{FragmentsStart, FragmentsStart.Plus(MaxFragments), resource.Text, true, false, false, MaxFragments, CybStrng},
+	{MailsAudioStart, MailsAudioStart.Plus(MaxMails), resource.Movie, false, false, false, MaxMails, CitALog},
+	{LogsAudioStart, LogsAudioStart.Plus(MaxLogAudios), resource.Movie, false, false, false, MaxLogAudios, CitALog},
 
 	{ObjectLongNames, ObjectLongNames.Plus(1), resource.Text, true, false, true, 0, CybStrng},
+	{ObjectShortNames, ObjectShortNames.Plus(1), resource.Text, true, false, true, 0, CybStrng},
 
 	{ArchiveName, ArchiveName.Plus(1), resource.Archive, false, false, false, 1, Archive},
 	{GameState, GameState.Plus(1), resource.Archive, false, true, false, 1, Archive},
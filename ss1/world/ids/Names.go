@@ -0,0 +1,178 @@
+package ids
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/inkyblackness/hacked/ss1/content/archive"
+	"github.com/inkyblackness/hacked/ss1/content/archive/level/lvlids"
+	"github.com/inkyblackness/hacked/ss1/content/object"
+	"github.com/inkyblackness/hacked/ss1/resource"
+)
+
+// Resource categories
+const (
+	CategoryPalettes   = "Palettes"
+	CategoryTextures   = "Textures"
+	CategoryBitmaps    = "Bitmaps"
+	CategoryAnimations = "Animations"
+	CategoryTexts      = "Texts"
+	CategoryMessages   = "Messages"
+	CategorySounds     = "Sounds"
+	CategoryArchive    = "Archive"
+	CategoryLevels     = "Levels"
+	CategoryUnknown    = "Unknown"
+)
+
+// ResourceName describes a resource in human-readable form.
+type ResourceName struct {
+	// Name is the unique name of the resource, such as "Object Long Names" or "Large Texture 12".
+	Name string
+	// Category is the group the resource belongs to, such as "Texts".
+	Category string
+	// Known is false if the resource is not known and the name is a formatted fallback.
+	Known bool
+}
+
+// NameOf returns the name of the identified resource.
+// Unknown resources are described with a name in the form "Resource 0x1234".
+func NameOf(id resource.ID) ResourceName {
+	name, known := nameByID[id]
+	if !known {
+		return ResourceName{Name: fallbackName(id), Category: CategoryUnknown}
+	}
+	return name
+}
+
+// IDNamed returns the identifier of the resource with given name. The name is compared case-insensitive.
+// It also resolves the fallback names that NameOf returns for unknown resources.
+func IDNamed(name string) (resource.ID, bool) {
+	id, known := idByName[strings.ToLower(name)]
+	if known {
+		return id, true
+	}
+	var value uint16
+	if _, err := fmt.Sscanf(name, fallbackNameFormat, &value); err == nil && (fallbackName(resource.ID(value)) == name) {
+		return resource.ID(value), true
+	}
+	return 0, false
+}
+
+const fallbackNameFormat = "Resource 0x%04X"
+
+func fallbackName(id resource.ID) string {
+	return fmt.Sprintf(fallbackNameFormat, id.Value())
+}
+
+type resourceNameGroup struct {
+	startID  resource.ID
+	count    int
+	name     string
+	category string
+}
+
+var nameByID = map[resource.ID]ResourceName{}
+var idByName = map[string]resource.ID{}
+
+func init() {
+	register := func(id resource.ID, name, category string) {
+		nameByID[id] = ResourceName{Name: name, Category: category, Known: true}
+		idByName[strings.ToLower(name)] = id
+	}
+	for _, group := range nameGroups {
+		if group.count == 1 {
+			register(group.startID, group.name, group.category)
+			continue
+		}
+		for index := 0; index < group.count; index++ {
+			register(group.startID.Plus(index), fmt.Sprintf("%s %d", group.name, index), group.category)
+		}
+	}
+	for lvl := 0; lvl < archive.MaxLevels; lvl++ {
+		levelStart := LevelResourcesStart.Plus(lvl * lvlids.PerLevel)
+		for offset, name := range levelResourceNames {
+			register(levelStart.Plus(offset), fmt.Sprintf("Level %d %s", lvl, name), CategoryLevels)
+		}
+		for class := 0; class < object.ClassCount; class++ {
+			register(levelStart.Plus(lvlids.ObjectClassTablesStart+class),
+				fmt.Sprintf("Level %d Object Class Table %d", lvl, class), CategoryLevels)
+			register(levelStart.Plus(lvlids.ObjectDefaultTablesStart+class),
+				fmt.Sprintf("Level %d Object Default Table %d", lvl, class), CategoryLevels)
+		}
+	}
+}
+
+var nameGroups = []resourceNameGroup{
+	{GamePalettesStart, MaxGamePalettes, "Game Palette", CategoryPalettes},
+
+	{IconTextures, 1, "Icon Textures", CategoryTextures},
+	{SmallTextures, 1, "Small Textures", CategoryTextures},
+	{MediumTextures, MaxTextures, "Medium Texture", CategoryTextures},
+	{LargeTextures, MaxTextures, "Large Texture", CategoryTextures},
+	{TextureNames, 1, "Texture Names", CategoryTexts},
+	{TextureUsages, 1, "Texture Usages", CategoryTexts},
+
+	{ObjectBitmaps, 1, "Object Bitmaps", CategoryBitmaps},
+	{ObjectTextureBitmaps, MaxObjectTextureBitmaps, "Object Texture Bitmap", CategoryBitmaps},
+	{ObjectMaterialBitmaps, MaxObjectMaterialBitmaps, "Object Material Bitmap", CategoryBitmaps},
+	{IconBitmaps, 1, "Icon Bitmaps", CategoryBitmaps},
+	{GraffitiBitmaps, 1, "Graffiti Bitmaps", CategoryBitmaps},
+	{MfdDataBitmaps, 1, "MFD Data Bitmaps", CategoryBitmaps},
+
+	{VideoMailBitmapsStart, MaxVideoMails, "Video Mail Bitmap", CategoryAnimations},
+	{VideoMailAnimationsStart, MaxVideoMails, "Video Mail Animation", CategoryAnimations},
+
+	{PaperTextsStart, MaxPaperTexts, "Paper Text", CategoryTexts},
+	{TrapMessageTexts, 1, "Trap Message Texts", CategoryTexts},
+	{WordTexts, 1, "Word Texts", CategoryTexts},
+	{PanelNameTexts, 1, "Panel Name Texts", CategoryTexts},
+	{LogCategoryTexts, 1, "Log Category Texts", CategoryTexts},
+	{VariousMessageTexts, 1, "Various Message Texts", CategoryTexts},
+	{ScreenMessageTexts, 1, "Screen Message Texts", CategoryTexts},
+	{InfoNodeMessageTexts, 1, "Info Node Message Texts", CategoryTexts},
+	{AccessCardNameTexts, 1, "Access Card Name Texts", CategoryTexts},
+	{DataletMessageTexts, 1, "Datalet Message Texts", CategoryTexts},
+	{ObjectLongNames, 1, "Object Long Names", CategoryTexts},
+	{ObjectShortNames, 1, "Object Short Names", CategoryTexts},
+
+	{MailsStart, MaxMails, "Mail", CategoryMessages},
+	{LogsStart, MaxLogs, "Log", CategoryMessages},
+	{FragmentsStart, MaxFragments, "Fragment", CategoryMessages},
+	{MailsAudioStart, MaxMails, "Mail Audio", CategorySounds},
+	{LogsAudioStart, MaxLogAudios, "Log Audio", CategorySounds},
+	{TrapMessagesAudioStart, MaxTrapMessages, "Trap Message Audio", CategorySounds},
+
+	{ArchiveName, 1, "Archive Name", CategoryArchive},
+	{GameState, 1, "Game State", CategoryArchive},
+}
+
+var levelResourceNames = map[int]string{
+	lvlids.MapVersionNumber:    "Map Version Number",
+	lvlids.ObjectVersionNumber: "Object Version Number",
+	lvlids.Information:         "Information",
+
+	lvlids.TileMap:             "Tile Map",
+	lvlids.Schedules:           "Schedules",
+	lvlids.TextureAtlas:        "Texture Atlas",
+	lvlids.ObjectMasterTable:   "Object Master Table",
+	lvlids.ObjectCrossRefTable: "Object Cross-Reference Table",
+
+	lvlids.SavefileVersion: "Savefile Version",
+	lvlids.Unused41:        "Unused 41",
+
+	lvlids.TextureAnimations:      "Texture Animations",
+	lvlids.SurveillanceSources:    "Surveillance Sources",
+	lvlids.SurveillanceSurrogates: "Surveillance Surrogates",
+	lvlids.Parameters:             "Parameters",
+	lvlids.MapNotes:               "Map Notes",
+	lvlids.MapNotesPointer:        "Map Notes Pointer",
+
+	lvlids.Unknown48: "Unknown 48",
+	lvlids.Unknown49: "Unknown 49",
+	lvlids.Unknown50: "Unknown 50",
+
+	lvlids.LoopConfiguration: "Loop Configuration",
+
+	lvlids.Unknown52:        "Unknown 52",
+	lvlids.HeightSemaphores: "Height Semaphores",
+}
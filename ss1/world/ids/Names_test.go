@@ -0,0 +1,74 @@
+package ids_test
+
+import (
+	"testing"
+
+	"github.com/inkyblackness/hacked/ss1/content/archive/level/lvlids"
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/world/ids"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNameOfKnownResources(t *testing.T) {
+	tt := []struct {
+		id       resource.ID
+		name     string
+		category string
+	}{
+		{ids.ObjectLongNames, "Object Long Names", ids.CategoryTexts},
+		{ids.LargeTextures.Plus(12), "Large Texture 12", ids.CategoryTextures},
+		{ids.GamePalettesStart, "Game Palette 0", ids.CategoryPalettes},
+		{ids.LevelResourcesStart.Plus(lvlids.PerLevel + lvlids.TileMap), "Level 1 Tile Map", ids.CategoryLevels},
+		{ids.LevelResourcesStart.Plus(lvlids.ObjectClassTablesStart + 3), "Level 0 Object Class Table 3", ids.CategoryLevels},
+	}
+
+	for _, tc := range tt {
+		td := tc
+		t.Run(td.name, func(t *testing.T) {
+			name := ids.NameOf(td.id)
+			assert.True(t, name.Known, "resource should be known")
+			assert.Equal(t, td.name, name.Name)
+			assert.Equal(t, td.category, name.Category)
+		})
+	}
+}
+
+func TestNameOfCoversEachResourceOfInfo(t *testing.T) {
+	for _, start := range []resource.ID{ids.GamePalettesStart, ids.ObjectMaterialBitmaps, ids.VideoMailBitmapsStart,
+		ids.VideoMailAnimationsStart, ids.PaperTextsStart, ids.MailsStart, ids.LogsStart, ids.FragmentsStart,
+		ids.MailsAudioStart, ids.LogsAudioStart, ids.TrapMessagesAudioStart} {
+		info, known := ids.Info(start)
+		assert.True(t, known, "info for %v should be known", start)
+		for id := info.StartID; id < info.EndID; id++ {
+			assert.True(t, ids.NameOf(id).Known, "resource %v should be named", id)
+		}
+	}
+}
+
+func TestNameOfUnknownResourceIsFormattedFallback(t *testing.T) {
+	name := ids.NameOf(resource.ID(0x0001))
+	assert.False(t, name.Known, "resource should not be known")
+	assert.Equal(t, "Resource 0x0001", name.Name)
+	assert.Equal(t, ids.CategoryUnknown, name.Category)
+}
+
+func TestIDNamedIsReverseOfNameOf(t *testing.T) {
+	for _, id := range []resource.ID{ids.ObjectShortNames, ids.MailsStart.Plus(5), resource.ID(0x0001),
+		ids.LevelResourcesStart.Plus(lvlids.PerLevel*15 + lvlids.HeightSemaphores)} {
+		resolved, found := ids.IDNamed(ids.NameOf(id).Name)
+		assert.True(t, found, "name of %v should be resolved", id)
+		assert.Equal(t, id, resolved)
+	}
+}
+
+func TestIDNamedIgnoresCase(t *testing.T) {
+	id, found := ids.IDNamed("object long names")
+	assert.True(t, found, "name should be resolved")
+	assert.Equal(t, ids.ObjectLongNames, id)
+}
+
+func TestIDNamedReturnsFalseForUnknownNames(t *testing.T) {
+	_, found := ids.IDNamed("Not A Resource")
+	assert.False(t, found)
+}
@@ -0,0 +1,60 @@
+package integrity
+
+import (
+	"fmt"
+
+	"github.com/inkyblackness/hacked/ss1/resource"
+)
+
+// Severity describes how serious a finding is.
+type Severity int
+
+// Severity constants, in increasing order.
+const (
+	SeverityInfo Severity = iota
+	SeverityWarning
+	SeverityError
+)
+
+// String returns the textual representation of the value.
+func (severity Severity) String() string {
+	switch severity {
+	case SeverityInfo:
+		return "Info"
+	case SeverityWarning:
+		return "Warning"
+	case SeverityError:
+		return "Error"
+	default:
+		return fmt.Sprintf("Unknown%d", int(severity))
+	}
+}
+
+// Category groups findings of the same nature.
+type Category string
+
+// Category constants.
+const (
+	CategoryResourceStructure Category = "Resource Structure"
+	CategoryObjectTables      Category = "Object Tables"
+	CategoryObjectBitmaps     Category = "Object Bitmaps"
+	CategoryLevels            Category = "Levels"
+	CategoryUnusedResources   Category = "Unused Resources"
+	CategoryLanguages         Category = "Languages"
+	CategoryDuplicates        Category = "Duplicate Resources"
+)
+
+// Finding describes one problem found by a validator.
+type Finding struct {
+	Severity Severity
+	Category Category
+	// Resource refers to the concerned resource. The index refers to the block, if applicable.
+	Resource resource.Key
+	Message  string
+}
+
+// String returns a one-line description of the finding.
+func (finding Finding) String() string {
+	key := finding.Resource
+	return fmt.Sprintf("%v [%v] %v/%v/%d: %v", finding.Severity, finding.Category, key.ID, key.Lang, key.Index, finding.Message)
+}
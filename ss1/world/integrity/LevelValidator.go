@@ -0,0 +1,74 @@
+package integrity
+
+import (
+	"fmt"
+
+	"github.com/inkyblackness/hacked/ss1/content/archive"
+	"github.com/inkyblackness/hacked/ss1/content/archive/level"
+	"github.com/inkyblackness/hacked/ss1/content/archive/level/lvlids"
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/world"
+	"github.com/inkyblackness/hacked/ss1/world/ids"
+)
+
+var requiredLevelResources = []int{
+	lvlids.Information,
+	lvlids.TileMap,
+	lvlids.TextureAtlas,
+	lvlids.ObjectMasterTable,
+	lvlids.ObjectCrossRefTable,
+}
+
+// ValidateLevels checks that all levels can be entered, which requires their essential resources.
+func ValidateLevels(mod *world.Mod, report func(Finding)) {
+	selector := mod.LocalizedResources(resource.LangAny)
+	for levelID := 0; levelID < archive.MaxLevels; levelID++ {
+		levelBase := ids.LevelResourcesStart.Plus(lvlids.PerLevel * levelID)
+		var missing []resource.ID
+		for _, offset := range requiredLevelResources {
+			id := levelBase.Plus(offset)
+			if _, err := selector.Select(id); err != nil {
+				missing = append(missing, id)
+			}
+		}
+		switch {
+		case len(missing) == len(requiredLevelResources):
+			report(Finding{
+				Severity: SeverityWarning,
+				Category: CategoryLevels,
+				Resource: resource.KeyOf(levelBase, resource.LangAny, 0),
+				Message:  fmt.Sprintf("level %d has no data and can not be entered", levelID),
+			})
+		case len(missing) > 0:
+			for _, id := range missing {
+				report(Finding{
+					Severity: SeverityError,
+					Category: CategoryLevels,
+					Resource: resource.KeyOf(id, resource.LangAny, 0),
+					Message:  fmt.Sprintf("level %d lacks a required resource and can not be entered", levelID),
+				})
+			}
+		}
+	}
+}
+
+// ValidateLevelTextures checks that the tiles of all levels refer to available textures.
+// Each dangling reference is reported with its tile coordinates.
+func ValidateLevelTextures(mod *world.Mod, report func(Finding)) {
+	textureCount := len(mod.TextureProperties())
+	if textureCount == 0 {
+		textureCount = world.MaxWorldTextures
+	}
+	for levelID := 0; levelID < archive.MaxLevels; levelID++ {
+		lvl := level.NewLevel(ids.LevelResourcesStart, levelID, mod)
+		tileMapID := ids.LevelResourcesStart.Plus(lvlids.PerLevel*levelID + lvlids.TileMap)
+		for _, ref := range lvl.DanglingTextureReferences(textureCount) {
+			report(Finding{
+				Severity: SeverityError,
+				Category: CategoryLevels,
+				Resource: resource.KeyOf(tileMapID, resource.LangAny, 0),
+				Message:  fmt.Sprintf("level %d %v", levelID, ref),
+			})
+		}
+	}
+}
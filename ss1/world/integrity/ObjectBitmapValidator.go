@@ -0,0 +1,110 @@
+package integrity
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/inkyblackness/hacked/ss1/content/bitmap"
+	"github.com/inkyblackness/hacked/ss1/content/object"
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/world"
+	"github.com/inkyblackness/hacked/ss1/world/ids"
+)
+
+// ObjectBitmapIndices returns the block indices of the object bitmaps for each object type.
+// Each type has three bitmaps, followed by the frames of its 3D bitmap.
+// The first block of the resource is not associated with any type.
+func ObjectBitmapIndices(table object.PropertiesTable) map[object.Triple][]int {
+	result := make(map[object.Triple][]int)
+	start := 1
+	table.Iterate(func(triple object.Triple, prop *object.Properties) bool {
+		count := 3 + int(prop.Common.Bitmap3D.FrameNumber())
+		indices := make([]int, count)
+		for offset := range indices {
+			indices[offset] = start + offset
+		}
+		result[triple] = indices
+		start += count
+		return true
+	})
+	return result
+}
+
+// ValidateObjectBitmaps checks that each object type has its bitmaps, and that they can be decoded.
+// Bitmaps without a block in the resource are reported as missing, separately from bitmaps that are present,
+// yet are empty or can not be decoded.
+// Findings are reported per object type, in order of class, subclass, and type.
+func ValidateObjectBitmaps(mod *world.Mod, report func(Finding)) {
+	table := mod.ObjectProperties()
+	if len(table) == 0 {
+		return
+	}
+	view, err := mod.LocalizedResources(resource.LangAny).Select(ids.ObjectBitmaps)
+	if err != nil {
+		report(Finding{
+			Severity: SeverityError,
+			Category: CategoryObjectBitmaps,
+			Resource: resource.KeyOf(ids.ObjectBitmaps, resource.LangAny, 0),
+			Message:  "no object bitmap resource, all objects are missing their bitmaps",
+		})
+		return
+	}
+	ranges := ObjectBitmapIndices(table)
+	for _, triple := range table.Triples() {
+		var missing []int
+		for offset, index := range ranges[triple] {
+			if index >= view.BlockCount() {
+				missing = append(missing, offset)
+				continue
+			}
+			if problem := objectBitmapProblem(blockData(view, index)); len(problem) > 0 {
+				report(Finding{
+					Severity: SeverityError,
+					Category: CategoryObjectBitmaps,
+					Resource: resource.KeyOf(ids.ObjectBitmaps, resource.LangAny, index),
+					Message:  fmt.Sprintf("object %v bitmap %d is corrupt: %s", triple, offset, problem),
+				})
+			}
+		}
+		if len(missing) > 0 {
+			report(Finding{
+				Severity: SeverityError,
+				Category: CategoryObjectBitmaps,
+				Resource: resource.KeyOf(ids.ObjectBitmaps, resource.LangAny, ranges[triple][missing[0]]),
+				Message:  fmt.Sprintf("object %v is missing bitmaps %v", triple, missing),
+			})
+		}
+	}
+}
+
+// blockData returns the raw data of the identified block. Blocks that are not available return nil.
+func blockData(view resource.View, index int) []byte {
+	if index >= view.BlockCount() {
+		return nil
+	}
+	reader, err := view.Block(index)
+	if err != nil {
+		return nil
+	}
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// objectBitmapProblem returns a description of why the given bitmap data is unusable, or an empty string.
+func objectBitmapProblem(data []byte) string {
+	if len(data) == 0 {
+		return "block is present but empty"
+	}
+	bmp, err := bitmap.Decode(bytes.NewReader(data))
+	if err != nil {
+		return err.Error()
+	}
+	if (bmp.Header.Width <= 0) || (bmp.Header.Height <= 0) {
+		return fmt.Sprintf("invalid size %dx%d", bmp.Header.Width, bmp.Header.Height)
+	}
+	return ""
+}
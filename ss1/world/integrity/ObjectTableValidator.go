@@ -0,0 +1,79 @@
+package integrity
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/inkyblackness/hacked/ss1/content/object"
+	"github.com/inkyblackness/hacked/ss1/edit"
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/world"
+	"github.com/inkyblackness/hacked/ss1/world/ids"
+)
+
+// ValidateObjectTables checks that the classes stay within the limit of object types, and that the
+// name tables of objects have an entry for each object type that fits its name slot.
+func ValidateObjectTables(mod *world.Mod, report func(Finding)) {
+	table := mod.ObjectProperties()
+	for class, classProperties := range table {
+		typeCount := 0
+		for _, subclassProperties := range classProperties {
+			typeCount += len(subclassProperties)
+		}
+		if typeCount > object.MaxTypesPerClass {
+			report(Finding{
+				Severity: SeverityError,
+				Category: CategoryObjectTables,
+				Resource: resource.KeyOf(ids.ObjectLongNames, resource.LangAny, 0),
+				Message: fmt.Sprintf("class %v has %d object types, the limit is %d",
+					object.Class(class), typeCount, object.MaxTypesPerClass),
+			})
+		}
+	}
+	objectCount := len(table.Triples())
+	for _, id := range []resource.ID{ids.ObjectShortNames, ids.ObjectLongNames} {
+		for _, lang := range resource.Languages() {
+			key := resource.KeyOf(id, lang, 0)
+			view, err := mod.LocalizedResources(lang).Select(id)
+			if err != nil {
+				report(Finding{
+					Severity: SeverityWarning,
+					Category: CategoryObjectTables,
+					Resource: key,
+					Message:  "object names are missing",
+				})
+				continue
+			}
+			validateObjectNameLengths(view, id, lang, report)
+			if view.BlockCount() < objectCount {
+				report(Finding{
+					Severity: SeverityError,
+					Category: CategoryObjectTables,
+					Resource: key,
+					Message:  fmt.Sprintf("has %d object names for %d objects", view.BlockCount(), objectCount),
+				})
+			}
+		}
+	}
+}
+
+// validateObjectNameLengths reports names that are longer than their name slot.
+// Names are encoded with one byte per character and are terminated with a zero byte.
+func validateObjectNameLengths(view resource.View, id resource.ID, lang resource.Language, report func(Finding)) {
+	limit := edit.ObjectNameLimit(id == ids.ObjectLongNames)
+	for index := 0; index < view.BlockCount(); index++ {
+		data := blockData(view, index)
+		length := bytes.IndexByte(data, 0x00)
+		if length < 0 {
+			length = len(data)
+		}
+		if length > limit {
+			report(Finding{
+				Severity: SeverityWarning,
+				Category: CategoryObjectTables,
+				Resource: resource.KeyOf(id, lang, index),
+				Message:  fmt.Sprintf("name has %d characters, the limit is %d", length, limit),
+			})
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package integrity
+
+import (
+	"sort"
+
+	"github.com/inkyblackness/hacked/ss1/world"
+)
+
+// Validator checks a mod and reports its findings to the given function.
+type Validator func(mod *world.Mod, report func(Finding))
+
+// DefaultValidators returns the list of all validators of this package.
+func DefaultValidators() []Validator {
+	return []Validator{
+		ValidateResourceStructure,
+		ValidateObjectTables,
+		ValidateObjectBitmaps,
+		ValidateLevels,
+		ValidateLevelTextures,
+		ValidateUnusedResources,
+		ValidateLanguages,
+		ValidateDuplicateResources,
+	}
+}
+
+// Report is the aggregated list of findings.
+type Report struct {
+	// Findings are sorted by severity (most severe first), then category, then resource.
+	Findings []Finding
+}
+
+// Check runs all the given validators on the mod and returns the aggregated findings.
+// If no validators are given, the DefaultValidators() are used.
+func Check(mod *world.Mod, validators ...Validator) Report {
+	if len(validators) == 0 {
+		validators = DefaultValidators()
+	}
+	var report Report
+	for _, validator := range validators {
+		validator(mod, func(finding Finding) {
+			report.Findings = append(report.Findings, finding)
+		})
+	}
+	sort.SliceStable(report.Findings, func(a, b int) bool {
+		findingA := report.Findings[a]
+		findingB := report.Findings[b]
+		if findingA.Severity != findingB.Severity {
+			return findingA.Severity > findingB.Severity
+		}
+		if findingA.Category != findingB.Category {
+			return findingA.Category < findingB.Category
+		}
+		return findingA.Resource.ID < findingB.Resource.ID
+	})
+	return report
+}
+
+// Count returns the number of findings with given severity.
+func (report Report) Count(severity Severity) int {
+	count := 0
+	for _, finding := range report.Findings {
+		if finding.Severity == severity {
+			count++
+		}
+	}
+	return count
+}
+
+// Categories returns the categories that have findings, in alphabetical order.
+func (report Report) Categories() []Category {
+	present := make(map[Category]bool)
+	var categories []Category
+	for _, finding := range report.Findings {
+		if !present[finding.Category] {
+			present[finding.Category] = true
+			categories = append(categories, finding.Category)
+		}
+	}
+	sort.Slice(categories, func(a, b int) bool { return categories[a] < categories[b] })
+	return categories
+}
+
+// InCategory returns the findings of given category, in the order of the report.
+func (report Report) InCategory(category Category) []Finding {
+	var findings []Finding
+	for _, finding := range report.Findings {
+		if finding.Category == category {
+			findings = append(findings, finding)
+		}
+	}
+	return findings
+}
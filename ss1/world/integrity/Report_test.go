@@ -0,0 +1,186 @@
+package integrity_test
+
+import (
+	"testing"
+
+	"github.com/inkyblackness/hacked/ss1/content/archive/level"
+	"github.com/inkyblackness/hacked/ss1/content/archive/level/lvlids"
+	"github.com/inkyblackness/hacked/ss1/content/bitmap"
+	"github.com/inkyblackness/hacked/ss1/content/object"
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/world"
+	"github.com/inkyblackness/hacked/ss1/world/ids"
+	"github.com/inkyblackness/hacked/ss1/world/integrity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func modWith(modifier func(world.Modder)) *world.Mod {
+	mod := world.NewMod(func([]resource.ID, []resource.ID) {}, func() {})
+	mod.Modify(modifier)
+	return mod
+}
+
+func TestCheckReportsUnusedResources(t *testing.T) {
+	mod := modWith(func(modder world.Modder) {
+		modder.SetResourceBlock(resource.LangAny, 0x0001, 0, []byte{0x01})
+	})
+
+	report := integrity.Check(mod, integrity.ValidateUnusedResources)
+
+	require.Equal(t, 1, len(report.Findings))
+	assert.Equal(t, integrity.CategoryUnusedResources, report.Findings[0].Category)
+	assert.Equal(t, resource.KeyOf(0x0001, resource.LangAny, 0), report.Findings[0].Resource)
+}
+
+func TestCheckReportsLanguageConflicts(t *testing.T) {
+	mod := modWith(func(modder world.Modder) {
+		modder.SetResourceBlock(resource.LangAny, 0x0001, 0, []byte{0x01})
+		modder.SetResourceBlock(resource.LangGerman, 0x0001, 0, []byte{0x02})
+	})
+
+	report := integrity.Check(mod, integrity.ValidateLanguages)
+
+	require.Equal(t, 1, len(report.Findings))
+	assert.Equal(t, resource.KeyOf(0x0001, resource.LangGerman, 0), report.Findings[0].Resource)
+}
+
+func TestCheckReportsIncompleteLevels(t *testing.T) {
+	mod := modWith(func(modder world.Modder) {
+		modder.SetResourceBlock(resource.LangAny, ids.LevelResourcesStart.Plus(lvlids.TileMap), 0, []byte{0x01})
+	})
+
+	report := integrity.Check(mod, integrity.ValidateLevels)
+
+	assert.Equal(t, 4, report.Count(integrity.SeverityError), "missing resources of level 0 expected")
+	assert.Equal(t, 15, report.Count(integrity.SeverityWarning), "other levels should be reported empty")
+	assert.Equal(t, integrity.SeverityError, report.Findings[0].Severity, "errors should be first")
+}
+
+func TestCheckAggregatesAllValidatorsByDefault(t *testing.T) {
+	mod := modWith(func(modder world.Modder) {
+		modder.SetResourceBlock(resource.LangAny, 0x0001, 0, []byte{0x01})
+	})
+
+	report := integrity.Check(mod)
+
+	assert.Equal(t, []integrity.Category{
+		integrity.CategoryLevels,
+		integrity.CategoryObjectTables,
+		integrity.CategoryUnusedResources,
+	}, report.Categories())
+	assert.Equal(t, 1, len(report.InCategory(integrity.CategoryUnusedResources)))
+	for index := 1; index < len(report.Findings); index++ {
+		assert.True(t, report.Findings[index-1].Severity >= report.Findings[index].Severity, "findings should be sorted")
+	}
+}
+
+func TestCheckReportsDanglingLevelTextures(t *testing.T) {
+	levelData := level.EmptyLevelData(level.EmptyLevelParameters{MapModifier: func(tiles level.TileMap) {
+		tile := tiles.Tile(10, 20)
+		tile.Type = level.TileTypeOpen
+		tile.TextureInfo = tile.TextureInfo.WithWallTextureIndex(60)
+	}})
+	mod := modWith(func(modder world.Modder) {
+		for id, data := range levelData {
+			if len(data) > 0 {
+				modder.SetResourceBlock(resource.LangAny, ids.LevelResourcesStart.Plus(id), 0, data)
+			}
+		}
+	})
+
+	report := integrity.Check(mod, integrity.ValidateLevelTextures)
+
+	require.Equal(t, 1, len(report.Findings))
+	assert.Equal(t, resource.KeyOf(ids.LevelResourcesStart.Plus(lvlids.TileMap), resource.LangAny, 0), report.Findings[0].Resource)
+	assert.Contains(t, report.Findings[0].Message, "tile 10/20 Wall")
+}
+
+func TestCheckReportsMissingObjectBitmapResource(t *testing.T) {
+	mod := modWith(func(modder world.Modder) {})
+	mod.Reset(nil, object.StandardPropertiesTable(), nil)
+
+	report := integrity.Check(mod, integrity.ValidateObjectBitmaps)
+
+	require.Equal(t, 1, len(report.Findings))
+	assert.Equal(t, integrity.CategoryObjectBitmaps, report.Findings[0].Category)
+}
+
+func TestCheckReportsEmptyObjectBitmapsAsCorrupt(t *testing.T) {
+	table := object.StandardPropertiesTable()
+	indices := integrity.ObjectBitmapIndices(table)
+	first := table.Triples()[0]
+	second := table.Triples()[1]
+	mod := world.NewMod(func([]resource.ID, []resource.ID) {}, func() {})
+	mod.Reset(nil, table, nil)
+	mod.Modify(func(modder world.Modder) {
+		modder.SetResourceBlock(resource.LangAny, ids.ObjectBitmaps, indices[second][0], []byte{0x01, 0x02})
+	})
+
+	report := integrity.Check(mod, integrity.ValidateObjectBitmaps)
+
+	require.True(t, len(report.Findings) > 0)
+	assert.Contains(t, report.Findings[0].Message, "object "+first.String()+" bitmap 0 is corrupt: block is present but empty")
+	for _, finding := range report.Findings {
+		assert.NotContains(t, finding.Message, "object "+first.String()+" is missing")
+	}
+}
+
+func TestCheckReportsMissingAndCorruptObjectBitmaps(t *testing.T) {
+	table := object.StandardPropertiesTable()
+	indices := integrity.ObjectBitmapIndices(table)
+	first := table.Triples()[0]
+	second := table.Triples()[1]
+	validBitmap := bitmap.Encode(&bitmap.Bitmap{
+		Header: bitmap.Header{Type: bitmap.TypeFlat8Bit, Width: 1, Height: 1, Stride: 1},
+		Pixels: []byte{0x01},
+	}, 0)
+	mod := world.NewMod(func([]resource.ID, []resource.ID) {}, func() {})
+	mod.Reset(nil, table, nil)
+	mod.Modify(func(modder world.Modder) {
+		for _, index := range indices[first] {
+			modder.SetResourceBlock(resource.LangAny, ids.ObjectBitmaps, index, validBitmap)
+		}
+		modder.SetResourceBlock(resource.LangAny, ids.ObjectBitmaps, indices[second][0], []byte{0x01, 0x02})
+	})
+
+	report := integrity.Check(mod, integrity.ValidateObjectBitmaps)
+
+	require.True(t, len(report.Findings) > 2)
+	assert.Contains(t, report.Findings[0].Message, "object "+second.String()+" bitmap 0 is corrupt")
+	assert.Contains(t, report.Findings[1].Message, "object "+second.String()+" is missing bitmaps")
+	for _, finding := range report.Findings {
+		assert.NotContains(t, finding.Message, "object "+first.String()+" ")
+	}
+}
+
+func TestCheckReportsDuplicateResources(t *testing.T) {
+	mod := modWith(func(modder world.Modder) {
+		modder.SetResourceBlock(resource.LangAny, 0x0001, 0, []byte{0x01, 0x02})
+		modder.SetResourceBlock(resource.LangAny, 0x0002, 0, []byte{0x01, 0x02})
+		modder.SetResourceBlock(resource.LangAny, 0x0003, 0, []byte{0x03})
+	})
+
+	report := integrity.Check(mod, integrity.ValidateDuplicateResources)
+
+	require.Equal(t, 1, len(report.Findings))
+	assert.Equal(t, integrity.SeverityInfo, report.Findings[0].Severity)
+	assert.Equal(t, resource.KeyOf(0x0001, resource.LangAny, 0), report.Findings[0].Resource)
+	assert.Contains(t, report.Findings[0].Message, "0002/Any/0")
+	assert.NotContains(t, report.Findings[0].Message, "keep")
+}
+
+func TestCheckIgnoresDuplicatesOfArchiveAndTexts(t *testing.T) {
+	levelStart := ids.LevelResourcesStart
+	mod := modWith(func(modder world.Modder) {
+		modder.SetResourceBlock(resource.LangAny, levelStart.Plus(lvlids.Parameters), 0, []byte{0x01, 0x02})
+		modder.SetResourceBlock(resource.LangAny, levelStart.Plus(lvlids.PerLevel+lvlids.Parameters), 0, []byte{0x01, 0x02})
+		modder.SetResourceBlock(resource.LangDefault, ids.TrapMessageTexts, 0, []byte{0x00})
+		modder.SetResourceBlock(resource.LangDefault, ids.TrapMessageTexts, 1, []byte{0x00})
+	})
+
+	report := integrity.Check(mod, integrity.ValidateDuplicateResources)
+
+	assert.Empty(t, report.Findings)
+}
@@ -0,0 +1,141 @@
+package integrity
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/inkyblackness/hacked/ss1/content/archive"
+	"github.com/inkyblackness/hacked/ss1/content/archive/level/lvlids"
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/world"
+	"github.com/inkyblackness/hacked/ss1/world/ids"
+)
+
+// ValidateResourceStructure checks the resources of the mod against the properties the game expects.
+// Resources unknown to the game are not checked, see ValidateUnusedResources.
+func ValidateResourceStructure(mod *world.Mod, report func(Finding)) {
+	for _, loc := range mod.ModifiedResources() {
+		for _, id := range loc.Store.IDs() {
+			info, known := ids.Info(id)
+			res, err := loc.Store.Resource(id)
+			if !known || (err != nil) {
+				continue
+			}
+			key := resource.KeyOf(id, loc.Language, 0)
+			reportError := func(format string, args ...interface{}) {
+				report(Finding{
+					Severity: SeverityError,
+					Category: CategoryResourceStructure,
+					Resource: key,
+					Message:  fmt.Sprintf(format, args...),
+				})
+			}
+			if res.ContentType() != info.ContentType {
+				reportError("content type is %v, expected %v", res.ContentType(), info.ContentType)
+			}
+			if res.Compound() != info.Compound {
+				reportError("compound flag is %v, expected %v", res.Compound(), info.Compound)
+			}
+			if !res.Compound() && (res.BlockCount() != 1) {
+				reportError("simple resource has %d blocks, expected 1", res.BlockCount())
+			}
+			if info.List && (info.MaxCount > 0) && (res.BlockCount() > info.MaxCount) {
+				reportError("list has %d entries, maximum is %d", res.BlockCount(), info.MaxCount)
+			}
+			if !info.ResFile.Matches(loc.Filename) {
+				report(Finding{
+					Severity: SeverityWarning,
+					Category: CategoryResourceStructure,
+					Resource: key,
+					Message:  fmt.Sprintf("stored in %v, the game does not load it from there", loc.Filename),
+				})
+			}
+		}
+	}
+}
+
+// ValidateUnusedResources reports resources of the mod that the game does not know about.
+func ValidateUnusedResources(mod *world.Mod, report func(Finding)) {
+	for _, loc := range mod.ModifiedResources() {
+		for _, id := range loc.Store.IDs() {
+			if _, known := ids.Info(id); !known {
+				report(Finding{
+					Severity: SeverityWarning,
+					Category: CategoryUnusedResources,
+					Resource: resource.KeyOf(id, loc.Language, 0),
+					Message:  fmt.Sprintf("resource in %v is not used by the game", loc.Filename),
+				})
+			}
+		}
+	}
+}
+
+// ValidateLanguages reports resources of the mod that are provided both language agnostic and for a specific language.
+// For the specific language, the language agnostic resource of the mod is hidden.
+func ValidateLanguages(mod *world.Mod, report func(Finding)) {
+	agnostic := make(map[resource.ID]bool)
+	for _, loc := range mod.ModifiedResources() {
+		if loc.Language == resource.LangAny {
+			for _, id := range loc.Store.IDs() {
+				agnostic[id] = true
+			}
+		}
+	}
+	for _, loc := range mod.ModifiedResources() {
+		if loc.Language == resource.LangAny {
+			continue
+		}
+		for _, id := range loc.Store.IDs() {
+			if agnostic[id] {
+				report(Finding{
+					Severity: SeverityWarning,
+					Category: CategoryLanguages,
+					Resource: resource.KeyOf(id, loc.Language, 0),
+					Message:  fmt.Sprintf("resource is also language agnostic; for %v, the language agnostic one is hidden", loc.Language),
+				})
+			}
+		}
+	}
+}
+
+// ValidateDuplicateResources reports blocks of the mod that have identical content, which wastes space.
+// Each group of duplicates is reported once, at its first key. Compound resources are compared per block.
+// As the game refers to blocks by their position, the findings are for information only.
+//
+// The resources of the archive and texts are not considered: Levels share identical state by design,
+// and many text entries are empty.
+func ValidateDuplicateResources(mod *world.Mod, report func(Finding)) {
+	checksums := make(map[resource.Key]resource.Checksum)
+	for _, loc := range mod.ModifiedResources() {
+		locChecksums, err := resource.BlockChecksums(loc.Store, loc.Language)
+		if err != nil {
+			continue
+		}
+		for key, checksum := range locChecksums {
+			if isDuplicateCandidate(loc.Store, key.ID) {
+				checksums[key] = checksum
+			}
+		}
+	}
+	for _, group := range resource.FindDuplicates(checksums) {
+		var redundant []string
+		for _, key := range group.Redundant() {
+			redundant = append(redundant, fmt.Sprintf("%v/%v/%d", key.ID, key.Lang, key.Index))
+		}
+		report(Finding{
+			Severity: SeverityInfo,
+			Category: CategoryDuplicates,
+			Resource: group.Keep(),
+			Message:  fmt.Sprintf("content is identical in %s", strings.Join(redundant, ", ")),
+		})
+	}
+}
+
+func isDuplicateCandidate(store resource.Store, id resource.ID) bool {
+	archiveEnd := ids.LevelResourcesStart.Plus(archive.MaxLevels * lvlids.PerLevel)
+	if (id >= ids.ArchiveName) && (id < archiveEnd) {
+		return false
+	}
+	res, err := store.Resource(id)
+	return (err == nil) && (res.ContentType() != resource.Text)
+}
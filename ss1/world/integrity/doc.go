@@ -0,0 +1,2 @@
+// Package integrity contains validators that check a mod for problems, and an aggregator for their findings.
+package integrity
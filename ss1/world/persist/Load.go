@@ -0,0 +1,78 @@
+package persist
+
+import (
+	"errors"
+
+	"github.com/inkyblackness/hacked/ss1/world"
+)
+
+// ErrNoResources is returned if the given files did not contain any usable resources.
+var ErrNoResources = errors.New("no usable resources found")
+
+// LoadManifestEntry stages the given files and returns them as a manifest entry.
+// The first name is used as the identifier of the entry.
+func LoadManifestEntry(names []string) (*world.ManifestEntry, error) {
+	return LoadManifestEntryWithProgress(names, nil)
+}
+
+// LoadManifestEntryWithProgress is like LoadManifestEntry, and reports the progress of loading
+// to the given function. The function is optional and may be nil.
+func LoadManifestEntryWithProgress(names []string, progress ProgressFunc) (*world.ManifestEntry, error) {
+	staging := NewStaging()
+	staging.Progress = progress
+	staging.StageAll(names)
+	if len(staging.Resources) == 0 {
+		return nil, ErrNoResources
+	}
+	return staging.ManifestEntry(names[0]), nil
+}
+
+// LoadMod stages the given files and resets the mod with them.
+// The first name is used as the path of the mod. A mod loaded from a zip archive has no path,
+// as it can not be saved back into the archive.
+func LoadMod(mod *world.Mod, names []string) error {
+	return LoadModWithProgress(mod, names, nil)
+}
+
+// LoadModWithProgress is like LoadMod, and reports the progress of loading
+// to the given function. The function is optional and may be nil.
+func LoadModWithProgress(mod *world.Mod, names []string, progress ProgressFunc) error {
+	staged, err := StageMod(names, progress)
+	if err != nil {
+		return err
+	}
+	staged.Apply(mod)
+	return nil
+}
+
+// StagedMod contains the files of a mod that were staged, yet not applied to a mod.
+type StagedMod struct {
+	path    string
+	staging *Staging
+}
+
+// StageMod stages the given files for a mod, reporting the progress to the given function,
+// which is optional and may be nil. The first name is used as the path of the mod.
+// As no mod is modified, this can be done concurrently to a mod being in use. See StagedMod.Apply().
+func StageMod(names []string, progress ProgressFunc) (*StagedMod, error) {
+	staging := NewStaging()
+	staging.Progress = progress
+	staging.StageAll(names)
+	if len(staging.Resources) == 0 {
+		return nil, ErrNoResources
+	}
+	staged := &StagedMod{staging: staging}
+	if !IsZipArchive(names[0]) {
+		staged.path = names[0]
+	}
+	return staged, nil
+}
+
+// Apply resets the given mod with the staged files.
+// A mod staged from a zip archive has no path, as it can not be saved back into the archive.
+func (staged StagedMod) Apply(mod *world.Mod) {
+	mod.SetPath(staged.path)
+	mod.Reset(staged.staging.LocalizedResources(), staged.staging.ObjectProperties, staged.staging.TextureProperties)
+	// fix list resources for any "old" mod.
+	mod.FixListResources()
+}
@@ -0,0 +1,94 @@
+package persist
+
+import (
+	"bytes"
+	"io"
+	"path/filepath"
+
+	"github.com/inkyblackness/hacked/ss1/content/object"
+	"github.com/inkyblackness/hacked/ss1/content/texture"
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/resource/lgres"
+	"github.com/inkyblackness/hacked/ss1/serial"
+	"github.com/inkyblackness/hacked/ss1/world"
+)
+
+// SaveOptions control how the files of a mod are written.
+type SaveOptions struct {
+	// Compression is consulted for every resource that is written.
+	Compression lgres.CompressionOptions
+}
+
+// DefaultSaveOptions returns the options with default compression.
+func DefaultSaveOptions() SaveOptions {
+	return SaveOptions{Compression: lgres.DefaultCompressionOptions()}
+}
+
+// SaveModTo writes all modified files of the mod into the given path.
+// Existing files are kept as backups according to the given backup strategy.
+func SaveModTo(mod *world.Mod, modPath string, backup *world.FileBackup, options SaveOptions) error {
+	localized := mod.ModifiedResources()
+	filenamesToSave := mod.ModifiedFilenames()
+
+	shallBeSaved := func(filename string) bool {
+		for _, toSave := range filenamesToSave {
+			if toSave == filename {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, loc := range localized {
+		if shallBeSaved(loc.Filename) {
+			err := saveResourcesTo(backup, loc.Store, filepath.Join(modPath, loc.Filename), options)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if shallBeSaved(world.TexturePropertiesFilename) {
+		err := saveTexturePropertiesTo(backup, mod.TextureProperties(), filepath.Join(modPath, world.TexturePropertiesFilename))
+		if err != nil {
+			return err
+		}
+	}
+	if shallBeSaved(world.ObjectPropertiesFilename) {
+		err := saveObjectPropertiesTo(backup, mod.ObjectProperties(), filepath.Join(modPath, world.ObjectPropertiesFilename))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func saveResourcesTo(backup *world.FileBackup, viewer resource.Viewer, absFilename string, options SaveOptions) error {
+	return backup.Save(absFilename, func(writer io.WriteSeeker) error {
+		return lgres.WriteWith(writer, viewer, lgres.WriteOptions{Compression: &options.Compression})
+	})
+}
+
+func saveTexturePropertiesTo(backup *world.FileBackup, list texture.PropertiesList, absFilename string) error {
+	return saveCodableTo(backup, list, absFilename)
+}
+
+func saveObjectPropertiesTo(backup *world.FileBackup, list object.PropertiesTable, absFilename string) error {
+	return saveCodableTo(backup, list, absFilename)
+}
+
+func saveCodableTo(backup *world.FileBackup, codable serial.Codable, absFilename string) error {
+	buffer := bytes.NewBuffer(nil)
+	encoder := serial.NewEncoder(buffer)
+	codable.Code(encoder)
+	err := encoder.FirstError()
+	if err != nil {
+		return err
+	}
+
+	return backup.Save(absFilename, func(writer io.WriteSeeker) error {
+		_, writeErr := writer.Write(buffer.Bytes())
+		return writeErr
+	})
+}
@@ -0,0 +1,270 @@
+package persist
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/inkyblackness/hacked/ss1/content/object"
+	"github.com/inkyblackness/hacked/ss1/content/texture"
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/resource/lgres"
+	"github.com/inkyblackness/hacked/ss1/serial"
+	"github.com/inkyblackness/hacked/ss1/world"
+	"github.com/inkyblackness/hacked/ss1/world/ids"
+)
+
+// ProgressFunc is called for each file that was staged, regardless of whether it was usable.
+// The filename is the base name of the file. Loaded is the number of files processed so far,
+// total the number of files known so far. The total grows while directories and archives are expanded.
+type ProgressFunc func(filename string, loaded, total int)
+
+// Staging collects the resources and properties found in a set of files.
+type Staging struct {
+	resultMutex sync.Mutex
+
+	// Progress is optional and receives the progress of staging files.
+	// It is called from several goroutines, though never concurrently.
+	Progress ProgressFunc
+	loaded   int
+	total    int
+
+	// FailedFiles is the number of files that could not be read.
+	FailedFiles int
+	// Savegames contains the savegame files, by filename.
+	Savegames map[string]resource.Viewer
+	// Resources contains the resource files, by filename.
+	Resources map[string]resource.Viewer
+
+	// ObjectProperties is set if an object properties file was found.
+	ObjectProperties object.PropertiesTable
+	// TextureProperties is set if a texture properties file was found.
+	TextureProperties texture.PropertiesList
+}
+
+// NewStaging returns a new, empty instance.
+func NewStaging() *Staging {
+	return &Staging{
+		Resources: make(map[string]resource.Viewer),
+		Savegames: make(map[string]resource.Viewer),
+	}
+}
+
+// StageAll reads the given files. If only one name is given and it refers to a directory,
+// the files in that directory are staged. Only well-known filenames are considered
+// for resources in this case.
+// Files that are zip archives are staged with their contained files, see StageZip().
+func (staging *Staging) StageAll(names []string) {
+	staging.stageList(names, len(names) == 1)
+}
+
+// StageZip reads the files contained in the given zip archive, without extracting them to disk.
+// The files are considered by their base name, regardless of the directory they are stored under
+// in the archive. Only well-known filenames are considered for resources, unless the archive
+// contains only one file.
+// An error is returned if the source is not a zip archive.
+func (staging *Staging) StageZip(source io.ReaderAt, size int64) error {
+	return staging.stageZip(source, size, 0)
+}
+
+// stageZip stages the files of the archive. The given offset is added to the total count of files,
+// together with the number of contained files.
+func (staging *Staging) stageZip(source io.ReaderAt, size int64, totalOffset int) error {
+	archive, err := zip.NewReader(source, size)
+	if err != nil {
+		return err
+	}
+	var files []*zip.File
+	for _, file := range archive.File {
+		if !file.FileInfo().IsDir() {
+			files = append(files, file)
+		}
+	}
+	staging.addToTotal(len(files) + totalOffset)
+	for _, file := range files {
+		staging.stageZipFile(file, len(files) == 1)
+	}
+	return nil
+}
+
+// IsZipArchive returns true if the given name refers to a zip archive, based on its extension.
+func IsZipArchive(name string) bool {
+	return strings.ToLower(filepath.Ext(name)) == ".zip"
+}
+
+// LocalizedResources returns the staged resource files as localized resources.
+func (staging *Staging) LocalizedResources() []*world.LocalizedResources {
+	var locs []*world.LocalizedResources
+	for filename, viewer := range staging.Resources {
+		loc := &world.LocalizedResources{
+			Filename: filename,
+			Language: ids.LocalizeFilename(filename),
+		}
+		for _, id := range viewer.IDs() {
+			view, err := viewer.View(id)
+			if err == nil {
+				_ = loc.Store.Put(id, view)
+			}
+			// TODO: handle error?
+		}
+		locs = append(locs, loc)
+	}
+	return locs
+}
+
+// ManifestEntry returns the staged files as a manifest entry with given identifier.
+func (staging *Staging) ManifestEntry(id string) *world.ManifestEntry {
+	entry := &world.ManifestEntry{
+		ID: id,
+	}
+	for filename, viewer := range staging.Resources {
+		localized := resource.LocalizedResources{
+			ID:       filename,
+			Language: ids.LocalizeFilename(filename),
+			Viewer:   viewer,
+		}
+		entry.Resources = append(entry.Resources, localized)
+	}
+	entry.ObjectProperties = staging.ObjectProperties
+	entry.TextureProperties = staging.TextureProperties
+	return entry
+}
+
+func (staging *Staging) stageList(names []string, isOnlyStagedFile bool) {
+	var wg sync.WaitGroup
+
+	staging.addToTotal(len(names))
+	for _, name := range names {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			staging.stage(name, isOnlyStagedFile)
+		}(name)
+	}
+	wg.Wait()
+}
+
+func (staging *Staging) stage(name string, isOnlyStagedFile bool) {
+	fileInfo, err := os.Stat(name)
+	if err != nil {
+		staging.markFailedFile()
+		staging.reportLoaded(filepath.Base(name))
+		return
+	}
+	file, err := os.Open(name)
+	if err != nil {
+		staging.markFailedFile()
+		staging.reportLoaded(filepath.Base(name))
+		return
+	}
+	defer file.Close() // nolint: errcheck
+
+	if fileInfo.IsDir() {
+		// Only the contained files are counted.
+		staging.addToTotal(-1)
+		if isOnlyStagedFile {
+			subNames, _ := file.Readdirnames(0)
+			joinedSubNames := make([]string, len(subNames))
+			for index, subName := range subNames {
+				joinedSubNames[index] = filepath.Join(name, subName)
+			}
+			staging.stageList(joinedSubNames, false)
+		}
+	} else if IsZipArchive(name) {
+		// Only the contained files are counted.
+		err = staging.stageZip(file, fileInfo.Size(), -1)
+		if err != nil {
+			staging.markFailedFile()
+			staging.reportLoaded(filepath.Base(name))
+		}
+	} else {
+		defer staging.reportLoaded(filepath.Base(name))
+		fileData, err := ioutil.ReadAll(file)
+		if err != nil {
+			staging.markFailedFile()
+			return
+		}
+		staging.stageData(filepath.Base(name), fileData, isOnlyStagedFile)
+	}
+}
+
+func (staging *Staging) stageZipFile(file *zip.File, isOnlyStagedFile bool) {
+	defer staging.reportLoaded(path.Base(file.Name))
+	reader, err := file.Open()
+	if err != nil {
+		staging.markFailedFile()
+		return
+	}
+	defer reader.Close() // nolint: errcheck
+	fileData, err := ioutil.ReadAll(reader)
+	if err != nil {
+		staging.markFailedFile()
+		return
+	}
+	staging.stageData(path.Base(file.Name), fileData, isOnlyStagedFile)
+}
+
+func (staging *Staging) stageData(filename string, fileData []byte, isOnlyStagedFile bool) {
+	reader, err := lgres.ReaderFrom(bytes.NewReader(fileData))
+	if (err == nil) && (isOnlyStagedFile || fileWhitelist.Matches(filename)) {
+		staging.modify(func() {
+			if world.IsSavegame(reader) {
+				staging.Savegames[filename] = reader
+			} else {
+				staging.Resources[filename] = reader
+			}
+		})
+	}
+	if strings.ToLower(filename) == world.ObjectPropertiesFilename {
+		decoder := serial.NewDecoder(bytes.NewReader(fileData))
+		properties := object.StandardPropertiesTable()
+		properties.Code(decoder)
+		err = decoder.FirstError()
+		if err == nil {
+			staging.modify(func() { staging.ObjectProperties = properties })
+		}
+	}
+	if strings.ToLower(filename) == world.TexturePropertiesFilename && (len(fileData) > 4) {
+		decoder := serial.NewDecoder(bytes.NewReader(fileData))
+		entryCount := (len(fileData) - 4) / texture.PropertiesSize
+		properties := make(texture.PropertiesList, entryCount)
+		properties.Code(decoder)
+		err = decoder.FirstError()
+		if err == nil {
+			staging.modify(func() { staging.TextureProperties = properties })
+		}
+	}
+
+	if err != nil {
+		staging.markFailedFile()
+	}
+}
+
+func (staging *Staging) addToTotal(count int) {
+	staging.modify(func() { staging.total += count })
+}
+
+func (staging *Staging) reportLoaded(filename string) {
+	staging.modify(func() {
+		staging.loaded++
+		if staging.Progress != nil {
+			staging.Progress(filename, staging.loaded, staging.total)
+		}
+	})
+}
+
+func (staging *Staging) markFailedFile() {
+	staging.modify(func() { staging.FailedFiles++ })
+}
+
+func (staging *Staging) modify(modifier func()) {
+	staging.resultMutex.Lock()
+	defer staging.resultMutex.Unlock()
+	modifier()
+}
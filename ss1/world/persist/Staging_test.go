@@ -0,0 +1,134 @@
+package persist_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/resource/lgres"
+	"github.com/inkyblackness/hacked/ss1/serial"
+	"github.com/inkyblackness/hacked/ss1/world/persist"
+)
+
+func TestStageZipReadsContainedResourceFiles(t *testing.T) {
+	archive := zipArchive(t, map[string][]byte{
+		"mod/data/cybstrng.res": resourceFile(t, 0x0800),
+		"mod/data/GERSTRNG.RES": resourceFile(t, 0x0801),
+		"mod/readme.txt":        []byte("hello"),
+		"other/unknown.res":     resourceFile(t, 0x0802),
+	})
+
+	staging := persist.NewStaging()
+	err := staging.StageZip(bytes.NewReader(archive), int64(len(archive)))
+	require.Nil(t, err, "no error expected")
+
+	locs := staging.LocalizedResources()
+	languages := make(map[string]resource.Language)
+	for _, loc := range locs {
+		languages[loc.Filename] = loc.Language
+	}
+	assert.Equal(t, map[string]resource.Language{
+		"cybstrng.res": resource.LangDefault,
+		"GERSTRNG.RES": resource.LangGerman,
+	}, languages)
+}
+
+func TestStageZipAcceptsSingleFileOfAnyName(t *testing.T) {
+	archive := zipArchive(t, map[string][]byte{"custom.res": resourceFile(t, 0x0800)})
+
+	staging := persist.NewStaging()
+	err := staging.StageZip(bytes.NewReader(archive), int64(len(archive)))
+	require.Nil(t, err, "no error expected")
+
+	assert.Equal(t, 1, len(staging.Resources))
+}
+
+func TestStageZipReturnsErrorForOtherData(t *testing.T) {
+	data := []byte("not an archive")
+	err := persist.NewStaging().StageZip(bytes.NewReader(data), int64(len(data)))
+	assert.Error(t, err)
+}
+
+func TestIsZipArchive(t *testing.T) {
+	assert.True(t, persist.IsZipArchive("some/path/Mod.ZIP"))
+	assert.False(t, persist.IsZipArchive("some/path/archive.dat"))
+}
+
+func TestStageAllReportsProgressOfContainedFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "staging")
+	require.Nil(t, err, "no error expected creating temp dir")
+	defer func() { _ = os.RemoveAll(dir) }()
+	writeFile := func(name string, data []byte) {
+		require.Nil(t, ioutil.WriteFile(filepath.Join(dir, name), data, 0640), "no error expected writing file")
+	}
+	writeFile("cybstrng.res", resourceFile(t, 0x0800))
+	writeFile("readme.txt", []byte("hello"))
+	writeFile("extra.zip", zipArchive(t, map[string][]byte{
+		"gerstrng.res": resourceFile(t, 0x0801),
+		"frnstrng.res": resourceFile(t, 0x0802),
+	}))
+
+	var filenames []string
+	var lastLoaded, lastTotal int
+	staging := persist.NewStaging()
+	staging.Progress = func(filename string, loaded, total int) {
+		filenames = append(filenames, filename)
+		assert.Equal(t, lastLoaded+1, loaded, "loaded count should increase by one")
+		assert.True(t, loaded <= total, "loaded should not exceed total")
+		lastLoaded, lastTotal = loaded, total
+	}
+	staging.StageAll([]string{dir})
+
+	sort.Strings(filenames)
+	assert.Equal(t, []string{"cybstrng.res", "frnstrng.res", "gerstrng.res", "readme.txt"}, filenames)
+	assert.Equal(t, 4, lastTotal)
+	assert.Equal(t, 3, len(staging.Resources))
+}
+
+func TestStageAllReportsProgressOfMissingFile(t *testing.T) {
+	var reported []string
+	staging := persist.NewStaging()
+	staging.Progress = func(filename string, loaded, total int) {
+		reported = append(reported, filename)
+		assert.Equal(t, 1, loaded)
+		assert.Equal(t, 1, total)
+	}
+	staging.StageAll([]string{filepath.Join("does", "not", "exist.res")})
+
+	assert.Equal(t, []string{"exist.res"}, reported)
+	assert.Equal(t, 1, staging.FailedFiles)
+}
+
+func zipArchive(t *testing.T, files map[string][]byte) []byte {
+	t.Helper()
+	buf := bytes.NewBuffer(nil)
+	writer := zip.NewWriter(buf)
+	for name, data := range files {
+		entry, err := writer.Create(name)
+		require.Nil(t, err, "no error expected creating entry")
+		_, err = entry.Write(data)
+		require.Nil(t, err, "no error expected writing entry")
+	}
+	require.Nil(t, writer.Close(), "no error expected closing archive")
+	return buf.Bytes()
+}
+
+func resourceFile(t *testing.T, id resource.ID) []byte {
+	t.Helper()
+	var store resource.Store
+	_ = store.Put(id, resource.Resource{
+		Properties: resource.Properties{ContentType: resource.Text},
+		Blocks:     resource.BlocksFrom([][]byte{{0x01}}),
+	})
+	target := serial.NewByteStore()
+	require.Nil(t, lgres.Write(target, store), "no error expected writing resources")
+	return target.Data()
+}
@@ -1,4 +1,4 @@
-package project
+package persist
 
 import (
 	"github.com/inkyblackness/hacked/ss1/resource"
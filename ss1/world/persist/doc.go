@@ -0,0 +1,3 @@
+// Package persist loads mods and manifest entries from the file system and saves mods back to it.
+// It has no dependency on any user interface and can be used by headless tools.
+package persist
@@ -112,6 +112,12 @@ func (context *Context) NewFrame() {
 	imgui.NewFrame()
 }
 
+// AbortFrame ends a frame that was started with NewFrame() without rendering it.
+// It is meant to be called when rendering failed, so that the next frame can start again.
+func (context *Context) AbortFrame() {
+	imgui.EndFrame()
+}
+
 // Render must be called at the end of rendering.
 func (context *Context) Render(bitmapTextureQuery BitmapTextureQuery) {
 	imgui.Render()
@@ -0,0 +1,10 @@
+package gui
+
+import "github.com/inkyblackness/hacked/ui/opengl"
+
+// FileResultHandler is an optional extension of a ModalState that reports what happened to dropped files.
+type FileResultHandler interface {
+	// HandleFilesWithResults is called for any dropped files, instead of HandleFiles.
+	// It returns one result per file.
+	HandleFilesWithResults(names []string) []opengl.FileDropResult
+}
@@ -1,5 +1,16 @@
 package gui
 
+import (
+	"errors"
+
+	"github.com/inkyblackness/hacked/ui/opengl"
+)
+
+var (
+	errNoFileReceiver  = errors.New("no dialog is waiting for files")
+	errUnreportedFiles = errors.New("the dialog does not take files")
+)
+
 // ModalStateWrapper is a wrapper over a series of modal popup states.
 // It implements both ModalState and ModalStateMachine to allow nested states, if necessary.
 type ModalStateWrapper struct {
@@ -24,3 +35,17 @@ func (machine *ModalStateWrapper) HandleFiles(filenames []string) {
 		machine.State.HandleFiles(filenames)
 	}
 }
+
+// HandleFilesWithResults forwards the given filenames to the current state and reports their results.
+// Without a current state, all files are ignored. If the current state does not report
+// results on its own, all files are considered to be ignored, as it is not known what the state did with them.
+func (machine *ModalStateWrapper) HandleFilesWithResults(filenames []string) []opengl.FileDropResult {
+	if machine.State == nil {
+		return opengl.FileDropResultsOf(filenames, opengl.FileDropIgnored, errNoFileReceiver)
+	}
+	if handler, isHandler := machine.State.(FileResultHandler); isHandler {
+		return handler.HandleFilesWithResults(filenames)
+	}
+	machine.State.HandleFiles(filenames)
+	return opengl.FileDropResultsOf(filenames, opengl.FileDropIgnored, errUnreportedFiles)
+}
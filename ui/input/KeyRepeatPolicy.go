@@ -0,0 +1,15 @@
+package input
+
+// KeyRepeatPolicy specifies how repeated presses of a held key are reported.
+type KeyRepeatPolicy int
+
+// KeyRepeatPolicy constants.
+const (
+	// KeyRepeatCycle reports a repeat as release, followed by a press of the key.
+	// This is the default policy.
+	KeyRepeatCycle KeyRepeatPolicy = iota
+	// KeyRepeatDownOnly reports a repeat as press of the key, without a release.
+	KeyRepeatDownOnly
+	// KeyRepeatIgnore drops repeats. Only the initial press and the final release are reported.
+	KeyRepeatIgnore
+)
@@ -0,0 +1,14 @@
+package input
+
+// ModifierTracking specifies how a StickyKeyBuffer determines the active modifier.
+type ModifierTracking int
+
+// ModifierTracking constants.
+const (
+	// ModifierTrackingReported takes the modifier that is reported with each key event,
+	// augmented by the modifier key being pressed. This is the default tracking.
+	ModifierTrackingReported ModifierTracking = iota
+	// ModifierTrackingKeys derives the modifier only from the modifier keys that are held down,
+	// ignoring the modifier reported with key events. Use this if the reported modifiers are not reliable.
+	ModifierTrackingKeys
+)
@@ -1,6 +1,9 @@
 package input
 
-import "strings"
+import (
+	"strings"
+	"unicode"
+)
 
 type shortcut struct {
 	keyName  string
@@ -18,8 +21,15 @@ var shortcuts = []shortcut{
 	{keyName: "s", modifier: ModControl, key: KeySave},
 }
 
+// ScancodeMap maps platform-specific scancodes to the names of the keys at the
+// same physical position on the standard US keyboard layout.
+type ScancodeMap map[int]string
+
 // ResolveShortcut tries to map the given name and modifier combination to a
 // known (common) shortcut key. For instance, Ctrl+C is KeyCopy.
+//
+// The name is the one of the key in the active keyboard layout. See ResolveKeyShortcut for
+// the precedence between name and physical position of a key.
 func ResolveShortcut(keyName string, modifier Modifier) (key Key, knownKey bool) {
 	lowercaseName := strings.ToLower(keyName)
 	for _, entry := range shortcuts {
@@ -31,3 +41,39 @@ func ResolveShortcut(keyName string, modifier Modifier) (key Key, knownKey bool)
 
 	return
 }
+
+// ResolveShortcutByScancode tries to map the key at the physical position of the given scancode,
+// together with the modifier, to a known (common) shortcut key.
+// This resolution is independent of the keyboard layout.
+func ResolveShortcutByScancode(scancode int, scancodes ScancodeMap, modifier Modifier) (key Key, knownKey bool) {
+	keyName, knownScancode := scancodes[scancode]
+	if !knownScancode {
+		return
+	}
+	return ResolveShortcut(keyName, modifier)
+}
+
+// ResolveKeyShortcut maps a key, identified by both its name and its scancode, to a known shortcut key.
+//
+// The name of the key in the active layout takes precedence, so that shortcuts follow the labels of the keys.
+// The scancode is only consulted if the key has no name, or a name outside of the Latin alphabet,
+// such as with Cyrillic layouts. Keys with a Latin name that is not part of a shortcut are not resolved
+// by their physical position, as they would otherwise trigger shortcuts of an unrelated label.
+func ResolveKeyShortcut(keyName string, scancode int, scancodes ScancodeMap, modifier Modifier) (key Key, knownKey bool) {
+	if isLatinKeyName(keyName) {
+		return ResolveShortcut(keyName, modifier)
+	}
+	return ResolveShortcutByScancode(scancode, scancodes, modifier)
+}
+
+func isLatinKeyName(keyName string) bool {
+	if len(keyName) == 0 {
+		return false
+	}
+	for _, r := range keyName {
+		if r > unicode.MaxASCII {
+			return false
+		}
+	}
+	return true
+}
@@ -20,3 +20,44 @@ func TestResolveShortcutReturnsFalseForUnknownCombo(t *testing.T) {
 
 	assert.False(t, knownKey)
 }
+
+func TestResolveShortcutByScancodeUsesNameOfPhysicalKey(t *testing.T) {
+	scancodes := input.ScancodeMap{10: "z"}
+	key, knownKey := input.ResolveShortcutByScancode(10, scancodes, input.ModControl)
+
+	assert.True(t, knownKey)
+	assert.Equal(t, input.KeyUndo, key)
+}
+
+func TestResolveShortcutByScancodeReturnsFalseForUnknownScancode(t *testing.T) {
+	_, knownKey := input.ResolveShortcutByScancode(11, input.ScancodeMap{10: "z"}, input.ModControl)
+
+	assert.False(t, knownKey)
+}
+
+func TestResolveKeyShortcut(t *testing.T) {
+	scancodes := input.ScancodeMap{10: "z", 11: "w"}
+	tt := []struct {
+		name      string
+		keyName   string
+		scancode  int
+		knownKey  bool
+		resultKey input.Key
+	}{
+		{name: "nameTakesPrecedence", keyName: "z", scancode: 11, knownKey: true, resultKey: input.KeyUndo},
+		{name: "latinNameIsNotOverridden", keyName: "w", scancode: 10, knownKey: false},
+		{name: "missingNameUsesScancode", keyName: "", scancode: 10, knownKey: true, resultKey: input.KeyUndo},
+		{name: "nonLatinNameUsesScancode", keyName: "я", scancode: 10, knownKey: true, resultKey: input.KeyUndo},
+	}
+
+	for _, tc := range tt {
+		td := tc
+		t.Run(td.name, func(t *testing.T) {
+			key, knownKey := input.ResolveKeyShortcut(td.keyName, td.scancode, scancodes, input.ModControl)
+			assert.Equal(t, td.knownKey, knownKey)
+			if td.knownKey {
+				assert.Equal(t, td.resultKey, key)
+			}
+		})
+	}
+}
@@ -12,11 +12,16 @@ type StickyKeyListener interface {
 // Keys can be reported being pressed or released. Their state will be forwarded
 // to a StickyKeyListener instance. If a specific key is reported to be pressed
 // more than once, the listener will have received the down state only once.
+//
+// Key events are forwarded with the active modifier at the time of the press, and
+// every change of the active modifier is forwarded as modifier event.
 type StickyKeyBuffer struct {
-	pressedKeys     map[Key]int
-	pressedModifier map[Modifier]int
-	activeModifier  Modifier
-	listener        StickyKeyListener
+	pressedKeys      map[Key]int
+	pressedModifier  map[Modifier]int
+	activeModifier   Modifier
+	listener         StickyKeyListener
+	repeatPolicies   map[Key]KeyRepeatPolicy
+	modifierTracking ModifierTracking
 }
 
 // NewStickyKeyBuffer returns a new instance of a sticky key buffer.
@@ -25,7 +30,8 @@ func NewStickyKeyBuffer(listener StickyKeyListener) *StickyKeyBuffer {
 		pressedKeys:     make(map[Key]int),
 		pressedModifier: make(map[Modifier]int),
 		activeModifier:  ModNone,
-		listener:        listener}
+		listener:        listener,
+		repeatPolicies:  make(map[Key]KeyRepeatPolicy)}
 
 	return buffer
 }
@@ -35,21 +41,87 @@ func (buffer *StickyKeyBuffer) ActiveModifier() Modifier {
 	return buffer.activeModifier
 }
 
+// HeldModifiers returns the set of modifiers whose keys are currently held down.
+// Unlike ActiveModifier(), this set does not include modifiers that were only reported with key events.
+func (buffer *StickyKeyBuffer) HeldModifiers() Modifier {
+	held := ModNone
+	for mod, count := range buffer.pressedModifier {
+		if count > 0 {
+			held = held.With(mod)
+		}
+	}
+	return held
+}
+
+// SetModifierTracking sets how the active modifier is determined.
+// The active modifier is updated immediately, which may result in a modifier event.
+func (buffer *StickyKeyBuffer) SetModifierTracking(tracking ModifierTracking) {
+	buffer.modifierTracking = tracking
+	if tracking == ModifierTrackingKeys {
+		buffer.setActiveModifier(buffer.HeldModifiers())
+	}
+}
+
+// trackedModifier returns the modifier to use as active modifier, given the reported one.
+func (buffer *StickyKeyBuffer) trackedModifier(reported Modifier) Modifier {
+	if buffer.modifierTracking == ModifierTrackingKeys {
+		return buffer.HeldModifiers()
+	}
+	return reported
+}
+
 // KeyDown registers a pressed key state. Multiple down states can be
 // registered for the same key and result in only one key event.
 func (buffer *StickyKeyBuffer) KeyDown(key Key, modifier Modifier) {
 	keyAsModifier := key.AsModifier()
 
-	buffer.setActiveModifier(modifier.With(keyAsModifier))
 	if keyAsModifier == ModNone {
+		buffer.setActiveModifier(buffer.trackedModifier(modifier))
 		oldCount := buffer.pressedKeys[key]
 
 		buffer.pressedKeys[key] = oldCount + 1
 		if oldCount == 0 {
-			buffer.listener.Key(key, modifier)
+			buffer.listener.Key(key, buffer.activeModifier)
 		}
 	} else {
 		buffer.pressedModifier[keyAsModifier]++
+		buffer.setActiveModifier(buffer.trackedModifier(modifier.With(keyAsModifier)))
+	}
+}
+
+// SetRepeatPolicy sets how repeats of the given key are handled in KeyRepeat.
+// Keys without a dedicated policy use KeyRepeatCycle.
+func (buffer *StickyKeyBuffer) SetRepeatPolicy(key Key, policy KeyRepeatPolicy) {
+	if policy == KeyRepeatCycle {
+		delete(buffer.repeatPolicies, key)
+	} else {
+		buffer.repeatPolicies[key] = policy
+	}
+}
+
+// KeyRepeat registers a repeated press of a key that is held down.
+// Depending on the repeat policy of the key, this results in a release and another press,
+// only another press, or nothing at all.
+func (buffer *StickyKeyBuffer) KeyRepeat(key Key, modifier Modifier) {
+	switch buffer.repeatPolicies[key] {
+	case KeyRepeatCycle:
+		buffer.KeyUp(key, modifier)
+		buffer.KeyDown(key, modifier)
+	case KeyRepeatDownOnly:
+		keyAsModifier := key.AsModifier()
+		switch {
+		case keyAsModifier != ModNone:
+			// A held modifier stays active. Counting the repeat as press would keep it active after its release.
+			if buffer.pressedModifier[keyAsModifier] == 0 {
+				buffer.KeyDown(key, modifier)
+			}
+		case buffer.pressedKeys[key] == 0:
+			buffer.KeyDown(key, modifier)
+		default:
+			buffer.setActiveModifier(buffer.trackedModifier(modifier))
+			buffer.listener.Key(key, buffer.activeModifier)
+		}
+	case KeyRepeatIgnore:
 	}
 }
 
@@ -61,7 +133,7 @@ func (buffer *StickyKeyBuffer) KeyUp(key Key, modifier Modifier) {
 	if keyAsModifier == ModNone {
 		oldCount := buffer.pressedKeys[key]
 
-		buffer.setActiveModifier(modifier)
+		buffer.setActiveModifier(buffer.trackedModifier(modifier))
 		if oldCount > 0 {
 			buffer.pressedKeys[key] = oldCount - 1
 		}
@@ -71,16 +143,18 @@ func (buffer *StickyKeyBuffer) KeyUp(key Key, modifier Modifier) {
 		if oldCount > 0 {
 			buffer.pressedModifier[keyAsModifier] = oldCount - 1
 			if oldCount == 1 {
-				buffer.setActiveModifier(modifier.Without(keyAsModifier))
+				buffer.setActiveModifier(buffer.trackedModifier(modifier.Without(keyAsModifier)))
 			}
 		}
 	}
 }
 
 // ReleaseAll notifies the listener of the reset of all modifiers.
-// Key states are reset to accept new down states.
+// Key states are reset to accept new down states, and no modifier is considered held anymore.
+// This is meant to be called when the input focus is lost.
 func (buffer *StickyKeyBuffer) ReleaseAll() {
 	buffer.pressedKeys = make(map[Key]int)
+	buffer.pressedModifier = make(map[Modifier]int)
 	buffer.setActiveModifier(ModNone)
 }
 
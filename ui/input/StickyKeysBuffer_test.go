@@ -215,3 +215,89 @@ func (suite *StickyKeyBufferSuite) TestModifierAreUpdatedOnOtherKeysWithoutThems
 
 	assert.Equal(suite.T(), []keyEvent{{isKey: false, key: 0, mod: input.ModShift}}, suite.listener.events)
 }
+
+func (suite *StickyKeyBufferSuite) TestRepeatsCycleByDefault() {
+	suite.buffer.KeyDown(input.KeyF1, input.ModNone)
+	suite.buffer.KeyRepeat(input.KeyF1, input.ModNone)
+	suite.buffer.KeyUp(input.KeyF1, input.ModNone)
+	suite.buffer.KeyDown(input.KeyF1, input.ModNone)
+
+	assert.Equal(suite.T(), 3, len(suite.listener.eventMap[input.KeyF1]))
+}
+
+func (suite *StickyKeyBufferSuite) TestRepeatsCanBeReportedAsDownOnly() {
+	suite.buffer.SetRepeatPolicy(input.KeyF1, input.KeyRepeatDownOnly)
+	suite.buffer.KeyDown(input.KeyF1, input.ModNone)
+	suite.buffer.KeyRepeat(input.KeyF1, input.ModNone)
+	suite.buffer.KeyRepeat(input.KeyF1, input.ModNone)
+	suite.buffer.KeyUp(input.KeyF1, input.ModNone)
+	suite.buffer.KeyDown(input.KeyF1, input.ModNone)
+
+	assert.Equal(suite.T(), 4, len(suite.listener.eventMap[input.KeyF1]), "key should not remain pressed")
+}
+
+func (suite *StickyKeyBufferSuite) TestDownOnlyRepeatsOfModifiersDoNotKeepThemPressed() {
+	suite.buffer.SetRepeatPolicy(input.KeyShift, input.KeyRepeatDownOnly)
+	suite.buffer.KeyDown(input.KeyShift, input.ModShift)
+	suite.buffer.KeyRepeat(input.KeyShift, input.ModShift)
+	suite.buffer.KeyRepeat(input.KeyShift, input.ModShift)
+	suite.buffer.KeyRepeat(input.KeyShift, input.ModShift)
+	assert.Equal(suite.T(), input.ModShift, suite.buffer.HeldModifiers(), "modifier should be held while repeating")
+	suite.buffer.KeyUp(input.KeyShift, input.ModNone)
+
+	assert.Equal(suite.T(), input.ModNone, suite.buffer.HeldModifiers(), "modifier should be released")
+	assert.Equal(suite.T(), input.ModNone, suite.buffer.ActiveModifier(), "modifier should not be active")
+}
+
+func (suite *StickyKeyBufferSuite) TestDownOnlyRepeatsOfModifiersDoNotKeepThemPressedWhenTrackingKeys() {
+	suite.buffer.SetModifierTracking(input.ModifierTrackingKeys)
+	suite.buffer.SetRepeatPolicy(input.KeyControl, input.KeyRepeatDownOnly)
+	suite.buffer.KeyDown(input.KeyControl, input.ModControl)
+	suite.buffer.KeyRepeat(input.KeyControl, input.ModControl)
+	suite.buffer.KeyRepeat(input.KeyControl, input.ModControl)
+	suite.buffer.KeyUp(input.KeyControl, input.ModNone)
+	suite.buffer.KeyDown(input.KeyF1, input.ModNone)
+
+	assert.Equal(suite.T(), input.ModNone, suite.buffer.ActiveModifier(), "modifier should not be active")
+}
+
+func (suite *StickyKeyBufferSuite) TestRepeatsCanBeIgnored() {
+	suite.buffer.SetRepeatPolicy(input.KeyF1, input.KeyRepeatIgnore)
+	suite.buffer.KeyDown(input.KeyF1, input.ModNone)
+	suite.buffer.KeyRepeat(input.KeyF1, input.ModNone)
+	suite.buffer.KeyRepeat(input.KeyF1, input.ModNone)
+	suite.buffer.KeyUp(input.KeyF1, input.ModNone)
+	suite.buffer.KeyDown(input.KeyF1, input.ModNone)
+
+	assert.Equal(suite.T(), 2, len(suite.listener.eventMap[input.KeyF1]))
+}
+
+func (suite *StickyKeyBufferSuite) TestHeldModifiersReturnsPressedModifierKeys() {
+	suite.buffer.KeyDown(input.KeyShift, input.ModShift)
+	suite.buffer.KeyDown(input.KeyF1, input.ModShift.With(input.ModControl))
+
+	assert.Equal(suite.T(), input.ModShift, suite.buffer.HeldModifiers())
+	assert.Equal(suite.T(), input.ModShift.With(input.ModControl), suite.buffer.ActiveModifier())
+}
+
+func (suite *StickyKeyBufferSuite) TestReleaseAllClearsHeldModifiers() {
+	suite.buffer.KeyDown(input.KeyShift, input.ModShift)
+	suite.buffer.ReleaseAll()
+
+	assert.Equal(suite.T(), input.ModNone, suite.buffer.HeldModifiers())
+	suite.buffer.KeyDown(input.KeyControl, input.ModControl)
+	assert.Equal(suite.T(), input.ModControl, suite.buffer.HeldModifiers())
+}
+
+func (suite *StickyKeyBufferSuite) TestModifierTrackingByKeysIgnoresReportedModifier() {
+	suite.buffer.SetModifierTracking(input.ModifierTrackingKeys)
+	suite.buffer.KeyDown(input.KeyControl, input.ModNone)
+	suite.buffer.KeyDown(input.KeyF1, input.ModShift)
+	suite.buffer.KeyUp(input.KeyControl, input.ModNone)
+
+	assert.Equal(suite.T(), []keyEvent{
+		{isKey: false, key: 0, mod: input.ModControl},
+		{isKey: true, key: input.KeyF1, mod: input.ModControl},
+		{isKey: false, key: 0, mod: input.ModNone},
+	}, suite.listener.events)
+}
@@ -0,0 +1,26 @@
+package native
+
+import (
+	"runtime"
+
+	"github.com/inkyblackness/hacked/ui/input"
+)
+
+// shortcutScancodes maps the scancodes of the keys used in shortcuts to their names.
+// Scancodes are platform-specific, the values are those of the key positions on a standard US keyboard.
+// Only the keys of the common shortcuts are listed.
+var shortcutScancodes = scancodesFor(runtime.GOOS)
+
+func scancodesFor(goos string) input.ScancodeMap {
+	switch goos {
+	case "windows":
+		return input.ScancodeMap{0x1F: "s", 0x15: "y", 0x2C: "z", 0x2D: "x", 0x2E: "c", 0x2F: "v"}
+	case "darwin":
+		return input.ScancodeMap{0x01: "s", 0x10: "y", 0x06: "z", 0x07: "x", 0x08: "c", 0x09: "v"}
+	case "linux", "freebsd", "openbsd", "netbsd":
+		// X11 key codes, which are the evdev codes offset by 8.
+		return input.ScancodeMap{39: "s", 29: "y", 52: "z", 53: "x", 54: "c", 55: "v"}
+	default:
+		return input.ScancodeMap{}
+	}
+}
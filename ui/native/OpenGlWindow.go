@@ -25,12 +25,33 @@ type OpenGLWindow struct {
 	framesPerSecond float64
 	frameTime       time.Duration
 	nextRenderTick  time.Time
+
+	renderOnDemand  bool
+	refreshInterval time.Duration
+	pendingFrames   int
+	lastRender      time.Time
+
+	renderRecovery opengl.RenderRecovery
+
+	fullScreen       bool
+	windowedGeometry opengl.WindowGeometry
 }
 
+var _ opengl.Window = (*OpenGLWindow)(nil)
+var _ opengl.EventDispatcher = (*OpenGLWindow)(nil)
+
+// renderFramesPerRequest is the number of frames rendered for one render request in on-demand mode.
+// The GUI typically needs a few frames to settle after an input.
+const renderFramesPerRequest = 3
+
 // NewOpenGLWindow tries to initialize the OpenGL environment and returns a
 // new window instance.
-func NewOpenGLWindow(title string, framesPerSecond float64) (window *OpenGLWindow, err error) {
+// The window is created with the given geometry. A geometry with an invalid size,
+// such as the zero value, results in a window of default size. A position that is not
+// on any of the connected monitors is moved onto one.
+func NewOpenGLWindow(title string, geometry opengl.WindowGeometry, framesPerSecond float64) (window *OpenGLWindow, err error) {
 	if err = glfw.Init(); err == nil {
+		geometry = geometry.OrDefault(screenAreas())
 		glfw.WindowHint(glfw.Resizable, 1)
 		glfw.WindowHint(glfw.Decorated, 1)
 		glfw.WindowHint(glfw.ClientAPI, glfw.OpenGLAPI)
@@ -38,9 +59,16 @@ func NewOpenGLWindow(title string, framesPerSecond float64) (window *OpenGLWindo
 		glfw.WindowHint(glfw.ContextVersionMinor, 2)
 		glfw.WindowHint(glfw.OpenGLProfile, glfw.OpenGLCoreProfile)
 		glfw.WindowHint(glfw.OpenGLForwardCompatible, glfw.True)
+		if geometry.Positioned {
+			glfw.WindowHint(glfw.Visible, glfw.False)
+		}
 		var glfwWindow *glfw.Window
-		glfwWindow, err = glfw.CreateWindow(1280, 720, title, nil, nil)
+		glfwWindow, err = glfw.CreateWindow(geometry.Width, geometry.Height, title, nil, nil)
 		if err == nil {
+			if geometry.Positioned {
+				glfwWindow.SetPos(geometry.Left, geometry.Top)
+				glfwWindow.Show()
+			}
 			glfwWindow.MakeContextCurrent()
 
 			window = &OpenGLWindow{
@@ -66,6 +94,27 @@ func NewOpenGLWindow(title string, framesPerSecond float64) (window *OpenGLWindo
 	return
 }
 
+// screenAreas returns the regions of the connected monitors, with the primary monitor first.
+func screenAreas() []opengl.ScreenArea {
+	primary := glfw.GetPrimaryMonitor()
+	var areas []opengl.ScreenArea
+	for _, monitor := range glfw.GetMonitors() {
+		videoMode := monitor.GetVideoMode()
+		if videoMode == nil {
+			continue
+		}
+		var area opengl.ScreenArea
+		area.Left, area.Top = monitor.GetPos()
+		area.Width, area.Height = videoMode.Width, videoMode.Height
+		if monitor == primary {
+			areas = append([]opengl.ScreenArea{area}, areas...)
+		} else {
+			areas = append(areas, area)
+		}
+	}
+	return areas
+}
+
 // ShouldClose returns true if the user requested the window to close.
 func (window *OpenGLWindow) ShouldClose() bool {
 	return window.glfwWindow.ShouldClose()
@@ -78,6 +127,16 @@ func (window *OpenGLWindow) Close() {
 	glfw.Terminate()
 }
 
+// ClipboardContent returns what the clipboard currently holds.
+// GLFW only provides access to text, so any other content is reported as unknown.
+func (window OpenGLWindow) ClipboardContent() opengl.ClipboardContent {
+	value, err := window.glfwWindow.GetClipboardString()
+	if (err != nil) || (len(value) == 0) {
+		return opengl.ClipboardContentUnknown
+	}
+	return opengl.ClipboardContentText
+}
+
 // ClipboardString returns the current value of the clipboard, if it is compatible with UTF-8.
 func (window OpenGLWindow) ClipboardString() (string, error) {
 	return window.glfwWindow.GetClipboardString()
@@ -101,14 +160,70 @@ func (window *OpenGLWindow) Update() {
 	}
 
 	if delta.Nanoseconds() >= window.frameTime.Nanoseconds() {
-		window.glfwWindow.MakeContextCurrent()
-		window.CallRender()
-		window.glfwWindow.SwapBuffers()
+		if window.renderRequired(now) {
+			window.render(now)
+		}
 		framesCovered := delta.Nanoseconds() / window.frameTime.Nanoseconds()
 		window.nextRenderTick = window.nextRenderTick.Add(time.Duration(framesCovered * window.frameTime.Nanoseconds()))
 	}
 }
 
+func (window *OpenGLWindow) renderRequired(now time.Time) bool {
+	return !window.renderOnDemand || (window.pendingFrames > 0) || (now.Sub(window.lastRender) >= window.refreshInterval)
+}
+
+func (window *OpenGLWindow) render(now time.Time) {
+	window.glfwWindow.MakeContextCurrent()
+	if window.renderRecovery.Call(now, window.CallRender) {
+		// The frame is incomplete and is not shown. Any following frame shall start from a known state.
+		window.resetRenderState()
+	} else {
+		window.glfwWindow.SwapBuffers()
+	}
+	window.lastRender = now
+	if window.pendingFrames > 0 {
+		window.pendingFrames--
+	}
+}
+
+func (window *OpenGLWindow) resetRenderState() {
+	gl := window.glWrapper
+	// Clear the pending error flags of the failed frame. A context that keeps reporting errors
+	// is not drained forever.
+	for attempt := 0; attempt < 16; attempt++ {
+		if gl.GetError() == opengl.NO_ERROR {
+			break
+		}
+	}
+	gl.UseProgram(0)
+	gl.BindVertexArray(0)
+	gl.BindBuffer(opengl.ARRAY_BUFFER, 0)
+	gl.BindBuffer(opengl.ELEMENT_ARRAY_BUFFER, 0)
+	gl.ActiveTexture(opengl.TEXTURE0)
+	gl.BindTexture(opengl.TEXTURE_2D, 0)
+	gl.Disable(opengl.SCISSOR_TEST)
+}
+
+// SetRenderOnDemand switches between continuous rendering and rendering on demand.
+// In on-demand mode, the window renders only after user input, calls to RequestRender(),
+// or when the given refresh interval has elapsed since the last render.
+// The frame rate never exceeds the one specified at creation.
+func (window *OpenGLWindow) SetRenderOnDemand(onDemand bool, refreshInterval time.Duration) {
+	window.renderOnDemand = onDemand
+	window.refreshInterval = refreshInterval
+	window.RequestRender()
+}
+
+// SetKeyRepeatPolicy sets how repeats of a held key are reported to the key callback.
+func (window *OpenGLWindow) SetKeyRepeatPolicy(key input.Key, policy input.KeyRepeatPolicy) {
+	window.keyBuffer.SetRepeatPolicy(key, policy)
+}
+
+// RequestRender marks the window to be rendered with the next update.
+func (window *OpenGLWindow) RequestRender() {
+	window.pendingFrames = renderFramesPerRequest
+}
+
 // OpenGL returns the OpenGL API.
 func (window *OpenGLWindow) OpenGL() opengl.OpenGL {
 	return window.glWrapper
@@ -119,6 +234,19 @@ func (window *OpenGLWindow) Size() (width int, height int) {
 	return window.glfwWindow.GetFramebufferSize()
 }
 
+// Geometry returns the size and position of the window on the desktop.
+// While in full screen mode, the geometry of the windowed mode is returned.
+func (window *OpenGLWindow) Geometry() opengl.WindowGeometry {
+	if window.fullScreen {
+		return window.windowedGeometry
+	}
+	var geometry opengl.WindowGeometry
+	geometry.Width, geometry.Height = window.glfwWindow.GetSize()
+	geometry.Left, geometry.Top = window.glfwWindow.GetPos()
+	geometry.Positioned = true
+	return geometry
+}
+
 // SetCursorVisible toggles the visibility of the cursor.
 func (window *OpenGLWindow) SetCursorVisible(visible bool) {
 	if visible {
@@ -130,13 +258,19 @@ func (window *OpenGLWindow) SetCursorVisible(visible bool) {
 
 // SetFullScreen toggles the windowed mode.
 func (window *OpenGLWindow) SetFullScreen(on bool) {
+	if on == window.fullScreen {
+		return
+	}
 	if on {
+		window.windowedGeometry = window.Geometry()
 		monitor := glfw.GetPrimaryMonitor()
 		videoMode := monitor.GetVideoMode()
 		window.glfwWindow.SetMonitor(monitor, 0, 0, videoMode.Width, videoMode.Height, glfw.DontCare)
 	} else {
-		window.glfwWindow.SetMonitor(nil, 0, 0, 1280, 720, glfw.DontCare)
+		geometry := window.windowedGeometry.OrDefault(screenAreas())
+		window.glfwWindow.SetMonitor(nil, geometry.Left, geometry.Top, geometry.Width, geometry.Height, glfw.DontCare)
 	}
+	window.fullScreen = on
 }
 
 // SetCloseRequest sets the should-close property of the window.
@@ -145,18 +279,26 @@ func (window *OpenGLWindow) SetCloseRequest(shouldClose bool) {
 }
 
 func (window *OpenGLWindow) onClosing(rawWindow *glfw.Window) {
+	window.RequestRender()
+	if !window.CallCloseRequest() {
+		window.SetCloseRequest(false)
+		return
+	}
 	window.CallClosing()
 }
 
 func (window *OpenGLWindow) onFramebufferResize(rawWindow *glfw.Window, width int, height int) {
+	window.RequestRender()
 	window.CallResize(width, height)
 }
 
 func (window *OpenGLWindow) onCursorPos(rawWindow *glfw.Window, x float64, y float64) {
+	window.RequestRender()
 	window.CallOnMouseMove(float32(x), float32(y))
 }
 
 func (window *OpenGLWindow) onMouseButton(rawWindow *glfw.Window, rawButton glfw.MouseButton, action glfw.Action, mods glfw.ModifierKey) {
+	window.RequestRender()
 	button, knownButton := buttonsByIndex[rawButton]
 
 	if knownButton {
@@ -171,10 +313,12 @@ func (window *OpenGLWindow) onMouseButton(rawWindow *glfw.Window, rawButton glfw
 }
 
 func (window *OpenGLWindow) onMouseScroll(rawWindow *glfw.Window, dx float64, dy float64) {
+	window.RequestRender()
 	window.CallOnMouseScroll(float32(dx), float32(dy))
 }
 
 func (window *OpenGLWindow) onKey(rawWindow *glfw.Window, glfwKey glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) {
+	window.RequestRender()
 	modifier := window.mapModifier(mods)
 	key, knownKey := keyMap[glfwKey]
 
@@ -183,20 +327,20 @@ func (window *OpenGLWindow) onKey(rawWindow *glfw.Window, glfwKey glfw.Key, scan
 		case glfw.Press:
 			window.keyBuffer.KeyDown(key, modifier)
 		case glfw.Repeat:
-			window.keyBuffer.KeyUp(key, modifier)
-			window.keyBuffer.KeyDown(key, modifier)
+			window.keyBuffer.KeyRepeat(key, modifier)
 		case glfw.Release:
 			window.keyBuffer.KeyUp(key, modifier)
 		}
 	} else if action != glfw.Release {
 		keyName := glfw.GetKeyName(glfwKey, scancode)
-		if key, knownKey = input.ResolveShortcut(keyName, modifier); knownKey {
+		if key, knownKey = input.ResolveKeyShortcut(keyName, scancode, shortcutScancodes, modifier); knownKey {
 			window.CallKey(key, modifier)
 		}
 	}
 }
 
 func (window *OpenGLWindow) onChar(rawWindow *glfw.Window, char rune) {
+	window.RequestRender()
 	window.CallCharCallback(char)
 }
 
@@ -220,5 +364,6 @@ func (window *OpenGLWindow) mapModifier(mods glfw.ModifierKey) input.Modifier {
 }
 
 func (window *OpenGLWindow) onDrop(rawWindow *glfw.Window, filePaths []string) {
+	window.RequestRender()
 	window.CallFileDropCallback(filePaths)
 }
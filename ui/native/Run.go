@@ -7,15 +7,15 @@ import (
 	"github.com/inkyblackness/hacked/ui/opengl"
 )
 
-// Run creates a native OpenGL window, initializes it with the given function and
+// Run creates a native OpenGL window with the given geometry, initializes it with the given function and
 // then runs the event loop until the window shall be closed.
 // The provided deferrer is a channel of tasks that can be injected into the event loop.
 // When the channel is closed, the loop is stopped and the window is closed.
-func Run(initializer func(opengl.Window) error, title string, framesPerSecond float64, deferrer <-chan func()) (err error) {
+func Run(initializer func(opengl.Window) error, title string, geometry opengl.WindowGeometry, framesPerSecond float64, deferrer <-chan func()) (err error) {
 	runtime.LockOSThread()
 
 	var window *OpenGLWindow
-	window, err = NewOpenGLWindow(title, framesPerSecond)
+	window, err = NewOpenGLWindow(title, geometry, framesPerSecond)
 	if err != nil {
 		return
 	}
@@ -32,6 +32,7 @@ func Run(initializer func(opengl.Window) error, title string, framesPerSecond fl
 		case task, ok := <-deferrer:
 			if ok {
 				task()
+				window.RequestRender()
 			} else {
 				stopLoop = true
 			}
@@ -0,0 +1,27 @@
+package opengl
+
+// ClipboardContent describes what the clipboard currently holds.
+type ClipboardContent int
+
+// ClipboardContent constants.
+const (
+	// ClipboardContentUnknown is reported if the clipboard is empty, holds content that is not supported,
+	// or if the content can not be determined on the current platform.
+	ClipboardContentUnknown ClipboardContent = iota
+	// ClipboardContentText is reported if the clipboard holds text that is compatible with UTF-8.
+	ClipboardContentText
+	// ClipboardContentImage is reported if the clipboard holds an image.
+	ClipboardContentImage
+)
+
+// String returns the textual representation of the value.
+func (content ClipboardContent) String() string {
+	switch content {
+	case ClipboardContentText:
+		return "Text"
+	case ClipboardContentImage:
+		return "Image"
+	default:
+		return "Unknown"
+	}
+}
@@ -0,0 +1,35 @@
+package opengl
+
+import "github.com/inkyblackness/hacked/ui/input"
+
+// EventDispatcher forwards events of a window to the callbacks registered via the Window interface.
+// Native windows dispatch the events they receive from the operating system. Tests can dispatch
+// synthesized events the same way, without an actual window or OpenGL context.
+type EventDispatcher interface {
+	// CallCloseRequest asks whether the window may be closed. It returns false if the request was vetoed.
+	CallCloseRequest() bool
+	// CallClosing notifies that the window is about to close.
+	CallClosing()
+	// CallClosed notifies that the window is being closed.
+	CallClosed()
+	// CallRender requests to render the scene.
+	CallRender()
+	// CallResize notifies about new dimensions of the window.
+	CallResize(width int, height int)
+	// CallOnMouseMove notifies about the current mouse coordinate.
+	CallOnMouseMove(x float32, y float32)
+	// CallOnMouseButtonDown notifies about pressed mouse buttons.
+	CallOnMouseButtonDown(buttonMask uint32, modifier input.Modifier)
+	// CallOnMouseButtonUp notifies about released mouse buttons.
+	CallOnMouseButtonUp(buttonMask uint32, modifier input.Modifier)
+	// CallOnMouseScroll notifies about scrolling.
+	CallOnMouseScroll(dx float32, dy float32)
+	// CallKey notifies about a pressed or released key.
+	CallKey(key input.Key, modifier input.Modifier)
+	// CallModifier notifies about a change of the currently active modifier.
+	CallModifier(modifier input.Modifier)
+	// CallCharCallback notifies about a typed character.
+	CallCharCallback(char rune)
+	// CallFileDropCallback notifies about files that were dropped into the window.
+	CallFileDropCallback(filePaths []string)
+}
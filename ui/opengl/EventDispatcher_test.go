@@ -0,0 +1,104 @@
+package opengl_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/inkyblackness/hacked/ui/input"
+	"github.com/inkyblackness/hacked/ui/opengl"
+)
+
+// fakeWindow is an example of a window without a native surface or OpenGL context.
+// It embeds the dispatcher for registering and calling the callbacks, and stubs the remaining functions.
+// Tests hand it to code that expects an opengl.Window, and synthesize events via the Call* functions.
+type fakeWindow struct {
+	opengl.WindowEventDispatcher
+
+	width, height  int
+	clipboard      string
+	renderRequests int
+}
+
+var _ opengl.Window = (*fakeWindow)(nil)
+var _ opengl.EventDispatcher = (*fakeWindow)(nil)
+
+func (window *fakeWindow) ClipboardContent() opengl.ClipboardContent {
+	if len(window.clipboard) == 0 {
+		return opengl.ClipboardContentUnknown
+	}
+	return opengl.ClipboardContentText
+}
+
+func (window *fakeWindow) ClipboardString() (string, error) { return window.clipboard, nil }
+func (window *fakeWindow) SetClipboardString(value string)  { window.clipboard = value }
+func (window *fakeWindow) SetCloseRequest(shouldClose bool) {}
+func (window *fakeWindow) OpenGL() opengl.OpenGL            { return nil }
+func (window *fakeWindow) RequestRender()                   { window.renderRequests++ }
+func (window *fakeWindow) Size() (width int, height int)    { return window.width, window.height }
+func (window *fakeWindow) SetFullScreen(on bool)            {}
+func (window *fakeWindow) SetCursorVisible(visible bool)    {}
+
+func (window *fakeWindow) SetRenderOnDemand(onDemand bool, refreshInterval time.Duration) {}
+
+func (window *fakeWindow) SetKeyRepeatPolicy(key input.Key, policy input.KeyRepeatPolicy) {}
+
+func (window *fakeWindow) Geometry() opengl.WindowGeometry {
+	return opengl.WindowGeometry{Width: window.width, Height: window.height}
+}
+
+// resize is an example of how a test synthesizes an event, as a native window would.
+func (window *fakeWindow) resize(width, height int) {
+	window.width, window.height = width, height
+	window.CallResize(width, height)
+}
+
+func TestFakeWindowDispatchesSynthesizedEvents(t *testing.T) {
+	window := &fakeWindow{}
+	var seen []string
+	var subject opengl.Window = window
+	subject.OnResize(func(width int, height int) { seen = append(seen, "resize") })
+	subject.OnKey(func(key input.Key, modifier input.Modifier) {
+		if key == input.KeyEnter && modifier.Has(input.ModShift) {
+			seen = append(seen, "shift+enter")
+		}
+	})
+	subject.OnMouseButtonDown(func(buttonMask uint32, modifier input.Modifier) {
+		if buttonMask == input.MousePrimary {
+			seen = append(seen, "primary down")
+		}
+	})
+	subject.OnRender(func() { seen = append(seen, "render") })
+
+	window.resize(640, 480)
+	window.CallKey(input.KeyEnter, input.ModShift)
+	window.CallOnMouseButtonDown(input.MousePrimary, input.ModNone)
+	window.CallRender()
+
+	assert.Equal(t, []string{"resize", "shift+enter", "primary down", "render"}, seen)
+	width, height := subject.Size()
+	assert.Equal(t, 640, width)
+	assert.Equal(t, 480, height)
+}
+
+func TestZeroWindowEventDispatcherIgnoresUnregisteredCallbacks(t *testing.T) {
+	var dispatcher opengl.WindowEventDispatcher
+
+	assert.NotPanics(t, func() {
+		dispatcher.CallRender()
+		dispatcher.CallKey(input.KeyEscape, input.ModNone)
+		dispatcher.CallFileDropCallback([]string{"file"})
+	})
+	assert.True(t, dispatcher.CallCloseRequest(), "close request should be accepted without callback")
+}
+
+func TestStickyKeyListenerDispatchesToKeyCallback(t *testing.T) {
+	dispatcher := opengl.NullWindowEventDispatcher()
+	var keys []input.Key
+	dispatcher.OnKey(func(key input.Key, modifier input.Modifier) { keys = append(keys, key) })
+
+	dispatcher.StickyKeyListener().Key(input.KeyCopy, input.ModControl)
+
+	assert.Equal(t, []input.Key{input.KeyCopy}, keys)
+}
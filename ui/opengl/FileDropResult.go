@@ -0,0 +1,78 @@
+package opengl
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FileDropOutcome describes what happened to a dropped file.
+type FileDropOutcome int
+
+const (
+	// FileDropLoaded is the outcome of a file that was taken and processed.
+	FileDropLoaded FileDropOutcome = iota
+	// FileDropIgnored is the outcome of a file that was not of interest, for example of the wrong type.
+	FileDropIgnored
+	// FileDropFailed is the outcome of a file that could not be processed.
+	FileDropFailed
+)
+
+func (outcome FileDropOutcome) String() string {
+	switch outcome {
+	case FileDropLoaded:
+		return "loaded"
+	case FileDropIgnored:
+		return "ignored"
+	case FileDropFailed:
+		return "failed"
+	default:
+		return fmt.Sprintf("Unknown%d", int(outcome))
+	}
+}
+
+// FileDropResult is the result of handling one dropped file.
+type FileDropResult struct {
+	// Path is the path of the dropped file.
+	Path string
+	// Outcome tells what happened to the file.
+	Outcome FileDropOutcome
+	// Reason explains why a file was ignored or failed. It is optional for ignored files.
+	Reason error
+}
+
+// FileDropHandler is called when one or more files were dropped into the window.
+// It returns one result for each of the dropped files.
+type FileDropHandler func(filePaths []string) []FileDropResult
+
+// FileDropResultCallback is called with the results of a FileDropHandler.
+type FileDropResultCallback func(results []FileDropResult)
+
+// FileDropResultsOf returns results of the same outcome for all given paths.
+func FileDropResultsOf(filePaths []string, outcome FileDropOutcome, reason error) []FileDropResult {
+	results := make([]FileDropResult, len(filePaths))
+	for index, filePath := range filePaths {
+		results[index] = FileDropResult{Path: filePath, Outcome: outcome, Reason: reason}
+	}
+	return results
+}
+
+// FileDropSummary returns a short, human readable summary of the given results,
+// such as "2 loaded, 1 ignored".
+func FileDropSummary(results []FileDropResult) string {
+	var counts [FileDropFailed + 1]int
+	for _, result := range results {
+		if (result.Outcome >= FileDropLoaded) && (result.Outcome <= FileDropFailed) {
+			counts[result.Outcome]++
+		}
+	}
+	var parts []string
+	for outcome, count := range counts {
+		if count > 0 {
+			parts = append(parts, fmt.Sprintf("%d %v", count, FileDropOutcome(outcome)))
+		}
+	}
+	if len(parts) == 0 {
+		return "no files"
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,57 @@
+package opengl_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/inkyblackness/hacked/ui/opengl"
+)
+
+func TestFileDropResultsOfAppliesOutcomeToAllPaths(t *testing.T) {
+	reason := errors.New("wrong type")
+	results := opengl.FileDropResultsOf([]string{"a", "b"}, opengl.FileDropIgnored, reason)
+	assert.Equal(t, []opengl.FileDropResult{
+		{Path: "a", Outcome: opengl.FileDropIgnored, Reason: reason},
+		{Path: "b", Outcome: opengl.FileDropIgnored, Reason: reason},
+	}, results)
+}
+
+func TestFileDropSummary(t *testing.T) {
+	tt := []struct {
+		outcomes []opengl.FileDropOutcome
+		expected string
+	}{
+		{outcomes: nil, expected: "no files"},
+		{outcomes: []opengl.FileDropOutcome{opengl.FileDropLoaded}, expected: "1 loaded"},
+		{outcomes: []opengl.FileDropOutcome{opengl.FileDropFailed, opengl.FileDropLoaded, opengl.FileDropLoaded},
+			expected: "2 loaded, 1 failed"},
+		{outcomes: []opengl.FileDropOutcome{opengl.FileDropIgnored, opengl.FileDropFailed},
+			expected: "1 ignored, 1 failed"},
+	}
+	for _, tc := range tt {
+		td := tc
+		t.Run(td.expected, func(t *testing.T) {
+			var results []opengl.FileDropResult
+			for _, outcome := range td.outcomes {
+				results = append(results, opengl.FileDropResult{Outcome: outcome})
+			}
+			assert.Equal(t, td.expected, opengl.FileDropSummary(results))
+		})
+	}
+}
+
+func TestWindowEventDispatcherOnFileDropForwardsResultsOfHandler(t *testing.T) {
+	dispatcher := opengl.NullWindowEventDispatcher()
+	var reported []opengl.FileDropResult
+	dispatcher.OnFileDrop(func(filePaths []string) []opengl.FileDropResult {
+		return opengl.FileDropResultsOf(filePaths, opengl.FileDropLoaded, nil)
+	}, func(results []opengl.FileDropResult) {
+		reported = results
+	})
+
+	dispatcher.CallFileDropCallback([]string{"file"})
+
+	assert.Equal(t, []opengl.FileDropResult{{Path: "file", Outcome: opengl.FileDropLoaded}}, reported)
+}
@@ -0,0 +1,68 @@
+package opengl
+
+import (
+	"log"
+	"runtime/debug"
+	"time"
+)
+
+// RenderPanicReporter is called for a panic that was recovered during rendering.
+// The stack is the one of the panicking goroutine. Suppressed is the number of panics that
+// happened since the previous report, which were not reported on their own.
+type RenderPanicReporter func(recovered interface{}, stack []byte, suppressed int)
+
+// DefaultRenderPanicReportInterval is the minimum time between two reports if no interval is specified.
+const DefaultRenderPanicReportInterval = 5 * time.Second
+
+// RenderRecovery calls a render callback and recovers from any panic it raises.
+// This keeps the application running if rendering fails, for example because of bad data,
+// so that the user can undo the offending action.
+// As a failing callback typically fails again for every frame, reports are limited to one per interval.
+type RenderRecovery struct {
+	// Interval is the minimum time between two reports. DefaultRenderPanicReportInterval is used if zero.
+	Interval time.Duration
+	// Report is called for recovered panics. If nil, panics are written to the standard logger.
+	Report RenderPanicReporter
+
+	reported   bool
+	lastReport time.Time
+	suppressed int
+}
+
+// Call calls the given render callback and returns true if it panicked.
+func (recovery *RenderRecovery) Call(now time.Time, render RenderCallback) (recovered bool) {
+	defer func() {
+		if value := recover(); value != nil {
+			recovered = true
+			recovery.reportPanic(now, value, debug.Stack())
+		}
+	}()
+	render()
+	return
+}
+
+func (recovery *RenderRecovery) reportPanic(now time.Time, value interface{}, stack []byte) {
+	interval := recovery.Interval
+	if interval <= 0 {
+		interval = DefaultRenderPanicReportInterval
+	}
+	if recovery.reported && (now.Sub(recovery.lastReport) < interval) {
+		recovery.suppressed++
+		return
+	}
+	report := recovery.Report
+	if report == nil {
+		report = logRenderPanic
+	}
+	report(value, stack, recovery.suppressed)
+	recovery.reported = true
+	recovery.lastReport = now
+	recovery.suppressed = 0
+}
+
+func logRenderPanic(recovered interface{}, stack []byte, suppressed int) {
+	if suppressed > 0 {
+		log.Printf("%d further panics during rendering were suppressed", suppressed)
+	}
+	log.Printf("Recovered from panic during rendering: %v\n%s", recovered, stack)
+}
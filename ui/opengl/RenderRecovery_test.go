@@ -0,0 +1,59 @@
+package opengl_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/inkyblackness/hacked/ui/opengl"
+)
+
+type recordedPanic struct {
+	value      interface{}
+	suppressed int
+}
+
+func TestRenderRecoveryRecoversFromPanickingCallback(t *testing.T) {
+	var reports []recordedPanic
+	recovery := opengl.RenderRecovery{Report: func(value interface{}, stack []byte, suppressed int) {
+		reports = append(reports, recordedPanic{value: value, suppressed: suppressed})
+		assert.NotEmpty(t, stack, "stack expected")
+	}}
+
+	recovered := recovery.Call(time.Now(), func() { panic("bad resource") })
+
+	assert.True(t, recovered, "panic should be recovered")
+	assert.Equal(t, []recordedPanic{{value: "bad resource"}}, reports)
+}
+
+func TestRenderRecoveryCallsRegularCallback(t *testing.T) {
+	called := false
+	recovery := opengl.RenderRecovery{Report: func(interface{}, []byte, int) {
+		assert.Fail(t, "no report expected")
+	}}
+
+	recovered := recovery.Call(time.Now(), func() { called = true })
+
+	assert.False(t, recovered, "nothing should be recovered")
+	assert.True(t, called, "callback should have been called")
+}
+
+func TestRenderRecoveryLimitsReportsPerInterval(t *testing.T) {
+	var reports []recordedPanic
+	recovery := opengl.RenderRecovery{
+		Interval: time.Second,
+		Report: func(value interface{}, stack []byte, suppressed int) {
+			reports = append(reports, recordedPanic{value: value, suppressed: suppressed})
+		},
+	}
+	start := time.Now()
+	panicking := func() { panic("again") }
+
+	for frame := 0; frame < 10; frame++ {
+		recovery.Call(start.Add(time.Duration(frame)*100*time.Millisecond), panicking)
+	}
+	recovery.Call(start.Add(time.Second), panicking)
+
+	assert.Equal(t, []recordedPanic{{value: "again"}, {value: "again", suppressed: 9}}, reports)
+}
@@ -1,12 +1,18 @@
 package opengl
 
 import (
+	"time"
+
 	"github.com/inkyblackness/hacked/ui/input"
 )
 
 // ClosingCallback is the function to handle close requests by the user.
 type ClosingCallback func()
 
+// CloseRequestCallback is called when the user requests to close the window, before the window is closing.
+// Returning false keeps the window open; the request can be repeated via SetCloseRequest(true) later on.
+type CloseRequestCallback func() bool
+
 // ClosedCallback is the function to clean up resources when the window is being closed.
 type ClosedCallback func()
 
@@ -46,11 +52,17 @@ type FileDropCallback func(filePaths []string)
 
 // Window represents an OpenGL render surface.
 type Window interface {
+	// ClipboardContent returns what the clipboard currently holds.
+	// ClipboardContentUnknown is returned if this can not be determined.
+	ClipboardContent() ClipboardContent
 	// ClipboardString returns the current value of the clipboard, if it is compatible with UTF-8.
 	ClipboardString() (string, error)
 	// SetClipboardString sets the current value of the clipboard as UTF-8 string.
 	SetClipboardString(value string)
 
+	// OnCloseRequest registers a callback function which can veto a close request by the user.
+	// Without a registered callback, close requests are always accepted.
+	OnCloseRequest(callback CloseRequestCallback)
 	// OnClosing registers a callback function which shall be called when the user requests to close the window.
 	OnClosing(callback ClosingCallback)
 	// OnClosed registers a callback function which shall be called when the window is being closed.
@@ -63,11 +75,20 @@ type Window interface {
 	OpenGL() OpenGL
 	// OnRender registers a callback function which shall be called to update the scene.
 	OnRender(callback RenderCallback)
+	// SetRenderOnDemand switches between continuous rendering and rendering on demand.
+	// In on-demand mode, the window is rendered after user input, explicit requests, or after the
+	// refresh interval has elapsed since the last render.
+	SetRenderOnDemand(onDemand bool, refreshInterval time.Duration)
+	// RequestRender requests the window to be rendered again, even if in on-demand mode.
+	RequestRender()
 
 	// OnResize registers a callback function for sizing events.
 	OnResize(callback ResizeCallback)
 	// Size returns the dimensions of the window display area in pixel.
 	Size() (width int, height int)
+	// Geometry returns the size and position of the window on the desktop, in screen coordinates.
+	// While in full screen mode, the geometry of the windowed mode is returned.
+	Geometry() WindowGeometry
 	// SetFullScreen sets the full screen state of the window.
 	SetFullScreen(on bool)
 
@@ -85,6 +106,9 @@ type Window interface {
 
 	// OnKey registers a callback function for key events.
 	OnKey(callback KeyCallback)
+	// SetKeyRepeatPolicy sets how repeats of a held key are reported to the key callback.
+	// By default, repeats are reported as release and press of the key.
+	SetKeyRepeatPolicy(key input.Key, policy input.KeyRepeatPolicy)
 	// OnModifier registers a callback function for change of modifier events.
 	OnModifier(callback ModifierCallback)
 	// OnCharCallback registers a callback function for typed characters.
@@ -92,4 +116,7 @@ type Window interface {
 
 	// OnFileDropCallback registers a callback function for dropped files.
 	OnFileDropCallback(callback FileDropCallback)
+	// OnFileDrop registers a handler for dropped files, and a callback that receives the results of the handler.
+	// This replaces a callback registered with OnFileDropCallback.
+	OnFileDrop(handler FileDropHandler, resultCallback FileDropResultCallback)
 }
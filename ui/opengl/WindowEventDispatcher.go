@@ -16,37 +16,43 @@ func (def *keyDeferrer) Modifier(modifier input.Modifier) {
 	def.window.CallModifier(modifier)
 }
 
-// WindowEventDispatcher implements the common, basic functionality of WindowEventDispatcher.
+// WindowEventDispatcher implements the common, basic functionality of registering window callbacks,
+// and of dispatching events to them. It implements EventDispatcher.
+// Callbacks that are not registered are not called; the zero value is ready to use.
 type WindowEventDispatcher struct {
-	CallClosing           ClosingCallback
-	CallClosed            ClosedCallback
-	CallRender            RenderCallback
-	CallResize            ResizeCallback
-	CallOnMouseMove       MouseMoveCallback
-	CallOnMouseButtonUp   MouseButtonCallback
-	CallOnMouseButtonDown MouseButtonCallback
-	CallOnMouseScroll     MouseScrollCallback
-	CallModifier          ModifierCallback
-	CallKey               KeyCallback
-	CallCharCallback      CharCallback
-	CallFileDropCallback  FileDropCallback
-}
+	closeRequest    CloseRequestCallback
+	closing         ClosingCallback
+	closed          ClosedCallback
+	render          RenderCallback
+	resize          ResizeCallback
+	mouseMove       MouseMoveCallback
+	mouseButtonUp   MouseButtonCallback
+	mouseButtonDown MouseButtonCallback
+	mouseScroll     MouseScrollCallback
+	modifier        ModifierCallback
+	key             KeyCallback
+	char            CharCallback
+	fileDrop        FileDropCallback
+}
+
+var _ EventDispatcher = (*WindowEventDispatcher)(nil)
 
 // NullWindowEventDispatcher returns an initialized instance with empty callbacks.
 func NullWindowEventDispatcher() WindowEventDispatcher {
 	return WindowEventDispatcher{
-		CallClosing:           func() {},
-		CallClosed:            func() {},
-		CallRender:            func() {},
-		CallResize:            func(int, int) {},
-		CallOnMouseMove:       func(float32, float32) {},
-		CallOnMouseButtonUp:   func(uint32, input.Modifier) {},
-		CallOnMouseButtonDown: func(uint32, input.Modifier) {},
-		CallOnMouseScroll:     func(float32, float32) {},
-		CallKey:               func(input.Key, input.Modifier) {},
-		CallModifier:          func(input.Modifier) {},
-		CallCharCallback:      func(rune) {},
-		CallFileDropCallback:  func([]string) {},
+		closeRequest:    func() bool { return true },
+		closing:         func() {},
+		closed:          func() {},
+		render:          func() {},
+		resize:          func(int, int) {},
+		mouseMove:       func(float32, float32) {},
+		mouseButtonUp:   func(uint32, input.Modifier) {},
+		mouseButtonDown: func(uint32, input.Modifier) {},
+		mouseScroll:     func(float32, float32) {},
+		key:             func(input.Key, input.Modifier) {},
+		modifier:        func(input.Modifier) {},
+		char:            func(rune) {},
+		fileDrop:        func([]string) {},
 	}
 }
 
@@ -56,62 +62,168 @@ func (window *WindowEventDispatcher) StickyKeyListener() input.StickyKeyListener
 	return &keyDeferrer{window: window}
 }
 
+// OnCloseRequest implements the WindowEventDispatcher interface.
+func (window *WindowEventDispatcher) OnCloseRequest(callback CloseRequestCallback) {
+	window.closeRequest = callback
+}
+
 // OnClosing implements the WindowEventDispatcher interface.
 func (window *WindowEventDispatcher) OnClosing(callback ClosingCallback) {
-	window.CallClosing = callback
+	window.closing = callback
 }
 
 // OnClosed implements the WindowEventDispatcher interface.
 func (window *WindowEventDispatcher) OnClosed(callback ClosedCallback) {
-	window.CallClosed = callback
+	window.closed = callback
 }
 
 // OnRender implements the WindowEventDispatcher interface.
 func (window *WindowEventDispatcher) OnRender(callback RenderCallback) {
-	window.CallRender = callback
+	window.render = callback
 }
 
 // OnResize implements the WindowEventDispatcher interface.
 func (window *WindowEventDispatcher) OnResize(callback ResizeCallback) {
-	window.CallResize = callback
+	window.resize = callback
 }
 
 // OnMouseMove implements the WindowEventDispatcher interface.
 func (window *WindowEventDispatcher) OnMouseMove(callback MouseMoveCallback) {
-	window.CallOnMouseMove = callback
+	window.mouseMove = callback
 }
 
 // OnMouseButtonDown implements the WindowEventDispatcher interface.
 func (window *WindowEventDispatcher) OnMouseButtonDown(callback MouseButtonCallback) {
-	window.CallOnMouseButtonDown = callback
+	window.mouseButtonDown = callback
 }
 
 // OnMouseButtonUp implements the WindowEventDispatcher interface.
 func (window *WindowEventDispatcher) OnMouseButtonUp(callback MouseButtonCallback) {
-	window.CallOnMouseButtonUp = callback
+	window.mouseButtonUp = callback
 }
 
 // OnMouseScroll implements the WindowEventDispatcher interface.
 func (window *WindowEventDispatcher) OnMouseScroll(callback MouseScrollCallback) {
-	window.CallOnMouseScroll = callback
+	window.mouseScroll = callback
 }
 
 // OnKey implements the WindowEventDispatcher interface
 func (window *WindowEventDispatcher) OnKey(callback KeyCallback) {
-	window.CallKey = callback
+	window.key = callback
 }
 
 // OnModifier implements the WindowEventDispatcher interface
 func (window *WindowEventDispatcher) OnModifier(callback ModifierCallback) {
-	window.CallModifier = callback
+	window.modifier = callback
 }
 
 // OnCharCallback implements the WindowEventDispatcher interface
 func (window *WindowEventDispatcher) OnCharCallback(callback CharCallback) {
-	window.CallCharCallback = callback
+	window.char = callback
 }
 
 // OnFileDropCallback implements the WindowEventDispatcher interface
 func (window *WindowEventDispatcher) OnFileDropCallback(callback FileDropCallback) {
-	window.CallFileDropCallback = callback
+	window.fileDrop = callback
+}
+
+// OnFileDrop implements the WindowEventDispatcher interface.
+// It is built on the raw file drop callback, which it replaces.
+func (window *WindowEventDispatcher) OnFileDrop(handler FileDropHandler, resultCallback FileDropResultCallback) {
+	window.fileDrop = func(filePaths []string) {
+		resultCallback(handler(filePaths))
+	}
+}
+
+// CallCloseRequest implements the EventDispatcher interface.
+// Without a registered callback, the request is accepted.
+func (window *WindowEventDispatcher) CallCloseRequest() bool {
+	if window.closeRequest == nil {
+		return true
+	}
+	return window.closeRequest()
+}
+
+// CallClosing implements the EventDispatcher interface.
+func (window *WindowEventDispatcher) CallClosing() {
+	if window.closing != nil {
+		window.closing()
+	}
+}
+
+// CallClosed implements the EventDispatcher interface.
+func (window *WindowEventDispatcher) CallClosed() {
+	if window.closed != nil {
+		window.closed()
+	}
+}
+
+// CallRender implements the EventDispatcher interface.
+func (window *WindowEventDispatcher) CallRender() {
+	if window.render != nil {
+		window.render()
+	}
+}
+
+// CallResize implements the EventDispatcher interface.
+func (window *WindowEventDispatcher) CallResize(width int, height int) {
+	if window.resize != nil {
+		window.resize(width, height)
+	}
+}
+
+// CallOnMouseMove implements the EventDispatcher interface.
+func (window *WindowEventDispatcher) CallOnMouseMove(x float32, y float32) {
+	if window.mouseMove != nil {
+		window.mouseMove(x, y)
+	}
+}
+
+// CallOnMouseButtonDown implements the EventDispatcher interface.
+func (window *WindowEventDispatcher) CallOnMouseButtonDown(buttonMask uint32, modifier input.Modifier) {
+	if window.mouseButtonDown != nil {
+		window.mouseButtonDown(buttonMask, modifier)
+	}
+}
+
+// CallOnMouseButtonUp implements the EventDispatcher interface.
+func (window *WindowEventDispatcher) CallOnMouseButtonUp(buttonMask uint32, modifier input.Modifier) {
+	if window.mouseButtonUp != nil {
+		window.mouseButtonUp(buttonMask, modifier)
+	}
+}
+
+// CallOnMouseScroll implements the EventDispatcher interface.
+func (window *WindowEventDispatcher) CallOnMouseScroll(dx float32, dy float32) {
+	if window.mouseScroll != nil {
+		window.mouseScroll(dx, dy)
+	}
+}
+
+// CallKey implements the EventDispatcher interface.
+func (window *WindowEventDispatcher) CallKey(key input.Key, modifier input.Modifier) {
+	if window.key != nil {
+		window.key(key, modifier)
+	}
+}
+
+// CallModifier implements the EventDispatcher interface.
+func (window *WindowEventDispatcher) CallModifier(modifier input.Modifier) {
+	if window.modifier != nil {
+		window.modifier(modifier)
+	}
+}
+
+// CallCharCallback implements the EventDispatcher interface.
+func (window *WindowEventDispatcher) CallCharCallback(char rune) {
+	if window.char != nil {
+		window.char(char)
+	}
+}
+
+// CallFileDropCallback implements the EventDispatcher interface.
+func (window *WindowEventDispatcher) CallFileDropCallback(filePaths []string) {
+	if window.fileDrop != nil {
+		window.fileDrop(filePaths)
+	}
 }
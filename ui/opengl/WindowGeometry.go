@@ -0,0 +1,88 @@
+package opengl
+
+import "fmt"
+
+const (
+	// DefaultWindowWidth is the width of a window if none is specified.
+	DefaultWindowWidth = 1280
+	// DefaultWindowHeight is the height of a window if none is specified.
+	DefaultWindowHeight = 720
+)
+
+// WindowGeometry describes the size and the position of a window on the desktop.
+type WindowGeometry struct {
+	// Width is the width of the window in screen coordinates.
+	Width int `json:"width"`
+	// Height is the height of the window in screen coordinates.
+	Height int `json:"height"`
+
+	// Positioned is set if Left and Top shall be applied.
+	// Otherwise, the window system decides where to place the window.
+	Positioned bool `json:"positioned"`
+	// Left is the horizontal position of the upper-left corner of the window, in screen coordinates.
+	Left int `json:"left"`
+	// Top is the vertical position of the upper-left corner of the window, in screen coordinates.
+	Top int `json:"top"`
+}
+
+// DefaultWindowGeometry returns the geometry of a window for which nothing is specified.
+func DefaultWindowGeometry() WindowGeometry {
+	return WindowGeometry{Width: DefaultWindowWidth, Height: DefaultWindowHeight}
+}
+
+// Validate returns an error if the geometry can not be used for a window.
+func (geometry WindowGeometry) Validate() error {
+	if (geometry.Width <= 0) || (geometry.Height <= 0) {
+		return fmt.Errorf("invalid window size %dx%d", geometry.Width, geometry.Height)
+	}
+	return nil
+}
+
+// ScreenArea describes the region of the desktop that one monitor covers, in screen coordinates.
+type ScreenArea struct {
+	Left   int
+	Top    int
+	Width  int
+	Height int
+}
+
+func (area ScreenArea) contains(x, y int) bool {
+	return (x >= area.Left) && (x < area.Left+area.Width) && (y >= area.Top) && (y < area.Top+area.Height)
+}
+
+// OrDefault returns the geometry itself if it is valid, or the default geometry otherwise.
+// The position is kept in either case, yet moved onto the given screens:
+// If the upper-left corner is on none of the screens, the window is placed on the first one.
+// The window is then moved so that it is fully visible on its screen, as far as its size allows.
+// Without any screen, the position is kept as is.
+func (geometry WindowGeometry) OrDefault(screens []ScreenArea) WindowGeometry {
+	result := geometry
+	if geometry.Validate() != nil {
+		result = DefaultWindowGeometry()
+		result.Positioned = geometry.Positioned
+		result.Left = geometry.Left
+		result.Top = geometry.Top
+	}
+	if !result.Positioned || (len(screens) == 0) {
+		return result
+	}
+	screen := screens[0]
+	for _, candidate := range screens {
+		if candidate.contains(result.Left, result.Top) {
+			screen = candidate
+			break
+		}
+	}
+	clamp := func(value, size, start, length int) int {
+		if value+size > start+length {
+			value = start + length - size
+		}
+		if value < start {
+			value = start
+		}
+		return value
+	}
+	result.Left = clamp(result.Left, result.Width, screen.Left, screen.Width)
+	result.Top = clamp(result.Top, result.Height, screen.Top, screen.Height)
+	return result
+}
@@ -0,0 +1,90 @@
+package opengl_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/inkyblackness/hacked/ui/opengl"
+)
+
+func TestWindowGeometryValidate(t *testing.T) {
+	tt := []struct {
+		width  int
+		height int
+		valid  bool
+	}{
+		{width: 800, height: 600, valid: true},
+		{width: 1, height: 1, valid: true},
+		{width: 0, height: 600, valid: false},
+		{width: 800, height: 0, valid: false},
+		{width: -10, height: 600, valid: false},
+		{width: 800, height: -1, valid: false},
+	}
+	for _, tc := range tt {
+		td := tc
+		t.Run("", func(t *testing.T) {
+			err := opengl.WindowGeometry{Width: td.width, Height: td.height}.Validate()
+			assert.Equal(t, td.valid, err == nil, "validity mismatch for %dx%d", td.width, td.height)
+		})
+	}
+}
+
+func TestWindowGeometryOrDefaultKeepsValidGeometry(t *testing.T) {
+	geometry := opengl.WindowGeometry{Width: 800, Height: 600, Positioned: true, Left: 10, Top: 20}
+	assert.Equal(t, geometry, geometry.OrDefault([]opengl.ScreenArea{{Width: 1920, Height: 1080}}))
+}
+
+func TestWindowGeometryOrDefaultReplacesInvalidSizeButKeepsPosition(t *testing.T) {
+	geometry := opengl.WindowGeometry{Width: 0, Height: 600, Positioned: true, Left: 10, Top: 20}
+	assert.Equal(t, opengl.WindowGeometry{
+		Width: opengl.DefaultWindowWidth, Height: opengl.DefaultWindowHeight,
+		Positioned: true, Left: 10, Top: 20}, geometry.OrDefault([]opengl.ScreenArea{{Width: 1920, Height: 1080}}))
+}
+
+func TestWindowGeometryOrDefaultMovesPositionOntoScreens(t *testing.T) {
+	screens := []opengl.ScreenArea{
+		{Left: 0, Top: 0, Width: 1920, Height: 1080},
+		{Left: 1920, Top: 0, Width: 1280, Height: 1024},
+	}
+	tt := []struct {
+		name      string
+		left      int
+		top       int
+		width     int
+		height    int
+		expLeft   int
+		expTop    int
+		expWidth  int
+		expHeight int
+	}{
+		{name: "on first screen", left: 100, top: 50, width: 800, height: 600, expLeft: 100, expTop: 50, expWidth: 800, expHeight: 600},
+		{name: "on second screen", left: 2000, top: 100, width: 800, height: 600, expLeft: 2000, expTop: 100, expWidth: 800, expHeight: 600},
+		{name: "beyond right edge of second screen", left: 2900, top: 100, width: 800, height: 600, expLeft: 2400, expTop: 100, expWidth: 800, expHeight: 600},
+		{name: "on removed screen", left: -1500, top: 200, width: 800, height: 600, expLeft: 0, expTop: 200, expWidth: 800, expHeight: 600},
+		{name: "below all screens", left: 100, top: 5000, width: 800, height: 600, expLeft: 100, expTop: 480, expWidth: 800, expHeight: 600},
+		{name: "larger than screen", left: 100, top: 100, width: 4000, height: 3000, expLeft: 0, expTop: 0, expWidth: 4000, expHeight: 3000},
+		{name: "invalid size on removed screen", left: 5000, top: 5000, width: 0, height: 0,
+			expLeft: 1920 - opengl.DefaultWindowWidth, expTop: 1080 - opengl.DefaultWindowHeight,
+			expWidth: opengl.DefaultWindowWidth, expHeight: opengl.DefaultWindowHeight},
+	}
+	for _, tc := range tt {
+		td := tc
+		t.Run(td.name, func(t *testing.T) {
+			geometry := opengl.WindowGeometry{Width: td.width, Height: td.height, Positioned: true, Left: td.left, Top: td.top}
+			assert.Equal(t, opengl.WindowGeometry{
+				Width: td.expWidth, Height: td.expHeight,
+				Positioned: true, Left: td.expLeft, Top: td.expTop}, geometry.OrDefault(screens))
+		})
+	}
+}
+
+func TestWindowGeometryOrDefaultKeepsPositionWithoutScreens(t *testing.T) {
+	geometry := opengl.WindowGeometry{Width: 800, Height: 600, Positioned: true, Left: -5000, Top: 7000}
+	assert.Equal(t, geometry, geometry.OrDefault(nil))
+}
+
+func TestWindowGeometryOrDefaultIgnoresScreensForUnpositionedWindow(t *testing.T) {
+	geometry := opengl.WindowGeometry{Width: 800, Height: 600, Left: -5000, Top: 7000}
+	assert.Equal(t, geometry, geometry.OrDefault([]opengl.ScreenArea{{Width: 1920, Height: 1080}}))
+}